@@ -0,0 +1,57 @@
+package activity
+
+import (
+	"fmt"
+	"go-expense-tracker/domain"
+)
+
+// BudgetRepository wraps a BudgetRepository and adds every change to a budget to the feed
+type BudgetRepository struct {
+	domain.BudgetRepository
+	feed *Feed
+}
+
+// NewBudgetRepository creates a new BudgetRepository around the given repository
+func NewBudgetRepository(inner domain.BudgetRepository, feed *Feed) *BudgetRepository {
+	return &BudgetRepository{
+		BudgetRepository: inner,
+		feed:             feed,
+	}
+}
+
+// AddBudget adds the budget and records it in the feed
+func (r *BudgetRepository) AddBudget(budget *domain.Budget) error {
+	if err := r.BudgetRepository.AddBudget(budget); err != nil {
+		return err
+	}
+
+	r.feed.Record(domain.ActivityBudgetChanged, budget.ID,
+		fmt.Sprintf("Created the %s budget %q of %.2f", budget.Period, budget.Name, budget.Amount), 0)
+	return nil
+}
+
+// UpdateBudget updates the budget and records it in the feed
+func (r *BudgetRepository) UpdateBudget(budget *domain.Budget) error {
+	if err := r.BudgetRepository.UpdateBudget(budget); err != nil {
+		return err
+	}
+
+	r.feed.Record(domain.ActivityBudgetChanged, budget.ID,
+		fmt.Sprintf("Changed the %s budget %q to %.2f", budget.Period, budget.Name, budget.Amount), 0)
+	return nil
+}
+
+// DeleteBudget deletes the budget and records it in the feed
+func (r *BudgetRepository) DeleteBudget(id string) error {
+	budget, err := r.BudgetRepository.GetBudgetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.BudgetRepository.DeleteBudget(id); err != nil {
+		return err
+	}
+
+	r.feed.Record(domain.ActivityBudgetChanged, budget.ID, fmt.Sprintf("Deleted the budget %q", budget.Name), 0)
+	return nil
+}
@@ -0,0 +1,43 @@
+package activity
+
+import (
+	"fmt"
+	"go-expense-tracker/domain"
+)
+
+// CategoryRepository wraps a CategoryRepository and adds changes to the limits of categories,
+// the budgets of the household, to the feed
+type CategoryRepository struct {
+	domain.CategoryRepository
+	feed *Feed
+}
+
+// NewCategoryRepository creates a new CategoryRepository around the given repository
+func NewCategoryRepository(inner domain.CategoryRepository, feed *Feed) *CategoryRepository {
+	return &CategoryRepository{
+		CategoryRepository: inner,
+		feed:               feed,
+	}
+}
+
+// UpdateCategory updates the category and records it in the feed when its limit changed
+func (r *CategoryRepository) UpdateCategory(category *domain.Category) error {
+	before, err := r.CategoryRepository.GetCategoryByID(category.ID.String())
+	if err != nil {
+		return err
+	}
+
+	if err := r.CategoryRepository.UpdateCategory(category); err != nil {
+		return err
+	}
+
+	if before.TransactionLimit == category.TransactionLimit && before.LimitMode == category.LimitMode {
+		return nil
+	}
+	summary := fmt.Sprintf("Removed the limit of %q", category.Name)
+	if category.TransactionLimit > 0 {
+		summary = fmt.Sprintf("Set the limit of %q to %.2f (%s)", category.Name, category.TransactionLimit, category.LimitMode)
+	}
+	r.feed.Record(domain.ActivityBudgetChanged, category.ID, summary, 0)
+	return nil
+}
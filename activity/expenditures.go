@@ -0,0 +1,75 @@
+package activity
+
+import (
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+)
+
+// ExpenditureRepository wraps an ExpenditureRepository and adds every change to the feed
+type ExpenditureRepository struct {
+	domain.ExpenditureWrapper
+	feed *Feed
+}
+
+// NewExpenditureRepository creates a new ExpenditureRepository around the given repository
+func NewExpenditureRepository(inner domain.ExpenditureRepository, feed *Feed) *ExpenditureRepository {
+	return &ExpenditureRepository{
+		ExpenditureWrapper: domain.ExpenditureWrapper{ExpenditureRepository: inner},
+		feed:               feed,
+	}
+}
+
+// AddExpenditure adds the expenditure and records it in the feed
+func (r *ExpenditureRepository) AddExpenditure(expenditure *domain.Expenditure) error {
+	if err := r.ExpenditureRepository.AddExpenditure(expenditure); err != nil {
+		return err
+	}
+
+	r.feed.Record(domain.ActivityExpenditureAdded, expenditure.ID,
+		fmt.Sprintf("Added %q for %.2f", expenditure.Description, expenditure.Amount), 0)
+	return nil
+}
+
+// AddExpenditures adds the expenditures in bulk and records them as one committed import
+func (r *ExpenditureRepository) AddExpenditures(expenditures []*domain.Expenditure) error {
+	if err := domain.AddExpenditures(r.ExpenditureRepository, expenditures); err != nil {
+		return err
+	}
+
+	total := 0.0
+	for _, expenditure := range expenditures {
+		total += expenditure.Amount
+	}
+	r.feed.Record(domain.ActivityImportCommitted, uuid.Nil,
+		fmt.Sprintf("Imported %d expenditures for %.2f", len(expenditures), total), len(expenditures))
+	return nil
+}
+
+// UpdateExpenditure updates the expenditure and records it in the feed
+func (r *ExpenditureRepository) UpdateExpenditure(expenditure *domain.Expenditure) error {
+	if err := r.ExpenditureRepository.UpdateExpenditure(expenditure); err != nil {
+		return err
+	}
+
+	r.feed.Record(domain.ActivityExpenditureEdited, expenditure.ID,
+		fmt.Sprintf("Edited %q, now %.2f", expenditure.Description, expenditure.Amount), 0)
+	return nil
+}
+
+// DeleteExpenditure deletes the expenditure and records it in the feed
+func (r *ExpenditureRepository) DeleteExpenditure(id string) error {
+	expenditure, err := r.ExpenditureRepository.GetExpenditureByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.ExpenditureRepository.DeleteExpenditure(id); err != nil {
+		return err
+	}
+
+	r.feed.Record(domain.ActivityExpenditureDeleted, expenditure.ID,
+		fmt.Sprintf("Deleted %q for %.2f", expenditure.Description, expenditure.Amount), 0)
+	return nil
+}
@@ -0,0 +1,110 @@
+// Package activity keeps a feed of the recent changes to expenditures and budgets, so that
+// members of a household can see what changed since they last looked.
+package activity
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Feed holds the latest activities. Like the operation log it is kept in memory only, and the
+// oldest activities are dropped once it is full
+type Feed struct {
+	size       int
+	activities []*domain.Activity // Oldest first
+	seq        int64
+	logger     *slog.Logger
+	mu         sync.RWMutex
+}
+
+// NewFeed creates a new Feed keeping up to size activities
+func NewFeed(size int, logger *slog.Logger) *Feed {
+	return &Feed{
+		size:   size,
+		logger: logger,
+	}
+}
+
+// Record adds an activity to the feed
+func (f *Feed) Record(kind string, subjectID uuid.UUID, summary string, count int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.seq++
+	activity := &domain.Activity{
+		Seq:       f.seq,
+		Kind:      kind,
+		SubjectID: subjectID,
+		Summary:   summary,
+		Count:     count,
+		At:        time.Now(),
+	}
+	if len(f.activities) >= f.size {
+		f.activities = append(f.activities[:0], f.activities[len(f.activities)-f.size+1:]...)
+	}
+	f.activities = append(f.activities, activity)
+	f.logger.Debug("Recorded activity", "seq", activity.Seq, "kind", kind, "subject_id", subjectID)
+}
+
+// Recent returns up to limit activities, newest first, that happened at or after since and come
+// before the activity with sequence number before; zero values leave the bounds open
+func (f *Feed) Recent(since time.Time, before int64, limit int) []*domain.Activity {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var activities []*domain.Activity
+	for i := len(f.activities) - 1; i >= 0 && len(activities) < limit; i-- {
+		activity := f.activities[i]
+		if before > 0 && activity.Seq >= before {
+			continue
+		}
+		if !since.IsZero() && activity.At.Before(since) {
+			break
+		}
+		copied := *activity
+		activities = append(activities, &copied)
+	}
+	return activities
+}
+
+// After returns up to limit activities of a kind, newest first, with a sequence number above after
+func (f *Feed) After(after int64, kind string, limit int) []*domain.Activity {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var activities []*domain.Activity
+	for i := len(f.activities) - 1; i >= 0 && len(activities) < limit; i-- {
+		activity := f.activities[i]
+		if activity.Seq <= after {
+			break
+		}
+		if activity.Kind != kind {
+			continue
+		}
+		copied := *activity
+		activities = append(activities, &copied)
+	}
+	return activities
+}
+
+// ScrubMember forgets the activities about the expenditures of an erased member that were
+// deleted with them, returning how many were forgotten. Activities name no member, so those
+// about the expenditures kept for the household stay
+func (f *Feed) ScrubMember(member uuid.UUID, erased []uuid.UUID) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	before := len(f.activities)
+	f.activities = slices.DeleteFunc(f.activities, func(activity *domain.Activity) bool {
+		return slices.Contains(erased, activity.SubjectID)
+	})
+	scrubbed := before - len(f.activities)
+
+	f.logger.Info("Scrubbed activities of erased member", "member_id", member, "count", scrubbed)
+	return scrubbed
+}
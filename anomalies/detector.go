@@ -0,0 +1,270 @@
+// Package anomalies flags spending that stands out from its history: amounts far above what is
+// usual for the category or merchant, charges that look like duplicates and days of spending
+// far above the days before them.
+package anomalies
+
+import (
+	"fmt"
+	"go-expense-tracker/domain"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Options tune how unusual spending has to be to be flagged
+type Options struct {
+	Threshold       float64       // Standard deviations above the mean, and at least twice the mean
+	MinHistory      int           // Expenditures, or days with spending, needed before judging
+	DuplicateWindow time.Duration // How soon a repeated charge looks like a duplicate
+	SpikeDays       int           // Days before a day its total is compared to
+}
+
+// DefaultOptions are used for options left at zero
+var DefaultOptions = Options{
+	Threshold:       3,
+	MinHistory:      5,
+	DuplicateWindow: 48 * time.Hour,
+	SpikeDays:       30,
+}
+
+// Detector finds anomalies in a history of expenditures. Refunds and planned payments are
+// left out, as they are not money spent yet or at all
+type Detector struct {
+	options Options
+}
+
+// NewDetector creates a new Detector; options left at zero take their default
+func NewDetector(options Options) *Detector {
+	if options.Threshold <= 0 {
+		options.Threshold = DefaultOptions.Threshold
+	}
+	if options.MinHistory <= 0 {
+		options.MinHistory = DefaultOptions.MinHistory
+	}
+	if options.DuplicateWindow <= 0 {
+		options.DuplicateWindow = DefaultOptions.DuplicateWindow
+	}
+	if options.SpikeDays <= 0 {
+		options.SpikeDays = DefaultOptions.SpikeDays
+	}
+	return &Detector{options: options}
+}
+
+// Detect returns the anomalies dated in [from, to), latest first; zero bounds are open. The
+// whole history is needed, since every expenditure is judged against the ones before it
+func (d *Detector) Detect(expenditures []*domain.Expenditure, from, to time.Time) []*domain.Anomaly {
+	spent := spending(expenditures)
+
+	var found []*domain.Anomaly
+	found = append(found, d.unusualAmounts(spent)...)
+	found = append(found, d.duplicates(spent)...)
+	found = append(found, d.spikes(spent)...)
+
+	anomalies := []*domain.Anomaly{}
+	for _, anomaly := range found {
+		if (from.IsZero() || !anomaly.Date.Before(from)) && (to.IsZero() || anomaly.Date.Before(to)) {
+			anomalies = append(anomalies, anomaly)
+		}
+	}
+
+	sort.SliceStable(anomalies, func(i, j int) bool {
+		return anomalies[i].Date.After(anomalies[j].Date)
+	})
+	return anomalies
+}
+
+// Check returns the anomalies a new expenditure causes, given the history including it: the
+// expenditure itself standing out, or its day becoming a spike through it
+func (d *Detector) Check(expenditures []*domain.Expenditure, expenditure *domain.Expenditure) []*domain.Anomaly {
+	day := startOfDay(expenditure.Date)
+	next := day.AddDate(0, 0, 1)
+
+	spikeBefore := false
+	for _, anomaly := range d.Detect(without(expenditures, expenditure.ID), day, next) {
+		spikeBefore = spikeBefore || anomaly.Kind == domain.AnomalyDailySpike
+	}
+
+	var caused []*domain.Anomaly
+	for _, anomaly := range d.Detect(expenditures, day, next) {
+		switch {
+		case anomaly.ExpenditureID == expenditure.ID:
+			caused = append(caused, anomaly)
+		case anomaly.Kind == domain.AnomalyDailySpike && !spikeBefore:
+			caused = append(caused, anomaly)
+		}
+	}
+	return caused
+}
+
+// unusualAmounts flags expenditures far above the mean of the earlier ones at the same merchant,
+// or in the same category when the merchant has too little history
+func (d *Detector) unusualAmounts(spent []*domain.Expenditure) []*domain.Anomaly {
+	byCategory := make(map[uuid.UUID]*stats)
+	byMerchant := make(map[uuid.UUID]*stats)
+
+	var anomalies []*domain.Anomaly
+	for _, expenditure := range spent {
+		baseline, of := byMerchant[expenditure.MerchantId], "merchant"
+		if expenditure.MerchantId == uuid.Nil || baseline == nil || baseline.n < d.options.MinHistory {
+			baseline, of = byCategory[expenditure.CategoryId], "category"
+		}
+
+		if baseline != nil && baseline.n >= d.options.MinHistory && d.outlier(expenditure.Amount, baseline) {
+			anomalies = append(anomalies, &domain.Anomaly{
+				Kind:          domain.AnomalyUnusualAmount,
+				Date:          expenditure.Date,
+				ExpenditureID: expenditure.ID,
+				Description:   expenditure.Description,
+				Amount:        expenditure.Amount,
+				Expected:      round(baseline.mean),
+				Reason:        fmt.Sprintf("%.2f is %.1f times the usual %.2f for the %s", expenditure.Amount, expenditure.Amount/baseline.mean, baseline.mean, of),
+			})
+		}
+
+		add(byCategory, expenditure.CategoryId, expenditure.Amount)
+		if expenditure.MerchantId != uuid.Nil {
+			add(byMerchant, expenditure.MerchantId, expenditure.Amount)
+		}
+	}
+	return anomalies
+}
+
+// duplicates flags charges repeating the amount of an earlier one at the same merchant within
+// the duplicate window
+func (d *Detector) duplicates(spent []*domain.Expenditure) []*domain.Anomaly {
+	type charge struct {
+		merchant string
+		cents    int64
+	}
+	last := make(map[charge]*domain.Expenditure)
+
+	var anomalies []*domain.Anomaly
+	for _, expenditure := range spent {
+		merchant := expenditure.MerchantId.String()
+		if expenditure.MerchantId == uuid.Nil {
+			merchant = domain.NormalizeMerchantName(expenditure.Description)
+		}
+		key := charge{merchant: merchant, cents: int64(math.Round(expenditure.Amount * 100))}
+
+		if earlier := last[key]; earlier != nil && expenditure.Date.Sub(earlier.Date) <= d.options.DuplicateWindow {
+			anomalies = append(anomalies, &domain.Anomaly{
+				Kind:          domain.AnomalyPossibleDuplicate,
+				Date:          expenditure.Date,
+				ExpenditureID: expenditure.ID,
+				DuplicateOf:   earlier.ID,
+				Description:   expenditure.Description,
+				Amount:        expenditure.Amount,
+				Reason:        fmt.Sprintf("Same amount of %.2f as %q on %s", expenditure.Amount, earlier.Description, earlier.Date.Format("2006-01-02")),
+			})
+		}
+		last[key] = expenditure
+	}
+	return anomalies
+}
+
+// spikes flags days whose total is far above the totals of the days before them, days without
+// spending included
+func (d *Detector) spikes(spent []*domain.Expenditure) []*domain.Anomaly {
+	totals := make(map[time.Time]float64)
+	var days []time.Time
+	for _, expenditure := range spent {
+		day := startOfDay(expenditure.Date)
+		if _, seen := totals[day]; !seen {
+			days = append(days, day)
+		}
+		totals[day] += expenditure.Amount
+	}
+
+	var anomalies []*domain.Anomaly
+	for _, day := range days {
+		baseline := &stats{}
+		active := 0
+		for i := 1; i <= d.options.SpikeDays; i++ {
+			total := totals[day.AddDate(0, 0, -i)]
+			baseline.add(total)
+			if total > 0 {
+				active++
+			}
+		}
+
+		if active >= d.options.MinHistory && d.outlier(totals[day], baseline) {
+			anomalies = append(anomalies, &domain.Anomaly{
+				Kind:     domain.AnomalyDailySpike,
+				Date:     day,
+				Amount:   round(totals[day]),
+				Expected: round(baseline.mean),
+				Reason:   fmt.Sprintf("%.2f spent on %s, %.1f times the daily average of %.2f over the %d days before", totals[day], day.Format("2006-01-02"), totals[day]/baseline.mean, baseline.mean, d.options.SpikeDays),
+			})
+		}
+	}
+	return anomalies
+}
+
+// outlier reports whether amount is more than the threshold of standard deviations above the
+// mean, and at least twice the mean so that steady amounts do not flag small changes
+func (d *Detector) outlier(amount float64, baseline *stats) bool {
+	return baseline.mean > 0 && amount >= 2*baseline.mean && amount > baseline.mean+d.options.Threshold*baseline.stddev()
+}
+
+// spending returns the money spent, oldest first
+func spending(expenditures []*domain.Expenditure) []*domain.Expenditure {
+	spent := make([]*domain.Expenditure, 0, len(expenditures))
+	for _, expenditure := range expenditures {
+		if expenditure.RefundOf == uuid.Nil && !expenditure.Planned {
+			spent = append(spent, expenditure)
+		}
+	}
+	sort.SliceStable(spent, func(i, j int) bool {
+		return spent[i].Date.Before(spent[j].Date)
+	})
+	return spent
+}
+
+func without(expenditures []*domain.Expenditure, id uuid.UUID) []*domain.Expenditure {
+	others := make([]*domain.Expenditure, 0, len(expenditures))
+	for _, expenditure := range expenditures {
+		if expenditure.ID != id {
+			others = append(others, expenditure)
+		}
+	}
+	return others
+}
+
+// startOfDay returns the calendar day of t, in UTC so days compare equal whatever the offset
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// stats keeps a running mean and variance (Welford's algorithm)
+type stats struct {
+	n    int
+	mean float64
+	m2   float64
+}
+
+func (s *stats) add(x float64) {
+	s.n++
+	delta := x - s.mean
+	s.mean += delta / float64(s.n)
+	s.m2 += delta * (x - s.mean)
+}
+
+func (s *stats) stddev() float64 {
+	if s.n < 2 {
+		return 0
+	}
+	return math.Sqrt(s.m2 / float64(s.n-1))
+}
+
+func add(groups map[uuid.UUID]*stats, key uuid.UUID, x float64) {
+	if groups[key] == nil {
+		groups[key] = &stats{}
+	}
+	groups[key].add(x)
+}
@@ -0,0 +1,56 @@
+package anomalies
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+)
+
+// Notifier is told about every anomaly a new expenditure causes; it must not block the caller
+type Notifier interface {
+	NotifyAnomaly(anomaly *domain.Anomaly)
+}
+
+// AlertingRepository wraps an ExpenditureRepository and checks every new expenditure for
+// anomalies, telling the notifiers about those found
+type AlertingRepository struct {
+	domain.ExpenditureWrapper
+	detector  *Detector
+	notifiers []Notifier
+	logger    *slog.Logger
+}
+
+// NewAlertingRepository creates a new AlertingRepository around the given repository
+func NewAlertingRepository(inner domain.ExpenditureRepository, detector *Detector, logger *slog.Logger, notifiers ...Notifier) *AlertingRepository {
+	return &AlertingRepository{
+		ExpenditureWrapper: domain.ExpenditureWrapper{ExpenditureRepository: inner},
+		detector:           detector,
+		notifiers:          notifiers,
+		logger:             logger,
+	}
+}
+
+// AddExpenditure adds the expenditure and alerts about the anomalies it causes. A failed check
+// is logged, the expenditure is added anyway
+func (r *AlertingRepository) AddExpenditure(expenditure *domain.Expenditure) error {
+	if err := r.ExpenditureRepository.AddExpenditure(expenditure); err != nil {
+		return err
+	}
+
+	var history []*domain.Expenditure
+	err := domain.EachExpenditure(r.ExpenditureRepository, func(expenditure *domain.Expenditure) error {
+		history = append(history, expenditure)
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("Failed to get expenditures for anomaly check", "error", err, "id", expenditure.ID)
+		return nil
+	}
+
+	for _, anomaly := range r.detector.Check(history, expenditure) {
+		r.logger.Info("Detected anomaly", "kind", anomaly.Kind, "expenditure_id", expenditure.ID, "amount", anomaly.Amount, "expected", anomaly.Expected)
+		for _, notifier := range r.notifiers {
+			notifier.NotifyAnomaly(anomaly)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// Package anonymize scrambles expenditures for sharing, e.g. in bug reports or demos, while
+// keeping the shape of the data: the same description, merchant, account or member always maps
+// to the same pseudonym, dates and categories are kept, and amounts only move by a small random
+// factor.
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"go-expense-tracker/domain"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// DefaultJitter is the largest relative change of an amount, 0.05 for ±5%
+const DefaultJitter = 0.05
+
+// coordinatePrecision rounds coordinates to two decimals, about a kilometre
+const coordinatePrecision = 100
+
+// Anonymizer replaces identifying values by pseudonyms derived from a secret key. Without the key
+// pseudonyms cannot be traced back, even by guessing likely descriptions
+type Anonymizer struct {
+	key    []byte
+	jitter float64
+}
+
+// New creates an Anonymizer with a random key, so two exports never share pseudonyms
+func New(jitter float64) *Anonymizer {
+	key := make([]byte, 32)
+	rand.Read(key)
+	return &Anonymizer{key: key, jitter: jitter}
+}
+
+// Expenditure returns an anonymized copy of the expenditure; the original is left untouched
+func (a *Anonymizer) Expenditure(e *domain.Expenditure) *domain.Expenditure {
+	anonymized := *e
+	anonymized.ID = a.uuid("id", e.ID)
+	anonymized.Description = a.pseudonym("Expense", e.Description)
+
+	if e.MerchantId != uuid.Nil {
+		anonymized.MerchantId = a.uuid("merchant", e.MerchantId)
+	}
+	if e.IsRefund() {
+		anonymized.RefundOf = a.uuid("id", e.RefundOf)
+	}
+	// Members and accounts keep apart, while the data no longer names them
+	if e.CreatedBy != uuid.Nil {
+		anonymized.CreatedBy = a.uuid("member", e.CreatedBy)
+	}
+	if e.AccountId != uuid.Nil {
+		anonymized.AccountId = a.uuid("account", e.AccountId)
+	}
+
+	anonymized.Tags = make([]string, len(e.Tags))
+	for i, tag := range e.Tags {
+		anonymized.Tags[i] = "tag-" + a.hash("tag", tag)
+	}
+
+	// Scale everything derived from the amount alike so that totals and taxes stay consistent
+	factor := a.factor(e.ID)
+	anonymized.Amount = roundCents(e.Amount * factor)
+	anonymized.TaxAmount = roundCents(e.TaxAmount * factor)
+	anonymized.OriginalAmount = roundCents(e.OriginalAmount * factor)
+	if e.Quantity > 0 {
+		anonymized.UnitPrice = e.UnitPrice * factor
+		anonymized.Amount = roundCents(e.Quantity * anonymized.UnitPrice)
+	}
+	if anonymized.Amount <= 0 && !e.IsRefund() {
+		anonymized.Amount = 0.01
+	}
+
+	if e.Location != nil {
+		anonymized.Location = &domain.Location{
+			Latitude:  math.Round(e.Location.Latitude*coordinatePrecision) / coordinatePrecision,
+			Longitude: math.Round(e.Location.Longitude*coordinatePrecision) / coordinatePrecision,
+			City:      e.Location.City,
+		}
+		if e.Location.PlaceName != "" {
+			anonymized.Location.PlaceName = a.pseudonym("Place", e.Location.PlaceName)
+		}
+	}
+
+	return &anonymized
+}
+
+// pseudonym maps value to "<prefix> <hash>", the same value always to the same pseudonym
+func (a *Anonymizer) pseudonym(prefix, value string) string {
+	return prefix + " " + a.hash(prefix, value)
+}
+
+func (a *Anonymizer) hash(kind, value string) string {
+	return hex.EncodeToString(a.sum(kind, []byte(value))[:4])
+}
+
+func (a *Anonymizer) uuid(kind string, id uuid.UUID) uuid.UUID {
+	anonymized, _ := uuid.FromBytes(a.sum(kind, id[:])[:16])
+	// Mark the result as a version 4 UUID like the originals
+	anonymized[6] = (anonymized[6] & 0x0f) | 0x40
+	anonymized[8] = (anonymized[8] & 0x3f) | 0x80
+	return anonymized
+}
+
+// factor is a multiplier in [1-jitter, 1+jitter], fixed per expenditure
+func (a *Anonymizer) factor(id uuid.UUID) float64 {
+	unit := float64(binary.BigEndian.Uint64(a.sum("amount", id[:]))) / math.MaxUint64
+	return 1 + a.jitter*(2*unit-1)
+}
+
+func (a *Anonymizer) sum(kind string, value []byte) []byte {
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write(value)
+	return mac.Sum(nil)
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,124 @@
+package app
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"time"
+)
+
+// AccountService implements the use cases of accounts: it keeps them and reconciles their
+// expenditures against statements
+type AccountService struct {
+	accounts     domain.AccountRepository
+	expenditures domain.ExpenditureRepository
+	logger       *slog.Logger
+}
+
+// NewAccountService creates a new AccountService
+func NewAccountService(accounts domain.AccountRepository, expenditures domain.ExpenditureRepository, logger *slog.Logger) *AccountService {
+	return &AccountService{
+		accounts:     accounts,
+		expenditures: expenditures,
+		logger:       logger,
+	}
+}
+
+// Get returns an account by ID
+func (s *AccountService) Get(id string) (*domain.Account, error) {
+	account, err := s.accounts.GetAccountByID(id)
+	if err == domain.ErrAccountNotFound {
+		return nil, notFound(err)
+	}
+	return account, err
+}
+
+// List returns all accounts
+func (s *AccountService) List() ([]*domain.Account, error) {
+	return s.accounts.GetAllAccounts()
+}
+
+// Create saves a new account
+func (s *AccountService) Create(name string, openingBalance float64) (*domain.Account, error) {
+	account, err := domain.NewAccount(name, openingBalance)
+	if err != nil {
+		s.logger.Warn("Invalid account", "error", err, "name", name)
+		return nil, invalid(err)
+	}
+
+	if err := s.accounts.AddAccount(account); err != nil {
+		s.logger.Error("Failed to add account", "error", err, "id", account.ID)
+		return nil, err
+	}
+	return account, nil
+}
+
+// Reconcile compares the balance of a statement ending on endDate with the account's
+// expenditures and marks the cleared ones up to the end date reconciled, which locks them
+// against changes. The discrepancy is reported rather than refused, so the user can look for the
+// missing or wrong entries
+func (s *AccountService) Reconcile(id string, endDate time.Time, statementBalance float64) (*domain.Reconciliation, error) {
+	account, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if endDate.IsZero() {
+		return nil, invalid(errors.New("statement end date is required"))
+	}
+
+	var expenditures []*domain.Expenditure
+	err = domain.EachExpenditure(s.expenditures, func(expenditure *domain.Expenditure) error {
+		if expenditure.AccountId == account.ID {
+			expenditures = append(expenditures, expenditure)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Failed to get expenditures for reconciliation", "id", id, "error", err)
+		return nil, err
+	}
+
+	reconciliation, matched, err := account.Reconcile(expenditures, endDate, statementBalance)
+	if err != nil {
+		s.logger.Warn("Invalid statement", "id", id, "end_date", endDate, "error", err)
+		return nil, invalid(err)
+	}
+
+	for _, expenditure := range matched {
+		expenditure.Reconciled = true
+		if err := s.expenditures.UpdateExpenditure(expenditure); err != nil {
+			s.logger.Error("Failed to mark expenditure reconciled", "id", id, "expenditure_id", expenditure.ID, "error", err)
+			return nil, err
+		}
+	}
+
+	account.Reconciled(reconciliation)
+	if err := s.accounts.UpdateAccount(account); err != nil {
+		s.logger.Error("Failed to update reconciled account", "id", id, "error", err)
+		return nil, err
+	}
+
+	s.logger.Info("Reconciled account", "id", id, "end_date", reconciliation.EndDate, "reconciled", reconciliation.Reconciled, "discrepancy", reconciliation.Discrepancy)
+	return &reconciliation, nil
+}
+
+// Unlock allows changes to a reconciled expenditure again
+func (s *ExpenditureService) Unlock(id string) (*domain.Expenditure, error) {
+	expenditure, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := expenditure.Unlock(); err != nil {
+		s.logger.Warn("Expenditure cannot be unlocked", "id", id, "error", err)
+		return nil, invalid(err)
+	}
+
+	if err := s.save(&ExpenditureResult{Expenditure: expenditure}); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Unlocked reconciled expenditure", "id", id)
+	return expenditure, nil
+}
@@ -0,0 +1,169 @@
+package app
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// Allowance is the allowance of a household member: the envelope of their allowance category
+// with how it was topped up and corrected, and what the member recorded
+type Allowance struct {
+	Member       *domain.HouseholdMember
+	Balance      *domain.EnvelopeBalance
+	Adjustments  []*domain.EnvelopeAllocation // Newest first
+	Expenditures []*domain.Expenditure        // Recorded by the member in any category, newest first
+}
+
+// AllowanceService implements the allowances of household members such as children, who may
+// only spend from the envelope of their allowance category while parents review and adjust it
+type AllowanceService struct {
+	households   domain.HouseholdRepository
+	envelopes    domain.EnvelopeRepository
+	expenditures domain.ExpenditureRepository
+	categories   domain.CategoryRepository
+	logger       *slog.Logger
+}
+
+// NewAllowanceService creates a new AllowanceService; categories may be nil when the storage has
+// no support for them
+func NewAllowanceService(households domain.HouseholdRepository, envelopes domain.EnvelopeRepository, expenditures domain.ExpenditureRepository, categories domain.CategoryRepository, logger *slog.Logger) *AllowanceService {
+	return &AllowanceService{
+		households:   households,
+		envelopes:    envelopes,
+		expenditures: expenditures,
+		categories:   categories,
+		logger:       logger,
+	}
+}
+
+// Get returns the allowance of a member of a household
+func (s *AllowanceService) Get(householdID, memberID string) (*Allowance, error) {
+	member, err := s.member(householdID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member.AllowanceCategoryId == uuid.Nil {
+		return nil, notFound(domain.ErrNoAllowance)
+	}
+	return s.allowance(member)
+}
+
+// Assign makes the envelope of a category the allowance of a member of a household
+func (s *AllowanceService) Assign(householdID, memberID string, categoryID uuid.UUID) (*Allowance, error) {
+	if categoryID == uuid.Nil {
+		return nil, invalid(domain.ErrEnvelopeCategoryIdEmpty)
+	}
+	member, err := s.member(householdID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if s.categories != nil {
+		if _, err := s.categories.GetCategoryByID(categoryID.String()); err != nil {
+			if err == domain.ErrCategoryNotFound {
+				return nil, invalid(err)
+			}
+			return nil, err
+		}
+	}
+
+	if err := s.households.SetHouseholdMemberAllowance(member.ID.String(), categoryID); err != nil {
+		if err == domain.ErrMemberNotFound {
+			return nil, notFound(err)
+		}
+		s.logger.Error("Failed to set allowance", "error", err, "member_id", member.ID)
+		return nil, err
+	}
+	member.AllowanceCategoryId = categoryID
+
+	s.logger.Info("Allowance assigned", "household_id", householdID, "member_id", member.ID, "category_id", categoryID)
+	return s.allowance(member)
+}
+
+// Adjust puts money into the allowance of a member of a household, or takes it out when amount
+// is negative. No more than the balance can be taken out
+func (s *AllowanceService) Adjust(householdID, memberID string, amount float64, note string) (*Allowance, error) {
+	allowance, err := s.Get(householdID, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustment, err := domain.NewEnvelopeAdjustment(allowance.Member.AllowanceCategoryId, amount, note)
+	if err != nil {
+		return nil, invalid(err)
+	}
+	if amount < 0 && allowance.Balance.Balance < -amount {
+		s.logger.Warn("Allowance too low for adjustment", "member_id", memberID, "balance", allowance.Balance.Balance, "amount", amount)
+		return nil, conflict(domain.ErrInsufficientEnvelopeBalance)
+	}
+
+	if err := s.envelopes.AddEnvelopeAllocations([]*domain.EnvelopeAllocation{adjustment}); err != nil {
+		s.logger.Error("Failed to adjust allowance", "error", err, "member_id", memberID)
+		return nil, err
+	}
+
+	s.logger.Info("Allowance adjusted", "household_id", householdID, "member_id", memberID, "amount", amount)
+	return s.allowance(allowance.Member)
+}
+
+// member returns a member of a household by ID
+func (s *AllowanceService) member(householdID, memberID string) (*domain.HouseholdMember, error) {
+	household, err := s.households.GetHouseholdByID(householdID)
+	if err != nil {
+		if err == domain.ErrHouseholdNotFound {
+			return nil, notFound(err)
+		}
+		return nil, err
+	}
+	for _, member := range household.Members {
+		if member.ID.String() == memberID {
+			return &member, nil
+		}
+	}
+	return nil, notFound(domain.ErrMemberNotFound)
+}
+
+// allowance computes the balance of the member's allowance envelope and collects their history
+func (s *AllowanceService) allowance(member *domain.HouseholdMember) (*Allowance, error) {
+	allocations, err := s.envelopes.GetAllEnvelopeAllocations()
+	if err != nil {
+		return nil, err
+	}
+	adjustments := []*domain.EnvelopeAllocation{}
+	for _, allocation := range allocations {
+		if allocation.CategoryId == member.AllowanceCategoryId {
+			adjustments = append(adjustments, allocation)
+		}
+	}
+	opened := domain.EnvelopesOpenedAt(adjustments)
+
+	var spent []*domain.Expenditure
+	recorded := []*domain.Expenditure{}
+	err = domain.EachExpenditure(s.expenditures, func(expenditure *domain.Expenditure) error {
+		if expenditure.CategoryId == member.AllowanceCategoryId && !opened.IsZero() {
+			spent = append(spent, expenditure)
+		}
+		if expenditure.CreatedBy == member.ID {
+			recorded = append(recorded, expenditure)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	balance := &domain.EnvelopeBalance{CategoryId: member.AllowanceCategoryId}
+	if balances := domain.EnvelopeBalances(adjustments, domain.SummarizeSpending(spent)); len(balances) > 0 {
+		balance = balances[0]
+	}
+
+	sort.Slice(adjustments, func(i, j int) bool {
+		return adjustments[i].At.After(adjustments[j].At)
+	})
+	sort.Slice(recorded, func(i, j int) bool {
+		return recorded[i].Date.After(recorded[j].Date)
+	})
+	return &Allowance{Member: member, Balance: balance, Adjustments: adjustments, Expenditures: recorded}, nil
+}
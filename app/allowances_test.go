@@ -0,0 +1,101 @@
+package app_test
+
+import (
+	"errors"
+	"go-expense-tracker/app"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/services"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestAllowances(t *testing.T) {
+	storage := services.NewMemoryService(slog.New(slog.DiscardHandler))
+	allowances := app.NewAllowanceService(storage, storage, storage, storage, slog.New(slog.DiscardHandler))
+
+	household, err := domain.NewHousehold("Home", "Alex", "alex@example.com")
+	if err != nil {
+		t.Fatalf("creating household: %v", err)
+	}
+	child, err := domain.NewHouseholdMember("Sam", "", domain.RoleChild)
+	if err != nil {
+		t.Fatalf("creating child: %v", err)
+	}
+	household.Members = append(household.Members, *child)
+	if err := storage.AddHousehold(household); err != nil {
+		t.Fatalf("adding household: %v", err)
+	}
+	category, err := domain.NewCategory("Pocket money", "#F28E2B")
+	if err != nil {
+		t.Fatalf("creating category: %v", err)
+	}
+	if err := storage.AddCategory(category); err != nil {
+		t.Fatalf("adding category: %v", err)
+	}
+	householdID, childID := household.ID.String(), child.ID.String()
+
+	// The steps run in order against the same storage
+	steps := []struct {
+		name        string
+		call        func() (*app.Allowance, error)
+		wantKind    app.ErrorKind
+		wantErr     error
+		wantBalance float64
+	}{
+		{"get unassigned", func() (*app.Allowance, error) { return allowances.Get(householdID, childID) }, app.KindNotFound, domain.ErrNoAllowance, 0},
+		{"adjust unassigned", func() (*app.Allowance, error) { return allowances.Adjust(householdID, childID, 20, "") }, app.KindNotFound, domain.ErrNoAllowance, 0},
+		{"assign no category", func() (*app.Allowance, error) { return allowances.Assign(householdID, childID, uuid.Nil) }, app.KindInvalid, domain.ErrEnvelopeCategoryIdEmpty, 0},
+		{"assign unknown category", func() (*app.Allowance, error) { return allowances.Assign(householdID, childID, uuid.New()) }, app.KindInvalid, domain.ErrCategoryNotFound, 0},
+		{"assign in unknown household", func() (*app.Allowance, error) { return allowances.Assign(uuid.NewString(), childID, category.ID) }, app.KindNotFound, domain.ErrHouseholdNotFound, 0},
+		{"assign to member of another household", func() (*app.Allowance, error) { return allowances.Assign(householdID, uuid.NewString(), category.ID) }, app.KindNotFound, domain.ErrMemberNotFound, 0},
+		{"assign", func() (*app.Allowance, error) { return allowances.Assign(householdID, childID, category.ID) }, 0, nil, 0},
+		{"top up", func() (*app.Allowance, error) { return allowances.Adjust(householdID, childID, 20, "Weekly") }, 0, nil, 20},
+		{"take out more than the balance", func() (*app.Allowance, error) { return allowances.Adjust(householdID, childID, -30, "") }, app.KindConflict, domain.ErrInsufficientEnvelopeBalance, 20},
+		{"take out no amount", func() (*app.Allowance, error) { return allowances.Adjust(householdID, childID, 0, "") }, app.KindInvalid, domain.ErrInvalidEnvelopeAdjustment, 20},
+		{"take out", func() (*app.Allowance, error) { return allowances.Adjust(householdID, childID, -5, "Lost") }, 0, nil, 15},
+		{"spend", func() (*app.Allowance, error) {
+			expenditure, err := domain.NewExpenditure("Ice cream", 3, time.Now(), category.ID)
+			if err != nil {
+				return nil, err
+			}
+			expenditure.CreatedBy = child.ID
+			if err := storage.AddExpenditure(expenditure); err != nil {
+				return nil, err
+			}
+			return allowances.Get(householdID, childID)
+		}, 0, nil, 12},
+	}
+
+	for _, step := range steps {
+		allowance, err := step.call()
+		if step.wantErr != nil {
+			if app.KindOf(err) != step.wantKind || !errors.Is(err, step.wantErr) {
+				t.Fatalf("%s: got %v (kind %d), want %v (kind %d)", step.name, err, app.KindOf(err), step.wantErr, step.wantKind)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if allowance.Member.AllowanceCategoryId != category.ID {
+			t.Errorf("%s: allowance category = %s, want %s", step.name, allowance.Member.AllowanceCategoryId, category.ID)
+		}
+		if allowance.Balance.Balance != step.wantBalance {
+			t.Errorf("%s: balance = %v, want %v", step.name, allowance.Balance.Balance, step.wantBalance)
+		}
+	}
+
+	allowance, err := allowances.Get(householdID, childID)
+	if err != nil {
+		t.Fatalf("getting allowance: %v", err)
+	}
+	if len(allowance.Adjustments) != 2 || allowance.Adjustments[0].Note != "Lost" {
+		t.Errorf("adjustments = %+v, want the two adjustments newest first", allowance.Adjustments)
+	}
+	if len(allowance.Expenditures) != 1 || allowance.Expenditures[0].Description != "Ice cream" {
+		t.Errorf("expenditures = %+v, want the one recorded by the child", allowance.Expenditures)
+	}
+}
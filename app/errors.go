@@ -0,0 +1,70 @@
+// Package app holds the application services: the use cases of the expense tracker with their
+// validation, category checks and notifications, independent of the transport. The HTTP handlers
+// only decode requests, call a service and encode its result, so other transports such as gRPC
+// or GraphQL can share the same logic.
+package app
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+)
+
+// ErrorKind classifies the errors of the services so each transport can map them to its own
+// status codes
+type ErrorKind int
+
+const (
+	KindInternal    ErrorKind = iota // Storage failures and other unexpected errors
+	KindInvalid                      // The input is invalid
+	KindNotFound                     // The record to act on does not exist
+	KindConflict                     // The record exists already
+	KindRejected                     // The input is valid but refused by a rule, e.g. a category limit
+	KindUnavailable                  // The storage cannot be reached at the moment
+	KindGone                         // The record exists but can no longer be used, e.g. an expired invitation
+)
+
+// Error is an error of a service together with its kind
+type Error struct {
+	Kind ErrorKind
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// KindOf returns the kind of an error returned by a service
+func KindOf(err error) ErrorKind {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Kind
+	}
+	if errors.Is(err, domain.ErrStorageUnavailable) {
+		return KindUnavailable
+	}
+	return KindInternal
+}
+
+func invalid(err error) error {
+	return &Error{Kind: KindInvalid, Err: err}
+}
+
+func notFound(err error) error {
+	return &Error{Kind: KindNotFound, Err: err}
+}
+
+func conflict(err error) error {
+	return &Error{Kind: KindConflict, Err: err}
+}
+
+func rejected(err error) error {
+	return &Error{Kind: KindRejected, Err: err}
+}
+
+func gone(err error) error {
+	return &Error{Kind: KindGone, Err: err}
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"go-expense-tracker/domain"
+	"time"
+)
+
+// Clear settles a pending expenditure; amount and date replace the pending ones when not zero
+// and nil. The settled amount is checked against the category's limit like a new one
+func (s *ExpenditureService) Clear(id string, amount float64, date *time.Time) (*ExpenditureResult, error) {
+	expenditure, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := expenditure.Clear(amount, date); err != nil {
+		s.logger.Warn("Expenditure cannot be cleared", "id", id, "status", expenditure.Status, "error", err)
+		return nil, invalid(err)
+	}
+
+	overLimit, err := s.checkLimit(expenditure)
+	if err != nil {
+		s.logger.Warn("Cleared amount rejected by category limit", "id", id, "error", err, "amount", expenditure.Amount)
+		return nil, err
+	}
+
+	result := &ExpenditureResult{Expenditure: expenditure, OverLimit: overLimit}
+	if err := s.save(result); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Cleared expenditure", "id", id, "amount", expenditure.Amount)
+	return result, nil
+}
+
+// Cancel marks a pending expenditure that will not settle; it no longer counts as spending
+func (s *ExpenditureService) Cancel(id string) (*domain.Expenditure, error) {
+	expenditure, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := expenditure.Cancel(); err != nil {
+		s.logger.Warn("Expenditure cannot be cancelled", "id", id, "status", expenditure.Status, "error", err)
+		return nil, invalid(err)
+	}
+
+	if err := s.save(&ExpenditureResult{Expenditure: expenditure}); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Cancelled expenditure", "id", id)
+	return expenditure, nil
+}
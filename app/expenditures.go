@@ -0,0 +1,473 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/merchants"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CategoryLimitNotifier is told about every saved expenditure above its category's transaction limit
+type CategoryLimitNotifier interface {
+	NotifyCategoryLimit(expenditure *domain.Expenditure, category *domain.Category)
+}
+
+// ExpenditureInput holds the fields of an expenditure as a client sends them
+type ExpenditureInput struct {
+	ID          uuid.UUID // ID of a new expenditure, generated when uuid.Nil
+	Description string
+	Amount      float64 // Derived from quantity and unit price when 0
+	Date        time.Time
+	CategoryId  uuid.UUID // The merchant's default or the uncategorized category when uuid.Nil
+	Tags        []string
+	Quantity    float64
+	UnitPrice   float64
+	Unit        string
+	TaxRate     float64
+	TaxAmount   float64
+	MerchantId  uuid.UUID        // Matched from the description when uuid.Nil
+	Location    *domain.Location // Validated like the other fields, may be nil
+	Status      string           // Pending or cleared; cleared for new expenditures and unchanged on updates when empty
+	AccountId   uuid.UUID        // Account paid from, may be uuid.Nil
+	CreatedBy   uuid.UUID        // Household member recording a new expenditure, kept on updates
+	// Amount and currency paid abroad, both or neither; Amount is what was settled in the home currency
+	OriginalAmount   float64
+	OriginalCurrency string
+}
+
+// ExpenditureResult is an expenditure as saved, or as it would be saved when only validated
+type ExpenditureResult struct {
+	Expenditure *domain.Expenditure
+	OverLimit   *domain.Category // Category whose transaction limit in warn mode the expenditure exceeds
+}
+
+// ExpenditureService implements the use cases of expenditures: it validates the input, checks
+// the category and its limit, links the merchant, saves the expenditure and tells the notifier
+// about expenditures above their category's limit
+type ExpenditureService struct {
+	expenditures  domain.ExpenditureRepository
+	categories    domain.CategoryRepository
+	uncategorized uuid.UUID
+	merchants     *merchants.Resolver
+	limitNotifier CategoryLimitNotifier
+	futureDates   domain.FutureDatePolicy
+	logger        *slog.Logger
+}
+
+// NewExpenditureService creates a new ExpenditureService; categories and merchants may be nil when
+// the storage has no category or merchant support and limitNotifier when no one listens.
+// Expenditures created without a category go to the uncategorized category, or are rejected
+// when it is uuid.Nil. futureDates decides whether expenditures may be dated in the future
+func NewExpenditureService(expenditures domain.ExpenditureRepository, categories domain.CategoryRepository, uncategorized uuid.UUID, merchants *merchants.Resolver, limitNotifier CategoryLimitNotifier, futureDates domain.FutureDatePolicy, logger *slog.Logger) *ExpenditureService {
+	return &ExpenditureService{
+		expenditures:  expenditures,
+		categories:    categories,
+		uncategorized: uncategorized,
+		merchants:     merchants,
+		limitNotifier: limitNotifier,
+		futureDates:   futureDates,
+		logger:        logger,
+	}
+}
+
+// Get returns an expenditure by ID
+func (s *ExpenditureService) Get(id string) (*domain.Expenditure, error) {
+	expenditure, err := s.expenditures.GetExpenditureByID(id)
+	if err == domain.ErrExpenditureNotFound {
+		return nil, notFound(err)
+	}
+	return expenditure, err
+}
+
+// Prepare validates the input of a new expenditure and returns it as Create would save it
+func (s *ExpenditureService) Prepare(input ExpenditureInput) (*ExpenditureResult, error) {
+	return s.prepare(input, nil)
+}
+
+// Create validates and saves a new expenditure
+func (s *ExpenditureService) Create(input ExpenditureInput) (*ExpenditureResult, error) {
+	result, err := s.Prepare(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.expenditures.AddExpenditure(result.Expenditure); err != nil {
+		if err == domain.ErrExpenditureAlreadyExists {
+			return nil, conflict(err)
+		}
+		s.logger.Error("Failed to add expenditure", "error", err, "id", result.Expenditure.ID)
+		return nil, err
+	}
+
+	s.notifyLimit(result)
+	return result, nil
+}
+
+// CreateMany validates and saves many expenditures, all of them or none. Errors name the index
+// of the failing input
+func (s *ExpenditureService) CreateMany(inputs []ExpenditureInput) ([]*ExpenditureResult, error) {
+	results := make([]*ExpenditureResult, 0, len(inputs))
+	expenditures := make([]*domain.Expenditure, 0, len(inputs))
+	for i, input := range inputs {
+		result, err := s.Prepare(input)
+		if err != nil {
+			return nil, fmt.Errorf("expenditure %d: %w", i, err)
+		}
+		results = append(results, result)
+		expenditures = append(expenditures, result.Expenditure)
+	}
+
+	if err := domain.AddExpenditures(s.expenditures, expenditures); err != nil {
+		if err == domain.ErrExpenditureAlreadyExists {
+			return nil, conflict(err)
+		}
+		s.logger.Error("Failed to add expenditures", "error", err, "count", len(expenditures))
+		return nil, err
+	}
+
+	for _, result := range results {
+		s.notifyLimit(result)
+	}
+	return results, nil
+}
+
+// PrepareUpdate validates the new input of an existing expenditure and returns it as Update
+// would save it
+func (s *ExpenditureService) PrepareUpdate(id string, input ExpenditureInput) (*ExpenditureResult, error) {
+	existing, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.IsRefund() {
+		s.logger.Warn("Refund cannot be updated", "id", id, "refund_of", existing.RefundOf)
+		return nil, invalid(domain.ErrRefundNotEditable)
+	}
+	if existing.Reconciled {
+		s.logger.Warn("Reconciled expenditure cannot be updated", "id", id)
+		return nil, conflict(domain.ErrExpenditureReconciled)
+	}
+
+	input.ID = existing.ID
+	result, err := s.prepare(input, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds, err := s.Refunds(id)
+	if err != nil {
+		return nil, err
+	}
+	if refunded := domain.RefundedTotal(refunds, existing.ID); !domain.RefundsWithin(result.Expenditure.Amount, refunded) {
+		s.logger.Warn("Expenditure amount below its refunds", "id", id, "amount", result.Expenditure.Amount, "refunded", refunded)
+		return nil, rejected(domain.ErrAmountBelowRefunded)
+	}
+	return result, nil
+}
+
+// Update validates and saves the new input of an existing expenditure
+func (s *ExpenditureService) Update(id string, input ExpenditureInput) (*ExpenditureResult, error) {
+	result, err := s.PrepareUpdate(id, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.save(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// save stores the changes to an existing expenditure and tells the notifier when it is above its
+// category's limit
+func (s *ExpenditureService) save(result *ExpenditureResult) error {
+	if err := s.expenditures.UpdateExpenditure(result.Expenditure); err != nil {
+		if err == domain.ErrExpenditureNotFound {
+			return notFound(err)
+		}
+		// Checked by the storage too, so a refund recorded meanwhile is counted
+		if errors.Is(err, domain.ErrAmountBelowRefunded) {
+			s.logger.Warn("Expenditure amount below its refunds", "id", result.Expenditure.ID, "amount", result.Expenditure.Amount)
+			return rejected(err)
+		}
+		s.logger.Error("Failed to update expenditure", "id", result.Expenditure.ID, "error", err)
+		return err
+	}
+
+	s.notifyLimit(result)
+	return nil
+}
+
+// Delete deletes an expenditure unless it is reconciled or has refunds, which must be deleted
+// first
+func (s *ExpenditureService) Delete(id string) error {
+	expenditure, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	if expenditure.Reconciled {
+		s.logger.Warn("Reconciled expenditure cannot be deleted", "id", id)
+		return conflict(domain.ErrExpenditureReconciled)
+	}
+
+	err = s.expenditures.DeleteExpenditure(id)
+	if err == domain.ErrExpenditureNotFound {
+		return notFound(err)
+	}
+	if errors.Is(err, domain.ErrExpenditureHasRefunds) {
+		s.logger.Warn("Expenditure with refunds cannot be deleted", "id", id)
+		return conflict(err)
+	}
+	if err != nil {
+		s.logger.Error("Failed to delete expenditure", "id", id, "error", err)
+	}
+	return err
+}
+
+// Duplicate saves a copy of an expenditure under a new ID, dated date or the date of the
+// original when date is nil. The copy is recorded by the household member by, not by whoever
+// recorded the original
+func (s *ExpenditureService) Duplicate(id string, date *time.Time, by uuid.UUID) (*domain.Expenditure, error) {
+	original, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if date == nil {
+		date = &original.Date
+	}
+
+	if err := s.checkCategory(original.CategoryId); err != nil {
+		s.logger.Warn("Failed to check category of duplicate", "id", id, "category_id", original.CategoryId, "error", err)
+		return nil, err
+	}
+
+	duplicate, err := original.Duplicate(*date, by, s.futureDates, time.Now())
+	if err != nil {
+		s.logger.Warn("Invalid duplicate date", "id", id, "date", *date, "error", err)
+		return nil, invalid(err)
+	}
+
+	if err := s.expenditures.AddExpenditure(duplicate); err != nil {
+		s.logger.Error("Failed to add duplicate expenditure", "id", duplicate.ID, "original_id", id, "error", err)
+		return nil, err
+	}
+	return duplicate, nil
+}
+
+// Categorize moves the expenditures to one category. Every expenditure is checked before any is
+// changed; it returns those that changed category
+func (s *ExpenditureService) Categorize(ids []uuid.UUID, categoryID uuid.UUID) ([]*domain.Expenditure, error) {
+	if len(ids) == 0 || categoryID == uuid.Nil {
+		return nil, invalid(errors.New("ids and categoryId are required"))
+	}
+
+	if err := s.checkCategory(categoryID); err != nil {
+		s.logger.Warn("Failed to check category of categorize request", "error", err, "category_id", categoryID)
+		return nil, err
+	}
+
+	expenditures := make([]*domain.Expenditure, 0, len(ids))
+	for _, id := range ids {
+		expenditure, err := s.Get(id.String())
+		if err != nil {
+			s.logger.Warn("Failed to get expenditure for categorizing", "id", id, "error", err)
+			return nil, fmt.Errorf("%w: %s", err, id)
+		}
+		if expenditure.CategoryId == categoryID {
+			continue
+		}
+		if expenditure.Reconciled {
+			s.logger.Warn("Reconciled expenditure cannot be categorized", "id", id)
+			return nil, fmt.Errorf("%w: %s", conflict(domain.ErrExpenditureReconciled), id)
+		}
+		expenditure.CategoryId = categoryID
+
+		if _, err := s.checkLimit(expenditure); err != nil {
+			s.logger.Warn("Categorizing rejected by category limit", "id", id, "error", err, "category_id", categoryID)
+			return nil, fmt.Errorf("%w: %s", err, id)
+		}
+		expenditures = append(expenditures, expenditure)
+	}
+
+	for _, expenditure := range expenditures {
+		if err := s.expenditures.UpdateExpenditure(expenditure); err != nil {
+			s.logger.Error("Failed to categorize expenditure", "id", expenditure.ID, "error", err)
+			return nil, err
+		}
+	}
+	return expenditures, nil
+}
+
+// prepare validates the input and builds the expenditure; existing is the stored expenditure
+// when the input updates one
+func (s *ExpenditureService) prepare(input ExpenditureInput, existing *domain.Expenditure) (*ExpenditureResult, error) {
+	// Per-unit expenses may omit the amount, it is then derived from quantity and unit price
+	amount, err := domain.ResolveUnitAmount(input.Amount, input.Quantity, input.UnitPrice)
+	if err != nil {
+		s.logger.Warn("Invalid per-unit expenditure", "error", err, "amount", input.Amount, "quantity", input.Quantity, "unit_price", input.UnitPrice)
+		return nil, invalid(err)
+	}
+
+	merchant, err := s.resolveMerchant(input.MerchantId, input.Description)
+	if err != nil {
+		s.logger.Warn("Failed to resolve merchant", "error", err, "merchant_id", input.MerchantId, "description", input.Description)
+		return nil, err
+	}
+
+	// The merchant's default category is used when none is given
+	categoryID := input.CategoryId
+	if merchant != nil && categoryID == uuid.Nil {
+		categoryID = merchant.DefaultCategoryId
+	}
+	if categoryID == uuid.Nil {
+		categoryID = s.uncategorized
+	}
+
+	// Expenditures may stay in a category that has been archived since
+	if categoryID != uuid.Nil && (existing == nil || categoryID != existing.CategoryId) {
+		if err := s.checkCategory(categoryID); err != nil {
+			s.logger.Warn("Failed to check expenditure category", "error", err, "category_id", categoryID)
+			return nil, err
+		}
+	}
+
+	status, err := domain.ParseExpenditureStatus(input.Status)
+	if err != nil {
+		s.logger.Warn("Invalid expenditure status", "error", err, "status", input.Status)
+		return nil, invalid(err)
+	}
+	if input.Status == "" && existing != nil {
+		status = existing.Status
+	}
+
+	expenditure, err := domain.NewExpenditureAt(input.Description, amount, input.Date, categoryID, s.futureDates, time.Now())
+	if err != nil {
+		s.logger.Warn("Invalid expenditure", "error", err, "description", input.Description, "amount", amount, "date", input.Date)
+		return nil, invalid(err)
+	}
+	expenditure.Status = status
+	expenditure.AccountId = input.AccountId
+	expenditure.CreatedBy = input.CreatedBy
+	if existing != nil {
+		expenditure.CreatedBy = existing.CreatedBy
+	}
+	if input.ID != uuid.Nil {
+		expenditure.ID = input.ID
+	}
+	expenditure.SetTags(input.Tags)
+	if merchant != nil {
+		expenditure.MerchantId = merchant.ID
+	}
+
+	if err := expenditure.SetQuantity(input.Quantity, input.UnitPrice, input.Unit); err != nil {
+		s.logger.Warn("Invalid per-unit expenditure", "error", err, "quantity", input.Quantity, "unit", input.Unit)
+		return nil, invalid(err)
+	}
+
+	if err := expenditure.SetTax(input.TaxRate, input.TaxAmount); err != nil {
+		s.logger.Warn("Invalid expenditure tax", "error", err, "tax_rate", input.TaxRate, "tax_amount", input.TaxAmount)
+		return nil, invalid(err)
+	}
+
+	if err := expenditure.SetOriginal(input.OriginalAmount, input.OriginalCurrency); err != nil {
+		s.logger.Warn("Invalid original amount", "error", err, "original_amount", input.OriginalAmount, "original_currency", input.OriginalCurrency)
+		return nil, invalid(err)
+	}
+
+	if input.Location != nil {
+		location := input.Location
+		expenditure.Location, err = domain.NewLocation(location.Latitude, location.Longitude, location.PlaceName, location.City)
+		if err != nil {
+			s.logger.Warn("Invalid expenditure location", "error", err)
+			return nil, invalid(err)
+		}
+	}
+
+	overLimit, err := s.checkLimit(expenditure)
+	if err != nil {
+		s.logger.Warn("Expenditure rejected by category limit", "error", err, "category_id", expenditure.CategoryId, "amount", expenditure.Amount)
+		return nil, err
+	}
+
+	return &ExpenditureResult{Expenditure: expenditure, OverLimit: overLimit}, nil
+}
+
+// resolveMerchant returns the merchant given by ID or, without one, the merchant matching the
+// description
+func (s *ExpenditureService) resolveMerchant(id uuid.UUID, description string) (*domain.Merchant, error) {
+	if s.merchants == nil {
+		return nil, nil
+	}
+
+	if id != uuid.Nil {
+		merchant, err := s.merchants.Get(id)
+		if err == domain.ErrMerchantNotFound {
+			return nil, invalid(err)
+		}
+		return merchant, err
+	}
+
+	return s.merchants.Match(description)
+}
+
+// checkCategory fails when the category does not exist or is archived; unavailable categories
+// always pass
+func (s *ExpenditureService) checkCategory(id uuid.UUID) error {
+	if s.categories == nil {
+		return nil
+	}
+
+	category, err := s.categories.GetCategoryByID(id.String())
+	if err == domain.ErrCategoryNotFound {
+		return invalid(err)
+	}
+	if err != nil {
+		return err
+	}
+	if !category.Active {
+		return invalid(domain.ErrCategoryArchived)
+	}
+	return nil
+}
+
+// checkLimit applies the transaction limit of the expenditure's category. Expenditures above a
+// limit in reject mode are rejected; in warn mode the category is returned so they can be flagged
+func (s *ExpenditureService) checkLimit(expenditure *domain.Expenditure) (*domain.Category, error) {
+	if s.categories == nil || expenditure.CategoryId == uuid.Nil {
+		return nil, nil
+	}
+
+	category, err := s.categories.GetCategoryByID(expenditure.CategoryId.String())
+	if err == domain.ErrCategoryNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !category.ExceedsLimit(expenditure.Amount) {
+		return nil, nil
+	}
+	if category.LimitMode == domain.CategoryLimitReject {
+		return nil, rejected(fmt.Errorf("%w: %s allows at most %.2f", domain.ErrCategoryLimitExceeded, category.Name, category.TransactionLimit))
+	}
+	return category, nil
+}
+
+// notifyLimit reports a saved expenditure above its category's limit
+func (s *ExpenditureService) notifyLimit(result *ExpenditureResult) {
+	if result.OverLimit == nil {
+		return
+	}
+
+	expenditure, category := result.Expenditure, result.OverLimit
+	s.logger.Warn("Expenditure exceeds category limit", "id", expenditure.ID, "category_id", category.ID, "amount", expenditure.Amount, "limit", category.TransactionLimit)
+	if s.limitNotifier != nil {
+		s.limitNotifier.NotifyCategoryLimit(expenditure, category)
+	}
+}
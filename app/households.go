@@ -0,0 +1,187 @@
+package app
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"time"
+)
+
+var ErrInvalidInvitationDays = errors.New("valid_days must be between 1 and 30")
+
+// InvitationSender delivers the token of a new invitation to the email address it was issued for
+type InvitationSender interface {
+	SendInvitation(household *domain.Household, invitation *domain.Invitation, token string) error
+}
+
+// Invite is a new invitation with its token, which is not stored and only handed out here. Sent
+// reports whether the token was emailed to the invitee, in which case the inviter need not pass
+// it on
+type Invite struct {
+	Invitation *domain.Invitation
+	Token      string
+	Sent       bool
+}
+
+// HouseholdService implements the use cases of households: founding them and inviting members
+// who join with the role they were invited for
+type HouseholdService struct {
+	households domain.HouseholdRepository
+	sender     InvitationSender
+	logger     *slog.Logger
+}
+
+// NewHouseholdService creates a new HouseholdService; sender may be nil when invitations are not
+// emailed
+func NewHouseholdService(households domain.HouseholdRepository, sender InvitationSender, logger *slog.Logger) *HouseholdService {
+	return &HouseholdService{
+		households: households,
+		sender:     sender,
+		logger:     logger,
+	}
+}
+
+// Get returns a household with its members by ID
+func (s *HouseholdService) Get(id string) (*domain.Household, error) {
+	household, err := s.households.GetHouseholdByID(id)
+	if err == domain.ErrHouseholdNotFound {
+		return nil, notFound(err)
+	}
+	return household, err
+}
+
+// List returns all households, oldest first
+func (s *HouseholdService) List() ([]*domain.Household, error) {
+	return s.households.GetAllHouseholds()
+}
+
+// Create saves a new household owned by its founder
+func (s *HouseholdService) Create(name, founderName, founderEmail string) (*domain.Household, error) {
+	household, err := domain.NewHousehold(name, founderName, founderEmail)
+	if err != nil {
+		s.logger.Warn("Invalid household", "error", err, "name", name)
+		return nil, invalid(err)
+	}
+
+	if err := s.households.AddHousehold(household); err != nil {
+		s.logger.Error("Failed to add household", "error", err, "id", household.ID)
+		return nil, err
+	}
+	return household, nil
+}
+
+// Invite issues an invitation to a household valid for validDays, 7 when zero, and emails its
+// token when an email address is given and a sender is configured. When sending fails the
+// invitation stands and the token is returned unsent, so the inviter can pass it on instead
+func (s *HouseholdService) Invite(householdID, email string, role domain.HouseholdRole, validDays int) (*Invite, error) {
+	if validDays == 0 {
+		validDays = 7
+	}
+	if validDays < 0 || validDays > 30 {
+		return nil, invalid(ErrInvalidInvitationDays)
+	}
+	if role == "" {
+		role = domain.RoleMember
+	}
+
+	household, err := s.Get(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, token, err := domain.NewInvitation(household.ID, email, role, time.Duration(validDays)*24*time.Hour)
+	if err != nil {
+		s.logger.Warn("Invalid invitation", "error", err, "household_id", householdID, "role", role)
+		return nil, invalid(err)
+	}
+	if err := s.households.AddInvitation(invitation); err != nil {
+		if err == domain.ErrHouseholdNotFound {
+			return nil, notFound(err)
+		}
+		s.logger.Error("Failed to add invitation", "error", err, "id", invitation.ID)
+		return nil, err
+	}
+
+	invite := &Invite{Invitation: invitation, Token: token}
+	if s.sender != nil && invitation.Email != "" {
+		if err := s.sender.SendInvitation(household, invitation, token); err != nil {
+			s.logger.Error("Failed to send invitation, returning its token instead", "error", err, "id", invitation.ID)
+		} else {
+			invite.Sent = true
+		}
+	}
+	return invite, nil
+}
+
+// Invitations returns the invitations to a household, newest first
+func (s *HouseholdService) Invitations(householdID string) ([]*domain.Invitation, error) {
+	if _, err := s.Get(householdID); err != nil {
+		return nil, err
+	}
+	invitations, err := s.households.GetInvitations(householdID)
+	if invitations == nil {
+		invitations = []*domain.Invitation{}
+	}
+	return invitations, err
+}
+
+// Revoke withdraws a pending invitation to a household
+func (s *HouseholdService) Revoke(householdID, invitationID string) (*domain.Invitation, error) {
+	invitation, err := s.households.GetInvitationByID(invitationID)
+	if err == domain.ErrInvitationNotFound || (err == nil && invitation.HouseholdId.String() != householdID) {
+		return nil, notFound(domain.ErrInvitationNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := invitation.Revoke(time.Now()); err != nil {
+		return nil, invitationError(err)
+	}
+	if err := s.households.RevokeInvitation(invitation); err != nil {
+		s.logger.Warn("Failed to revoke invitation", "error", err, "id", invitationID)
+		return nil, invitationError(err)
+	}
+	return invitation, nil
+}
+
+// Accept adds a member named name to the household of the invitation with the token, with the
+// role it was issued for, and returns the household. An invitation is used once
+func (s *HouseholdService) Accept(token, name string) (*domain.Household, *domain.HouseholdMember, error) {
+	invitation, err := s.households.GetInvitationByTokenHash(domain.HashInvitationToken(token))
+	if err != nil {
+		return nil, nil, invitationError(err)
+	}
+
+	member, err := invitation.Accept(name, time.Now())
+	if err != nil {
+		if err == domain.ErrMemberNameEmpty {
+			return nil, nil, invalid(err)
+		}
+		return nil, nil, invitationError(err)
+	}
+	if err := s.households.AcceptInvitation(invitation, member); err != nil {
+		s.logger.Warn("Failed to accept invitation", "error", err, "id", invitation.ID)
+		return nil, nil, invitationError(err)
+	}
+
+	household, err := s.Get(invitation.HouseholdId.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	s.logger.Info("Member joined household", "household_id", household.ID, "member_id", member.ID, "role", member.Role)
+	return household, member, nil
+}
+
+// invitationError gives the errors of invitations that can no longer be used their kind
+func invitationError(err error) error {
+	switch err {
+	case domain.ErrInvitationNotFound, domain.ErrHouseholdNotFound:
+		return notFound(err)
+	case domain.ErrInvitationExpired, domain.ErrInvitationRevoked:
+		return gone(err)
+	case domain.ErrInvitationAccepted:
+		return conflict(err)
+	}
+	return err
+}
@@ -0,0 +1,161 @@
+package app_test
+
+import (
+	"errors"
+	"go-expense-tracker/app"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/services"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestInvitations(t *testing.T) {
+	storage := services.NewMemoryService(slog.New(slog.DiscardHandler))
+	households := app.NewHouseholdService(storage, nil, slog.New(slog.DiscardHandler))
+
+	household, err := households.Create("Home", "Alex", "alex@example.com")
+	if err != nil {
+		t.Fatalf("creating household: %v", err)
+	}
+	other, err := households.Create("Cabin", "Robin", "")
+	if err != nil {
+		t.Fatalf("creating household: %v", err)
+	}
+	householdID := household.ID.String()
+
+	invite := func() *app.Invite {
+		invite, err := households.Invite(householdID, "sam@example.com", domain.RoleViewer, 0)
+		if err != nil {
+			t.Fatalf("inviting: %v", err)
+		}
+		return invite
+	}
+	accepted, revoked := invite(), invite()
+	// Invitations cannot be issued expired, so one is stored directly
+	expired, expiredToken, err := domain.NewInvitation(household.ID, "", domain.RoleMember, -time.Minute)
+	if err != nil {
+		t.Fatalf("creating invitation: %v", err)
+	}
+	if err := storage.AddInvitation(expired); err != nil {
+		t.Fatalf("adding invitation: %v", err)
+	}
+
+	accept := func(token, name string) func() error {
+		return func() error { _, _, err := households.Accept(token, name); return err }
+	}
+	revoke := func(householdID string, invitation *domain.Invitation) func() error {
+		return func() error { _, err := households.Revoke(householdID, invitation.ID.String()); return err }
+	}
+
+	// The steps run in order against the same storage
+	steps := []struct {
+		name     string
+		call     func() error
+		wantKind app.ErrorKind
+		wantErr  error
+	}{
+		{"invite for too long", func() error { _, err := households.Invite(householdID, "", domain.RoleMember, 31); return err }, app.KindInvalid, app.ErrInvalidInvitationDays},
+		{"invite with an unknown role", func() error { _, err := households.Invite(householdID, "", "boss", 7); return err }, app.KindInvalid, domain.ErrInvalidHouseholdRole},
+		{"invite to an unknown household", func() error { _, err := households.Invite(uuid.NewString(), "", domain.RoleMember, 7); return err }, app.KindNotFound, domain.ErrHouseholdNotFound},
+		{"accept an unknown token", accept("guess", "Sam"), app.KindNotFound, domain.ErrInvitationNotFound},
+		{"accept without a name", accept(accepted.Token, " "), app.KindInvalid, domain.ErrMemberNameEmpty},
+		{"accept", accept(accepted.Token, "Sam"), 0, nil},
+		{"accept twice", accept(accepted.Token, "Kim"), app.KindConflict, domain.ErrInvitationAccepted},
+		{"revoke once accepted", revoke(householdID, accepted.Invitation), app.KindConflict, domain.ErrInvitationAccepted},
+		{"revoke in another household", revoke(other.ID.String(), revoked.Invitation), app.KindNotFound, domain.ErrInvitationNotFound},
+		{"revoke", revoke(householdID, revoked.Invitation), 0, nil},
+		{"revoke twice", revoke(householdID, revoked.Invitation), app.KindGone, domain.ErrInvitationRevoked},
+		{"accept once revoked", accept(revoked.Token, "Kim"), app.KindGone, domain.ErrInvitationRevoked},
+		{"accept once expired", accept(expiredToken, "Kim"), app.KindGone, domain.ErrInvitationExpired},
+		{"revoke once expired", revoke(householdID, expired), app.KindGone, domain.ErrInvitationExpired},
+	}
+
+	for _, step := range steps {
+		err := step.call()
+		if !errors.Is(err, step.wantErr) || (err != nil && app.KindOf(err) != step.wantKind) {
+			t.Fatalf("%s: got %v (kind %d), want %v (kind %d)", step.name, err, app.KindOf(err), step.wantErr, step.wantKind)
+		}
+	}
+
+	household, err = households.Get(householdID)
+	if err != nil {
+		t.Fatalf("getting household: %v", err)
+	}
+	if len(household.Members) != 2 || household.Members[1].Name != "Sam" || household.Members[1].Role != domain.RoleViewer || household.Members[1].Email != "sam@example.com" {
+		t.Errorf("members = %+v, want the founder and Sam, who joined as a viewer", household.Members)
+	}
+
+	invitations, err := households.Invitations(householdID)
+	if err != nil {
+		t.Fatalf("listing invitations: %v", err)
+	}
+	statuses := map[uuid.UUID]string{}
+	for _, invitation := range invitations {
+		statuses[invitation.ID] = invitation.Status(time.Now())
+		if invitation.ID == accepted.Invitation.ID && invitation.MemberId != household.Members[1].ID {
+			t.Errorf("accepted invitation names member %s, want %s", invitation.MemberId, household.Members[1].ID)
+		}
+	}
+	want := map[uuid.UUID]string{
+		accepted.Invitation.ID: domain.InvitationAccepted,
+		revoked.Invitation.ID:  domain.InvitationRevoked,
+		expired.ID:             domain.InvitationExpired,
+	}
+	for id, status := range want {
+		if statuses[id] != status {
+			t.Errorf("invitation %s is %q, want %q", id, statuses[id], status)
+		}
+	}
+}
+
+func TestInvitationAcceptedOnce(t *testing.T) {
+	storage := services.NewMemoryService(slog.New(slog.DiscardHandler))
+	households := app.NewHouseholdService(storage, nil, slog.New(slog.DiscardHandler))
+
+	household, err := households.Create("Home", "Alex", "")
+	if err != nil {
+		t.Fatalf("creating household: %v", err)
+	}
+	invite, err := households.Invite(household.ID.String(), "", domain.RoleMember, 1)
+	if err != nil {
+		t.Fatalf("inviting: %v", err)
+	}
+
+	// Everyone holding the token tries to join at once; the invitation lets only one in
+	const tries = 10
+	errs := make([]error, tries)
+	var wg sync.WaitGroup
+	for i := range tries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, errs[i] = households.Accept(invite.Token, "Sam")
+		}()
+	}
+	wg.Wait()
+
+	joined := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			joined++
+		case app.KindOf(err) != app.KindConflict || !errors.Is(err, domain.ErrInvitationAccepted):
+			t.Errorf("got %v (kind %d), want %v (kind %d)", err, app.KindOf(err), domain.ErrInvitationAccepted, app.KindConflict)
+		}
+	}
+	if joined != 1 {
+		t.Errorf("%d accepted, want 1", joined)
+	}
+
+	household, err = households.Get(household.ID.String())
+	if err != nil {
+		t.Fatalf("getting household: %v", err)
+	}
+	if len(household.Members) != 2 {
+		t.Errorf("%d members, want the founder and the one who joined", len(household.Members))
+	}
+}
@@ -0,0 +1,231 @@
+package app
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// plannedInstallments saves installments dated in the future as planned expenditures, whatever
+// the configured future date policy, so they count as spent only once due
+var plannedInstallments = domain.FutureDatePolicy{Mode: domain.FutureDatesPlanned}
+
+// InstallmentInput holds the fields of an installment purchase as a client sends them
+type InstallmentInput struct {
+	Description string
+	TotalAmount float64
+	Count       int       // Number of monthly installments
+	FirstDate   time.Time // Day of the first installment
+	CategoryId  uuid.UUID // The uncategorized category when uuid.Nil
+	Tags        []string
+}
+
+// InstallmentService implements the use cases of installment purchases: it saves the purchase
+// together with an expenditure per installment and keeps both in step when the remaining
+// installments are amended or paid off
+type InstallmentService struct {
+	purchases    domain.InstallmentRepository
+	expenditures *ExpenditureService
+	logger       *slog.Logger
+}
+
+// NewInstallmentService creates a new InstallmentService; the installments are checked against
+// categories and their limits like other expenditures
+func NewInstallmentService(purchases domain.InstallmentRepository, expenditures *ExpenditureService, logger *slog.Logger) *InstallmentService {
+	return &InstallmentService{
+		purchases:    purchases,
+		expenditures: expenditures,
+		logger:       logger,
+	}
+}
+
+// Get returns an installment purchase by ID
+func (s *InstallmentService) Get(id string) (*domain.InstallmentPurchase, error) {
+	purchase, err := s.purchases.GetInstallmentPurchaseByID(id)
+	if err == domain.ErrInstallmentPurchaseNotFound {
+		return nil, notFound(err)
+	}
+	return purchase, err
+}
+
+// List returns all installment purchases
+func (s *InstallmentService) List() ([]*domain.InstallmentPurchase, error) {
+	return s.purchases.GetAllInstallmentPurchases()
+}
+
+// Create saves a new installment purchase and the expenditures of its installments
+func (s *InstallmentService) Create(input InstallmentInput) (*domain.InstallmentPurchase, error) {
+	categoryID := input.CategoryId
+	if categoryID == uuid.Nil {
+		categoryID = s.expenditures.uncategorized
+	}
+	if categoryID != uuid.Nil {
+		if err := s.expenditures.checkCategory(categoryID); err != nil {
+			s.logger.Warn("Failed to check installment purchase category", "error", err, "category_id", categoryID)
+			return nil, err
+		}
+	}
+
+	purchase, err := domain.NewInstallmentPurchase(input.Description, input.TotalAmount, input.Count, categoryID, input.Tags, input.FirstDate)
+	if err != nil {
+		s.logger.Warn("Invalid installment purchase", "error", err, "description", input.Description, "total_amount", input.TotalAmount, "count", input.Count)
+		return nil, invalid(err)
+	}
+
+	results, err := s.prepare(purchase, 0, purchase.Installments)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.add(results); err != nil {
+		return nil, err
+	}
+
+	if err := s.purchases.AddInstallmentPurchase(purchase); err != nil {
+		s.logger.Error("Failed to add installment purchase", "error", err, "id", purchase.ID)
+		s.remove(purchase.Installments)
+		return nil, err
+	}
+
+	s.logger.Info("Created installment purchase", "id", purchase.ID, "installments", len(purchase.Installments))
+	return purchase, nil
+}
+
+// Amend replaces the remaining installments of a purchase with count new ones splitting
+// remainingAmount; the paid installments stay as they are
+func (s *InstallmentService) Amend(id string, count int, remainingAmount float64) (*domain.InstallmentPurchase, error) {
+	purchase, err := s.edit(id)
+	if err != nil {
+		return nil, err
+	}
+
+	paid := len(purchase.Installments)
+	removed, added, err := purchase.Amend(count, remainingAmount, time.Now())
+	if err != nil {
+		s.logger.Warn("Invalid installment amendment", "id", id, "error", err, "count", count, "remaining_amount", remainingAmount)
+		return nil, invalid(err)
+	}
+	paid -= len(removed)
+
+	results, err := s.prepare(purchase, paid, added)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.replace(purchase, removed, results); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Amended installment purchase", "id", id, "removed", len(removed), "added", len(added))
+	return purchase, nil
+}
+
+// PayOff replaces the remaining installments of a purchase with one expenditure today for
+// their sum
+func (s *InstallmentService) PayOff(id string) (*domain.InstallmentPurchase, error) {
+	purchase, err := s.edit(id)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, payoff, err := purchase.PayOff(time.Now())
+	if err != nil {
+		s.logger.Warn("Installment purchase cannot be paid off", "id", id, "error", err)
+		return nil, invalid(err)
+	}
+
+	results, err := s.prepare(purchase, len(purchase.Installments)-1, []domain.Installment{payoff})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.replace(purchase, removed, results); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Paid off installment purchase", "id", id, "removed", len(removed), "amount", payoff.Amount)
+	return purchase, nil
+}
+
+// edit returns a copy of an installment purchase to change, so a storage handing out the stored
+// purchase keeps it unchanged when the change fails
+func (s *InstallmentService) edit(id string) (*domain.InstallmentPurchase, error) {
+	purchase, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	copied := *purchase
+	return &copied, nil
+}
+
+// prepare builds the expenditures of installments, the first being the from-th of the purchase
+func (s *InstallmentService) prepare(purchase *domain.InstallmentPurchase, from int, installments []domain.Installment) ([]*ExpenditureResult, error) {
+	now := time.Now()
+	results := make([]*ExpenditureResult, 0, len(installments))
+	for i, installment := range installments {
+		expenditure, err := domain.NewExpenditureAt(purchase.InstallmentDescription(from+i), installment.Amount, installment.Date, purchase.CategoryId, plannedInstallments, now)
+		if err != nil {
+			s.logger.Warn("Invalid installment", "error", err, "purchase_id", purchase.ID, "date", installment.Date)
+			return nil, invalid(err)
+		}
+		expenditure.ID = installment.ExpenditureId
+		expenditure.SetTags(purchase.Tags)
+
+		overLimit, err := s.expenditures.checkLimit(expenditure)
+		if err != nil {
+			s.logger.Warn("Installment rejected by category limit", "error", err, "purchase_id", purchase.ID, "amount", expenditure.Amount)
+			return nil, err
+		}
+		results = append(results, &ExpenditureResult{Expenditure: expenditure, OverLimit: overLimit})
+	}
+	return results, nil
+}
+
+// add saves the expenditures of installments, all of them or none where the storage allows
+func (s *InstallmentService) add(results []*ExpenditureResult) error {
+	expenditures := make([]*domain.Expenditure, 0, len(results))
+	for _, result := range results {
+		expenditures = append(expenditures, result.Expenditure)
+	}
+
+	if err := domain.AddExpenditures(s.expenditures.expenditures, expenditures); err != nil {
+		s.logger.Error("Failed to add installments", "error", err, "count", len(expenditures))
+		return err
+	}
+
+	for _, result := range results {
+		s.expenditures.notifyLimit(result)
+	}
+	return nil
+}
+
+// replace saves the new installments of an amended purchase, then the purchase, and deletes the
+// expenditures of the replaced installments last so a failure leaves them in place
+func (s *InstallmentService) replace(purchase *domain.InstallmentPurchase, removed []domain.Installment, results []*ExpenditureResult) error {
+	if err := s.add(results); err != nil {
+		return err
+	}
+
+	if err := s.purchases.UpdateInstallmentPurchase(purchase); err != nil {
+		s.logger.Error("Failed to update installment purchase", "error", err, "id", purchase.ID)
+		added := make([]domain.Installment, 0, len(results))
+		for _, result := range results {
+			added = append(added, domain.Installment{ExpenditureId: result.Expenditure.ID})
+		}
+		s.remove(added)
+		return err
+	}
+
+	s.remove(removed)
+	return nil
+}
+
+// remove deletes the expenditures of installments; ones deleted already are skipped and other
+// failures only logged, leaving an expenditure the user can delete
+func (s *InstallmentService) remove(installments []domain.Installment) {
+	for _, installment := range installments {
+		err := s.expenditures.expenditures.DeleteExpenditure(installment.ExpenditureId.String())
+		if err != nil && err != domain.ErrExpenditureNotFound {
+			s.logger.Error("Failed to delete installment", "error", err, "expenditure_id", installment.ExpenditureId)
+		}
+	}
+}
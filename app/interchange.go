@@ -0,0 +1,358 @@
+package app
+
+import (
+	"fmt"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/interchange"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// interchangeNamespace derives the IDs of imported expenditures from their source and reference,
+// so importing the same file twice adds nothing the second time
+var interchangeNamespace = uuid.MustParse("0b6f7a52-3c1e-4d8b-a9f4-5e27c1d3b860")
+
+// importColors colour the categories created by imports, in turn
+var importColors = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac"}
+
+// ImportSummary is what an import of another tool's file added, or would add on a dry run
+type ImportSummary struct {
+	Imported   int      `json:"imported"`           // Expenditures or budgets added
+	Duplicates int      `json:"duplicates"`         // Already imported before, left alone
+	Skipped    int      `json:"skipped"`            // Rows that are no spending, e.g. income or transfers
+	Categories []string `json:"categories_created"` // Paths of the categories created, e.g. "Food / Groceries"
+	Accounts   []string `json:"accounts_created"`
+	DryRun     bool     `json:"dry_run"`
+}
+
+// InterchangeService implements imports from other finance tools: it finds or creates the
+// categories and accounts of the transactions read and adds them as expenditures through the
+// expenditure service, or their budgets as monthly budgets
+type InterchangeService struct {
+	expenditures *ExpenditureService
+	repository   domain.ExpenditureRepository
+	categories   domain.CategoryRepository
+	accounts     domain.AccountRepository
+	budgets      domain.BudgetRepository
+	logger       *slog.Logger
+}
+
+// NewInterchangeService creates a new InterchangeService; categories, accounts and budgets may be
+// nil when the storage has no support for them, in which case transactions are imported
+// uncategorized or without their account. Budgets can only be imported with both categories and
+// budgets
+func NewInterchangeService(expenditures *ExpenditureService, repository domain.ExpenditureRepository, categories domain.CategoryRepository, accounts domain.AccountRepository, budgets domain.BudgetRepository, logger *slog.Logger) *InterchangeService {
+	return &InterchangeService{
+		expenditures: expenditures,
+		repository:   repository,
+		categories:   categories,
+		accounts:     accounts,
+		budgets:      budgets,
+		logger:       logger,
+	}
+}
+
+// ImportTransactions adds the transactions read from a file of source, e.g. ynab, as expenditures,
+// all of them or none; skipped counts the rows the reader left out. Categories and accounts are
+// matched by name, case-insensitively, and created when missing. Transactions imported before
+// are recognized by their reference and counted as duplicates. A dry run validates the
+// transactions and reports what would be created without saving anything
+func (s *InterchangeService) ImportTransactions(source string, transactions []interchange.Transaction, skipped int, dryRun bool) (*ImportSummary, error) {
+	summary := &ImportSummary{Skipped: skipped, Categories: []string{}, Accounts: []string{}, DryRun: dryRun}
+
+	categories, err := s.categoryIndex()
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.accountIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := make([]ExpenditureInput, 0, len(transactions))
+	imported := make(map[uuid.UUID]bool, len(transactions))
+	for _, transaction := range transactions {
+		id := uuid.NewSHA1(interchangeNamespace, []byte(source+"\x00"+transaction.Reference))
+		if imported[id] {
+			summary.Duplicates++
+			continue
+		}
+		if _, err := s.repository.GetExpenditureByID(id.String()); err == nil {
+			summary.Duplicates++
+			continue
+		} else if err != domain.ErrExpenditureNotFound {
+			s.logger.Error("Failed to look up imported expenditure", "error", err, "id", id)
+			return nil, err
+		}
+		imported[id] = true
+
+		categoryID, err := categories.resolve(transaction.Category, dryRun, summary)
+		if err != nil {
+			return nil, err
+		}
+		accountID, err := accounts.resolve(transaction.Account, dryRun, summary)
+		if err != nil {
+			return nil, err
+		}
+
+		// Rows with neither payee nor memo still need a description
+		description := transaction.Description
+		if description == "" {
+			description = "Imported from " + source
+		}
+		input := ExpenditureInput{
+			ID:          id,
+			Description: description,
+			Amount:      transaction.Amount,
+			Date:        transaction.Date,
+			CategoryId:  categoryID,
+			Tags:        []string{source},
+			AccountId:   accountID,
+		}
+		if transaction.Pending {
+			input.Status = string(domain.StatusPending)
+		}
+		inputs = append(inputs, input)
+	}
+
+	if dryRun {
+		for i, input := range inputs {
+			if _, err := s.expenditures.Prepare(input); err != nil {
+				s.logger.Warn("Invalid imported transaction", "error", err, "source", source, "index", i)
+				return nil, fmt.Errorf("expenditure %d: %w", i, err)
+			}
+		}
+	} else if len(inputs) > 0 {
+		if _, err := s.expenditures.CreateMany(inputs); err != nil {
+			return nil, err
+		}
+	}
+
+	summary.Imported = len(inputs)
+	s.logger.Info("Imported transactions", "source", source, "imported", summary.Imported, "duplicates", summary.Duplicates, "skipped", summary.Skipped, "dry_run", dryRun)
+	return summary, nil
+}
+
+// ImportBudgets turns the plan of a YNAB budget into monthly budgets with rollover, as YNAB carries
+// what is left of a category into the next month. Every category gets one budget, limited to what
+// was assigned in the latest month with an assignment and starting in the first one. Categories
+// already with a monthly budget are counted as duplicates
+func (s *InterchangeService) ImportBudgets(source string, plans []interchange.BudgetPlan, skipped int, dryRun bool) (*ImportSummary, error) {
+	summary := &ImportSummary{Skipped: skipped, Categories: []string{}, Accounts: []string{}, DryRun: dryRun}
+
+	type plan struct {
+		category []string
+		first    time.Time
+		latest   time.Time
+		amount   float64
+	}
+	byCategory := make(map[string]*plan)
+	var keys []string
+	for _, p := range plans {
+		if p.Assigned <= 0 {
+			continue
+		}
+		key := strings.ToLower(strings.Join(p.Category, "\x00"))
+		current, ok := byCategory[key]
+		if !ok {
+			current = &plan{category: p.Category, first: p.Month, latest: p.Month, amount: p.Assigned}
+			byCategory[key] = current
+			keys = append(keys, key)
+		}
+		if p.Month.Before(current.first) {
+			current.first = p.Month
+		}
+		if !p.Month.Before(current.latest) {
+			current.latest, current.amount = p.Month, p.Assigned
+		}
+	}
+	sort.Strings(keys)
+
+	categories, err := s.categoryIndex()
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.budgets.GetAllBudgets()
+	if err != nil {
+		s.logger.Error("Failed to get budgets for import", "error", err)
+		return nil, err
+	}
+	budgeted := make(map[uuid.UUID]bool, len(existing))
+	for _, budget := range existing {
+		if budget.Period == domain.BudgetMonthly && budget.CategoryId != uuid.Nil {
+			budgeted[budget.CategoryId] = true
+		}
+	}
+
+	var budgets []*domain.Budget
+	for _, key := range keys {
+		p := byCategory[key]
+		categoryID, err := categories.resolve(p.category, dryRun, summary)
+		if err != nil {
+			return nil, err
+		}
+		if categoryID != uuid.Nil && budgeted[categoryID] {
+			summary.Duplicates++
+			continue
+		}
+
+		budget, err := domain.NewBudget(p.category[len(p.category)-1], p.amount, domain.BudgetMonthly, categoryID, p.first, nil, true)
+		if err != nil {
+			s.logger.Warn("Invalid imported budget", "error", err, "source", source, "category", strings.Join(p.category, " / "))
+			return nil, invalid(err)
+		}
+		budgets = append(budgets, budget)
+	}
+
+	if !dryRun {
+		for _, budget := range budgets {
+			if err := s.budgets.AddBudget(budget); err != nil {
+				s.logger.Error("Failed to add imported budget", "error", err, "id", budget.ID)
+				return nil, err
+			}
+		}
+	}
+
+	summary.Imported = len(budgets)
+	s.logger.Info("Imported budgets", "source", source, "imported", summary.Imported, "duplicates", summary.Duplicates, "dry_run", dryRun)
+	return summary, nil
+}
+
+// categoryIndex finds categories by their parent and name
+type categoryIndex struct {
+	service  *InterchangeService
+	children map[uuid.UUID]map[string]uuid.UUID
+	planned  map[uuid.UUID]bool // Categories a dry run would create
+	created  int
+}
+
+func (s *InterchangeService) categoryIndex() (*categoryIndex, error) {
+	index := &categoryIndex{service: s, children: make(map[uuid.UUID]map[string]uuid.UUID), planned: make(map[uuid.UUID]bool)}
+	if s.categories == nil {
+		return index, nil
+	}
+
+	categories, err := s.categories.GetAllCategories()
+	if err != nil {
+		s.logger.Error("Failed to get categories for import", "error", err)
+		return nil, err
+	}
+	// Active categories win over archived ones of the same name, which cannot take expenditures
+	for _, category := range categories {
+		name := strings.ToLower(category.Name)
+		siblings := index.siblings(category.ParentID)
+		if _, ok := siblings[name]; !ok || category.Active {
+			siblings[name] = category.ID
+		}
+	}
+	return index, nil
+}
+
+func (c *categoryIndex) siblings(parentID uuid.UUID) map[string]uuid.UUID {
+	siblings, ok := c.children[parentID]
+	if !ok {
+		siblings = make(map[string]uuid.UUID)
+		c.children[parentID] = siblings
+	}
+	return siblings
+}
+
+// resolve returns the category at the end of a path, creating the categories missing on the way.
+// On a dry run nothing is created and categories that would be are uuid.Nil, uncategorized
+func (c *categoryIndex) resolve(path []string, dryRun bool, summary *ImportSummary) (uuid.UUID, error) {
+	if c.service.categories == nil || len(path) == 0 {
+		return uuid.Nil, nil
+	}
+
+	parentID := uuid.Nil
+	for i, name := range path {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		siblings := c.siblings(parentID)
+		id, ok := siblings[strings.ToLower(name)]
+		if !ok {
+			summary.Categories = append(summary.Categories, strings.Join(path[:i+1], " / "))
+			if dryRun {
+				id = uuid.New()
+				c.planned[id] = true
+			} else {
+				category, err := domain.NewCategory(name, importColors[c.created%len(importColors)])
+				if err != nil {
+					return uuid.Nil, invalid(err)
+				}
+				category.ParentID = parentID
+				if err := c.service.categories.AddCategory(category); err != nil {
+					c.service.logger.Error("Failed to add imported category", "error", err, "name", name)
+					return uuid.Nil, err
+				}
+				id = category.ID
+			}
+			c.created++
+			siblings[strings.ToLower(name)] = id
+		}
+		parentID = id
+	}
+
+	if c.planned[parentID] {
+		return uuid.Nil, nil
+	}
+	return parentID, nil
+}
+
+// accountIndex finds accounts by their name
+type accountIndex struct {
+	service *InterchangeService
+	byName  map[string]uuid.UUID
+}
+
+func (s *InterchangeService) accountIndex() (*accountIndex, error) {
+	index := &accountIndex{service: s, byName: make(map[string]uuid.UUID)}
+	if s.accounts == nil {
+		return index, nil
+	}
+
+	accounts, err := s.accounts.GetAllAccounts()
+	if err != nil {
+		s.logger.Error("Failed to get accounts for import", "error", err)
+		return nil, err
+	}
+	for _, account := range accounts {
+		index.byName[strings.ToLower(account.Name)] = account.ID
+	}
+	return index, nil
+}
+
+// resolve returns the account of a name, creating it when missing; on a dry run accounts that
+// would be created are uuid.Nil
+func (a *accountIndex) resolve(name string, dryRun bool, summary *ImportSummary) (uuid.UUID, error) {
+	if a.service.accounts == nil || name == "" {
+		return uuid.Nil, nil
+	}
+
+	key := strings.ToLower(name)
+	if id, ok := a.byName[key]; ok {
+		return id, nil
+	}
+
+	summary.Accounts = append(summary.Accounts, name)
+	if dryRun {
+		a.byName[key] = uuid.Nil
+		return uuid.Nil, nil
+	}
+	account, err := domain.NewAccount(name, 0)
+	if err != nil {
+		return uuid.Nil, invalid(err)
+	}
+	if err := a.service.accounts.AddAccount(account); err != nil {
+		a.service.logger.Error("Failed to add imported account", "error", err, "name", name)
+		return uuid.Nil, err
+	}
+	a.byName[key] = account.ID
+	return account.ID, nil
+}
@@ -0,0 +1,327 @@
+package app
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidErasureMode = errors.New("erasure mode must be anonymize or delete")
+
+// ErasureMode decides what happens to the expenditures a member recorded when they are erased
+type ErasureMode string
+
+const (
+	EraseAnonymize ErasureMode = "anonymize" // Keep them for the household, no longer attributed to anyone
+	EraseDelete    ErasureMode = "delete"    // Delete them together with their history
+)
+
+// ParseErasureMode reads the mode of an erasure; an empty mode anonymizes
+func ParseErasureMode(mode string) (ErasureMode, error) {
+	switch ErasureMode(mode) {
+	case "", EraseAnonymize:
+		return EraseAnonymize, nil
+	case EraseDelete:
+		return EraseDelete, nil
+	default:
+		return "", ErrInvalidErasureMode
+	}
+}
+
+// HouseholdRef names the household a member belongs to
+type HouseholdRef struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+// DataExport is everything kept about a household member, for them to take elsewhere
+type DataExport struct {
+	ExportedAt   time.Time                  `json:"exported_at"`
+	Member       *domain.HouseholdMember    `json:"member"`
+	Household    HouseholdRef               `json:"household"`
+	Invitation   *domain.Invitation         `json:"invitation,omitempty"` // The invitation the member joined with
+	Expenditures []*domain.Expenditure      `json:"expenditures"`         // Recorded by the member, newest first
+	Drafts       []*domain.Draft            `json:"drafts"`               // Saved by the member, oldest first
+	Events       []*domain.ExpenditureEvent `json:"events"`               // History of the member's expenditures, with event sourcing
+}
+
+// Erasure reports what erasing a member removed
+type Erasure struct {
+	MemberId               uuid.UUID   `json:"member_id"`
+	Mode                   ErasureMode `json:"mode"`
+	ExpendituresDeleted    int         `json:"expenditures_deleted"`
+	ExpendituresAnonymized int         `json:"expenditures_anonymized"`
+	DraftsDeleted          int         `json:"drafts_deleted"`
+	EventsScrubbed         int         `json:"events_scrubbed"`
+	ArchivedScrubbed       int         `json:"archived_scrubbed"`        // Archived expenditures anonymized or deleted
+	OutboxScrubbed         int         `json:"outbox_messages_scrubbed"` // Undelivered messages that named the member
+	LogEntriesScrubbed     int         `json:"log_entries_scrubbed"`     // Operations and activities forgotten or anonymized
+	HouseholdLeft          uuid.UUID   `json:"household_left"`
+	ErasedAt               time.Time   `json:"erased_at"`
+}
+
+// MemberScrubber is implemented by the logs kept in memory next to the storage that hold copies
+// of expenditures, the operation log and the activity feed, so erasures reach them too
+type MemberScrubber interface {
+	// ScrubMember forgets the entries about the erased expenditures and removes the member from
+	// the others, returning how many entries changed
+	ScrubMember(member uuid.UUID, erased []uuid.UUID) int
+}
+
+// PrivacyService answers the requests of members for their data: a complete export, and erasure
+// from the household with everything they recorded
+type PrivacyService struct {
+	households   domain.HouseholdRepository
+	expenditures domain.ExpenditureRepository
+	drafts       domain.DraftRepository
+	events       domain.ExpenditureEventStore
+	archives     domain.ArchiveRepository
+	outbox       domain.OutboxRepository
+	logs         []MemberScrubber
+	logger       *slog.Logger
+}
+
+// NewPrivacyService creates a new PrivacyService; drafts, events, archives and outbox may be nil
+// when the storage keeps none of them or expenditures are not event-sourced
+func NewPrivacyService(households domain.HouseholdRepository, expenditures domain.ExpenditureRepository, drafts domain.DraftRepository, events domain.ExpenditureEventStore, archives domain.ArchiveRepository, outbox domain.OutboxRepository, logs []MemberScrubber, logger *slog.Logger) *PrivacyService {
+	return &PrivacyService{
+		households:   households,
+		expenditures: expenditures,
+		drafts:       drafts,
+		events:       events,
+		archives:     archives,
+		outbox:       outbox,
+		logs:         logs,
+		logger:       logger,
+	}
+}
+
+// Export collects everything kept about a member
+func (s *PrivacyService) Export(memberID uuid.UUID) (*DataExport, error) {
+	household, err := s.household(memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &DataExport{
+		ExportedAt: time.Now(),
+		Member:     household.Member(memberID),
+		Household:  HouseholdRef{ID: household.ID, Name: household.Name},
+		Events:     []*domain.ExpenditureEvent{},
+	}
+
+	invitations, err := s.households.GetInvitations(household.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	for _, invitation := range invitations {
+		if invitation.MemberId == memberID {
+			export.Invitation = invitation
+		}
+	}
+
+	if export.Expenditures, err = s.recorded(memberID); err != nil {
+		return nil, err
+	}
+	if export.Drafts, err = s.savedDrafts(memberID); err != nil {
+		return nil, err
+	}
+
+	// The log also holds expenditures the member recorded and deleted since
+	if s.events != nil {
+		events, err := s.events.GetExpenditureEvents(0, 0)
+		if err != nil {
+			return nil, err
+		}
+		theirs := make(map[uuid.UUID]bool)
+		for _, event := range events {
+			if event.Expenditure != nil && event.Expenditure.CreatedBy == memberID {
+				theirs[event.ExpenditureID] = true
+			}
+			if theirs[event.ExpenditureID] {
+				export.Events = append(export.Events, event)
+			}
+		}
+	}
+
+	s.logger.Info("Exported member data", "member_id", memberID, "expenditures", len(export.Expenditures), "drafts", len(export.Drafts), "events", len(export.Events))
+	return export, nil
+}
+
+// Erase removes a member from their household together with their drafts, and anonymizes or
+// deletes the expenditures they recorded. The copies of those expenditures kept elsewhere, in
+// the event log, the archives, the outbox, the operation log and the activity feed, are
+// scrubbed the same way. The last owner of a household others belong to cannot be erased
+func (s *PrivacyService) Erase(memberID uuid.UUID, mode ErasureMode) (*Erasure, error) {
+	household, err := s.household(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if err := household.CanLeave(memberID); err != nil {
+		s.logger.Warn("Member cannot leave household", "error", err, "member_id", memberID, "household_id", household.ID)
+		return nil, conflict(err)
+	}
+
+	erasure := &Erasure{MemberId: memberID, Mode: mode, HouseholdLeft: household.ID}
+
+	recorded, err := s.recorded(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if mode == EraseDelete {
+		if recorded, err = s.refundsFirst(recorded); err != nil {
+			return nil, err
+		}
+	}
+	var erased []uuid.UUID
+	for _, expenditure := range recorded {
+		if mode == EraseDelete {
+			if err := s.expenditures.DeleteExpenditure(expenditure.ID.String()); err != nil {
+				s.logger.Error("Failed to delete expenditure of erased member", "error", err, "id", expenditure.ID)
+				return nil, err
+			}
+			erased = append(erased, expenditure.ID)
+			erasure.ExpendituresDeleted++
+			continue
+		}
+
+		expenditure.CreatedBy = uuid.Nil
+		if err := s.expenditures.UpdateExpenditure(expenditure); err != nil {
+			s.logger.Error("Failed to anonymize expenditure of erased member", "error", err, "id", expenditure.ID)
+			return nil, err
+		}
+		erasure.ExpendituresAnonymized++
+	}
+
+	drafts, err := s.savedDrafts(memberID)
+	if err != nil {
+		return nil, err
+	}
+	for _, draft := range drafts {
+		if err := s.drafts.DeleteDraft(draft.ID.String()); err != nil {
+			s.logger.Error("Failed to delete draft of erased member", "error", err, "id", draft.ID)
+			return nil, err
+		}
+		erasure.DraftsDeleted++
+	}
+
+	if s.events != nil {
+		if erasure.EventsScrubbed, err = s.events.ScrubExpenditureEvents(memberID, erased); err != nil {
+			s.logger.Error("Failed to scrub expenditure events of erased member", "error", err, "member_id", memberID)
+			return nil, err
+		}
+	}
+	if s.archives != nil {
+		if erasure.ArchivedScrubbed, err = s.archives.ScrubArchivedExpenditures(memberID, mode == EraseDelete); err != nil {
+			s.logger.Error("Failed to scrub archived expenditures of erased member", "error", err, "member_id", memberID)
+			return nil, err
+		}
+	}
+	// After the deletions, which queue messages of their own
+	if s.outbox != nil {
+		if erasure.OutboxScrubbed, err = s.outbox.ScrubOutboxMessages(memberID); err != nil {
+			s.logger.Error("Failed to scrub outbox messages of erased member", "error", err, "member_id", memberID)
+			return nil, err
+		}
+	}
+	for _, log := range s.logs {
+		erasure.LogEntriesScrubbed += log.ScrubMember(memberID, erased)
+	}
+
+	if err := s.households.RemoveHouseholdMember(memberID.String()); err != nil {
+		if err == domain.ErrMemberNotFound {
+			return nil, notFound(err)
+		}
+		s.logger.Error("Failed to remove erased member", "error", err, "member_id", memberID)
+		return nil, err
+	}
+
+	erasure.ErasedAt = time.Now()
+	s.logger.Info("Erased member", "member_id", memberID, "mode", mode, "household_id", household.ID,
+		"expenditures_deleted", erasure.ExpendituresDeleted, "expenditures_anonymized", erasure.ExpendituresAnonymized,
+		"drafts_deleted", erasure.DraftsDeleted, "events_scrubbed", erasure.EventsScrubbed, "archived_scrubbed", erasure.ArchivedScrubbed,
+		"outbox_scrubbed", erasure.OutboxScrubbed, "log_entries_scrubbed", erasure.LogEntriesScrubbed)
+	return erasure, nil
+}
+
+// household returns the household the member belongs to
+func (s *PrivacyService) household(memberID uuid.UUID) (*domain.Household, error) {
+	households, err := s.households.GetAllHouseholds()
+	if err != nil {
+		return nil, err
+	}
+	for _, household := range households {
+		if household.Member(memberID) != nil {
+			return household, nil
+		}
+	}
+	return nil, notFound(domain.ErrMemberNotFound)
+}
+
+// recorded returns the expenditures the member recorded, newest first
+func (s *PrivacyService) recorded(memberID uuid.UUID) ([]*domain.Expenditure, error) {
+	recorded := []*domain.Expenditure{}
+	err := domain.EachExpenditure(s.expenditures, func(expenditure *domain.Expenditure) error {
+		if expenditure.CreatedBy == memberID {
+			recorded = append(recorded, expenditure)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(recorded, func(i, j int) bool {
+		return recorded[i].Date.After(recorded[j].Date)
+	})
+	return recorded, nil
+}
+
+// refundsFirst adds the refunds of the expenditures, whoever recorded them, ahead of them: an
+// expenditure cannot be deleted before its refunds
+func (s *PrivacyService) refundsFirst(expenditures []*domain.Expenditure) ([]*domain.Expenditure, error) {
+	originals := make(map[uuid.UUID]bool, len(expenditures))
+	for _, expenditure := range expenditures {
+		originals[expenditure.ID] = true
+	}
+
+	var refunds, rest []*domain.Expenditure
+	err := domain.EachExpenditure(s.expenditures, func(expenditure *domain.Expenditure) error {
+		if originals[expenditure.RefundOf] {
+			refunds = append(refunds, expenditure)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, expenditure := range expenditures {
+		if !expenditure.IsRefund() || !originals[expenditure.RefundOf] {
+			rest = append(rest, expenditure)
+		}
+	}
+	return append(refunds, rest...), nil
+}
+
+// savedDrafts returns the drafts the member saved, oldest first
+func (s *PrivacyService) savedDrafts(memberID uuid.UUID) ([]*domain.Draft, error) {
+	saved := []*domain.Draft{}
+	if s.drafts == nil {
+		return saved, nil
+	}
+
+	drafts, err := s.drafts.GetAllDrafts()
+	if err != nil {
+		return nil, err
+	}
+	for _, draft := range drafts {
+		if draft.CreatedBy == memberID {
+			saved = append(saved, draft)
+		}
+	}
+	return saved, nil
+}
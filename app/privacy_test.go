@@ -0,0 +1,112 @@
+package app_test
+
+import (
+	"go-expense-tracker/activity"
+	"go-expense-tracker/app"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/operations"
+	"go-expense-tracker/services"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestEraseScrubsCopies(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         app.ErasureMode
+		wantArchived int // Archived expenditures left
+		wantOps      int // Operations left
+		wantActivity int // Activities left
+	}{
+		{"anonymize", app.EraseAnonymize, 1, 1, 1},
+		{"delete", app.EraseDelete, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := slog.New(slog.DiscardHandler)
+			storage := services.NewMemoryService(logger)
+			if err := storage.EnableOutbox(true); err != nil {
+				t.Fatalf("enabling outbox: %v", err)
+			}
+			recorder := operations.NewRecorder(storage, time.Hour, logger)
+			feed := activity.NewFeed(10, logger)
+			recorded := activity.NewExpenditureRepository(recorder, feed)
+			privacy := app.NewPrivacyService(storage, storage, storage, nil, storage, storage, []app.MemberScrubber{recorder, feed}, logger)
+
+			household, err := domain.NewHousehold("Home", "Alex", "")
+			if err != nil {
+				t.Fatalf("creating household: %v", err)
+			}
+			member, err := domain.NewHouseholdMember("Sam", "", domain.RoleMember)
+			if err != nil {
+				t.Fatalf("creating member: %v", err)
+			}
+			household.Members = append(household.Members, *member)
+			if err := storage.AddHousehold(household); err != nil {
+				t.Fatalf("adding household: %v", err)
+			}
+
+			add := func(repo domain.ExpenditureRepository, description string, date time.Time) {
+				expenditure, err := domain.NewExpenditure(description, 10, date, uuid.New())
+				if err != nil {
+					t.Fatalf("creating expenditure: %v", err)
+				}
+				expenditure.CreatedBy = member.ID
+				if err := repo.AddExpenditure(expenditure); err != nil {
+					t.Fatalf("adding expenditure: %v", err)
+				}
+			}
+			add(storage, "Old lunch", time.Date(2000, 6, 1, 0, 0, 0, 0, time.UTC))
+			archive, err := storage.ArchiveExpenditures(time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				t.Fatalf("archiving: %v", err)
+			}
+			add(recorded, "Lunch", time.Now())
+
+			if _, err := privacy.Erase(member.ID, tt.mode); err != nil {
+				t.Fatalf("erasing: %v", err)
+			}
+
+			archived, err := storage.GetArchivedExpenditures(archive.ID.String())
+			if err != nil {
+				t.Fatalf("getting archived expenditures: %v", err)
+			}
+			if len(archived) != tt.wantArchived {
+				t.Errorf("%d archived expenditures, want %d", len(archived), tt.wantArchived)
+			}
+			for _, expenditure := range archived {
+				if expenditure.CreatedBy == member.ID {
+					t.Errorf("archived expenditure %s names the erased member", expenditure.ID)
+				}
+			}
+
+			messages, err := storage.GetPendingOutboxMessages(10)
+			if err != nil {
+				t.Fatalf("getting outbox messages: %v", err)
+			}
+			for _, message := range messages {
+				if message.Expenditure != nil && message.Expenditure.CreatedBy == member.ID {
+					t.Errorf("outbox message %d (%s) names the erased member", message.ID, message.Topic)
+				}
+			}
+
+			operations := recorder.Recent()
+			if len(operations) != tt.wantOps {
+				t.Errorf("%d operations, want %d", len(operations), tt.wantOps)
+			}
+			for _, operation := range operations {
+				if operation.After != nil && operation.After.CreatedBy == member.ID {
+					t.Errorf("operation %s names the erased member", operation.ID)
+				}
+			}
+
+			if activities := feed.Recent(time.Time{}, 0, 10); len(activities) != tt.wantActivity {
+				t.Errorf("%d activities, want %d", len(activities), tt.wantActivity)
+			}
+		})
+	}
+}
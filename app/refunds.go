@@ -0,0 +1,88 @@
+package app
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"time"
+)
+
+// RefundInput holds the fields of a refund as a client sends them
+type RefundInput struct {
+	Amount      float64
+	Date        time.Time // Today when zero
+	Description string    // Derived from the original's description when empty
+}
+
+// Refunds returns the refunds recorded against an expenditure
+func (s *ExpenditureService) Refunds(id string) ([]*domain.Expenditure, error) {
+	original, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds := []*domain.Expenditure{}
+	err = domain.EachExpenditure(s.expenditures, func(expenditure *domain.Expenditure) error {
+		if expenditure.RefundOf == original.ID {
+			refunds = append(refunds, expenditure)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Failed to get refunds", "id", id, "error", err)
+		return nil, err
+	}
+	return refunds, nil
+}
+
+// Refund records a partial or full refund of an expenditure. The refunds of an expenditure
+// together may not exceed its amount
+func (s *ExpenditureService) Refund(id string, input RefundInput) (*domain.Expenditure, error) {
+	original, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds, err := s.Refunds(id)
+	if err != nil {
+		return nil, err
+	}
+	var refunded float64
+	for _, refund := range refunds {
+		refunded -= refund.Amount
+	}
+
+	now := time.Now()
+	date := input.Date
+	if date.IsZero() {
+		date = now
+	}
+
+	refund, err := original.NewRefund(input.Amount, date, input.Description, refunded, now)
+	switch err {
+	case nil:
+	case domain.ErrRefundExceedsOriginal:
+		s.logger.Warn("Refund exceeds original expenditure", "id", id, "amount", input.Amount, "refunded", refunded, "original_amount", original.Amount)
+		return nil, rejected(err)
+	default:
+		s.logger.Warn("Invalid refund", "id", id, "error", err, "amount", input.Amount, "date", date)
+		return nil, invalid(err)
+	}
+
+	// The storage sums the refunds again as it adds this one, so concurrent refunds cannot
+	// together exceed the original, nor can one follow the original's deletion
+	err = s.expenditures.AddExpenditure(refund)
+	switch {
+	case err == nil:
+	case errors.Is(err, domain.ErrRefundExceedsOriginal):
+		s.logger.Warn("Refund exceeds original expenditure", "id", id, "amount", input.Amount)
+		return nil, rejected(err)
+	case errors.Is(err, domain.ErrExpenditureNotFound):
+		return nil, notFound(err)
+	default:
+		s.logger.Error("Failed to add refund", "id", refund.ID, "original_id", id, "error", err)
+		return nil, err
+	}
+
+	s.logger.Info("Recorded refund", "id", refund.ID, "original_id", id, "amount", input.Amount)
+	return refund, nil
+}
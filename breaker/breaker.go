@@ -0,0 +1,207 @@
+// Package breaker keeps the API up when the storage is not: a circuit breaker counts storage
+// failures and, after a number of them in a row, turns writes away at once while reads are
+// served from the last known copies, until a health probe or a trial call finds the storage back.
+package breaker
+
+import (
+	"context"
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/i18n"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// State is the state of a Breaker
+type State int
+
+const (
+	Closed   State = iota // The storage works and calls go through
+	Open                  // The storage is unavailable and calls are turned away
+	HalfOpen              // The storage was unavailable; calls go through on trial
+)
+
+func (s State) String() string {
+	switch s {
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// Breaker tracks the health of a storage. It is closed while the storage works and opens after
+// threshold failures in a row. An interval after the last failure it turns half-open and lets
+// calls through on trial: the first that reaches the storage closes it again, the first that
+// fails opens it for another interval. A successful probe closes it at any time. It is safe for
+// concurrent use
+type Breaker struct {
+	threshold int
+	interval  time.Duration
+	storage   domain.HealthChecker
+	logger    *slog.Logger
+	now       func() time.Time
+
+	mu       sync.Mutex
+	failures int       // Failures in a row
+	openedAt time.Time // Zero while closed
+	retryAt  time.Time // When calls are tried again while not closed
+}
+
+// New creates a closed Breaker for storage, which is probed every interval
+func New(storage domain.HealthChecker, threshold int, interval time.Duration, logger *slog.Logger) *Breaker {
+	return &Breaker{
+		threshold: threshold,
+		interval:  interval,
+		storage:   storage,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// State returns the state of the breaker
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state()
+}
+
+func (b *Breaker) state() State {
+	switch {
+	case b.openedAt.IsZero():
+		return Closed
+	case b.now().Before(b.retryAt):
+		return Open
+	default:
+		return HalfOpen
+	}
+}
+
+// Open reports whether the breaker is open, the storage being considered unavailable and calls
+// being turned away
+func (b *Breaker) Open() bool {
+	return b.State() == Open
+}
+
+// Record counts the outcome of a storage call and reports whether err is a storage failure
+func (b *Breaker) Record(err error) bool {
+	if err == nil || !b.storage.IsUnavailable(err) {
+		// The storage answered
+		b.close()
+		return false
+	}
+	b.fail(err)
+	return true
+}
+
+func (b *Breaker) fail(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	switch {
+	case !b.openedAt.IsZero():
+		// A failed trial or probe opens the breaker for another interval
+		b.retryAt = b.now().Add(b.interval)
+	case b.failures >= b.threshold:
+		b.openedAt = b.now()
+		b.retryAt = b.openedAt.Add(b.interval)
+		b.logger.Error("Storage unavailable, opening circuit breaker", "error", err, "failures", b.failures)
+	}
+}
+
+// Run probes the storage every interval until ctx is cancelled. Probes count like storage
+// calls, so an outage is noticed without traffic, and a successful probe closes an open breaker
+func (b *Breaker) Run(ctx context.Context) {
+	b.logger.Info("Starting storage health probe", "interval", b.interval.String(), "threshold", b.threshold)
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			b.logger.Info("Stopping storage health probe")
+			return
+		case <-ticker.C:
+		}
+		b.probe(ctx)
+	}
+}
+
+func (b *Breaker) probe(ctx context.Context) {
+	probeCtx, cancel := context.WithTimeout(ctx, b.interval)
+	err := b.storage.Ping(probeCtx)
+	cancel()
+
+	if err != nil {
+		// A failing probe counts whatever the error, the storage did not answer
+		b.fail(err)
+		return
+	}
+	b.close()
+}
+
+func (b *Breaker) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	if !b.openedAt.IsZero() {
+		b.logger.Info("Storage is back, closing circuit breaker", "outage", b.now().Sub(b.openedAt).Round(time.Second).String())
+		b.openedAt = time.Time{}
+		b.retryAt = time.Time{}
+	}
+}
+
+// RetryAfter returns the seconds until calls are tried again, for Retry-After headers
+func (b *Breaker) RetryAfter() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wait := b.interval
+	if !b.openedAt.IsZero() {
+		wait = b.retryAt.Sub(b.now())
+	}
+	// Round up, so clients don't come back before the breaker turns half-open
+	return max(int((wait+time.Second-1)/time.Second), 1)
+}
+
+// Error is the error of the responses turned away while the breaker is open
+type Error struct {
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	RetryAfter int    `json:"retry_after"` // Seconds until the storage is probed again
+}
+
+// Middleware answers requests that change data with 503 Service Unavailable while the breaker
+// is open, without waiting for the storage to fail them. Reads go through, to be served from
+// the last known copies where possible, and so do all requests while the breaker is half-open
+func (b *Breaker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !b.Open() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		retryAfter := b.RetryAfter()
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]Error{"error": {
+			Code:       "storage_unavailable",
+			Message:    i18n.T(r.Context(), domain.ErrStorageUnavailable.Error()),
+			RetryAfter: retryAfter,
+		}})
+	})
+}
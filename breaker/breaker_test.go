@@ -0,0 +1,211 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/services"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var errUnreachable = errors.New("connection refused")
+
+// flakyStorage is an expenditure repository that cannot be reached while down
+type flakyStorage struct {
+	*services.MemoryService
+	down bool
+}
+
+func (s *flakyStorage) Ping(context.Context) error {
+	if s.down {
+		return errUnreachable
+	}
+	return nil
+}
+
+func (s *flakyStorage) IsUnavailable(err error) bool { return errors.Is(err, errUnreachable) }
+
+func (s *flakyStorage) AddExpenditure(expenditure *domain.Expenditure) error {
+	if s.down {
+		return errUnreachable
+	}
+	return s.MemoryService.AddExpenditure(expenditure)
+}
+
+func (s *flakyStorage) GetExpenditureByID(id string) (*domain.Expenditure, error) {
+	if s.down {
+		return nil, errUnreachable
+	}
+	return s.MemoryService.GetExpenditureByID(id)
+}
+
+// clock is a fake time that only moves when advanced
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time           { return c.now }
+func (c *clock) advance(by time.Duration) { c.now = c.now.Add(by) }
+
+// newTestBreaker creates a breaker opening after three failures and probing every 5s, on a
+// fake clock
+func newTestBreaker(storage domain.HealthChecker) (*Breaker, *clock) {
+	c := &clock{now: time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)}
+	b := New(storage, 3, 5*time.Second, slog.New(slog.DiscardHandler))
+	b.now = c.Now
+	return b, c
+}
+
+func TestBreakerTransitions(t *testing.T) {
+	storage := &flakyStorage{}
+	b, clock := newTestBreaker(storage)
+
+	record := func(err error) func() { return func() { b.Record(err) } }
+	probe := func(down bool) func() {
+		return func() {
+			storage.down = down
+			b.probe(context.Background())
+		}
+	}
+
+	// The steps run in order against the same breaker
+	steps := []struct {
+		name           string
+		advance        time.Duration
+		do             func()
+		want           State
+		wantRetryAfter int
+	}{
+		{"new", 0, nil, Closed, 5},
+		{"first failure", 0, record(errUnreachable), Closed, 5},
+		{"second failure", 0, record(errUnreachable), Closed, 5},
+		{"answered", 0, record(domain.ErrExpenditureNotFound), Closed, 5},
+		{"failures start over", 0, record(errUnreachable), Closed, 5},
+		{"second failure again", 0, record(errUnreachable), Closed, 5},
+		{"third failure in a row", 0, record(errUnreachable), Open, 5},
+		{"waiting", 2500 * time.Millisecond, nil, Open, 3},
+		{"almost", 2499 * time.Millisecond, nil, Open, 1},
+		{"an interval after the failure", time.Millisecond, nil, HalfOpen, 1},
+		{"failed trial", 0, record(errUnreachable), Open, 5},
+		{"an interval after the failed trial", 5 * time.Second, nil, HalfOpen, 1},
+		{"successful trial", 0, record(nil), Closed, 5},
+		{"failing probe", 0, probe(true), Closed, 5},
+		{"second failing probe", time.Second, probe(true), Closed, 5},
+		{"third failing probe", time.Second, probe(true), Open, 5},
+		{"failing probe while open", 4 * time.Second, probe(true), Open, 5},
+		{"successful probe while open", time.Second, probe(false), Closed, 5},
+	}
+
+	for _, step := range steps {
+		clock.advance(step.advance)
+		if step.do != nil {
+			step.do()
+		}
+		if got := b.State(); got != step.want {
+			t.Fatalf("%s: state = %s, want %s", step.name, got, step.want)
+		}
+		if got := b.Open(); got != (step.want == Open) {
+			t.Errorf("%s: Open() = %v in state %s", step.name, got, step.want)
+		}
+		if got := b.RetryAfter(); got != step.wantRetryAfter {
+			t.Errorf("%s: retry after %ds, want %ds", step.name, got, step.wantRetryAfter)
+		}
+	}
+}
+
+func TestBreakerMiddleware(t *testing.T) {
+	storage := &flakyStorage{}
+	b, clock := newTestBreaker(storage)
+	for range 3 {
+		b.Record(errUnreachable)
+	}
+	clock.advance(time.Second)
+
+	handler := b.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	serve := func(method string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, "/expenditures", nil))
+		return rec
+	}
+
+	tests := []struct {
+		name           string
+		advance        time.Duration
+		method         string
+		wantStatus     int
+		wantRetryAfter string
+	}{
+		{"read while open", 0, http.MethodGet, http.StatusNoContent, ""},
+		{"write while open", 0, http.MethodPost, http.StatusServiceUnavailable, "4"},
+		{"delete while open", 0, http.MethodDelete, http.StatusServiceUnavailable, "4"},
+		{"write while half-open", 4 * time.Second, http.MethodPost, http.StatusNoContent, ""},
+	}
+
+	for _, tt := range tests {
+		clock.advance(tt.advance)
+		rec := serve(tt.method)
+		if rec.Code != tt.wantStatus || rec.Header().Get("Retry-After") != tt.wantRetryAfter {
+			t.Errorf("%s: status = %d with Retry-After %q, want %d with %q", tt.name, rec.Code, rec.Header().Get("Retry-After"), tt.wantStatus, tt.wantRetryAfter)
+		}
+	}
+}
+
+func TestRepository(t *testing.T) {
+	storage := &flakyStorage{MemoryService: services.NewMemoryService(slog.New(slog.DiscardHandler))}
+	b, clock := newTestBreaker(storage)
+	repo := NewRepository(storage, b)
+
+	newExpenditure := func() *domain.Expenditure {
+		expenditure, err := domain.NewExpenditure("Lunch", 12.5, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), uuid.New())
+		if err != nil {
+			t.Fatalf("creating expenditure: %v", err)
+		}
+		return expenditure
+	}
+	known := newExpenditure()
+	if err := repo.AddExpenditure(known); err != nil {
+		t.Fatalf("adding expenditure: %v", err)
+	}
+	unknown := newExpenditure()
+	if err := storage.MemoryService.AddExpenditure(unknown); err != nil {
+		t.Fatalf("adding expenditure: %v", err)
+	}
+
+	// The steps run in order against the same repository
+	steps := []struct {
+		name    string
+		advance time.Duration
+		down    bool
+		call    func() error
+		wantErr error
+		want    State
+	}{
+		{"read reaching the storage", 0, false, func() error { _, err := repo.GetExpenditureByID(unknown.ID.String()); return err }, nil, Closed},
+		{"write failing", 0, true, func() error { return repo.AddExpenditure(newExpenditure()) }, domain.ErrStorageUnavailable, Closed},
+		{"read from the copies", 0, true, func() error { _, err := repo.GetExpenditureByID(known.ID.String()); return err }, nil, Closed},
+		{"third failure opens", 0, true, func() error { _, err := repo.GetExpenditureByID(uuid.NewString()); return err }, domain.ErrStorageUnavailable, Open},
+		{"write turned away", 0, false, func() error { return repo.AddExpenditure(newExpenditure()) }, domain.ErrStorageUnavailable, Open},
+		{"read from the copies while open", 0, false, func() error { _, err := repo.GetExpenditureByID(unknown.ID.String()); return err }, nil, Open},
+		{"failed trial", 5 * time.Second, true, func() error { return repo.AddExpenditure(newExpenditure()) }, domain.ErrStorageUnavailable, Open},
+		{"successful trial", 5 * time.Second, false, func() error { return repo.AddExpenditure(newExpenditure()) }, nil, Closed},
+	}
+
+	for _, step := range steps {
+		clock.advance(step.advance)
+		storage.down = step.down
+		if err := step.call(); !errors.Is(err, step.wantErr) {
+			t.Fatalf("%s: got %v, want %v", step.name, err, step.wantErr)
+		}
+		if got := b.State(); got != step.want {
+			t.Fatalf("%s: state = %s, want %s", step.name, got, step.want)
+		}
+	}
+}
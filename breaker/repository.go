@@ -0,0 +1,265 @@
+package breaker
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"sync"
+	"time"
+)
+
+// Repository guards an expenditure repository with a Breaker. While the breaker is closed calls
+// go through and the expenditures read or written are kept as the last known copies; while it
+// is open, or when a read fails to reach the storage, reads are served from those copies and
+// writes fail with domain.ErrStorageUnavailable
+type Repository struct {
+	repo    domain.ExpenditureRepository
+	breaker *Breaker
+
+	mu       sync.RWMutex
+	byID     map[string]*domain.Expenditure
+	complete bool // Whether byID holds every expenditure, as of the last full listing
+}
+
+// NewRepository guards repo with breaker
+func NewRepository(repo domain.ExpenditureRepository, breaker *Breaker) *Repository {
+	return &Repository{
+		repo:    repo,
+		breaker: breaker,
+		byID:    make(map[string]*domain.Expenditure),
+	}
+}
+
+// unavailable records the outcome of a storage call and reports whether the storage could not
+// be reached, logging the failure
+func (r *Repository) unavailable(err error, op string) bool {
+	if !r.breaker.Record(err) {
+		return false
+	}
+	r.breaker.logger.Warn("Storage unavailable", "operation", op, "error", err)
+	return true
+}
+
+func (r *Repository) AddExpenditure(expenditure *domain.Expenditure) error {
+	if r.breaker.Open() {
+		return domain.ErrStorageUnavailable
+	}
+	err := r.repo.AddExpenditure(expenditure)
+	if r.unavailable(err, "add") {
+		return domain.ErrStorageUnavailable
+	}
+	if err == nil {
+		r.remember(expenditure)
+	}
+	return err
+}
+
+func (r *Repository) AddExpenditures(expenditures []*domain.Expenditure) error {
+	if r.breaker.Open() {
+		return domain.ErrStorageUnavailable
+	}
+	err := domain.AddExpenditures(r.repo, expenditures)
+	if r.unavailable(err, "add many") {
+		return domain.ErrStorageUnavailable
+	}
+	if err == nil {
+		r.remember(expenditures...)
+	}
+	return err
+}
+
+func (r *Repository) UpdateExpenditure(expenditure *domain.Expenditure) error {
+	if r.breaker.Open() {
+		return domain.ErrStorageUnavailable
+	}
+	err := r.repo.UpdateExpenditure(expenditure)
+	if r.unavailable(err, "update") {
+		return domain.ErrStorageUnavailable
+	}
+	if err == nil {
+		r.remember(expenditure)
+	}
+	return err
+}
+
+func (r *Repository) DeleteExpenditure(id string) error {
+	if r.breaker.Open() {
+		return domain.ErrStorageUnavailable
+	}
+	err := r.repo.DeleteExpenditure(id)
+	if r.unavailable(err, "delete") {
+		return domain.ErrStorageUnavailable
+	}
+	if err == nil || errors.Is(err, domain.ErrExpenditureNotFound) {
+		r.forget(id)
+	}
+	return err
+}
+
+func (r *Repository) GetExpenditureByID(id string) (*domain.Expenditure, error) {
+	if r.breaker.Open() {
+		return r.cached(id)
+	}
+	expenditure, err := r.repo.GetExpenditureByID(id)
+	if r.unavailable(err, "get") {
+		return r.cached(id)
+	}
+	switch {
+	case err == nil:
+		r.remember(expenditure)
+	case errors.Is(err, domain.ErrExpenditureNotFound):
+		r.forget(id)
+	}
+	return expenditure, err
+}
+
+func (r *Repository) GetAllExpenditures() ([]*domain.Expenditure, error) {
+	if r.breaker.Open() {
+		return r.cachedAll()
+	}
+	expenditures, err := r.repo.GetAllExpenditures()
+	if r.unavailable(err, "list") {
+		return r.cachedAll()
+	}
+	if err == nil {
+		r.replace(expenditures)
+	}
+	return expenditures, err
+}
+
+func (r *Repository) StreamExpenditures(fn func(*domain.Expenditure) error) error {
+	if r.breaker.Open() {
+		return r.cachedEach(fn)
+	}
+	// A stream that fails to reach the storage halfway cannot be taken back from fn, so only
+	// one that fails before handing out anything falls back to the copies
+	streamed := false
+	err := domain.EachExpenditure(r.repo, func(expenditure *domain.Expenditure) error {
+		streamed = true
+		return fn(expenditure)
+	})
+	if r.unavailable(err, "stream") {
+		if !streamed {
+			return r.cachedEach(fn)
+		}
+		return domain.ErrStorageUnavailable
+	}
+	return err
+}
+
+func (r *Repository) GetExpenditurePage(query domain.ExpenditurePageQuery) ([]*domain.Expenditure, error) {
+	if r.breaker.Open() {
+		return r.cachedPage(query)
+	}
+	expenditures, err := domain.GetExpenditurePage(r.repo, query)
+	if r.unavailable(err, "page") {
+		return r.cachedPage(query)
+	}
+	if err == nil {
+		r.remember(expenditures...)
+	}
+	return expenditures, err
+}
+
+func (r *Repository) CountExpenditures(from, to time.Time) (int, error) {
+	if r.breaker.Open() {
+		return r.cachedCount(from, to)
+	}
+	count, err := domain.CountExpenditures(r.repo, from, to)
+	if r.unavailable(err, "count") {
+		return r.cachedCount(from, to)
+	}
+	return count, err
+}
+
+func (r *Repository) remember(expenditures ...*domain.Expenditure) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, expenditure := range expenditures {
+		r.byID[expenditure.ID.String()] = expenditure.Clone()
+	}
+}
+
+func (r *Repository) forget(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.byID, id)
+}
+
+func (r *Repository) replace(expenditures []*domain.Expenditure) {
+	byID := make(map[string]*domain.Expenditure, len(expenditures))
+	for _, expenditure := range expenditures {
+		byID[expenditure.ID.String()] = expenditure.Clone()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byID = byID
+	r.complete = true
+}
+
+// cached returns the last known copy of an expenditure. One that is not known is only reported
+// missing when every expenditure is known
+func (r *Repository) cached(id string) (*domain.Expenditure, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if expenditure, ok := r.byID[id]; ok {
+		return expenditure.Clone(), nil
+	}
+	if r.complete {
+		return nil, domain.ErrExpenditureNotFound
+	}
+	return nil, domain.ErrStorageUnavailable
+}
+
+// cachedAll returns the last known copies of all expenditures, unless some may be missing
+func (r *Repository) cachedAll() ([]*domain.Expenditure, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.complete {
+		return nil, domain.ErrStorageUnavailable
+	}
+	expenditures := make([]*domain.Expenditure, 0, len(r.byID))
+	for _, expenditure := range r.byID {
+		expenditures = append(expenditures, expenditure.Clone())
+	}
+	domain.SortExpenditures(expenditures)
+	return expenditures, nil
+}
+
+func (r *Repository) cachedEach(fn func(*domain.Expenditure) error) error {
+	expenditures, err := r.cachedAll()
+	if err != nil {
+		return err
+	}
+	for _, expenditure := range expenditures {
+		if err := fn(expenditure); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Repository) cachedPage(query domain.ExpenditurePageQuery) ([]*domain.Expenditure, error) {
+	return domain.GetExpenditurePage(cache{r}, query)
+}
+
+func (r *Repository) cachedCount(from, to time.Time) (int, error) {
+	return domain.CountExpenditures(cache{r}, from, to)
+}
+
+// cache reads the last known copies through the domain helpers, which page and count listings
+type cache struct {
+	r *Repository
+}
+
+func (c cache) AddExpenditure(*domain.Expenditure) error { return domain.ErrStorageUnavailable }
+func (c cache) UpdateExpenditure(*domain.Expenditure) error {
+	return domain.ErrStorageUnavailable
+}
+func (c cache) DeleteExpenditure(string) error { return domain.ErrStorageUnavailable }
+func (c cache) GetExpenditureByID(id string) (*domain.Expenditure, error) {
+	return c.r.cached(id)
+}
+func (c cache) GetAllExpenditures() ([]*domain.Expenditure, error) { return c.r.cachedAll() }
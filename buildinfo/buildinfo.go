@@ -0,0 +1,71 @@
+// Package buildinfo describes the build of the running binary, so logs and bug reports can
+// name the exact build. Release builds set the version, commit and date with -ldflags:
+//
+//	go build -ldflags "-X go-expense-tracker/buildinfo.Version=1.4.0 \
+//	  -X go-expense-tracker/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X go-expense-tracker/buildinfo.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Without them the commit comes from the version control information the go command stamps
+// into binaries built from a checkout, and the date is that of the commit.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// Set with -ldflags "-X go-expense-tracker/buildinfo.Version=..." and so on
+var (
+	Version = "dev" // Semantic version of the release, e.g. 1.4.0
+	Commit  = ""    // Git commit the binary was built from
+	Date    = ""    // When the binary was built, in RFC 3339
+)
+
+// Info describes a build
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Date      string `json:"build_date,omitempty"`
+	Modified  bool   `json:"modified,omitempty"` // Built from a checkout with uncommitted changes
+	GoVersion string `json:"go_version"`
+}
+
+var info = sync.OnceValue(func() Info {
+	info := Info{
+		Version:   strings.TrimPrefix(Version, "v"),
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+	}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	// A binary installed with go install knows its module version
+	if info.Version == "dev" && build.Main.Version != "" && build.Main.Version != "(devel)" {
+		info.Version = strings.TrimPrefix(build.Main.Version, "v")
+	}
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.Date == "" {
+				info.Date = setting.Value
+			}
+		case "vcs.modified":
+			info.Modified = Commit == "" && setting.Value == "true"
+		}
+	}
+	return info
+})
+
+// Get returns the build of the running binary
+func Get() Info {
+	return info()
+}
@@ -0,0 +1,263 @@
+// Package chaos injects faults into the API for resilience testing: configured routes answer
+// late, fail with 503 Service Unavailable or lose their connection for a share of requests, so
+// client retries, circuit breakers and offline sync can be tried without a real outage. It is
+// meant for development only.
+package chaos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-expense-tracker/breaker"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/i18n"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Path is where the rules are managed at runtime; faults are never injected into it
+const Path = "/admin/chaos"
+
+// RetryAfter is the seconds clients are told to wait by injected 503 responses
+const RetryAfter = 1
+
+// Rule describes the faults injected into the requests to some routes. Each percentage is the
+// share of those requests the fault is injected into, drawn independently of the others
+type Rule struct {
+	Method              string        // Any method when empty
+	Path                string        // Prefix of the paths the rule applies to
+	Latency             time.Duration // Delay before the request is handled
+	LatencyPercent      float64       // Share of the requests delayed
+	ErrorPercent        float64       // Share answered with 503 without being handled
+	DropPercent         float64       // Share whose connection is closed without being handled
+	DropResponsePercent float64       // Share handled, whose connection is then closed without a response
+}
+
+// ParseRules parses rules separated by semicolons. Each is an optional method and a path
+// prefix followed by faults, e.g. "POST /expenditures latency=2s@50 error=10 drop=5
+// drop_response=5": a latency applies to every request unless followed by @ and a
+// percentage, the other faults take a percentage. An empty string holds no rules
+func ParseRules(s string) ([]Rule, error) {
+	var rules []Rule
+	for _, text := range strings.Split(s, ";") {
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		rule, err := ParseRule(text)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// ParseRule parses a single rule in the syntax of ParseRules
+func ParseRule(text string) (Rule, error) {
+	var rule Rule
+	fields := strings.Fields(text)
+	if len(fields) > 0 && !strings.HasPrefix(fields[0], "/") && !strings.Contains(fields[0], "=") {
+		rule.Method = strings.ToUpper(fields[0])
+		fields = fields[1:]
+	}
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return Rule{}, fmt.Errorf("rule %q: expected a path starting with /", strings.TrimSpace(text))
+	}
+	rule.Path = fields[0]
+
+	for _, field := range fields[1:] {
+		name, value, found := strings.Cut(field, "=")
+		if !found {
+			return Rule{}, fmt.Errorf("rule %q: expected fault=value, got %q", strings.TrimSpace(text), field)
+		}
+		var err error
+		switch name {
+		case "latency":
+			latency, percent, sampled := strings.Cut(value, "@")
+			rule.Latency, err = time.ParseDuration(latency)
+			if err == nil && rule.Latency <= 0 {
+				err = errors.New("latency must be positive")
+			}
+			rule.LatencyPercent = 100
+			if err == nil && sampled {
+				rule.LatencyPercent, err = parsePercent(percent)
+			}
+		case "error":
+			rule.ErrorPercent, err = parsePercent(value)
+		case "drop":
+			rule.DropPercent, err = parsePercent(value)
+		case "drop_response":
+			rule.DropResponsePercent, err = parsePercent(value)
+		default:
+			err = errors.New("unknown fault, expected latency, error, drop or drop_response")
+		}
+		if err != nil {
+			return Rule{}, fmt.Errorf("rule %q: %s: %w", strings.TrimSpace(text), name, err)
+		}
+	}
+	return rule, nil
+}
+
+func parsePercent(value string) (float64, error) {
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil {
+		return 0, err
+	}
+	if percent < 0 || percent > 100 {
+		return 0, fmt.Errorf("percentage %v out of range 0-100", percent)
+	}
+	return percent, nil
+}
+
+// String renders the rule in the syntax of ParseRules
+func (r Rule) String() string {
+	parts := []string{r.Path}
+	if r.Method != "" {
+		parts = []string{r.Method, r.Path}
+	}
+	if r.Latency > 0 {
+		latency := "latency=" + r.Latency.String()
+		if r.LatencyPercent != 100 {
+			latency += "@" + formatPercent(r.LatencyPercent)
+		}
+		parts = append(parts, latency)
+	}
+	if r.ErrorPercent > 0 {
+		parts = append(parts, "error="+formatPercent(r.ErrorPercent))
+	}
+	if r.DropPercent > 0 {
+		parts = append(parts, "drop="+formatPercent(r.DropPercent))
+	}
+	if r.DropResponsePercent > 0 {
+		parts = append(parts, "drop_response="+formatPercent(r.DropResponsePercent))
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatPercent(percent float64) string {
+	return strconv.FormatFloat(percent, 'f', -1, 64)
+}
+
+func (r Rule) matches(req *http.Request) bool {
+	return (r.Method == "" || r.Method == req.Method) && strings.HasPrefix(req.URL.Path, r.Path)
+}
+
+// Injector injects the faults of its rules into the requests passing through its middleware.
+// It is safe for concurrent use
+type Injector struct {
+	logger *slog.Logger
+	roll   func() float64 // Returns a percentage in [0, 100)
+
+	mu    sync.RWMutex
+	rules []Rule
+}
+
+// New creates an Injector with rules
+func New(rules []Rule, logger *slog.Logger) *Injector {
+	return &Injector{
+		logger: logger,
+		roll:   func() float64 { return rand.Float64() * 100 },
+		rules:  rules,
+	}
+}
+
+// Rules returns the rules in effect
+func (i *Injector) Rules() []Rule {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return append([]Rule{}, i.rules...)
+}
+
+// SetRules replaces the rules; no rules lets every request through untouched
+func (i *Injector) SetRules(rules []Rule) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.rules = append([]Rule{}, rules...)
+}
+
+// rule returns the first rule matching req
+func (i *Injector) rule(req *http.Request) (Rule, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	for _, rule := range i.rules {
+		if rule.matches(req) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+func (i *Injector) hit(percent float64) bool {
+	return percent > 0 && i.roll() < percent
+}
+
+// Middleware injects the faults of the first rule matching each request: the latency first,
+// then a dropped connection or a 503 response, or a response lost after handling. Injected 503
+// responses look like those of an open storage circuit breaker, so clients treat them alike
+func (i *Injector) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == Path {
+			next.ServeHTTP(w, r)
+			return
+		}
+		rule, found := i.rule(r)
+		if !found {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if rule.Latency > 0 && i.hit(rule.LatencyPercent) {
+			i.logger.Info("Injecting latency", "method", r.Method, "path", r.URL.Path, "latency", rule.Latency.String())
+			timer := time.NewTimer(rule.Latency)
+			select {
+			case <-timer.C:
+			case <-r.Context().Done():
+				timer.Stop()
+				return
+			}
+		}
+
+		if i.hit(rule.DropPercent) {
+			i.logger.Info("Injecting dropped connection", "method", r.Method, "path", r.URL.Path)
+			// Aborting the handler closes the connection without a response
+			panic(http.ErrAbortHandler)
+		}
+
+		if i.hit(rule.ErrorPercent) {
+			i.logger.Info("Injecting unavailable response", "method", r.Method, "path", r.URL.Path)
+			w.Header().Set("Retry-After", strconv.Itoa(RetryAfter))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(map[string]breaker.Error{"error": {
+				Code:       "storage_unavailable",
+				Message:    i18n.T(r.Context(), domain.ErrStorageUnavailable.Error()),
+				RetryAfter: RetryAfter,
+			}})
+			return
+		}
+
+		if i.hit(rule.DropResponsePercent) {
+			// The request takes effect, but the client never learns of it
+			next.ServeHTTP(discardWriter{header: http.Header{}}, r)
+			i.logger.Info("Injecting lost response", "method", r.Method, "path", r.URL.Path)
+			panic(http.ErrAbortHandler)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// discardWriter takes a response that is never sent
+type discardWriter struct {
+	header http.Header
+}
+
+func (d discardWriter) Header() http.Header         { return d.header }
+func (d discardWriter) Write(b []byte) (int, error) { return io.Discard.Write(b) }
+func (d discardWriter) WriteHeader(int)             {}
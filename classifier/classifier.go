@@ -0,0 +1,234 @@
+// Package classifier suggests categories for expenditures from their descriptions. A naive Bayes
+// model is trained in-process on the categorized history, retrained on a schedule and corrected
+// as expenditures are recategorized in between.
+package classifier
+
+import (
+	"context"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Suggestion is a category the classifier proposes for a description
+type Suggestion struct {
+	CategoryId  uuid.UUID `json:"category_id"`
+	Probability float64   `json:"probability"` // Of the category among all known ones, 0 to 1
+}
+
+// Classifier suggests categories with a model trained on the expenditures of the repository. It
+// is safe for concurrent use
+type Classifier struct {
+	expenditures  domain.ExpenditureRepository
+	uncategorized uuid.UUID // Never learned from or suggested
+	interval      time.Duration
+	logger        *slog.Logger
+	mu            sync.RWMutex
+	model         *model
+	trainedAt     time.Time
+}
+
+// NewClassifier creates a new Classifier retraining every interval; expenditures in the
+// uncategorized category, if any, teach it nothing
+func NewClassifier(expenditures domain.ExpenditureRepository, uncategorized uuid.UUID, interval time.Duration, logger *slog.Logger) *Classifier {
+	return &Classifier{
+		expenditures:  expenditures,
+		uncategorized: uncategorized,
+		interval:      interval,
+		logger:        logger,
+		model:         newModel(),
+	}
+}
+
+// Run trains on startup and then every interval until the context is cancelled
+func (c *Classifier) Run(ctx context.Context) {
+	c.logger.Info("Starting classifier training scheduler", "interval", c.interval.String())
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Train(); err != nil {
+			c.logger.Error("Failed to train classifier", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			c.logger.Info("Stopping classifier training scheduler")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Train replaces the model with one trained on every categorized expenditure
+func (c *Classifier) Train() error {
+	trained := newModel()
+	err := domain.EachExpenditure(c.expenditures, func(expenditure *domain.Expenditure) error {
+		if c.teaches(expenditure) {
+			trained.add(expenditure.Description, expenditure.CategoryId, 1)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.model = trained
+	c.trainedAt = time.Now()
+	c.mu.Unlock()
+
+	c.logger.Info("Trained classifier", "examples", trained.examples, "categories", len(trained.docs), "words", len(trained.vocabulary))
+	return nil
+}
+
+// TrainedAt returns when the model was last trained, the zero time before the first training
+func (c *Classifier) TrainedAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.trainedAt
+}
+
+// Learn adds an expenditure to the model until the next training
+func (c *Classifier) Learn(expenditure *domain.Expenditure) {
+	if !c.teaches(expenditure) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.model.add(expenditure.Description, expenditure.CategoryId, 1)
+}
+
+// Forget removes an expenditure learned before, e.g. the state before a correction
+func (c *Classifier) Forget(expenditure *domain.Expenditure) {
+	if !c.teaches(expenditure) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.model.add(expenditure.Description, expenditure.CategoryId, -1)
+}
+
+// Suggest returns up to limit categories for the description, most likely first. Nothing is
+// suggested before the model knows two categories or when the description has no usable words
+func (c *Classifier) Suggest(description string, limit int) []Suggestion {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.model.suggest(description, limit)
+}
+
+// teaches reports whether the expenditure is an example of its category
+func (c *Classifier) teaches(expenditure *domain.Expenditure) bool {
+	return expenditure.CategoryId != uuid.Nil && expenditure.CategoryId != c.uncategorized && expenditure.RefundOf == uuid.Nil
+}
+
+// model is a multinomial naive Bayes model over the words of descriptions
+type model struct {
+	examples   int
+	docs       map[uuid.UUID]int            // Examples per category
+	words      map[uuid.UUID]map[string]int // Occurrences of each word per category
+	totals     map[uuid.UUID]int            // Words per category
+	vocabulary map[string]int               // Occurrences of each word in all categories
+}
+
+func newModel() *model {
+	return &model{
+		docs:       make(map[uuid.UUID]int),
+		words:      make(map[uuid.UUID]map[string]int),
+		totals:     make(map[uuid.UUID]int),
+		vocabulary: make(map[string]int),
+	}
+}
+
+// add counts the description as an example of the category n times; a negative n removes it
+func (m *model) add(description string, category uuid.UUID, n int) {
+	words := tokens(description)
+	if len(words) == 0 {
+		return
+	}
+
+	m.examples += n
+	m.docs[category] += n
+	if m.words[category] == nil {
+		m.words[category] = make(map[string]int)
+	}
+	for _, word := range words {
+		m.words[category][word] += n
+		m.totals[category] += n
+		m.vocabulary[word] += n
+		if m.vocabulary[word] <= 0 {
+			delete(m.vocabulary, word)
+		}
+		if m.words[category][word] <= 0 {
+			delete(m.words[category], word)
+		}
+	}
+	if m.docs[category] <= 0 {
+		delete(m.docs, category)
+		delete(m.words, category)
+		delete(m.totals, category)
+	}
+}
+
+func (m *model) suggest(description string, limit int) []Suggestion {
+	suggestions := []Suggestion{}
+	words := tokens(description)
+	if len(words) == 0 || len(m.docs) < 2 {
+		return suggestions
+	}
+
+	// Log probabilities with add-one smoothing, so unseen words do not rule a category out
+	scores := make(map[uuid.UUID]float64, len(m.docs))
+	best := math.Inf(-1)
+	for category, docs := range m.docs {
+		score := math.Log(float64(docs) / float64(m.examples))
+		denominator := float64(m.totals[category] + len(m.vocabulary))
+		for _, word := range words {
+			score += math.Log(float64(m.words[category][word]+1) / denominator)
+		}
+		scores[category] = score
+		best = max(best, score)
+	}
+
+	sum := 0.0
+	for category, score := range scores {
+		scores[category] = math.Exp(score - best)
+		sum += scores[category]
+	}
+	for category, score := range scores {
+		suggestions = append(suggestions, Suggestion{CategoryId: category, Probability: math.Round(score/sum*1000) / 1000})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Probability != suggestions[j].Probability {
+			return suggestions[i].Probability > suggestions[j].Probability
+		}
+		return suggestions[i].CategoryId.String() < suggestions[j].CategoryId.String()
+	})
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// tokens splits a description into lower-cased words of letters, leaving out single letters and
+// numbers such as card or order numbers
+func tokens(description string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		if len([]rune(word)) > 1 {
+			words = append(words, word)
+		}
+	}
+	return words
+}
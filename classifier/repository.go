@@ -0,0 +1,73 @@
+package classifier
+
+import (
+	"go-expense-tracker/domain"
+)
+
+// LearningRepository wraps an ExpenditureRepository and teaches the classifier every change, so
+// a corrected category is suggested before the next training
+type LearningRepository struct {
+	domain.ExpenditureWrapper
+	classifier *Classifier
+}
+
+// NewLearningRepository creates a new LearningRepository around the given repository
+func NewLearningRepository(inner domain.ExpenditureRepository, classifier *Classifier) *LearningRepository {
+	return &LearningRepository{
+		ExpenditureWrapper: domain.ExpenditureWrapper{ExpenditureRepository: inner},
+		classifier:         classifier,
+	}
+}
+
+// AddExpenditure adds the expenditure and learns its category
+func (r *LearningRepository) AddExpenditure(expenditure *domain.Expenditure) error {
+	if err := r.ExpenditureRepository.AddExpenditure(expenditure); err != nil {
+		return err
+	}
+	r.classifier.Learn(expenditure)
+	return nil
+}
+
+// AddExpenditures adds the expenditures in bulk and learns their categories
+func (r *LearningRepository) AddExpenditures(expenditures []*domain.Expenditure) error {
+	if err := domain.AddExpenditures(r.ExpenditureRepository, expenditures); err != nil {
+		return err
+	}
+	for _, expenditure := range expenditures {
+		r.classifier.Learn(expenditure)
+	}
+	return nil
+}
+
+// UpdateExpenditure updates the expenditure and, when its category or description changed,
+// replaces what was learned from it
+func (r *LearningRepository) UpdateExpenditure(expenditure *domain.Expenditure) error {
+	previous, err := r.ExpenditureRepository.GetExpenditureByID(expenditure.ID.String())
+	if err != nil {
+		return err
+	}
+
+	if err := r.ExpenditureRepository.UpdateExpenditure(expenditure); err != nil {
+		return err
+	}
+
+	if previous.CategoryId != expenditure.CategoryId || previous.Description != expenditure.Description {
+		r.classifier.Forget(previous)
+		r.classifier.Learn(expenditure)
+	}
+	return nil
+}
+
+// DeleteExpenditure deletes the expenditure and forgets it
+func (r *LearningRepository) DeleteExpenditure(id string) error {
+	expenditure, err := r.ExpenditureRepository.GetExpenditureByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.ExpenditureRepository.DeleteExpenditure(id); err != nil {
+		return err
+	}
+	r.classifier.Forget(expenditure)
+	return nil
+}
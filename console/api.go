@@ -0,0 +1,267 @@
+package console
+
+import (
+	"encoding/json"
+	"errors"
+	"go-expense-tracker/app"
+	"go-expense-tracker/buildinfo"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/features"
+	"go-expense-tracker/leader"
+	"go-expense-tracker/maintenance"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxFailedMessages caps the outbox messages given up on that the console lists and counts
+const maxFailedMessages = 500
+
+// MeResponse is the operator signed in, with what their role allows
+type MeResponse struct {
+	Name        string       `json:"name"`
+	Role        Role         `json:"role"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// OverviewResponse is the state of the deployment at a glance
+type OverviewResponse struct {
+	Build          buildinfo.Info    `json:"build"`
+	Maintenance    maintenance.State `json:"maintenance"`
+	Leader         leader.Status     `json:"leader"`
+	RunningJobs    int               `json:"running_jobs"`
+	FailedMessages *int              `json:"failed_outbox_messages,omitempty"` // Missing without an outbox
+	Sections       []string          `json:"sections"`                         // Sections the storage supports
+}
+
+// HouseholdResponse is a household with its invitations
+type HouseholdResponse struct {
+	*domain.Household
+	Invitations []InvitationResponse `json:"invitations"`
+}
+
+// InvitationResponse is an invitation with its status
+type InvitationResponse struct {
+	*domain.Invitation
+	Status string `json:"status"`
+}
+
+// JobsResponse lists the background work of this replica
+type JobsResponse struct {
+	Leader leader.Status `json:"leader"`
+	Jobs   []*domain.Job `json:"jobs"`
+}
+
+func (c *Console) getMe(w http.ResponseWriter, r *http.Request, operator *Operator, _ []string) {
+	writeJSON(w, http.StatusOK, MeResponse{Name: operator.Name, Role: operator.Role, Permissions: operator.Role.Permissions()})
+}
+
+func (c *Console) getOverview(w http.ResponseWriter, r *http.Request, _ *Operator, _ []string) {
+	overview := OverviewResponse{
+		Build:       c.build,
+		Maintenance: c.maintenance.State(),
+		Leader:      c.elector.Status(),
+		Sections:    []string{"overview"},
+	}
+	for _, job := range c.jobs.Recent() {
+		if job.Status == domain.JobRunning {
+			overview.RunningJobs++
+		}
+	}
+	if c.households != nil {
+		overview.Sections = append(overview.Sections, "households")
+	}
+	overview.Sections = append(overview.Sections, "integrations", "flags", "jobs")
+	if c.outbox != nil {
+		messages, err := c.outbox.GetFailedOutboxMessages(maxFailedMessages)
+		if err != nil {
+			c.logger.Error("Failed to get failed outbox messages", "error", err)
+			writeError(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		failed := len(messages)
+		overview.FailedMessages = &failed
+		overview.Sections = append(overview.Sections, "outbox")
+	}
+	writeJSON(w, http.StatusOK, overview)
+}
+
+func (c *Console) getHouseholds(w http.ResponseWriter, r *http.Request, _ *Operator, _ []string) {
+	households, err := c.households.List()
+	if err != nil {
+		c.logger.Error("Failed to get households", "error", err)
+		writeError(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	now := time.Now()
+	response := make([]HouseholdResponse, 0, len(households))
+	for _, household := range households {
+		invitations, err := c.households.Invitations(household.ID.String())
+		if err != nil {
+			c.logger.Error("Failed to get invitations", "error", err, "household_id", household.ID)
+			writeError(w, err.Error(), serviceStatus(err))
+			return
+		}
+		entry := HouseholdResponse{Household: household, Invitations: make([]InvitationResponse, 0, len(invitations))}
+		for _, invitation := range invitations {
+			entry.Invitations = append(entry.Invitations, InvitationResponse{Invitation: invitation, Status: invitation.Status(now)})
+		}
+		response = append(response, entry)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// removeMember erases a member as they could erase themselves, keeping the expenditures they
+// recorded for their household without attributing them to anyone
+func (c *Console) removeMember(w http.ResponseWriter, r *http.Request, operator *Operator, path []string) {
+	id, err := uuid.Parse(path[1])
+	if err != nil {
+		writeError(w, "Invalid ID, expected a UUID", http.StatusBadRequest)
+		return
+	}
+
+	erasure, err := c.privacy.Erase(id, app.EraseAnonymize)
+	if err != nil {
+		c.logger.Warn("Failed to remove member", "error", err, "member_id", id, "operator", operator.Name)
+		writeError(w, err.Error(), serviceStatus(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, erasure)
+}
+
+func (c *Console) revokeInvitation(w http.ResponseWriter, r *http.Request, operator *Operator, path []string) {
+	invitation, err := c.households.Revoke(path[1], path[3])
+	if err != nil {
+		c.logger.Warn("Failed to revoke invitation", "error", err, "household_id", path[1], "id", path[3], "operator", operator.Name)
+		writeError(w, err.Error(), serviceStatus(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, InvitationResponse{Invitation: invitation, Status: invitation.Status(time.Now())})
+}
+
+func (c *Console) getIntegrations(w http.ResponseWriter, r *http.Request, _ *Operator, _ []string) {
+	integrations := c.integrations
+	if integrations == nil {
+		integrations = []Integration{}
+	}
+	writeJSON(w, http.StatusOK, integrations)
+}
+
+func (c *Console) getFlags(w http.ResponseWriter, r *http.Request, _ *Operator, _ []string) {
+	writeJSON(w, http.StatusOK, c.flags.States())
+}
+
+// toggleRequest is the body of a flag toggle, {"enabled": true}
+type toggleRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+func (c *Console) toggleFlag(w http.ResponseWriter, r *http.Request, _ *Operator, path []string) {
+	var req toggleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Enabled == nil {
+		writeError(w, "Invalid request body, expected {\"enabled\": true} or {\"enabled\": false}", http.StatusBadRequest)
+		return
+	}
+
+	name := path[1]
+	if err := c.flags.Toggle(name, *req.Enabled); err != nil {
+		switch {
+		case errors.Is(err, features.ErrUnknownFlag):
+			writeError(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, features.ErrStartupOnlyFlag):
+			writeError(w, err.Error(), http.StatusConflict)
+		default:
+			writeError(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, c.flags.States())
+}
+
+// maintenanceRequest is the body of a change of the maintenance mode
+type maintenanceRequest struct {
+	ReadOnly *bool  `json:"read_only"`
+	Reason   string `json:"reason"`
+}
+
+func (c *Console) setMaintenance(w http.ResponseWriter, r *http.Request, operator *Operator, _ []string) {
+	var req maintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ReadOnly == nil {
+		writeError(w, "Invalid request body, expected {\"read_only\": true, \"reason\": \"...\"}", http.StatusBadRequest)
+		return
+	}
+
+	if *req.ReadOnly {
+		reason := req.Reason
+		if reason == "" {
+			reason = "Entered from the admin console by " + operator.Name
+		}
+		c.maintenance.Enable(reason, 0)
+	} else {
+		c.maintenance.Disable()
+	}
+	writeJSON(w, http.StatusOK, c.maintenance.State())
+}
+
+func (c *Console) getJobs(w http.ResponseWriter, r *http.Request, _ *Operator, _ []string) {
+	jobs := c.jobs.Recent()
+	if jobs == nil {
+		jobs = []*domain.Job{}
+	}
+	writeJSON(w, http.StatusOK, JobsResponse{Leader: c.elector.Status(), Jobs: jobs})
+}
+
+func (c *Console) getFailedOutboxMessages(w http.ResponseWriter, r *http.Request, _ *Operator, _ []string) {
+	messages, err := c.outbox.GetFailedOutboxMessages(maxFailedMessages)
+	if err != nil {
+		c.logger.Error("Failed to get failed outbox messages", "error", err)
+		writeError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if messages == nil {
+		messages = []*domain.OutboxMessage{}
+	}
+	writeJSON(w, http.StatusOK, messages)
+}
+
+func (c *Console) redeliverOutboxMessage(w http.ResponseWriter, r *http.Request, _ *Operator, path []string) {
+	id, err := strconv.ParseInt(path[1], 10, 64)
+	if err != nil {
+		writeError(w, "Invalid ID, expected the number of an outbox message", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.outbox.RedeliverOutboxMessage(id); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrOutboxMessageNotFound):
+			writeError(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, domain.ErrOutboxMessageNotFailed):
+			writeError(w, err.Error(), http.StatusConflict)
+		default:
+			writeError(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serviceStatus maps the kind of an error of the application services to a status code
+func serviceStatus(err error) int {
+	switch app.KindOf(err) {
+	case app.KindInvalid:
+		return http.StatusBadRequest
+	case app.KindNotFound:
+		return http.StatusNotFound
+	case app.KindConflict:
+		return http.StatusConflict
+	case app.KindGone:
+		return http.StatusGone
+	case app.KindUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
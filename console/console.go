@@ -0,0 +1,186 @@
+// Package console serves the admin web console under /admin/console: a page for operators to
+// look after a deployment without calling the API with curl. It shows the build, the
+// households and their members, the configured integrations, the feature flags and the
+// background subsystems, jobs and outbox, and lets operators act on them as far as their console
+// role allows. Operators sign in with tokens of their own, separate from the admin tokens of the
+// API and the household roles.
+package console
+
+import (
+	"embed"
+	"encoding/json"
+	"go-expense-tracker/app"
+	"go-expense-tracker/buildinfo"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/features"
+	"go-expense-tracker/jobs"
+	"go-expense-tracker/leader"
+	"go-expense-tracker/maintenance"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Path is where the console is served
+const Path = "/admin/console/"
+
+// MaintenancePath is where the console sets the maintenance mode, which must stay writable while
+// the API is read-only, see maintenance.Mode.Exempt
+const MaintenancePath = Path + "api/maintenance"
+
+//go:embed static
+var static embed.FS
+
+// Integration is a configured connection to another service, shown without its secrets
+type Integration struct {
+	Kind   string `json:"kind"`   // e.g. "outbox-webhook", "mqtt" or "slack"
+	Target string `json:"target"` // Host or name the integration talks to, never a secret
+	Detail string `json:"detail,omitempty"`
+}
+
+// Target returns the scheme and host of the URL of an integration, leaving out the credentials,
+// path and query that may hold secrets
+func Target(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "(invalid URL)"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// Console serves the page and the JSON API behind it. The households, privacy and outbox
+// services are nil when the storage does not support them, which hides their section
+type Console struct {
+	operators    Operators
+	build        buildinfo.Info
+	flags        *features.Set
+	maintenance  *maintenance.Mode
+	households   *app.HouseholdService
+	privacy      *app.PrivacyService
+	outbox       domain.OutboxRepository
+	jobs         *jobs.Runner
+	elector      *leader.Elector
+	integrations []Integration
+	logger       *slog.Logger
+}
+
+// New creates a new Console letting in the given operators
+func New(operators Operators, build buildinfo.Info, flags *features.Set, maintenanceMode *maintenance.Mode, households *app.HouseholdService, privacy *app.PrivacyService, outbox domain.OutboxRepository, jobRunner *jobs.Runner, elector *leader.Elector, integrations []Integration, logger *slog.Logger) *Console {
+	return &Console{
+		operators:    operators,
+		build:        build,
+		flags:        flags,
+		maintenance:  maintenanceMode,
+		households:   households,
+		privacy:      privacy,
+		outbox:       outbox,
+		jobs:         jobRunner,
+		elector:      elector,
+		integrations: integrations,
+		logger:       logger,
+	}
+}
+
+// Handler serves the page at Path and its API below Path + "api/". The page holds no data, so it
+// is served to anyone; every API call needs the token of an operator
+func (c *Console) Handler() http.Handler {
+	files, _ := fs.Sub(static, "static")
+	page := http.StripPrefix(strings.TrimSuffix(Path, "/"), http.FileServerFS(files))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The page must not be framed by other sites, nor load anything but its own files
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+
+		rest, isAPI := strings.CutPrefix(r.URL.Path, Path+"api/")
+		if !isAPI {
+			page.ServeHTTP(w, r)
+			return
+		}
+
+		operator, ok := c.operators.Authenticate(r)
+		if !ok {
+			c.logger.Warn("Console request without a valid token", "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+			writeError(w, "Console token required", http.StatusUnauthorized)
+			return
+		}
+		c.route(w, r, operator, strings.Split(strings.TrimSuffix(rest, "/"), "/"))
+	})
+}
+
+// route dispatches an API call by the segments of its path below the API, checking the role of
+// the operator allows it
+func (c *Console) route(w http.ResponseWriter, r *http.Request, operator *Operator, path []string) {
+	type route struct {
+		method     string
+		permission Permission
+		handle     func(w http.ResponseWriter, r *http.Request, operator *Operator, path []string)
+	}
+	var found *route
+
+	switch {
+	case len(path) == 1 && path[0] == "me":
+		found = &route{http.MethodGet, PermissionView, c.getMe}
+	case len(path) == 1 && path[0] == "overview":
+		found = &route{http.MethodGet, PermissionView, c.getOverview}
+	case len(path) == 1 && path[0] == "households" && c.households != nil:
+		found = &route{http.MethodGet, PermissionView, c.getHouseholds}
+	case len(path) == 2 && path[0] == "members" && c.privacy != nil:
+		found = &route{http.MethodDelete, PermissionManage, c.removeMember}
+	case len(path) == 4 && path[0] == "households" && path[2] == "invitations" && c.households != nil:
+		found = &route{http.MethodDelete, PermissionManage, c.revokeInvitation}
+	case len(path) == 1 && path[0] == "integrations":
+		found = &route{http.MethodGet, PermissionView, c.getIntegrations}
+	case len(path) == 1 && path[0] == "flags":
+		found = &route{http.MethodGet, PermissionView, c.getFlags}
+	case len(path) == 2 && path[0] == "flags":
+		found = &route{http.MethodPut, PermissionOperate, c.toggleFlag}
+	case len(path) == 1 && path[0] == "maintenance":
+		found = &route{http.MethodPut, PermissionOperate, c.setMaintenance}
+	case len(path) == 1 && path[0] == "jobs":
+		found = &route{http.MethodGet, PermissionView, c.getJobs}
+	case len(path) == 2 && path[0] == "outbox" && path[1] == "failed" && c.outbox != nil:
+		found = &route{http.MethodGet, PermissionView, c.getFailedOutboxMessages}
+	case len(path) == 3 && path[0] == "outbox" && path[2] == "redeliver" && c.outbox != nil:
+		found = &route{http.MethodPost, PermissionOperate, c.redeliverOutboxMessage}
+	}
+
+	if found == nil {
+		writeError(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if r.Method != found.method {
+		w.Header().Set("Allow", found.method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !operator.Role.Can(found.permission) {
+		c.logger.Warn("Console operator not allowed", "operator", operator.Name, "role", operator.Role, "method", r.Method, "path", r.URL.Path)
+		writeError(w, "Your console role does not allow this", http.StatusForbidden)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		// Changes are logged with who made them, as the API they go around has no such record
+		c.logger.Warn("Console change", "operator", operator.Name, "role", operator.Role, "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+	}
+	found.handle(w, r, operator, path)
+}
+
+// errorResponse is the body of a failed API call, for the page to show
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, message string, status int) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
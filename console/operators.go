@@ -0,0 +1,107 @@
+package console
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+)
+
+var ErrInvalidRole = errors.New("invalid console role, use viewer, operator or admin")
+
+// Role is what an operator may do in the console, independent of the household roles of the API
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"   // Sees everything, changes nothing
+	RoleOperator Role = "operator" // Also toggles feature flags, maintenance mode and redelivers outbox messages
+	RoleAdmin    Role = "admin"    // Also removes household members and revokes invitations
+)
+
+// Permission is something a console role may allow, see Role.Can
+type Permission string
+
+const (
+	PermissionView    Permission = "view"
+	PermissionOperate Permission = "operate"
+	PermissionManage  Permission = "manage"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleViewer:   {PermissionView},
+	RoleOperator: {PermissionView, PermissionOperate},
+	RoleAdmin:    {PermissionView, PermissionOperate, PermissionManage},
+}
+
+// Can reports whether the role allows permission
+func (r Role) Can(permission Permission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
+
+// Permissions lists what the role allows, for the console to show only what can be used
+func (r Role) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+// Operator is someone allowed into the console. Only the SHA-256 of their token is configured, so
+// the operators file cannot be used to sign in
+type Operator struct {
+	Name        string `json:"name"`
+	Role        Role   `json:"role"`
+	TokenSHA256 string `json:"token_sha256"` // Hex-encoded, e.g. from `printf %s "$TOKEN" | sha256sum`
+}
+
+// Operators are the operators allowed into the console
+type Operators []Operator
+
+// LoadOperators reads the operators from a JSON file
+func LoadOperators(path string) (Operators, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading console operators file: %w", err)
+	}
+
+	var operators Operators
+	if err := json.Unmarshal(data, &operators); err != nil {
+		return nil, fmt.Errorf("error parsing console operators file: %w", err)
+	}
+
+	for i, operator := range operators {
+		if strings.TrimSpace(operator.Name) == "" {
+			return nil, fmt.Errorf("console operator %d has no name", i)
+		}
+		if _, ok := rolePermissions[operator.Role]; !ok {
+			return nil, fmt.Errorf("console operator %s: %w", operator.Name, ErrInvalidRole)
+		}
+		if hash, err := hex.DecodeString(operator.TokenSHA256); err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("console operator %s has no valid token_sha256", operator.Name)
+		}
+	}
+
+	return operators, nil
+}
+
+// Authenticate returns the operator whose token the request carries as bearer token. The hashes
+// of all operators are compared in constant time
+func (o Operators) Authenticate(r *http.Request) (*Operator, bool) {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		return nil, false
+	}
+	sum := sha256.Sum256([]byte(token))
+	hash := hex.EncodeToString(sum[:])
+
+	var match *Operator
+	for i := range o {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(strings.ToLower(o[i].TokenSHA256))) == 1 {
+			match = &o[i]
+		}
+	}
+	return match, match != nil
+}
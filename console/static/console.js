@@ -0,0 +1,217 @@
+// Admin console: signs in with an operator token kept for the browser session and renders the
+// sections of the console API. Everything is built with DOM calls, never from HTML strings, so
+// names and errors coming from the API cannot inject markup.
+"use strict";
+
+const api = "api/";
+let operator = null;
+let sections = [];
+let current = "overview";
+
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  for (const [key, value] of Object.entries(attrs || {})) {
+    if (key.startsWith("on")) {
+      node.addEventListener(key.slice(2), value);
+    } else {
+      node.setAttribute(key, value);
+    }
+  }
+  for (const child of children.flat()) {
+    if (child !== null && child !== undefined) {
+      node.append(child instanceof Node ? child : String(child));
+    }
+  }
+  return node;
+}
+
+function can(permission) {
+  return operator && operator.permissions.includes(permission);
+}
+
+async function call(method, path, body) {
+  const response = await fetch(api + path, {
+    method,
+    headers: {
+      Authorization: "Bearer " + sessionStorage.getItem("console-token"),
+      "Content-Type": "application/json",
+    },
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  if (response.status === 401) {
+    signOut();
+    throw new Error("Signed out, the token is no longer valid");
+  }
+  if (response.status === 204) {
+    return null;
+  }
+  const data = await response.json();
+  if (!response.ok) {
+    throw new Error(data.error || response.statusText);
+  }
+  return data;
+}
+
+function table(headers, rows) {
+  if (rows.length === 0) {
+    return el("p", { class: "muted" }, "Nothing to show");
+  }
+  return el("table", {},
+    el("thead", {}, el("tr", {}, headers.map((h) => el("th", {}, h)))),
+    el("tbody", {}, rows.map((row) => el("tr", {}, row.map((cell) => el("td", {}, cell))))));
+}
+
+function action(label, permission, run, danger) {
+  if (!can(permission)) {
+    return null;
+  }
+  return el("button", {
+    type: "button",
+    class: danger ? "danger" : "",
+    onclick: async () => {
+      if (danger && !confirm(label + "?")) {
+        return;
+      }
+      try {
+        await run();
+        await show(current);
+      } catch (err) {
+        showError(err);
+      }
+    },
+  }, label);
+}
+
+const renderers = {
+  async overview() {
+    const o = await call("GET", "overview");
+    const items = [
+      ["Version", o.build.version + (o.build.commit ? " (" + o.build.commit.slice(0, 12) + ")" : "")],
+      ["Go", o.build.go_version],
+      ["Read-only", o.maintenance.read_only ? "yes, " + (o.maintenance.reason || "no reason given") : "no"],
+      ["Background subsystems", o.leader.leading || !o.leader.elected ? "running on this replica" : "running on another replica"],
+      ["Running jobs", o.running_jobs],
+    ];
+    if (o.failed_outbox_messages !== undefined) {
+      items.push(["Outbox messages given up on", o.failed_outbox_messages]);
+    }
+    return [
+      el("dl", {}, items.map(([k, v]) => [el("dt", {}, k), el("dd", {}, v)])),
+      action(o.maintenance.read_only ? "Lift read-only mode" : "Make the API read-only", "operate",
+        () => call("PUT", "maintenance", { read_only: !o.maintenance.read_only }), !o.maintenance.read_only),
+    ];
+  },
+
+  async households() {
+    const households = await call("GET", "households");
+    return households.map((h) => [
+      el("h2", {}, h.name),
+      table(["Member", "Email", "Role", "Joined", ""], h.members.map((m) => [
+        m.name, m.email || "", m.role, new Date(m.joined_at).toLocaleDateString(),
+        action("Remove", "manage", () => call("DELETE", "members/" + m.id), true),
+      ])),
+      table(["Invitation", "Role", "Status", "Expires", ""], h.invitations.map((i) => [
+        i.email || i.id, i.role, i.status, new Date(i.expires_at).toLocaleDateString(),
+        i.status === "pending" ? action("Revoke", "manage", () => call("DELETE", "households/" + h.id + "/invitations/" + i.id), true) : null,
+      ])),
+    ]);
+  },
+
+  async integrations() {
+    const integrations = await call("GET", "integrations");
+    return table(["Kind", "Target", "Detail"], integrations.map((i) => [i.kind, i.target, i.detail || ""]));
+  },
+
+  async flags() {
+    const flags = await call("GET", "flags");
+    return table(["Flag", "Description", "Enabled", ""], flags.map((f) => [
+      f.name, f.description, f.enabled ? "yes" : "no",
+      f.startup_only ? el("span", { class: "muted" }, "needs a restart") :
+        action(f.enabled ? "Disable" : "Enable", "operate", () => call("PUT", "flags/" + f.name, { enabled: !f.enabled }), f.enabled),
+    ]));
+  },
+
+  async jobs() {
+    const status = await call("GET", "jobs");
+    return [
+      el("h2", {}, "Background subsystems"),
+      el("p", {}, !status.leader.elected ? "Every replica runs them." :
+        status.leader.leading ? "This replica leads and runs them." : "Another replica leads and runs them."),
+      table(["Subsystem"], status.leader.subsystems.map((s) => [s])),
+      el("h2", {}, "Jobs of this replica"),
+      table(["Kind", "Status", "Rows", "Started", "Error"], status.jobs.map((j) => [
+        j.kind, j.status, j.rows, new Date(j.created_at).toLocaleString(), j.error || "",
+      ])),
+    ];
+  },
+
+  async outbox() {
+    const messages = await call("GET", "outbox/failed");
+    return table(["ID", "Event", "Expenditure", "Attempts", "Last error", ""], messages.map((m) => [
+      m.id, m.topic, m.expenditure_id, m.attempts, m.last_error,
+      action("Redeliver", "operate", () => call("POST", "outbox/" + m.id + "/redeliver")),
+    ]));
+  },
+};
+
+function showError(err) {
+  document.getElementById("error").textContent = err ? err.message : "";
+}
+
+async function show(section) {
+  current = section;
+  showError(null);
+  const tabs = document.getElementById("tabs");
+  tabs.replaceChildren(...sections.map((s) => el("button", {
+    type: "button",
+    class: s === current ? "active" : "",
+    onclick: () => show(s),
+  }, s[0].toUpperCase() + s.slice(1))));
+
+  const content = document.getElementById("content");
+  try {
+    const rendered = await renderers[section]();
+    content.replaceChildren(...[rendered].flat(2).filter(Boolean));
+  } catch (err) {
+    content.replaceChildren();
+    showError(err);
+  }
+}
+
+async function start() {
+  operator = await call("GET", "me");
+  const overview = await call("GET", "overview");
+  sections = overview.sections;
+  document.getElementById("operator").textContent = operator.name + " (" + operator.role + ")";
+  document.getElementById("session").hidden = false;
+  document.getElementById("sign-in").hidden = true;
+  document.getElementById("app").hidden = false;
+  await show("overview");
+}
+
+function signOut() {
+  sessionStorage.removeItem("console-token");
+  operator = null;
+  document.getElementById("session").hidden = true;
+  document.getElementById("app").hidden = true;
+  document.getElementById("sign-in").hidden = false;
+}
+
+document.getElementById("sign-in").addEventListener("submit", async (event) => {
+  event.preventDefault();
+  sessionStorage.setItem("console-token", document.getElementById("token").value);
+  document.getElementById("token").value = "";
+  try {
+    await start();
+    document.getElementById("sign-in-error").textContent = "";
+  } catch (err) {
+    signOut();
+    document.getElementById("sign-in-error").textContent = err.message;
+  }
+});
+
+document.getElementById("sign-out").addEventListener("click", signOut);
+
+if (sessionStorage.getItem("console-token")) {
+  start().catch(signOut);
+}
@@ -0,0 +1,93 @@
+package devconsole
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// Capture is a slog.Handler passing records on to another handler, which also records them, at
+// any level, while the console sends a request. It passes records through untouched otherwise
+type Capture struct {
+	inner slog.Handler
+	tap   slog.Handler
+	state *captureState
+}
+
+type captureState struct {
+	run       sync.Mutex // Held while a request is recorded, one request at a time
+	recording atomic.Bool
+	mu        sync.Mutex
+	buf       bytes.Buffer
+}
+
+func (s *captureState) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+// NewCapture creates a new Capture in front of inner
+func NewCapture(inner slog.Handler) *Capture {
+	state := &captureState{}
+	return &Capture{
+		inner: inner,
+		tap:   slog.NewJSONHandler(state, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		state: state,
+	}
+}
+
+// Enabled reports whether inner handles records of the level, or a request is being recorded
+func (c *Capture) Enabled(ctx context.Context, level slog.Level) bool {
+	return c.state.recording.Load() || c.inner.Enabled(ctx, level)
+}
+
+// Handle records the record while a request is recorded and passes it on
+func (c *Capture) Handle(ctx context.Context, r slog.Record) error {
+	if c.state.recording.Load() {
+		c.tap.Handle(ctx, r)
+	}
+	if !c.inner.Enabled(ctx, r.Level) {
+		return nil
+	}
+	return c.inner.Handle(ctx, r)
+}
+
+// WithAttrs returns a Capture adding attrs to the records it passes on and records
+func (c *Capture) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Capture{inner: c.inner.WithAttrs(attrs), tap: c.tap.WithAttrs(attrs), state: c.state}
+}
+
+// WithGroup returns a Capture grouping the attributes of the records it passes on and records
+func (c *Capture) WithGroup(name string) slog.Handler {
+	return &Capture{inner: c.inner.WithGroup(name), tap: c.tap.WithGroup(name), state: c.state}
+}
+
+// Record runs fn and returns the records logged meanwhile as JSON objects. The server logs
+// without knowing which request a record belongs to, so records of other requests and of the
+// background subsystems logged at the same time are returned as well
+func (c *Capture) Record(fn func()) []json.RawMessage {
+	c.state.run.Lock()
+	defer c.state.run.Unlock()
+
+	c.state.mu.Lock()
+	c.state.buf.Reset()
+	c.state.mu.Unlock()
+
+	c.state.recording.Store(true)
+	fn()
+	c.state.recording.Store(false)
+
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	records := []json.RawMessage{}
+	for _, line := range bytes.Split(bytes.TrimSpace(c.state.buf.Bytes()), []byte("\n")) {
+		if len(line) > 0 {
+			records = append(records, json.RawMessage(bytes.Clone(line)))
+		}
+	}
+	return records
+}
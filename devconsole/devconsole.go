@@ -0,0 +1,194 @@
+// Package devconsole serves an interactive API console under /dev/console in development mode:
+// a page listing the requests of the API with sample headers and payloads to edit and send, which
+// shows the response along with the structured logs the server wrote while handling it.
+package devconsole
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Path is where the console is served
+const Path = "/dev/console/"
+
+const (
+	// sendTimeout cuts off requests that do not end by themselves, such as event streams
+	sendTimeout = 30 * time.Second
+	// maxResponseBody caps the response body shown by the console
+	maxResponseBody = 1 << 20
+)
+
+//go:embed static
+var static embed.FS
+
+// Console serves the page and sends its requests to the API in process, recording the logs
+type Console struct {
+	api     http.Handler
+	samples []Sample
+	capture *Capture
+	logger  *slog.Logger
+}
+
+// New creates a new Console sending requests to api, with the records of capture
+func New(api http.Handler, samples []Sample, capture *Capture, logger *slog.Logger) *Console {
+	return &Console{
+		api:     api,
+		samples: samples,
+		capture: capture,
+		logger:  logger,
+	}
+}
+
+// SendRequest is a request crafted on the page
+type SendRequest struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+}
+
+// SendResponse is the response of the API to a crafted request, with the logs written meanwhile
+type SendResponse struct {
+	Status     int                 `json:"status"`
+	Headers    map[string][]string `json:"headers"`
+	Body       string              `json:"body"`
+	Truncated  bool                `json:"truncated,omitempty"`
+	DurationMS int64               `json:"duration_ms"`
+	Logs       []json.RawMessage   `json:"logs"`
+}
+
+// Handler serves the page at Path, the samples at Path + "api/samples" and sends requests posted
+// to Path + "api/send"
+func (c *Console) Handler() http.Handler {
+	files, _ := fs.Sub(static, "static")
+	page := http.StripPrefix(strings.TrimSuffix(Path, "/"), http.FileServerFS(files))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case Path + "api/samples":
+			if r.Method != http.MethodGet {
+				w.Header().Set("Allow", http.MethodGet)
+				writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			samples := c.samples
+			if samples == nil {
+				samples = []Sample{}
+			}
+			writeJSON(w, http.StatusOK, samples)
+		case Path + "api/send":
+			if r.Method != http.MethodPost {
+				w.Header().Set("Allow", http.MethodPost)
+				writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			c.send(w, r)
+		default:
+			page.ServeHTTP(w, r)
+		}
+	})
+}
+
+func (c *Console) send(w http.ResponseWriter, r *http.Request) {
+	var req SendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "Invalid request body, expected {\"method\": \"GET\", \"path\": \"/expenditures\"}", http.StatusBadRequest)
+		return
+	}
+	if req.Method == "" {
+		req.Method = http.MethodGet
+	}
+	if !strings.HasPrefix(req.Path, "/") || strings.HasPrefix(req.Path, Path) {
+		writeError(w, "Path must start with / and lie outside the console", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), sendTimeout)
+	defer cancel()
+	apiReq, err := http.NewRequestWithContext(ctx, strings.ToUpper(req.Method), req.Path, strings.NewReader(req.Body))
+	if err != nil {
+		writeError(w, "Invalid request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	apiReq.Host = r.Host
+	apiReq.RemoteAddr = r.RemoteAddr
+	for name, value := range req.Headers {
+		apiReq.Header.Set(name, value)
+	}
+
+	rec := newRecorder()
+	start := time.Now()
+	logs := c.capture.Record(func() {
+		c.api.ServeHTTP(rec, apiReq)
+	})
+
+	c.logger.Debug("API console request sent", "method", apiReq.Method, "path", req.Path, "status", rec.status)
+
+	body := rec.body.Bytes()
+	response := SendResponse{
+		Status:     rec.status,
+		Headers:    rec.header,
+		DurationMS: time.Since(start).Milliseconds(),
+		Logs:       logs,
+	}
+	if len(body) > maxResponseBody {
+		body = body[:maxResponseBody]
+		response.Truncated = true
+	}
+	response.Body = string(body)
+	writeJSON(w, http.StatusOK, response)
+}
+
+// recorder keeps the response of the API for the console to show
+type recorder struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func newRecorder() *recorder {
+	return &recorder{header: http.Header{}, status: http.StatusOK}
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	return r.body.Write(p)
+}
+
+// Flush lets streaming handlers flush; the body is shown once the request ends
+func (r *recorder) Flush() {}
+
+// errorResponse is the body of a failed console call, for the page to show
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, message string, status int) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
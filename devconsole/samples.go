@@ -0,0 +1,69 @@
+package devconsole
+
+import (
+	"bufio"
+	"bytes"
+	"net/url"
+	"strings"
+)
+
+// Sample is an example request of the API, listed by the console to start from
+type Sample struct {
+	Name    string            `json:"name"`
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// ParseSamples reads the example requests of a file in the format of requests.http: each starts
+// with a "### Name" line, followed by the request line "METHOD URL", the headers and, after a
+// blank line, the body. The URLs are reduced to their path and query, so the console sends them
+// to the server it is served by
+func ParseSamples(data []byte) []Sample {
+	var samples []Sample
+	var current *Sample
+	var body []string
+	inBody := false
+
+	flush := func() {
+		if current != nil && current.Method != "" {
+			current.Body = strings.TrimSpace(strings.Join(body, "\n"))
+			samples = append(samples, *current)
+		}
+		current, body, inBody = nil, nil, false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if name, ok := strings.CutPrefix(line, "###"); ok {
+			flush()
+			current = &Sample{Name: strings.TrimSpace(name), Headers: map[string]string{}}
+			continue
+		}
+		switch {
+		case current == nil:
+		case current.Method == "":
+			method, target, ok := strings.Cut(strings.TrimSpace(line), " ")
+			if !ok {
+				continue
+			}
+			current.Method = method
+			current.Path = target
+			if u, err := url.Parse(strings.TrimSpace(target)); err == nil && u.Path != "" {
+				current.Path = u.RequestURI()
+			}
+		case inBody:
+			body = append(body, line)
+		case strings.TrimSpace(line) == "":
+			inBody = true
+		default:
+			if name, value, ok := strings.Cut(line, ":"); ok {
+				current.Headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+			}
+		}
+	}
+	flush()
+	return samples
+}
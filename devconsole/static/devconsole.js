@@ -0,0 +1,118 @@
+// API console: lists the sample requests, sends the request crafted in the form through the
+// console API and shows the response with the logs written meanwhile. Everything is built with
+// DOM calls, never from HTML strings, so responses and logs cannot inject markup.
+"use strict";
+
+let samples = [];
+
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  for (const [key, value] of Object.entries(attrs || {})) {
+    if (key.startsWith("on")) {
+      node.addEventListener(key.slice(2), value);
+    } else {
+      node.setAttribute(key, value);
+    }
+  }
+  for (const child of children.flat()) {
+    if (child !== null && child !== undefined) {
+      node.append(child instanceof Node ? child : String(child));
+    }
+  }
+  return node;
+}
+
+function showError(err) {
+  document.getElementById("error").textContent = err ? err.message : "";
+}
+
+function pick(sample) {
+  document.getElementById("method").value = sample.method;
+  document.getElementById("path").value = sample.path;
+  document.getElementById("headers").value = Object.entries(sample.headers)
+    .map(([name, value]) => name + ": " + value).join("\n");
+  document.getElementById("body").value = sample.body || "";
+}
+
+function listSamples() {
+  const filter = document.getElementById("filter").value.toLowerCase();
+  const shown = samples.filter((s) =>
+    (s.name + " " + s.method + " " + s.path).toLowerCase().includes(filter));
+  document.getElementById("samples").replaceChildren(...shown.map((s) => el("li", {},
+    el("button", { type: "button", title: s.path, onclick: () => pick(s) },
+      el("span", { class: "method" }, s.method), s.name))));
+}
+
+function parseHeaders(text) {
+  const headers = {};
+  for (const line of text.split("\n")) {
+    const at = line.indexOf(":");
+    if (at > 0) {
+      headers[line.slice(0, at).trim()] = line.slice(at + 1).trim();
+    }
+  }
+  return headers;
+}
+
+function pretty(body, headers) {
+  const type = (headers["Content-Type"] || []).join(",");
+  if (type.includes("json")) {
+    try {
+      return JSON.stringify(JSON.parse(body), null, 2);
+    } catch (err) {
+      // Streams of JSON lines are shown as they are
+    }
+  }
+  return body;
+}
+
+function renderLog(record) {
+  const { time, level, msg, ...attrs } = record;
+  return el("div", { class: "log " + level },
+    el("strong", {}, level + " " + msg),
+    el("span", { class: "muted" }, " " + new Date(time).toLocaleTimeString()),
+    Object.keys(attrs).length ? el("div", {}, JSON.stringify(attrs)) : null);
+}
+
+async function send(event) {
+  event.preventDefault();
+  showError(null);
+  const request = {
+    method: document.getElementById("method").value,
+    path: document.getElementById("path").value,
+    headers: parseHeaders(document.getElementById("headers").value),
+    body: document.getElementById("body").value,
+  };
+  try {
+    const response = await fetch("api/send", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify(request),
+    });
+    const data = await response.json();
+    if (!response.ok) {
+      throw new Error(data.error || response.statusText);
+    }
+    document.getElementById("status").textContent = data.status + " in " + data.duration_ms + " ms";
+    document.getElementById("response-headers").textContent = Object.entries(data.headers)
+      .map(([name, values]) => values.map((v) => name + ": " + v).join("\n")).join("\n");
+    document.getElementById("response-body").textContent = pretty(data.body, data.headers) +
+      (data.truncated ? "\n\n(truncated)" : "");
+    document.getElementById("logs").replaceChildren(...(data.logs.length ?
+      data.logs.map(renderLog) : [el("p", { class: "muted" }, "Nothing was logged")]));
+    document.getElementById("result").hidden = false;
+  } catch (err) {
+    showError(err);
+  }
+}
+
+document.getElementById("request").addEventListener("submit", send);
+document.getElementById("filter").addEventListener("input", listSamples);
+
+fetch("api/samples")
+  .then((response) => response.json())
+  .then((data) => {
+    samples = data;
+    listSamples();
+  })
+  .catch(showError);
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var ErrAccountNameEmpty = errors.New("account name cannot be empty")
+var ErrStatementBeforeReconciled = errors.New("statement ends before the account was last reconciled")
+var ErrExpenditureReconciled = errors.New("expenditure is reconciled; unlock it before changing it")
+var ErrExpenditureNotReconciled = errors.New("expenditure is not reconciled")
+
+// Account is a bank or card account expenditures are paid from, reconciled against its statements
+type Account struct {
+	ID                uuid.UUID  `json:"id"`
+	Name              string     `json:"name"`
+	OpeningBalance    float64    `json:"opening_balance"`              // Balance before the first expenditure recorded on it
+	ReconciledThrough *time.Time `json:"reconciled_through,omitempty"` // Last day of the last reconciled statement
+	ReconciledBalance float64    `json:"reconciled_balance"`           // Balance of the last reconciled statement
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// Reconciliation is the outcome of reconciling an account against a statement
+type Reconciliation struct {
+	AccountId        uuid.UUID `json:"account_id"`
+	EndDate          time.Time `json:"end_date"`
+	StatementBalance float64   `json:"statement_balance"`
+	Balance          float64   `json:"balance"`     // Balance of the account at the end date as recorded
+	Discrepancy      float64   `json:"discrepancy"` // Statement balance minus the recorded balance, 0 when they agree
+	Reconciled       int       `json:"reconciled"`  // Expenditures newly marked reconciled
+	Pending          int       `json:"pending"`     // Pending expenditures left out until they clear
+}
+
+func NewAccount(name string, openingBalance float64) (*Account, error) {
+	if name == "" {
+		return nil, ErrAccountNameEmpty
+	}
+
+	return &Account{
+		ID:             uuid.New(),
+		Name:           name,
+		OpeningBalance: round2(openingBalance),
+		CreatedAt:      time.Now(),
+	}, nil
+}
+
+// Reconcile compares the balance of a statement ending on endDate with the balance recorded from
+// the account's expenditures, and returns the outcome with the cleared expenditures up to the end
+// date that are not reconciled yet. Refunds, having negative amounts, add to the balance
+func (a *Account) Reconcile(expenditures []*Expenditure, endDate time.Time, statementBalance float64) (Reconciliation, []*Expenditure, error) {
+	endDate = SpendingDay(endDate)
+	if a.ReconciledThrough != nil && endDate.Before(*a.ReconciledThrough) {
+		return Reconciliation{}, nil, ErrStatementBeforeReconciled
+	}
+
+	reconciliation := Reconciliation{AccountId: a.ID, EndDate: endDate, StatementBalance: round2(statementBalance)}
+	balance := a.OpeningBalance
+	var matched []*Expenditure
+	for _, expenditure := range expenditures {
+		if expenditure.AccountId != a.ID || expenditure.IsCancelled() || !expenditure.Date.Before(endDate.AddDate(0, 0, 1)) {
+			continue
+		}
+		if expenditure.IsPending() {
+			reconciliation.Pending++
+			continue
+		}
+		balance -= expenditure.Amount
+		if !expenditure.Reconciled {
+			matched = append(matched, expenditure)
+		}
+	}
+
+	reconciliation.Balance = round2(balance)
+	reconciliation.Discrepancy = round2(reconciliation.StatementBalance - reconciliation.Balance)
+	reconciliation.Reconciled = len(matched)
+	return reconciliation, matched, nil
+}
+
+// Reconciled records the statement the account was last reconciled against
+func (a *Account) Reconciled(reconciliation Reconciliation) {
+	endDate := reconciliation.EndDate
+	a.ReconciledThrough = &endDate
+	a.ReconciledBalance = reconciliation.StatementBalance
+}
+
+// Unlock allows changes to a reconciled expenditure again; it is reconciled anew with the next
+// statement
+func (e *Expenditure) Unlock() error {
+	if !e.Reconciled {
+		return ErrExpenditureNotReconciled
+	}
+	e.Reconciled = false
+	return nil
+}
+
+// Clone returns a deep copy of the account
+func (a *Account) Clone() *Account {
+	clone := *a
+	clone.ReconciledThrough = cloneTime(a.ReconciledThrough)
+	return &clone
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Activity kinds
+const (
+	ActivityExpenditureAdded   = "expenditure_added"
+	ActivityExpenditureEdited  = "expenditure_edited"
+	ActivityExpenditureDeleted = "expenditure_deleted"
+	ActivityImportCommitted    = "import_committed"
+	ActivityBudgetChanged      = "budget_changed"
+)
+
+// Activity is an entry of the activity feed, a change someone made described for people
+type Activity struct {
+	Seq       int64     `json:"seq"` // Increases with every activity, used as paging cursor
+	Kind      string    `json:"kind"`
+	SubjectID uuid.UUID `json:"subject_id"` // Expenditure, category or budget changed, nil for imports
+	Summary   string    `json:"summary"`
+	Count     int       `json:"count,omitempty"` // Expenditures committed by an import
+	At        time.Time `json:"at"`
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var ErrInvalidAnomalyKind = errors.New("anomaly kind must be unusual_amount, possible_duplicate or daily_spike")
+
+// AnomalyKind is the reason an expenditure, or a day of spending, looks unusual
+type AnomalyKind string
+
+const (
+	AnomalyUnusualAmount     AnomalyKind = "unusual_amount"     // Far above what is usually spent in the category or at the merchant
+	AnomalyPossibleDuplicate AnomalyKind = "possible_duplicate" // Same amount at the same merchant shortly after another charge
+	AnomalyDailySpike        AnomalyKind = "daily_spike"        // A day's total far above the days before it
+)
+
+// ParseAnomalyKind reads an anomaly kind; an empty kind is returned as is, meaning any
+func ParseAnomalyKind(kind string) (AnomalyKind, error) {
+	switch AnomalyKind(kind) {
+	case "", AnomalyUnusualAmount, AnomalyPossibleDuplicate, AnomalyDailySpike:
+		return AnomalyKind(kind), nil
+	default:
+		return "", ErrInvalidAnomalyKind
+	}
+}
+
+// Anomaly is spending that stands out from the history before it
+type Anomaly struct {
+	Kind          AnomalyKind `json:"kind"`
+	Date          time.Time   `json:"date"`                    // Of the expenditure, or the day of a spike
+	ExpenditureID uuid.UUID   `json:"expenditure_id,omitzero"` // Empty for daily spikes
+	DuplicateOf   uuid.UUID   `json:"duplicate_of,omitzero"`   // Earlier charge a possible duplicate repeats
+	Description   string      `json:"description,omitempty"`   // Of the expenditure
+	Amount        float64     `json:"amount"`                  // Of the expenditure, or the day's total
+	Expected      float64     `json:"expected,omitempty"`      // Usual amount it is compared to, empty for duplicates
+	Reason        string      `json:"reason"`                  // Human-readable explanation
+}
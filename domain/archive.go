@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"math"
+	"slices"
+	"sort"
+	"time"
+)
+
+var ErrNothingToArchive = errors.New("no expenditures to archive")
+var ErrInvalidRetentionYears = errors.New("retention must keep at least the current year")
+
+// Archive records a run of the archival, which moves old expenditures out of the live
+// expenditures into cold storage while keeping their monthly totals queryable
+type Archive struct {
+	ID        uuid.UUID        `json:"id"`         // Unique identifier for the archive
+	Before    time.Time        `json:"before"`     // Expenditures dated before this were archived
+	Count     int              `json:"count"`      // Number of archived expenditures
+	Total     float64          `json:"total"`      // Sum of the archived amounts
+	Summaries []ArchiveSummary `json:"summaries"`  // Totals per month and category
+	CreatedAt time.Time        `json:"created_at"` // When the archival ran
+}
+
+// ArchiveSummary is the spending of one month in one category of an archive
+type ArchiveSummary struct {
+	Month      string    `json:"month"` // YYYY-MM
+	CategoryId uuid.UUID `json:"category_id"`
+	Count      int       `json:"count"`
+	Total      float64   `json:"total"`
+}
+
+// NewArchive summarizes the expenditures moved by an archival run
+func NewArchive(before time.Time, expenditures []*Expenditure) *Archive {
+	archive := &Archive{
+		ID:        uuid.New(),
+		Before:    before,
+		Count:     len(expenditures),
+		CreatedAt: time.Now(),
+	}
+
+	type key struct {
+		month      string
+		categoryId uuid.UUID
+	}
+	summaries := make(map[key]*ArchiveSummary)
+	for _, expenditure := range expenditures {
+		k := key{expenditure.Date.Format("2006-01"), expenditure.CategoryId}
+		summary, ok := summaries[k]
+		if !ok {
+			summary = &ArchiveSummary{Month: k.month, CategoryId: k.categoryId}
+			summaries[k] = summary
+		}
+		summary.Count++
+		summary.Total += expenditure.Amount
+		archive.Total += expenditure.Amount
+	}
+
+	archive.Summaries = make([]ArchiveSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		summary.Total = math.Round(summary.Total*100) / 100
+		archive.Summaries = append(archive.Summaries, *summary)
+	}
+	sort.Slice(archive.Summaries, func(i, j int) bool {
+		if archive.Summaries[i].Month != archive.Summaries[j].Month {
+			return archive.Summaries[i].Month < archive.Summaries[j].Month
+		}
+		return archive.Summaries[i].CategoryId.String() < archive.Summaries[j].CategoryId.String()
+	})
+	archive.Total = math.Round(archive.Total*100) / 100
+
+	return archive
+}
+
+// ArchiveCutoff returns the start of the oldest year kept live when retentionYears years
+// are kept, the current one included. With 2 years kept in 2024, everything before
+// 2023-01-01 is archived
+func ArchiveCutoff(now time.Time, retentionYears int) (time.Time, error) {
+	if retentionYears < 1 {
+		return time.Time{}, ErrInvalidRetentionYears
+	}
+	return time.Date(now.Year()-retentionYears+1, time.January, 1, 0, 0, 0, 0, now.Location()), nil
+}
+
+// Clone returns a deep copy of the archive
+func (a *Archive) Clone() *Archive {
+	clone := *a
+	clone.Summaries = slices.Clone(a.Summaries)
+	return &clone
+}
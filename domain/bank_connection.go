@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var ErrBankConnectionConnectorEmpty = errors.New("bank connection connector cannot be empty")
+var ErrBankConnectionNameEmpty = errors.New("bank connection name cannot be empty")
+var ErrBankConnectionAccessTokenEmpty = errors.New("bank connection access token cannot be empty")
+
+// BankConnection holds the credentials a BankConnector uses to pull transactions from one account
+type BankConnection struct {
+	ID           uuid.UUID  `json:"id"`             // Unique identifier for the connection
+	Connector    string     `json:"connector"`      // Name of the connector, e.g. "plaid"
+	Name         string     `json:"name"`           // User-facing name, e.g. "Checking account"
+	AccessToken  string     `json:"-"`              // Connector credential, never returned by the API
+	Cursor       string     `json:"-"`              // Connector-specific sync position
+	LastSyncedAt *time.Time `json:"last_synced_at"` // When the last successful sync finished
+	LastError    string     `json:"last_error"`     // Error of the last failed sync, empty when it succeeded
+	CreatedAt    time.Time  `json:"created_at"`     // When the connection was added
+}
+
+func NewBankConnection(connector, name, accessToken string) (*BankConnection, error) {
+	if connector == "" {
+		return nil, ErrBankConnectionConnectorEmpty
+	}
+
+	if name == "" {
+		return nil, ErrBankConnectionNameEmpty
+	}
+
+	if accessToken == "" {
+		return nil, ErrBankConnectionAccessTokenEmpty
+	}
+
+	return &BankConnection{
+		ID:          uuid.New(),
+		Connector:   connector,
+		Name:        name,
+		AccessToken: accessToken,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// Clone returns a deep copy of the bank connection
+func (c *BankConnection) Clone() *BankConnection {
+	clone := *c
+	clone.LastSyncedAt = cloneTime(c.LastSyncedAt)
+	return &clone
+}
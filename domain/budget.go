@@ -0,0 +1,295 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"math"
+	"time"
+)
+
+var ErrBudgetNameEmpty = errors.New("budget name cannot be empty")
+var ErrInvalidBudgetAmount = errors.New("invalid budget amount")
+var ErrInvalidBudgetPeriod = errors.New("budget period must be weekly, monthly, quarterly, yearly or custom")
+var ErrBudgetEndBeforeStart = errors.New("budget end date must not be before its start date")
+var ErrBudgetEndDateRequired = errors.New("custom budgets need an end date")
+var ErrBudgetRolloverCustom = errors.New("custom budgets have a single period and cannot roll over")
+
+// BudgetPeriod is how often the amount of a budget is available again
+type BudgetPeriod string
+
+const (
+	BudgetWeekly    BudgetPeriod = "weekly"    // Weeks starting on Monday
+	BudgetMonthly   BudgetPeriod = "monthly"   // Fiscal months
+	BudgetQuarterly BudgetPeriod = "quarterly" // Three fiscal months
+	BudgetYearly    BudgetPeriod = "yearly"    // Twelve fiscal months
+	BudgetCustom    BudgetPeriod = "custom"    // Once, from the start to the end date
+)
+
+// Budget limits the spending, in one category or overall, per period
+type Budget struct {
+	ID         uuid.UUID    `json:"id"`
+	Name       string       `json:"name"`
+	Amount     float64      `json:"amount"` // Limit per period
+	Period     BudgetPeriod `json:"period"`
+	CategoryId uuid.UUID    `json:"category_id"`        // Category whose spending counts, empty for all spending
+	StartDate  time.Time    `json:"start_date"`         // First day of the budget
+	EndDate    *time.Time   `json:"end_date,omitempty"` // Last day of the budget, nil when it keeps recurring
+	Rollover   bool         `json:"rollover"`           // Whether unused amounts and overspending carry into the next period
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// BudgetStatus is the spending against a budget in one of its periods
+type BudgetStatus struct {
+	BudgetID    uuid.UUID        `json:"budget_id"`
+	Name        string           `json:"name"`
+	Period      BudgetPeriod     `json:"period"`
+	From        time.Time        `json:"from"` // First day of the period
+	To          time.Time        `json:"to"`   // Day after the period
+	Limit       float64          `json:"limit"`
+	Prorated    bool             `json:"prorated"`        // Whether the budget covers only part of the period
+	Carried     float64          `json:"carried"`         // Rolled over from earlier periods, negative for a deficit
+	Effective   float64          `json:"effective_limit"` // Limit plus the amount carried
+	Spent       float64          `json:"spent"`
+	Remaining   float64          `json:"remaining"` // Negative when overspent
+	PercentUsed float64          `json:"percent_used"`
+	Overspent   bool             `json:"overspent"`
+	History     []BudgetRollover `json:"history,omitempty"`  // Earlier periods of rollover budgets, oldest first
+	Envelope    *EnvelopeBalance `json:"envelope,omitempty"` // Envelope of the budget's category, if any
+}
+
+// BudgetPace compares the spending of a budget's period up to a day with spending its effective
+// limit evenly over the period
+type BudgetPace struct {
+	BudgetID uuid.UUID `json:"budget_id"`
+	Name     string    `json:"name"`
+	Limit    float64   `json:"limit"` // Effective limit of the period
+	Pace     float64   `json:"pace"`  // Share of the limit for the days of the period up to the day
+	Spent    float64   `json:"spent"` // In the period up to the day
+	OverPace bool      `json:"over_pace"`
+}
+
+// BudgetRollover is an earlier period of a rollover budget and what it carried into the next one
+type BudgetRollover struct {
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Limit   float64   `json:"limit"`
+	Spent   float64   `json:"spent"`
+	Carried float64   `json:"carried"` // Carried into the next period, including earlier carries
+}
+
+func NewBudget(name string, amount float64, period BudgetPeriod, categoryId uuid.UUID, startDate time.Time, endDate *time.Time, rollover bool) (*Budget, error) {
+	budget := &Budget{ID: uuid.New(), CreatedAt: time.Now()}
+	if err := budget.Update(name, amount, period, categoryId, startDate, endDate, rollover); err != nil {
+		return nil, err
+	}
+	return budget, nil
+}
+
+// Update changes the budget; the start and end dates are truncated to days in UTC
+func (b *Budget) Update(name string, amount float64, period BudgetPeriod, categoryId uuid.UUID, startDate time.Time, endDate *time.Time, rollover bool) error {
+	if name == "" {
+		return ErrBudgetNameEmpty
+	}
+
+	if amount <= 0 {
+		return ErrInvalidBudgetAmount
+	}
+
+	switch period {
+	case BudgetWeekly, BudgetMonthly, BudgetQuarterly, BudgetYearly, BudgetCustom:
+	default:
+		return ErrInvalidBudgetPeriod
+	}
+
+	if period == BudgetCustom && endDate == nil {
+		return ErrBudgetEndDateRequired
+	}
+
+	if period == BudgetCustom && rollover {
+		return ErrBudgetRolloverCustom
+	}
+
+	startDate = SpendingDay(startDate)
+	if endDate != nil {
+		end := SpendingDay(*endDate)
+		if end.Before(startDate) {
+			return ErrBudgetEndBeforeStart
+		}
+		endDate = &end
+	}
+
+	b.Name = name
+	b.Amount = amount
+	b.Period = period
+	b.CategoryId = categoryId
+	b.StartDate = startDate
+	b.EndDate = endDate
+	b.Rollover = rollover
+
+	return nil
+}
+
+// PeriodAt returns the period of the budget containing the day at, as [from, to). Before the start
+// of the budget it is the first period and after its end the last one
+func (b *Budget) PeriodAt(at time.Time, calendar FiscalCalendar) (time.Time, time.Time) {
+	day := SpendingDay(at)
+	if day.Before(b.StartDate) {
+		day = b.StartDate
+	}
+	if b.EndDate != nil && day.After(*b.EndDate) {
+		day = *b.EndDate
+	}
+
+	switch b.Period {
+	case BudgetWeekly:
+		from := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return from, from.AddDate(0, 0, 7)
+	case BudgetMonthly:
+		return calendar.Period(day)
+	case BudgetQuarterly:
+		return calendar.Span(day, 3)
+	case BudgetYearly:
+		return calendar.Span(day, 12)
+	default:
+		return b.StartDate, b.EndDate.AddDate(0, 0, 1)
+	}
+}
+
+// PeriodStarts returns the days in [from, to) on which a period of the budget begins, in order.
+// The first period begins on the start date of the budget, even when it starts within a period
+func (b *Budget) PeriodStarts(from, to time.Time, calendar FiscalCalendar) []time.Time {
+	starts := []time.Time{}
+	day := SpendingDay(from)
+	if day.Before(b.StartDate) {
+		day = b.StartDate
+	}
+	for day.Before(to) && b.Covers(day) {
+		start, end := b.PeriodAt(day, calendar)
+		if start.Before(b.StartDate) {
+			start = b.StartDate
+		}
+		if !start.Before(SpendingDay(from)) {
+			starts = append(starts, start)
+		}
+		day = end
+	}
+	return starts
+}
+
+// SpendingFrom returns the first day whose spending the status at needs; rollover budgets need
+// every period since their start
+func (b *Budget) SpendingFrom(at time.Time, calendar FiscalCalendar) time.Time {
+	if b.Rollover {
+		at = b.StartDate
+	}
+	from, _ := b.PeriodAt(at, calendar)
+	return from
+}
+
+// Status computes the spending against the budget in the period containing at. The limit is
+// prorated by day when the budget starts or ends within the period. For rollover budgets the
+// balance of every earlier period is carried into the effective limit
+func (b *Budget) Status(spending []*DailySpending, at time.Time, calendar FiscalCalendar) BudgetStatus {
+	from, to := b.PeriodAt(at, calendar)
+	status := BudgetStatus{
+		BudgetID: b.ID,
+		Name:     b.Name,
+		Period:   b.Period,
+		From:     from,
+		To:       to,
+	}
+	status.Limit, status.Prorated, status.Spent = b.periodSpending(spending, from, to)
+
+	if b.Rollover {
+		for periodFrom, periodTo := b.PeriodAt(b.StartDate, calendar); periodFrom.Before(from); periodFrom, periodTo = b.PeriodAt(periodTo, calendar) {
+			limit, _, spent := b.periodSpending(spending, periodFrom, periodTo)
+			status.Carried = round2(status.Carried + limit - spent)
+			status.History = append(status.History, BudgetRollover{
+				From:    periodFrom,
+				To:      periodTo,
+				Limit:   limit,
+				Spent:   spent,
+				Carried: status.Carried,
+			})
+		}
+	}
+
+	status.Effective = round2(status.Limit + status.Carried)
+	status.Remaining = round2(status.Effective - status.Spent)
+	if status.Effective > 0 {
+		status.PercentUsed = round2(status.Spent / status.Effective * 100)
+	}
+	status.Overspent = status.Spent > status.Effective
+	return status
+}
+
+// Covers reports whether the day falls between the start and the end of the budget
+func (b *Budget) Covers(day time.Time) bool {
+	day = SpendingDay(day)
+	return !day.Before(b.StartDate) && (b.EndDate == nil || !day.After(*b.EndDate))
+}
+
+// Pace compares the spending of the period containing day, up to the end of day, with an even
+// pace through the days the budget covers in the period
+func (b *Budget) Pace(spending []*DailySpending, day time.Time, calendar FiscalCalendar) BudgetPace {
+	from, to := b.PeriodAt(day, calendar)
+	status := b.Status(spending, day, calendar)
+	end := SpendingDay(day).AddDate(0, 0, 1)
+
+	coveredFrom, coveredTo := from, to
+	if b.StartDate.After(coveredFrom) {
+		coveredFrom = b.StartDate
+	}
+	if b.EndDate != nil && b.EndDate.AddDate(0, 0, 1).Before(coveredTo) {
+		coveredTo = b.EndDate.AddDate(0, 0, 1)
+	}
+
+	pace := BudgetPace{BudgetID: b.ID, Name: b.Name, Limit: status.Effective}
+	if elapsed := days(coveredFrom, end); elapsed > 0 {
+		pace.Pace = round2(status.Effective * min(elapsed/days(coveredFrom, coveredTo), 1))
+	}
+	_, _, pace.Spent = b.periodSpending(spending, from, end)
+	pace.OverPace = pace.Spent > pace.Pace
+	return pace
+}
+
+// periodSpending returns the limit of the period [from, to), prorated when the budget covers only
+// part of it, and the spending counting against it
+func (b *Budget) periodSpending(spending []*DailySpending, from, to time.Time) (float64, bool, float64) {
+	coveredFrom, coveredTo := from, to
+	if b.StartDate.After(coveredFrom) {
+		coveredFrom = b.StartDate
+	}
+	if b.EndDate != nil && b.EndDate.AddDate(0, 0, 1).Before(coveredTo) {
+		coveredTo = b.EndDate.AddDate(0, 0, 1)
+	}
+
+	limit, prorated := b.Amount, false
+	if !coveredFrom.Equal(from) || !coveredTo.Equal(to) {
+		limit, prorated = round2(b.Amount*days(coveredFrom, coveredTo)/days(from, to)), true
+	}
+
+	var spent float64
+	for _, day := range spending {
+		if day.Day.Before(coveredFrom) || !day.Day.Before(coveredTo) {
+			continue
+		}
+		if b.CategoryId != uuid.Nil && day.CategoryId != b.CategoryId {
+			continue
+		}
+		spent += day.Total
+	}
+	return limit, prorated, round2(spent)
+}
+
+// days counts the days in [from, to), rounding so that DST changes do not matter
+func days(from, to time.Time) float64 {
+	return math.Round(to.Sub(from).Hours() / 24)
+}
+
+// Clone returns a deep copy of the budget
+func (b *Budget) Clone() *Budget {
+	clone := *b
+	clone.EndDate = cloneTime(b.EndDate)
+	return &clone
+}
@@ -0,0 +1,229 @@
+package domain_test
+
+import (
+	"go-expense-tracker/domain"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// day returns midnight UTC of the date, as budgets keep their days
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+// newBudget creates a budget or fails the test
+func newBudget(t *testing.T, amount float64, period domain.BudgetPeriod, start time.Time, end *time.Time, rollover bool) *domain.Budget {
+	t.Helper()
+	budget, err := domain.NewBudget("Budget", amount, period, uuid.Nil, start, end, rollover)
+	if err != nil {
+		t.Fatalf("creating budget: %v", err)
+	}
+	return budget
+}
+
+func TestBudgetPeriodStarts(t *testing.T) {
+	end := day(2024, 4, 10)
+	tests := []struct {
+		name     string
+		period   domain.BudgetPeriod
+		start    time.Time
+		end      *time.Time
+		calendar domain.FiscalCalendar
+		from, to time.Time
+		want     []time.Time
+	}{
+		{"monthly", domain.BudgetMonthly, day(2024, 1, 1), nil, domain.FiscalCalendar{}, day(2024, 1, 15), day(2024, 4, 1), []time.Time{day(2024, 2, 1), day(2024, 3, 1)}},
+		{"monthly starting within the window", domain.BudgetMonthly, day(2024, 2, 20), nil, domain.FiscalCalendar{}, day(2024, 1, 1), day(2024, 4, 2), []time.Time{day(2024, 2, 20), day(2024, 3, 1), day(2024, 4, 1)}},
+		{"monthly until an end date", domain.BudgetMonthly, day(2024, 1, 1), &end, domain.FiscalCalendar{}, day(2024, 3, 15), day(2024, 7, 1), []time.Time{day(2024, 4, 1)}},
+		{"fiscal months", domain.BudgetMonthly, day(2024, 1, 1), nil, domain.FiscalCalendar{StartDay: 25}, day(2024, 1, 1), day(2024, 3, 1), []time.Time{day(2024, 1, 1), day(2024, 1, 25), day(2024, 2, 25)}},
+		{"weekly", domain.BudgetWeekly, day(2024, 3, 6), nil, domain.FiscalCalendar{}, day(2024, 3, 1), day(2024, 3, 19), []time.Time{day(2024, 3, 6), day(2024, 3, 11), day(2024, 3, 18)}},
+		{"quarterly", domain.BudgetQuarterly, day(2024, 1, 1), nil, domain.FiscalCalendar{}, day(2024, 2, 1), day(2025, 1, 1), []time.Time{day(2024, 4, 1), day(2024, 7, 1), day(2024, 10, 1)}},
+		{"custom", domain.BudgetCustom, day(2024, 3, 5), &end, domain.FiscalCalendar{}, day(2024, 3, 1), day(2024, 6, 1), []time.Time{day(2024, 3, 5)}},
+		{"ended", domain.BudgetMonthly, day(2024, 1, 1), &end, domain.FiscalCalendar{}, day(2024, 5, 1), day(2024, 8, 1), []time.Time{}},
+		{"not started by the end of the window", domain.BudgetMonthly, day(2024, 6, 1), nil, domain.FiscalCalendar{}, day(2024, 3, 1), day(2024, 6, 1), []time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := newBudget(t, 100, tt.period, tt.start, tt.end, false)
+			if got := budget.PeriodStarts(tt.from, tt.to, tt.calendar); !slices.EqualFunc(got, tt.want, time.Time.Equal) {
+				t.Errorf("PeriodStarts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBudgetPeriodAt(t *testing.T) {
+	end := day(2024, 6, 10)
+	payday := domain.FiscalCalendar{StartDay: 25}
+	tests := []struct {
+		name     string
+		period   domain.BudgetPeriod
+		start    time.Time
+		end      *time.Time
+		calendar domain.FiscalCalendar
+		at       time.Time
+		from, to time.Time
+	}{
+		{"month end", domain.BudgetMonthly, day(2024, 1, 1), nil, domain.FiscalCalendar{}, time.Date(2024, 1, 31, 23, 59, 0, 0, time.UTC), day(2024, 1, 1), day(2024, 2, 1)},
+		{"leap day", domain.BudgetMonthly, day(2024, 1, 1), nil, domain.FiscalCalendar{}, day(2024, 2, 29), day(2024, 2, 1), day(2024, 3, 1)},
+		{"february of a common year", domain.BudgetMonthly, day(2023, 1, 1), nil, domain.FiscalCalendar{}, day(2023, 2, 28), day(2023, 2, 1), day(2023, 3, 1)},
+		{"fiscal month across the leap day", domain.BudgetMonthly, day(2024, 1, 1), nil, payday, day(2024, 2, 29), day(2024, 2, 25), day(2024, 3, 25)},
+		{"fiscal month before the start day", domain.BudgetMonthly, day(2024, 1, 1), nil, payday, day(2024, 3, 24), day(2024, 2, 25), day(2024, 3, 25)},
+		{"weekly on a Sunday", domain.BudgetWeekly, day(2024, 1, 1), nil, domain.FiscalCalendar{}, day(2024, 3, 3), day(2024, 2, 26), day(2024, 3, 4)},
+		{"weekly on a Monday", domain.BudgetWeekly, day(2024, 1, 1), nil, domain.FiscalCalendar{}, day(2024, 3, 4), day(2024, 3, 4), day(2024, 3, 11)},
+		{"weekly across the year", domain.BudgetWeekly, day(2024, 1, 1), nil, domain.FiscalCalendar{}, day(2024, 12, 31), day(2024, 12, 30), day(2025, 1, 6)},
+		{"quarterly", domain.BudgetQuarterly, day(2024, 1, 1), nil, domain.FiscalCalendar{}, day(2024, 5, 31), day(2024, 4, 1), day(2024, 7, 1)},
+		{"yearly in a leap year", domain.BudgetYearly, day(2024, 1, 1), nil, domain.FiscalCalendar{}, day(2024, 2, 29), day(2024, 1, 1), day(2025, 1, 1)},
+		{"custom", domain.BudgetCustom, day(2024, 2, 10), &end, domain.FiscalCalendar{}, day(2024, 3, 1), day(2024, 2, 10), day(2024, 6, 11)},
+		{"before the start", domain.BudgetMonthly, day(2024, 3, 15), nil, domain.FiscalCalendar{}, day(2024, 1, 10), day(2024, 3, 1), day(2024, 4, 1)},
+		{"after the end", domain.BudgetMonthly, day(2024, 1, 1), &end, domain.FiscalCalendar{}, day(2024, 9, 1), day(2024, 6, 1), day(2024, 7, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := newBudget(t, 100, tt.period, tt.start, tt.end, false)
+			from, to := budget.PeriodAt(tt.at, tt.calendar)
+			if !from.Equal(tt.from) || !to.Equal(tt.to) {
+				t.Errorf("PeriodAt = [%s, %s), want [%s, %s)", from.Format(time.DateOnly), to.Format(time.DateOnly), tt.from.Format(time.DateOnly), tt.to.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+func TestBudgetStatus(t *testing.T) {
+	groceries, transport := uuid.New(), uuid.New()
+	spending := []*domain.DailySpending{
+		{Day: day(2024, 1, 31), CategoryId: groceries, Total: 20},
+		{Day: day(2024, 2, 1), CategoryId: groceries, Total: 30},
+		{Day: day(2024, 2, 14), CategoryId: groceries, Total: 10},
+		{Day: day(2024, 2, 15), CategoryId: transport, Total: 15.5},
+		{Day: day(2024, 2, 29), CategoryId: groceries, Total: 40},
+		{Day: day(2024, 3, 1), CategoryId: groceries, Total: 5},
+	}
+	end := day(2024, 2, 14)
+	tests := []struct {
+		name         string
+		period       domain.BudgetPeriod
+		category     uuid.UUID
+		start        time.Time
+		end          *time.Time
+		at           time.Time
+		wantLimit    float64
+		wantProrated bool
+		wantSpent    float64
+		wantOver     bool
+	}{
+		{"whole leap month", domain.BudgetMonthly, groceries, day(2024, 1, 1), nil, day(2024, 2, 10), 100, false, 80, false},
+		{"all categories", domain.BudgetMonthly, uuid.Nil, day(2024, 1, 1), nil, day(2024, 2, 10), 100, false, 95.5, false},
+		// 15 of the 29 days of February 2024
+		{"started mid-month", domain.BudgetMonthly, groceries, day(2024, 2, 15), nil, day(2024, 2, 20), 51.72, true, 40, false},
+		// 14 of the 29 days, and only the spending up to the end counts
+		{"ended mid-month", domain.BudgetMonthly, groceries, day(2024, 1, 1), &end, day(2024, 2, 10), 48.28, true, 40, false},
+		{"month end", domain.BudgetMonthly, groceries, day(2024, 1, 1), nil, day(2024, 1, 31), 100, false, 20, false},
+		// 1 of the 7 days of the week of Monday, February 26
+		{"weekly started on its last day", domain.BudgetWeekly, groceries, day(2024, 3, 3), nil, day(2024, 3, 3), 14.29, true, 0, false},
+		// The week of Monday, February 26 runs into March
+		{"weekly over the leap day", domain.BudgetWeekly, groceries, day(2024, 1, 1), nil, day(2024, 2, 29), 100, false, 45, false},
+		{"quarterly", domain.BudgetQuarterly, groceries, day(2024, 1, 1), nil, day(2024, 3, 31), 100, false, 105, true},
+		{"custom", domain.BudgetCustom, groceries, day(2024, 2, 1), &end, day(2024, 2, 1), 100, false, 40, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget, err := domain.NewBudget("Budget", 100, tt.period, tt.category, tt.start, tt.end, false)
+			if err != nil {
+				t.Fatalf("creating budget: %v", err)
+			}
+			status := budget.Status(spending, tt.at, domain.FiscalCalendar{})
+			if status.Limit != tt.wantLimit || status.Prorated != tt.wantProrated || status.Spent != tt.wantSpent || status.Overspent != tt.wantOver {
+				t.Errorf("status = limit %v (prorated %v), spent %v (overspent %v), want %v (%v), %v (%v)",
+					status.Limit, status.Prorated, status.Spent, status.Overspent, tt.wantLimit, tt.wantProrated, tt.wantSpent, tt.wantOver)
+			}
+			if status.Effective != status.Limit || status.Remaining != round2(status.Limit-status.Spent) {
+				t.Errorf("effective %v and remaining %v, want the limit and what is left of it", status.Effective, status.Remaining)
+			}
+		})
+	}
+}
+
+// round2 rounds to cents as budgets do
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func TestBudgetRollover(t *testing.T) {
+	spending := []*domain.DailySpending{
+		{Day: day(2024, 1, 31), Total: 60},
+		{Day: day(2024, 2, 29), Total: 130},
+		{Day: day(2024, 3, 1), Total: 25},
+	}
+	tests := []struct {
+		name      string
+		period    domain.BudgetPeriod
+		start     time.Time
+		at        time.Time
+		wantFrom  time.Time
+		want      []domain.BudgetRollover
+		carried   float64
+		effective float64
+		spent     float64
+	}{
+		{"first period carries nothing", domain.BudgetMonthly, day(2024, 1, 1), day(2024, 1, 15), day(2024, 1, 1), nil, 0, 100, 60},
+		{"unused amount carries", domain.BudgetMonthly, day(2024, 1, 1), day(2024, 2, 1), day(2024, 2, 1), []domain.BudgetRollover{
+			{From: day(2024, 1, 1), To: day(2024, 2, 1), Limit: 100, Spent: 60, Carried: 40},
+		}, 40, 140, 130},
+		{"deficit carries", domain.BudgetMonthly, day(2024, 1, 1), day(2024, 3, 31), day(2024, 3, 1), []domain.BudgetRollover{
+			{From: day(2024, 1, 1), To: day(2024, 2, 1), Limit: 100, Spent: 60, Carried: 40},
+			{From: day(2024, 2, 1), To: day(2024, 3, 1), Limit: 100, Spent: 130, Carried: 10},
+		}, 10, 110, 25},
+		// 17 of the 31 days of January
+		{"prorated first period", domain.BudgetMonthly, day(2024, 1, 15), day(2024, 2, 29), day(2024, 2, 1), []domain.BudgetRollover{
+			{From: day(2024, 1, 1), To: day(2024, 2, 1), Limit: 54.84, Spent: 60, Carried: -5.16},
+		}, -5.16, 94.84, 130},
+		{"periods with no spending", domain.BudgetMonthly, day(2023, 11, 1), day(2024, 1, 1), day(2024, 1, 1), []domain.BudgetRollover{
+			{From: day(2023, 11, 1), To: day(2023, 12, 1), Limit: 100, Spent: 0, Carried: 100},
+			{From: day(2023, 12, 1), To: day(2024, 1, 1), Limit: 100, Spent: 0, Carried: 200},
+		}, 200, 300, 60},
+		{"weekly across the leap day", domain.BudgetWeekly, day(2024, 2, 19), day(2024, 3, 4), day(2024, 3, 4), []domain.BudgetRollover{
+			{From: day(2024, 2, 19), To: day(2024, 2, 26), Limit: 100, Spent: 0, Carried: 100},
+			{From: day(2024, 2, 26), To: day(2024, 3, 4), Limit: 100, Spent: 155, Carried: 45},
+		}, 45, 145, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := newBudget(t, 100, tt.period, tt.start, nil, true)
+			status := budget.Status(spending, tt.at, domain.FiscalCalendar{})
+			if !status.From.Equal(tt.wantFrom) {
+				t.Errorf("period from %s, want %s", status.From.Format(time.DateOnly), tt.wantFrom.Format(time.DateOnly))
+			}
+			if !slices.EqualFunc(status.History, tt.want, func(a, b domain.BudgetRollover) bool {
+				return a.From.Equal(b.From) && a.To.Equal(b.To) && a.Limit == b.Limit && a.Spent == b.Spent && a.Carried == b.Carried
+			}) {
+				t.Errorf("history = %+v, want %+v", status.History, tt.want)
+			}
+			if status.Carried != tt.carried || status.Effective != tt.effective || status.Spent != tt.spent {
+				t.Errorf("carried %v, effective %v, spent %v, want %v, %v, %v", status.Carried, status.Effective, status.Spent, tt.carried, tt.effective, tt.spent)
+			}
+			if status.Remaining != round2(tt.effective-tt.spent) || status.Overspent != (tt.spent > tt.effective) {
+				t.Errorf("remaining %v (overspent %v), want %v", status.Remaining, status.Overspent, round2(tt.effective-tt.spent))
+			}
+		})
+	}
+
+	t.Run("spending needed", func(t *testing.T) {
+		rollover := newBudget(t, 100, domain.BudgetMonthly, day(2024, 1, 15), nil, true)
+		plain := newBudget(t, 100, domain.BudgetMonthly, day(2024, 1, 15), nil, false)
+		if got := rollover.SpendingFrom(day(2024, 3, 10), domain.FiscalCalendar{}); !got.Equal(day(2024, 1, 1)) {
+			t.Errorf("rollover budget needs spending from %s, want the first period", got.Format(time.DateOnly))
+		}
+		if got := plain.SpendingFrom(day(2024, 3, 10), domain.FiscalCalendar{}); !got.Equal(day(2024, 3, 1)) {
+			t.Errorf("budget needs spending from %s, want its period", got.Format(time.DateOnly))
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrRuleNameEmpty = errors.New("rule name cannot be empty")
+var ErrRuleMatchEmpty = errors.New("rule must match some text, e.g. \"UBER\"")
+var ErrRuleActionEmpty = errors.New("rule must set a category, tags, an account or a merchant")
+
+// CategorizationRule fills in imported entries whose text contains its match, e.g. rows
+// containing "UBER" go to Transportation, tagged rideshare and paid from the credit card.
+// Rules are evaluated in the order they were added
+type CategorizationRule struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	Contains   string    `json:"contains"`             // Matched case-insensitively against the description and raw text
+	CategoryId uuid.UUID `json:"category_id,omitzero"` // Category of matching entries, may be empty
+	Tags       []string  `json:"tags"`                 // Added to matching entries
+	AccountId  uuid.UUID `json:"account_id,omitzero"`  // Account matching entries were paid from, may be empty
+	MerchantId uuid.UUID `json:"merchant_id,omitzero"` // Merchant of matching entries, may be empty
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func NewCategorizationRule(name, contains string, categoryId uuid.UUID, tags []string, accountId, merchantId uuid.UUID) (*CategorizationRule, error) {
+	rule := &CategorizationRule{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+	}
+	if err := rule.Update(name, contains, categoryId, tags, accountId, merchantId); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (r *CategorizationRule) Update(name, contains string, categoryId uuid.UUID, tags []string, accountId, merchantId uuid.UUID) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrRuleNameEmpty
+	}
+	contains = strings.TrimSpace(contains)
+	if contains == "" {
+		return ErrRuleMatchEmpty
+	}
+	tags = NormalizeTags(tags)
+	if categoryId == uuid.Nil && len(tags) == 0 && accountId == uuid.Nil && merchantId == uuid.Nil {
+		return ErrRuleActionEmpty
+	}
+
+	r.Name = name
+	r.Contains = contains
+	r.CategoryId = categoryId
+	r.Tags = tags
+	r.AccountId = accountId
+	r.MerchantId = merchantId
+	return nil
+}
+
+// Matches reports whether any of the texts of an entry contains the rule's match
+func (r *CategorizationRule) Matches(texts ...string) bool {
+	match := strings.ToLower(r.Contains)
+	for _, text := range texts {
+		if strings.Contains(strings.ToLower(text), match) {
+			return true
+		}
+	}
+	return false
+}
+
+// Clone returns a deep copy of the rule
+func (r *CategorizationRule) Clone() *CategorizationRule {
+	clone := *r
+	clone.Tags = slices.Clone(r.Tags)
+	return &clone
+}
+
+// RuleMatch is what the rules matching an entry set. The first matching rule setting a category,
+// account or merchant wins it; tags of all matching rules are added
+type RuleMatch struct {
+	Rules      []*CategorizationRule `json:"rules"` // Matching rules, in evaluation order
+	CategoryId uuid.UUID             `json:"category_id,omitzero"`
+	Tags       []string              `json:"tags"`
+	AccountId  uuid.UUID             `json:"account_id,omitzero"`
+	MerchantId uuid.UUID             `json:"merchant_id,omitzero"`
+}
+
+// MatchRules evaluates the rules, in order, against the texts of an entry
+func MatchRules(rules []*CategorizationRule, texts ...string) *RuleMatch {
+	match := &RuleMatch{Rules: []*CategorizationRule{}}
+	var tags []string
+	for _, rule := range rules {
+		if !rule.Matches(texts...) {
+			continue
+		}
+
+		match.Rules = append(match.Rules, rule)
+		tags = append(tags, rule.Tags...)
+		if match.CategoryId == uuid.Nil {
+			match.CategoryId = rule.CategoryId
+		}
+		if match.AccountId == uuid.Nil {
+			match.AccountId = rule.AccountId
+		}
+		if match.MerchantId == uuid.Nil {
+			match.MerchantId = rule.MerchantId
+		}
+	}
+	match.Tags = NormalizeTags(tags)
+	return match
+}
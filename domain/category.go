@@ -3,15 +3,92 @@ package domain
 import (
 	"errors"
 	"github.com/google/uuid"
+	"sort"
 )
 
 var ErrCategoryColorEmpty = errors.New("category color cannot be empty")
+var ErrCategoryColorNotInPalette = errors.New("category color is not in the palette")
 var ErrCategoryNameEmpty = errors.New("category name cannot be empty")
+var ErrCategoryParentNotFound = errors.New("parent category not found")
+var ErrCategoryCycle = errors.New("category cannot be nested under itself or one of its subcategories")
+var ErrCategoryLimitExceeded = errors.New("amount exceeds the per-transaction limit of the category")
+var ErrInvalidCategoryLimit = errors.New("category limit must not be negative")
+var ErrInvalidCategoryLimitMode = errors.New("category limit mode must be warn or reject")
+var ErrCategoryArchived = errors.New("category is archived")
+var ErrInvalidCategoryIcon = errors.New("unknown category icon")
+var ErrDuplicateCategoryInOrder = errors.New("category listed more than once in order")
+var ErrCategoryMergeIntoSubcategory = errors.New("category cannot be merged into itself or one of its subcategories")
 
 type Category struct {
-	ID    uuid.UUID `json:"id"`   // Unique identifier for the category
-	Name  string    `json:"name"` // Name of the category
-	Color string    `json:"color"`
+	ID         uuid.UUID `json:"id"`   // Unique identifier for the category
+	Name       string    `json:"name"` // Name of the category
+	Color      string    `json:"color"`
+	Deductible bool      `json:"deductible"` // Spending in the category is tax deductible, e.g. business travel
+	ParentID   uuid.UUID `json:"parent_id"`  // Parent category, empty for top-level categories
+	Icon       string    `json:"icon"`       // One of CategoryIcons, may be empty
+	SortOrder  int       `json:"sort_order"` // Position chosen by the user, categories with equal positions sort by name
+	Active     bool      `json:"active"`     // Archived categories are inactive and cannot be used by new expenditures
+
+	TransactionLimit float64           `json:"transaction_limit"` // Cap on single expenditures, 0 for none
+	LimitMode        CategoryLimitMode `json:"limit_mode"`        // What happens to expenditures above the cap
+}
+
+// CategoryLimitMode decides how expenditures above a category's transaction limit are handled
+type CategoryLimitMode string
+
+const (
+	CategoryLimitWarn   CategoryLimitMode = "warn"   // Accept the expenditure but flag it
+	CategoryLimitReject CategoryLimitMode = "reject" // Refuse the expenditure
+)
+
+// UncategorizedCategoryName is the default name of the category given to expenditures
+// created without one
+const UncategorizedCategoryName = "Uncategorized"
+
+// DefaultCategories are the names and colors of the categories every storage starts with
+var DefaultCategories = map[string]string{
+	"Food & Dining":      "#FF6B6B",
+	"Transportation":     "#4ECDC4",
+	"Housing":            "#1A535C",
+	"Utilities":          "#FFE66D",
+	"Health & Fitness":   "#2EC4B6",
+	"Entertainment":      "#FF9F1C",
+	"Shopping":           "#C084FC",
+	"Travel":             "#00A8E8",
+	"Education":          "#6D6875",
+	"Financial Services": "#5D2E8C",
+	"Personal Care":      "#FFB6B9",
+	"Gifts & Donations":  "#FF7E67",
+	"Miscellaneous":      "#A0AEC0",
+	"Uncategorized":      "#CBD5E0",
+}
+
+// DefaultCategoryIcons are the icons of the default categories
+var DefaultCategoryIcons = map[string]string{
+	"Food & Dining":      "utensils",
+	"Transportation":     "car",
+	"Housing":            "home",
+	"Utilities":          "bolt",
+	"Health & Fitness":   "heart",
+	"Entertainment":      "film",
+	"Shopping":           "shopping-bag",
+	"Travel":             "plane",
+	"Education":          "book",
+	"Financial Services": "bank",
+	"Personal Care":      "scissors",
+	"Gifts & Donations":  "gift",
+	"Miscellaneous":      "tag",
+	"Uncategorized":      "tag",
+}
+
+// CategoryIcons are the icon names clients know how to render
+var CategoryIcons = map[string]bool{
+	"bank": true, "bicycle": true, "bolt": true, "book": true, "briefcase": true, "bus": true,
+	"car": true, "coffee": true, "film": true, "fuel": true, "gift": true, "graduation-cap": true,
+	"heart": true, "home": true, "laptop": true, "medkit": true, "music": true, "paw": true,
+	"phone": true, "piggy-bank": true, "plane": true, "receipt": true, "scissors": true,
+	"shopping-bag": true, "shopping-cart": true, "tag": true, "train": true, "tshirt": true,
+	"utensils": true, "wifi": true,
 }
 
 func NewCategory(name string, color string) (*Category, error) {
@@ -24,9 +101,10 @@ func NewCategory(name string, color string) (*Category, error) {
 	}
 
 	return &Category{
-		ID:    uuid.New(),
-		Name:  name,
-		Color: color,
+		ID:     uuid.New(),
+		Name:   name,
+		Color:  color,
+		Active: true,
 	}, nil
 }
 
@@ -44,3 +122,225 @@ func (c *Category) Update(name string, color string) error {
 
 	return nil
 }
+
+// SetIcon sets the icon of the category; an empty name removes it
+func (c *Category) SetIcon(icon string) error {
+	if icon != "" && !CategoryIcons[icon] {
+		return ErrInvalidCategoryIcon
+	}
+	c.Icon = icon
+	return nil
+}
+
+// SetLimit sets the per-transaction limit of the category; a limit of 0 removes it and an
+// empty mode defaults to warn
+func (c *Category) SetLimit(limit float64, mode CategoryLimitMode) error {
+	if limit < 0 {
+		return ErrInvalidCategoryLimit
+	}
+	if mode == "" {
+		mode = CategoryLimitWarn
+	}
+	if mode != CategoryLimitWarn && mode != CategoryLimitReject {
+		return ErrInvalidCategoryLimitMode
+	}
+
+	if limit == 0 {
+		mode = ""
+	}
+	c.TransactionLimit = limit
+	c.LimitMode = mode
+	return nil
+}
+
+// ExceedsLimit reports whether a single expenditure of amount is above the category's limit
+func (c *Category) ExceedsLimit(amount float64) bool {
+	return c.TransactionLimit > 0 && amount > c.TransactionLimit
+}
+
+// ActiveCategories returns the categories that are not archived
+func ActiveCategories(categories []*Category) []*Category {
+	active := make([]*Category, 0, len(categories))
+	for _, category := range categories {
+		if category.Active {
+			active = append(active, category)
+		}
+	}
+	return active
+}
+
+// SortCategories orders categories by their sort order, then by name
+func SortCategories(categories []*Category) {
+	sort.SliceStable(categories, func(i, j int) bool {
+		if categories[i].SortOrder != categories[j].SortOrder {
+			return categories[i].SortOrder < categories[j].SortOrder
+		}
+		return categories[i].Name < categories[j].Name
+	})
+}
+
+// OrderCategories renumbers the sort order of categories: those listed in ids come first in
+// that order, the others follow in their current order
+func OrderCategories(categories []*Category, ids []uuid.UUID) error {
+	byID := make(map[uuid.UUID]*Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	listed := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if _, ok := byID[id]; !ok {
+			return ErrCategoryNotFound
+		}
+		if listed[id] {
+			return ErrDuplicateCategoryInOrder
+		}
+		listed[id] = true
+	}
+
+	rest := make([]*Category, 0, len(categories)-len(ids))
+	for _, category := range categories {
+		if !listed[category.ID] {
+			rest = append(rest, category)
+		}
+	}
+	SortCategories(rest)
+
+	for i, id := range ids {
+		byID[id].SortOrder = i + 1
+	}
+	for i, category := range rest {
+		category.SortOrder = len(ids) + i + 1
+	}
+	return nil
+}
+
+// CheckCategoryParent validates nesting the category id under parentID: the parent must exist
+// and must not be the category itself or one of its subcategories
+func CheckCategoryParent(categories []*Category, id, parentID uuid.UUID) error {
+	if parentID == uuid.Nil {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	if _, ok := byID[parentID]; !ok {
+		return ErrCategoryParentNotFound
+	}
+
+	// Walk up from the new parent; the step limit guards against cycles already stored
+	current := parentID
+	for steps := 0; current != uuid.Nil && steps <= len(categories); steps++ {
+		if current == id {
+			return ErrCategoryCycle
+		}
+		parent, ok := byID[current]
+		if !ok {
+			break
+		}
+		current = parent.ParentID
+	}
+	return nil
+}
+
+// CategoryAncestors maps every category to itself followed by its ancestors, nearest first, so
+// amounts can be rolled up from subcategories into their parents
+func CategoryAncestors(categories []*Category) map[uuid.UUID][]uuid.UUID {
+	byID := make(map[uuid.UUID]*Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	ancestors := make(map[uuid.UUID][]uuid.UUID, len(categories))
+	for _, category := range categories {
+		lineage := []uuid.UUID{category.ID}
+		seen := map[uuid.UUID]bool{category.ID: true}
+		for parent, ok := byID[category.ParentID]; ok && !seen[parent.ID]; parent, ok = byID[parent.ParentID] {
+			lineage = append(lineage, parent.ID)
+			seen[parent.ID] = true
+		}
+		ancestors[category.ID] = lineage
+	}
+	return ancestors
+}
+
+// CategoryNode is a category with its subcategories
+type CategoryNode struct {
+	*Category
+	Children []*CategoryNode `json:"children"`
+}
+
+// CategoryTree nests the categories under their parents, ordered by sort order and name on every level.
+// Categories whose parent is missing are placed at the top level
+func CategoryTree(categories []*Category) []*CategoryNode {
+	nodes := make(map[uuid.UUID]*CategoryNode, len(categories))
+	for _, category := range categories {
+		nodes[category.ID] = &CategoryNode{Category: category, Children: []*CategoryNode{}}
+	}
+
+	roots := make([]*CategoryNode, 0)
+	for _, category := range categories {
+		node := nodes[category.ID]
+		parent, ok := nodes[category.ParentID]
+		if ok && CheckCategoryParent(categories, category.ID, category.ParentID) == nil {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	var sortNodes func([]*CategoryNode)
+	sortNodes = func(level []*CategoryNode) {
+		sort.Slice(level, func(i, j int) bool {
+			if level[i].SortOrder != level[j].SortOrder {
+				return level[i].SortOrder < level[j].SortOrder
+			}
+			return level[i].Name < level[j].Name
+		})
+		for _, node := range level {
+			sortNodes(node.Children)
+		}
+	}
+	sortNodes(roots)
+
+	return roots
+}
+
+// CategoryMerge reports the records moved, or that would be moved by a dry run, when a category
+// is merged into another one
+type CategoryMerge struct {
+	SourceID           uuid.UUID `json:"source_id"`
+	TargetID           uuid.UUID `json:"target_id"`
+	DryRun             bool      `json:"dry_run"`
+	Expenditures       int       `json:"expenditures"`
+	Goals              int       `json:"goals"`
+	Budgets            int       `json:"budgets"`
+	Recurring          int       `json:"recurring"`           // Recurring expenditures
+	Installments       int       `json:"installments"`        // Installment purchases
+	Envelopes          int       `json:"envelopes"`           // Envelope allocations
+	Merchants          int       `json:"merchants"`           // Merchants using the category as default
+	StagedExpenditures int       `json:"staged_expenditures"` // Entries of the import review queue
+	Drafts             int       `json:"drafts"`
+	Views              int       `json:"views"`         // Saved views filtering by the category
+	Subcategories      int       `json:"subcategories"` // Children moved under the target
+}
+
+// CheckCategoryMerge validates merging the category source into target, which must exist and
+// must not be source or one of its subcategories
+func CheckCategoryMerge(categories []*Category, source, target uuid.UUID) error {
+	if source == target {
+		return ErrCategoryMergeIntoSubcategory
+	}
+
+	err := CheckCategoryParent(categories, source, target)
+	if err == ErrCategoryParentNotFound {
+		return ErrCategoryNotFound
+	}
+	if err == ErrCategoryCycle {
+		return ErrCategoryMergeIntoSubcategory
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+package domain_test
+
+import (
+	"go-expense-tracker/domain"
+	"go-expense-tracker/storagetest"
+	"testing"
+)
+
+// TestCloneSharesNoMemory fills every field, so a field added later without being copied by
+// Clone fails the test
+func TestCloneSharesNoMemory(t *testing.T) {
+	tests := []struct {
+		name  string
+		clone func() (any, any)
+	}{
+		{"Expenditure", func() (any, any) { v := &domain.Expenditure{}; storagetest.Populate(v); return v, v.Clone() }},
+		{"Account", func() (any, any) { v := &domain.Account{}; storagetest.Populate(v); return v, v.Clone() }},
+		{"Archive", func() (any, any) { v := &domain.Archive{}; storagetest.Populate(v); return v, v.Clone() }},
+		{"BankConnection", func() (any, any) { v := &domain.BankConnection{}; storagetest.Populate(v); return v, v.Clone() }},
+		{"Budget", func() (any, any) { v := &domain.Budget{}; storagetest.Populate(v); return v, v.Clone() }},
+		{"Draft", func() (any, any) { v := &domain.Draft{}; storagetest.Populate(v); return v, v.Clone() }},
+		{"ExpenditureEvent", func() (any, any) { v := &domain.ExpenditureEvent{}; storagetest.Populate(v); return v, v.Clone() }},
+		{"ExpenseReport", func() (any, any) { v := &domain.ExpenseReport{}; storagetest.Populate(v); return v, v.Clone() }},
+		{"Household", func() (any, any) { v := &domain.Household{}; storagetest.Populate(v); return v, v.Clone() }},
+		{"InstallmentPurchase", func() (any, any) { v := &domain.InstallmentPurchase{}; storagetest.Populate(v); return v, v.Clone() }},
+		{"Invitation", func() (any, any) { v := &domain.Invitation{}; storagetest.Populate(v); return v, v.Clone() }},
+		{"CategorizationRule", func() (any, any) { v := &domain.CategorizationRule{}; storagetest.Populate(v); return v, v.Clone() }},
+		{"Merchant", func() (any, any) { v := &domain.Merchant{}; storagetest.Populate(v); return v, v.Clone() }},
+		{"OutboxMessage", func() (any, any) { v := &domain.OutboxMessage{}; storagetest.Populate(v); return v, v.Clone() }},
+		{"RecurringExpenditure", func() (any, any) { v := &domain.RecurringExpenditure{}; storagetest.Populate(v); return v, v.Clone() }},
+		{"RetentionPurge", func() (any, any) { v := &domain.RetentionPurge{}; storagetest.Populate(v); return v, v.Clone() }},
+		{"ReportSnapshot", func() (any, any) { v := &domain.ReportSnapshot{}; storagetest.Populate(v); return v, v.Clone() }},
+		{"View", func() (any, any) { v := &domain.View{}; storagetest.Populate(v); return v, v.Clone() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original, clone := tt.clone()
+			if path, shared := storagetest.SharesMemory(original, clone); shared {
+				t.Errorf("clone shares %s with the original", path)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var ErrDraftNotFound = errors.New("draft not found")
+var ErrDraftAlreadyExists = errors.New("draft already exists")
+var ErrDraftEmpty = errors.New("draft needs at least a description or an amount")
+
+// Draft is a partially filled expenditure saved for later, e.g. when the amount is known but the
+// category is not. Its fields are only validated when it is published as a real Expenditure, and
+// drafts are kept apart from expenditures so reports and budgets never include them
+type Draft struct {
+	Expenditure
+	CreatedAt time.Time `json:"created_at"` // When the draft was saved
+}
+
+// NewDraft saves the fields of an expenditure as a draft under a new ID; the date defaults to now
+func NewDraft(fields Expenditure) (*Draft, error) {
+	if fields.Description == "" && fields.Amount == 0 {
+		return nil, ErrDraftEmpty
+	}
+	if fields.Amount < 0 {
+		return nil, ErrInvalidExpenditureAmount
+	}
+
+	fields.ID = uuid.New()
+	if fields.Date.IsZero() {
+		fields.Date = time.Now()
+	}
+	if fields.Tags == nil {
+		fields.Tags = []string{}
+	}
+
+	return &Draft{Expenditure: fields, CreatedAt: time.Now()}, nil
+}
+
+// Clone returns a deep copy of the draft that shares no tags or location with it
+func (d *Draft) Clone() *Draft {
+	return &Draft{Expenditure: *d.Expenditure.Clone(), CreatedAt: d.CreatedAt}
+}
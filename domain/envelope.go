@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"sort"
+	"time"
+)
+
+var ErrNoEnvelopeAllocations = errors.New("allocate an amount to at least one envelope")
+var ErrInvalidEnvelopeAmount = errors.New("envelope amounts must be positive")
+var ErrEnvelopeCategoryIdEmpty = errors.New("envelope category ID cannot be empty")
+var ErrAllocationsExceedIncome = errors.New("allocations exceed the incoming amount")
+var ErrEnvelopeMoveToSelf = errors.New("money must move between two different envelopes")
+var ErrInsufficientEnvelopeBalance = errors.New("envelope balance is too low")
+var ErrInvalidEnvelopeAdjustment = errors.New("envelope adjustments must not be zero")
+
+// EnvelopeAllocation is money put into, or taken out of, the envelope of a category. Moves
+// between envelopes are a pair of allocations sharing a transfer ID
+type EnvelopeAllocation struct {
+	ID         uuid.UUID `json:"id"`
+	CategoryId uuid.UUID `json:"category_id"`
+	Amount     float64   `json:"amount"` // Negative when money is taken out
+	Note       string    `json:"note,omitempty"`
+	TransferID uuid.UUID `json:"transfer_id"` // Shared by the allocations of one allocation or move
+	At         time.Time `json:"at"`
+}
+
+// EnvelopeBalance is the money left in the envelope of a category
+type EnvelopeBalance struct {
+	CategoryId uuid.UUID `json:"category_id"`
+	OpenedAt   time.Time `json:"opened_at"` // First allocation; spending from this day on counts
+	Allocated  float64   `json:"allocated"` // Net amount put in, moves included
+	Spent      float64   `json:"spent"`
+	Balance    float64   `json:"balance"` // Negative when overspent
+}
+
+// NewEnvelopeAllocations splits incoming money across envelopes. When income is positive the
+// allocations must not exceed it
+func NewEnvelopeAllocations(income float64, amounts map[uuid.UUID]float64, note string) ([]*EnvelopeAllocation, error) {
+	if len(amounts) == 0 {
+		return nil, ErrNoEnvelopeAllocations
+	}
+
+	transferID, now := uuid.New(), time.Now()
+	var total float64
+	allocations := make([]*EnvelopeAllocation, 0, len(amounts))
+	for categoryId, amount := range amounts {
+		if categoryId == uuid.Nil {
+			return nil, ErrEnvelopeCategoryIdEmpty
+		}
+		if amount <= 0 {
+			return nil, ErrInvalidEnvelopeAmount
+		}
+		total += amount
+		allocations = append(allocations, &EnvelopeAllocation{
+			ID:         uuid.New(),
+			CategoryId: categoryId,
+			Amount:     amount,
+			Note:       note,
+			TransferID: transferID,
+			At:         now,
+		})
+	}
+
+	if income > 0 && round2(total) > round2(income) {
+		return nil, ErrAllocationsExceedIncome
+	}
+
+	sort.Slice(allocations, func(i, j int) bool {
+		return allocations[i].CategoryId.String() < allocations[j].CategoryId.String()
+	})
+	return allocations, nil
+}
+
+// NewEnvelopeMove moves money from the envelope of one category to another
+func NewEnvelopeMove(from, to uuid.UUID, amount float64, note string) ([]*EnvelopeAllocation, error) {
+	if from == uuid.Nil || to == uuid.Nil {
+		return nil, ErrEnvelopeCategoryIdEmpty
+	}
+	if from == to {
+		return nil, ErrEnvelopeMoveToSelf
+	}
+	if amount <= 0 {
+		return nil, ErrInvalidEnvelopeAmount
+	}
+
+	transferID, now := uuid.New(), time.Now()
+	return []*EnvelopeAllocation{
+		{ID: uuid.New(), CategoryId: from, Amount: -amount, Note: note, TransferID: transferID, At: now},
+		{ID: uuid.New(), CategoryId: to, Amount: amount, Note: note, TransferID: transferID, At: now},
+	}, nil
+}
+
+// NewEnvelopeAdjustment puts money into the envelope of a category, or takes it out when amount is
+// negative, such as when topping up or correcting an allowance
+func NewEnvelopeAdjustment(categoryId uuid.UUID, amount float64, note string) (*EnvelopeAllocation, error) {
+	if categoryId == uuid.Nil {
+		return nil, ErrEnvelopeCategoryIdEmpty
+	}
+	if round2(amount) == 0 {
+		return nil, ErrInvalidEnvelopeAdjustment
+	}
+
+	return &EnvelopeAllocation{
+		ID:         uuid.New(),
+		CategoryId: categoryId,
+		Amount:     amount,
+		Note:       note,
+		TransferID: uuid.New(),
+		At:         time.Now(),
+	}, nil
+}
+
+// EnvelopesOpenedAt returns the day the first envelope was opened, the earliest day whose
+// spending the balances need, or the zero time without allocations
+func EnvelopesOpenedAt(allocations []*EnvelopeAllocation) time.Time {
+	var opened time.Time
+	for _, allocation := range allocations {
+		if opened.IsZero() || allocation.At.Before(opened) {
+			opened = allocation.At
+		}
+	}
+	if opened.IsZero() {
+		return opened
+	}
+	return SpendingDay(opened)
+}
+
+// EnvelopeBalances computes the balance of every envelope from its allocations and the spending in
+// its category since the envelope was opened, ordered by category
+func EnvelopeBalances(allocations []*EnvelopeAllocation, spending []*DailySpending) []*EnvelopeBalance {
+	byCategory := make(map[uuid.UUID]*EnvelopeBalance)
+	var balances []*EnvelopeBalance
+	for _, allocation := range allocations {
+		balance, ok := byCategory[allocation.CategoryId]
+		if !ok {
+			balance = &EnvelopeBalance{CategoryId: allocation.CategoryId, OpenedAt: allocation.At}
+			byCategory[allocation.CategoryId] = balance
+			balances = append(balances, balance)
+		}
+		if allocation.At.Before(balance.OpenedAt) {
+			balance.OpenedAt = allocation.At
+		}
+		balance.Allocated += allocation.Amount
+	}
+
+	for _, day := range spending {
+		balance, ok := byCategory[day.CategoryId]
+		if ok && !day.Day.Before(SpendingDay(balance.OpenedAt)) {
+			balance.Spent += day.Total
+		}
+	}
+
+	for _, balance := range balances {
+		balance.Allocated = round2(balance.Allocated)
+		balance.Spent = round2(balance.Spent)
+		balance.Balance = round2(balance.Allocated - balance.Spent)
+	}
+
+	sort.Slice(balances, func(i, j int) bool {
+		return balances[i].CategoryId.String() < balances[j].CategoryId.String()
+	})
+	return balances
+}
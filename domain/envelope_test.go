@@ -0,0 +1,181 @@
+package domain_test
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewEnvelopeAllocations(t *testing.T) {
+	groceries, rent := uuid.New(), uuid.New()
+	tests := []struct {
+		name    string
+		income  float64
+		amounts map[uuid.UUID]float64
+		wantErr error
+	}{
+		{"split", 1000, map[uuid.UUID]float64{groceries: 300, rent: 700}, nil},
+		{"less than the income", 1000, map[uuid.UUID]float64{groceries: 300}, nil},
+		{"rounded to cents", 0.3, map[uuid.UUID]float64{groceries: 0.1, rent: 0.2}, nil},
+		{"no income to cap", 0, map[uuid.UUID]float64{groceries: 5000}, nil},
+		{"more than the income", 1000, map[uuid.UUID]float64{groceries: 300, rent: 700.01}, domain.ErrAllocationsExceedIncome},
+		{"nothing", 1000, nil, domain.ErrNoEnvelopeAllocations},
+		{"no category", 1000, map[uuid.UUID]float64{uuid.Nil: 300}, domain.ErrEnvelopeCategoryIdEmpty},
+		{"zero", 1000, map[uuid.UUID]float64{groceries: 0}, domain.ErrInvalidEnvelopeAmount},
+		{"negative", 1000, map[uuid.UUID]float64{groceries: -5}, domain.ErrInvalidEnvelopeAmount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allocations, err := domain.NewEnvelopeAllocations(tt.income, tt.amounts, "Payday")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(allocations) != len(tt.amounts) {
+				t.Fatalf("%d allocations, want %d", len(allocations), len(tt.amounts))
+			}
+			for _, allocation := range allocations {
+				if allocation.Amount != tt.amounts[allocation.CategoryId] || allocation.TransferID != allocations[0].TransferID || allocation.Note != "Payday" {
+					t.Errorf("allocation %+v, want %v into its envelope in one transfer", allocation, tt.amounts[allocation.CategoryId])
+				}
+			}
+		})
+	}
+}
+
+func TestNewEnvelopeMove(t *testing.T) {
+	groceries, rent := uuid.New(), uuid.New()
+	tests := []struct {
+		name     string
+		from, to uuid.UUID
+		amount   float64
+		wantErr  error
+	}{
+		{"move", groceries, rent, 50, nil},
+		{"to itself", groceries, groceries, 50, domain.ErrEnvelopeMoveToSelf},
+		{"from no category", uuid.Nil, rent, 50, domain.ErrEnvelopeCategoryIdEmpty},
+		{"to no category", groceries, uuid.Nil, 50, domain.ErrEnvelopeCategoryIdEmpty},
+		{"nothing", groceries, rent, 0, domain.ErrInvalidEnvelopeAmount},
+		{"backwards", groceries, rent, -50, domain.ErrInvalidEnvelopeAmount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			move, err := domain.NewEnvelopeMove(tt.from, tt.to, tt.amount, "")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(move) != 2 || move[0].CategoryId != tt.from || move[0].Amount != -tt.amount ||
+				move[1].CategoryId != tt.to || move[1].Amount != tt.amount || move[0].TransferID != move[1].TransferID {
+				t.Errorf("move = %+v %+v, want %v out of one envelope and into the other", move[0], move[1], tt.amount)
+			}
+		})
+	}
+}
+
+func TestNewEnvelopeAdjustment(t *testing.T) {
+	tests := []struct {
+		name     string
+		category uuid.UUID
+		amount   float64
+		wantErr  error
+	}{
+		{"top up", uuid.New(), 20, nil},
+		{"take out", uuid.New(), -5, nil},
+		{"nothing", uuid.New(), 0, domain.ErrInvalidEnvelopeAdjustment},
+		{"less than a cent", uuid.New(), 0.004, domain.ErrInvalidEnvelopeAdjustment},
+		{"no category", uuid.Nil, 20, domain.ErrEnvelopeCategoryIdEmpty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adjustment, err := domain.NewEnvelopeAdjustment(tt.category, tt.amount, "")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (adjustment.Amount != tt.amount || adjustment.CategoryId != tt.category) {
+				t.Errorf("adjustment = %+v, want %v", adjustment, tt.amount)
+			}
+		})
+	}
+}
+
+func TestEnvelopeBalances(t *testing.T) {
+	groceries, rent, other := uuid.New(), uuid.New(), uuid.New()
+	allocation := func(category uuid.UUID, amount float64, at time.Time) *domain.EnvelopeAllocation {
+		return &domain.EnvelopeAllocation{ID: uuid.New(), CategoryId: category, Amount: amount, TransferID: uuid.New(), At: at}
+	}
+	// Envelopes are opened at some time of their first day, whose spending counts in full
+	opened := time.Date(2024, 2, 29, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		allocations []*domain.EnvelopeAllocation
+		spending    []*domain.DailySpending
+		want        map[uuid.UUID]domain.EnvelopeBalance
+	}{
+		{"no allocations", nil, []*domain.DailySpending{{Day: day(2024, 3, 1), CategoryId: groceries, Total: 10}}, map[uuid.UUID]domain.EnvelopeBalance{}},
+		{"spending since opened", []*domain.EnvelopeAllocation{allocation(groceries, 300, opened)}, []*domain.DailySpending{
+			{Day: day(2024, 2, 28), CategoryId: groceries, Total: 99},
+			{Day: day(2024, 2, 29), CategoryId: groceries, Total: 10},
+			{Day: day(2024, 3, 1), CategoryId: groceries, Total: 20.25},
+			{Day: day(2024, 3, 1), CategoryId: other, Total: 500},
+		}, map[uuid.UUID]domain.EnvelopeBalance{groceries: {Allocated: 300, Spent: 30.25, Balance: 269.75}}},
+		{"opened by the earliest allocation", []*domain.EnvelopeAllocation{
+			allocation(groceries, 100, day(2024, 3, 5)),
+			allocation(groceries, 100, opened),
+		}, []*domain.DailySpending{{Day: day(2024, 3, 1), CategoryId: groceries, Total: 50}},
+			map[uuid.UUID]domain.EnvelopeBalance{groceries: {Allocated: 200, Spent: 50, Balance: 150}}},
+		{"moved and overspent", []*domain.EnvelopeAllocation{
+			allocation(groceries, 100, opened),
+			allocation(rent, 700, opened),
+			allocation(groceries, -60, opened.AddDate(0, 0, 1)),
+			allocation(rent, 60, opened.AddDate(0, 0, 1)),
+		}, []*domain.DailySpending{
+			{Day: day(2024, 3, 1), CategoryId: groceries, Total: 45.1},
+			{Day: day(2024, 3, 1), CategoryId: rent, Total: 750},
+		}, map[uuid.UUID]domain.EnvelopeBalance{
+			groceries: {Allocated: 40, Spent: 45.1, Balance: -5.1},
+			rent:      {Allocated: 760, Spent: 750, Balance: 10},
+		}},
+		{"rounded to cents", []*domain.EnvelopeAllocation{
+			allocation(groceries, 0.1, opened),
+			allocation(groceries, 0.2, opened),
+		}, []*domain.DailySpending{{Day: day(2024, 3, 1), CategoryId: groceries, Total: 0.3}},
+			map[uuid.UUID]domain.EnvelopeBalance{groceries: {Allocated: 0.3, Spent: 0.3, Balance: 0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balances := domain.EnvelopeBalances(tt.allocations, tt.spending)
+			if len(balances) != len(tt.want) {
+				t.Fatalf("%d balances, want %d", len(balances), len(tt.want))
+			}
+			for i, balance := range balances {
+				if i > 0 && balances[i-1].CategoryId.String() > balance.CategoryId.String() {
+					t.Errorf("balances are not ordered by category")
+				}
+				want := tt.want[balance.CategoryId]
+				if balance.Allocated != want.Allocated || balance.Spent != want.Spent || balance.Balance != want.Balance {
+					t.Errorf("balance = allocated %v, spent %v, balance %v, want %v, %v, %v",
+						balance.Allocated, balance.Spent, balance.Balance, want.Allocated, want.Spent, want.Balance)
+				}
+				if !balance.OpenedAt.Equal(opened) {
+					t.Errorf("opened at %s, want %s", balance.OpenedAt, opened)
+				}
+			}
+			if opened := domain.EnvelopesOpenedAt(tt.allocations); len(tt.allocations) > 0 && !opened.Equal(day(2024, 2, 29)) {
+				t.Errorf("envelopes opened at %s, want the day of the first allocation", opened)
+			}
+		})
+	}
+}
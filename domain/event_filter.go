@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidEventFilter = errors.New("event filters are category, amount_above and created_by")
+
+// EventFilter selects the changes to expenditures a subscriber receives, so clients don't have
+// to receive and discard the others. The zero filter matches every change
+type EventFilter struct {
+	CategoryIds []uuid.UUID `json:"category_ids,omitempty"` // Only expenditures in one of these categories
+	AmountAbove float64     `json:"amount_above,omitempty"` // Only expenditures of more than this amount
+	CreatedBy   uuid.UUID   `json:"created_by,omitzero"`    // Only expenditures recorded by this household member
+}
+
+// ParseEventFilter reads a filter from query parameters: `category`, repeated or comma-separated,
+// `amount_above` and `created_by`. Other parameters are ignored, so the filter can share a query
+func ParseEventFilter(query url.Values) (EventFilter, error) {
+	var filter EventFilter
+
+	for _, value := range query["category"] {
+		for _, part := range strings.Split(value, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(part))
+			if err != nil {
+				return EventFilter{}, ErrInvalidEventFilter
+			}
+			filter.CategoryIds = append(filter.CategoryIds, id)
+		}
+	}
+
+	if value := query.Get("amount_above"); value != "" {
+		amount, err := strconv.ParseFloat(value, 64)
+		if err != nil || amount < 0 {
+			return EventFilter{}, ErrInvalidEventFilter
+		}
+		filter.AmountAbove = amount
+	}
+
+	if value := query.Get("created_by"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return EventFilter{}, ErrInvalidEventFilter
+		}
+		filter.CreatedBy = id
+	}
+
+	return filter, nil
+}
+
+// IsZero reports whether the filter matches every change
+func (f EventFilter) IsZero() bool {
+	return len(f.CategoryIds) == 0 && f.AmountAbove == 0 && f.CreatedBy == uuid.Nil
+}
+
+// Matches reports whether a change to the expenditure passes the filter; expenditure is its state
+// after the change, or before it for deletions. Only the zero filter matches a nil expenditure
+func (f EventFilter) Matches(expenditure *Expenditure) bool {
+	if f.IsZero() {
+		return true
+	}
+	if expenditure == nil {
+		return false
+	}
+
+	if len(f.CategoryIds) > 0 && !slices.Contains(f.CategoryIds, expenditure.CategoryId) {
+		return false
+	}
+	if f.AmountAbove > 0 && expenditure.Amount <= f.AmountAbove {
+		return false
+	}
+	if f.CreatedBy != uuid.Nil && expenditure.CreatedBy != f.CreatedBy {
+		return false
+	}
+	return true
+}
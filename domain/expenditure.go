@@ -3,6 +3,9 @@ package domain
 import (
 	"errors"
 	"github.com/google/uuid"
+	"math"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -10,17 +13,43 @@ var ErrInvalidExpenditureAmount = errors.New("invalid expenditure amount")
 var ErrExpenditureDescriptionEmpty = errors.New("expenditure description cannot be empty")
 var ErrExpenditureFutureDate = errors.New("expenditure date cannot be in the future")
 var ErrExpenditureCategoryIdEmpty = errors.New("expenditure category ID cannot be empty")
+var ErrInvalidExpenditureQuantity = errors.New("invalid expenditure quantity")
+var ErrInvalidExpenditureUnitPrice = errors.New("invalid expenditure unit price")
+var ErrExpenditureUnitEmpty = errors.New("expenditure unit cannot be empty when a quantity is given")
+var ErrExpenditureAmountMismatch = errors.New("expenditure amount does not match quantity times unit price")
 
 // Expenditure represents a money expenditure by a person
 type Expenditure struct {
-	ID          uuid.UUID `json:"id"`          // Unique identifier for the expenditure
-	Description string    `json:"description"` // Description of what the money was spent on
-	Amount      float64   `json:"amount"`      // Amount of money spent
-	Date        time.Time `json:"date"`        // Date when the expenditure occurred
-	CategoryId  uuid.UUID `json:"category_id"` // ID of the category to which the expenditure belongs
+	ID               uuid.UUID         `json:"id"`                          // Unique identifier for the expenditure
+	Description      string            `json:"description"`                 // Description of what the money was spent on
+	Amount           float64           `json:"amount"`                      // Amount of money spent
+	Date             time.Time         `json:"date"`                        // Date when the expenditure occurred
+	CategoryId       uuid.UUID         `json:"category_id"`                 // ID of the category to which the expenditure belongs
+	Tags             []string          `json:"tags"`                        // Free-form labels such as "work" or "rideshare"
+	Quantity         float64           `json:"quantity,omitempty"`          // Number of units for per-unit expenses, e.g. 340 (km)
+	UnitPrice        float64           `json:"unit_price,omitempty"`        // Price per unit, e.g. 0.30
+	Unit             string            `json:"unit,omitempty"`              // Unit of the quantity, e.g. "km"
+	TaxRate          float64           `json:"tax_rate,omitempty"`          // VAT/sales tax rate in percent, e.g. 19
+	TaxAmount        float64           `json:"tax_amount,omitempty"`        // Tax included in the amount
+	MerchantId       uuid.UUID         `json:"merchant_id"`                 // Merchant the money was spent at, may be empty
+	Location         *Location         `json:"location,omitempty"`          // Where the money was spent, may be nil
+	Planned          bool              `json:"planned,omitempty"`           // Scheduled payment dated in the future, see IsPlanned
+	RefundOf         uuid.UUID         `json:"refund_of,omitzero"`          // Expenditure this refund credits back, see NewRefund
+	Status           ExpenditureStatus `json:"status"`                      // Pending until the transaction settles, see Clear
+	AccountId        uuid.UUID         `json:"account_id,omitzero"`         // Account the money was paid from, may be empty
+	Reconciled       bool              `json:"reconciled,omitempty"`        // Matched against a statement and locked, see Unlock
+	OriginalAmount   float64           `json:"original_amount,omitempty"`   // Amount paid abroad in the original currency, see SetOriginal
+	OriginalCurrency string            `json:"original_currency,omitempty"` // ISO 4217 code of the original amount, empty in the home currency
+	CreatedBy        uuid.UUID         `json:"created_by,omitzero"`         // Household member who recorded the expenditure, may be empty
 }
 
 func NewExpenditure(description string, amount float64, date time.Time, categoryId uuid.UUID) (*Expenditure, error) {
+	return NewExpenditureAt(description, amount, date, categoryId, RejectFutureDates, time.Now())
+}
+
+// NewExpenditureAt is NewExpenditure with the future date policy applied at now instead of the
+// default rejecting all future dates
+func NewExpenditureAt(description string, amount float64, date time.Time, categoryId uuid.UUID, policy FutureDatePolicy, now time.Time) (*Expenditure, error) {
 
 	if description == "" {
 		return nil, ErrExpenditureDescriptionEmpty
@@ -31,8 +60,9 @@ func NewExpenditure(description string, amount float64, date time.Time, category
 	}
 
 	// Check if the date is in the future
-	if date.After(time.Now()) {
-		return nil, ErrExpenditureFutureDate
+	planned, err := policy.Check(date, now)
+	if err != nil {
+		return nil, err
 	}
 
 	if categoryId == uuid.Nil {
@@ -45,5 +75,126 @@ func NewExpenditure(description string, amount float64, date time.Time, category
 		Amount:      amount,
 		Date:        date,
 		CategoryId:  categoryId,
+		Tags:        []string{},
+		Planned:     planned,
+		Status:      StatusCleared,
 	}, nil
 }
+
+// Duplicate returns a copy of the expenditure with a new ID, dated date and recorded by the
+// household member by, who may be empty, applying the future date policy at now
+func (e *Expenditure) Duplicate(date time.Time, by uuid.UUID, policy FutureDatePolicy, now time.Time) (*Expenditure, error) {
+	if e.IsRefund() {
+		return nil, ErrRefundNotEditable
+	}
+
+	planned, err := policy.Check(date, now)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicate := e.Clone()
+	duplicate.ID = uuid.New()
+	duplicate.Date = date
+	duplicate.Planned = planned
+	duplicate.Status = StatusCleared
+	duplicate.Reconciled = false
+	duplicate.CreatedBy = by
+	if duplicate.Tags == nil {
+		duplicate.Tags = []string{}
+	}
+	return duplicate, nil
+}
+
+// Clone returns a deep copy of the expenditure that shares no tags or location with it
+func (e *Expenditure) Clone() *Expenditure {
+	clone := *e
+	if e.Tags != nil {
+		clone.Tags = append(make([]string, 0, len(e.Tags)), e.Tags...)
+	}
+	if e.Location != nil {
+		location := *e.Location
+		clone.Location = &location
+	}
+	return &clone
+}
+
+// SetTags replaces the tags with their normalized form: trimmed, lower-cased, without a
+// leading '#', de-duplicated and sorted
+func (e *Expenditure) SetTags(tags []string) {
+	e.Tags = NormalizeTags(tags)
+}
+
+// HasTag reports whether the expenditure carries the normalized tag
+func (e *Expenditure) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// ResolveUnitAmount checks a per-unit expense: when quantity and unitPrice are given, the amount
+// must equal their product (to the cent), and a zero amount is derived from them
+func ResolveUnitAmount(amount, quantity, unitPrice float64) (float64, error) {
+	if quantity == 0 && unitPrice == 0 {
+		return amount, nil
+	}
+
+	if quantity <= 0 {
+		return 0, ErrInvalidExpenditureQuantity
+	}
+
+	if unitPrice <= 0 {
+		return 0, ErrInvalidExpenditureUnitPrice
+	}
+
+	derived := math.Round(quantity*unitPrice*100) / 100
+	if amount == 0 {
+		return derived, nil
+	}
+
+	if math.Abs(amount-derived) >= 0.005 {
+		return 0, ErrExpenditureAmountMismatch
+	}
+
+	return amount, nil
+}
+
+// SetQuantity records the per-unit breakdown of the expenditure; all zero values clear it
+func (e *Expenditure) SetQuantity(quantity, unitPrice float64, unit string) error {
+	if quantity == 0 && unitPrice == 0 && unit == "" {
+		e.Quantity, e.UnitPrice, e.Unit = 0, 0, ""
+		return nil
+	}
+
+	if _, err := ResolveUnitAmount(e.Amount, quantity, unitPrice); err != nil {
+		return err
+	}
+
+	unit = strings.TrimSpace(unit)
+	if unit == "" {
+		return ErrExpenditureUnitEmpty
+	}
+
+	e.Quantity = quantity
+	e.UnitPrice = unitPrice
+	e.Unit = unit
+	return nil
+}
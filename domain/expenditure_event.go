@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var ErrExpenditureEventNotFound = errors.New("expenditure event not found")
+var ErrExpenditureEventSuperseded = errors.New("expenditure event is not the latest of its expenditure")
+
+// Expenditure event kinds
+const (
+	ExpenditureCreated  = "expenditure_created"
+	ExpenditureAmended  = "expenditure_amended"
+	ExpenditureDeleted  = "expenditure_deleted"
+	ExpenditureArchived = "expenditure_archived"
+)
+
+// ExpenditureEvent is a change to one expenditure. In event-sourced storage the events are the
+// source of truth and the stored expenditures are a projection of them
+type ExpenditureEvent struct {
+	Seq           int64        `json:"seq"` // Increases with every event, assigned when appended
+	Kind          string       `json:"kind"`
+	ExpenditureID uuid.UUID    `json:"expenditure_id"`
+	Expenditure   *Expenditure `json:"expenditure,omitempty"` // State after the event, nil once deleted or archived
+	At            time.Time    `json:"at"`
+}
+
+// NewExpenditureEvent creates an event of the given kind; expenditure is the state after the
+// change and is dropped for deletions and archival
+func NewExpenditureEvent(kind string, id uuid.UUID, expenditure *Expenditure) *ExpenditureEvent {
+	event := &ExpenditureEvent{
+		Kind:          kind,
+		ExpenditureID: id,
+		At:            time.Now(),
+	}
+	if kind == ExpenditureCreated || kind == ExpenditureAmended {
+		state := *expenditure
+		event.Expenditure = &state
+	}
+	return event
+}
+
+// ProjectExpenditures folds the events, oldest first, into the current state of every
+// expenditure they mention. Deleted and archived expenditures map to nil
+func ProjectExpenditures(events []*ExpenditureEvent) map[uuid.UUID]*Expenditure {
+	state := make(map[uuid.UUID]*Expenditure)
+	for _, event := range events {
+		state[event.ExpenditureID] = event.Expenditure
+	}
+	return state
+}
+
+// ExpenditureBefore returns the state of an expenditure just before the event with the given
+// sequence number, nil when it did not exist yet; events are those of the expenditure, oldest first
+func ExpenditureBefore(events []*ExpenditureEvent, seq int64) *Expenditure {
+	var state *Expenditure
+	for _, event := range events {
+		if event.Seq >= seq {
+			break
+		}
+		state = event.Expenditure
+	}
+	return state
+}
+
+// Clone returns a deep copy of the event, with a copy of the expenditure state
+func (e *ExpenditureEvent) Clone() *ExpenditureEvent {
+	clone := *e
+	if e.Expenditure != nil {
+		clone.Expenditure = e.Expenditure.Clone()
+	}
+	return &clone
+}
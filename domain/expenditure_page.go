@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"github.com/google/uuid"
+	"sort"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ExpenditureCursor is a position in the listing of expenditures, which is ordered newest first
+// by date and then by ID. Unlike an offset it stays valid when expenditures are added before it
+type ExpenditureCursor struct {
+	Date time.Time
+	ID   uuid.UUID
+}
+
+// CursorAfter returns the cursor pointing right after the expenditure
+func CursorAfter(expenditure *Expenditure) ExpenditureCursor {
+	return ExpenditureCursor{Date: expenditure.Date, ID: expenditure.ID}
+}
+
+// Encode returns the cursor as an opaque token for clients
+func (c ExpenditureCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.Date.UTC().Format(time.RFC3339Nano) + "," + c.ID.String()))
+}
+
+// ParseExpenditureCursor reads a token created by Encode
+func ParseExpenditureCursor(token string) (ExpenditureCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return ExpenditureCursor{}, ErrInvalidCursor
+	}
+
+	dateStr, idStr, found := strings.Cut(string(raw), ",")
+	if !found {
+		return ExpenditureCursor{}, ErrInvalidCursor
+	}
+	date, err := time.Parse(time.RFC3339Nano, dateStr)
+	if err != nil {
+		return ExpenditureCursor{}, ErrInvalidCursor
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return ExpenditureCursor{}, ErrInvalidCursor
+	}
+
+	return ExpenditureCursor{Date: date, ID: id}, nil
+}
+
+// Precedes reports whether the expenditure is listed before the cursor position
+func (c ExpenditureCursor) Precedes(expenditure *Expenditure) bool {
+	if !expenditure.Date.Equal(c.Date) {
+		return expenditure.Date.After(c.Date)
+	}
+	return strings.Compare(expenditure.ID.String(), c.ID.String()) >= 0
+}
+
+// ExpenditurePageQuery selects one page of the expenditure listing
+type ExpenditurePageQuery struct {
+	From       time.Time          // Only expenditures dated on or after, zero for no bound
+	To         time.Time          // Only expenditures dated before, zero for no bound
+	After      *ExpenditureCursor // Continue after this position, nil for the first page
+	CategoryId uuid.UUID          // Only expenditures in this category, uuid.Nil for all
+	Limit      int                // Page size
+}
+
+// SortExpenditures orders expenditures the way they are listed: newest first, then by ID
+func SortExpenditures(expenditures []*Expenditure) {
+	sort.Slice(expenditures, func(i, j int) bool {
+		if !expenditures[i].Date.Equal(expenditures[j].Date) {
+			return expenditures[i].Date.After(expenditures[j].Date)
+		}
+		return expenditures[i].ID.String() > expenditures[j].ID.String()
+	})
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"math"
+	"strings"
+	"time"
+)
+
+var ErrInvalidExpenditureStatus = errors.New("expenditure status must be pending or cleared")
+var ErrExpenditureNotPending = errors.New("only pending expenditures can be cleared or cancelled")
+
+// ExpenditureStatus is where a transaction is in its settlement, e.g. a card payment stays
+// pending for days and its amount may change when it clears
+type ExpenditureStatus string
+
+const (
+	StatusPending   ExpenditureStatus = "pending"
+	StatusCleared   ExpenditureStatus = "cleared" // Settled; expenditures stored without a status are cleared
+	StatusCancelled ExpenditureStatus = "cancelled"
+)
+
+// pendingMatchWindow is how far apart the dates of a pending expenditure and the cleared
+// transaction settling it may be
+const pendingMatchWindow = 7 * 24 * time.Hour
+
+// pendingMatchTolerance is how much the cleared amount may differ from the pending one, as a
+// fraction of the pending amount, e.g. for tips added to a restaurant bill
+const pendingMatchTolerance = 0.2
+
+// ParseExpenditureStatus reads the status of a new or updated expenditure; an empty status
+// is cleared. Expenditures are only cancelled through Cancel
+func ParseExpenditureStatus(status string) (ExpenditureStatus, error) {
+	switch ExpenditureStatus(status) {
+	case "", StatusCleared:
+		return StatusCleared, nil
+	case StatusPending:
+		return StatusPending, nil
+	default:
+		return "", ErrInvalidExpenditureStatus
+	}
+}
+
+// IsPending reports whether the expenditure has not settled yet
+func (e *Expenditure) IsPending() bool {
+	return e.Status == StatusPending
+}
+
+// IsCancelled reports whether the expenditure was cancelled before it settled; it counts as no
+// spending at all
+func (e *Expenditure) IsCancelled() bool {
+	return e.Status == StatusCancelled
+}
+
+// Clear settles a pending expenditure. The amount and date of the settlement replace the pending
+// ones when given; the tax is derived from the new amount at the same rate
+func (e *Expenditure) Clear(amount float64, date *time.Time) error {
+	if !e.IsPending() {
+		return ErrExpenditureNotPending
+	}
+
+	if amount < 0 {
+		return ErrInvalidExpenditureAmount
+	}
+
+	if amount > 0 {
+		e.Amount = amount
+		e.TaxAmount = TaxIncluded(amount, e.TaxRate)
+	}
+
+	if date != nil {
+		e.Date = *date
+	}
+	e.Status = StatusCleared
+	return nil
+}
+
+// Cancel marks a pending expenditure that will not settle, e.g. a released card authorization
+func (e *Expenditure) Cancel() error {
+	if !e.IsPending() {
+		return ErrExpenditureNotPending
+	}
+
+	e.Status = StatusCancelled
+	return nil
+}
+
+// MatchPending returns the pending expenditure that a cleared transaction imported with the
+// description, amount and date most likely settles, or nil when none does. Candidates are at the
+// same merchant or have the same description, are dated within a week and differ in amount by
+// at most a fifth; the closest in amount wins
+func MatchPending(pending []*Expenditure, description string, merchantId uuid.UUID, amount float64, date time.Time) *Expenditure {
+	var best *Expenditure
+	for _, candidate := range pending {
+		if !candidate.IsPending() {
+			continue
+		}
+
+		sameMerchant := merchantId != uuid.Nil && candidate.MerchantId == merchantId
+		if !sameMerchant && !strings.EqualFold(strings.TrimSpace(candidate.Description), strings.TrimSpace(description)) {
+			continue
+		}
+
+		if d := date.Sub(candidate.Date); d > pendingMatchWindow || d < -pendingMatchWindow {
+			continue
+		}
+
+		difference := math.Abs(amount - candidate.Amount)
+		if difference > candidate.Amount*pendingMatchTolerance {
+			continue
+		}
+		if best == nil || difference < math.Abs(amount-best.Amount) {
+			best = candidate
+		}
+	}
+	return best
+}
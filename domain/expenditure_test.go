@@ -0,0 +1,51 @@
+package domain_test
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestExpenditureDuplicate(t *testing.T) {
+	recorder, copier := uuid.New(), uuid.New()
+	original, err := domain.NewExpenditure("Lunch", 12.5, day(2024, 2, 29), uuid.New())
+	if err != nil {
+		t.Fatalf("creating expenditure: %v", err)
+	}
+	original.CreatedBy = recorder
+	original.Reconciled = true
+	refund := original.Clone()
+	refund.RefundOf = uuid.New()
+
+	tests := []struct {
+		name          string
+		original      *domain.Expenditure
+		by            uuid.UUID
+		wantErr       error
+		wantCreatedBy uuid.UUID
+	}{
+		{"by another member", original, copier, nil, copier},
+		{"by no member", original, uuid.Nil, nil, uuid.Nil},
+		{"refund", refund, copier, domain.ErrRefundNotEditable, uuid.Nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			duplicate, err := tt.original.Duplicate(day(2024, 3, 1), tt.by, domain.RejectFutureDates, day(2024, 3, 2))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if duplicate.CreatedBy != tt.wantCreatedBy {
+				t.Errorf("created by %s, want %s", duplicate.CreatedBy, tt.wantCreatedBy)
+			}
+			if duplicate.ID == tt.original.ID || duplicate.Reconciled || !duplicate.Date.Equal(day(2024, 3, 1)) {
+				t.Errorf("duplicate = %+v, want a new, unreconciled expenditure dated 2024-03-01", duplicate)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"slices"
+	"time"
+)
+
+var ErrExpenseReportTitleEmpty = errors.New("expense report title cannot be empty")
+var ErrExpenseReportEmpty = errors.New("expense report has no expenditures")
+var ErrExpenseReportNotDraft = errors.New("expense report can only be changed while in draft or rejected")
+var ErrInvalidExpenseReportTransition = errors.New("invalid expense report status transition")
+var ErrExpenditureAlreadyClaimed = errors.New("expenditure is already part of another expense report")
+var ErrExpenseReportCommentRequired = errors.New("a comment is required when rejecting an expense report")
+
+// ExpenseReportStatus is the state of a reimbursement claim
+type ExpenseReportStatus string
+
+const (
+	ExpenseReportDraft      ExpenseReportStatus = "draft"
+	ExpenseReportSubmitted  ExpenseReportStatus = "submitted"
+	ExpenseReportApproved   ExpenseReportStatus = "approved"
+	ExpenseReportRejected   ExpenseReportStatus = "rejected"
+	ExpenseReportReimbursed ExpenseReportStatus = "reimbursed"
+)
+
+// Actions recorded in the audit trail of an expense report
+const (
+	ExpenseReportActionCreate    = "create"
+	ExpenseReportActionSubmit    = "submit"
+	ExpenseReportActionApprove   = "approve"
+	ExpenseReportActionReject    = "reject"
+	ExpenseReportActionReimburse = "reimburse"
+)
+
+// expenseReportTransitions is the approval state machine: the statuses an action may be
+// taken from and the status it leads to. Rejected reports can be edited and resubmitted
+var expenseReportTransitions = map[string]struct {
+	from []ExpenseReportStatus
+	to   ExpenseReportStatus
+}{
+	ExpenseReportActionSubmit:    {from: []ExpenseReportStatus{ExpenseReportDraft, ExpenseReportRejected}, to: ExpenseReportSubmitted},
+	ExpenseReportActionApprove:   {from: []ExpenseReportStatus{ExpenseReportSubmitted}, to: ExpenseReportApproved},
+	ExpenseReportActionReject:    {from: []ExpenseReportStatus{ExpenseReportSubmitted}, to: ExpenseReportRejected},
+	ExpenseReportActionReimburse: {from: []ExpenseReportStatus{ExpenseReportApproved}, to: ExpenseReportReimbursed},
+}
+
+// ExpenseReportEvent is an entry of a report's audit trail
+type ExpenseReportEvent struct {
+	At         time.Time           `json:"at"`
+	Action     string              `json:"action"`
+	Actor      string              `json:"actor"`             // Claimant or reviewer who took the action
+	Comment    string              `json:"comment,omitempty"` // e.g. the reason for a rejection
+	FromStatus ExpenseReportStatus `json:"from_status,omitempty"`
+	ToStatus   ExpenseReportStatus `json:"to_status"`
+}
+
+// ExpenseReport bundles expenditures into a claim submitted to an employer for reimbursement.
+// Reports move from draft to submitted, approved and finally reimbursed
+type ExpenseReport struct {
+	ID             uuid.UUID            `json:"id"`
+	Title          string               `json:"title"`           // e.g. "Berlin trip, June 2024"
+	Claimant       string               `json:"claimant"`        // Name printed on the exported claim
+	ExpenditureIDs []uuid.UUID          `json:"expenditure_ids"` // Expenditures claimed in this report
+	Status         ExpenseReportStatus  `json:"status"`
+	CreatedAt      time.Time            `json:"created_at"`
+	SubmittedAt    *time.Time           `json:"submitted_at,omitempty"`
+	ApprovedAt     *time.Time           `json:"approved_at,omitempty"`
+	ReimbursedAt   *time.Time           `json:"reimbursed_at,omitempty"`
+	History        []ExpenseReportEvent `json:"history"` // Audit trail, oldest first
+}
+
+func NewExpenseReport(title, claimant string, expenditureIDs []uuid.UUID) (*ExpenseReport, error) {
+	report := &ExpenseReport{
+		ID:        uuid.New(),
+		Status:    ExpenseReportDraft,
+		CreatedAt: time.Now(),
+	}
+	if err := report.Update(title, claimant, expenditureIDs); err != nil {
+		return nil, err
+	}
+	report.History = []ExpenseReportEvent{{
+		At:       report.CreatedAt,
+		Action:   ExpenseReportActionCreate,
+		Actor:    claimant,
+		ToStatus: ExpenseReportDraft,
+	}}
+	return report, nil
+}
+
+// Update changes the title, claimant and claimed expenditures of a draft or rejected report
+func (r *ExpenseReport) Update(title, claimant string, expenditureIDs []uuid.UUID) error {
+	if r.Status != ExpenseReportDraft && r.Status != ExpenseReportRejected {
+		return ErrExpenseReportNotDraft
+	}
+
+	if title == "" {
+		return ErrExpenseReportTitleEmpty
+	}
+
+	// Keep the given order but drop duplicates
+	ids := make([]uuid.UUID, 0, len(expenditureIDs))
+	seen := make(map[uuid.UUID]bool, len(expenditureIDs))
+	for _, id := range expenditureIDs {
+		if id == uuid.Nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	r.Title = title
+	r.Claimant = claimant
+	r.ExpenditureIDs = ids
+	return nil
+}
+
+// Contains reports whether the expenditure is claimed in this report
+func (r *ExpenseReport) Contains(expenditureID uuid.UUID) bool {
+	for _, id := range r.ExpenditureIDs {
+		if id == expenditureID {
+			return true
+		}
+	}
+	return false
+}
+
+// Editable reports whether the report may still be changed by its claimant
+func (r *ExpenseReport) Editable() bool {
+	return r.Status == ExpenseReportDraft || r.Status == ExpenseReportRejected
+}
+
+func (r *ExpenseReport) Submit(actor string) error {
+	if len(r.ExpenditureIDs) == 0 {
+		return ErrExpenseReportEmpty
+	}
+	return r.transition(ExpenseReportActionSubmit, actor, "")
+}
+
+func (r *ExpenseReport) Approve(reviewer, comment string) error {
+	return r.transition(ExpenseReportActionApprove, reviewer, comment)
+}
+
+func (r *ExpenseReport) Reject(reviewer, comment string) error {
+	if comment == "" {
+		return ErrExpenseReportCommentRequired
+	}
+	return r.transition(ExpenseReportActionReject, reviewer, comment)
+}
+
+func (r *ExpenseReport) MarkReimbursed(actor, comment string) error {
+	return r.transition(ExpenseReportActionReimburse, actor, comment)
+}
+
+// LastEvent returns the most recent audit trail entry
+func (r *ExpenseReport) LastEvent() ExpenseReportEvent {
+	if len(r.History) == 0 {
+		return ExpenseReportEvent{}
+	}
+	return r.History[len(r.History)-1]
+}
+
+// transition applies an action of the state machine and records it in the audit trail
+func (r *ExpenseReport) transition(action, actor, comment string) error {
+	t, ok := expenseReportTransitions[action]
+	if !ok {
+		return ErrInvalidExpenseReportTransition
+	}
+
+	allowed := false
+	for _, from := range t.from {
+		if r.Status == from {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return ErrInvalidExpenseReportTransition
+	}
+
+	now := time.Now()
+	switch t.to {
+	case ExpenseReportSubmitted:
+		r.SubmittedAt = &now
+		r.ApprovedAt = nil
+	case ExpenseReportApproved:
+		r.ApprovedAt = &now
+	case ExpenseReportReimbursed:
+		r.ReimbursedAt = &now
+	}
+
+	r.History = append(r.History, ExpenseReportEvent{
+		At:         now,
+		Action:     action,
+		Actor:      actor,
+		Comment:    comment,
+		FromStatus: r.Status,
+		ToStatus:   t.to,
+	})
+	r.Status = t.to
+	return nil
+}
+
+// Clone returns a deep copy of the expense report
+func (r *ExpenseReport) Clone() *ExpenseReport {
+	clone := *r
+	clone.ExpenditureIDs = slices.Clone(r.ExpenditureIDs)
+	clone.History = slices.Clone(r.History)
+	clone.SubmittedAt = cloneTime(r.SubmittedAt)
+	clone.ApprovedAt = cloneTime(r.ApprovedAt)
+	clone.ReimbursedAt = cloneTime(r.ReimbursedAt)
+	return &clone
+}
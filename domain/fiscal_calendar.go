@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var ErrInvalidFiscalCalendar = errors.New("fiscal month must be calendar, 4-4-5 or a start day between 1 and 28")
+var ErrInvalidFiscalPeriod = errors.New("invalid month, use YYYY-MM")
+
+// FiscalCalendar decides where the months of budgets and reports begin. The zero value uses
+// calendar months
+type FiscalCalendar struct {
+	StartDay int  // Day of the month periods start on, e.g. payday; 0 or 1 for calendar months
+	Weeks445 bool // Periods of 4, 4 and 5 weeks per quarter, in weeks starting on Monday
+}
+
+// ParseFiscalCalendar reads a fiscal calendar from configuration: "calendar", "4-4-5" or the day
+// of the month periods start on
+func ParseFiscalCalendar(s string) (FiscalCalendar, error) {
+	switch s {
+	case "", "calendar":
+		return FiscalCalendar{}, nil
+	case "4-4-5":
+		return FiscalCalendar{Weeks445: true}, nil
+	}
+
+	day, err := strconv.Atoi(s)
+	if err != nil || day < 1 || day > 28 {
+		return FiscalCalendar{}, ErrInvalidFiscalCalendar
+	}
+	return FiscalCalendar{StartDay: day}, nil
+}
+
+func (c FiscalCalendar) String() string {
+	switch {
+	case c.Weeks445:
+		return "4-4-5"
+	case c.StartDay > 1:
+		return strconv.Itoa(c.StartDay)
+	default:
+		return "calendar"
+	}
+}
+
+// Period returns the fiscal month containing t as [from, to), in the location of t
+func (c FiscalCalendar) Period(t time.Time) (time.Time, time.Time) {
+	if c.Weeks445 {
+		year, week := t.ISOWeek()
+		return c.weekPeriod(year, periodOfWeek(week), t.Location())
+	}
+
+	from := time.Date(t.Year(), t.Month(), max(c.StartDay, 1), 0, 0, 0, 0, t.Location())
+	if t.Before(from) {
+		from = from.AddDate(0, -1, 0)
+	}
+	return from, from.AddDate(0, 1, 0)
+}
+
+// Label names the fiscal month containing t as YYYY-MM. Months with a start day are named after
+// the month they start in; 4-4-5 periods are numbered 01 to 12 within their ISO week year
+func (c FiscalCalendar) Label(t time.Time) string {
+	if c.Weeks445 {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-%02d", year, periodOfWeek(week)+1)
+	}
+
+	from, _ := c.Period(t)
+	return from.Format("2006-01")
+}
+
+// PeriodOf returns the fiscal month named label, as returned by Label, as [from, to)
+func (c FiscalCalendar) PeriodOf(label string, loc *time.Location) (time.Time, time.Time, error) {
+	month, err := time.ParseInLocation("2006-01", label, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidFiscalPeriod
+	}
+
+	if c.Weeks445 {
+		from, to := c.weekPeriod(month.Year(), int(month.Month())-1, loc)
+		return from, to, nil
+	}
+
+	from := month.AddDate(0, 0, max(c.StartDay, 1)-1)
+	return from, from.AddDate(0, 1, 0), nil
+}
+
+// Span returns the span of n fiscal months containing t as [from, to), aligned to the start of the
+// year, e.g. n = 3 for quarters and 12 for years
+func (c FiscalCalendar) Span(t time.Time, n int) (time.Time, time.Time) {
+	year, month := 0, 0
+	if c.Weeks445 {
+		var week int
+		year, week = t.ISOWeek()
+		month = periodOfWeek(week) + 1
+	} else {
+		start, _ := c.Period(t)
+		year, month = start.Year(), int(start.Month())
+	}
+
+	first := (month-1)/n*n + 1
+	from, _, _ := c.PeriodOf(fmt.Sprintf("%04d-%02d", year, first), t.Location())
+	_, to, _ := c.PeriodOf(fmt.Sprintf("%04d-%02d", year, first+n-1), t.Location())
+	return from, to
+}
+
+// weekPeriod returns the 4-4-5 period with the given index, 0 to 11, of an ISO week year. The
+// last period takes the extra week of years with 53 weeks
+func (c FiscalCalendar) weekPeriod(year, period int, loc *time.Location) (time.Time, time.Time) {
+	from := isoWeekStart(year, firstWeekOfPeriod(period), loc)
+	if period == 11 {
+		return from, isoWeekStart(year+1, 1, loc)
+	}
+	return from, isoWeekStart(year, firstWeekOfPeriod(period+1), loc)
+}
+
+// periodOfWeek returns the index of the 4-4-5 period containing an ISO week
+func periodOfWeek(week int) int {
+	if week > 52 {
+		return 11
+	}
+	quarter, weekOfQuarter := (week-1)/13, (week-1)%13
+	switch {
+	case weekOfQuarter < 4:
+		return quarter * 3
+	case weekOfQuarter < 8:
+		return quarter*3 + 1
+	default:
+		return quarter*3 + 2
+	}
+}
+
+func firstWeekOfPeriod(period int) int {
+	return period/3*13 + [3]int{0, 4, 8}[period%3] + 1
+}
+
+// isoWeekStart returns the Monday starting an ISO week; week 1 is the one containing January 4
+func isoWeekStart(year, week int, loc *time.Location) time.Time {
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+	return monday.AddDate(0, 0, 7*(week-1))
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidOriginalAmount = errors.New("original amount must be positive")
+var ErrInvalidOriginalCurrency = errors.New("original currency must be a three-letter ISO 4217 code such as EUR")
+var ErrOriginalCurrencyMissing = errors.New("original amount and original currency must be given together")
+
+// SetOriginal records the amount and currency paid abroad, e.g. 25.00 EUR, next to the amount
+// settled in the home currency; zero values clear them. The currency is upper-cased
+func (e *Expenditure) SetOriginal(amount float64, currency string) error {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if amount == 0 && currency == "" {
+		e.OriginalAmount = 0
+		e.OriginalCurrency = ""
+		return nil
+	}
+
+	if amount == 0 || currency == "" {
+		return ErrOriginalCurrencyMissing
+	}
+
+	if amount < 0 {
+		return ErrInvalidOriginalAmount
+	}
+
+	if len(currency) != 3 || strings.Trim(currency, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return ErrInvalidOriginalCurrency
+	}
+
+	e.OriginalAmount = round2(amount)
+	e.OriginalCurrency = currency
+	return nil
+}
+
+// IsForeign reports whether the expenditure was paid in another currency
+func (e *Expenditure) IsForeign() bool {
+	return e.OriginalCurrency != ""
+}
+
+// ExchangeRate returns the home currency paid per unit of the original currency, including any
+// fees the bank charged, or 0 for expenditures in the home currency
+func (e *Expenditure) ExchangeRate() float64 {
+	if !e.IsForeign() || e.OriginalAmount == 0 {
+		return 0
+	}
+	return e.Amount / e.OriginalAmount
+}
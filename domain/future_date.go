@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrExpenditureTooFarAhead = errors.New("expenditure date is too far in the future")
+var ErrInvalidFutureDatePolicy = errors.New("future date policy must be reject, allow or planned")
+
+// FutureDateMode decides what happens to expenditures dated in the future
+type FutureDateMode string
+
+const (
+	FutureDatesReject  FutureDateMode = "reject"  // Refuse them
+	FutureDatesAllow   FutureDateMode = "allow"   // Accept them as spent, e.g. post-dated entries
+	FutureDatesPlanned FutureDateMode = "planned" // Accept them as planned, not spent until their date
+)
+
+// FutureDatePolicy decides whether expenditures may be dated in the future and how far
+type FutureDatePolicy struct {
+	Mode   FutureDateMode
+	Window time.Duration // How far ahead dates may be, 0 for no bound; unused when rejecting
+}
+
+// RejectFutureDates is the default policy: expenditures cannot be dated in the future
+var RejectFutureDates = FutureDatePolicy{Mode: FutureDatesReject}
+
+// ParseFutureDatePolicy reads a policy from its mode and the number of days dates may be ahead,
+// 0 for no bound. An empty mode rejects future dates
+func ParseFutureDatePolicy(mode string, days int) (FutureDatePolicy, error) {
+	if days < 0 {
+		return FutureDatePolicy{}, fmt.Errorf("future date window must not be negative: %d days", days)
+	}
+
+	switch FutureDateMode(mode) {
+	case "", FutureDatesReject:
+		return RejectFutureDates, nil
+	case FutureDatesAllow, FutureDatesPlanned:
+		return FutureDatePolicy{Mode: FutureDateMode(mode), Window: time.Duration(days) * 24 * time.Hour}, nil
+	default:
+		return FutureDatePolicy{}, ErrInvalidFutureDatePolicy
+	}
+}
+
+// Check applies the policy to a date and reports whether an expenditure on it is planned
+func (p FutureDatePolicy) Check(date, now time.Time) (bool, error) {
+	if !date.After(now) {
+		return false, nil
+	}
+	if p.Mode == FutureDatesReject || p.Mode == "" {
+		return false, ErrExpenditureFutureDate
+	}
+	if p.Window > 0 && date.After(now.Add(p.Window)) {
+		return false, fmt.Errorf("%w: at most %d days ahead", ErrExpenditureTooFarAhead, int(p.Window.Hours()/24))
+	}
+	return p.Mode == FutureDatesPlanned, nil
+}
+
+// IsPlanned reports whether the expenditure is a planned payment not spent yet at now. Planned
+// expenditures count as spent once their date has passed
+func (e *Expenditure) IsPlanned(now time.Time) bool {
+	return e.Planned && e.Date.After(now)
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var ErrGoalNameEmpty = errors.New("goal name cannot be empty")
+var ErrInvalidGoalTargetAmount = errors.New("invalid goal target amount")
+var ErrGoalDeadlineBeforeStart = errors.New("goal deadline must be after its start date")
+var ErrGoalCategoryIdEmpty = errors.New("goal category ID cannot be empty")
+
+// Goal is a savings target. Money put towards it is recorded as expenditures in the linked
+// category, e.g. transfers to a savings account
+type Goal struct {
+	ID           uuid.UUID `json:"id"`            // Unique identifier for the goal
+	Name         string    `json:"name"`          // Name of the goal, e.g. "Emergency fund"
+	TargetAmount float64   `json:"target_amount"` // Amount to save
+	StartDate    time.Time `json:"start_date"`    // Expenditures from this date on count towards the goal
+	Deadline     time.Time `json:"deadline"`      // Date by which the target should be reached
+	CategoryId   uuid.UUID `json:"category_id"`   // Category whose expenditures count towards the goal
+}
+
+func NewGoal(name string, targetAmount float64, startDate, deadline time.Time, categoryId uuid.UUID) (*Goal, error) {
+	goal := &Goal{ID: uuid.New()}
+	if err := goal.Update(name, targetAmount, startDate, deadline, categoryId); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+func (g *Goal) Update(name string, targetAmount float64, startDate, deadline time.Time, categoryId uuid.UUID) error {
+	if name == "" {
+		return ErrGoalNameEmpty
+	}
+
+	if targetAmount <= 0 {
+		return ErrInvalidGoalTargetAmount
+	}
+
+	if !deadline.After(startDate) {
+		return ErrGoalDeadlineBeforeStart
+	}
+
+	if categoryId == uuid.Nil {
+		return ErrGoalCategoryIdEmpty
+	}
+
+	g.Name = name
+	g.TargetAmount = targetAmount
+	g.StartDate = startDate
+	g.Deadline = deadline
+	g.CategoryId = categoryId
+
+	return nil
+}
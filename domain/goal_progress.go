@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// GoalProgressPoint is the cumulative amount saved at the end of a month
+type GoalProgressPoint struct {
+	Month string  `json:"month"` // Month in YYYY-MM format
+	Saved float64 `json:"saved"` // Cumulative amount saved by the end of the month
+}
+
+// GoalProgress compares the amount saved towards a goal with its target
+type GoalProgress struct {
+	GoalID          string              `json:"goal_id"`
+	TargetAmount    float64             `json:"target_amount"`
+	Saved           float64             `json:"saved"`
+	Remaining       float64             `json:"remaining"`
+	PercentComplete float64             `json:"percent_complete"`
+	ExpectedSaved   float64             `json:"expected_saved"`   // Amount that should be saved by now at a steady pace
+	OnTrack         bool                `json:"on_track"`         // Whether Saved is at least ExpectedSaved
+	MonthlyRequired float64             `json:"monthly_required"` // Monthly amount needed to reach the target by the deadline
+	History         []GoalProgressPoint `json:"history"`
+}
+
+// Progress computes the goal's progress at now from the expenditures in its category
+func (g *Goal) Progress(expenditures []*Expenditure, now time.Time) GoalProgress {
+	monthly := make(map[string]float64)
+	var saved float64
+	for _, expenditure := range expenditures {
+		if expenditure.CategoryId != g.CategoryId || expenditure.Date.Before(g.StartDate) || expenditure.Date.After(now) {
+			continue
+		}
+		saved += expenditure.Amount
+		monthly[expenditure.Date.Format("2006-01")] += expenditure.Amount
+	}
+
+	// Cumulative history, one point per month from the start date to now
+	history := []GoalProgressPoint{}
+	var cumulative float64
+	end := now
+	if g.Deadline.Before(end) {
+		end = g.Deadline
+	}
+	for month := time.Date(g.StartDate.Year(), g.StartDate.Month(), 1, 0, 0, 0, 0, time.UTC); !month.After(end); month = month.AddDate(0, 1, 0) {
+		key := month.Format("2006-01")
+		cumulative += monthly[key]
+		history = append(history, GoalProgressPoint{Month: key, Saved: round2(cumulative)})
+	}
+
+	progress := GoalProgress{
+		GoalID:          g.ID.String(),
+		TargetAmount:    g.TargetAmount,
+		Saved:           round2(saved),
+		Remaining:       round2(math.Max(g.TargetAmount-saved, 0)),
+		PercentComplete: round2(math.Min(saved/g.TargetAmount*100, 100)),
+		History:         history,
+	}
+
+	// Expected amount assuming a steady pace between the start date and the deadline
+	elapsed := now.Sub(g.StartDate).Hours()
+	total := g.Deadline.Sub(g.StartDate).Hours()
+	progress.ExpectedSaved = round2(g.TargetAmount * math.Min(math.Max(elapsed/total, 0), 1))
+	progress.OnTrack = saved >= progress.ExpectedSaved
+
+	if progress.Remaining > 0 {
+		monthsLeft := g.Deadline.Sub(now).Hours() / 24 / 30.4375
+		if monthsLeft < 1 {
+			monthsLeft = 1
+		}
+		progress.MonthlyRequired = round2(progress.Remaining / monthsLeft)
+	}
+
+	return progress
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,265 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrHouseholdNotFound = errors.New("household not found")
+var ErrHouseholdNameEmpty = errors.New("household name cannot be empty")
+var ErrMemberNameEmpty = errors.New("member name cannot be empty")
+var ErrMemberNotFound = errors.New("household member not found")
+var ErrInvalidHouseholdRole = errors.New("invalid role, use owner, member, viewer or child")
+var ErrNoAllowance = errors.New("member has no allowance")
+var ErrInvitationNotFound = errors.New("invitation not found")
+var ErrInvitationExpired = errors.New("invitation has expired")
+var ErrInvitationRevoked = errors.New("invitation has been revoked")
+var ErrInvitationAccepted = errors.New("invitation has already been accepted")
+var ErrLastOwner = errors.New("the last owner of a household cannot leave while others belong to it")
+
+// HouseholdRole is what a member may do in a household
+type HouseholdRole string
+
+const (
+	RoleOwner  HouseholdRole = "owner"  // Manages the household and invites others
+	RoleMember HouseholdRole = "member" // Records and edits expenditures
+	RoleViewer HouseholdRole = "viewer" // Only reads
+	RoleChild  HouseholdRole = "child"  // Records expenditures against their own allowance and reviews it
+)
+
+// Permission is something a household role may allow its members to do, see HouseholdRole.Can
+type Permission string
+
+const (
+	PermissionRead      Permission = "read"      // View expenditures, reports and settings
+	PermissionWrite     Permission = "write"     // Record and change expenditures, budgets and the like
+	PermissionManage    Permission = "manage"    // Found households, invite members and adjust allowances
+	PermissionAllowance Permission = "allowance" // Record expenditures against the own allowance and review it
+)
+
+var rolePermissions = map[HouseholdRole][]Permission{
+	RoleOwner:  {PermissionRead, PermissionWrite, PermissionManage, PermissionAllowance},
+	RoleMember: {PermissionRead, PermissionWrite, PermissionAllowance},
+	RoleViewer: {PermissionRead},
+	RoleChild:  {PermissionAllowance},
+}
+
+// Can reports whether members with the role have the permission
+func (r HouseholdRole) Can(permission Permission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
+
+// ParseHouseholdRole returns the role named s
+func ParseHouseholdRole(s string) (HouseholdRole, error) {
+	role := HouseholdRole(s)
+	switch role {
+	case RoleOwner, RoleMember, RoleViewer, RoleChild:
+		return role, nil
+	}
+	return "", ErrInvalidHouseholdRole
+}
+
+// Invitation statuses, see Invitation.Status
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationRevoked  = "revoked"
+	InvitationExpired  = "expired"
+)
+
+// Household is the people sharing the expenditures tracked here
+type Household struct {
+	ID        uuid.UUID         `json:"id"`
+	Name      string            `json:"name"`
+	Members   []HouseholdMember `json:"members"` // In the order they joined, the founder first
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// HouseholdMember is a person in a household
+type HouseholdMember struct {
+	ID       uuid.UUID     `json:"id"`
+	Name     string        `json:"name"`
+	Email    string        `json:"email,omitempty"`
+	Role     HouseholdRole `json:"role"`
+	JoinedAt time.Time     `json:"joined_at"`
+	// Category whose envelope holds the member's allowance, required for children to record expenditures
+	AllowanceCategoryId uuid.UUID `json:"allowance_category_id,omitzero"`
+}
+
+// Invitation lets whoever holds its token join a household with the role it was issued for. Only
+// a hash of the token is kept, so the stored invitations cannot be used to join
+type Invitation struct {
+	ID          uuid.UUID     `json:"id"`
+	HouseholdId uuid.UUID     `json:"household_id"`
+	Email       string        `json:"email,omitempty"` // Who the invitation was sent to, may be empty
+	Role        HouseholdRole `json:"role"`
+	TokenHash   string        `json:"-"`
+	CreatedAt   time.Time     `json:"created_at"`
+	ExpiresAt   time.Time     `json:"expires_at"`
+	AcceptedAt  *time.Time    `json:"accepted_at,omitempty"`
+	MemberId    uuid.UUID     `json:"member_id,omitzero"` // Member who joined with it
+	RevokedAt   *time.Time    `json:"revoked_at,omitempty"`
+}
+
+// NewHousehold creates a household founded by its first member, who owns it
+func NewHousehold(name, founderName, founderEmail string) (*Household, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrHouseholdNameEmpty
+	}
+	founder, err := NewHouseholdMember(founderName, founderEmail, RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Household{
+		ID:        uuid.New(),
+		Name:      name,
+		Members:   []HouseholdMember{*founder},
+		CreatedAt: founder.JoinedAt,
+	}, nil
+}
+
+func NewHouseholdMember(name, email string, role HouseholdRole) (*HouseholdMember, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrMemberNameEmpty
+	}
+	if _, err := ParseHouseholdRole(string(role)); err != nil {
+		return nil, err
+	}
+
+	return &HouseholdMember{
+		ID:       uuid.New(),
+		Name:     name,
+		Email:    strings.TrimSpace(email),
+		Role:     role,
+		JoinedAt: time.Now(),
+	}, nil
+}
+
+// NewInvitation creates an invitation to a household valid for ttl, and returns it with its
+// token. The token is not kept and cannot be recovered; it is handed to the invitee once
+func NewInvitation(householdId uuid.UUID, email string, role HouseholdRole, ttl time.Duration) (*Invitation, string, error) {
+	if _, err := ParseHouseholdRole(string(role)); err != nil {
+		return nil, "", err
+	}
+
+	secret := make([]byte, 24)
+	rand.Read(secret)
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	now := time.Now()
+	return &Invitation{
+		ID:          uuid.New(),
+		HouseholdId: householdId,
+		Email:       strings.TrimSpace(email),
+		Role:        role,
+		TokenHash:   HashInvitationToken(token),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	}, token, nil
+}
+
+// HashInvitationToken returns the hash invitations are looked up by
+func HashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Status returns whether the invitation is pending, accepted, revoked or expired at now
+func (i *Invitation) Status(now time.Time) string {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationAccepted
+	case i.RevokedAt != nil:
+		return InvitationRevoked
+	case !now.Before(i.ExpiresAt):
+		return InvitationExpired
+	}
+	return InvitationPending
+}
+
+// Accept creates the member joining with a pending invitation and marks the invitation accepted
+func (i *Invitation) Accept(name string, now time.Time) (*HouseholdMember, error) {
+	if err := i.checkPending(now); err != nil {
+		return nil, err
+	}
+	member, err := NewHouseholdMember(name, i.Email, i.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	member.JoinedAt = now
+	i.AcceptedAt = &now
+	i.MemberId = member.ID
+	return member, nil
+}
+
+// Revoke withdraws a pending invitation
+func (i *Invitation) Revoke(now time.Time) error {
+	if err := i.checkPending(now); err != nil {
+		return err
+	}
+	i.RevokedAt = &now
+	return nil
+}
+
+func (i *Invitation) checkPending(now time.Time) error {
+	switch i.Status(now) {
+	case InvitationAccepted:
+		return ErrInvitationAccepted
+	case InvitationRevoked:
+		return ErrInvitationRevoked
+	case InvitationExpired:
+		return ErrInvitationExpired
+	}
+	return nil
+}
+
+// Member returns the member of the household with the given ID, nil when there is none
+func (h *Household) Member(id uuid.UUID) *HouseholdMember {
+	for i := range h.Members {
+		if h.Members[i].ID == id {
+			return &h.Members[i]
+		}
+	}
+	return nil
+}
+
+// CanLeave checks that a member may leave the household without leaving it without an owner
+func (h *Household) CanLeave(id uuid.UUID) error {
+	member := h.Member(id)
+	if member == nil {
+		return ErrMemberNotFound
+	}
+	if member.Role != RoleOwner || len(h.Members) == 1 {
+		return nil
+	}
+	for _, other := range h.Members {
+		if other.ID != id && other.Role == RoleOwner {
+			return nil
+		}
+	}
+	return ErrLastOwner
+}
+
+func (h *Household) Clone() *Household {
+	clone := *h
+	clone.Members = slices.Clone(h.Members)
+	return &clone
+}
+
+func (i *Invitation) Clone() *Invitation {
+	clone := *i
+	clone.AcceptedAt = cloneTime(i.AcceptedAt)
+	clone.RevokedAt = cloneTime(i.RevokedAt)
+	return &clone
+}
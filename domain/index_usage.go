@@ -0,0 +1,12 @@
+package domain
+
+// IndexUsage is how much a database index has been used since the statistics were last reset
+type IndexUsage struct {
+	Table         string `json:"table"`
+	Name          string `json:"name"`
+	Definition    string `json:"definition"`
+	Scans         int64  `json:"scans"`
+	TuplesRead    int64  `json:"tuples_read"`
+	TuplesFetched int64  `json:"tuples_fetched"`
+	SizeBytes     int64  `json:"size_bytes"`
+}
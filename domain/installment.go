@@ -0,0 +1,191 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"math"
+	"slices"
+	"time"
+)
+
+var ErrInstallmentDescriptionEmpty = errors.New("installment purchase description cannot be empty")
+var ErrInvalidInstallmentAmount = errors.New("invalid installment purchase amount")
+var ErrInvalidInstallmentCount = errors.New("installment count must be between 1 and 360")
+var ErrInstallmentsPaid = errors.New("installment purchase has no remaining installments")
+
+const maxInstallments = 360
+
+// Installment is one monthly payment of an installment purchase, saved as an expenditure.
+// Installments dated in the future are planned expenditures until their date
+type Installment struct {
+	ExpenditureId uuid.UUID `json:"expenditure_id"`
+	Date          time.Time `json:"date"`
+	Amount        float64   `json:"amount"`
+}
+
+// InstallmentPurchase is a large purchase paid in monthly installments, such as a phone on a
+// payment plan. It keeps the schedule; each installment is an expenditure of its own
+type InstallmentPurchase struct {
+	ID           uuid.UUID     `json:"id"`
+	Description  string        `json:"description"`
+	TotalAmount  float64       `json:"total_amount"` // Sum of the installments
+	CategoryId   uuid.UUID     `json:"category_id"`
+	Tags         []string      `json:"tags"`
+	FirstDate    time.Time     `json:"first_date"` // Day of the first installment, the others follow monthly
+	Installments []Installment `json:"installments"`
+	PaidOffAt    *time.Time    `json:"paid_off_at,omitempty"` // When the remaining installments were paid at once
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+// InstallmentBalance is how far an installment purchase has been paid at a point in time
+type InstallmentBalance struct {
+	Paid           float64    `json:"paid"`
+	Remaining      float64    `json:"remaining"`
+	RemainingCount int        `json:"remaining_count"`
+	NextDate       *time.Time `json:"next_date,omitempty"`
+}
+
+// NewInstallmentPurchase splits the total amount into count monthly installments from the first
+// date on; the first date is truncated to a day in UTC
+func NewInstallmentPurchase(description string, totalAmount float64, count int, categoryId uuid.UUID, tags []string, firstDate time.Time) (*InstallmentPurchase, error) {
+	if description == "" {
+		return nil, ErrInstallmentDescriptionEmpty
+	}
+
+	if totalAmount <= 0 {
+		return nil, ErrInvalidInstallmentAmount
+	}
+
+	if count < 1 || count > maxInstallments {
+		return nil, ErrInvalidInstallmentCount
+	}
+
+	if tags == nil {
+		tags = []string{}
+	}
+
+	purchase := &InstallmentPurchase{
+		ID:          uuid.New(),
+		Description: description,
+		TotalAmount: round2(totalAmount),
+		CategoryId:  categoryId,
+		Tags:        tags,
+		FirstDate:   SpendingDay(firstDate),
+		CreatedAt:   time.Now(),
+	}
+	purchase.Installments = purchase.schedule(0, totalAmount, count)
+	return purchase, nil
+}
+
+// InstallmentDescription returns the description of the expenditure of the i-th installment,
+// counting from 0
+func (p *InstallmentPurchase) InstallmentDescription(i int) string {
+	return fmt.Sprintf("%s (installment %d)", p.Description, i+1)
+}
+
+// Balance returns what has been paid at now and what remains; installments count as paid once
+// their date has passed
+func (p *InstallmentPurchase) Balance(now time.Time) InstallmentBalance {
+	var balance InstallmentBalance
+	for _, installment := range p.Installments {
+		if installment.Date.After(now) {
+			if balance.NextDate == nil {
+				date := installment.Date
+				balance.NextDate = &date
+			}
+			balance.Remaining += installment.Amount
+			balance.RemainingCount++
+			continue
+		}
+		balance.Paid += installment.Amount
+	}
+	balance.Paid = round2(balance.Paid)
+	balance.Remaining = round2(balance.Remaining)
+	return balance
+}
+
+// Amend replaces the remaining installments with count new monthly ones splitting
+// remainingAmount, e.g. after renegotiating the plan. It returns the removed and the added
+// installments
+func (p *InstallmentPurchase) Amend(count int, remainingAmount float64, now time.Time) ([]Installment, []Installment, error) {
+	if count < 1 || count > maxInstallments {
+		return nil, nil, ErrInvalidInstallmentCount
+	}
+
+	if remainingAmount <= 0 {
+		return nil, nil, ErrInvalidInstallmentAmount
+	}
+
+	paid, removed := p.split(now)
+	if len(removed) == 0 {
+		return nil, nil, ErrInstallmentsPaid
+	}
+
+	added := p.schedule(len(paid), remainingAmount, count)
+	p.Installments = append(paid, added...)
+	p.TotalAmount = round2(p.Balance(now).Paid + remainingAmount)
+	return removed, added, nil
+}
+
+// PayOff replaces the remaining installments with a single one at now for their sum. It returns
+// the removed installments and the payoff
+func (p *InstallmentPurchase) PayOff(now time.Time) ([]Installment, Installment, error) {
+	paid, removed := p.split(now)
+	if len(removed) == 0 {
+		return nil, Installment{}, ErrInstallmentsPaid
+	}
+
+	var amount float64
+	for _, installment := range removed {
+		amount += installment.Amount
+	}
+
+	payoff := Installment{ExpenditureId: uuid.New(), Date: now, Amount: round2(amount)}
+	p.Installments = append(paid, payoff)
+	p.PaidOffAt = &now
+	return removed, payoff, nil
+}
+
+// split returns the installments paid at now and the remaining ones
+func (p *InstallmentPurchase) split(now time.Time) ([]Installment, []Installment) {
+	var paid, remaining []Installment
+	for _, installment := range p.Installments {
+		if installment.Date.After(now) {
+			remaining = append(remaining, installment)
+		} else {
+			paid = append(paid, installment)
+		}
+	}
+	return paid, remaining
+}
+
+// schedule splits amount into count monthly installments, the first being the from-th month after
+// the first date. Amounts are in cents; the last installment takes the rounding difference
+func (p *InstallmentPurchase) schedule(from int, amount float64, count int) []Installment {
+	cents := int64(math.Round(amount * 100))
+	each := cents / int64(count)
+
+	installments := make([]Installment, count)
+	for i := range installments {
+		share := each
+		if i == count-1 {
+			share = cents - each*int64(count-1)
+		}
+		installments[i] = Installment{
+			ExpenditureId: uuid.New(),
+			Date:          addMonths(p.FirstDate, from+i),
+			Amount:        float64(share) / 100,
+		}
+	}
+	return installments
+}
+
+// Clone returns a deep copy of the installment purchase
+func (p *InstallmentPurchase) Clone() *InstallmentPurchase {
+	clone := *p
+	clone.Tags = slices.Clone(p.Tags)
+	clone.Installments = slices.Clone(p.Installments)
+	clone.PaidOffAt = cloneTime(p.PaidOffAt)
+	return &clone
+}
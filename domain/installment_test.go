@@ -0,0 +1,80 @@
+package domain_test
+
+import (
+	"go-expense-tracker/domain"
+	"math"
+	"math/rand"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// installmentCents returns the sum of the installments of a purchase in cents
+func installmentCents(purchase *domain.InstallmentPurchase) int64 {
+	var cents int64
+	for _, installment := range purchase.Installments {
+		cents += int64(math.Round(installment.Amount * 100))
+	}
+	return cents
+}
+
+// TestInstallmentsSumToTotal checks that however a purchase is split, amended or paid off, its
+// installments add up to its total to the cent
+func TestInstallmentsSumToTotal(t *testing.T) {
+	first := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	config := &quick.Config{MaxCount: 500, Rand: rand.New(rand.NewSource(1))}
+
+	split := func(totalCents uint32, count uint16) bool {
+		total := float64(totalCents%10_000_000+1) / 100
+		n := int(count%360) + 1
+		purchase, err := domain.NewInstallmentPurchase("Laptop", total, n, uuid.New(), nil, first)
+		if err != nil {
+			t.Logf("NewInstallmentPurchase(%v, %d): %v", total, n, err)
+			return false
+		}
+		return len(purchase.Installments) == n && installmentCents(purchase) == int64(math.Round(total*100))
+	}
+	if err := quick.Check(split, config); err != nil {
+		t.Errorf("split: %v", err)
+	}
+
+	amend := func(totalCents, remainingCents uint32, count, newCount, paidMonths uint8) bool {
+		total := float64(totalCents%10_000_000+1) / 100
+		purchase, err := domain.NewInstallmentPurchase("Laptop", total, int(count%24)+2, uuid.New(), nil, first)
+		if err != nil {
+			return false
+		}
+		// Some installments are paid, at least one remains
+		now := first.AddDate(0, int(paidMonths)%(len(purchase.Installments)-1), 1)
+		paid := purchase.Balance(now).Paid
+		remaining := float64(remainingCents%10_000_000+1) / 100
+		if _, _, err := purchase.Amend(int(newCount%24)+1, remaining, now); err != nil {
+			t.Logf("Amend: %v", err)
+			return false
+		}
+		return installmentCents(purchase) == int64(math.Round((paid+remaining)*100)) &&
+			int64(math.Round(purchase.TotalAmount*100)) == installmentCents(purchase)
+	}
+	if err := quick.Check(amend, config); err != nil {
+		t.Errorf("amend: %v", err)
+	}
+
+	payOff := func(totalCents uint32, count, paidMonths uint8) bool {
+		total := float64(totalCents%10_000_000+1) / 100
+		purchase, err := domain.NewInstallmentPurchase("Laptop", total, int(count%24)+2, uuid.New(), nil, first)
+		if err != nil {
+			return false
+		}
+		now := first.AddDate(0, int(paidMonths)%(len(purchase.Installments)-1), 1)
+		if _, _, err := purchase.PayOff(now); err != nil {
+			t.Logf("PayOff: %v", err)
+			return false
+		}
+		return installmentCents(purchase) == int64(math.Round(total*100))
+	}
+	if err := quick.Check(payOff, config); err != nil {
+		t.Errorf("pay off: %v", err)
+	}
+}
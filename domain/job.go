@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var ErrJobNotFound = errors.New("job not found")
+var ErrJobNotCompleted = errors.New("job has not completed")
+
+// Job statuses
+const (
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+)
+
+// Job is a report or export running in the background because it was too large to be
+// served within a single request
+type Job struct {
+	ID          uuid.UUID  `json:"id"`
+	Kind        string     `json:"kind"`   // What is produced, e.g. "expenditures" or "location-report"
+	Status      string     `json:"status"` // running, completed or failed
+	Error       string     `json:"error,omitempty"`
+	Rows        int        `json:"rows"` // Expenditures the job reads
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+func NewJob(kind string, rows int) *Job {
+	return &Job{
+		ID:        uuid.New(),
+		Kind:      kind,
+		Status:    JobRunning,
+		Rows:      rows,
+		CreatedAt: time.Now(),
+	}
+}
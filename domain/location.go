@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidLatitude = errors.New("latitude must be between -90 and 90")
+var ErrInvalidLongitude = errors.New("longitude must be between -180 and 180")
+
+// Location is where an expenditure was made, as reported by a mobile client
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	PlaceName string  `json:"place_name,omitempty"` // e.g. "Blue Bottle Coffee"
+	City      string  `json:"city,omitempty"`
+}
+
+func NewLocation(latitude, longitude float64, placeName, city string) (*Location, error) {
+	if latitude < -90 || latitude > 90 {
+		return nil, ErrInvalidLatitude
+	}
+
+	if longitude < -180 || longitude > 180 {
+		return nil, ErrInvalidLongitude
+	}
+
+	return &Location{
+		Latitude:  latitude,
+		Longitude: longitude,
+		PlaceName: strings.TrimSpace(placeName),
+		City:      strings.TrimSpace(city),
+	}, nil
+}
@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrMerchantNameEmpty = errors.New("merchant name cannot be empty")
+
+// Merchant is a shop or service provider money is spent at. Expenditures are linked to a
+// merchant by matching their description against the merchant's name and aliases
+type Merchant struct {
+	ID                uuid.UUID `json:"id"`
+	Name              string    `json:"name"`                // Display name, e.g. "Amazon"
+	Aliases           []string  `json:"aliases"`             // Other spellings found on statements, e.g. "AMZN Mktp"
+	DefaultCategoryId uuid.UUID `json:"default_category_id"` // Category used when an expenditure has none, may be empty
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+func NewMerchant(name string, aliases []string, defaultCategoryId uuid.UUID) (*Merchant, error) {
+	merchant := &Merchant{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+	}
+	if err := merchant.Update(name, aliases, defaultCategoryId); err != nil {
+		return nil, err
+	}
+	return merchant, nil
+}
+
+func (m *Merchant) Update(name string, aliases []string, defaultCategoryId uuid.UUID) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrMerchantNameEmpty
+	}
+
+	// Drop aliases that normalize to nothing or to a spelling already known
+	seen := map[string]bool{NormalizeMerchantName(name): true}
+	kept := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		key := NormalizeMerchantName(alias)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		kept = append(kept, alias)
+	}
+
+	m.Name = name
+	m.Aliases = kept
+	m.DefaultCategoryId = defaultCategoryId
+	return nil
+}
+
+// merchantPrefixes are added by payment processors in front of the merchant name
+var merchantPrefixes = []string{"sq *", "sq*", "tst *", "tst*", "paypal *", "paypal*", "pp *", "pp*", "sumup *", "sumup*"}
+
+// merchantNoise are words that do not help to tell merchants apart
+var merchantNoise = map[string]bool{
+	"com": true, "www": true, "inc": true, "llc": true, "ltd": true, "gmbh": true, "co": true,
+	"us": true, "uk": true, "de": true, "eu": true, "pos": true, "purchase": true,
+}
+
+// NormalizeMerchantName reduces a statement description to a comparable merchant key, so
+// "AMZN Mktp US*2K4AB" and "Amzn Mktp US" both become "amzn mktp"
+func NormalizeMerchantName(description string) string {
+	s := strings.ToLower(strings.TrimSpace(description))
+
+	for _, prefix := range merchantPrefixes {
+		if strings.HasPrefix(s, prefix) {
+			s = s[len(prefix):]
+			break
+		}
+	}
+
+	// Processors append order and terminal references after a "*"
+	if i := strings.Index(s, "*"); i > 0 {
+		s = s[:i]
+	}
+
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&' && r != '\''
+	})
+
+	kept := make([]string, 0, len(words))
+	for _, word := range words {
+		// Store numbers, dates and references
+		if strings.IndexFunc(word, unicode.IsDigit) >= 0 || merchantNoise[word] {
+			continue
+		}
+		kept = append(kept, word)
+	}
+	return strings.Join(kept, " ")
+}
+
+// MatchMerchant finds the merchant a description belongs to: an exact match of the name or
+// an alias wins, then the longest name the description starts with, then the closest
+// spelling within a small edit distance. It returns nil when nothing matches
+func MatchMerchant(merchants []*Merchant, description string) *Merchant {
+	key := NormalizeMerchantName(description)
+	if key == "" {
+		return nil
+	}
+
+	var prefixMatch, fuzzyMatch *Merchant
+	prefixLen, fuzzyDistance := 0, 0
+
+	for _, merchant := range merchants {
+		for _, name := range append([]string{merchant.Name}, merchant.Aliases...) {
+			candidate := NormalizeMerchantName(name)
+			if candidate == "" {
+				continue
+			}
+
+			if candidate == key {
+				return merchant
+			}
+
+			if strings.HasPrefix(key, candidate+" ") && len(candidate) > prefixLen {
+				prefixMatch, prefixLen = merchant, len(candidate)
+				continue
+			}
+
+			if len(candidate) < 4 {
+				continue
+			}
+			distance := editDistance(key, candidate)
+			if distance <= len(candidate)/5 && (fuzzyMatch == nil || distance < fuzzyDistance) {
+				fuzzyMatch, fuzzyDistance = merchant, distance
+			}
+		}
+	}
+
+	if prefixMatch != nil {
+		return prefixMatch
+	}
+	return fuzzyMatch
+}
+
+// MerchantDisplayName turns a normalized key into a name for an automatically created merchant
+func MerchantDisplayName(key string) string {
+	words := strings.Fields(key)
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// Clone returns a deep copy of the merchant
+func (m *Merchant) Clone() *Merchant {
+	clone := *m
+	clone.Aliases = slices.Clone(m.Aliases)
+	return &clone
+}
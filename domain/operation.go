@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var ErrOperationNotFound = errors.New("operation not found")
+var ErrOperationAlreadyUndone = errors.New("operation was already undone")
+var ErrOperationUndoExpired = errors.New("operation can no longer be undone")
+var ErrOperationSuperseded = errors.New("only the last operation on an expenditure can be undone")
+
+// Operation kinds
+const (
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+)
+
+// Operation is an entry of the operation log, a change to an expenditure with the state before
+// and after it
+type Operation struct {
+	ID            uuid.UUID    `json:"id"`
+	Kind          string       `json:"kind"`           // create, update or delete
+	ExpenditureID uuid.UUID    `json:"expenditure_id"` // Expenditure that was changed
+	Before        *Expenditure `json:"before"`         // Nil for creates
+	After         *Expenditure `json:"after"`          // Nil for deletes
+	At            time.Time    `json:"at"`
+	UndoneAt      *time.Time   `json:"undone_at,omitempty"`
+}
+
+func NewOperation(kind string, expenditureID uuid.UUID, before, after *Expenditure) *Operation {
+	return &Operation{
+		ID:            uuid.New(),
+		Kind:          kind,
+		ExpenditureID: expenditureID,
+		Before:        before,
+		After:         after,
+		At:            time.Now(),
+	}
+}
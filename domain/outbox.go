@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var (
+	ErrOutboxMessageNotFound  = errors.New("outbox message not found")
+	ErrOutboxMessageNotFailed = errors.New("outbox message has not been given up on, it is still being delivered")
+)
+
+// Outbox topics
+const (
+	OutboxExpenditureCreated = "expenditure.created"
+	OutboxExpenditureUpdated = "expenditure.updated"
+	OutboxExpenditureDeleted = "expenditure.deleted"
+)
+
+// OutboxMessage is a change to an expenditure waiting to be published. It is written together
+// with the change itself, so a crash cannot lose it, and stays until it has been delivered
+type OutboxMessage struct {
+	ID            int64        `json:"id"` // Increases with every message, messages are delivered in this order
+	Topic         string       `json:"topic"`
+	ExpenditureID uuid.UUID    `json:"expenditure_id"`
+	Expenditure   *Expenditure `json:"expenditure"` // State after the change, before it for deletions
+	CreatedAt     time.Time    `json:"created_at"`
+	Attempts      int          `json:"attempts"`
+	NextAttemptAt time.Time    `json:"next_attempt_at"`
+	LastError     string       `json:"last_error,omitempty"`
+}
+
+// Clone returns a deep copy of the message, with a copy of the expenditure state
+func (m *OutboxMessage) Clone() *OutboxMessage {
+	clone := *m
+	if m.Expenditure != nil {
+		clone.Expenditure = m.Expenditure.Clone()
+	}
+	return &clone
+}
@@ -0,0 +1,244 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"slices"
+	"time"
+)
+
+var ErrRecurringDescriptionEmpty = errors.New("recurring expenditure description cannot be empty")
+var ErrInvalidRecurringAmount = errors.New("invalid recurring expenditure amount")
+var ErrInvalidRecurringFrequency = errors.New("recurring frequency must be weekly, monthly or yearly")
+var ErrRecurringEndBeforeStart = errors.New("recurring expenditure end date must not be before its start date")
+var ErrRecurringFinished = errors.New("recurring expenditure has no more occurrences")
+var ErrRecurringPauseInPast = errors.New("recurring expenditure can only be paused until a later date")
+
+// RecurringFrequency is how often a recurring expenditure occurs
+type RecurringFrequency string
+
+const (
+	RecurringWeekly  RecurringFrequency = "weekly"
+	RecurringMonthly RecurringFrequency = "monthly" // On the day of the start date, or the last day of shorter months
+	RecurringYearly  RecurringFrequency = "yearly"
+)
+
+// After returns the day one period of the frequency after day
+func (f RecurringFrequency) After(day time.Time) time.Time {
+	switch f {
+	case RecurringWeekly:
+		return day.AddDate(0, 0, 7)
+	case RecurringMonthly:
+		return addMonths(day, 1)
+	default:
+		return addMonths(day, 12)
+	}
+}
+
+// RecurringExpenditure is a payment that repeats, such as rent or a gym membership; an
+// expenditure is generated on each of its occurrences
+type RecurringExpenditure struct {
+	ID          uuid.UUID          `json:"id"`
+	Description string             `json:"description"`
+	Amount      float64            `json:"amount"`
+	CategoryId  uuid.UUID          `json:"category_id"` // Empty for the uncategorized category
+	Tags        []string           `json:"tags"`
+	Frequency   RecurringFrequency `json:"frequency"`
+	StartDate   time.Time          `json:"start_date"`         // Day of the first occurrence
+	EndDate     *time.Time         `json:"end_date,omitempty"` // Last day an occurrence may fall on, nil when it keeps recurring
+	Occurrences int                `json:"occurrences"`        // Occurrences generated or skipped so far
+	NextDate    *time.Time         `json:"next_date"`          // Day of the next occurrence, nil once finished
+	Paused      bool               `json:"paused"`
+	PausedUntil *time.Time         `json:"paused_until,omitempty"` // Day generation resumes on, nil when paused until resumed
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+func NewRecurringExpenditure(description string, amount float64, categoryId uuid.UUID, tags []string, frequency RecurringFrequency, startDate time.Time, endDate *time.Time) (*RecurringExpenditure, error) {
+	recurring := &RecurringExpenditure{ID: uuid.New(), CreatedAt: time.Now()}
+	if err := recurring.Update(description, amount, categoryId, tags, frequency, startDate, endDate); err != nil {
+		return nil, err
+	}
+	return recurring, nil
+}
+
+// Update changes the recurring expenditure and starts its occurrences over; the start and end
+// dates are truncated to days in UTC
+func (r *RecurringExpenditure) Update(description string, amount float64, categoryId uuid.UUID, tags []string, frequency RecurringFrequency, startDate time.Time, endDate *time.Time) error {
+	if description == "" {
+		return ErrRecurringDescriptionEmpty
+	}
+
+	if amount <= 0 {
+		return ErrInvalidRecurringAmount
+	}
+
+	switch frequency {
+	case RecurringWeekly, RecurringMonthly, RecurringYearly:
+	default:
+		return ErrInvalidRecurringFrequency
+	}
+
+	startDate = SpendingDay(startDate)
+	if endDate != nil {
+		end := SpendingDay(*endDate)
+		if end.Before(startDate) {
+			return ErrRecurringEndBeforeStart
+		}
+		endDate = &end
+	}
+
+	if tags == nil {
+		tags = []string{}
+	}
+
+	r.Description = description
+	r.Amount = amount
+	r.CategoryId = categoryId
+	r.Tags = tags
+	r.Frequency = frequency
+	r.StartDate = startDate
+	r.EndDate = endDate
+	r.Occurrences = 0
+	r.NextDate = r.occurrence(0)
+
+	return nil
+}
+
+// Upcoming returns the days of the next occurrences that will be generated, at most count. A
+// paused recurring expenditure has none until resumed, or only those from the day it resumes on
+func (r *RecurringExpenditure) Upcoming(count int) []time.Time {
+	upcoming := []time.Time{}
+	if r.Paused && r.PausedUntil == nil {
+		return upcoming
+	}
+
+	for i := r.Occurrences; len(upcoming) < count; i++ {
+		date := r.occurrence(i)
+		if date == nil {
+			break
+		}
+		if r.Paused && date.Before(*r.PausedUntil) {
+			continue
+		}
+		upcoming = append(upcoming, *date)
+	}
+	return upcoming
+}
+
+// Between returns the days of the occurrences still to be generated that fall in [from, to),
+// leaving out those skipped by a pause like Upcoming
+func (r *RecurringExpenditure) Between(from, to time.Time) []time.Time {
+	between := []time.Time{}
+	if r.Paused && r.PausedUntil == nil {
+		return between
+	}
+
+	for i := r.Occurrences; ; i++ {
+		date := r.occurrence(i)
+		if date == nil || !date.Before(to) {
+			break
+		}
+		if date.Before(from) || (r.Paused && date.Before(*r.PausedUntil)) {
+			continue
+		}
+		between = append(between, *date)
+	}
+	return between
+}
+
+// SkipNext moves past the next occurrence without generating it
+func (r *RecurringExpenditure) SkipNext() error {
+	if r.NextDate == nil {
+		return ErrRecurringFinished
+	}
+	r.advance()
+	return nil
+}
+
+// Pause stops generating occurrences until Resume, or until the day given when until is not nil.
+// Occurrences falling in the pause are skipped, not generated afterwards
+func (r *RecurringExpenditure) Pause(until *time.Time, now time.Time) error {
+	if until != nil {
+		day := SpendingDay(*until)
+		if !day.After(SpendingDay(now)) {
+			return ErrRecurringPauseInPast
+		}
+		until = &day
+	}
+
+	r.Paused = true
+	r.PausedUntil = until
+	return nil
+}
+
+// Resume generates occurrences again from now on, skipping the ones that fell in the pause
+func (r *RecurringExpenditure) Resume(now time.Time) {
+	r.Paused = false
+	r.PausedUntil = nil
+	for r.NextDate != nil && r.NextDate.Before(SpendingDay(now)) {
+		r.advance()
+	}
+}
+
+// Due returns the day of the next occurrence when it should be generated at now, and nil
+// otherwise. A pause that has ended is lifted first
+func (r *RecurringExpenditure) Due(now time.Time) *time.Time {
+	if r.Paused && r.PausedUntil != nil && !r.PausedUntil.After(now) {
+		r.Resume(*r.PausedUntil)
+	}
+	if r.Paused || r.NextDate == nil || r.NextDate.After(now) {
+		return nil
+	}
+	return r.NextDate
+}
+
+// OccurrenceID returns the ID of the expenditure generated on the next occurrence; it is the same
+// every time, so an occurrence is not generated twice
+func (r *RecurringExpenditure) OccurrenceID() uuid.UUID {
+	return uuid.NewSHA1(r.ID, []byte(r.NextDate.Format(time.DateOnly)))
+}
+
+// Generated moves past the next occurrence once its expenditure has been saved
+func (r *RecurringExpenditure) Generated() {
+	r.advance()
+}
+
+func (r *RecurringExpenditure) advance() {
+	r.Occurrences++
+	r.NextDate = r.occurrence(r.Occurrences)
+}
+
+// occurrence returns the day of the i-th occurrence, counting from 0, or nil after the end date
+func (r *RecurringExpenditure) occurrence(i int) *time.Time {
+	var date time.Time
+	switch r.Frequency {
+	case RecurringWeekly:
+		date = r.StartDate.AddDate(0, 0, 7*i)
+	case RecurringMonthly:
+		date = addMonths(r.StartDate, i)
+	default:
+		date = addMonths(r.StartDate, 12*i)
+	}
+
+	if r.EndDate != nil && date.After(*r.EndDate) {
+		return nil
+	}
+	return &date
+}
+
+// addMonths adds months to a day, keeping its day of the month unless the month is shorter
+func addMonths(day time.Time, months int) time.Time {
+	first := time.Date(day.Year(), day.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+	return time.Date(first.Year(), first.Month(), min(day.Day(), last), 0, 0, 0, 0, time.UTC)
+}
+
+// Clone returns a deep copy of the recurring expenditure
+func (r *RecurringExpenditure) Clone() *RecurringExpenditure {
+	clone := *r
+	clone.Tags = slices.Clone(r.Tags)
+	clone.EndDate = cloneTime(r.EndDate)
+	clone.NextDate = cloneTime(r.NextDate)
+	clone.PausedUntil = cloneTime(r.PausedUntil)
+	return &clone
+}
@@ -0,0 +1,199 @@
+package domain_test
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// newRecurring creates a recurring expenditure or fails the test
+func newRecurring(t *testing.T, frequency domain.RecurringFrequency, start time.Time, end *time.Time) *domain.RecurringExpenditure {
+	t.Helper()
+	recurring, err := domain.NewRecurringExpenditure("Gym", 30, uuid.New(), nil, frequency, start, end)
+	if err != nil {
+		t.Fatalf("creating recurring expenditure: %v", err)
+	}
+	return recurring
+}
+
+// dates formats days for failure messages
+func dates(days []time.Time) []string {
+	formatted := make([]string, len(days))
+	for i, d := range days {
+		formatted[i] = d.Format(time.DateOnly)
+	}
+	return formatted
+}
+
+func TestNewRecurringExpenditure(t *testing.T) {
+	start := day(2024, 3, 1)
+	before, same := day(2024, 2, 29), start.Add(15*time.Hour)
+	tests := []struct {
+		name        string
+		description string
+		amount      float64
+		frequency   domain.RecurringFrequency
+		end         *time.Time
+		wantErr     error
+	}{
+		{"open ended", "Gym", 30, domain.RecurringMonthly, nil, nil},
+		{"ends on its start day", "Gym", 30, domain.RecurringWeekly, &same, nil},
+		{"no description", "", 30, domain.RecurringMonthly, nil, domain.ErrRecurringDescriptionEmpty},
+		{"nothing", "Gym", 0, domain.RecurringMonthly, nil, domain.ErrInvalidRecurringAmount},
+		{"negative", "Gym", -30, domain.RecurringMonthly, nil, domain.ErrInvalidRecurringAmount},
+		{"daily", "Gym", 30, domain.RecurringFrequency("daily"), nil, domain.ErrInvalidRecurringFrequency},
+		{"ends before its start", "Gym", 30, domain.RecurringMonthly, &before, domain.ErrRecurringEndBeforeStart},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := domain.NewRecurringExpenditure(tt.description, tt.amount, uuid.New(), nil, tt.frequency, start, tt.end)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecurringUpcoming(t *testing.T) {
+	end := day(2024, 3, 20)
+	tests := []struct {
+		name      string
+		frequency domain.RecurringFrequency
+		start     time.Time
+		end       *time.Time
+		// prepare changes the recurring expenditure before the upcoming occurrences are listed
+		prepare func(t *testing.T, r *domain.RecurringExpenditure)
+		count   int
+		want    []time.Time
+	}{
+		{"monthly from a month end", domain.RecurringMonthly, day(2024, 1, 31), nil, nil, 5,
+			[]time.Time{day(2024, 1, 31), day(2024, 2, 29), day(2024, 3, 31), day(2024, 4, 30), day(2024, 5, 31)}},
+		{"monthly across a common February", domain.RecurringMonthly, day(2023, 1, 30), nil, nil, 3,
+			[]time.Time{day(2023, 1, 30), day(2023, 2, 28), day(2023, 3, 30)}},
+		{"yearly from a leap day", domain.RecurringYearly, day(2024, 2, 29), nil, nil, 5,
+			[]time.Time{day(2024, 2, 29), day(2025, 2, 28), day(2026, 2, 28), day(2027, 2, 28), day(2028, 2, 29)}},
+		{"weekly across the leap day", domain.RecurringWeekly, day(2024, 2, 22), nil, nil, 3,
+			[]time.Time{day(2024, 2, 22), day(2024, 2, 29), day(2024, 3, 7)}},
+		{"until the end date", domain.RecurringWeekly, day(2024, 3, 1), &end, nil, 10,
+			[]time.Time{day(2024, 3, 1), day(2024, 3, 8), day(2024, 3, 15)}},
+		{"on the end date", domain.RecurringMonthly, day(2024, 1, 20), &end, nil, 10,
+			[]time.Time{day(2024, 1, 20), day(2024, 2, 20), day(2024, 3, 20)}},
+		{"after skipping the next", domain.RecurringMonthly, day(2024, 1, 31), nil, func(t *testing.T, r *domain.RecurringExpenditure) {
+			if err := r.SkipNext(); err != nil {
+				t.Fatalf("skipping: %v", err)
+			}
+		}, 2, []time.Time{day(2024, 2, 29), day(2024, 3, 31)}},
+		{"paused until resumed", domain.RecurringMonthly, day(2024, 1, 31), nil, func(t *testing.T, r *domain.RecurringExpenditure) {
+			if err := r.Pause(nil, day(2024, 1, 1)); err != nil {
+				t.Fatalf("pausing: %v", err)
+			}
+		}, 3, []time.Time{}},
+		{"paused until a day", domain.RecurringMonthly, day(2024, 1, 31), nil, func(t *testing.T, r *domain.RecurringExpenditure) {
+			until := day(2024, 3, 1)
+			if err := r.Pause(&until, day(2024, 1, 1)); err != nil {
+				t.Fatalf("pausing: %v", err)
+			}
+		}, 2, []time.Time{day(2024, 3, 31), day(2024, 4, 30)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recurring := newRecurring(t, tt.frequency, tt.start, tt.end)
+			if tt.prepare != nil {
+				tt.prepare(t, recurring)
+			}
+			if got := recurring.Upcoming(tt.count); !slices.EqualFunc(got, tt.want, time.Time.Equal) {
+				t.Errorf("Upcoming = %v, want %v", dates(got), dates(tt.want))
+			}
+		})
+	}
+}
+
+func TestRecurringBetween(t *testing.T) {
+	recurring := newRecurring(t, domain.RecurringMonthly, day(2024, 1, 31), nil)
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     []time.Time
+	}{
+		{"a month", day(2024, 2, 1), day(2024, 3, 1), []time.Time{day(2024, 2, 29)}},
+		{"to is excluded", day(2024, 2, 1), day(2024, 2, 29), []time.Time{}},
+		{"from is included", day(2024, 1, 31), day(2024, 3, 1), []time.Time{day(2024, 1, 31), day(2024, 2, 29)}},
+		{"a quarter", day(2024, 4, 1), day(2024, 7, 1), []time.Time{day(2024, 4, 30), day(2024, 5, 31), day(2024, 6, 30)}},
+		{"before the start", day(2023, 1, 1), day(2024, 1, 1), []time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recurring.Between(tt.from, tt.to); !slices.EqualFunc(got, tt.want, time.Time.Equal) {
+				t.Errorf("Between = %v, want %v", dates(got), dates(tt.want))
+			}
+		})
+	}
+}
+
+func TestRecurringSkipAndPause(t *testing.T) {
+	end := day(2024, 3, 31)
+	recurring := newRecurring(t, domain.RecurringMonthly, day(2024, 1, 31), &end)
+
+	// The steps run in order against the same recurring expenditure
+	steps := []struct {
+		name    string
+		step    func() error
+		now     time.Time
+		wantErr error
+		// Day of the occurrence due at now, zero when none is due
+		wantDue time.Time
+	}{
+		{"first occurrence", nil, day(2024, 1, 31), nil, day(2024, 1, 31)},
+		{"not due yet", nil, day(2024, 1, 30), nil, time.Time{}},
+		{"skip the first", recurring.SkipNext, day(2024, 1, 31), nil, time.Time{}},
+		{"second occurrence", nil, day(2024, 2, 29), nil, day(2024, 2, 29)},
+		{"pause until the past", func() error {
+			until := day(2024, 2, 1)
+			return recurring.Pause(&until, day(2024, 2, 10))
+		}, day(2024, 2, 29), domain.ErrRecurringPauseInPast, day(2024, 2, 29)},
+		{"pause until March 15", func() error {
+			until := day(2024, 3, 15)
+			return recurring.Pause(&until, day(2024, 2, 10))
+		}, day(2024, 2, 29), nil, time.Time{}},
+		// The pause has ended; the occurrence in it is skipped
+		{"pause lifted", nil, day(2024, 3, 31), nil, day(2024, 3, 31)},
+		{"skip the last", recurring.SkipNext, day(2024, 3, 31), nil, time.Time{}},
+		{"skip when finished", recurring.SkipNext, day(2024, 4, 30), domain.ErrRecurringFinished, time.Time{}},
+	}
+
+	for _, step := range steps {
+		if step.step != nil {
+			if err := step.step(); !errors.Is(err, step.wantErr) {
+				t.Fatalf("%s: got %v, want %v", step.name, err, step.wantErr)
+			}
+		}
+		due := recurring.Due(step.now)
+		switch {
+		case step.wantDue.IsZero() && due != nil:
+			t.Errorf("%s: %s is due, want none", step.name, due.Format(time.DateOnly))
+		case !step.wantDue.IsZero() && (due == nil || !due.Equal(step.wantDue)):
+			t.Errorf("%s: due %v, want %s", step.name, due, step.wantDue.Format(time.DateOnly))
+		}
+	}
+
+	t.Run("resume", func(t *testing.T) {
+		recurring := newRecurring(t, domain.RecurringWeekly, day(2024, 1, 1), nil)
+		if err := recurring.Pause(nil, day(2024, 1, 1)); err != nil {
+			t.Fatalf("pausing: %v", err)
+		}
+		if due := recurring.Due(day(2024, 2, 1)); due != nil {
+			t.Errorf("due %s while paused, want none", due.Format(time.DateOnly))
+		}
+		recurring.Resume(day(2024, 2, 1))
+		if due := recurring.Due(day(2024, 2, 5)); due == nil || !due.Equal(day(2024, 2, 5)) {
+			t.Errorf("due %v after resuming, want the first occurrence from then on, 2024-02-05", due)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"math"
+	"time"
+)
+
+var ErrInvalidRefundAmount = errors.New("invalid refund amount")
+var ErrRefundExceedsOriginal = errors.New("refunds cannot exceed the original amount")
+var ErrRefundBeforeOriginal = errors.New("refund cannot be dated before the original expenditure")
+var ErrRefundOfCancelled = errors.New("cancelled expenditures cannot be refunded")
+var ErrRefundNotEditable = errors.New("refunds cannot be refunded, edited or duplicated; delete and record them again")
+var ErrExpenditureHasRefunds = errors.New("expenditure has refunds; delete them first")
+var ErrAmountBelowRefunded = errors.New("amount cannot be lower than the refunds recorded against it")
+
+// IsRefund reports whether the expenditure credits money back on another one
+func (e *Expenditure) IsRefund() bool {
+	return e.RefundOf != uuid.Nil
+}
+
+// RefundsWithin reports whether refunds totalling refunded, a positive sum, fit within an
+// original of amount. Storages check it again when they add a refund or change an original, in
+// the same transaction, so refunds recorded at the same time cannot together exceed it
+func RefundsWithin(amount, refunded float64) bool {
+	return math.Round(refunded*100) <= math.Round(amount*100)
+}
+
+// RefundedTotal returns the positive sum of the refunds of original among expenditures
+func RefundedTotal(expenditures []*Expenditure, original uuid.UUID) float64 {
+	var refunded float64
+	for _, expenditure := range expenditures {
+		if expenditure.RefundOf == original {
+			refunded -= expenditure.Amount
+		}
+	}
+	return refunded
+}
+
+// NewRefund returns a refund of amount on the expenditure, dated date at now; refunded is the sum of
+// its earlier refunds. Refunds are expenditures with a negative amount in the category of the
+// original, so reports net them against the spending. The tax is refunded in proportion
+func (e *Expenditure) NewRefund(amount float64, date time.Time, description string, refunded float64, now time.Time) (*Expenditure, error) {
+	if e.IsRefund() {
+		return nil, ErrRefundNotEditable
+	}
+
+	if e.IsCancelled() {
+		return nil, ErrRefundOfCancelled
+	}
+
+	if amount <= 0 {
+		return nil, ErrInvalidRefundAmount
+	}
+
+	if !RefundsWithin(e.Amount, refunded+amount) {
+		return nil, ErrRefundExceedsOriginal
+	}
+
+	if date.Before(e.Date) {
+		return nil, ErrRefundBeforeOriginal
+	}
+
+	if date.After(now) {
+		return nil, ErrExpenditureFutureDate
+	}
+
+	if description == "" {
+		description = "Refund: " + e.Description
+	}
+
+	refund := &Expenditure{
+		ID:          uuid.New(),
+		Description: description,
+		Amount:      -amount,
+		Date:        date,
+		CategoryId:  e.CategoryId,
+		Tags:        append([]string{}, e.Tags...),
+		TaxRate:     e.TaxRate,
+		TaxAmount:   -round2(e.TaxAmount * amount / e.Amount),
+		MerchantId:  e.MerchantId,
+		AccountId:   e.AccountId,
+		RefundOf:    e.ID,
+		Status:      StatusCleared,
+	}
+
+	// A foreign purchase is refunded in its currency in proportion, at the rate it was paid at
+	if e.IsForeign() {
+		refund.OriginalAmount = -round2(e.OriginalAmount * amount / e.Amount)
+		refund.OriginalCurrency = e.OriginalCurrency
+	}
+	return refund, nil
+}
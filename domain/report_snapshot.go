@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"slices"
+	"sort"
+	"time"
+)
+
+var ErrReportSnapshotNotFound = errors.New("report snapshot not found")
+
+// ReportSnapshot is the monthly report as it was computed at one point in time, usually when the
+// month closed. Later edits to the expenditures of the month do not change it
+type ReportSnapshot struct {
+	ID         uuid.UUID          `json:"id"`
+	Month      string             `json:"month"` // Fiscal month, YYYY-MM
+	From       time.Time          `json:"from"`
+	To         time.Time          `json:"to"` // Day after the month
+	TakenAt    time.Time          `json:"taken_at"`
+	Count      int                `json:"count"`
+	Total      float64            `json:"total"`
+	Categories []SnapshotCategory `json:"categories"`
+}
+
+// SnapshotCategory is the spending in one category at the time of a snapshot. The name is kept
+// so the snapshot still reads the same after the category is renamed or merged
+type SnapshotCategory struct {
+	CategoryId uuid.UUID `json:"category_id"` // Empty for uncategorized spending
+	Name       string    `json:"name"`
+	Count      int       `json:"count"`
+	Total      float64   `json:"total"`
+}
+
+// ReportSnapshotDiff compares two versions of a monthly report
+type ReportSnapshotDiff struct {
+	Month       string         `json:"month"`
+	BaseID      uuid.UUID      `json:"base_id"`
+	BaseTakenAt time.Time      `json:"base_taken_at"`
+	OtherID     uuid.UUID      `json:"other_id"` // Empty when compared with the current figures
+	TakenAt     time.Time      `json:"taken_at"`
+	CountChange int            `json:"count_change"`
+	TotalChange float64        `json:"total_change"`
+	Categories  []CategoryDiff `json:"categories"` // Only the categories that changed
+}
+
+// CategoryDiff is the change of the spending in one category between two snapshots
+type CategoryDiff struct {
+	CategoryId  uuid.UUID `json:"category_id"`
+	Name        string    `json:"name"`
+	Before      float64   `json:"before"`
+	After       float64   `json:"after"`
+	Change      float64   `json:"change"`
+	CountChange int       `json:"count_change"`
+}
+
+// NewReportSnapshot summarizes the spending of the month [from, to) per category; names maps the
+// IDs of the categories to their names
+func NewReportSnapshot(month string, from, to time.Time, spending []*DailySpending, names map[uuid.UUID]string) *ReportSnapshot {
+	snapshot := &ReportSnapshot{
+		ID:         uuid.New(),
+		Month:      month,
+		From:       from,
+		To:         to,
+		TakenAt:    time.Now(),
+		Categories: []SnapshotCategory{},
+	}
+
+	byCategory := make(map[uuid.UUID]*SnapshotCategory)
+	var order []uuid.UUID
+	for _, day := range spending {
+		if day.Day.Before(from) || !day.Day.Before(to) {
+			continue
+		}
+		category, ok := byCategory[day.CategoryId]
+		if !ok {
+			category = &SnapshotCategory{CategoryId: day.CategoryId, Name: names[day.CategoryId]}
+			byCategory[day.CategoryId] = category
+			order = append(order, day.CategoryId)
+		}
+		category.Count += day.Count
+		category.Total += day.Total
+		snapshot.Count += day.Count
+		snapshot.Total += day.Total
+	}
+
+	for _, id := range order {
+		category := *byCategory[id]
+		category.Total = round2(category.Total)
+		snapshot.Categories = append(snapshot.Categories, category)
+	}
+	sort.Slice(snapshot.Categories, func(i, j int) bool {
+		return snapshot.Categories[i].Total > snapshot.Categories[j].Total
+	})
+	snapshot.Total = round2(snapshot.Total)
+	return snapshot
+}
+
+// Diff compares the snapshot with another version of the same month
+func (s *ReportSnapshot) Diff(other *ReportSnapshot) ReportSnapshotDiff {
+	diff := ReportSnapshotDiff{
+		Month:       s.Month,
+		BaseID:      s.ID,
+		BaseTakenAt: s.TakenAt,
+		OtherID:     other.ID,
+		TakenAt:     other.TakenAt,
+		CountChange: other.Count - s.Count,
+		TotalChange: round2(other.Total - s.Total),
+		Categories:  []CategoryDiff{},
+	}
+
+	changes := make(map[uuid.UUID]*CategoryDiff)
+	var order []uuid.UUID
+	change := func(category SnapshotCategory, after bool) {
+		entry, ok := changes[category.CategoryId]
+		if !ok {
+			entry = &CategoryDiff{CategoryId: category.CategoryId, Name: category.Name}
+			changes[category.CategoryId] = entry
+			order = append(order, category.CategoryId)
+		}
+		if after {
+			entry.After = category.Total
+			entry.CountChange += category.Count
+			entry.Name = category.Name
+		} else {
+			entry.Before = category.Total
+			entry.CountChange -= category.Count
+		}
+	}
+	for _, category := range s.Categories {
+		change(category, false)
+	}
+	for _, category := range other.Categories {
+		change(category, true)
+	}
+
+	for _, id := range order {
+		entry := changes[id]
+		entry.Change = round2(entry.After - entry.Before)
+		if entry.Change != 0 || entry.CountChange != 0 {
+			diff.Categories = append(diff.Categories, *entry)
+		}
+	}
+	return diff
+}
+
+// Clone returns a deep copy of the report snapshot
+func (s *ReportSnapshot) Clone() *ReportSnapshot {
+	clone := *s
+	clone.Categories = slices.Clone(s.Categories)
+	return &clone
+}
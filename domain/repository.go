@@ -1,10 +1,21 @@
 package domain
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
 
 var ErrExpenditureAlreadyExists = errors.New("expenditure already exists")
 var ErrExpenditureNotFound = errors.New("expenditure not found")
 
+// ExpenditureRepository stores expenditures. Like every repository of this package, it owns
+// what it stores: it keeps a copy of the domain objects it receives and hands out copies, so
+// callers may change what they passed in or got back without affecting the storage or other
+// callers. Storages that serialize, such as the SQL and file ones, copy by nature; in-memory
+// ones copy with the Clone methods. storagetest.SharesMemory catches implementations that don't
 type ExpenditureRepository interface {
 	AddExpenditure(expenditure *Expenditure) error
 	GetExpenditureByID(id string) (*Expenditure, error)
@@ -13,9 +24,469 @@ type ExpenditureRepository interface {
 	DeleteExpenditure(id string) error
 }
 
+// BulkExpenditureRepository is implemented by storages with a fast path for adding many
+// expenditures at once, e.g. when importing a bank statement
+type BulkExpenditureRepository interface {
+	// AddExpenditures adds all expenditures or, when one fails, none of them
+	AddExpenditures(expenditures []*Expenditure) error
+}
+
+// AddExpenditures adds the expenditures to repo, in bulk when the storage supports it. Without
+// bulk support they are added one by one and those before a failure stay added
+func AddExpenditures(repo ExpenditureRepository, expenditures []*Expenditure) error {
+	if bulk, ok := repo.(BulkExpenditureRepository); ok {
+		return bulk.AddExpenditures(expenditures)
+	}
+
+	for _, expenditure := range expenditures {
+		if err := repo.AddExpenditure(expenditure); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExpenditureStreamer is implemented by storages that can hand out expenditures one at a time,
+// so large listings are never loaded as a whole
+type ExpenditureStreamer interface {
+	// StreamExpenditures calls fn for every expenditure and stops at the first error fn returns
+	StreamExpenditures(fn func(*Expenditure) error) error
+}
+
+// EachExpenditure calls fn for every expenditure of repo, streaming when the storage supports it
+func EachExpenditure(repo ExpenditureRepository, fn func(*Expenditure) error) error {
+	if streamer, ok := repo.(ExpenditureStreamer); ok {
+		return streamer.StreamExpenditures(fn)
+	}
+
+	expenditures, err := repo.GetAllExpenditures()
+	if err != nil {
+		return err
+	}
+	for _, expenditure := range expenditures {
+		if err := fn(expenditure); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExpenditurePager is implemented by storages that can read one page of the expenditure listing
+// without going through the expenditures before it
+type ExpenditurePager interface {
+	GetExpenditurePage(query ExpenditurePageQuery) ([]*Expenditure, error)
+}
+
+// GetExpenditurePage returns one page of the expenditures of repo, ordered newest first, reading
+// all of them when the storage cannot seek to the page itself
+func GetExpenditurePage(repo ExpenditureRepository, query ExpenditurePageQuery) ([]*Expenditure, error) {
+	if pager, ok := repo.(ExpenditurePager); ok {
+		return pager.GetExpenditurePage(query)
+	}
+
+	var matching []*Expenditure
+	err := EachExpenditure(repo, func(expenditure *Expenditure) error {
+		if !query.From.IsZero() && expenditure.Date.Before(query.From) {
+			return nil
+		}
+		if !query.To.IsZero() && !expenditure.Date.Before(query.To) {
+			return nil
+		}
+		if query.CategoryId != uuid.Nil && expenditure.CategoryId != query.CategoryId {
+			return nil
+		}
+		if query.After != nil && query.After.Precedes(expenditure) {
+			return nil
+		}
+		matching = append(matching, expenditure)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	SortExpenditures(matching)
+	if len(matching) > query.Limit {
+		matching = matching[:query.Limit]
+	}
+	return matching, nil
+}
+
+// ExpenditureCounter is implemented by storages that can count expenditures without reading them
+type ExpenditureCounter interface {
+	// CountExpenditures counts the expenditures dated within [from, to); zero bounds are open
+	CountExpenditures(from, to time.Time) (int, error)
+}
+
+// CountExpenditures counts the expenditures of repo dated within [from, to), reading them only
+// when the storage cannot count them itself
+func CountExpenditures(repo ExpenditureRepository, from, to time.Time) (int, error) {
+	if counter, ok := repo.(ExpenditureCounter); ok {
+		return counter.CountExpenditures(from, to)
+	}
+
+	count := 0
+	err := EachExpenditure(repo, func(expenditure *Expenditure) error {
+		if (from.IsZero() || !expenditure.Date.Before(from)) && (to.IsZero() || expenditure.Date.Before(to)) {
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// ExpenditureWrapper is embedded by repositories wrapping another one to add to some of its
+// methods, such as the operation log or the activity feed. Embedding the wrapped repository
+// alone would hide what the storage implements beyond ExpenditureRepository, so the wrapper
+// passes pages, counts and streams on to it
+type ExpenditureWrapper struct {
+	ExpenditureRepository
+}
+
+// GetExpenditurePage keeps keyset pagination available through the wrapper
+func (w ExpenditureWrapper) GetExpenditurePage(query ExpenditurePageQuery) ([]*Expenditure, error) {
+	return GetExpenditurePage(w.ExpenditureRepository, query)
+}
+
+// CountExpenditures keeps counting in the storage available through the wrapper
+func (w ExpenditureWrapper) CountExpenditures(from, to time.Time) (int, error) {
+	return CountExpenditures(w.ExpenditureRepository, from, to)
+}
+
+// StreamExpenditures keeps streaming available through the wrapper
+func (w ExpenditureWrapper) StreamExpenditures(fn func(*Expenditure) error) error {
+	return EachExpenditure(w.ExpenditureRepository, fn)
+}
+
 var ErrCategoryNotFound = errors.New("category not found")
+var ErrCategoryAlreadyExists = errors.New("category already exists")
 
 type CategoryRepository interface {
+	AddCategory(category *Category) error
 	GetCategoryByID(id string) (*Category, error)
 	GetAllCategories() ([]*Category, error)
+	UpdateCategory(category *Category) error
+	// ReorderCategories applies OrderCategories to the stored categories
+	ReorderCategories(ids []uuid.UUID) error
+	// MergeCategory moves everything referring to source over to target and deletes source, all
+	// or nothing; a dry run only counts the records that would move
+	MergeCategory(source, target uuid.UUID, dryRun bool) (*CategoryMerge, error)
+}
+
+// EnsureCategory returns the category with the given name, ignoring case, and creates it with
+// the given color when there is none
+func EnsureCategory(repo CategoryRepository, name, color string) (*Category, error) {
+	categories, err := repo.GetAllCategories()
+	if err != nil {
+		return nil, err
+	}
+	for _, category := range categories {
+		if strings.EqualFold(category.Name, name) {
+			return category, nil
+		}
+	}
+
+	category, err := NewCategory(name, color)
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.AddCategory(category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+var ErrStagedExpenditureNotFound = errors.New("staged expenditure not found")
+var ErrStagedExpenditureAlreadyExists = errors.New("staged expenditure already exists")
+
+type ImportRepository interface {
+	AddStagedExpenditure(staged *StagedExpenditure) error
+	GetStagedExpenditureByID(id string) (*StagedExpenditure, error)
+	GetAllStagedExpenditures() ([]*StagedExpenditure, error)
+	UpdateStagedExpenditure(staged *StagedExpenditure) error
+}
+
+var ErrBankConnectionNotFound = errors.New("bank connection not found")
+var ErrBankConnectionAlreadyExists = errors.New("bank connection already exists")
+
+type BankConnectionRepository interface {
+	AddBankConnection(connection *BankConnection) error
+	GetBankConnectionByID(id string) (*BankConnection, error)
+	GetAllBankConnections() ([]*BankConnection, error)
+	UpdateBankConnection(connection *BankConnection) error
+	DeleteBankConnection(id string) error
+}
+
+var ErrGoalNotFound = errors.New("goal not found")
+var ErrGoalAlreadyExists = errors.New("goal already exists")
+
+type GoalRepository interface {
+	AddGoal(goal *Goal) error
+	GetGoalByID(id string) (*Goal, error)
+	GetAllGoals() ([]*Goal, error)
+	UpdateGoal(goal *Goal) error
+	DeleteGoal(id string) error
+}
+
+var ErrBudgetNotFound = errors.New("budget not found")
+
+type BudgetRepository interface {
+	AddBudget(budget *Budget) error
+	GetBudgetByID(id string) (*Budget, error)
+	GetAllBudgets() ([]*Budget, error)
+	UpdateBudget(budget *Budget) error
+	DeleteBudget(id string) error
+}
+
+var ErrInstallmentPurchaseNotFound = errors.New("installment purchase not found")
+
+// InstallmentRepository is implemented by storages that can keep installment purchases
+type InstallmentRepository interface {
+	AddInstallmentPurchase(purchase *InstallmentPurchase) error
+	GetInstallmentPurchaseByID(id string) (*InstallmentPurchase, error)
+	GetAllInstallmentPurchases() ([]*InstallmentPurchase, error)
+	UpdateInstallmentPurchase(purchase *InstallmentPurchase) error
+}
+
+var ErrAccountNotFound = errors.New("account not found")
+
+// AccountRepository is implemented by storages that can keep accounts
+type AccountRepository interface {
+	AddAccount(account *Account) error
+	GetAccountByID(id string) (*Account, error)
+	GetAllAccounts() ([]*Account, error)
+	UpdateAccount(account *Account) error
+}
+
+var ErrRecurringNotFound = errors.New("recurring expenditure not found")
+
+// RecurringRepository is implemented by storages that can keep recurring expenditures
+type RecurringRepository interface {
+	AddRecurring(recurring *RecurringExpenditure) error
+	GetRecurringByID(id string) (*RecurringExpenditure, error)
+	GetAllRecurring() ([]*RecurringExpenditure, error)
+	UpdateRecurring(recurring *RecurringExpenditure) error
+	DeleteRecurring(id string) error
+}
+
+// HouseholdRepository is implemented by storages that can keep households and their invitations
+type HouseholdRepository interface {
+	AddHousehold(household *Household) error
+	GetHouseholdByID(id string) (*Household, error)
+	// GetAllHouseholds returns the households, oldest first
+	GetAllHouseholds() ([]*Household, error)
+	// GetHouseholdMember returns a member of any household, failing with ErrMemberNotFound
+	GetHouseholdMember(id string) (*HouseholdMember, error)
+	// SetHouseholdMemberAllowance sets the category whose envelope holds a member's allowance,
+	// failing with ErrMemberNotFound
+	SetHouseholdMemberAllowance(id string, categoryId uuid.UUID) error
+	AddInvitation(invitation *Invitation) error
+	GetInvitationByID(id string) (*Invitation, error)
+	GetInvitationByTokenHash(hash string) (*Invitation, error)
+	// GetInvitations returns the invitations to a household, newest first
+	GetInvitations(householdId string) ([]*Invitation, error)
+	// RevokeInvitation saves a revoked invitation, failing with ErrInvitationAccepted when it was
+	// accepted meanwhile
+	RevokeInvitation(invitation *Invitation) error
+	// AcceptInvitation saves an accepted invitation and adds the member to its household in one
+	// step, failing with ErrInvitationAccepted or ErrInvitationRevoked when it is no longer pending
+	AcceptInvitation(invitation *Invitation, member *HouseholdMember) error
+	// RemoveHouseholdMember removes a member from their household, and the household with its
+	// invitations when they were its last member. The email of the invitation they joined with is
+	// cleared. Fails with ErrMemberNotFound
+	RemoveHouseholdMember(id string) error
+}
+
+// ReportSnapshotRepository is implemented by storages that can keep report snapshots
+type ReportSnapshotRepository interface {
+	AddReportSnapshot(snapshot *ReportSnapshot) error
+	GetReportSnapshotByID(id string) (*ReportSnapshot, error)
+	// GetAllReportSnapshots returns the snapshots, latest month first and then latest taken first
+	GetAllReportSnapshots() ([]*ReportSnapshot, error)
+}
+
+// EnvelopeRepository is implemented by storages that can keep envelope allocations
+type EnvelopeRepository interface {
+	// AddEnvelopeAllocations adds all allocations or, when one fails, none of them
+	AddEnvelopeAllocations(allocations []*EnvelopeAllocation) error
+	// GetAllEnvelopeAllocations returns the allocations, oldest first
+	GetAllEnvelopeAllocations() ([]*EnvelopeAllocation, error)
+}
+
+var ErrExpenseReportNotFound = errors.New("expense report not found")
+var ErrExpenseReportAlreadyExists = errors.New("expense report already exists")
+
+type ExpenseReportRepository interface {
+	AddExpenseReport(report *ExpenseReport) error
+	GetExpenseReportByID(id string) (*ExpenseReport, error)
+	GetAllExpenseReports() ([]*ExpenseReport, error)
+	UpdateExpenseReport(report *ExpenseReport) error
+	DeleteExpenseReport(id string) error
+}
+
+var ErrMerchantNotFound = errors.New("merchant not found")
+var ErrMerchantAlreadyExists = errors.New("merchant already exists")
+
+type MerchantRepository interface {
+	AddMerchant(merchant *Merchant) error
+	GetMerchantByID(id string) (*Merchant, error)
+	GetAllMerchants() ([]*Merchant, error)
+	UpdateMerchant(merchant *Merchant) error
+	DeleteMerchant(id string) error
+}
+
+var ErrRuleNotFound = errors.New("rule not found")
+var ErrRuleAlreadyExists = errors.New("rule already exists")
+
+type CategorizationRuleRepository interface {
+	AddRule(rule *CategorizationRule) error
+	GetRuleByID(id string) (*CategorizationRule, error)
+	// GetAllRules returns the rules in evaluation order, oldest first
+	GetAllRules() ([]*CategorizationRule, error)
+	UpdateRule(rule *CategorizationRule) error
+	DeleteRule(id string) error
+}
+
+// SpendingSummaryRepository is implemented by storages that keep the spending per day and
+// category up to date as expenditures change, so reports need not aggregate every expenditure
+type SpendingSummaryRepository interface {
+	// GetDailySpending returns the summaries of the days in [from, to); zero bounds are open
+	GetDailySpending(from, to time.Time) ([]*DailySpending, error)
+	// RefreshSpendingSummaries rebuilds all summaries from the expenditures
+	RefreshSpendingSummaries() error
+}
+
+var ErrArchiveNotFound = errors.New("archive not found")
+
+type ArchiveRepository interface {
+	// ArchiveExpenditures moves every expenditure dated before the cutoff into a new archive,
+	// with the refunds of those expenditures dated later, returning ErrNothingToArchive when
+	// there is none
+	ArchiveExpenditures(before time.Time) (*Archive, error)
+	GetArchiveByID(id string) (*Archive, error)
+	GetAllArchives() ([]*Archive, error)
+	GetArchivedExpenditures(archiveID string) ([]*Expenditure, error)
+	// DeleteArchive deletes an archive with its expenditures, failing with ErrArchiveNotFound
+	DeleteArchive(id string) error
+	// ScrubArchivedExpenditures removes the member from the archived expenditures they recorded,
+	// or with erase deletes those expenditures. The counts and totals of the archives keep them.
+	// Returns the number of expenditures changed
+	ScrubArchivedExpenditures(member uuid.UUID, erase bool) (int, error)
+}
+
+// RetentionRepository is implemented by storages that can keep the audit log of retention purges
+type RetentionRepository interface {
+	AddRetentionPurge(purge *RetentionPurge) error
+	// GetAllRetentionPurges returns the purges, latest first
+	GetAllRetentionPurges() ([]*RetentionPurge, error)
+}
+
+// IndexStatsRepository is implemented by storages that can report the usage of their indexes
+type IndexStatsRepository interface {
+	GetIndexUsage() ([]*IndexUsage, error)
+}
+
+// DraftRepository is implemented by storages that can keep expenditure drafts
+type DraftRepository interface {
+	AddDraft(draft *Draft) error
+	GetDraftByID(id string) (*Draft, error)
+	// GetAllDrafts returns the drafts, oldest first
+	GetAllDrafts() ([]*Draft, error)
+	DeleteDraft(id string) error
+}
+
+type ViewRepository interface {
+	AddView(view *View) error
+	GetViewByID(id string) (*View, error)
+	GetAllViews() ([]*View, error)
+	UpdateView(view *View) error
+	DeleteView(id string) error
+}
+
+// PinRepository is implemented by storages that can star expenditures
+type PinRepository interface {
+	// PinExpenditure stars an expenditure; pinning it again is not an error
+	PinExpenditure(id uuid.UUID) error
+	// UnpinExpenditure removes the star; unpinning an expenditure that is not pinned is not an error
+	UnpinExpenditure(id uuid.UUID) error
+	// GetPinnedExpenditureIDs returns the pinned expenditures, most recently pinned first
+	GetPinnedExpenditureIDs() ([]uuid.UUID, error)
+}
+
+// ExpenditureEventStore is implemented by storages that can keep an append-only log of the
+// changes to expenditures, used by event-sourced storage
+type ExpenditureEventStore interface {
+	// AppendExpenditureEvents appends all events, assigning their sequence numbers, or none of them
+	AppendExpenditureEvents(events []*ExpenditureEvent) error
+	// GetExpenditureEvents returns up to limit events after the given sequence number, oldest
+	// first; a limit of 0 returns all of them
+	GetExpenditureEvents(after int64, limit int) ([]*ExpenditureEvent, error)
+	// GetExpenditureHistory returns the events of one expenditure, oldest first
+	GetExpenditureHistory(id uuid.UUID) ([]*ExpenditureEvent, error)
+	// ScrubExpenditureEvents removes personal data from the log, the one change made to past
+	// events: the states of the erased expenditures are dropped, and the member is removed from the
+	// states of the others they recorded. Returns the number of events changed
+	ScrubExpenditureEvents(member uuid.UUID, erased []uuid.UUID) (int, error)
+}
+
+// OutboxRepository is implemented by storages that can write an outbox message in the same
+// transaction as every change to an expenditure
+type OutboxRepository interface {
+	// EnableOutbox starts or stops writing outbox messages
+	EnableOutbox(enabled bool) error
+	// GetPendingOutboxMessages returns up to limit undelivered messages, oldest first, leaving out
+	// those given up on
+	GetPendingOutboxMessages(limit int) ([]*OutboxMessage, error)
+	MarkOutboxMessageDelivered(id int64) error
+	// MarkOutboxMessageFailed records a failed attempt; a zero retryAt gives up on the message
+	MarkOutboxMessageFailed(id int64, reason string, retryAt time.Time) error
+	// GetFailedOutboxMessages returns up to limit messages given up on, oldest first
+	GetFailedOutboxMessages(limit int) ([]*OutboxMessage, error)
+	// RedeliverOutboxMessage queues a message given up on for delivery again, with a fresh count
+	// of attempts; ErrOutboxMessageNotFailed for a message still being delivered
+	RedeliverOutboxMessage(id int64) error
+	// ScrubOutboxMessages removes the member from the expenditure states of the messages not
+	// delivered yet, returning the number of messages changed
+	ScrubOutboxMessages(member uuid.UUID) (int, error)
+}
+
+// LeaderLock is held by one replica of the server at a time, see LeaderLocker
+type LeaderLock interface {
+	// Check returns an error once the lock may have been lost, e.g. with the connection holding it
+	Check(ctx context.Context) error
+	Release() error
+}
+
+// LeaderLocker is implemented by storages several replicas of the server can share, so the
+// background subsystems run on one replica only
+type LeaderLocker interface {
+	// TryLeaderLock takes the lock of name, or returns nil when another replica holds it
+	TryLeaderLock(ctx context.Context, name string) (LeaderLock, error)
+}
+
+// ErrStorageUnavailable is returned while the storage cannot be reached, see HealthChecker
+var ErrStorageUnavailable = errors.New("storage is unavailable, try again later")
+
+// HealthChecker is implemented by storages behind a connection that can drop, such as a
+// database server, so the storage circuit breaker can probe whether they are back
+type HealthChecker interface {
+	Ping(ctx context.Context) error
+	// IsUnavailable tells errors of a storage that could not be reached, such as a dropped
+	// connection, from those it answered with, such as a missing expenditure
+	IsUnavailable(err error) bool
+}
+
+// TransientErrorChecker is implemented by storages whose calls can fail for a moment, such as on
+// a dropped connection or a serialization failure, and succeed when made again
+type TransientErrorChecker interface {
+	IsTransient(err error) bool
+}
+
+// cloneTime returns a copy of an optional time, for the Clone methods of domain objects
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
 }
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRetentionRule = errors.New("retention rules are target=age, e.g. expenditures=7y or drafts=90d")
+
+// RetentionTarget is the kind of records a retention rule purges
+type RetentionTarget string
+
+const (
+	RetainExpenditures RetentionTarget = "expenditures" // Live expenditures, by their date
+	RetainArchives     RetentionTarget = "archives"     // Archival runs with their expenditures, by their cutoff
+	RetainDrafts       RetentionTarget = "drafts"       // Drafts, by when they were saved
+)
+
+// RetentionRule purges the records of its target once they are older than its age
+type RetentionRule struct {
+	Target RetentionTarget `json:"target"`
+	Age    string          `json:"age"` // As configured, e.g. "7y", "18m", "6w" or "90d"
+	years  int
+	months int
+	days   int
+}
+
+// ParseRetentionRules reads comma-separated rules such as "expenditures=7y,drafts=90d". Ages are
+// a number of years (y), months (m), weeks (w) or days (d); a target can have one rule
+func ParseRetentionRules(config string) ([]RetentionRule, error) {
+	var rules []RetentionRule
+	for _, entry := range strings.Split(config, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+
+		target, age, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, ErrInvalidRetentionRule
+		}
+		rule, err := NewRetentionRule(RetentionTarget(strings.TrimSpace(target)), strings.TrimSpace(age))
+		if err != nil {
+			return nil, err
+		}
+		if slices.ContainsFunc(rules, func(other RetentionRule) bool { return other.Target == rule.Target }) {
+			return nil, fmt.Errorf("%w: %s has more than one rule", ErrInvalidRetentionRule, rule.Target)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// NewRetentionRule creates a rule purging the records of target older than age
+func NewRetentionRule(target RetentionTarget, age string) (RetentionRule, error) {
+	switch target {
+	case RetainExpenditures, RetainArchives, RetainDrafts:
+	default:
+		return RetentionRule{}, fmt.Errorf("%w: unknown target %q", ErrInvalidRetentionRule, target)
+	}
+
+	if len(age) < 2 {
+		return RetentionRule{}, ErrInvalidRetentionRule
+	}
+	n, err := strconv.Atoi(age[:len(age)-1])
+	if err != nil || n < 1 {
+		return RetentionRule{}, ErrInvalidRetentionRule
+	}
+
+	rule := RetentionRule{Target: target, Age: age}
+	switch age[len(age)-1] {
+	case 'y':
+		rule.years = n
+	case 'm':
+		rule.months = n
+	case 'w':
+		rule.days = 7 * n
+	case 'd':
+		rule.days = n
+	default:
+		return RetentionRule{}, ErrInvalidRetentionRule
+	}
+	return rule, nil
+}
+
+// Cutoff returns the time before which records are purged at now
+func (r RetentionRule) Cutoff(now time.Time) time.Time {
+	return now.AddDate(-r.years, -r.months, -r.days)
+}
+
+// RetentionPurge is the audit entry of records removed by a retention rule. Previews of the next
+// run have the same shape but no ID and time
+type RetentionPurge struct {
+	ID       uuid.UUID       `json:"id,omitzero"`
+	Target   RetentionTarget `json:"target"`
+	Age      string          `json:"age"`    // Of the rule
+	Before   time.Time       `json:"before"` // Records older than this were removed
+	Count    int             `json:"count"`
+	IDs      []uuid.UUID     `json:"ids"` // Of the removed records
+	PurgedAt time.Time       `json:"purged_at,omitzero"`
+}
+
+// Clone returns a deep copy of the purge
+func (p *RetentionPurge) Clone() *RetentionPurge {
+	clone := *p
+	clone.IDs = slices.Clone(p.IDs)
+	return &clone
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"sort"
+	"time"
+)
+
+// DailySpending is the spending recorded in one category on one day. Reports over long periods
+// add these up instead of going through every expenditure
+type DailySpending struct {
+	Day        time.Time `json:"day"`         // Midnight UTC of the day
+	CategoryId uuid.UUID `json:"category_id"` // Empty for uncategorized spending
+	Count      int       `json:"count"`       // Number of expenditures
+	Total      float64   `json:"total"`       // Sum of the amounts, tax included
+	TaxAmount  float64   `json:"tax_amount"`  // Sum of the tax amounts
+
+	// Pending expenditures are included above and counted here too, so reports can leave them out
+	PendingCount     int     `json:"pending_count,omitempty"`
+	PendingTotal     float64 `json:"pending_total,omitempty"`
+	PendingTaxAmount float64 `json:"pending_tax_amount,omitempty"`
+}
+
+// SpendingDay returns the day an expenditure dated t is summarized under
+func SpendingDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// SummarizeSpending aggregates expenditures per day and category, ordered by day; cancelled
+// expenditures are left out
+func SummarizeSpending(expenditures []*Expenditure) []*DailySpending {
+	type key struct {
+		day        time.Time
+		categoryID uuid.UUID
+	}
+
+	byKey := make(map[key]*DailySpending)
+	var summaries []*DailySpending
+	for _, expenditure := range expenditures {
+		if expenditure.IsCancelled() {
+			continue
+		}
+		k := key{SpendingDay(expenditure.Date), expenditure.CategoryId}
+		summary, ok := byKey[k]
+		if !ok {
+			summary = &DailySpending{Day: k.day, CategoryId: k.categoryID}
+			byKey[k] = summary
+			summaries = append(summaries, summary)
+		}
+		summary.Count++
+		summary.Total += expenditure.Amount
+		summary.TaxAmount += expenditure.TaxAmount
+		if expenditure.IsPending() {
+			summary.PendingCount++
+			summary.PendingTotal += expenditure.Amount
+			summary.PendingTaxAmount += expenditure.TaxAmount
+		}
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].Day.Before(summaries[j].Day)
+	})
+	return summaries
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var ErrStagedExpenditureNotPending = errors.New("staged expenditure has already been reviewed")
+var ErrStagedExpenditureSourceEmpty = errors.New("staged expenditure source cannot be empty")
+
+type ImportStatus string
+
+const (
+	ImportStatusPending  ImportStatus = "pending"
+	ImportStatusApproved ImportStatus = "approved"
+	ImportStatusRejected ImportStatus = "rejected"
+)
+
+// StagedExpenditure is an imported expenditure waiting in the review queue. Its fields may be
+// incomplete, they are only validated when it is approved into a real Expenditure
+type StagedExpenditure struct {
+	ID            uuid.UUID    `json:"id"`                       // Unique identifier for the staged expenditure
+	Source        string       `json:"source"`                   // Where the entry came from, e.g. "email"
+	Reference     string       `json:"reference"`                // Source-specific reference such as an email Message-Id
+	Description   string       `json:"description"`              // Proposed description
+	Amount        float64      `json:"amount"`                   // Proposed amount, 0 when it could not be detected
+	Date          time.Time    `json:"date"`                     // Proposed date
+	CategoryId    uuid.UUID    `json:"category_id"`              // Proposed category, uuid.Nil when unknown
+	RawText       string       `json:"raw_text"`                 // Original text the entry was parsed from
+	Status        ImportStatus `json:"status"`                   // Review status
+	ExpenditureID uuid.UUID    `json:"expenditure_id,omitempty"` // Expenditure created on approval
+	CreatedAt     time.Time    `json:"created_at"`               // When the entry was staged
+}
+
+func NewStagedExpenditure(source, reference, description string, amount float64, date time.Time, rawText string) (*StagedExpenditure, error) {
+	if source == "" {
+		return nil, ErrStagedExpenditureSourceEmpty
+	}
+
+	return &StagedExpenditure{
+		ID:          uuid.New(),
+		Source:      source,
+		Reference:   reference,
+		Description: description,
+		Amount:      amount,
+		Date:        date,
+		RawText:     rawText,
+		Status:      ImportStatusPending,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// Approve validates the staged entry as an Expenditure and marks it approved
+func (s *StagedExpenditure) Approve() (*Expenditure, error) {
+	if s.Status != ImportStatusPending {
+		return nil, ErrStagedExpenditureNotPending
+	}
+
+	expenditure, err := NewExpenditure(s.Description, s.Amount, s.Date, s.CategoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Status = ImportStatusApproved
+	s.ExpenditureID = expenditure.ID
+	return expenditure, nil
+}
+
+// Reject marks the staged entry as rejected
+func (s *StagedExpenditure) Reject() error {
+	if s.Status != ImportStatusPending {
+		return ErrStagedExpenditureNotPending
+	}
+
+	s.Status = ImportStatusRejected
+	return nil
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// DetectedSubscription is a charge that repeats on a cadence in the history, such as a streaming
+// service billed every month, which is not yet managed as a recurring expenditure
+type DetectedSubscription struct {
+	ID             uuid.UUID          `json:"id"` // Derived from the merchant and amount, the same on every detection
+	Description    string             `json:"description"`
+	MerchantId     uuid.UUID          `json:"merchant_id,omitzero"`
+	Amount         float64            `json:"amount"`
+	CategoryId     uuid.UUID          `json:"category_id"` // Of the latest charge
+	Frequency      RecurringFrequency `json:"frequency"`
+	Charges        int                `json:"charges"`
+	FirstDate      time.Time          `json:"first_date"`
+	LastDate       time.Time          `json:"last_date"`
+	NextDate       time.Time          `json:"next_date"`    // Expected day of the next charge
+	MonthlyCost    float64            `json:"monthly_cost"` // Amount spread over a month, e.g. a twelfth of a yearly charge
+	ExpenditureIDs []uuid.UUID        `json:"expenditure_ids"`
+}
+
+// Recurring returns a recurring expenditure taking the subscription over from its next charge
+// due today or later, so no charge already in the history is generated again
+func (s *DetectedSubscription) Recurring(now time.Time) (*RecurringExpenditure, error) {
+	start := s.NextDate
+	for start.Before(SpendingDay(now)) {
+		start = s.Frequency.After(start)
+	}
+	return NewRecurringExpenditure(s.Description, s.Amount, s.CategoryId, nil, s.Frequency, start, nil)
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrInvalidTaxRate = errors.New("tax rate must be between 0 and 100 percent")
+var ErrInvalidTaxAmount = errors.New("tax amount must be between 0 and the expenditure amount")
+var ErrTaxAmountMismatch = errors.New("tax amount does not match the tax rate")
+
+// ResolveTax validates the tax of a gross amount and derives whichever of rate and tax
+// amount is missing. Amounts include tax, so a 19% rate on 119.00 is 19.00 of tax
+func ResolveTax(amount, rate, taxAmount float64) (float64, float64, error) {
+	if rate == 0 && taxAmount == 0 {
+		return 0, 0, nil
+	}
+
+	if rate < 0 || rate > 100 {
+		return 0, 0, ErrInvalidTaxRate
+	}
+
+	if taxAmount < 0 || taxAmount >= amount {
+		return 0, 0, ErrInvalidTaxAmount
+	}
+
+	if taxAmount == 0 {
+		return rate, TaxIncluded(amount, rate), nil
+	}
+
+	if rate == 0 {
+		return round2(taxAmount / (amount - taxAmount) * 100), taxAmount, nil
+	}
+
+	// Receipts round the tax, so allow a cent of difference
+	if math.Abs(TaxIncluded(amount, rate)-taxAmount) > 0.01 {
+		return 0, 0, ErrTaxAmountMismatch
+	}
+
+	return rate, taxAmount, nil
+}
+
+// TaxIncluded returns the tax contained in a gross amount at the given rate
+func TaxIncluded(amount, rate float64) float64 {
+	return round2(amount * rate / (100 + rate))
+}
+
+// SetTax records the tax included in the expenditure; zero values clear it
+func (e *Expenditure) SetTax(rate, taxAmount float64) error {
+	rate, taxAmount, err := ResolveTax(e.Amount, rate, taxAmount)
+	if err != nil {
+		return err
+	}
+
+	e.TaxRate = rate
+	e.TaxAmount = taxAmount
+	return nil
+}
+
+// NetAmount returns the amount excluding tax
+func (e *Expenditure) NetAmount() float64 {
+	return round2(e.Amount - e.TaxAmount)
+}
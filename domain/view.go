@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrViewNotFound = errors.New("view not found")
+var ErrViewNameEmpty = errors.New("view name cannot be empty")
+var ErrInvalidViewDate = errors.New("invalid view date, use YYYY-MM-DD")
+var ErrInvalidViewRange = errors.New("view end date must not be before its start date")
+var ErrInvalidViewAmounts = errors.New("view amounts must not be negative and the minimum must not exceed the maximum")
+
+// ExpenditureFilter selects expenditures; empty fields do not filter
+type ExpenditureFilter struct {
+	From        string      `json:"from,omitempty"`         // First day included, YYYY-MM-DD
+	To          string      `json:"to,omitempty"`           // Last day included, YYYY-MM-DD
+	Tags        []string    `json:"tags,omitempty"`         // Expenditures must carry all of them
+	CategoryIds []uuid.UUID `json:"category_ids,omitempty"` // Expenditures must be in one of them
+	MerchantId  uuid.UUID   `json:"merchant_id"`            // Expenditures must be at this merchant
+	MinAmount   float64     `json:"min_amount,omitempty"`
+	MaxAmount   float64     `json:"max_amount,omitempty"`
+	Search      string      `json:"search,omitempty"` // Text the description must contain, ignoring case
+	Pinned      bool        `json:"pinned,omitempty"` // Only pinned expenditures
+}
+
+// Range returns the dates of the filter as [from, to); zero bounds are open
+func (f ExpenditureFilter) Range() (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+
+	if f.From != "" {
+		if from, err = time.Parse("2006-01-02", f.From); err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidViewDate
+		}
+	}
+	if f.To != "" {
+		if to, err = time.Parse("2006-01-02", f.To); err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidViewDate
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+
+	if !from.IsZero() && !to.IsZero() && !to.After(from) {
+		return time.Time{}, time.Time{}, ErrInvalidViewRange
+	}
+	return from, to, nil
+}
+
+// Validate checks the filter and normalizes its tags
+func (f *ExpenditureFilter) Validate() error {
+	if _, _, err := f.Range(); err != nil {
+		return err
+	}
+	if f.MinAmount < 0 || f.MaxAmount < 0 || (f.MaxAmount > 0 && f.MinAmount > f.MaxAmount) {
+		return ErrInvalidViewAmounts
+	}
+
+	f.Tags = NormalizeTags(f.Tags)
+	f.Search = strings.TrimSpace(f.Search)
+	return nil
+}
+
+// Match reports whether the expenditure passes the filter; pinned tells whether it is pinned.
+// The filter must have been validated
+func (f ExpenditureFilter) Match(e *Expenditure, pinned bool) bool {
+	from, to, _ := f.Range()
+	if (!from.IsZero() && e.Date.Before(from)) || (!to.IsZero() && !e.Date.Before(to)) {
+		return false
+	}
+
+	if f.Pinned && !pinned {
+		return false
+	}
+	if f.MerchantId != uuid.Nil && e.MerchantId != f.MerchantId {
+		return false
+	}
+	if f.MinAmount > 0 && e.Amount < f.MinAmount {
+		return false
+	}
+	if f.MaxAmount > 0 && e.Amount > f.MaxAmount {
+		return false
+	}
+	if f.Search != "" && !strings.Contains(strings.ToLower(e.Description), strings.ToLower(f.Search)) {
+		return false
+	}
+
+	if len(f.CategoryIds) > 0 {
+		found := false
+		for _, id := range f.CategoryIds {
+			found = found || e.CategoryId == id
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for _, tag := range f.Tags {
+		if !e.HasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// View is a named, saved expenditure filter such as "Work travel 2024"
+type View struct {
+	ID        uuid.UUID         `json:"id"`
+	Name      string            `json:"name"`
+	Filter    ExpenditureFilter `json:"filter"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+func NewView(name string, filter ExpenditureFilter) (*View, error) {
+	view := &View{ID: uuid.New(), CreatedAt: time.Now()}
+	if err := view.Update(name, filter); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+func (v *View) Update(name string, filter ExpenditureFilter) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrViewNameEmpty
+	}
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+
+	v.Name = name
+	v.Filter = filter
+	v.UpdatedAt = time.Now()
+	return nil
+}
+
+// ReplaceCategory points the view at target where it selected source, e.g. after a merge. It
+// reports whether the view changed
+func (v *View) ReplaceCategory(source, target uuid.UUID) bool {
+	changed := false
+	ids := make([]uuid.UUID, 0, len(v.Filter.CategoryIds))
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range v.Filter.CategoryIds {
+		if id == source {
+			id = target
+			changed = true
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if changed {
+		v.Filter.CategoryIds = ids
+	}
+	return changed
+}
+
+// Clone returns a deep copy of the view that shares no filter tags or categories with it
+func (v *View) Clone() *View {
+	clone := *v
+	clone.Filter.Tags = slices.Clone(v.Filter.Tags)
+	clone.Filter.CategoryIds = slices.Clone(v.Filter.CategoryIds)
+	return &clone
+}
@@ -0,0 +1,46 @@
+package eventsourcing
+
+import (
+	"go-expense-tracker/domain"
+	"time"
+)
+
+// ArchiveRepository wraps an ArchiveRepository and records the archived expenditures, so
+// replaying the events does not bring them back
+type ArchiveRepository struct {
+	domain.ArchiveRepository
+	expenditures *ExpenditureRepository
+}
+
+// NewArchiveRepository creates a new ArchiveRepository around the given repository
+func NewArchiveRepository(inner domain.ArchiveRepository, expenditures *ExpenditureRepository) *ArchiveRepository {
+	return &ArchiveRepository{
+		ArchiveRepository: inner,
+		expenditures:      expenditures,
+	}
+}
+
+// ArchiveExpenditures archives the expenditures and records each of them as archived
+func (r *ArchiveRepository) ArchiveExpenditures(before time.Time) (*domain.Archive, error) {
+	r.expenditures.mu.Lock()
+	defer r.expenditures.mu.Unlock()
+
+	archive, err := r.ArchiveRepository.ArchiveExpenditures(before)
+	if err != nil {
+		return nil, err
+	}
+
+	archived, err := r.ArchiveRepository.GetArchivedExpenditures(archive.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*domain.ExpenditureEvent, 0, len(archived))
+	for _, expenditure := range archived {
+		events = append(events, domain.NewExpenditureEvent(domain.ExpenditureArchived, expenditure.ID, nil))
+	}
+	if err := r.expenditures.append(events...); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
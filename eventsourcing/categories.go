@@ -0,0 +1,57 @@
+package eventsourcing
+
+import (
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+)
+
+// CategoryRepository wraps a CategoryRepository and records the expenditures moved by a
+// category merge as amended, so replaying the events does not undo the merge
+type CategoryRepository struct {
+	domain.CategoryRepository
+	expenditures *ExpenditureRepository
+}
+
+// NewCategoryRepository creates a new CategoryRepository around the given repository
+func NewCategoryRepository(inner domain.CategoryRepository, expenditures *ExpenditureRepository) *CategoryRepository {
+	return &CategoryRepository{
+		CategoryRepository: inner,
+		expenditures:       expenditures,
+	}
+}
+
+// MergeCategory merges the categories and records every expenditure moved to the target
+func (r *CategoryRepository) MergeCategory(source, target uuid.UUID, dryRun bool) (*domain.CategoryMerge, error) {
+	if dryRun {
+		return r.CategoryRepository.MergeCategory(source, target, dryRun)
+	}
+
+	r.expenditures.mu.Lock()
+	defer r.expenditures.mu.Unlock()
+
+	var moved []*domain.ExpenditureEvent
+	err := domain.EachExpenditure(r.expenditures.ExpenditureRepository, func(expenditure *domain.Expenditure) error {
+		if expenditure.CategoryId == source {
+			event := domain.NewExpenditureEvent(domain.ExpenditureAmended, expenditure.ID, expenditure)
+			event.Expenditure.CategoryId = target
+			moved = append(moved, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merge, err := r.CategoryRepository.MergeCategory(source, target, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(moved) > 0 {
+		if err := r.expenditures.append(moved...); err != nil {
+			return nil, err
+		}
+	}
+	return merge, nil
+}
@@ -0,0 +1,171 @@
+// Package eventsourcing stores every change to expenditures as an event. The events are the
+// source of truth; the wrapped storage only holds their projection, the current expenditures.
+package eventsourcing
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// ExpenditureRepository wraps the storage of the projected expenditures. Every change is
+// appended to the event store first and then applied to the projection; when applying fails
+// the projection catches up the next time Project runs
+type ExpenditureRepository struct {
+	domain.ExpenditureWrapper
+	events domain.ExpenditureEventStore
+	logger *slog.Logger
+	mu     sync.Mutex // Keeps the order of the events and the projection the same
+}
+
+// NewExpenditureRepository creates a new ExpenditureRepository around the given projection
+func NewExpenditureRepository(projection domain.ExpenditureRepository, events domain.ExpenditureEventStore, logger *slog.Logger) *ExpenditureRepository {
+	return &ExpenditureRepository{
+		ExpenditureWrapper: domain.ExpenditureWrapper{ExpenditureRepository: projection},
+		events:             events,
+		logger:             logger,
+	}
+}
+
+// AddExpenditure records the creation of the expenditure and adds it to the projection
+func (r *ExpenditureRepository) AddExpenditure(expenditure *domain.Expenditure) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.append(domain.NewExpenditureEvent(domain.ExpenditureCreated, expenditure.ID, expenditure)); err != nil {
+		return err
+	}
+	return r.project(r.ExpenditureRepository.AddExpenditure(expenditure))
+}
+
+// AddExpenditures records the creation of all expenditures at once and adds them to the projection
+func (r *ExpenditureRepository) AddExpenditures(expenditures []*domain.Expenditure) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := make([]*domain.ExpenditureEvent, 0, len(expenditures))
+	for _, expenditure := range expenditures {
+		events = append(events, domain.NewExpenditureEvent(domain.ExpenditureCreated, expenditure.ID, expenditure))
+	}
+	if err := r.append(events...); err != nil {
+		return err
+	}
+	return r.project(domain.AddExpenditures(r.ExpenditureRepository, expenditures))
+}
+
+// UpdateExpenditure records the amendment of the expenditure and updates the projection
+func (r *ExpenditureRepository) UpdateExpenditure(expenditure *domain.Expenditure) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.ExpenditureRepository.GetExpenditureByID(expenditure.ID.String()); err != nil {
+		return err
+	}
+
+	if err := r.append(domain.NewExpenditureEvent(domain.ExpenditureAmended, expenditure.ID, expenditure)); err != nil {
+		return err
+	}
+	return r.project(r.ExpenditureRepository.UpdateExpenditure(expenditure))
+}
+
+// DeleteExpenditure records the deletion of the expenditure and removes it from the projection
+func (r *ExpenditureRepository) DeleteExpenditure(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expenditure, err := r.ExpenditureRepository.GetExpenditureByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.append(domain.NewExpenditureEvent(domain.ExpenditureDeleted, expenditure.ID, nil)); err != nil {
+		return err
+	}
+	return r.project(r.ExpenditureRepository.DeleteExpenditure(id))
+}
+
+// Project brings the projection in line with the events, replaying those it missed. Expenditures
+// in the projection without any event, such as those stored before event sourcing was enabled,
+// are recorded as created
+func (r *ExpenditureRepository) Project() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events, err := r.events.GetExpenditureEvents(0, 0)
+	if err != nil {
+		return fmt.Errorf("error loading expenditure events: %w", err)
+	}
+	state := domain.ProjectExpenditures(events)
+
+	projected := make(map[uuid.UUID]*domain.Expenditure)
+	var adopted []*domain.ExpenditureEvent
+	err = domain.EachExpenditure(r.ExpenditureRepository, func(expenditure *domain.Expenditure) error {
+		projected[expenditure.ID] = expenditure
+		if _, ok := state[expenditure.ID]; !ok {
+			adopted = append(adopted, domain.NewExpenditureEvent(domain.ExpenditureCreated, expenditure.ID, expenditure))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error reading the projected expenditures: %w", err)
+	}
+
+	replayed := 0
+	for id, expenditure := range state {
+		current, exists := projected[id]
+		switch {
+		case expenditure == nil && exists:
+			err = r.ExpenditureRepository.DeleteExpenditure(id.String())
+		case expenditure == nil:
+			continue
+		case !exists:
+			err = r.ExpenditureRepository.AddExpenditure(expenditure)
+		case !sameExpenditure(current, expenditure):
+			err = r.ExpenditureRepository.UpdateExpenditure(expenditure)
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error projecting expenditure %s: %w", id, err)
+		}
+		replayed++
+	}
+
+	if len(adopted) > 0 {
+		if err := r.append(adopted...); err != nil {
+			return err
+		}
+	}
+
+	r.logger.Info("Projected expenditure events", "events", len(events), "replayed", replayed, "adopted", len(adopted))
+	return nil
+}
+
+// append adds events to the store; callers hold the lock
+func (r *ExpenditureRepository) append(events ...*domain.ExpenditureEvent) error {
+	if err := r.events.AppendExpenditureEvents(events); err != nil {
+		r.logger.Error("Failed to append expenditure events", "error", err, "count", len(events))
+		return err
+	}
+	return nil
+}
+
+// project reports an error applying recorded events to the projection
+func (r *ExpenditureRepository) project(err error) error {
+	if err != nil {
+		r.logger.Error("Failed to apply expenditure events to the projection", "error", err)
+	}
+	return err
+}
+
+// sameExpenditure compares two expenditures by their JSON form, which ignores differences such
+// as the location of otherwise equal dates
+func sameExpenditure(a, b *domain.Expenditure) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(encodedA) == string(encodedB)
+}
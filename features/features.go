@@ -0,0 +1,137 @@
+// Package features switches features on and off per deployment, so risky ones can be rolled
+// out gradually and turned off again without a rebuild. Flags are configured at startup and,
+// unless they only take effect then, can be toggled at runtime.
+package features
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var ErrUnknownFlag = errors.New("unknown feature flag")
+var ErrStartupOnlyFlag = errors.New("feature flag only takes effect at startup and cannot be toggled at runtime")
+var ErrInvalidFlags = errors.New("invalid feature flags, use name=true or name=false pairs such as bank-sync=false")
+
+// Names of the feature flags
+const (
+	EventSourcing       = "event-sourcing"
+	BankSync            = "bank-sync"
+	ExpenseReports      = "expense-reports"
+	CategorySuggestions = "category-suggestions"
+)
+
+// Flag describes a feature that can be switched on and off
+type Flag struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Default     bool   `json:"default"`
+	StartupOnly bool   `json:"startup_only"` // Read once at startup, toggling it needs a restart
+}
+
+// Flags are the known feature flags
+var Flags = []Flag{
+	{Name: EventSourcing, Description: "Store every change to expenditures as an event and serve the /events API", StartupOnly: true},
+	{Name: BankSync, Description: "Bank connections, their sync API and the scheduled transaction sync", Default: true},
+	{Name: ExpenseReports, Description: "Expense reports and their review workflow", Default: true},
+	{Name: CategorySuggestions, Description: "Category suggestions from a classifier trained on the categorized expenditures", StartupOnly: true},
+}
+
+// State is a flag with whether it is enabled
+type State struct {
+	Flag
+	Enabled bool `json:"enabled"`
+}
+
+// Set holds whether each flag is enabled. It is safe for concurrent use
+type Set struct {
+	mu      sync.RWMutex
+	enabled map[string]bool
+}
+
+// Parse creates a Set from a configuration such as "bank-sync=false,event-sourcing=true";
+// flags left out keep their default
+func Parse(config string) (*Set, error) {
+	s := &Set{enabled: make(map[string]bool, len(Flags))}
+	for _, flag := range Flags {
+		s.enabled[flag.Name] = flag.Default
+	}
+	if strings.TrimSpace(config) == "" {
+		return s, nil
+	}
+
+	for _, pair := range strings.Split(config, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, ErrInvalidFlags
+		}
+		name = strings.TrimSpace(name)
+		if _, known := s.enabled[name]; !known {
+			return nil, ErrUnknownFlag
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return nil, ErrInvalidFlags
+		}
+		s.enabled[name] = enabled
+	}
+	return s, nil
+}
+
+// Enabled reports whether the flag is enabled; unknown flags are not
+func (s *Set) Enabled(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.enabled[name]
+}
+
+// Toggle enables or disables a flag at runtime
+func (s *Set) Toggle(name string, enabled bool) error {
+	flag, ok := lookup(name)
+	if !ok {
+		return ErrUnknownFlag
+	}
+	if flag.StartupOnly {
+		return ErrStartupOnlyFlag
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.enabled[name] = enabled
+	return nil
+}
+
+// States returns every flag with whether it is enabled, in the order of Flags
+func (s *Set) States() []State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	states := make([]State, len(Flags))
+	for i, flag := range Flags {
+		states[i] = State{Flag: flag, Enabled: s.enabled[flag.Name]}
+	}
+	return states
+}
+
+// Require serves next only while the flag is enabled; otherwise the feature does not exist as
+// far as clients can tell
+func (s *Set) Require(name string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.Enabled(name) {
+			http.NotFound(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func lookup(name string) (Flag, bool) {
+	for _, flag := range Flags {
+		if flag.Name == name {
+			return flag, true
+		}
+	}
+	return Flag{}, false
+}
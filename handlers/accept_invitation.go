@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// AcceptInvitation handles POST /invitations/{token}/accept, which adds the caller to the
+// household as a member with the role of the invitation and returns the household. Expired and
+// revoked invitations are answered with 410 Gone, used ones with 409 Conflict
+func (h *HouseholdHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling accept invitation request", "method", r.Method, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token, _, ok := splitPath(r.URL.Path, "/invitations/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	var req AcceptInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	household, member, err := h.service.Accept(token, req.Name)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully accepted invitation", "household_id", household.ID, "member_id", member.ID, "role", member.Role)
+	w.Header().Set("Location", "/households/"+household.ID.String())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(household)
+}
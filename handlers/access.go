@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var errForbidden = errors.New("your household role does not permit this request")
+var errOutsideAllowance = errors.New("expenditures must be recorded in the category of your allowance")
+
+// AccessMiddleware enforces the household role of the member making a request, as looked up by
+// MemberMiddleware: viewers only read, only owners manage households and allowances and call the
+// admin endpoints without a token of their own, and children only record expenditures and review
+// their own allowance. Every member may manage their own data under /users/me. Requests naming
+// no member reach it only while no household has restricted members, see MemberMiddleware, and
+// are not restricted
+func AccessMiddleware(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		member := Member(r.Context())
+		if member == nil || r.Method == http.MethodOptions || permitted(member, r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		logger.Warn("Request not permitted for member role", "member_id", member.ID, "role", member.Role, "method", r.Method, "path", r.URL.Path)
+		http.Error(w, errForbidden.Error(), http.StatusForbidden)
+	})
+}
+
+// permitted reports whether the role of the member allows the request
+func permitted(member *domain.HouseholdMember, r *http.Request) bool {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	read := r.Method == http.MethodGet || r.Method == http.MethodHead
+
+	// Every member may take their data along or have it erased, whatever their role
+	if path == "/users/me" || strings.HasPrefix(path, "/users/me/") {
+		return true
+	}
+
+	// Recording an expenditure is checked against the allowance by the handler, see checkAllowance
+	if member.Role.Can(domain.PermissionAllowance) {
+		if path == "/expenditures" && r.Method == http.MethodPost {
+			return true
+		}
+		if read && ownAllowance(path, member) {
+			return true
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(path, "/admin/") && !hasCredential(r.URL.Path):
+		// Admin endpoints without a token of their own, such as archiving, are for owners
+		return member.Role.Can(domain.PermissionManage)
+	case (path == "/households" || strings.HasPrefix(path, "/households/")) && !read:
+		return member.Role.Can(domain.PermissionManage)
+	case read:
+		return member.Role.Can(domain.PermissionRead)
+	default:
+		return member.Role.Can(domain.PermissionWrite)
+	}
+}
+
+// ownAllowance reports whether path is the member's own allowance,
+// /households/{id}/members/{memberId}/allowance
+func ownAllowance(path string, member *domain.HouseholdMember) bool {
+	rest, found := strings.CutPrefix(path, "/households/")
+	if !found {
+		return false
+	}
+	household, sub, found := strings.Cut(rest, "/members/")
+	return found && household != "" && !strings.Contains(household, "/") && sub == member.ID.String()+"/allowance"
+}
+
+// checkAllowance refuses expenditures outside the allowance category of a member of the context
+// who may not record others
+func checkAllowance(ctx context.Context, categoryID uuid.UUID) error {
+	member := Member(ctx)
+	if member == nil || member.Role.Can(domain.PermissionWrite) {
+		return nil
+	}
+	if member.AllowanceCategoryId == uuid.Nil {
+		return domain.ErrNoAllowance
+	}
+	if categoryID != member.AllowanceCategoryId {
+		return errOutsideAllowance
+	}
+	return nil
+}
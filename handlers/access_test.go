@@ -0,0 +1,237 @@
+package handlers_test
+
+import (
+	"go-expense-tracker/domain"
+	"go-expense-tracker/handlers"
+	"go-expense-tracker/services"
+	"go-expense-tracker/storagetest"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// servedOK answers every request it is handed with 200 OK
+var servedOK = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+func newTestMember(t *testing.T, role domain.HouseholdRole) *domain.HouseholdMember {
+	t.Helper()
+	member, err := domain.NewHouseholdMember(string(role), "", role)
+	if err != nil {
+		t.Fatalf("creating member: %v", err)
+	}
+	return member
+}
+
+func TestAccessMiddleware(t *testing.T) {
+	roles := []domain.HouseholdRole{domain.RoleOwner, domain.RoleMember, domain.RoleViewer, domain.RoleChild}
+	members := map[domain.HouseholdRole]*domain.HouseholdMember{}
+	for _, role := range roles {
+		members[role] = newTestMember(t, role)
+	}
+	household := uuid.NewString()
+	expenditure := "/expenditures/" + uuid.NewString()
+	// own returns the allowance path of the member of the request, other that of someone else
+	own := func(member *domain.HouseholdMember) string {
+		return "/households/" + household + "/members/" + member.ID.String() + "/allowance"
+	}
+	other := func(*domain.HouseholdMember) string {
+		return "/households/" + household + "/members/" + uuid.NewString() + "/allowance"
+	}
+	fixed := func(path string) func(*domain.HouseholdMember) string {
+		return func(*domain.HouseholdMember) string { return path }
+	}
+
+	const ok, forbidden = http.StatusOK, http.StatusForbidden
+	tests := []struct {
+		method string
+		path   func(*domain.HouseholdMember) string
+		// Statuses of the owner, member, viewer and child
+		want [4]int
+	}{
+		{http.MethodGet, fixed("/expenditures"), [4]int{ok, ok, ok, forbidden}},
+		{http.MethodHead, fixed("/budgets"), [4]int{ok, ok, ok, forbidden}},
+		{http.MethodPost, fixed("/expenditures"), [4]int{ok, ok, forbidden, ok}},
+		{http.MethodPost, fixed("/expenditures/"), [4]int{ok, ok, forbidden, ok}},
+		{http.MethodPost, fixed("/expenditures/bulk"), [4]int{ok, ok, forbidden, forbidden}},
+		{http.MethodPut, fixed(expenditure), [4]int{ok, ok, forbidden, forbidden}},
+		{http.MethodDelete, fixed(expenditure), [4]int{ok, ok, forbidden, forbidden}},
+		{http.MethodPost, fixed("/categories"), [4]int{ok, ok, forbidden, forbidden}},
+		{http.MethodOptions, fixed("/categories"), [4]int{ok, ok, ok, ok}},
+		{http.MethodGet, fixed("/households/" + household), [4]int{ok, ok, ok, forbidden}},
+		{http.MethodPost, fixed("/households"), [4]int{ok, forbidden, forbidden, forbidden}},
+		{http.MethodPost, fixed("/households/" + household + "/invitations"), [4]int{ok, forbidden, forbidden, forbidden}},
+		{http.MethodGet, own, [4]int{ok, ok, ok, ok}},
+		{http.MethodGet, other, [4]int{ok, ok, ok, forbidden}},
+		{http.MethodPut, own, [4]int{ok, forbidden, forbidden, forbidden}},
+		{http.MethodPost, func(m *domain.HouseholdMember) string { return own(m) + "/adjustments" }, [4]int{ok, forbidden, forbidden, forbidden}},
+		{http.MethodGet, func(m *domain.HouseholdMember) string { return own(m) + "/adjustments" }, [4]int{ok, ok, ok, forbidden}},
+		{http.MethodGet, func(m *domain.HouseholdMember) string {
+			return "/households/" + household + "/extra/members/" + m.ID.String() + "/allowance"
+		}, [4]int{ok, ok, ok, forbidden}},
+		{http.MethodGet, fixed("/users/me"), [4]int{ok, ok, ok, ok}},
+		{http.MethodDelete, fixed("/users/me"), [4]int{ok, ok, ok, ok}},
+		{http.MethodGet, fixed("/users/me/export"), [4]int{ok, ok, ok, ok}},
+		{http.MethodPost, fixed("/admin/archive"), [4]int{ok, forbidden, forbidden, forbidden}},
+		{http.MethodGet, fixed("/admin/retention/preview"), [4]int{ok, forbidden, forbidden, forbidden}},
+		{http.MethodPost, fixed("/admin/flags/bank_sync"), [4]int{ok, ok, forbidden, forbidden}},
+	}
+
+	handler := handlers.AccessMiddleware(slog.New(slog.DiscardHandler), servedOK)
+	for _, tt := range tests {
+		for i, role := range roles {
+			member := members[role]
+			path := tt.path(member)
+			t.Run(string(role)+" "+tt.method+" "+path, func(t *testing.T) {
+				req := httptest.NewRequest(tt.method, path, nil)
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req.WithContext(handlers.WithMember(req.Context(), member)))
+
+				if rec.Code != tt.want[i] {
+					t.Errorf("status = %d, want %d", rec.Code, tt.want[i])
+				}
+			})
+		}
+	}
+
+	t.Run("no member", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, expenditure, nil))
+		if rec.Code != ok {
+			t.Errorf("status = %d, want %d", rec.Code, ok)
+		}
+	})
+}
+
+func TestMemberMiddleware(t *testing.T) {
+	storage := services.NewMemoryService(slog.New(slog.DiscardHandler))
+	household, err := domain.NewHousehold("Home", "Alex", "alex@example.com")
+	if err != nil {
+		t.Fatalf("creating household: %v", err)
+	}
+	if err := storage.AddHousehold(household); err != nil {
+		t.Fatalf("adding household: %v", err)
+	}
+	owner := household.Members[0].ID.String()
+
+	var seen *domain.HouseholdMember
+	handler := handlers.MemberMiddleware(storage, slog.New(slog.DiscardHandler), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = handlers.Member(r.Context())
+	}))
+	exportDownload := "/exports/" + uuid.NewString() + "/download"
+
+	tests := []struct {
+		name       string
+		restricted bool // Whether the household has a child by then
+		method     string
+		path       string
+		member     string
+		want       int
+		wantMember string
+	}{
+		{"unidentified without restricted members", false, http.MethodDelete, "/expenditures", "", http.StatusOK, ""},
+		{"owner", false, http.MethodGet, "/expenditures", owner, http.StatusOK, owner},
+		{"unknown member", false, http.MethodGet, "/expenditures", uuid.NewString(), http.StatusBadRequest, ""},
+		{"malformed member", false, http.MethodGet, "/expenditures", "not-a-uuid", http.StatusBadRequest, ""},
+		{"unidentified read", true, http.MethodGet, "/expenditures", "", http.StatusUnauthorized, ""},
+		{"unidentified write", true, http.MethodPost, "/households", "", http.StatusUnauthorized, ""},
+		{"unidentified export status", true, http.MethodGet, "/exports/" + uuid.NewString(), "", http.StatusUnauthorized, ""},
+		{"identified once restricted", true, http.MethodGet, "/expenditures", owner, http.StatusOK, owner},
+		{"preflight", true, http.MethodOptions, "/expenditures", "", http.StatusOK, ""},
+		{"admin endpoint", true, http.MethodPost, "/admin/flags", "", http.StatusOK, ""},
+		{"admin console", true, http.MethodGet, "/admin/console/api/overview", "", http.StatusOK, ""},
+		{"unidentified archiving", true, http.MethodPost, "/admin/archive", "", http.StatusUnauthorized, ""},
+		{"unidentified retention preview", true, http.MethodGet, "/admin/retention/preview", "", http.StatusUnauthorized, ""},
+		{"unidentified seeding", true, http.MethodPost, "/admin/seed", "", http.StatusUnauthorized, ""},
+		{"unidentified index usage", true, http.MethodGet, "/admin/indexes", "", http.StatusUnauthorized, ""},
+		{"invitation acceptance", true, http.MethodPost, "/invitations/token/accept", "", http.StatusOK, ""},
+		{"shared report", true, http.MethodGet, "/reports/shared/link", "", http.StatusOK, ""},
+		{"export download", true, http.MethodGet, exportDownload, "", http.StatusOK, ""},
+		{"calendar feed", true, http.MethodGet, "/calendar.ics", "", http.StatusOK, ""},
+		{"webhook", true, http.MethodPost, "/integrations/slack/commands", "", http.StatusOK, ""},
+		{"version", true, http.MethodGet, "/version", "", http.StatusOK, ""},
+	}
+
+	// The cases run in order; the first restricted one adds a child to the household
+	for _, tt := range tests {
+		if tt.restricted && len(household.Members) == 1 {
+			child := newTestMember(t, domain.RoleChild)
+			invitation, _, err := domain.NewInvitation(household.ID, "kid@example.com", domain.RoleChild, 0)
+			if err != nil {
+				t.Fatalf("creating invitation: %v", err)
+			}
+			if err := storage.AddInvitation(invitation); err != nil {
+				t.Fatalf("adding invitation: %v", err)
+			}
+			if err := storage.AcceptInvitation(invitation, child); err != nil {
+				t.Fatalf("adding child: %v", err)
+			}
+			household.Members = append(household.Members, *child)
+		}
+
+		t.Run(tt.name, func(t *testing.T) {
+			seen = nil
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.member != "" {
+				req.Header.Set(handlers.MemberHeader, tt.member)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %q)", rec.Code, tt.want, rec.Body.String())
+			}
+			var got string
+			if seen != nil {
+				got = seen.ID.String()
+			}
+			if got != tt.wantMember {
+				t.Errorf("member = %q, want %q", got, tt.wantMember)
+			}
+		})
+	}
+}
+
+func TestCheckAllowance(t *testing.T) {
+	allowance := uuid.New()
+	child := newTestMember(t, domain.RoleChild)
+	child.AllowanceCategoryId = allowance
+	unassigned := newTestMember(t, domain.RoleChild)
+	member := newTestMember(t, domain.RoleMember)
+
+	tests := []struct {
+		name     string
+		member   *domain.HouseholdMember
+		category uuid.UUID
+		want     int
+		wantBody string
+	}{
+		{"child in allowance", child, allowance, http.StatusCreated, ""},
+		{"child outside allowance", child, uuid.New(), http.StatusForbidden, "expenditures must be recorded in the category of your allowance"},
+		{"child without allowance", unassigned, allowance, http.StatusForbidden, domain.ErrNoAllowance.Error()},
+		{"member in any category", member, uuid.New(), http.StatusCreated, ""},
+		{"no member", nil, uuid.New(), http.StatusCreated, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"description": "Ice cream", "amount": 3, "date": "2024-03-01T00:00:00Z", "categoryId": "` + tt.category.String() + `"}`
+			req := httptest.NewRequest(http.MethodPost, "/expenditures", strings.NewReader(body))
+			if tt.member != nil {
+				req = req.WithContext(handlers.WithMember(req.Context(), tt.member))
+			}
+			rec := httptest.NewRecorder()
+			newTestRouter(t, storagetest.NewRepository()).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %q)", rec.Code, tt.want, rec.Body.String())
+			}
+			if tt.wantBody != "" && strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"go-expense-tracker/app"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type AccountHandler struct {
+	service *app.AccountService
+	logger  *slog.Logger
+}
+
+func NewAccountHandler(service *app.AccountService, logger *slog.Logger) *AccountHandler {
+	return &AccountHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func AccountRouter(handler *AccountHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/accounts" {
+			Methods{
+				http.MethodGet:  handler.GetAllAccounts,
+				http.MethodPost: handler.AddAccount,
+			}.ServeHTTP(w, r)
+			return
+		}
+
+		_, sub, ok := splitPath(path, "/accounts/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch sub {
+		case "":
+			Methods{http.MethodGet: handler.GetAccount}.ServeHTTP(w, r)
+		case "reconcile":
+			Methods{http.MethodPost: handler.ReconcileAccount}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
@@ -0,0 +1,14 @@
+package handlers
+
+import "time"
+
+type AccountRequest struct {
+	Name           string  `json:"name"`
+	OpeningBalance float64 `json:"openingBalance"` // Balance before the first expenditure recorded on it
+}
+
+// ReconcileRequest holds the closing figures of an account statement
+type ReconcileRequest struct {
+	EndDate time.Time `json:"endDate"`
+	Balance float64   `json:"balance"`
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"go-expense-tracker/activity"
+	"log/slog"
+	"net/http"
+)
+
+type ActivityHandler struct {
+	feed   *activity.Feed
+	logger *slog.Logger
+}
+
+func NewActivityHandler(feed *activity.Feed, logger *slog.Logger) *ActivityHandler {
+	return &ActivityHandler{
+		feed:   feed,
+		logger: logger,
+	}
+}
+
+func ActivityRouter(handler *ActivityHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/activity" {
+			Methods{http.MethodGet: handler.GetActivity}.ServeHTTP(w, r)
+			return
+		}
+
+		http.NotFound(w, r)
+	})
+}
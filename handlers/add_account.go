@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func (h *AccountHandler) AddAccount(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling add account request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req AccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	account, err := h.service.Create(req.Name, req.OpeningBalance)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully added account", "id", account.ID, "name", account.Name)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(account)
+}
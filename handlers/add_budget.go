@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+	"time"
+)
+
+func (h *BudgetHandler) AddBudget(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling add budget request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req BudgetRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Debug("Decoded budget request", "name", req.Name, "amount", req.Amount, "period", req.Period)
+
+	if req.Period == "" {
+		req.Period = domain.BudgetMonthly
+	}
+	if req.StartDate.IsZero() {
+		req.StartDate = time.Now()
+	}
+
+	budget, err := domain.NewBudget(req.Name, req.Amount, req.Period, req.CategoryId, req.StartDate, req.EndDate, req.Rollover)
+	if err != nil {
+		h.logger.Error("Failed to create budget", "error", err, "name", req.Name)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	exists, err := h.checkCategory(budget.CategoryId)
+	if err != nil {
+		h.logger.Error("Failed to check budget category", "error", err, "category_id", budget.CategoryId)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		h.logger.Warn("Budget category not found", "category_id", budget.CategoryId)
+		http.Error(w, domain.ErrCategoryNotFound.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.budgets.AddBudget(budget)
+	if err != nil {
+		h.logger.Error("Failed to add budget", "error", err, "id", budget.ID)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully added budget", "id", budget.ID, "name", budget.Name, "period", budget.Period)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(budget)
+}
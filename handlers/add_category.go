@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/meta"
+	"net/http"
+)
+
+func (h *CategoryHandler) AddCategory(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling add category request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req CategoryRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	category, err := domain.NewCategory(req.Name, req.Color)
+	if err != nil {
+		h.logger.Warn("Invalid category", "error", err, "name", req.Name)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !meta.InPalette(req.Color) {
+		h.logger.Warn("Category color not in the palette", "name", req.Name, "color", req.Color)
+		http.Error(w, domain.ErrCategoryColorNotInPalette.Error(), http.StatusBadRequest)
+		return
+	}
+	category.Deductible = req.Deductible
+
+	err = category.SetIcon(req.Icon)
+	if err != nil {
+		h.logger.Warn("Invalid category icon", "name", req.Name, "icon", req.Icon)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = category.SetLimit(req.TransactionLimit, req.LimitMode)
+	if err != nil {
+		h.logger.Warn("Invalid category limit", "name", req.Name, "limit", req.TransactionLimit, "mode", req.LimitMode)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	status, err := h.checkParent(category.ID, req.ParentId)
+	if err != nil {
+		h.logger.Warn("Invalid parent category", "parent_id", req.ParentId, "error", err)
+		http.Error(w, err.Error(), status)
+		return
+	}
+	category.ParentID = req.ParentId
+
+	// Once the user has chosen an order, new categories go last
+	categories, err := h.categories.GetAllCategories()
+	if err != nil {
+		h.logger.Error("Failed to get categories", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, other := range categories {
+		if other.SortOrder > 0 && other.SortOrder >= category.SortOrder {
+			category.SortOrder = other.SortOrder + 1
+		}
+	}
+
+	err = h.categories.AddCategory(category)
+	if err != nil {
+		if err == domain.ErrCategoryAlreadyExists {
+			h.logger.Warn("Category already exists", "name", category.Name)
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		h.logger.Error("Failed to add category", "error", err, "id", category.ID)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully added category", "id", category.ID, "name", category.Name, "parent_id", category.ParentID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(category)
+}
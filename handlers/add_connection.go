@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *ConnectionHandler) AddConnection(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling add connection request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ConnectionRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Never log the access token
+	h.logger.Debug("Decoded connection request", "connector", req.Connector, "name", req.Name)
+
+	if req.Connector != "" && !h.syncer.HasConnector(req.Connector) {
+		h.logger.Warn("Unknown connector in connection request", "connector", req.Connector)
+		http.Error(w, "unknown or unconfigured connector", http.StatusBadRequest)
+		return
+	}
+
+	connection, err := domain.NewBankConnection(req.Connector, req.Name, req.AccessToken)
+	if err != nil {
+		h.logger.Error("Failed to create connection", "error", err, "connector", req.Connector, "name", req.Name)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.connections.AddBankConnection(connection)
+	if err != nil {
+		h.logger.Error("Failed to add connection", "error", err, "id", connection.ID)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully added connection", "id", connection.ID, "connector", connection.Connector)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(connection)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+// addDraft saves a partially filled create request, sent with ?draft=true, without validating
+// its category, merchant or amounts
+func (h *ExpenditureHandler) addDraft(w http.ResponseWriter, req ExpenditureRequest) {
+	if h.drafts == nil {
+		h.logger.Warn("Drafts are not supported by the storage")
+		http.Error(w, "drafts are not available", http.StatusNotImplemented)
+		return
+	}
+
+	location, err := req.location()
+	if err != nil {
+		h.logger.Warn("Invalid draft location", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	draft, err := domain.NewDraft(domain.Expenditure{
+		Description: req.Description,
+		Amount:      req.Amount,
+		Date:        req.Date,
+		CategoryId:  req.CategoryId,
+		Tags:        domain.NormalizeTags(req.Tags),
+		Quantity:    req.Quantity,
+		UnitPrice:   req.UnitPrice,
+		Unit:        req.Unit,
+		TaxRate:     req.TaxRate,
+		TaxAmount:   req.TaxAmount,
+		MerchantId:  req.MerchantId,
+		Location:    location,
+	})
+	if err != nil {
+		h.logger.Warn("Invalid draft", "error", err, "description", req.Description, "amount", req.Amount)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.drafts.AddDraft(draft); err != nil {
+		h.logger.Error("Failed to add draft", "error", err, "id", draft.ID)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully saved draft", "id", draft.ID, "description", draft.Description)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(draft)
+}
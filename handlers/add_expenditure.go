@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"encoding/json"
-	"go-expense-tracker/domain"
 	"net/http"
 )
 
@@ -25,24 +24,36 @@ func (h *ExpenditureHandler) AddExpenditure(w http.ResponseWriter, r *http.Reque
 
 	h.logger.Debug("Decoded expenditure request", "description", req.Description, "amount", req.Amount, "date", req.Date)
 
-	//TODO: Need to check if category exists
+	if err := checkAllowance(r.Context(), req.CategoryId); err != nil {
+		h.logger.Warn("Expenditure outside the member's allowance", "error", err, "category_id", req.CategoryId)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
 
-	expenditure, err := domain.NewExpenditure(req.Description, req.Amount, req.Date, req.CategoryId)
+	if r.URL.Query().Get("draft") == "true" {
+		h.addDraft(w, req)
+		return
+	}
 
-	if err != nil {
-		h.logger.Error("Failed to create expenditure", "error", err, "description", req.Description, "amount", req.Amount, "date", req.Date)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if validateOnly(r) {
+		result, err := h.expenditures.Prepare(req.input(r.Context()))
+		if err != nil {
+			http.Error(w, err.Error(), serviceStatus(err))
+			return
+		}
+		h.writeValidated(w, r, result)
 		return
 	}
 
-	err = h.service.AddExpenditure(expenditure)
+	result, err := h.expenditures.Create(req.input(r.Context()))
 	if err != nil {
-		h.logger.Error("Failed to add expenditure", "error", err, "id", expenditure.ID)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), serviceStatus(err))
 		return
 	}
+	expenditure := result.Expenditure
 
 	h.logger.Info("Successfully added expenditure", "id", expenditure.ID, "description", expenditure.Description, "date", expenditure.Date)
+	flagLimit(w, result)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(expenditure)
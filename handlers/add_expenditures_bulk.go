@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker/app"
+	"net/http"
+)
+
+// maxBulkExpenditures bounds the number of expenditures of one bulk request
+const maxBulkExpenditures = 50000
+
+type BulkExpenditureResponse struct {
+	Count int `json:"count"`
+}
+
+// AddExpenditures adds many expenditures in one request, all of them or none
+func (h *ExpenditureHandler) AddExpenditures(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling bulk add expenditures request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var reqs []ExpenditureRequest
+	err := json.NewDecoder(r.Body).Decode(&reqs)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(reqs) > maxBulkExpenditures {
+		h.logger.Warn("Too many expenditures in bulk request", "count", len(reqs))
+		http.Error(w, fmt.Sprintf("At most %d expenditures can be added at once", maxBulkExpenditures), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	h.logger.Debug("Decoded bulk expenditure request", "count", len(reqs))
+
+	inputs := make([]app.ExpenditureInput, len(reqs))
+	for i, req := range reqs {
+		inputs[i] = req.input(r.Context())
+	}
+
+	results, err := h.expenditures.CreateMany(inputs)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	overLimit := 0
+	for _, result := range results {
+		if result.OverLimit != nil {
+			overLimit++
+		}
+	}
+	if overLimit > 0 {
+		w.Header().Set(categoryLimitHeader, fmt.Sprintf("%d expenditures exceed the limit of their category", overLimit))
+	}
+
+	h.logger.Info("Successfully added expenditures", "count", len(results), "over_limit", overLimit)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(BulkExpenditureResponse{Count: len(results)})
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *ExpenseReportHandler) AddExpenseReport(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling add expense report request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ExpenseReportRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Debug("Decoded expense report request", "title", req.Title, "expenditures", len(req.ExpenditureIds))
+
+	report, err := domain.NewExpenseReport(req.Title, req.Claimant, req.ExpenditureIds)
+	if err != nil {
+		h.logger.Error("Failed to create expense report", "error", err, "title", req.Title)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	status, err := h.checkExpenditures(report)
+	if err != nil {
+		h.logger.Warn("Invalid expenditures in expense report", "error", err, "title", req.Title)
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	err = h.reports.AddExpenseReport(report)
+	if err != nil {
+		h.logger.Error("Failed to add expense report", "error", err, "id", report.ID)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully added expense report", "id", report.ID, "title", report.Title)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(report)
+}
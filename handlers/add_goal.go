@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+	"time"
+)
+
+func (h *GoalHandler) AddGoal(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling add goal request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req GoalRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Debug("Decoded goal request", "name", req.Name, "target_amount", req.TargetAmount, "deadline", req.Deadline)
+
+	if req.StartDate.IsZero() {
+		req.StartDate = time.Now()
+	}
+
+	goal, err := domain.NewGoal(req.Name, req.TargetAmount, req.StartDate, req.Deadline, req.CategoryId)
+	if err != nil {
+		h.logger.Error("Failed to create goal", "error", err, "name", req.Name)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	exists, err := h.checkCategory(goal.CategoryId.String())
+	if err != nil {
+		h.logger.Error("Failed to check goal category", "error", err, "category_id", goal.CategoryId)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		h.logger.Warn("Goal category not found", "category_id", goal.CategoryId)
+		http.Error(w, domain.ErrCategoryNotFound.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.goals.AddGoal(goal)
+	if err != nil {
+		h.logger.Error("Failed to add goal", "error", err, "id", goal.ID)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully added goal", "id", goal.ID, "name", goal.Name)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(goal)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// AddHousehold handles POST /households, which founds a household owned by its founder
+func (h *HouseholdHandler) AddHousehold(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling add household request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req HouseholdRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	household, err := h.service.Create(req.Name, req.FounderName, req.FounderEmail)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully added household", "id", household.ID, "name", household.Name)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(household)
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/app"
+	"net/http"
+	"time"
+)
+
+// AddInstallmentPurchase handles POST /installments, which saves a purchase split into monthly
+// installments together with an expenditure per installment
+func (h *InstallmentHandler) AddInstallmentPurchase(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling add installment purchase request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req InstallmentRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Debug("Decoded installment purchase request", "description", req.Description, "total_amount", req.TotalAmount, "installments", req.Installments)
+
+	now := time.Now()
+	if req.FirstDate.IsZero() {
+		req.FirstDate = now
+	}
+
+	purchase, err := h.service.Create(app.InstallmentInput{
+		Description: req.Description,
+		TotalAmount: req.TotalAmount,
+		Count:       req.Installments,
+		FirstDate:   req.FirstDate,
+		CategoryId:  req.CategoryId,
+		Tags:        req.Tags,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully added installment purchase", "id", purchase.ID, "installments", len(purchase.Installments))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(installmentResponse(purchase, now))
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+	"time"
+)
+
+// AddInvitation handles POST /households/{id}/invitations. The invitation's token is emailed to
+// the invitee when emails are configured; otherwise, or when sending fails, it is returned once
+// in the response for the inviter to pass on
+func (h *HouseholdHandler) AddInvitation(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling add invitation request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	householdID, ok := pathID(w, r, "/households/")
+	if !ok {
+		return
+	}
+
+	var req InvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	invite, err := h.service.Invite(householdID, req.Email, domain.HouseholdRole(req.Role), req.ValidDays)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	resp := newInvitationResponse(invite.Invitation, time.Now())
+	resp.EmailSent = invite.Sent
+	if !invite.Sent {
+		resp.Token = invite.Token
+		resp.AcceptURL = "/invitations/" + invite.Token + "/accept"
+	}
+
+	h.logger.Info("Successfully added invitation", "id", invite.Invitation.ID, "household_id", householdID, "role", invite.Invitation.Role, "email_sent", invite.Sent)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
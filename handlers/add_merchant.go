@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *MerchantHandler) AddMerchant(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling add merchant request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req MerchantRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Debug("Decoded merchant request", "name", req.Name, "aliases", req.Aliases)
+
+	merchant, err := domain.NewMerchant(req.Name, req.Aliases, req.DefaultCategoryId)
+	if err != nil {
+		h.logger.Error("Failed to create merchant", "error", err, "name", req.Name)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	exists, err := h.checkCategory(merchant.DefaultCategoryId)
+	if err != nil {
+		h.logger.Error("Failed to check merchant default category", "error", err, "category_id", merchant.DefaultCategoryId)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		h.logger.Warn("Merchant default category not found", "category_id", merchant.DefaultCategoryId)
+		http.Error(w, domain.ErrCategoryNotFound.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.merchants.AddMerchant(merchant)
+	if err != nil {
+		h.logger.Error("Failed to add merchant", "error", err, "id", merchant.ID)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully added merchant", "id", merchant.ID, "name", merchant.Name)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(merchant)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+	"time"
+)
+
+func (h *RecurringHandler) AddRecurring(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling add recurring expenditure request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req RecurringRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Debug("Decoded recurring expenditure request", "description", req.Description, "amount", req.Amount, "frequency", req.Frequency)
+
+	if req.Frequency == "" {
+		req.Frequency = domain.RecurringMonthly
+	}
+	if req.StartDate.IsZero() {
+		req.StartDate = time.Now()
+	}
+
+	recurring, err := domain.NewRecurringExpenditure(req.Description, req.Amount, req.CategoryId, req.Tags, req.Frequency, req.StartDate, req.EndDate)
+	if err != nil {
+		h.logger.Error("Failed to create recurring expenditure", "error", err, "description", req.Description)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	exists, err := h.checkCategory(recurring.CategoryId)
+	if err != nil {
+		h.logger.Error("Failed to check recurring expenditure category", "error", err, "category_id", recurring.CategoryId)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		h.logger.Warn("Recurring expenditure category not found", "category_id", recurring.CategoryId)
+		http.Error(w, domain.ErrCategoryNotFound.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.recurring.AddRecurring(recurring)
+	if err != nil {
+		h.logger.Error("Failed to add recurring expenditure", "error", err, "id", recurring.ID)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully added recurring expenditure", "id", recurring.ID, "description", recurring.Description, "frequency", recurring.Frequency)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(recurring)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *RuleHandler) AddRule(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling add rule request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req RuleRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Debug("Decoded rule request", "name", req.Name, "contains", req.Contains)
+
+	rule, err := domain.NewCategorizationRule(req.Name, req.Contains, req.CategoryId, req.Tags, req.AccountId, req.MerchantId)
+	if err != nil {
+		h.logger.Error("Failed to create rule", "error", err, "name", req.Name)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.checkReferences(rule); err != nil {
+		if isMissingReference(err) {
+			h.logger.Warn("Rule refers to a missing record", "error", err, "name", rule.Name)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.logger.Error("Failed to check rule references", "error", err, "name", rule.Name)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = h.rules.AddRule(rule)
+	if err != nil {
+		h.logger.Error("Failed to add rule", "error", err, "id", rule.ID)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully added rule", "id", rule.ID, "name", rule.Name)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rule)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// AdjustAllowance handles POST /households/{id}/members/{memberId}/allowance, which tops up the
+// member's allowance or, with a negative amount, takes money out of it
+func (h *HouseholdHandler) AdjustAllowance(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling adjust allowance request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	householdID, memberID, ok := nestedPathIDs(w, r, "/households/", "members")
+	if !ok {
+		return
+	}
+
+	var req AllowanceAdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	allowance, err := h.allowances.Adjust(householdID, memberID, req.Amount, req.Note)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully adjusted allowance", "household_id", householdID, "member_id", memberID, "amount", req.Amount)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newAllowanceResponse(allowance))
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"math"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// AllocateEnvelopes handles POST /envelopes/allocate, which splits incoming money across the
+// envelopes of several categories
+func (h *EnvelopeHandler) AllocateEnvelopes(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling allocate envelopes request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req EnvelopeAllocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	amounts := make(map[uuid.UUID]float64, len(req.Allocations))
+	ids := make([]uuid.UUID, 0, len(req.Allocations))
+	for _, allocation := range req.Allocations {
+		if allocation.Amount <= 0 {
+			h.logger.Warn("Invalid envelope amount", "category_id", allocation.CategoryId, "amount", allocation.Amount)
+			http.Error(w, domain.ErrInvalidEnvelopeAmount.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, ok := amounts[allocation.CategoryId]; !ok {
+			ids = append(ids, allocation.CategoryId)
+		}
+		amounts[allocation.CategoryId] += allocation.Amount
+	}
+
+	allocations, err := domain.NewEnvelopeAllocations(req.Income, amounts, req.Note)
+	if err != nil {
+		h.logger.Warn("Invalid envelope allocation", "error", err, "income", req.Income)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	missing, err := h.checkCategories(ids...)
+	if err != nil {
+		h.logger.Error("Failed to check envelope categories", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if missing != uuid.Nil {
+		h.logger.Warn("Envelope category not found", "category_id", missing)
+		http.Error(w, domain.ErrCategoryNotFound.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.envelopes.AddEnvelopeAllocations(allocations); err != nil {
+		h.logger.Error("Failed to add envelope allocations", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := EnvelopeAllocationResponse{Allocations: allocations}
+	if req.Income > 0 {
+		allocated := 0.0
+		for _, allocation := range allocations {
+			allocated += allocation.Amount
+		}
+		response.Unallocated = math.Round((req.Income-allocated)*100) / 100
+	}
+
+	h.logger.Info("Successfully allocated envelopes", "count", len(allocations), "unallocated", response.Unallocated)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
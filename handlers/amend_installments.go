@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// AmendInstallments handles POST /installments/{id}/amend, which replaces the remaining
+// installments, e.g. {"installments": 6, "remainingAmount": 480}
+func (h *InstallmentHandler) AmendInstallments(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling amend installments request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req AmendInstallmentsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	id, ok := pathID(w, r, "/installments/")
+	if !ok {
+		return
+	}
+	purchase, err := h.service.Amend(id, req.Installments, req.RemainingAmount)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully amended installments", "id", id, "installments", len(purchase.Installments))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(installmentResponse(purchase, time.Now()))
+}
+
+// PayOffInstallments handles POST /installments/{id}/pay-off, which pays the remaining
+// installments at once today
+func (h *InstallmentHandler) PayOffInstallments(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling pay off installments request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/installments/")
+	if !ok {
+		return
+	}
+	purchase, err := h.service.PayOff(id)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully paid off installments", "id", id, "paid_off_at", purchase.PaidOffAt)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(installmentResponse(purchase, time.Now()))
+}
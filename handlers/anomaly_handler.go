@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"go-expense-tracker/anomalies"
+	"go-expense-tracker/domain"
+	"log/slog"
+)
+
+type AnomalyHandler struct {
+	service  domain.ExpenditureRepository
+	detector *anomalies.Detector
+	logger   *slog.Logger
+}
+
+// NewAnomalyHandler creates a new AnomalyHandler finding anomalies in the expenditures of service
+func NewAnomalyHandler(service domain.ExpenditureRepository, detector *anomalies.Detector, logger *slog.Logger) *AnomalyHandler {
+	return &AnomalyHandler{
+		service:  service,
+		detector: detector,
+		logger:   logger,
+	}
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"go-expense-tracker/app"
+	"go-expense-tracker/domain"
+	"io"
+	"net/http"
+)
+
+func (h *ImportHandler) ApproveStagedExpenditure(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling approve staged expenditure request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/imports/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Approving staged expenditure", "id", id)
+
+	staged, err := h.imports.GetStagedExpenditureByID(id)
+	if err != nil {
+		if err == domain.ErrStagedExpenditureNotFound {
+			h.logger.Warn("Staged expenditure not found for approval", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get staged expenditure", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// The body is optional and overrides the fields detected during import
+	var req ExpenditureRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("Failed to decode approve request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Description != "" {
+		staged.Description = req.Description
+	}
+	if req.Amount != 0 {
+		staged.Amount = req.Amount
+	}
+	if !req.Date.IsZero() {
+		staged.Date = req.Date
+	}
+	if req.CategoryId != uuid.Nil {
+		staged.CategoryId = req.CategoryId
+	}
+
+	// Categorization rules fill in what the import and the review left open
+	match := &domain.RuleMatch{}
+	if h.rules != nil {
+		rules, err := h.rules.GetAllRules()
+		if err != nil {
+			h.logger.Error("Failed to get categorization rules", "id", id, "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		match = domain.MatchRules(rules, staged.Description, staged.RawText)
+		if len(match.Rules) > 0 {
+			h.logger.Debug("Staged expenditure matches rules", "id", id, "rules", len(match.Rules), "first", match.Rules[0].Name)
+		}
+		if staged.CategoryId == uuid.Nil {
+			staged.CategoryId = match.CategoryId
+		}
+	}
+
+	// Imported descriptions are merchant names, so unknown merchants are added to the directory
+	// unless a rule names the merchant. The merchant is optional, so a failed lookup does not
+	// block the approval
+	var merchant *domain.Merchant
+	if h.merchants != nil {
+		if match.MerchantId != uuid.Nil {
+			merchant, err = h.merchants.Get(match.MerchantId)
+		} else {
+			merchant, err = h.merchants.MatchOrCreate(staged.Description)
+		}
+		if err != nil {
+			h.logger.Warn("Failed to resolve merchant of staged expenditure", "id", id, "error", err)
+		}
+		if merchant != nil && staged.CategoryId == uuid.Nil {
+			staged.CategoryId = merchant.DefaultCategoryId
+		}
+	}
+
+	expenditure, err := staged.Approve()
+	if err != nil {
+		if err == domain.ErrStagedExpenditureNotPending {
+			h.logger.Warn("Staged expenditure already reviewed", "id", id, "status", staged.Status)
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		h.logger.Warn("Staged expenditure is not a valid expenditure", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if merchant != nil {
+		expenditure.MerchantId = merchant.ID
+	}
+	expenditure.SetTags(append(req.Tags, match.Tags...))
+	expenditure.AccountId = match.AccountId
+	if req.AccountId != uuid.Nil {
+		expenditure.AccountId = req.AccountId
+	}
+
+	// Imported transactions have cleared, so one recorded while still pending is settled
+	// instead of recording it twice
+	status := http.StatusCreated
+	pending, err := h.matchPending(expenditure)
+	if err != nil {
+		h.logger.Error("Failed to look for pending expenditure", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if pending != nil {
+		h.logger.Info("Staged expenditure settles pending expenditure", "id", id, "expenditure_id", pending.ID, "pending_amount", pending.Amount, "amount", expenditure.Amount)
+		if err := pending.Clear(expenditure.Amount, &expenditure.Date); err != nil {
+			h.logger.Error("Failed to clear pending expenditure", "id", id, "expenditure_id", pending.ID, "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		expenditure = pending
+		staged.ExpenditureID = pending.ID
+		status = http.StatusOK
+		if err := h.repository.UpdateExpenditure(expenditure); err != nil {
+			h.logger.Error("Failed to save settled expenditure", "id", id, "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	} else {
+		// Recorded like any other expenditure, so date policy, category checks and limits apply
+		result, err := h.expenditures.Create(app.ExpenditureInput{
+			ID:          expenditure.ID,
+			Description: expenditure.Description,
+			Amount:      expenditure.Amount,
+			Date:        expenditure.Date,
+			CategoryId:  expenditure.CategoryId,
+			Tags:        expenditure.Tags,
+			MerchantId:  expenditure.MerchantId,
+			AccountId:   expenditure.AccountId,
+			CreatedBy:   memberID(r.Context()),
+		})
+		if err != nil {
+			h.logger.Warn("Failed to record approved expenditure", "id", id, "error", err)
+			http.Error(w, err.Error(), serviceStatus(err))
+			return
+		}
+		expenditure = result.Expenditure
+		flagLimit(w, result)
+	}
+
+	err = h.imports.UpdateStagedExpenditure(staged)
+	if err != nil {
+		h.logger.Error("Failed to update staged expenditure", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully approved staged expenditure", "id", id, "expenditure_id", expenditure.ID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(expenditure)
+}
+
+// matchPending returns the pending expenditure an approved transaction settles, or nil when it
+// settles none
+func (h *ImportHandler) matchPending(expenditure *domain.Expenditure) (*domain.Expenditure, error) {
+	var pending []*domain.Expenditure
+	err := domain.EachExpenditure(h.repository, func(candidate *domain.Expenditure) error {
+		if candidate.IsPending() {
+			pending = append(pending, candidate)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return domain.MatchPending(pending, expenditure.Description, expenditure.MerchantId, expenditure.Amount, expenditure.Date), nil
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+	"strings"
+)
+
+// SetCategoryActive handles POST /categories/{id}/archive and /categories/{id}/unarchive.
+// Archived categories keep their expenditures but cannot be used by new ones
+func (h *CategoryHandler) SetCategoryActive(w http.ResponseWriter, r *http.Request, active bool) {
+	h.logger.Info("Handling archive category request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr, "active", active)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/categories/")
+	if !ok {
+		return
+	}
+	id = strings.TrimSuffix(strings.TrimSuffix(id, "/unarchive"), "/archive")
+
+	category, err := h.categories.GetCategoryByID(id)
+	if err != nil {
+		if err == domain.ErrCategoryNotFound {
+			h.logger.Warn("Category not found for archiving", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get category", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if category.Active != active {
+		category.Active = active
+		err = h.categories.UpdateCategory(category)
+		if err != nil {
+			h.logger.Error("Failed to update category", "id", id, "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	h.logger.Info("Successfully changed category state", "id", id, "active", category.Active)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(category)
+}
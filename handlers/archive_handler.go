@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type ArchiveHandler struct {
+	archives       domain.ArchiveRepository
+	retentionYears int
+	logger         *slog.Logger
+}
+
+// NewArchiveHandler creates a new ArchiveHandler keeping retentionYears years of expenditures
+// live, the current one included, when an archival runs without an explicit cutoff
+func NewArchiveHandler(archives domain.ArchiveRepository, retentionYears int, logger *slog.Logger) *ArchiveHandler {
+	return &ArchiveHandler{
+		archives:       archives,
+		retentionYears: retentionYears,
+		logger:         logger,
+	}
+}
+
+func ArchiveRouter(handler *ArchiveHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/admin/archive" {
+			Methods{
+				http.MethodGet:  handler.GetAllArchives,
+				http.MethodPost: handler.RunArchive,
+			}.ServeHTTP(w, r)
+			return
+		}
+
+		if path == "/admin/archive/summaries" {
+			Methods{http.MethodGet: handler.GetArchiveSummaries}.ServeHTTP(w, r)
+			return
+		}
+
+		_, sub, ok := splitPath(path, "/admin/archive/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch sub {
+		case "":
+			Methods{http.MethodGet: handler.GetArchiveByID}.ServeHTTP(w, r)
+		case "export":
+			Methods{http.MethodGet: handler.ExportArchive}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
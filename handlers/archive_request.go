@@ -0,0 +1,6 @@
+package handlers
+
+// ArchiveRequest is the optional body of an archival run
+type ArchiveRequest struct {
+	Before string `json:"before"` // YYYY-MM-DD, defaults to the start of the oldest retained year
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type BatchHandler struct {
+	target http.Handler
+	logger *slog.Logger
+}
+
+// NewBatchHandler creates a new BatchHandler running the requests of batches through target,
+// which should be the server with its middleware, so they are checked as if sent alone
+func NewBatchHandler(target http.Handler, logger *slog.Logger) *BatchHandler {
+	return &BatchHandler{
+		target: target,
+		logger: logger,
+	}
+}
+
+// BatchResponse is the response to one request of a batch. The body is embedded as JSON when
+// the response is JSON, and as a string otherwise
+type BatchResponse struct {
+	ID      string            `json:"id,omitempty"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// batchRecorder keeps the response of a request of a batch in memory
+type batchRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBatchRecorder() *batchRecorder {
+	return &batchRecorder{header: make(http.Header)}
+}
+
+func (rec *batchRecorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *batchRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+func (rec *batchRecorder) Write(p []byte) (int, error) {
+	rec.WriteHeader(http.StatusOK)
+	return rec.body.Write(p)
+}
+
+// response returns what was recorded as the response to a request of a batch
+func (rec *batchRecorder) response(id string) BatchResponse {
+	resp := BatchResponse{ID: id, Status: rec.status, Headers: make(map[string]string, len(rec.header))}
+	if resp.Status == 0 {
+		resp.Status = http.StatusOK
+	}
+	for name := range rec.header {
+		resp.Headers[name] = rec.header.Get(name)
+	}
+
+	body := bytes.TrimSpace(rec.body.Bytes())
+	switch {
+	case len(body) == 0:
+	case strings.Contains(rec.header.Get("Content-Type"), "json") && json.Valid(body):
+		resp.Body = body
+	default:
+		resp.Body, _ = json.Marshal(string(body))
+	}
+	return resp
+}
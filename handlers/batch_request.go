@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// maxBatchRequests is the most requests one batch may carry
+const maxBatchRequests = 20
+
+var errBatchSize = errors.New("a batch must hold between 1 and 20 requests")
+var errBatchMethod = errors.New("batch requests may only read, use GET or HEAD")
+var errBatchPath = errors.New("batch request paths must start with / and cannot be /batch")
+
+// BatchRequest is one read request of a batch
+type BatchRequest struct {
+	ID      string            `json:"id,omitempty"`     // Echoed in the response, to tell requests apart
+	Method  string            `json:"method"`           // GET when empty, or HEAD
+	Path    string            `json:"path"`             // Path with query, e.g. "/expenditures?limit=10"
+	Headers map[string]string `json:"headers,omitempty"` // Added to the headers of the batch request itself
+}
+
+// validate checks that the request only reads a path of the API
+func (req BatchRequest) validate() error {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead:
+	default:
+		return errBatchMethod
+	}
+
+	path, _, _ := strings.Cut(req.Path, "?")
+	if !strings.HasPrefix(path, "/") || strings.TrimSuffix(path, "/") == "/batch" {
+		return errBatchPath
+	}
+	return nil
+}
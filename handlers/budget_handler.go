@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type BudgetHandler struct {
+	budgets      domain.BudgetRepository
+	envelopes    domain.EnvelopeRepository
+	expenditures domain.ExpenditureRepository
+	categories   domain.CategoryRepository
+	summaries    domain.SpendingSummaryRepository
+	calendar     domain.FiscalCalendar
+	logger       *slog.Logger
+}
+
+// NewBudgetHandler creates a new BudgetHandler; envelopes, categories and summaries may be nil
+// when the storage has no support for them. The calendar decides where monthly, quarterly and
+// yearly periods begin
+func NewBudgetHandler(budgets domain.BudgetRepository, envelopes domain.EnvelopeRepository, expenditures domain.ExpenditureRepository, categories domain.CategoryRepository, summaries domain.SpendingSummaryRepository, calendar domain.FiscalCalendar, logger *slog.Logger) *BudgetHandler {
+	return &BudgetHandler{
+		budgets:      budgets,
+		envelopes:    envelopes,
+		expenditures: expenditures,
+		categories:   categories,
+		summaries:    summaries,
+		calendar:     calendar,
+		logger:       logger,
+	}
+}
+
+func BudgetRouter(handler *BudgetHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/budgets" {
+			Methods{
+				http.MethodGet:  handler.GetAllBudgets,
+				http.MethodPost: handler.AddBudget,
+			}.ServeHTTP(w, r)
+			return
+		}
+
+		if path == "/budgets/status" {
+			Methods{http.MethodGet: handler.GetBudgetStatus}.ServeHTTP(w, r)
+			return
+		}
+
+		_, sub, ok := splitPath(path, "/budgets/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch sub {
+		case "":
+			Methods{
+				http.MethodGet:    handler.GetBudgetByID,
+				http.MethodPut:    handler.UpdateBudget,
+				http.MethodDelete: handler.DeleteBudget,
+			}.ServeHTTP(w, r)
+		case "status":
+			Methods{http.MethodGet: handler.GetBudgetStatus}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// checkCategory reports whether the category exists; budgets on all spending have no category
+// and categories are not checked when they are unavailable
+func (h *BudgetHandler) checkCategory(id uuid.UUID) (bool, error) {
+	if h.categories == nil || id == uuid.Nil {
+		return true, nil
+	}
+
+	_, err := h.categories.GetCategoryByID(id.String())
+	if err == domain.ErrCategoryNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BudgetRequest struct {
+	Name       string              `json:"name"`
+	Amount     float64             `json:"amount"`
+	Period     domain.BudgetPeriod `json:"period"`     // Defaults to monthly
+	CategoryId uuid.UUID           `json:"categoryId"` // Optional, all spending counts without it
+	StartDate  time.Time           `json:"startDate"`  // Optional, defaults to today on creation
+	EndDate    *time.Time          `json:"endDate"`    // Last day, required for custom budgets
+	Rollover   bool                `json:"rollover"`   // Carry unused amounts and overspending into the next period
+}
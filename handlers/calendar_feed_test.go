@@ -0,0 +1,116 @@
+package handlers_test
+
+import (
+	"go-expense-tracker/domain"
+	"go-expense-tracker/handlers"
+	"go-expense-tracker/services"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCalendarFeed(t *testing.T) {
+	storage := services.NewMemoryService(slog.New(slog.DiscardHandler))
+	today := domain.SpendingDay(time.Now())
+	date := func(days int) time.Time { return today.AddDate(0, 0, days) }
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	expenditure := newTestExpenditure(t)
+	if err := storage.AddExpenditure(expenditure); err != nil {
+		t.Fatalf("adding expenditure: %v", err)
+	}
+
+	recurring := func(description string, start time.Time, end *time.Time, paused bool) *domain.RecurringExpenditure {
+		r, err := domain.NewRecurringExpenditure(description, 9.99, uuid.New(), nil, domain.RecurringWeekly, start, end)
+		if err != nil {
+			t.Fatalf("creating recurring expenditure: %v", err)
+		}
+		r.Paused = paused
+		if err := storage.AddRecurring(r); err != nil {
+			t.Fatalf("adding recurring expenditure: %v", err)
+		}
+		return r
+	}
+	streaming := recurring("Streaming", date(1), ptr(date(20)), false)
+	recurring("Paused", date(1), nil, true)
+	recurring("Ended", date(-30), ptr(date(-1)), false)
+
+	budget := func(name string, start time.Time, end *time.Time) *domain.Budget {
+		b, err := domain.NewBudget(name, 250, domain.BudgetCustom, uuid.Nil, start, end, false)
+		if err != nil {
+			t.Fatalf("creating budget: %v", err)
+		}
+		if err := storage.AddBudget(b); err != nil {
+			t.Fatalf("adding budget: %v", err)
+		}
+		return b
+	}
+	trip := budget("Trip", date(5), ptr(date(30)))
+	budget("Past trip", date(-30), ptr(date(-5)))
+
+	handler := handlers.Methods{http.MethodGet: handlers.NewCalendarHandler(storage, storage, storage, domain.FiscalCalendar{}, "secret", slog.New(slog.DiscardHandler)).GetCalendarFeed}
+
+	t.Run("invalid token", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics?token=guess", nil))
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics?token=secret", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	// Unfold the long lines
+	feed := strings.ReplaceAll(rec.Body.String(), "\r\n ", "")
+
+	event := func(kind, id string, day time.Time) string {
+		if kind != "expenditure" {
+			id += "-" + day.Format("20060102")
+		}
+		return "UID:" + kind + "-" + id + "@go-expense-tracker\r\nDTSTAMP:"
+	}
+	want := []struct {
+		name    string
+		uid     string
+		start   time.Time
+		summary string
+	}{
+		{"expenditure", event("expenditure", expenditure.ID.String(), expenditure.Date), expenditure.Date, `Lunch (12.50)`},
+		{"first charge", event("recurring", streaming.ID.String(), date(1)), date(1), `Streaming (9.99\, recurring)`},
+		{"second charge", event("recurring", streaming.ID.String(), date(8)), date(8), `Streaming (9.99\, recurring)`},
+		{"third charge", event("recurring", streaming.ID.String(), date(15)), date(15), `Streaming (9.99\, recurring)`},
+		{"budget period", event("budget", trip.ID.String(), date(5)), date(5), `Trip: 250.00 available`},
+	}
+	for _, w := range want {
+		at := strings.Index(feed, w.uid)
+		if at < 0 {
+			t.Errorf("%s: no event %q in the feed", w.name, w.uid)
+			continue
+		}
+		rest := feed[at:]
+		rest = rest[:strings.Index(rest, "END:VEVENT")]
+		if !strings.Contains(rest, "DTSTART;VALUE=DATE:"+w.start.Format("20060102")+"\r\n") || !strings.Contains(rest, "SUMMARY:"+w.summary+"\r\n") {
+			t.Errorf("%s: event = %q, want it on %s as %q", w.name, rest, w.start.Format("2006-01-02"), w.summary)
+		}
+	}
+	if got := strings.Count(feed, "BEGIN:VEVENT"); got != len(want) {
+		t.Errorf("feed has %d events, want %d; paused and ended recurring expenditures and past budgets have none:\n%s", got, len(want), feed)
+	}
+
+	t.Run("without budgets or recurring expenditures", func(t *testing.T) {
+		handler := handlers.NewCalendarHandler(storage, nil, nil, domain.FiscalCalendar{}, "secret", slog.New(slog.DiscardHandler))
+		rec := httptest.NewRecorder()
+		handler.GetCalendarFeed(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics?token=secret", nil))
+		if got := strings.Count(rec.Body.String(), "BEGIN:VEVENT"); rec.Code != http.StatusOK || got != 1 {
+			t.Errorf("status = %d with %d events, want %d with the expenditure only", rec.Code, got, http.StatusOK)
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+)
+
+// calendarFeedDays is how far ahead the feed shows recurring charges and budget periods
+const calendarFeedDays = 90
+
+type CalendarHandler struct {
+	service   domain.ExpenditureRepository
+	budgets   domain.BudgetRepository
+	recurring domain.RecurringRepository
+	calendar  domain.FiscalCalendar
+	token     string
+	logger    *slog.Logger
+}
+
+// NewCalendarHandler creates a CalendarHandler; the feed is fetched by calendar apps
+// without custom headers, so access is granted by a token in the query string. budgets and
+// recurring may be nil when the storage cannot keep budgets or recurring expenditures
+func NewCalendarHandler(service domain.ExpenditureRepository, budgets domain.BudgetRepository, recurring domain.RecurringRepository, calendar domain.FiscalCalendar, token string, logger *slog.Logger) *CalendarHandler {
+	return &CalendarHandler{
+		service:   service,
+		budgets:   budgets,
+		recurring: recurring,
+		calendar:  calendar,
+		token:     token,
+		logger:    logger,
+	}
+}
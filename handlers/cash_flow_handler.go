@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+)
+
+type CashFlowHandler struct {
+	service   domain.ExpenditureRepository
+	summaries domain.SpendingSummaryRepository
+	budgets   domain.BudgetRepository
+	recurring domain.RecurringRepository
+	calendar  domain.FiscalCalendar
+	logger    *slog.Logger
+}
+
+// NewCashFlowHandler creates a new CashFlowHandler; summaries, budgets and recurring may be nil
+// when the storage has no support for them
+func NewCashFlowHandler(service domain.ExpenditureRepository, summaries domain.SpendingSummaryRepository, budgets domain.BudgetRepository, recurring domain.RecurringRepository, calendar domain.FiscalCalendar, logger *slog.Logger) *CashFlowHandler {
+	return &CashFlowHandler{
+		service:   service,
+		summaries: summaries,
+		budgets:   budgets,
+		recurring: recurring,
+		calendar:  calendar,
+		logger:    logger,
+	}
+}
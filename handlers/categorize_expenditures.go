@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// CategorizeExpenditures moves the listed expenditures to one category, e.g. to sort out the
+// uncategorized ones. Every expenditure is checked before any is changed
+func (h *ExpenditureHandler) CategorizeExpenditures(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling categorize expenditures request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req CategorizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Debug("Decoded categorize request", "count", len(req.IDs), "category_id", req.CategoryId)
+
+	expenditures, err := h.expenditures.Categorize(req.IDs, req.CategoryId)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully categorized expenditures", "count", len(expenditures), "category_id", req.CategoryId)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(expenditures)
+}
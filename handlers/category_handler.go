@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// categoryCacheControl lets clients reuse the category list for a minute and revalidate it
+// with its ETag afterwards
+const categoryCacheControl = "public, max-age=60"
+
+type CategoryHandler struct {
+	categories   domain.CategoryRepository
+	expenditures domain.ExpenditureRepository
+	summaries    domain.SpendingSummaryRepository
+	calendar     domain.FiscalCalendar
+	logger       *slog.Logger
+}
+
+// NewCategoryHandler creates a new CategoryHandler; summaries may be nil when the storage
+// does not keep spending summaries. The calendar decides where the months of the spending begin
+func NewCategoryHandler(categories domain.CategoryRepository, expenditures domain.ExpenditureRepository, summaries domain.SpendingSummaryRepository, calendar domain.FiscalCalendar, logger *slog.Logger) *CategoryHandler {
+	return &CategoryHandler{
+		categories:   categories,
+		expenditures: expenditures,
+		summaries:    summaries,
+		calendar:     calendar,
+		logger:       logger,
+	}
+}
+
+func CategoryRouter(handler *CategoryHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		switch path {
+		case "/categories":
+			Methods{
+				http.MethodGet:  handler.GetAllCategories,
+				http.MethodPost: handler.AddCategory,
+			}.ServeHTTP(w, r)
+			return
+		case "/categories/spending":
+			Methods{http.MethodGet: handler.GetCategorySpending}.ServeHTTP(w, r)
+			return
+		case "/categories/reorder":
+			Methods{http.MethodPatch: handler.ReorderCategories}.ServeHTTP(w, r)
+			return
+		}
+
+		// /categories/{id}/merge-into/{target} is the one path naming two categories
+		if rest, ok := strings.CutPrefix(path, "/categories/"); ok {
+			if source, target, found := strings.Cut(rest, "/merge-into/"); found && source != "" && target != "" && !strings.Contains(target, "/") {
+				Methods{http.MethodPost: handler.MergeCategory}.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		_, sub, ok := splitPath(path, "/categories/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch sub {
+		case "":
+			Methods{
+				http.MethodGet: handler.GetCategoryByID,
+				http.MethodPut: handler.UpdateCategory,
+			}.ServeHTTP(w, r)
+		case "archive":
+			Methods{http.MethodPost: func(w http.ResponseWriter, r *http.Request) { handler.SetCategoryActive(w, r, false) }}.ServeHTTP(w, r)
+		case "unarchive":
+			Methods{http.MethodPost: func(w http.ResponseWriter, r *http.Request) { handler.SetCategoryActive(w, r, true) }}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// checkParent validates nesting the category under parentID; it returns the status code to
+// reply with when the parent is invalid
+func (h *CategoryHandler) checkParent(id, parentID uuid.UUID) (int, error) {
+	if parentID == uuid.Nil {
+		return http.StatusOK, nil
+	}
+
+	categories, err := h.categories.GetAllCategories()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if err := domain.CheckCategoryParent(categories, id, parentID); err != nil {
+		return http.StatusBadRequest, err
+	}
+	return http.StatusOK, nil
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+)
+
+type CategoryRequest struct {
+	Name       string    `json:"name"`
+	Color      string    `json:"color"`
+	Deductible bool      `json:"deductible"`
+	ParentId   uuid.UUID `json:"parentId"` // Optional, nests the category under another one
+	Icon       string    `json:"icon"`     // Optional, one of domain.CategoryIcons
+
+	TransactionLimit float64                  `json:"transactionLimit"` // Optional cap on single expenditures
+	LimitMode        domain.CategoryLimitMode `json:"limitMode"`        // warn (default) or reject
+}
+
+// CategoryOrderRequest lists category IDs in the order they should be presented
+type CategoryOrderRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+}
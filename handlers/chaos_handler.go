@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"go-expense-tracker/chaos"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type ChaosHandler struct {
+	injector *chaos.Injector
+	token    string
+	logger   *slog.Logger
+}
+
+// NewChaosHandler creates a new ChaosHandler; every request must carry token as bearer token
+func NewChaosHandler(injector *chaos.Injector, token string, logger *slog.Logger) *ChaosHandler {
+	return &ChaosHandler{
+		injector: injector,
+		token:    token,
+		logger:   logger,
+	}
+}
+
+func ChaosRouter(handler *ChaosHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(token), []byte(handler.token)) != 1 {
+			handler.logger.Warn("Chaos request without a valid token", "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+			http.Error(w, "Admin token required", http.StatusUnauthorized)
+			return
+		}
+
+		Methods{
+			http.MethodGet: handler.GetChaos,
+			http.MethodPut: handler.SetChaos,
+		}.ServeHTTP(w, r)
+	})
+}
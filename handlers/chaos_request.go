@@ -0,0 +1,9 @@
+package handlers
+
+type ChaosRequest struct {
+	Rules []string `json:"rules"` // Rules such as "POST /expenditures error=10 drop=5"; empty stops injecting faults
+}
+
+type ChaosResponse struct {
+	Rules []string `json:"rules"`
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// ConnectionSyncer runs a transaction sync for a single bank connection
+type ConnectionSyncer interface {
+	HasConnector(name string) bool
+	SyncConnection(ctx context.Context, connection *domain.BankConnection) (int, error)
+}
+
+type ConnectionHandler struct {
+	connections domain.BankConnectionRepository
+	syncer      ConnectionSyncer
+	logger      *slog.Logger
+}
+
+func NewConnectionHandler(connections domain.BankConnectionRepository, syncer ConnectionSyncer, logger *slog.Logger) *ConnectionHandler {
+	return &ConnectionHandler{
+		connections: connections,
+		syncer:      syncer,
+		logger:      logger,
+	}
+}
+
+func ConnectionRouter(handler *ConnectionHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/connections" {
+			Methods{
+				http.MethodGet:  handler.GetAllConnections,
+				http.MethodPost: handler.AddConnection,
+			}.ServeHTTP(w, r)
+			return
+		}
+
+		_, sub, ok := splitPath(path, "/connections/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch sub {
+		case "":
+			Methods{
+				http.MethodGet:    handler.GetConnectionByID,
+				http.MethodDelete: handler.DeleteConnection,
+			}.ServeHTTP(w, r)
+		case "sync":
+			Methods{http.MethodPost: handler.SyncConnection}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
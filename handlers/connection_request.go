@@ -0,0 +1,7 @@
+package handlers
+
+type ConnectionRequest struct {
+	Connector   string `json:"connector"`
+	Name        string `json:"name"`
+	AccessToken string `json:"access_token"`
+}
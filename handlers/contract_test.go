@@ -0,0 +1,408 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"go-expense-tracker/app"
+	"go-expense-tracker/buildinfo"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/handlers"
+	"go-expense-tracker/jobs"
+	"go-expense-tracker/merchants"
+	"go-expense-tracker/recurring"
+	"go-expense-tracker/services"
+	"go-expense-tracker/signedurl"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// update rewrites the golden files with the responses of the current code: go test ./handlers -run TestContract -update
+var update = flag.Bool("update", false, "Rewrite the golden files of the contract tests")
+
+// IDs of the contract fixture, kept in the golden files as they are
+var (
+	groceriesID   = uuid.MustParse("11111111-1111-4111-8111-111111111111")
+	transportID   = uuid.MustParse("22222222-2222-4222-8222-222222222222")
+	lunchID       = uuid.MustParse("33333333-3333-4333-8333-333333333333")
+	busPassID     = uuid.MustParse("44444444-4444-4444-8444-444444444444")
+	budgetID      = uuid.MustParse("55555555-5555-4555-8555-555555555555")
+	goalID        = uuid.MustParse("66666666-6666-4666-8666-666666666666")
+	merchantID    = uuid.MustParse("77777777-7777-4777-8777-777777777777")
+	accountID     = uuid.MustParse("88888888-8888-4888-8888-888888888888")
+	unknownID     = uuid.MustParse("99999999-9999-4999-8999-999999999999")
+	householdID   = uuid.MustParse("aaaaaaaa-aaaa-4aaa-8aaa-aaaaaaaaaaaa")
+	ownerID       = uuid.MustParse("bbbbbbbb-bbbb-4bbb-8bbb-bbbbbbbbbbbb")
+	childID       = uuid.MustParse("cccccccc-cccc-4ccc-8ccc-cccccccccccc")
+	invitationID  = uuid.MustParse("dddddddd-dddd-4ddd-8ddd-dddddddddddd")
+	expiredID     = uuid.MustParse("eeeeeeee-eeee-4eee-8eee-eeeeeeeeeeee")
+	recurringID   = uuid.MustParse("ffffffff-ffff-4fff-8fff-ffffffffffff")
+	draftID       = uuid.MustParse("12121212-1212-4212-8212-121212121212")
+	fixtureIDs    = []uuid.UUID{uuid.Nil, groceriesID, transportID, lunchID, busPassID, budgetID, goalID, merchantID, accountID, unknownID, householdID, ownerID, childID, invitationID, expiredID, recurringID, draftID}
+	fixtureMonth  = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	uuidPattern   = regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+	contractBuild = buildinfo.Info{Version: "1.2.3", Commit: "0123456789abcdef", GoVersion: "go1.24"}
+	// Links of the fixture are signed with a fixed key and expire long after the tests run
+	contractLinks  = signedurl.New([]byte("contract-key"))
+	linksExpire    = time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+	sharedReport   = contractLinks.Sign("/reports/shared/2024-03", linksExpire) + "&format=json"
+	exportDownload = contractLinks.Sign("/exports/"+unknownID.String()+"/download", linksExpire)
+)
+
+// Tokens of the pending and the expired invitation of the fixture
+const (
+	invitationToken = "contract-invitation"
+	expiredToken    = "contract-expired-invitation"
+)
+
+// newContractAPI serves the resource routers as main does, over an in-memory storage holding
+// the same categories, expenditures, budget, goal, merchant, account, draft, recurring
+// expenditure and household with its invitations every time, and the category palette and icons
+func newContractAPI(t *testing.T) http.Handler {
+	t.Helper()
+	logger := slog.New(slog.DiscardHandler)
+	storage := services.NewMemoryService(logger)
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("setting up the contract fixture: %v", err)
+		}
+	}
+	category := func(id uuid.UUID, name, color string) {
+		c, err := domain.NewCategory(name, color)
+		must(err)
+		c.ID = id
+		must(storage.AddCategory(c))
+	}
+	category(groceriesID, "Groceries", "#2f9e44")
+	category(transportID, "Transport", "#1971c2")
+
+	expenditure := func(id uuid.UUID, description string, amount float64, day int, categoryID uuid.UUID) {
+		e, err := domain.NewExpenditure(description, amount, fixtureMonth.AddDate(0, 0, day-1), categoryID)
+		must(err)
+		e.ID = id
+		must(storage.AddExpenditure(e))
+	}
+	expenditure(lunchID, "Lunch", 12.5, 4, groceriesID)
+	expenditure(busPassID, "Bus pass", 49, 1, transportID)
+
+	budget, err := domain.NewBudget("Food", 400, domain.BudgetMonthly, groceriesID, fixtureMonth, nil, false)
+	must(err)
+	budget.ID = budgetID
+	must(storage.AddBudget(budget))
+
+	goal, err := domain.NewGoal("Spend less on transport", 100, fixtureMonth, fixtureMonth.AddDate(0, 6, 0), transportID)
+	must(err)
+	goal.ID = goalID
+	must(storage.AddGoal(goal))
+
+	merchant, err := domain.NewMerchant("Corner Shop", []string{"CORNER SHOP 42"}, groceriesID)
+	must(err)
+	merchant.ID = merchantID
+	must(storage.AddMerchant(merchant))
+
+	account, err := domain.NewAccount("Checking", 1000)
+	must(err)
+	account.ID = accountID
+	must(storage.AddAccount(account))
+
+	draft, err := domain.NewDraft(domain.Expenditure{Description: "Dinner", Amount: 30, Date: fixtureMonth.AddDate(0, 0, 9), CategoryId: groceriesID})
+	must(err)
+	draft.ID = draftID
+	draft.CreatedAt = fixtureMonth
+	must(storage.AddDraft(draft))
+
+	end := fixtureMonth.AddDate(0, 9, 0)
+	rent, err := domain.NewRecurringExpenditure("Rent", 900, transportID, nil, domain.RecurringMonthly, fixtureMonth, &end)
+	must(err)
+	rent.ID = recurringID
+	rent.CreatedAt = fixtureMonth
+	must(storage.AddRecurring(rent))
+
+	household, err := domain.NewHousehold("Home", "Alex", "alex@example.com")
+	must(err)
+	household.ID = householdID
+	household.CreatedAt = fixtureMonth
+	household.Members[0].ID = ownerID
+	household.Members[0].JoinedAt = fixtureMonth
+	household.Members = append(household.Members, domain.HouseholdMember{ID: childID, Name: "Sam", Role: domain.RoleChild, JoinedAt: fixtureMonth})
+	must(storage.AddHousehold(household))
+	must(storage.SetHouseholdMemberAllowance(childID.String(), groceriesID))
+	invitation := func(id uuid.UUID, token string, expires time.Time) {
+		i, _, err := domain.NewInvitation(householdID, "sam@example.com", domain.RoleMember, time.Hour)
+		must(err)
+		i.ID = id
+		i.TokenHash = domain.HashInvitationToken(token)
+		i.CreatedAt = fixtureMonth
+		i.ExpiresAt = expires
+		must(storage.AddInvitation(i))
+	}
+	invitation(invitationID, invitationToken, linksExpire)
+	invitation(expiredID, expiredToken, fixtureMonth.AddDate(0, 0, 7))
+
+	var calendar domain.FiscalCalendar
+	expenditures := app.NewExpenditureService(storage, storage, uuid.Nil, nil, nil, domain.RejectFutureDates, logger)
+	households := app.NewHouseholdService(storage, nil, logger)
+	householdHandler := handlers.NewHouseholdHandler(households, app.NewAllowanceService(storage, storage, storage, storage, logger), logger)
+
+	mux := http.NewServeMux()
+	handle := func(path string, handler http.Handler) {
+		mux.Handle(path, handler)
+		mux.Handle(path+"/", handler)
+	}
+	mux.Handle("/version", handlers.Methods{http.MethodGet: handlers.NewVersionHandler(contractBuild, logger).GetVersion})
+	handle("/expenditures", handlers.ExpenditureRouter(handlers.NewExpenditureHandler(storage, expenditures, storage, storage, uuid.Nil, storage, nil, nil, nil, handlers.JournalExport{}, logger)))
+	handle("/categories", handlers.CategoryRouter(handlers.NewCategoryHandler(storage, storage, nil, calendar, logger)))
+	handle("/budgets", handlers.BudgetRouter(handlers.NewBudgetHandler(storage, nil, storage, storage, nil, calendar, logger)))
+	handle("/goals", handlers.GoalRouter(handlers.NewGoalHandler(storage, storage, storage, logger)))
+	handle("/merchants", handlers.MerchantRouter(handlers.NewMerchantHandler(storage, storage, storage, logger)))
+	handle("/accounts", handlers.AccountRouter(handlers.NewAccountHandler(app.NewAccountService(storage, storage, logger), logger)))
+	mux.Handle("/reports/", handlers.ReportRouter(handlers.NewReportHandler(storage, storage, storage, storage, nil, storage, nil, calendar, logger)))
+	handle("/envelopes", handlers.EnvelopeRouter(handlers.NewEnvelopeHandler(storage, storage, storage, nil, logger)))
+	handle("/installments", handlers.InstallmentRouter(handlers.NewInstallmentHandler(app.NewInstallmentService(storage, expenditures, logger), logger)))
+	handle("/recurring", handlers.RecurringRouter(handlers.NewRecurringHandler(storage, recurring.NewGenerator(storage, expenditures, time.Hour, logger), storage, logger)))
+	handle("/households", handlers.HouseholdRouter(householdHandler))
+	mux.Handle("/invitations/", handlers.InvitationRouter(householdHandler))
+	handle("/exports", handlers.ExportRouter(handlers.NewExportHandler(storage, jobs.NewRunner(time.Hour, logger), contractLinks, time.Hour, logger)))
+	handle("/imports", handlers.ImportRouter(handlers.NewImportHandler(storage, expenditures, storage, merchants.NewResolver(storage, logger), storage, nil, logger)))
+	shares := handlers.ReportShareRouter(handlers.NewReportShareHandler(storage, storage, nil, calendar, contractLinks, handlers.ExportFormatting{}, logger))
+	mux.Handle("/reports/share", shares)
+	mux.Handle("/reports/shared/", shares)
+	mux.Handle("/meta/", handlers.MetaRouter(handlers.NewMetaHandler(logger)))
+	return mux
+}
+
+// TestContract sends requests covering every resource and its error cases and compares the
+// status, content type and body of each response with its golden file in testdata/contract,
+// so changes to the shape of responses show up in review
+func TestContract(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"version", http.MethodGet, "/version", ""},
+
+		{"expenditures-list", http.MethodGet, "/expenditures", ""},
+		{"expenditures-list-invalid-limit", http.MethodGet, "/expenditures?limit=abc", ""},
+		{"expenditures-get", http.MethodGet, "/expenditures/" + lunchID.String(), ""},
+		{"expenditures-get-unknown", http.MethodGet, "/expenditures/" + unknownID.String(), ""},
+		{"expenditures-get-invalid-id", http.MethodGet, "/expenditures/not-a-uuid", ""},
+		{"expenditures-create", http.MethodPost, "/expenditures", `{"description": "Groceries", "amount": 23.4, "date": "2024-03-05T10:00:00Z", "categoryId": "` + groceriesID.String() + `"}`},
+		{"expenditures-create-invalid-json", http.MethodPost, "/expenditures", `{"description":`},
+		{"expenditures-create-invalid-amount", http.MethodPost, "/expenditures", `{"description": "Groceries", "amount": -1, "date": "2024-03-05T10:00:00Z"}`},
+		{"expenditures-update", http.MethodPut, "/expenditures/" + lunchID.String(), `{"description": "Team lunch", "amount": 18, "date": "2024-03-04T12:00:00Z", "categoryId": "` + groceriesID.String() + `"}`},
+		{"expenditures-update-unknown", http.MethodPut, "/expenditures/" + unknownID.String(), `{"description": "Team lunch", "amount": 18, "date": "2024-03-04T12:00:00Z"}`},
+		{"expenditures-delete", http.MethodDelete, "/expenditures/" + lunchID.String(), ""},
+		{"expenditures-delete-unknown", http.MethodDelete, "/expenditures/" + unknownID.String(), ""},
+		{"expenditures-method-not-allowed", http.MethodPatch, "/expenditures", ""},
+
+		{"categories-list", http.MethodGet, "/categories", ""},
+		{"categories-get", http.MethodGet, "/categories/" + groceriesID.String(), ""},
+		{"categories-get-unknown", http.MethodGet, "/categories/" + unknownID.String(), ""},
+		{"categories-create", http.MethodPost, "/categories", `{"name": "Books", "color": "#F28E2B", "icon": "book"}`},
+		{"categories-create-invalid", http.MethodPost, "/categories", `{"name": ""}`},
+		{"categories-create-off-palette", http.MethodPost, "/categories", `{"name": "Books", "color": "#e8590c"}`},
+
+		{"budgets-list", http.MethodGet, "/budgets", ""},
+		{"budgets-get", http.MethodGet, "/budgets/" + budgetID.String(), ""},
+		{"budgets-get-unknown", http.MethodGet, "/budgets/" + unknownID.String(), ""},
+		{"budgets-create-invalid", http.MethodPost, "/budgets", `{"name": "Food", "amount": -5}`},
+
+		{"goals-list", http.MethodGet, "/goals", ""},
+		{"goals-get-unknown", http.MethodGet, "/goals/" + unknownID.String(), ""},
+		{"goals-create-invalid", http.MethodPost, "/goals", `{"name": ""}`},
+
+		{"merchants-list", http.MethodGet, "/merchants", ""},
+		{"merchants-get", http.MethodGet, "/merchants/" + merchantID.String(), ""},
+		{"merchants-get-unknown", http.MethodGet, "/merchants/" + unknownID.String(), ""},
+		{"merchants-create-invalid", http.MethodPost, "/merchants", `{"name": ""}`},
+
+		{"accounts-list", http.MethodGet, "/accounts", ""},
+		{"accounts-get", http.MethodGet, "/accounts/" + accountID.String(), ""},
+		{"accounts-get-unknown", http.MethodGet, "/accounts/" + unknownID.String(), ""},
+		{"accounts-create-invalid", http.MethodPost, "/accounts", `{"name": ""}`},
+
+		{"meta-palette", http.MethodGet, "/meta/palette", ""},
+		{"meta-icons", http.MethodGet, "/meta/icons", ""},
+		{"meta-icon-unknown", http.MethodGet, "/meta/icons/unicorn.svg", ""},
+
+		{"reports-categories", http.MethodGet, "/reports/categories?from=2024-03-01&to=2024-03-31", ""},
+		{"reports-categories-invalid-range", http.MethodGet, "/reports/categories?from=2024-03-31&to=2024-03-01", ""},
+		{"reports-merchants", http.MethodGet, "/reports/merchants?from=2024-03-01&to=2024-03-31", ""},
+		{"reports-tax", http.MethodGet, "/reports/tax?year=2024", ""},
+		{"reports-by-member", http.MethodGet, "/reports/by-member?from=2024-03-01&to=2024-03-31", ""},
+		{"reports-snapshot-unknown", http.MethodGet, "/reports/snapshots/" + unknownID.String(), ""},
+		{"reports-unknown", http.MethodGet, "/reports/unknown", ""},
+
+		{"envelopes-list", http.MethodGet, "/envelopes", ""},
+		{"envelopes-allocate", http.MethodPost, "/envelopes/allocate", `{"income": 500, "allocations": [{"categoryId": "` + groceriesID.String() + `", "amount": 300}], "note": "March"}`},
+		{"envelopes-allocate-invalid", http.MethodPost, "/envelopes/allocate", `{"allocations": [{"categoryId": "` + groceriesID.String() + `", "amount": -5}]}`},
+		{"envelopes-move-insufficient", http.MethodPost, "/envelopes/move", `{"fromCategoryId": "` + groceriesID.String() + `", "toCategoryId": "` + transportID.String() + `", "amount": 50}`},
+		{"envelopes-allocations", http.MethodGet, "/envelopes/allocations", ""},
+
+		{"drafts-list", http.MethodGet, "/expenditures/drafts", ""},
+		{"drafts-create", http.MethodPost, "/expenditures?draft=true", `{"description": "Taxi", "amount": 0}`},
+		{"drafts-publish", http.MethodPost, "/expenditures/" + draftID.String() + "/publish", ""},
+		{"drafts-publish-unknown", http.MethodPost, "/expenditures/" + unknownID.String() + "/publish", ""},
+		{"drafts-delete", http.MethodDelete, "/expenditures/drafts/" + draftID.String(), ""},
+		{"drafts-delete-unknown", http.MethodDelete, "/expenditures/drafts/" + unknownID.String(), ""},
+
+		{"installments-list", http.MethodGet, "/installments", ""},
+		{"installments-create", http.MethodPost, "/installments", `{"description": "Laptop", "totalAmount": 1000, "installments": 3, "firstDate": "2024-03-15T00:00:00Z", "categoryId": "` + groceriesID.String() + `"}`},
+		{"installments-create-invalid", http.MethodPost, "/installments", `{"description": "Laptop", "totalAmount": 1000, "installments": 0}`},
+		{"installments-get-unknown", http.MethodGet, "/installments/" + unknownID.String(), ""},
+
+		{"recurring-list", http.MethodGet, "/recurring", ""},
+		{"recurring-get", http.MethodGet, "/recurring/" + recurringID.String(), ""},
+		{"recurring-get-unknown", http.MethodGet, "/recurring/" + unknownID.String(), ""},
+		{"recurring-create", http.MethodPost, "/recurring", `{"description": "Gym", "amount": 30, "categoryId": "` + groceriesID.String() + `", "frequency": "monthly", "startDate": "2024-03-01T00:00:00Z", "endDate": "2024-06-01T00:00:00Z"}`},
+		{"recurring-create-invalid", http.MethodPost, "/recurring", `{"description": "Gym", "amount": 30, "frequency": "hourly"}`},
+		{"recurring-delete", http.MethodDelete, "/recurring/" + recurringID.String(), ""},
+
+		{"refunds-create", http.MethodPost, "/expenditures/" + busPassID.String() + "/refunds", `{"amount": 9, "date": "2024-03-02T00:00:00Z", "description": "Partial refund"}`},
+		{"refunds-create-exceeding", http.MethodPost, "/expenditures/" + busPassID.String() + "/refunds", `{"amount": 50, "date": "2024-03-02T00:00:00Z"}`},
+		{"refunds-create-invalid", http.MethodPost, "/expenditures/" + busPassID.String() + "/refunds", `{"amount": 0}`},
+		{"refunds-create-unknown", http.MethodPost, "/expenditures/" + unknownID.String() + "/refunds", `{"amount": 5}`},
+		{"refunds-list", http.MethodGet, "/expenditures/" + busPassID.String() + "/refunds", ""},
+
+		{"households-list", http.MethodGet, "/households", ""},
+		{"households-get", http.MethodGet, "/households/" + householdID.String(), ""},
+		{"households-get-unknown", http.MethodGet, "/households/" + unknownID.String(), ""},
+		{"households-create", http.MethodPost, "/households", `{"name": "Flat", "founder_name": "Robin"}`},
+		{"households-create-invalid", http.MethodPost, "/households", `{"name": ""}`},
+
+		{"invitations-list", http.MethodGet, "/households/" + householdID.String() + "/invitations?status=all", ""},
+		{"invitations-create-invalid-role", http.MethodPost, "/households/" + householdID.String() + "/invitations", `{"email": "kim@example.com", "role": "boss"}`},
+		{"invitations-revoke", http.MethodDelete, "/households/" + householdID.String() + "/invitations/" + invitationID.String(), ""},
+		{"invitations-revoke-unknown", http.MethodDelete, "/households/" + householdID.String() + "/invitations/" + unknownID.String(), ""},
+		{"invitations-accept", http.MethodPost, "/invitations/" + invitationToken + "/accept", `{"name": "Kim"}`},
+		{"invitations-accept-expired", http.MethodPost, "/invitations/" + expiredToken + "/accept", `{"name": "Kim"}`},
+		{"invitations-accept-unknown", http.MethodPost, "/invitations/unknown-token/accept", `{"name": "Kim"}`},
+
+		{"allowances-get", http.MethodGet, "/households/" + householdID.String() + "/members/" + childID.String() + "/allowance", ""},
+		{"allowances-get-unassigned", http.MethodGet, "/households/" + householdID.String() + "/members/" + ownerID.String() + "/allowance", ""},
+		{"allowances-set", http.MethodPut, "/households/" + householdID.String() + "/members/" + ownerID.String() + "/allowance", `{"category_id": "` + transportID.String() + `"}`},
+		{"allowances-set-invalid", http.MethodPut, "/households/" + householdID.String() + "/members/" + childID.String() + "/allowance", `{"category_id": "` + unknownID.String() + `"}`},
+		{"allowances-adjust", http.MethodPost, "/households/" + householdID.String() + "/members/" + childID.String() + "/allowance", `{"amount": 20, "note": "Weekly"}`},
+		{"allowances-adjust-insufficient", http.MethodPost, "/households/" + householdID.String() + "/members/" + childID.String() + "/allowance", `{"amount": -20}`},
+		{"allowances-get-unknown-member", http.MethodGet, "/households/" + householdID.String() + "/members/" + unknownID.String() + "/allowance", ""},
+
+		{"exports-create-invalid", http.MethodPost, "/exports", `{"format": "xml"}`},
+		{"exports-get-unknown", http.MethodGet, "/exports/" + unknownID.String(), ""},
+		{"exports-download-unsigned", http.MethodGet, "/exports/" + unknownID.String() + "/download", ""},
+		{"exports-download-unknown", http.MethodGet, exportDownload, ""},
+
+		{"imports-list", http.MethodGet, "/imports", ""},
+		{"imports-get-unknown", http.MethodGet, "/imports/" + unknownID.String(), ""},
+		{"imports-approve-unknown", http.MethodPost, "/imports/" + unknownID.String() + "/approve", ""},
+
+		{"reports-share-invalid", http.MethodPost, "/reports/share", `{"month": "March"}`},
+		{"reports-shared", http.MethodGet, sharedReport, ""},
+		{"reports-shared-unsigned", http.MethodGet, "/reports/shared/2024-03", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			rec := httptest.NewRecorder()
+			newContractAPI(t).ServeHTTP(rec, req)
+
+			got := contractResponse(t, rec)
+			path := filepath.Join("testdata", "contract", tt.name+".json")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("reading golden file, run `make goldens` to create it: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("response of %s %s changed, run `make goldens` if this is intended\n--- got\n%s\n--- want\n%s", tt.method, tt.path, got, want)
+			}
+		})
+	}
+}
+
+// contractResponse renders a response as its golden file: generated IDs and timestamps of the
+// moment are replaced by placeholders, so the file only changes with the shape of the response
+func contractResponse(t *testing.T, rec *httptest.ResponseRecorder) []byte {
+	t.Helper()
+	var body any = strings.TrimSpace(rec.Body.String())
+	var decoded any
+	if json.Unmarshal(rec.Body.Bytes(), &decoded) == nil {
+		body = normalize(decoded)
+	} else {
+		body = normalize(body)
+	}
+
+	golden := struct {
+		Status      int    `json:"status"`
+		ContentType string `json:"content_type,omitempty"`
+		Location    string `json:"location,omitempty"`
+		Body        any    `json:"body"`
+	}{
+		Status:      rec.Code,
+		ContentType: rec.Header().Get("Content-Type"),
+		Location:    normalize(rec.Header().Get("Location")).(string),
+		Body:        body,
+	}
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(golden); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+func normalize(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = normalize(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+		return v
+	case string:
+		if at, err := time.Parse(time.RFC3339Nano, v); err == nil && time.Since(at).Abs() < time.Hour {
+			return "<now>"
+		}
+		return uuidPattern.ReplaceAllStringFunc(v, func(id string) string {
+			for _, fixed := range fixtureIDs {
+				if id == fixed.String() {
+					return id
+				}
+			}
+			return "<generated-id>"
+		})
+	default:
+		return v
+	}
+}
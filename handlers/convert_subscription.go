@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// ConvertSubscription turns a detected subscription into a recurring expenditure generating its
+// charges from the next one on
+func (h *SubscriptionHandler) ConvertSubscription(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling convert subscription request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathUUID(w, r, "/subscriptions/detected/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Converting subscription", "id", id)
+
+	now := time.Now()
+	subscriptions, err := h.detect(now)
+	if err != nil {
+		h.logger.Error("Failed to detect subscriptions", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		if subscription.ID != id {
+			continue
+		}
+
+		recurring, err := subscription.Recurring(now)
+		if err != nil {
+			h.logger.Error("Failed to create recurring expenditure from subscription", "error", err, "id", id)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if err := h.recurring.AddRecurring(recurring); err != nil {
+			h.logger.Error("Failed to add recurring expenditure", "error", err, "id", recurring.ID)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.logger.Info("Successfully converted subscription", "id", id, "recurring_id", recurring.ID, "frequency", recurring.Frequency)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(recurring)
+		return
+	}
+
+	// Converted subscriptions are managed by their recurring expenditure and no longer detected
+	h.logger.Warn("Detected subscription not found", "id", id)
+	http.Error(w, "detected subscription not found", http.StatusNotFound)
+}
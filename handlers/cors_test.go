@@ -0,0 +1,81 @@
+package handlers_test
+
+import (
+	"go-expense-tracker/handlers"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestParseCORSOrigins(t *testing.T) {
+	tests := []struct {
+		config string
+		want   []string
+	}{
+		{"", nil},
+		{"*", []string{"*"}},
+		{"https://app.example.com/, http://localhost:3000", []string{"https://app.example.com", "http://localhost:3000"}},
+		{" , https://app.example.com,", []string{"https://app.example.com"}},
+	}
+
+	for _, tt := range tests {
+		if got := handlers.ParseCORSOrigins(tt.config); !slices.Equal(got, tt.want) {
+			t.Errorf("ParseCORSOrigins(%q) = %q, want %q", tt.config, got, tt.want)
+		}
+	}
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	resource := handlers.Methods{
+		http.MethodGet:  func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) },
+		http.MethodPost: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusCreated) },
+	}
+
+	tests := []struct {
+		name        string
+		origins     []string
+		method      string
+		origin      string
+		preflight   bool
+		want        int
+		wantOrigin  string
+		wantMethods string
+		wantHeaders string
+	}{
+		{"preflight from an allowed origin", []string{"https://app.example.com"}, http.MethodOptions, "https://app.example.com", true, http.StatusNoContent, "https://app.example.com", "GET, HEAD, OPTIONS, POST", "Content-Type, X-Member-ID"},
+		{"preflight from another origin", []string{"https://app.example.com"}, http.MethodOptions, "https://evil.example.com", true, http.StatusNoContent, "", "GET, HEAD, OPTIONS, POST", ""},
+		{"preflight from any origin", []string{"*"}, http.MethodOptions, "https://evil.example.com", true, http.StatusNoContent, "*", "GET, HEAD, OPTIONS, POST", "Content-Type, X-Member-ID"},
+		{"request from an allowed origin", []string{"https://app.example.com"}, http.MethodPost, "https://app.example.com", false, http.StatusCreated, "https://app.example.com", "", ""},
+		{"request from another origin", []string{"https://app.example.com"}, http.MethodGet, "https://evil.example.com", false, http.StatusOK, "", "", ""},
+		{"same-origin request", []string{"https://app.example.com"}, http.MethodGet, "", false, http.StatusOK, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/expenditures", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+				req.Header.Set("Access-Control-Request-Headers", "Content-Type, X-Member-ID")
+			}
+			rec := httptest.NewRecorder()
+			handlers.CORSMiddleware(tt.origins, resource).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			for header, want := range map[string]string{
+				"Access-Control-Allow-Origin":  tt.wantOrigin,
+				"Access-Control-Allow-Methods": tt.wantMethods,
+				"Access-Control-Allow-Headers": tt.wantHeaders,
+			} {
+				if got := rec.Header().Get(header); got != want {
+					t.Errorf("%s = %q, want %q", header, got, want)
+				}
+			}
+		})
+	}
+}
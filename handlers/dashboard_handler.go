@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"go-expense-tracker/reports"
+	"log/slog"
+	"net/http"
+)
+
+// dashboardCacheControl lets clients keep the dashboard but revalidate it with its ETag on every
+// load, which is answered with 304 Not Modified while the server's copy is unchanged
+const dashboardCacheControl = "private, no-cache"
+
+type DashboardHandler struct {
+	dashboard *reports.DashboardCache
+	logger    *slog.Logger
+}
+
+func NewDashboardHandler(dashboard *reports.DashboardCache, logger *slog.Logger) *DashboardHandler {
+	return &DashboardHandler{
+		dashboard: dashboard,
+		logger:    logger,
+	}
+}
+
+// InvalidateDashboard drops the cached dashboard after every request that may change data, so the
+// home screen shows the change on its next load
+func InvalidateDashboard(dashboard *reports.DashboardCache, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			dashboard.Invalidate()
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *BudgetHandler) DeleteBudget(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling delete budget request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodDelete {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/budgets/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Deleting budget", "id", id)
+
+	err := h.budgets.DeleteBudget(id)
+	if err != nil {
+		if err == domain.ErrBudgetNotFound {
+			h.logger.Warn("Budget not found for deletion", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to delete budget", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully deleted budget", "id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+// DeleteDraft discards a draft that will not be published
+func (h *ExpenditureHandler) DeleteDraft(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling delete draft request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodDelete {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.drafts == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	id, ok := pathID(w, r, "/expenditures/drafts/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Deleting draft", "id", id)
+
+	err := h.drafts.DeleteDraft(id)
+	if err != nil {
+		if err == domain.ErrDraftNotFound {
+			h.logger.Warn("Draft not found for deletion", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to delete draft", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully deleted draft", "id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,9 +1,7 @@
 package handlers
 
 import (
-	"go-expense-tracker/domain"
 	"net/http"
-	"strings"
 )
 
 func (h *ExpenditureHandler) DeleteExpenditure(w http.ResponseWriter, r *http.Request) {
@@ -15,18 +13,15 @@ func (h *ExpenditureHandler) DeleteExpenditure(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	id := strings.TrimPrefix(r.URL.Path, "/expenditures/")
+	id, ok := pathID(w, r, "/expenditures/")
+	if !ok {
+		return
+	}
 	h.logger.Debug("Deleting expenditure", "id", id)
 
-	err := h.service.DeleteExpenditure(id)
-	if err != nil {
-		if err == domain.ErrExpenditureNotFound {
-			h.logger.Warn("Expenditure not found for deletion", "id", id)
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		}
-		h.logger.Error("Failed to delete expenditure", "id", id, "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := h.expenditures.Delete(id); err != nil {
+		h.logger.Warn("Failed to delete expenditure", "id", id, "error", err)
+		http.Error(w, err.Error(), serviceStatus(err))
 		return
 	}
 
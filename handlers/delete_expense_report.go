@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *ExpenseReportHandler) DeleteExpenseReport(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling delete expense report request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodDelete {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/expense-reports/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Deleting expense report", "id", id)
+
+	report, err := h.reports.GetExpenseReportByID(id)
+	if err != nil {
+		if err == domain.ErrExpenseReportNotFound {
+			h.logger.Warn("Expense report not found for deletion", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get expense report", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Claims under review or approved are kept as a record of what was sent to the employer
+	if !report.Editable() {
+		h.logger.Warn("Cannot delete submitted expense report", "id", id, "status", report.Status)
+		http.Error(w, domain.ErrExpenseReportNotDraft.Error(), http.StatusConflict)
+		return
+	}
+
+	err = h.reports.DeleteExpenseReport(id)
+	if err != nil {
+		if err == domain.ErrExpenseReportNotFound {
+			h.logger.Warn("Expense report not found for deletion", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to delete expense report", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully deleted expense report", "id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *MerchantHandler) DeleteMerchant(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling delete merchant request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodDelete {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/merchants/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Deleting merchant", "id", id)
+
+	err := h.merchants.DeleteMerchant(id)
+	if err != nil {
+		if err == domain.ErrMerchantNotFound {
+			h.logger.Warn("Merchant not found for deletion", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to delete merchant", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully deleted merchant", "id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
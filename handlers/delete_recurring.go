@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+// DeleteRecurring stops a recurring expenditure; the expenditures it generated are kept
+func (h *RecurringHandler) DeleteRecurring(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling delete recurring expenditure request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodDelete {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/recurring/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Deleting recurring expenditure", "id", id)
+
+	err := h.recurring.DeleteRecurring(id)
+	if err != nil {
+		if err == domain.ErrRecurringNotFound {
+			h.logger.Warn("Recurring expenditure not found for deletion", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to delete recurring expenditure", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully deleted recurring expenditure", "id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *RuleHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling delete rule request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodDelete {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/rules/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Deleting rule", "id", id)
+
+	err := h.rules.DeleteRule(id)
+	if err != nil {
+		if err == domain.ErrRuleNotFound {
+			h.logger.Warn("Rule not found for deletion", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to delete rule", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully deleted rule", "id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
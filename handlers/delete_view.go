@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *ViewHandler) DeleteView(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling delete view request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodDelete {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/views/")
+	if !ok {
+		return
+	}
+
+	err := h.views.DeleteView(id)
+	if err != nil {
+		if err == domain.ErrViewNotFound {
+			h.logger.Warn("View not found for deletion", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to delete view", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully deleted view", "id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
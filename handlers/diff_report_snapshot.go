@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// DiffReportSnapshot compares a snapshot with another snapshot given as against, or with the
+// report of the same month as it is now when against is omitted
+func (h *ReportHandler) DiffReportSnapshot(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling diff report snapshot request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/reports/snapshots/")
+	if !ok {
+		return
+	}
+	base, err := h.snapshots.GetReportSnapshotByID(id)
+	if err != nil {
+		if err == domain.ErrReportSnapshotNotFound {
+			h.logger.Warn("Report snapshot not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get report snapshot", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var other *domain.ReportSnapshot
+	if against := r.URL.Query().Get("against"); against != "" {
+		other, err = h.snapshots.GetReportSnapshotByID(against)
+		if err != nil {
+			if err == domain.ErrReportSnapshotNotFound {
+				h.logger.Warn("Report snapshot to compare against not found", "id", against)
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			h.logger.Error("Failed to get report snapshot", "id", against, "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if other.Month != base.Month {
+			h.logger.Warn("Report snapshots are of different months", "month", base.Month, "against_month", other.Month)
+			http.Error(w, "Report snapshots must be of the same month", http.StatusBadRequest)
+			return
+		}
+	} else {
+		other, err = h.snapshot.Compute(base.Month)
+		if err != nil {
+			h.logger.Error("Failed to compute current report", "month", base.Month, "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		other.ID = uuid.Nil
+	}
+
+	diff := base.Diff(other)
+
+	h.logger.Info("Successfully compared report snapshots", "id", id, "changed_categories", len(diff.Categories))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(diff)
+}
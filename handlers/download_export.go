@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"go-expense-tracker/signedurl"
+	"net/http"
+	"time"
+)
+
+// DownloadExport handles GET /exports/{id}/download, the file of a completed export. The link
+// must carry the expires and signature parameters of the download URL of GET /exports/{id}:
+// a missing or wrong signature is answered with 403 Forbidden, an expired link with 410 Gone
+func (h *ExportHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling download export request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/exports/")
+	if !ok {
+		return
+	}
+
+	if err := h.links.Verify(exportDownloadPath(id), r.URL.Query(), time.Now()); err != nil {
+		h.logger.Warn("Rejected export download link", "id", id, "error", err)
+		if err == signedurl.ErrLinkExpired {
+			http.Error(w, err.Error(), http.StatusGone)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	if _, err := h.getExportJob(id); err != nil {
+		if err == domain.ErrJobNotFound {
+			h.logger.Warn("Export not found for download", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get export", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result, header, err := h.jobs.Result(id)
+	if err != nil {
+		switch err {
+		case domain.ErrJobNotFound:
+			h.logger.Warn("Export not found for download", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case domain.ErrJobNotCompleted:
+			h.logger.Warn("Export file not available", "id", id)
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			h.logger.Error("Failed to get export file", "id", id, "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.logger.Info("Successfully served export", "id", id, "bytes", len(result))
+	for key, values := range header {
+		w.Header()[key] = values
+	}
+	w.Write(result)
+}
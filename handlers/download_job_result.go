@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+// DownloadJobResult serves the output of a completed job
+func (h *JobHandler) DownloadJobResult(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling download job result request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/jobs/")
+	if !ok {
+		return
+	}
+
+	result, header, err := h.jobs.Result(id)
+	if err != nil {
+		switch err {
+		case domain.ErrJobNotFound:
+			h.logger.Warn("Job not found for download", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case domain.ErrJobNotCompleted:
+			h.logger.Warn("Job result not available", "id", id)
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			h.logger.Error("Failed to get job result", "id", id, "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.logger.Info("Successfully served job result", "id", id, "bytes", len(result))
+	for key, values := range header {
+		w.Header()[key] = values
+	}
+	w.Write(result)
+}
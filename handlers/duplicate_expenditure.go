@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+func (h *ExpenditureHandler) DuplicateExpenditure(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling duplicate expenditure request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/expenditures/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Duplicating expenditure", "id", id)
+
+	var req DuplicateExpenditureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	duplicate, err := h.expenditures.Duplicate(id, req.Date, memberID(r.Context()))
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully duplicated expenditure", "id", duplicate.ID, "original_id", id, "date", duplicate.Date)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(duplicate)
+}
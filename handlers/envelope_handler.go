@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"go-expense-tracker/reports"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EnvelopeHandler struct {
+	envelopes    domain.EnvelopeRepository
+	expenditures domain.ExpenditureRepository
+	categories   domain.CategoryRepository
+	summaries    domain.SpendingSummaryRepository
+	logger       *slog.Logger
+}
+
+// NewEnvelopeHandler creates a new EnvelopeHandler; categories and summaries may be nil when the
+// storage has no support for them
+func NewEnvelopeHandler(envelopes domain.EnvelopeRepository, expenditures domain.ExpenditureRepository, categories domain.CategoryRepository, summaries domain.SpendingSummaryRepository, logger *slog.Logger) *EnvelopeHandler {
+	return &EnvelopeHandler{
+		envelopes:    envelopes,
+		expenditures: expenditures,
+		categories:   categories,
+		summaries:    summaries,
+		logger:       logger,
+	}
+}
+
+func EnvelopeRouter(handler *EnvelopeHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/envelopes":
+			Methods{http.MethodGet: handler.GetEnvelopes}.ServeHTTP(w, r)
+		case "/envelopes/allocations":
+			Methods{http.MethodGet: handler.GetEnvelopeAllocations}.ServeHTTP(w, r)
+		case "/envelopes/allocate":
+			Methods{http.MethodPost: handler.AllocateEnvelopes}.ServeHTTP(w, r)
+		case "/envelopes/move":
+			Methods{http.MethodPost: handler.MoveEnvelopeMoney}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// envelopeBalances computes the balance of every envelope from the stored allocations
+func envelopeBalances(envelopes domain.EnvelopeRepository, summaries domain.SpendingSummaryRepository, expenditures domain.ExpenditureRepository) ([]*domain.EnvelopeBalance, error) {
+	allocations, err := envelopes.GetAllEnvelopeAllocations()
+	if err != nil {
+		return nil, err
+	}
+
+	opened := domain.EnvelopesOpenedAt(allocations)
+	if opened.IsZero() {
+		return []*domain.EnvelopeBalance{}, nil
+	}
+
+	spending, err := reports.DailySpending(summaries, expenditures, opened, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return domain.EnvelopeBalances(allocations, spending), nil
+}
+
+// checkCategories returns the first of the categories that does not exist, or uuid.Nil when all
+// do or categories are unavailable
+func (h *EnvelopeHandler) checkCategories(ids ...uuid.UUID) (uuid.UUID, error) {
+	if h.categories == nil {
+		return uuid.Nil, nil
+	}
+
+	for _, id := range ids {
+		_, err := h.categories.GetCategoryByID(id.String())
+		if err == domain.ErrCategoryNotFound {
+			return id, nil
+		}
+		if err != nil {
+			return uuid.Nil, err
+		}
+	}
+	return uuid.Nil, nil
+}
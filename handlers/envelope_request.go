@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+)
+
+type EnvelopeAllocationRequest struct {
+	Income      float64 `json:"income"` // Optional incoming amount the allocations must not exceed
+	Allocations []struct {
+		CategoryId uuid.UUID `json:"categoryId"`
+		Amount     float64   `json:"amount"`
+	} `json:"allocations"`
+	Note string `json:"note"`
+}
+
+// EnvelopeAllocationResponse lists the new allocations and what is left of the income
+type EnvelopeAllocationResponse struct {
+	Allocations []*domain.EnvelopeAllocation `json:"allocations"`
+	Unallocated float64                      `json:"unallocated"`
+}
+
+type EnvelopeMoveRequest struct {
+	FromCategoryId uuid.UUID `json:"fromCategoryId"`
+	ToCategoryId   uuid.UUID `json:"toCategoryId"`
+	Amount         float64   `json:"amount"`
+	Note           string    `json:"note"`
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/app"
+	"net/http"
+)
+
+// EraseUser handles DELETE /users/me, which removes the member making the request from their
+// household. `mode=anonymize`, the default, keeps the expenditures they recorded for the household
+// without attributing them; `mode=delete` deletes them
+func (h *UserHandler) EraseUser(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling erase user request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodDelete {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	member := Member(r.Context())
+	if member == nil {
+		h.logger.Warn("Erasure requested without a member")
+		http.Error(w, errNoMember.Error(), http.StatusBadRequest)
+		return
+	}
+
+	mode, err := app.ParseErasureMode(r.URL.Query().Get("mode"))
+	if err != nil {
+		h.logger.Warn("Invalid erasure mode", "mode", r.URL.Query().Get("mode"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	erasure, err := h.privacy.Erase(member.ID, mode)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully erased user", "member_id", member.ID, "mode", mode)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(erasure)
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type EventHandler struct {
+	events  domain.ExpenditureEventStore
+	service domain.ExpenditureRepository
+	logger  *slog.Logger
+}
+
+// NewEventHandler creates a new EventHandler; reverted events are undone through service so
+// they are recorded like any other change
+func NewEventHandler(events domain.ExpenditureEventStore, service domain.ExpenditureRepository, logger *slog.Logger) *EventHandler {
+	return &EventHandler{
+		events:  events,
+		service: service,
+		logger:  logger,
+	}
+}
+
+func EventRouter(handler *EventHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/events" {
+			Methods{http.MethodGet: handler.GetExpenditureEvents}.ServeHTTP(w, r)
+			return
+		}
+
+		if _, sub, ok := splitPath(path, "/events/"); ok && sub == "revert" {
+			Methods{http.MethodPost: handler.RevertExpenditureEvent}.ServeHTTP(w, r)
+			return
+		}
+
+		http.NotFound(w, r)
+	})
+}
@@ -1,54 +1,183 @@
 package handlers
 
 import (
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker/app"
+	"go-expense-tracker/classifier"
 	"go-expense-tracker/domain"
 	"log/slog"
 	"net/http"
 	"strings"
+
+	"github.com/google/uuid"
 )
 
+// categoryLimitHeader flags responses for expenditures above their category's transaction limit
+const categoryLimitHeader = "X-Category-Limit-Warning"
+
 type ExpenditureHandler struct {
-	service domain.ExpenditureRepository
-	logger  *slog.Logger
+	service       domain.ExpenditureRepository
+	expenditures  *app.ExpenditureService
+	categories    domain.CategoryRepository
+	rules         domain.CategorizationRuleRepository
+	uncategorized uuid.UUID
+	drafts        domain.DraftRepository
+	pins          domain.PinRepository
+	guard         *QueryGuard
+	classifier    *classifier.Classifier
+	journals      JournalExport
+	logger        *slog.Logger
 }
 
-func NewExpenditureHandler(service domain.ExpenditureRepository, logger *slog.Logger) *ExpenditureHandler {
+// NewExpenditureHandler creates a new ExpenditureHandler. Changes to expenditures go through the
+// expenditures service, listings read the repository directly; categories may be nil when the
+// storage has no category support, rules when it keeps no categorization rules, drafts and pins
+// when it cannot keep drafts or pin expenditures, guard when listings are not limited and
+// classifier when categories are not suggested. Journals configures exports to plain-text accounting. Listings of uncategorized
+// expenditures are refused when uncategorized is uuid.Nil
+func NewExpenditureHandler(service domain.ExpenditureRepository, expenditures *app.ExpenditureService, categories domain.CategoryRepository, rules domain.CategorizationRuleRepository, uncategorized uuid.UUID, drafts domain.DraftRepository, pins domain.PinRepository, guard *QueryGuard, classifier *classifier.Classifier, journals JournalExport, logger *slog.Logger) *ExpenditureHandler {
 	return &ExpenditureHandler{
-		service: service,
-		logger:  logger,
+		service:       service,
+		expenditures:  expenditures,
+		categories:    categories,
+		rules:         rules,
+		uncategorized: uncategorized,
+		drafts:        drafts,
+		pins:          pins,
+		guard:         guard,
+		classifier:    classifier,
+		journals:      journals,
+		logger:        logger,
 	}
 }
 
 func ExpenditureRouter(handler *ExpenditureHandler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		path := r.URL.Path
-
-		if path == "/expenditures" {
-			switch r.Method {
-			case http.MethodGet:
-				handler.GetAllExpenditures(w, r)
-			case http.MethodPost:
-				handler.AddExpenditure(w, r)
-			default:
-				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			}
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		switch path {
+		case "/expenditures":
+			Methods{
+				http.MethodGet:  handler.GetAllExpenditures,
+				http.MethodPost: handler.AddExpenditure,
+			}.ServeHTTP(w, r)
+			return
+		case "/expenditures/bulk":
+			Methods{http.MethodPost: handler.AddExpenditures}.ServeHTTP(w, r)
+			return
+		case "/expenditures/drafts":
+			Methods{http.MethodGet: handler.GetAllDrafts}.ServeHTTP(w, r)
+			return
+		case "/expenditures/categorize":
+			Methods{http.MethodPost: handler.CategorizeExpenditures}.ServeHTTP(w, r)
+			return
+		case "/expenditures/export":
+			Methods{http.MethodGet: handler.ExportExpenditures}.ServeHTTP(w, r)
+			return
+		case "/expenditures/quick":
+			Methods{http.MethodPost: handler.QuickAddExpenditure}.ServeHTTP(w, r)
+			return
+		}
+
+		if _, sub, ok := splitPath(path, "/expenditures/drafts/"); ok && sub == "" {
+			Methods{http.MethodDelete: handler.DeleteDraft}.ServeHTTP(w, r)
 			return
 		}
 
-		if strings.HasPrefix(path, "/expenditures/") {
-			switch r.Method {
-			case http.MethodGet:
-				handler.GetExpenditureByID(w, r)
-			case http.MethodPut:
-				handler.UpdateExpenditure(w, r)
-			case http.MethodDelete:
-				handler.DeleteExpenditure(w, r)
-			default:
-				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			}
+		_, sub, ok := splitPath(path, "/expenditures/")
+		if !ok {
+			http.NotFound(w, r)
 			return
 		}
 
-		http.NotFound(w, r)
+		switch sub {
+		case "":
+			Methods{
+				http.MethodGet:    handler.GetExpenditureByID,
+				http.MethodPut:    handler.UpdateExpenditure,
+				http.MethodDelete: handler.DeleteExpenditure,
+			}.ServeHTTP(w, r)
+		case "pin":
+			Methods{
+				http.MethodPut:    func(w http.ResponseWriter, r *http.Request) { handler.SetPinned(w, r, true) },
+				http.MethodDelete: func(w http.ResponseWriter, r *http.Request) { handler.SetPinned(w, r, false) },
+			}.ServeHTTP(w, r)
+		case "refunds":
+			Methods{
+				http.MethodGet:  handler.GetRefunds,
+				http.MethodPost: handler.RefundExpenditure,
+			}.ServeHTTP(w, r)
+		case "publish":
+			Methods{http.MethodPost: handler.PublishDraft}.ServeHTTP(w, r)
+		case "clear":
+			Methods{http.MethodPost: handler.ClearExpenditure}.ServeHTTP(w, r)
+		case "cancel":
+			Methods{http.MethodPost: handler.CancelExpenditure}.ServeHTTP(w, r)
+		case "unlock":
+			Methods{http.MethodPost: handler.UnlockExpenditure}.ServeHTTP(w, r)
+		case "duplicate":
+			Methods{http.MethodPost: handler.DuplicateExpenditure}.ServeHTTP(w, r)
+		case "category-suggestions":
+			Methods{http.MethodGet: handler.GetCategorySuggestions}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
 	})
 }
+
+// serviceStatus returns the status code to reply with for an error of an application service
+func serviceStatus(err error) int {
+	switch app.KindOf(err) {
+	case app.KindInvalid:
+		return http.StatusBadRequest
+	case app.KindNotFound:
+		return http.StatusNotFound
+	case app.KindConflict:
+		return http.StatusConflict
+	case app.KindRejected:
+		return http.StatusUnprocessableEntity
+	case app.KindUnavailable:
+		return http.StatusServiceUnavailable
+	case app.KindGone:
+		return http.StatusGone
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// validateOnly reports whether a mutation should only be validated, requested with ?validate=true
+// or Prefer: handling=validate
+func validateOnly(r *http.Request) bool {
+	return r.URL.Query().Get("validate") == "true" || prefers(r, "handling=validate")
+}
+
+// prefers reports whether the Prefer header of the request lists preference
+func prefers(r *http.Request, preference string) bool {
+	for _, p := range strings.Split(r.Header.Get("Prefer"), ",") {
+		if strings.EqualFold(strings.TrimSpace(p), preference) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeValidated replies to a validate-only request with the normalized expenditure that would have been saved
+func (h *ExpenditureHandler) writeValidated(w http.ResponseWriter, r *http.Request, result *app.ExpenditureResult) {
+	h.logger.Info("Validated expenditure without saving", "id", result.Expenditure.ID, "method", r.Method)
+	if r.Header.Get("Prefer") != "" {
+		w.Header().Set("Preference-Applied", "handling=validate")
+	}
+	flagLimit(w, result)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result.Expenditure)
+}
+
+// flagLimit marks the response of an expenditure above its category's limit
+func flagLimit(w http.ResponseWriter, result *app.ExpenditureResult) {
+	if result.OverLimit == nil {
+		return
+	}
+	expenditure, category := result.Expenditure, result.OverLimit
+	w.Header().Set(categoryLimitHeader, fmt.Sprintf("%s limit of %.2f exceeded by %.2f", category.Name, category.TransactionLimit, expenditure.Amount-category.TransactionLimit))
+}
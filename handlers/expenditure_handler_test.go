@@ -0,0 +1,210 @@
+package handlers_test
+
+import (
+	"errors"
+	"go-expense-tracker/app"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/handlers"
+	"go-expense-tracker/services"
+	"go-expense-tracker/storagetest"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func newTestRouter(t *testing.T, repo domain.ExpenditureRepository) http.Handler {
+	t.Helper()
+	logger := slog.New(slog.DiscardHandler)
+	expenditures := app.NewExpenditureService(repo, nil, uuid.Nil, nil, nil, domain.RejectFutureDates, logger)
+	handler := handlers.NewExpenditureHandler(repo, expenditures, nil, nil, uuid.Nil, nil, nil, nil, nil, handlers.JournalExport{}, logger)
+	return handlers.ExpenditureRouter(handler)
+}
+
+func newTestExpenditure(t *testing.T) *domain.Expenditure {
+	t.Helper()
+	expenditure, err := domain.NewExpenditure("Lunch", 12.5, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), uuid.New())
+	if err != nil {
+		t.Fatalf("creating expenditure: %v", err)
+	}
+	return expenditure
+}
+
+func TestStorageFailures(t *testing.T) {
+	expenditure := newTestExpenditure(t)
+	path := "/expenditures/" + expenditure.ID.String()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		fail   storagetest.Method
+		err    error
+		want   int
+	}{
+		{"get succeeds", http.MethodGet, path, "", nil, http.StatusOK},
+		{"get unknown", http.MethodGet, "/expenditures/" + uuid.NewString(), "", nil, http.StatusNotFound},
+		{"get not found", http.MethodGet, path, storagetest.GetExpenditureByID, domain.ErrExpenditureNotFound, http.StatusNotFound},
+		{"get timeout", http.MethodGet, path, storagetest.GetExpenditureByID, storagetest.ErrTimeout, http.StatusInternalServerError},
+		{"list fails", http.MethodGet, "/expenditures", storagetest.GetAllExpenditures, errors.New("connection reset"), http.StatusInternalServerError},
+		{"delete not found", http.MethodDelete, path, storagetest.DeleteExpenditure, domain.ErrExpenditureNotFound, http.StatusNotFound},
+		{"delete timeout", http.MethodDelete, path, storagetest.DeleteExpenditure, storagetest.ErrTimeout, http.StatusInternalServerError},
+		{"delete with refunds", http.MethodDelete, path, storagetest.DeleteExpenditure, domain.ErrExpenditureHasRefunds, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := storagetest.NewRepository(expenditure)
+			if tt.err != nil {
+				repo.FailWith(tt.fail, tt.err)
+			}
+
+			rec := httptest.NewRecorder()
+			newTestRouter(t, repo).ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %q)", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestFailNextRecovers(t *testing.T) {
+	expenditure := newTestExpenditure(t)
+	repo := storagetest.NewRepository(expenditure)
+	repo.FailNext(storagetest.GetExpenditureByID, storagetest.ErrTimeout)
+	router := newTestRouter(t, repo)
+
+	for _, want := range []int{http.StatusInternalServerError, http.StatusOK} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/expenditures/"+expenditure.ID.String(), nil))
+		if rec.Code != want {
+			t.Errorf("status = %d, want %d", rec.Code, want)
+		}
+	}
+
+	if calls := repo.Calls(storagetest.GetExpenditureByID); calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestSlowStorageTimesOut(t *testing.T) {
+	expenditure := newTestExpenditure(t)
+	repo := storagetest.NewRepository(expenditure)
+	repo.SetLatency(200*time.Millisecond, storagetest.GetExpenditureByID)
+	router := http.TimeoutHandler(newTestRouter(t, repo), 20*time.Millisecond, "storage too slow")
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/expenditures/"+expenditure.ID.String(), nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestExpenditurePaths(t *testing.T) {
+	expenditure := newTestExpenditure(t)
+	id := expenditure.ID.String()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{"trailing slash", http.MethodGet, "/expenditures/" + id + "/", http.StatusOK},
+		{"upper case ID", http.MethodGet, "/expenditures/" + strings.ToUpper(id), http.StatusOK},
+		{"collection with trailing slash", http.MethodGet, "/expenditures/", http.StatusOK},
+		{"malformed ID", http.MethodGet, "/expenditures/not-a-uuid", http.StatusBadRequest},
+		{"malformed ID of sub-resource", http.MethodPost, "/expenditures/not-a-uuid/duplicate", http.StatusBadRequest},
+		{"unknown sub-resource", http.MethodGet, "/expenditures/" + id + "/extra", http.StatusNotFound},
+		{"nested too deep", http.MethodGet, "/expenditures/" + id + "/refunds/extra", http.StatusNotFound},
+		{"empty ID", http.MethodGet, "/expenditures//refunds", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			newTestRouter(t, storagetest.NewRepository(expenditure)).ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %q)", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestExpenditureMethods(t *testing.T) {
+	expenditure := newTestExpenditure(t)
+	path := "/expenditures/" + expenditure.ID.String()
+
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		want      int
+		wantAllow string
+	}{
+		{"options", http.MethodOptions, path, http.StatusNoContent, "DELETE, GET, HEAD, OPTIONS, PUT"},
+		{"options of sub-resource", http.MethodOptions, path + "/refunds", http.StatusNoContent, "GET, HEAD, OPTIONS, POST"},
+		{"head", http.MethodHead, path, http.StatusOK, ""},
+		{"head unknown", http.MethodHead, "/expenditures/" + uuid.NewString(), http.StatusNotFound, ""},
+		{"not allowed", http.MethodPatch, path, http.StatusMethodNotAllowed, "DELETE, GET, HEAD, OPTIONS, PUT"},
+		{"head of post-only resource", http.MethodHead, path + "/duplicate", http.StatusMethodNotAllowed, "OPTIONS, POST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			newTestRouter(t, storagetest.NewRepository(expenditure)).ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %q)", rec.Code, tt.want, rec.Body.String())
+			}
+			if allow := rec.Header().Get("Allow"); allow != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", allow, tt.wantAllow)
+			}
+		})
+	}
+}
+
+func TestRefundIntegrity(t *testing.T) {
+	repo := services.NewMemoryService(slog.New(slog.DiscardHandler))
+	expenditure := newTestExpenditure(t)
+	if err := repo.AddExpenditure(expenditure); err != nil {
+		t.Fatalf("adding expenditure: %v", err)
+	}
+	router := newTestRouter(t, repo)
+	path := "/expenditures/" + expenditure.ID.String()
+	update := func(amount string) string {
+		return `{"description": "Lunch", "amount": ` + amount + `, "date": "2024-03-01T00:00:00Z", "categoryId": "` + expenditure.CategoryId.String() + `"}`
+	}
+
+	// The steps run in order against the same storage
+	steps := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"refund", http.MethodPost, path + "/refunds", `{"amount": 10, "date": "2024-03-02T00:00:00Z"}`, http.StatusCreated},
+		{"refund beyond the rest", http.MethodPost, path + "/refunds", `{"amount": 5, "date": "2024-03-02T00:00:00Z"}`, http.StatusUnprocessableEntity},
+		{"lower below refunds", http.MethodPut, path, update("8"), http.StatusUnprocessableEntity},
+		{"raise", http.MethodPut, path, update("20"), http.StatusOK},
+		{"delete with refunds", http.MethodDelete, path, "", http.StatusConflict},
+		{"still there", http.MethodGet, path, "", http.StatusOK},
+	}
+
+	for _, step := range steps {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(step.method, step.path, strings.NewReader(step.body)))
+		if rec.Code != step.want {
+			t.Fatalf("%s: status = %d, want %d (body %q)", step.name, rec.Code, step.want, rec.Body.String())
+		}
+	}
+}
@@ -1,13 +1,105 @@
 package handlers
 
 import (
+	"context"
 	"github.com/google/uuid"
+	"go-expense-tracker/app"
+	"go-expense-tracker/domain"
 	"time"
 )
 
 type ExpenditureRequest struct {
-	Description string    `json:"description"`
+	Description string           `json:"description"`
+	Amount      float64          `json:"amount"`
+	Date        time.Time        `json:"date"`
+	CategoryId  uuid.UUID        `json:"categoryId"`
+	Tags        []string         `json:"tags"`
+	Quantity    float64          `json:"quantity"`   // Optional, for per-unit expenses such as mileage
+	UnitPrice   float64          `json:"unitPrice"`  // Optional, the amount is derived from it when omitted
+	Unit        string           `json:"unit"`       // Required with a quantity, e.g. "km"
+	TaxRate     float64          `json:"taxRate"`    // Optional VAT rate in percent, the tax amount is derived from it when omitted
+	TaxAmount   float64          `json:"taxAmount"`  // Optional tax included in the amount
+	MerchantId  uuid.UUID        `json:"merchantId"` // Optional, matched from the description when omitted
+	Location    *LocationRequest `json:"location"`   // Optional, sent by mobile clients
+	Status      string           `json:"status"`     // Optional, "pending" for transactions not settled yet
+	AccountId   uuid.UUID        `json:"accountId"`  // Optional, the account paid from for reconciliation
+	// Optional, the amount and currency paid abroad, e.g. 25.00 EUR; amount is then what was settled at home
+	OriginalAmount   float64 `json:"originalAmount"`
+	OriginalCurrency string  `json:"originalCurrency"`
+}
+
+type LocationRequest struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	PlaceName string  `json:"placeName"`
+	City      string  `json:"city"`
+}
+
+// location validates the optional location of a request; nil means no location
+func (req ExpenditureRequest) location() (*domain.Location, error) {
+	if req.Location == nil {
+		return nil, nil
+	}
+	return domain.NewLocation(req.Location.Latitude, req.Location.Longitude, req.Location.PlaceName, req.Location.City)
+}
+
+// input returns the request as input of the expenditure service, attributed to the household
+// member of the context
+func (req ExpenditureRequest) input(ctx context.Context) app.ExpenditureInput {
+	input := app.ExpenditureInput{
+		Description:      req.Description,
+		Amount:           req.Amount,
+		Date:             req.Date,
+		CategoryId:       req.CategoryId,
+		Tags:             req.Tags,
+		Quantity:         req.Quantity,
+		UnitPrice:        req.UnitPrice,
+		Unit:             req.Unit,
+		TaxRate:          req.TaxRate,
+		TaxAmount:        req.TaxAmount,
+		MerchantId:       req.MerchantId,
+		Status:           req.Status,
+		AccountId:        req.AccountId,
+		CreatedBy:        memberID(ctx),
+		OriginalAmount:   req.OriginalAmount,
+		OriginalCurrency: req.OriginalCurrency,
+	}
+	if req.Location != nil {
+		input.Location = &domain.Location{
+			Latitude:  req.Location.Latitude,
+			Longitude: req.Location.Longitude,
+			PlaceName: req.Location.PlaceName,
+			City:      req.Location.City,
+		}
+	}
+	return input
+}
+
+// CategorizeRequest moves several expenditures to one category at once
+type CategorizeRequest struct {
+	IDs        []uuid.UUID `json:"ids"`
+	CategoryId uuid.UUID   `json:"categoryId"`
+}
+
+// DuplicateExpenditureRequest is the optional body of a duplicate request
+type DuplicateExpenditureRequest struct {
+	Date *time.Time `json:"date"` // Defaults to the date of the original
+}
+
+// ClearExpenditureRequest is the optional body of a clear request, for settlements that differ
+// from the pending transaction
+type ClearExpenditureRequest struct {
+	Amount float64    `json:"amount"` // Defaults to the pending amount
+	Date   *time.Time `json:"date"`   // Defaults to the pending date
+}
+
+// RefundRequest records a partial or full refund of an expenditure
+type RefundRequest struct {
 	Amount      float64   `json:"amount"`
-	Date        time.Time `json:"date"`
-	CategoryId  uuid.UUID `json:"categoryId"`
+	Date        time.Time `json:"date"`        // Defaults to today
+	Description string    `json:"description"` // Defaults to "Refund: " and the original's description
+}
+
+type QuickExpenditureRequest struct {
+	Text string `json:"text"`
 }
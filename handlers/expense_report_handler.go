@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// ExpenseReportNotifier is told about every status change of an expense report
+type ExpenseReportNotifier interface {
+	NotifyExpenseReport(report *domain.ExpenseReport, event domain.ExpenseReportEvent)
+}
+
+type ExpenseReportHandler struct {
+	reports      domain.ExpenseReportRepository
+	expenditures domain.ExpenditureRepository
+	reviewers    map[string]string // Bearer token to reviewer name
+	notifier     ExpenseReportNotifier
+	formatting   ExportFormatting
+	logger       *slog.Logger
+}
+
+// NewExpenseReportHandler creates a new ExpenseReportHandler. reviewers maps access tokens to
+// the names of users holding the reviewer role; when empty anyone may review reports.
+// notifier may be nil
+func NewExpenseReportHandler(reports domain.ExpenseReportRepository, expenditures domain.ExpenditureRepository, reviewers map[string]string, notifier ExpenseReportNotifier, formatting ExportFormatting, logger *slog.Logger) *ExpenseReportHandler {
+	return &ExpenseReportHandler{
+		reports:      reports,
+		expenditures: expenditures,
+		reviewers:    reviewers,
+		notifier:     notifier,
+		formatting:   formatting,
+		logger:       logger,
+	}
+}
+
+func ExpenseReportRouter(handler *ExpenseReportHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/expense-reports" {
+			Methods{
+				http.MethodGet:  handler.GetAllExpenseReports,
+				http.MethodPost: handler.AddExpenseReport,
+			}.ServeHTTP(w, r)
+			return
+		}
+
+		_, sub, ok := splitPath(path, "/expense-reports/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch sub {
+		case "":
+			Methods{
+				http.MethodGet:    handler.GetExpenseReportByID,
+				http.MethodPut:    handler.UpdateExpenseReport,
+				http.MethodDelete: handler.DeleteExpenseReport,
+			}.ServeHTTP(w, r)
+		case "submit":
+			Methods{http.MethodPost: handler.SubmitExpenseReport}.ServeHTTP(w, r)
+		case "approve":
+			Methods{http.MethodPost: handler.ApproveExpenseReport}.ServeHTTP(w, r)
+		case "reject":
+			Methods{http.MethodPost: handler.RejectExpenseReport}.ServeHTTP(w, r)
+		case "reimburse":
+			Methods{http.MethodPost: handler.ReimburseExpenseReport}.ServeHTTP(w, r)
+		case "export":
+			Methods{http.MethodGet: handler.ExportExpenseReport}.ServeHTTP(w, r)
+		case "history":
+			Methods{http.MethodGet: handler.GetExpenseReportHistory}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// ExpenseReportDetail is an expense report with its claimed expenditures and their total
+type ExpenseReportDetail struct {
+	*domain.ExpenseReport
+	Expenditures []*domain.Expenditure `json:"expenditures"`
+	Total        float64               `json:"total"`
+}
+
+// loadExpenditures returns the expenditures claimed in a report, in the report's order;
+// with skipMissing set, expenditures deleted since they were claimed are left out
+func (h *ExpenseReportHandler) loadExpenditures(report *domain.ExpenseReport, skipMissing bool) ([]*domain.Expenditure, error) {
+	expenditures := make([]*domain.Expenditure, 0, len(report.ExpenditureIDs))
+	for _, id := range report.ExpenditureIDs {
+		expenditure, err := h.expenditures.GetExpenditureByID(id.String())
+		if err == domain.ErrExpenditureNotFound && skipMissing {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		expenditures = append(expenditures, expenditure)
+	}
+	return expenditures, nil
+}
+
+func (h *ExpenseReportHandler) detail(report *domain.ExpenseReport) (*ExpenseReportDetail, error) {
+	expenditures, err := h.loadExpenditures(report, true)
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0.0
+	for _, expenditure := range expenditures {
+		total += expenditure.Amount
+	}
+
+	return &ExpenseReportDetail{
+		ExpenseReport: report,
+		Expenditures:  expenditures,
+		Total:         total,
+	}, nil
+}
+
+// checkExpenditures verifies that every claimed expenditure exists and is not already part
+// of another report; it returns the status code to reply with when the check fails
+func (h *ExpenseReportHandler) checkExpenditures(report *domain.ExpenseReport) (int, error) {
+	if _, err := h.loadExpenditures(report, false); err != nil {
+		if err == domain.ErrExpenditureNotFound {
+			return http.StatusBadRequest, err
+		}
+		return http.StatusInternalServerError, err
+	}
+
+	others, err := h.reports.GetAllExpenseReports()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	for _, other := range others {
+		if other.ID == report.ID {
+			continue
+		}
+		for _, id := range report.ExpenditureIDs {
+			if other.Contains(id) {
+				return http.StatusConflict, domain.ErrExpenditureAlreadyClaimed
+			}
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+// reviewer returns the name of the reviewer authenticated by the request's bearer token
+func (h *ExpenseReportHandler) reviewer(r *http.Request) (string, bool) {
+	if len(h.reviewers) == 0 {
+		return "anonymous", true
+	}
+
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		return "", false
+	}
+
+	for candidate, name := range h.reviewers {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(candidate)) == 1 {
+			return name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"github.com/google/uuid"
+)
+
+type ExpenseReportRequest struct {
+	Title          string      `json:"title"`
+	Claimant       string      `json:"claimant"`
+	ExpenditureIds []uuid.UUID `json:"expenditureIds"`
+}
+
+// ExpenseReportReviewRequest is the optional body of the lifecycle endpoints
+type ExpenseReportReviewRequest struct {
+	Comment string `json:"comment"`
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-expense-tracker/domain"
+	"io"
+	"net/http"
+)
+
+func (h *ExpenseReportHandler) SubmitExpenseReport(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, domain.ExpenseReportActionSubmit, false, func(report *domain.ExpenseReport, _, _ string) error {
+		return report.Submit(report.Claimant)
+	})
+}
+
+func (h *ExpenseReportHandler) ApproveExpenseReport(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, domain.ExpenseReportActionApprove, true, (*domain.ExpenseReport).Approve)
+}
+
+func (h *ExpenseReportHandler) RejectExpenseReport(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, domain.ExpenseReportActionReject, true, (*domain.ExpenseReport).Reject)
+}
+
+func (h *ExpenseReportHandler) ReimburseExpenseReport(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, domain.ExpenseReportActionReimburse, true, (*domain.ExpenseReport).MarkReimbursed)
+}
+
+// transition applies a lifecycle action to the report addressed by /expense-reports/{id}/{action}.
+// Actions taken by reviewers require a reviewer token; the claimant submits
+func (h *ExpenseReportHandler) transition(w http.ResponseWriter, r *http.Request, action string, needsReviewer bool, apply func(report *domain.ExpenseReport, actor, comment string) error) {
+	h.logger.Info("Handling "+action+" expense report request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/expense-reports/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Changing expense report status", "id", id, "action", action)
+
+	actor := ""
+	if needsReviewer {
+		reviewer, ok := h.reviewer(r)
+		if !ok {
+			h.logger.Warn("Expense report action requires a reviewer", "id", id, "action", action, "remote_addr", r.RemoteAddr)
+			http.Error(w, "Reviewer token required", http.StatusUnauthorized)
+			return
+		}
+		actor = reviewer
+	}
+
+	// The body is optional and carries the reviewer's comment
+	var req ExpenseReportReviewRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("Failed to decode review request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.reports.GetExpenseReportByID(id)
+	if err != nil {
+		if err == domain.ErrExpenseReportNotFound {
+			h.logger.Warn("Expense report not found", "id", id, "action", action)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get expense report", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = apply(report, actor, req.Comment)
+	if err != nil {
+		h.logger.Warn("Invalid expense report status change", "id", id, "action", action, "status", report.Status, "error", err)
+		if err == domain.ErrExpenseReportCommentRequired {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	err = h.reports.UpdateExpenseReport(report)
+	if err != nil {
+		h.logger.Error("Failed to update expense report", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if h.notifier != nil {
+		h.notifier.NotifyExpenseReport(report, report.LastEvent())
+	}
+
+	h.logger.Info("Successfully changed expense report status", "id", id, "status", report.Status, "actor", actor)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+// ExportArchive downloads the archived expenditures as gzip-compressed JSON lines
+func (h *ArchiveHandler) ExportArchive(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling export archive request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/admin/archive/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Exporting archive", "id", id)
+
+	expenditures, err := h.archives.GetArchivedExpenditures(id)
+	if err != nil {
+		if err == domain.ErrArchiveNotFound {
+			h.logger.Warn("Archive not found for export", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get archived expenditures", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="archive-%s.jsonl.gz"`, id))
+
+	gz := gzip.NewWriter(w)
+	encoder := json.NewEncoder(gz)
+	for _, expenditure := range expenditures {
+		if err := encoder.Encode(expenditure); err != nil {
+			h.logger.Error("Failed to write archive export", "id", id, "error", err)
+			return
+		}
+	}
+	if err := gz.Close(); err != nil {
+		h.logger.Error("Failed to write archive export", "id", id, "error", err)
+		return
+	}
+
+	h.logger.Info("Successfully exported archive", "id", id, "count", len(expenditures))
+}
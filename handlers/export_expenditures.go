@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"fmt"
+	"go-expense-tracker/anonymize"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/interchange"
+	"go-expense-tracker/ledger"
+	"net/http"
+	"time"
+)
+
+// ExportExpenditures downloads the expenditures in the ?from=&to= range as a file, JSON unless
+// ?format=ledger or ?format=beancount asks for a plain-text accounting journal, or ?format=ynab,
+// gnucash or gnucash-xml for a file to import into YNAB or GnuCash, with dates in
+// ?date_format= for the CSV files. With
+// ?anonymize=true descriptions, merchants, tags and places are replaced by pseudonyms and
+// amounts are jittered, so the data can be shared without exposing the real spending
+func (h *ExpenditureHandler) ExportExpenditures(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling export expenditures request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		h.logger.Warn("Invalid date range", "error", err, "query", r.URL.RawQuery)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.guard.checkSpan(from, to); err != nil {
+		h.logger.Warn("Rejected expenditure export over a long date range", "error", err, "query", r.URL.RawQuery)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var anonymizer *anonymize.Anonymizer
+	if r.URL.Query().Get("anonymize") == "true" {
+		anonymizer = anonymize.New(anonymize.DefaultJitter)
+	}
+
+	var create func(http.ResponseWriter) (entryWriter, error)
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" {
+		if journal, err := ledger.ParseFormat(format); err == nil {
+			create = func(w http.ResponseWriter) (entryWriter, error) {
+				return h.newJournal(w, journal)
+			}
+		} else if file, err := interchange.ParseFormat(format); err == nil {
+			layout, err := interchange.ParseDateFormat(r.URL.Query().Get("date_format"))
+			if err != nil {
+				h.logger.Warn("Invalid date format", "date_format", r.URL.Query().Get("date_format"))
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			create = func(w http.ResponseWriter) (entryWriter, error) {
+				return h.newInterchangeWriter(w, file, layout)
+			}
+		} else {
+			h.logger.Warn("Unsupported export format", "format", format)
+			http.Error(w, "Unsupported format, use json, ledger, beancount, ynab, gnucash or gnucash-xml", http.StatusBadRequest)
+			return
+		}
+	}
+
+	render := func(w http.ResponseWriter) error {
+		if create != nil {
+			return h.writeEntries(w, format, create, from, to, anonymizer)
+		}
+
+		stream := newJSONStream(w, r)
+		extension := "json"
+		if stream.ndjson {
+			extension = "jsonl"
+		}
+		filename := "expenditures-" + time.Now().Format("2006-01-02")
+		if anonymizer != nil {
+			filename += "-anonymized"
+		}
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, extension))
+
+		err := domain.EachExpenditure(h.service, func(expenditure *domain.Expenditure) error {
+			if (!from.IsZero() && expenditure.Date.Before(from)) || (!to.IsZero() && !expenditure.Date.Before(to)) {
+				return nil
+			}
+			if anonymizer != nil {
+				expenditure = anonymizer.Expenditure(expenditure)
+			}
+			return stream.Write(expenditure)
+		})
+		if err == nil {
+			err = stream.Close()
+		}
+		if err != nil {
+			h.logger.Error("Failed to export expenditures", "error", err)
+			if !stream.Started() {
+				w.Header().Del("Content-Disposition")
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return err
+		}
+
+		h.logger.Info("Successfully exported expenditures", "count", stream.count, "anonymized", anonymizer != nil)
+		return nil
+	}
+
+	deferred, err := h.guard.deferLarge(w, r, h.service, "export", from, to, render)
+	if err != nil {
+		h.logger.Error("Failed to count expenditures", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !deferred {
+		render(w)
+	}
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/i18n"
+	"net/http"
+	"strings"
+)
+
+func (h *ExpenseReportHandler) ExportExpenseReport(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling export expense report request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/expense-reports/")
+	if !ok {
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	h.logger.Debug("Exporting expense report", "id", id, "format", format)
+
+	if format != "csv" && format != "pdf" {
+		h.logger.Warn("Unsupported export format", "format", format)
+		http.Error(w, "Unsupported format, use csv or pdf", http.StatusBadRequest)
+		return
+	}
+
+	locale, err := h.formatting.formatFor(r)
+	if err != nil {
+		h.logger.Warn("Unsupported export locale", "locale", r.URL.Query().Get("locale"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.reports.GetExpenseReportByID(id)
+	if err != nil {
+		if err == domain.ErrExpenseReportNotFound {
+			h.logger.Warn("Expense report not found for export", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get expense report", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	detail, err := h.detail(report)
+	if err != nil {
+		h.logger.Error("Failed to get expense report expenditures", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("expense-report-%s.%s", report.ID, format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	if format == "pdf" {
+		w.Header().Set("Content-Type", "application/pdf")
+		_, err = expenseReportPDF(r.Context(), detail, locale, h.formatting.Currency).WriteTo(w)
+	} else {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		err = writeExpenseReportCSV(r.Context(), w, detail, locale, h.formatting.Currency)
+	}
+	if err != nil {
+		h.logger.Error("Failed to write expense report export", "id", id, "format", format, "error", err)
+		return
+	}
+
+	h.logger.Info("Successfully exported expense report", "id", id, "format", format, "locale", locale.Locale, "count", len(detail.Expenditures))
+}
+
+// writeExpenseReportCSV writes the expenditures of a report in the format of the locale, without
+// currency symbols so spreadsheet programs read the amounts as numbers; the currency, when
+// configured, is named in the header instead
+func writeExpenseReportCSV(ctx context.Context, w http.ResponseWriter, detail *ExpenseReportDetail, locale i18n.Format, currency string) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = locale.CSVComma()
+
+	amountHeader := i18n.T(ctx, "Amount")
+	if currency != "" {
+		amountHeader += " (" + currency + ")"
+	}
+	cw.Write([]string{i18n.T(ctx, "Date"), i18n.T(ctx, "Description"), i18n.T(ctx, "Quantity"), i18n.T(ctx, "Unit"), amountHeader})
+
+	for _, expenditure := range detail.Expenditures {
+		quantity := ""
+		if expenditure.Unit != "" {
+			quantity = locale.Number(expenditure.Quantity, -1)
+		}
+		cw.Write([]string{
+			locale.Date(expenditure.Date),
+			expenditure.Description,
+			quantity,
+			expenditure.Unit,
+			locale.Amount(expenditure.Amount, ""),
+		})
+	}
+
+	cw.Write([]string{"", i18n.T(ctx, "Total"), "", "", locale.Amount(detail.Total, "")})
+	cw.Flush()
+	return cw.Error()
+}
+
+func expenseReportPDF(ctx context.Context, detail *ExpenseReportDetail, locale i18n.Format, currency string) *pdfDocument {
+	const (
+		dateX        = pdfMargin
+		descriptionX = pdfMargin + 80
+		amountX      = pdfPageWidth - pdfMargin
+	)
+
+	doc := newPDFDocument()
+	doc.row(18, true, pdfCell{x: pdfMargin, text: i18n.T(ctx, "Expense Report")})
+	doc.row(12, false, pdfCell{x: pdfMargin, text: detail.Title})
+	if detail.Claimant != "" {
+		doc.row(10, false, pdfCell{x: pdfMargin, text: i18n.T(ctx, "Claimant") + ": " + detail.Claimant})
+	}
+	doc.row(10, false, pdfCell{x: pdfMargin, text: i18n.T(ctx, "Status") + ": " + string(detail.Status)})
+	if detail.SubmittedAt != nil {
+		doc.row(10, false, pdfCell{x: pdfMargin, text: i18n.T(ctx, "Submitted") + ": " + locale.Date(*detail.SubmittedAt)})
+	}
+	doc.space(12)
+
+	doc.row(10, true,
+		pdfCell{x: dateX, text: i18n.T(ctx, "Date")},
+		pdfCell{x: descriptionX, text: i18n.T(ctx, "Description")},
+		pdfCell{x: amountX, text: i18n.T(ctx, "Amount"), alignRight: true})
+	doc.rule()
+
+	for _, expenditure := range detail.Expenditures {
+		description := expenditure.Description
+		if expenditure.Unit != "" {
+			description = fmt.Sprintf("%s (%s %s)", description, locale.Number(expenditure.Quantity, -1), expenditure.Unit)
+		}
+		if len(description) > 70 {
+			description = strings.ToValidUTF8(description[:67], "") + "..."
+		}
+
+		doc.row(10, false,
+			pdfCell{x: dateX, text: locale.Date(expenditure.Date)},
+			pdfCell{x: descriptionX, text: description},
+			pdfCell{x: amountX, text: locale.Amount(expenditure.Amount, currency), alignRight: true})
+	}
+
+	doc.rule()
+	doc.row(11, true,
+		pdfCell{x: descriptionX, text: i18n.T(ctx, "Total")},
+		pdfCell{x: amountX, text: locale.Amount(detail.Total, currency), alignRight: true})
+
+	return doc
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"go-expense-tracker/i18n"
+	"net/http"
+)
+
+// ExportFormatting configures how exports meant for people, such as expense report CSV and PDF
+// files, write numbers, amounts and dates
+type ExportFormatting struct {
+	Locale   string // Locale used instead of the one the client asks for, when set
+	Currency string // ISO 4217 code of the amounts, written without a currency when empty
+}
+
+// formatFor returns the format of an export: that of the locale query parameter, else of the
+// configured locale, else of the client's Accept-Language header
+func (f ExportFormatting) formatFor(r *http.Request) (i18n.Format, error) {
+	locale := r.URL.Query().Get("locale")
+	if locale == "" {
+		locale = f.Locale
+	}
+	if locale == "" {
+		return i18n.NegotiateFormat(r.Header.Get("Accept-Language")), nil
+	}
+
+	format, ok := i18n.FormatFor(locale)
+	if !ok {
+		return i18n.Format{}, i18n.ErrUnsupportedLocale
+	}
+	return format, nil
+}
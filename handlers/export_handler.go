@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"go-expense-tracker/jobs"
+	"go-expense-tracker/signedurl"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// exportJobKind tells the jobs started by POST /exports apart from other jobs, so
+// /exports/{id} only serves exports
+const exportJobKind = "expenditure-export"
+
+type ExportHandler struct {
+	service domain.ExpenditureRepository
+	jobs    *jobs.Runner
+	links   *signedurl.Signer
+	linkTTL time.Duration
+	logger  *slog.Logger
+}
+
+// NewExportHandler creates a new ExportHandler running exports on runner. Download links are
+// signed with links and valid for linkTTL
+func NewExportHandler(service domain.ExpenditureRepository, runner *jobs.Runner, links *signedurl.Signer, linkTTL time.Duration, logger *slog.Logger) *ExportHandler {
+	return &ExportHandler{
+		service: service,
+		jobs:    runner,
+		links:   links,
+		linkTTL: linkTTL,
+		logger:  logger,
+	}
+}
+
+func ExportRouter(handler *ExportHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/exports" {
+			Methods{http.MethodPost: handler.StartExport}.ServeHTTP(w, r)
+			return
+		}
+
+		_, sub, ok := splitPath(path, "/exports/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch sub {
+		case "":
+			Methods{http.MethodGet: handler.GetExport}.ServeHTTP(w, r)
+		case "download":
+			Methods{http.MethodGet: handler.DownloadExport}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// ExportResponse is an export job with a signed link to its file once it has completed. The
+// link works without any other credentials until it expires
+type ExportResponse struct {
+	*domain.Job
+	DownloadURL string     `json:"download_url,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+func (h *ExportHandler) newExportResponse(job *domain.Job) ExportResponse {
+	resp := ExportResponse{Job: job}
+	if job.Status == domain.JobCompleted {
+		expires := time.Now().Add(h.linkTTL).Truncate(time.Second)
+		resp.DownloadURL = h.links.Sign(exportDownloadPath(job.ID.String()), expires)
+		resp.ExpiresAt = &expires
+	}
+	return resp
+}
+
+func exportDownloadPath(id string) string {
+	return "/exports/" + id + "/download"
+}
+
+// getExportJob returns the export job, other jobs are not found
+func (h *ExportHandler) getExportJob(id string) (*domain.Job, error) {
+	job, err := h.jobs.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Kind != exportJobKind {
+		return nil, domain.ErrJobNotFound
+	}
+	return job, nil
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var errInvalidExportFormat = errors.New("invalid export format, use json, ndjson or csv")
+
+// ExportRequest describes an export of expenditures run in the background; every filter is
+// optional
+type ExportRequest struct {
+	Format     string    `json:"format"`      // json (default), ndjson or csv
+	From       string    `json:"from"`        // First day, YYYY-MM-DD
+	To         string    `json:"to"`          // Last day, YYYY-MM-DD
+	CategoryId uuid.UUID `json:"category_id"` // Only expenditures of this category
+	Tag        string    `json:"tag"`         // Only expenditures with this tag
+	Anonymize  bool      `json:"anonymize"`   // Replace descriptions, merchants, tags and places by pseudonyms
+}
+
+// validate checks the format and returns the date range of the export as [from, to)
+func (req *ExportRequest) validate() (time.Time, time.Time, error) {
+	switch req.Format {
+	case "":
+		req.Format = "json"
+	case "json", "ndjson", "csv":
+	default:
+		return time.Time{}, time.Time{}, errInvalidExportFormat
+	}
+
+	var from, to time.Time
+	var err error
+	if req.From != "" {
+		if from, err = time.Parse("2006-01-02", req.From); err != nil {
+			return time.Time{}, time.Time{}, errInvalidDateRange
+		}
+	}
+	if req.To != "" {
+		if to, err = time.Parse("2006-01-02", req.To); err != nil {
+			return time.Time{}, time.Time{}, errInvalidDateRange
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	if !from.IsZero() && !to.IsZero() && !to.After(from) {
+		return time.Time{}, time.Time{}, errInvalidDateRange
+	}
+	return from, to, nil
+}
+
+// matches reports whether an expenditure dated within [from, to) passes the filters
+func (req *ExportRequest) matches(expenditure *domain.Expenditure, from, to time.Time) bool {
+	if (!from.IsZero() && expenditure.Date.Before(from)) || (!to.IsZero() && !expenditure.Date.Before(to)) {
+		return false
+	}
+	if req.CategoryId != uuid.Nil && expenditure.CategoryId != req.CategoryId {
+		return false
+	}
+	return req.Tag == "" || slices.Contains(expenditure.Tags, req.Tag)
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ExportUserData handles GET /users/me/data-export, which downloads everything kept about the
+// member making the request as one JSON document
+func (h *UserHandler) ExportUserData(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling export user data request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	member := Member(r.Context())
+	if member == nil {
+		h.logger.Warn("Data export requested without a member")
+		http.Error(w, errNoMember.Error(), http.StatusBadRequest)
+		return
+	}
+
+	export, err := h.privacy.Export(member.ID)
+	if err != nil {
+		h.logger.Error("Failed to export user data", "error", err, "member_id", member.ID)
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully exported user data", "member_id", member.ID, "expenditures", len(export.Expenditures))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"member-%s.json\"", member.ID))
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(export)
+}
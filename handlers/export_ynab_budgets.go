@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"fmt"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/interchange"
+	"go-expense-tracker/reports"
+	"net/http"
+	"time"
+)
+
+// ExportYNABBudgets handles GET /interchange/ynab/budgets, downloading the monthly budgets on
+// categories as a YNAB plan export of a month, the current one unless `?month=YYYY-MM` names
+// another
+func (h *InterchangeHandler) ExportYNABBudgets(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling export YNAB budgets request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.budgets == nil || h.categories == nil {
+		h.logger.Warn("Budgets or categories are not supported by the storage")
+		http.Error(w, "Budgets are not supported by the storage", http.StatusNotFound)
+		return
+	}
+
+	at := time.Now().UTC()
+	if month := r.URL.Query().Get("month"); month != "" {
+		parsed, err := time.Parse("2006-01", month)
+		if err != nil {
+			h.logger.Warn("Invalid budget export month", "month", month)
+			http.Error(w, "Invalid month, use YYYY-MM", http.StatusBadRequest)
+			return
+		}
+		at = parsed
+	}
+
+	all, err := h.budgets.GetAllBudgets()
+	if err != nil {
+		h.logger.Error("Failed to get all budgets", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// YNAB plans every category per month
+	var budgets []*domain.Budget
+	for _, budget := range all {
+		if budget.Period == domain.BudgetMonthly && budget.Covers(at) {
+			budgets = append(budgets, budget)
+		}
+	}
+
+	categories, err := h.categories.GetAllCategories()
+	if err != nil {
+		h.logger.Error("Failed to get categories for budget export", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	statuses, err := reports.BudgetStatuses(budgets, h.summaries, h.expenditures, at, h.calendar)
+	if err != nil {
+		h.logger.Error("Failed to get spending for budget export", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", interchange.FormatYNAB.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="budgets-%s.csv"`, h.calendar.Label(at)))
+	if err := interchange.WriteYNABPlan(w, statuses, budgets, categories); err != nil {
+		h.logger.Error("Failed to export budgets", "error", err)
+		return
+	}
+	h.logger.Info("Successfully exported YNAB budgets", "count", len(budgets))
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"go-expense-tracker/features"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type FlagHandler struct {
+	flags  *features.Set
+	token  string
+	logger *slog.Logger
+}
+
+// NewFlagHandler creates a new FlagHandler; every request must carry token as bearer token
+func NewFlagHandler(flags *features.Set, token string, logger *slog.Logger) *FlagHandler {
+	return &FlagHandler{
+		flags:  flags,
+		token:  token,
+		logger: logger,
+	}
+}
+
+func FlagRouter(handler *FlagHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !handler.authorized(r) {
+			handler.logger.Warn("Feature flag request without a valid token", "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+			http.Error(w, "Admin token required", http.StatusUnauthorized)
+			return
+		}
+
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/admin/flags" {
+			Methods{http.MethodGet: handler.GetAllFlags}.ServeHTTP(w, r)
+			return
+		}
+
+		if _, sub, ok := splitPath(path, "/admin/flags/"); ok && sub == "" {
+			Methods{http.MethodPut: handler.ToggleFlag}.ServeHTTP(w, r)
+			return
+		}
+
+		http.NotFound(w, r)
+	})
+}
+
+func (h *FlagHandler) authorized(r *http.Request) bool {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return found && subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) == 1
+}
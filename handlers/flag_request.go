@@ -0,0 +1,5 @@
+package handlers
+
+type FlagRequest struct {
+	Enabled *bool `json:"enabled"`
+}
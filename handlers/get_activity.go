@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// GetActivity lists the recent activities, newest first. `since` limits them to those after a
+// point in time, e.g. the last visit, and `limit` and `cursor` page through them
+func (h *ActivityHandler) GetActivity(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get activity request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+
+	var since time.Time
+	if value := query.Get("since"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			h.logger.Warn("Invalid since parameter", "since", value)
+			http.Error(w, "since must be an RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		since = parsed
+	}
+
+	limit := defaultPageSize
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxPageSize {
+			h.logger.Warn("Invalid page size", "limit", value)
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", maxPageSize), http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	var before int64
+	if value := query.Get("cursor"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 1 {
+			h.logger.Warn("Invalid cursor", "cursor", value)
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+		before = parsed
+	}
+
+	// One extra activity tells whether there is a next page
+	activities := h.feed.Recent(since, before, limit+1)
+	if len(activities) > limit {
+		activities = activities[:limit]
+		next := strconv.FormatInt(activities[limit-1].Seq, 10)
+
+		nextQuery := r.URL.Query()
+		nextQuery.Set("cursor", next)
+		nextQuery.Set("limit", strconv.Itoa(limit))
+		w.Header().Set(nextCursorHeader, next)
+		w.Header().Set("Link", fmt.Sprintf("<%s?%s>; rel=\"next\"", r.URL.Path, nextQuery.Encode()))
+	}
+
+	h.logger.Info("Successfully retrieved activity", "count", len(activities), "has_next", w.Header().Get(nextCursorHeader) != "")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(activities)
+}
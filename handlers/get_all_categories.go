@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *CategoryHandler) GetAllCategories(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get all categories request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	categories, err := h.categories.GetAllCategories()
+	if err != nil {
+		h.logger.Error("Failed to get all categories", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	view := r.URL.Query().Get("view")
+	if view != "" && view != "flat" && view != "tree" {
+		h.logger.Warn("Unsupported category view", "view", view)
+		http.Error(w, "Unsupported view, use flat or tree", http.StatusBadRequest)
+		return
+	}
+
+	// Archived categories are only listed on request, as they cannot be used anymore
+	if r.URL.Query().Get("include_archived") != "true" {
+		categories = domain.ActiveCategories(categories)
+	}
+	domain.SortCategories(categories)
+
+	var body interface{} = categories
+	if view == "tree" {
+		body = domain.CategoryTree(categories)
+	}
+
+	h.logger.Info("Successfully retrieved all categories", "count", len(categories), "view", view)
+	if err := writeCacheableJSON(w, r, body, categoryCacheControl); err != nil {
+		h.logger.Error("Failed to write categories", "error", err)
+	}
+}
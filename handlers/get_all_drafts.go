@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func (h *ExpenditureHandler) GetAllDrafts(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get all drafts request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.drafts == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	drafts, err := h.drafts.GetAllDrafts()
+	if err != nil {
+		h.logger.Error("Failed to get all drafts", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully retrieved all drafts", "count", len(drafts))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(drafts)
+}
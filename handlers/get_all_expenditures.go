@@ -1,8 +1,21 @@
 package handlers
 
 import (
-	"encoding/json"
+	"fmt"
+	"go-expense-tracker/domain"
 	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// nextCursorHeader carries the cursor of the next page of a paginated listing
+const nextCursorHeader = "X-Next-Cursor"
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
 )
 
 func (h *ExpenditureHandler) GetAllExpenditures(w http.ResponseWriter, r *http.Request) {
@@ -14,14 +27,137 @@ func (h *ExpenditureHandler) GetAllExpenditures(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	expenditures, err := h.service.GetAllExpenditures()
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		h.logger.Warn("Invalid date range", "error", err, "query", r.URL.RawQuery)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// ?uncategorized=true lists what still needs sorting out
+	var categoryID uuid.UUID
+	query := r.URL.Query()
+	if query.Get("uncategorized") == "true" {
+		if h.uncategorized == uuid.Nil {
+			h.logger.Warn("Uncategorized listing without an uncategorized category")
+			http.Error(w, "no uncategorized category configured", http.StatusBadRequest)
+			return
+		}
+		categoryID = h.uncategorized
+	}
+
+	if query.Get("pinned") == "true" {
+		h.getPinnedExpenditures(w, r, from, to)
+		return
+	}
+
+	if query.Has("limit") || query.Has("cursor") {
+		h.getExpenditurePage(w, r, from, to, categoryID)
+		return
+	}
+
+	if err := h.guard.checkSpan(from, to); err != nil {
+		h.logger.Warn("Rejected expenditure listing over a long date range", "error", err, "query", r.URL.RawQuery)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	render := func(w http.ResponseWriter) error {
+		stream := newJSONStream(w, r)
+		err := domain.EachExpenditure(h.service, func(expenditure *domain.Expenditure) error {
+			if (!from.IsZero() && expenditure.Date.Before(from)) || (!to.IsZero() && !expenditure.Date.Before(to)) {
+				return nil
+			}
+			if categoryID != uuid.Nil && expenditure.CategoryId != categoryID {
+				return nil
+			}
+			return stream.Write(expenditure)
+		})
+		if err == nil {
+			err = stream.Close()
+		}
+		if err != nil {
+			h.logger.Error("Failed to get all expenditures", "error", err)
+			if !stream.Started() {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return err
+		}
+
+		h.logger.Info("Successfully retrieved all expenditures", "count", stream.count)
+		return nil
+	}
+
+	deferred, err := h.guard.deferLarge(w, r, h.service, "expenditures", from, to, render)
 	if err != nil {
-		h.logger.Error("Failed to get all expenditures", "error", err)
+		h.logger.Error("Failed to count expenditures", "error", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if !deferred {
+		render(w)
+	}
+}
+
+// getExpenditurePage lists one page of expenditures after the ?cursor= of the previous page.
+// Pages stay consistent when expenditures are added meanwhile, unlike offsets
+func (h *ExpenditureHandler) getExpenditurePage(w http.ResponseWriter, r *http.Request, from, to time.Time, categoryID uuid.UUID) {
+	query := r.URL.Query()
+
+	limit := defaultPageSize
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxPageSize {
+			h.logger.Warn("Invalid page size", "limit", value)
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", maxPageSize), http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	pageQuery := domain.ExpenditurePageQuery{From: from, To: to, CategoryId: categoryID, Limit: limit + 1}
+	if token := query.Get("cursor"); token != "" {
+		cursor, err := domain.ParseExpenditureCursor(token)
+		if err != nil {
+			h.logger.Warn("Invalid cursor", "cursor", token)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		pageQuery.After = &cursor
+	}
+
+	// One extra expenditure tells whether there is a next page
+	expenditures, err := domain.GetExpenditurePage(h.service, pageQuery)
+	if err != nil {
+		h.logger.Error("Failed to get expenditure page", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if len(expenditures) > limit {
+		expenditures = expenditures[:limit]
+		next := domain.CursorAfter(expenditures[limit-1]).Encode()
+
+		nextQuery := r.URL.Query()
+		nextQuery.Set("cursor", next)
+		nextQuery.Set("limit", strconv.Itoa(limit))
+		w.Header().Set(nextCursorHeader, next)
+		w.Header().Set("Link", fmt.Sprintf("<%s?%s>; rel=\"next\"", r.URL.Path, nextQuery.Encode()))
+	}
+
+	stream := newJSONStream(w, r)
+	for _, expenditure := range expenditures {
+		if err = stream.Write(expenditure); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = stream.Close()
+	}
+	if err != nil {
+		h.logger.Error("Failed to write expenditure page", "error", err)
+		return
+	}
 
-	h.logger.Info("Successfully retrieved all expenditures", "count", len(expenditures))
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(expenditures)
+	h.logger.Info("Successfully retrieved expenditure page", "count", len(expenditures), "has_next", w.Header().Get(nextCursorHeader) != "")
 }
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *ExpenseReportHandler) GetAllExpenseReports(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get all expense reports request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	reports, err := h.reports.GetAllExpenseReports()
+	if err != nil {
+		h.logger.Error("Failed to get all expense reports", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Optional filter, e.g. ?status=submitted
+	if status := r.URL.Query().Get("status"); status != "" {
+		filtered := make([]*domain.ExpenseReport, 0, len(reports))
+		for _, report := range reports {
+			if string(report.Status) == status {
+				filtered = append(filtered, report)
+			}
+		}
+		reports = filtered
+	}
+
+	h.logger.Info("Successfully retrieved all expense reports", "count", len(reports))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
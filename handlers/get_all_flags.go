@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// GetAllFlags lists the feature flags with whether they are enabled
+func (h *FlagHandler) GetAllFlags(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get all feature flags request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	states := h.flags.States()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(states)
+	h.logger.Info("Successfully retrieved feature flags", "count", len(states))
+}
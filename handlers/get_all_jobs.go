@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func (h *JobHandler) GetAllJobs(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get all jobs request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	jobs := h.jobs.Recent()
+	resp := make([]JobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		resp = append(resp, newJobResponse(job))
+	}
+
+	h.logger.Info("Successfully retrieved all jobs", "count", len(resp))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func (h *MerchantHandler) GetAllMerchants(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get all merchants request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	merchants, err := h.merchants.GetAllMerchants()
+	if err != nil {
+		h.logger.Error("Failed to get all merchants", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully retrieved all merchants", "count", len(merchants))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(merchants)
+}
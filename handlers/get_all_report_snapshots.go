@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *ReportHandler) GetAllReportSnapshots(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get all report snapshots request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	snapshots, err := h.snapshots.GetAllReportSnapshots()
+	if err != nil {
+		h.logger.Error("Failed to get report snapshots", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if month := r.URL.Query().Get("month"); month != "" {
+		matching := make([]*domain.ReportSnapshot, 0, len(snapshots))
+		for _, snapshot := range snapshots {
+			if snapshot.Month == month {
+				matching = append(matching, snapshot)
+			}
+		}
+		snapshots = matching
+	}
+
+	if snapshots == nil {
+		snapshots = []*domain.ReportSnapshot{}
+	}
+
+	h.logger.Info("Successfully retrieved report snapshots", "count", len(snapshots))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(snapshots)
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// GetAllRetentionPurges handles GET /admin/retention/purges, the audit log of the records removed
+// by retention rules, latest first
+func (h *RetentionHandler) GetAllRetentionPurges(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get all retention purges request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	purges, err := h.purges.GetAllRetentionPurges()
+	if err != nil {
+		h.logger.Error("Failed to get all retention purges", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully retrieved all retention purges", "count", len(purges))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(purges)
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *ImportHandler) GetAllStagedExpenditures(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get all staged expenditures request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	staged, err := h.imports.GetAllStagedExpenditures()
+	if err != nil {
+		h.logger.Error("Failed to get all staged expenditures", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Filter by review status, e.g. ?status=pending
+	if status := r.URL.Query().Get("status"); status != "" {
+		filtered := make([]*domain.StagedExpenditure, 0, len(staged))
+		for _, s := range staged {
+			if string(s.Status) == status {
+				filtered = append(filtered, s)
+			}
+		}
+		staged = filtered
+	}
+
+	h.logger.Info("Successfully retrieved all staged expenditures", "count", len(staged))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(staged)
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// GetAllowance handles GET /households/{id}/members/{memberId}/allowance, which parents review
+// and children may call for their own allowance
+func (h *HouseholdHandler) GetAllowance(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get allowance request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	householdID, memberID, ok := nestedPathIDs(w, r, "/households/", "members")
+	if !ok {
+		return
+	}
+
+	allowance, err := h.allowances.Get(householdID, memberID)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully retrieved allowance", "household_id", householdID, "member_id", memberID, "balance", allowance.Balance.Balance)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newAllowanceResponse(allowance))
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+	"time"
+)
+
+// GetAnomalies lists the unusual spending of a period, latest first: ?from= and ?to= default to
+// the last 30 days and ?kind= keeps one kind of anomaly
+func (h *AnomalyHandler) GetAnomalies(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get anomalies request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		h.logger.Warn("Invalid anomaly period", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if from.IsZero() && !r.URL.Query().Has("from") {
+		from = time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -30)
+	}
+
+	kind, err := domain.ParseAnomalyKind(r.URL.Query().Get("kind"))
+	if err != nil {
+		h.logger.Warn("Invalid anomaly kind", "kind", r.URL.Query().Get("kind"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Every expenditure is judged against the history before it, so the whole history is read
+	var history []*domain.Expenditure
+	err = domain.EachExpenditure(h.service, func(expenditure *domain.Expenditure) error {
+		history = append(history, expenditure)
+		return nil
+	})
+	if err != nil {
+		h.logger.Error("Failed to get expenditures for anomalies", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	found := []*domain.Anomaly{}
+	for _, anomaly := range h.detector.Detect(history, from, to) {
+		if kind == "" || anomaly.Kind == kind {
+			found = append(found, anomaly)
+		}
+	}
+
+	h.logger.Info("Successfully detected anomalies", "count", len(found), "from", from, "to", to, "kind", kind)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(found)
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *ArchiveHandler) GetArchiveByID(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get archive by ID request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/admin/archive/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Getting archive by ID", "id", id)
+
+	archive, err := h.archives.GetArchiveByID(id)
+	if err != nil {
+		if err == domain.ErrArchiveNotFound {
+			h.logger.Warn("Archive not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get archive by ID", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully retrieved archive", "id", id, "count", archive.Count)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(archive)
+}
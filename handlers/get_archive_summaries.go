@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+func (h *ArchiveHandler) GetArchiveSummaries(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get archive summaries request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	year := r.URL.Query().Get("year")
+	if year != "" {
+		if parsed, err := strconv.Atoi(year); err != nil || parsed < 1900 || parsed > 9999 {
+			h.logger.Warn("Invalid archive summaries year", "year", year)
+			http.Error(w, "Invalid year", http.StatusBadRequest)
+			return
+		}
+	}
+
+	archives, err := h.archives.GetAllArchives()
+	if err != nil {
+		h.logger.Error("Failed to get archives for summaries", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Archives never overlap, but merge anyway in case a month was archived in two runs
+	type key struct {
+		month      string
+		categoryId uuid.UUID
+	}
+	merged := make(map[key]*domain.ArchiveSummary)
+	for _, archive := range archives {
+		for _, summary := range archive.Summaries {
+			if year != "" && !strings.HasPrefix(summary.Month, year+"-") {
+				continue
+			}
+			k := key{summary.Month, summary.CategoryId}
+			if existing, ok := merged[k]; ok {
+				existing.Count += summary.Count
+				existing.Total = math.Round((existing.Total+summary.Total)*100) / 100
+				continue
+			}
+			copied := summary
+			merged[k] = &copied
+		}
+	}
+
+	summaries := make([]domain.ArchiveSummary, 0, len(merged))
+	for _, summary := range merged {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Month != summaries[j].Month {
+			return summaries[i].Month < summaries[j].Month
+		}
+		return summaries[i].CategoryId.String() < summaries[j].CategoryId.String()
+	})
+
+	h.logger.Info("Successfully computed archive summaries", "count", len(summaries), "year", year)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summaries)
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *BudgetHandler) GetBudgetByID(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get budget by ID request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/budgets/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Getting budget by ID", "id", id)
+
+	budget, err := h.budgets.GetBudgetByID(id)
+	if err != nil {
+		if err == domain.ErrBudgetNotFound {
+			h.logger.Warn("Budget not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get budget by ID", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully retrieved budget", "id", id, "name", budget.Name)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(budget)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/reports"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetBudgetStatus handles GET /budgets/status and GET /budgets/{id}/status, the spending against
+// every budget or one of them in its current period. `?date=YYYY-MM-DD` picks the period
+// containing another day
+func (h *BudgetHandler) GetBudgetStatus(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get budget status request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	at := time.Now().UTC()
+	if date := r.URL.Query().Get("date"); date != "" {
+		parsed, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			h.logger.Warn("Invalid budget status date", "date", date)
+			http.Error(w, "Invalid date, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		at = parsed
+	}
+
+	// GET /budgets/{id}/status is about one budget, GET /budgets/status about all of them
+	var budgets []*domain.Budget
+	_, sub, _ := splitPath(r.URL.Path, "/budgets/")
+	single := sub == "status"
+	id := ""
+	if single {
+		var ok bool
+		id, ok = pathID(w, r, "/budgets/")
+		if !ok {
+			return
+		}
+		budget, err := h.budgets.GetBudgetByID(id)
+		if err != nil {
+			if err == domain.ErrBudgetNotFound {
+				h.logger.Warn("Budget not found", "id", id)
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			h.logger.Error("Failed to get budget", "id", id, "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		budgets = append(budgets, budget)
+	} else {
+		var err error
+		budgets, err = h.budgets.GetAllBudgets()
+		if err != nil {
+			h.logger.Error("Failed to get all budgets", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	statuses, err := reports.BudgetStatuses(budgets, h.summaries, h.expenditures, at, h.calendar)
+	if err != nil {
+		h.logger.Error("Failed to get spending for budget status", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Envelope balances are reported alongside the budgets of their categories
+	if h.envelopes != nil && len(budgets) > 0 {
+		balances, err := envelopeBalances(h.envelopes, h.summaries, h.expenditures)
+		if err != nil {
+			h.logger.Error("Failed to compute envelope balances for budget status", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for i := range statuses {
+			for _, balance := range balances {
+				if budgets[i].CategoryId != uuid.Nil && balance.CategoryId == budgets[i].CategoryId {
+					statuses[i].Envelope = balance
+				}
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if single {
+		h.logger.Info("Successfully computed budget status", "id", id, "spent", statuses[0].Spent, "overspent", statuses[0].Overspent)
+		json.NewEncoder(w).Encode(statuses[0])
+		return
+	}
+
+	h.logger.Info("Successfully computed budget statuses", "count", len(statuses))
+	json.NewEncoder(w).Encode(statuses)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"go-expense-tracker/domain"
+	"net/http"
+	"time"
+)
+
+// GetCalendarFeed handles GET /calendar.ics?token=..., the expenditures on the days they occurred
+// and, for the next calendarFeedDays, the charges of recurring expenditures and the days budget
+// periods begin
+func (h *CalendarHandler) GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get calendar feed request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.URL.Query().Get("token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		h.logger.Warn("Invalid calendar feed token", "remote_addr", r.RemoteAddr)
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	expenditures, err := h.service.GetAllExpenditures()
+	if err != nil {
+		h.logger.Error("Failed to get expenditures for calendar feed", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var budgets []*domain.Budget
+	if h.budgets != nil {
+		if budgets, err = h.budgets.GetAllBudgets(); err != nil {
+			h.logger.Error("Failed to get budgets for calendar feed", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	var recurring []*domain.RecurringExpenditure
+	if h.recurring != nil {
+		if recurring, err = h.recurring.GetAllRecurring(); err != nil {
+			h.logger.Error("Failed to get recurring expenditures for calendar feed", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="expenditures.ics"`)
+
+	now := time.Now()
+	ical := &icalWriter{w: w}
+	ical.line("BEGIN", "VCALENDAR")
+	ical.line("VERSION", "2.0")
+	ical.line("PRODID", "-//go-expense-tracker//Expenditures//EN")
+	ical.line("CALSCALE", "GREGORIAN")
+	ical.text("X-WR-CALNAME", "Expenditures")
+
+	for _, expenditure := range expenditures {
+		ical.event(icalUID("expenditure", expenditure.ID.String()), now, expenditure.Date, fmt.Sprintf("%s (%.2f)", expenditure.Description, expenditure.Amount))
+	}
+
+	today := domain.SpendingDay(now)
+	until := today.AddDate(0, 0, calendarFeedDays)
+	for _, charge := range recurring {
+		for _, date := range charge.Between(today, until) {
+			ical.event(icalUID("recurring", charge.ID.String()+"-"+icalDate(date)), now, date, fmt.Sprintf("%s (%.2f, recurring)", charge.Description, charge.Amount))
+		}
+	}
+	for _, budget := range budgets {
+		for _, start := range budget.PeriodStarts(today, until, h.calendar) {
+			ical.event(icalUID("budget", budget.ID.String()+"-"+icalDate(start)), now, start, fmt.Sprintf("%s: %.2f available", budget.Name, budget.Amount))
+		}
+	}
+
+	ical.line("END", "VCALENDAR")
+
+	if ical.err != nil {
+		h.logger.Error("Failed to write calendar feed", "error", ical.err)
+		return
+	}
+
+	h.logger.Info("Successfully served calendar feed", "count", len(expenditures), "budgets", len(budgets), "recurring", len(recurring))
+}
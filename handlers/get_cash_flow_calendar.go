@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/reports"
+	"net/http"
+	"time"
+)
+
+// GetCashFlowCalendar handles GET /calendar?month=2024-06, the fiscal month day by day with the
+// spending, the recurring charges still to come and the budget paces; the current month by default
+func (h *CashFlowHandler) GetCashFlowCalendar(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get cash flow calendar request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	now := time.Now().UTC()
+	month := r.URL.Query().Get("month")
+	if month == "" {
+		month = h.calendar.Label(now)
+	}
+	from, to, err := h.calendar.PeriodOf(month, time.UTC)
+	if err != nil {
+		h.logger.Warn("Invalid month", "error", err, "month", month)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	chart, err := parseChart(r)
+	if err != nil {
+		h.logger.Warn("Invalid format option", "format", r.URL.Query().Get("format"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var budgets []*domain.Budget
+	if h.budgets != nil {
+		if budgets, err = h.budgets.GetAllBudgets(); err != nil {
+			h.logger.Error("Failed to get budgets for cash flow calendar", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	var recurring []*domain.RecurringExpenditure
+	if h.recurring != nil {
+		if recurring, err = h.recurring.GetAllRecurring(); err != nil {
+			h.logger.Error("Failed to get recurring expenditures for cash flow calendar", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	cashFlow, err := reports.CashFlow(h.summaries, h.service, budgets, recurring, from, to, now, h.calendar)
+	if err != nil {
+		h.logger.Error("Failed to compute cash flow calendar", "error", err, "month", month)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully computed cash flow calendar", "month", cashFlow.Month, "spent", cashFlow.Spent, "scheduled", cashFlow.Scheduled)
+	w.Header().Set("Content-Type", "application/json")
+	if chart {
+		json.NewEncoder(w).Encode(reports.CashFlowChart(cashFlow))
+		return
+	}
+	json.NewEncoder(w).Encode(cashFlow)
+}
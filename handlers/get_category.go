@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *CategoryHandler) GetCategoryByID(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get category by ID request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/categories/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Getting category by ID", "id", id)
+
+	category, err := h.categories.GetCategoryByID(id)
+	if err != nil {
+		if err == domain.ErrCategoryNotFound {
+			h.logger.Warn("Category not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get category by ID", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully retrieved category", "id", id, "name", category.Name)
+	if err := writeCacheableJSON(w, r, category, categoryCacheControl); err != nil {
+		h.logger.Error("Failed to write category", "id", id, "error", err)
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/reports"
+	"net/http"
+)
+
+func (h *ReportHandler) GetCategoryReport(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get category report request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.categories == nil {
+		h.logger.Warn("Category report requested without category support")
+		http.Error(w, "Categories are not supported by the storage", http.StatusNotFound)
+		return
+	}
+
+	from, to, err := h.parsePeriod(r)
+	if err != nil {
+		h.logger.Warn("Invalid date range", "error", err, "query", r.URL.RawQuery)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	includePending, err := parsePending(r)
+	if err != nil {
+		h.logger.Warn("Invalid pending option", "pending", r.URL.Query().Get("pending"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	chart, err := parseChart(r)
+	if err != nil {
+		h.logger.Warn("Invalid format option", "format", r.URL.Query().Get("format"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	categories, err := h.categories.GetAllCategories()
+	if err != nil {
+		h.logger.Error("Failed to get categories for category report", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	summaries, err := reports.DailySpending(h.summaries, h.service, from, to)
+	if err != nil {
+		h.logger.Error("Failed to get spending for category report", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !includePending {
+		summaries = reports.ExcludePending(summaries)
+	}
+
+	spending := reports.CategorySpendingFromSummaries(summaries, categories)
+
+	h.logger.Info("Successfully computed category report", "categories", len(spending))
+	w.Header().Set("Content-Type", "application/json")
+	if chart {
+		json.NewEncoder(w).Encode(reports.CategoryChart(spending))
+		return
+	}
+	json.NewEncoder(w).Encode(spending)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/reports"
+	"net/http"
+	"time"
+)
+
+// GetCategorySpending returns every category with its spending in one fiscal month, the current one
+// by default
+func (h *CategoryHandler) GetCategorySpending(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get category spending request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, to := h.calendar.Period(time.Now())
+	if month := r.URL.Query().Get("month"); month != "" {
+		var err error
+		from, to, err = h.calendar.PeriodOf(month, time.Local)
+		if err != nil {
+			h.logger.Warn("Invalid category spending month", "month", month)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	includePending, err := parsePending(r)
+	if err != nil {
+		h.logger.Warn("Invalid pending option", "pending", r.URL.Query().Get("pending"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	categories, err := h.categories.GetAllCategories()
+	if err != nil {
+		h.logger.Error("Failed to get categories for spending", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	summaries, err := reports.DailySpending(h.summaries, h.expenditures, from, to)
+	if err != nil {
+		h.logger.Error("Failed to get spending for category spending", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !includePending {
+		summaries = reports.ExcludePending(summaries)
+	}
+
+	spending := reports.CategorySpendingFromSummaries(summaries, categories)
+
+	h.logger.Info("Successfully computed category spending", "count", len(spending), "month", h.calendar.Label(from), "from", from, "to", to)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(spending)
+}
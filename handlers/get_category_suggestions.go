@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/classifier"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxSuggestions bounds the categories suggested at once
+const maxSuggestions = 10
+
+// CategorySuggestionsResponse lists the categories the classifier suggests for an entry
+type CategorySuggestionsResponse struct {
+	CategoryId  uuid.UUID               `json:"category_id,omitzero"` // Current category, empty when none is set
+	Suggestions []classifier.Suggestion `json:"suggestions"`          // Most likely first
+	TrainedAt   time.Time               `json:"trained_at,omitzero"`  // Last training of the classifier
+}
+
+// GetCategorySuggestions suggests categories for an expenditure from its description, e.g. to
+// sort out the uncategorized ones
+func (h *ExpenditureHandler) GetCategorySuggestions(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get category suggestions request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.classifier == nil {
+		h.logger.Warn("Category suggestions requested without a classifier")
+		http.Error(w, "category suggestions are not enabled", http.StatusNotFound)
+		return
+	}
+
+	id, ok := pathID(w, r, "/expenditures/")
+	if !ok {
+		return
+	}
+
+	expenditure, err := h.service.GetExpenditureByID(id)
+	if err != nil {
+		if err == domain.ErrExpenditureNotFound {
+			h.logger.Warn("Expenditure not found for category suggestions", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get expenditure for category suggestions", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeCategorySuggestions(w, r, h.classifier, expenditure.Description, expenditure.CategoryId, h.logger)
+}
+
+// writeCategorySuggestions answers with up to ?limit= (default 3) categories for the description
+func writeCategorySuggestions(w http.ResponseWriter, r *http.Request, classifier *classifier.Classifier, description string, categoryID uuid.UUID, logger *slog.Logger) {
+	limit := 3
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxSuggestions {
+			logger.Warn("Invalid suggestion limit", "limit", value)
+			http.Error(w, "limit must be between 1 and "+strconv.Itoa(maxSuggestions), http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	response := CategorySuggestionsResponse{
+		CategoryId:  categoryID,
+		Suggestions: classifier.Suggest(description, limit),
+		TrainedAt:   classifier.TrainedAt(),
+	}
+
+	logger.Info("Successfully suggested categories", "path", r.URL.Path, "count", len(response.Suggestions))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/chaos"
+	"net/http"
+)
+
+// GetChaos lists the rules of the faults injected into requests
+func (h *ChaosHandler) GetChaos(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get chaos request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(chaosResponse(h.injector.Rules()))
+}
+
+func chaosResponse(rules []chaos.Rule) ChaosResponse {
+	response := ChaosResponse{Rules: []string{}}
+	for _, rule := range rules {
+		response.Rules = append(response.Rules, rule.String())
+	}
+	return response
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *ConnectionHandler) GetConnectionByID(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get connection by ID request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/connections/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Getting connection by ID", "id", id)
+
+	connection, err := h.connections.GetBankConnectionByID(id)
+	if err != nil {
+		if err == domain.ErrBankConnectionNotFound {
+			h.logger.Warn("Connection not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get connection by ID", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully retrieved connection", "id", id)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(connection)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/reports"
+	"net/http"
+	"time"
+)
+
+// GetCurrencyReport returns the spending paid in each foreign currency, in that currency and as
+// settled in the home currency, with the average rate paid
+func (h *ReportHandler) GetCurrencyReport(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get currency report request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, to, err := h.parsePeriod(r)
+	if err != nil {
+		h.logger.Warn("Invalid date range", "error", err, "query", r.URL.RawQuery)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	includePending, err := parsePending(r)
+	if err != nil {
+		h.logger.Warn("Invalid pending option", "pending", r.URL.Query().Get("pending"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	chart, err := parseChart(r)
+	if err != nil {
+		h.logger.Warn("Invalid format option", "format", r.URL.Query().Get("format"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	render := func(w http.ResponseWriter) error {
+		expenditures, err := h.service.GetAllExpenditures()
+		if err != nil {
+			h.logger.Error("Failed to get expenditures for currency report", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return err
+		}
+
+		expenditures = reports.Actual(expenditures, time.Now())
+		if !includePending {
+			expenditures = reports.Cleared(expenditures)
+		}
+
+		totals := reports.CurrencyTotals(reports.FilterByDate(expenditures, from, to))
+
+		h.logger.Info("Successfully computed currency report", "currencies", len(totals))
+		w.Header().Set("Content-Type", "application/json")
+		if chart {
+			return json.NewEncoder(w).Encode(reports.CurrencyChart(totals))
+		}
+		return json.NewEncoder(w).Encode(totals)
+	}
+
+	deferred, err := h.guard.deferLarge(w, r, h.service, "currency-report", from, to, render)
+	if err != nil {
+		h.logger.Error("Failed to count expenditures for currency report", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !deferred {
+		render(w)
+	}
+}
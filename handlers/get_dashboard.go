@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+)
+
+// GetDashboard handles GET /dashboard, everything the home screen shows in one response: this
+// month's total, the budget statuses, the last expenditures, the top categories and the trend
+func (h *DashboardHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get dashboard request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	dashboard, err := h.dashboard.Get(time.Now())
+	if err != nil {
+		h.logger.Error("Failed to compute dashboard", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeCacheableJSON(w, r, dashboard, dashboardCacheControl); err != nil {
+		h.logger.Error("Failed to write dashboard", "error", err)
+		return
+	}
+	h.logger.Info("Successfully retrieved dashboard", "month", dashboard.Month, "generated_at", dashboard.GeneratedAt)
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"time"
+)
+
+// GetDetectedSubscriptions lists the charges repeating on a cadence that are not managed as
+// recurring expenditures yet, most expensive per month first
+func (h *SubscriptionHandler) GetDetectedSubscriptions(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get detected subscriptions request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	subscriptions, err := h.detect(time.Now())
+	if err != nil {
+		h.logger.Error("Failed to detect subscriptions", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := DetectedSubscriptionsResponse{Subscriptions: subscriptions}
+	for _, subscription := range subscriptions {
+		response.MonthlyCost += subscription.MonthlyCost
+	}
+	response.MonthlyCost = math.Round(response.MonthlyCost*100) / 100
+
+	h.logger.Info("Successfully detected subscriptions", "count", len(subscriptions), "monthly_cost", response.MonthlyCost)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+// GetEnvelopeAllocations lists every allocation and move, oldest first
+func (h *EnvelopeHandler) GetEnvelopeAllocations(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get envelope allocations request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	allocations, err := h.envelopes.GetAllEnvelopeAllocations()
+	if err != nil {
+		h.logger.Error("Failed to get envelope allocations", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if allocations == nil {
+		allocations = []*domain.EnvelopeAllocation{}
+	}
+
+	h.logger.Info("Successfully retrieved envelope allocations", "count", len(allocations))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(allocations)
+}
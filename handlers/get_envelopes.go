@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// GetEnvelopes returns the balance of every envelope
+func (h *EnvelopeHandler) GetEnvelopes(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get envelopes request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	balances, err := envelopeBalances(h.envelopes, h.summaries, h.expenditures)
+	if err != nil {
+		h.logger.Error("Failed to compute envelope balances", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully computed envelope balances", "count", len(balances))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(balances)
+}
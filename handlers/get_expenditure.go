@@ -2,9 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
-	"go-expense-tracker/domain"
 	"net/http"
-	"strings"
 )
 
 func (h *ExpenditureHandler) GetExpenditureByID(w http.ResponseWriter, r *http.Request) {
@@ -16,18 +14,16 @@ func (h *ExpenditureHandler) GetExpenditureByID(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	id := strings.TrimPrefix(r.URL.Path, "/expenditures/")
+	id, ok := pathID(w, r, "/expenditures/")
+	if !ok {
+		return
+	}
 	h.logger.Debug("Getting expenditure by ID", "id", id)
 
-	expenditure, err := h.service.GetExpenditureByID(id)
+	expenditure, err := h.expenditures.Get(id)
 	if err != nil {
-		if err == domain.ErrExpenditureNotFound {
-			h.logger.Warn("Expenditure not found", "id", id)
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		}
-		h.logger.Error("Failed to get expenditure by ID", "id", id, "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		h.logger.Warn("Failed to get expenditure by ID", "id", id, "error", err)
+		http.Error(w, err.Error(), serviceStatus(err))
 		return
 	}
 
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker/domain"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// GetExpenditureEvents lists the expenditure events, oldest first. `after` and `limit` page
+// through the whole log, e.g. to sync another system, and `expenditure` returns the full
+// history of one expenditure instead. The log can be filtered by `category`, `amount_above` and
+// `created_by`, or `mine=true` for the member making the request; pages are then filled with
+// matching events only
+func (h *EventHandler) GetExpenditureEvents(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get expenditure events request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+
+	if value := query.Get("expenditure"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			h.logger.Warn("Invalid expenditure ID", "expenditure", value)
+			http.Error(w, "Invalid expenditure ID", http.StatusBadRequest)
+			return
+		}
+
+		events, err := h.events.GetExpenditureHistory(id)
+		if err != nil {
+			h.logger.Error("Failed to get expenditure history", "id", id, "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if len(events) == 0 {
+			h.logger.Warn("Expenditure history not found", "id", id)
+			http.Error(w, domain.ErrExpenditureNotFound.Error(), http.StatusNotFound)
+			return
+		}
+
+		h.logger.Info("Successfully retrieved expenditure history", "id", id, "count", len(events))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(events)
+		return
+	}
+
+	limit := defaultPageSize
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxPageSize {
+			h.logger.Warn("Invalid page size", "limit", value)
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", maxPageSize), http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	var after int64
+	if value := query.Get("after"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 0 {
+			h.logger.Warn("Invalid after parameter", "after", value)
+			http.Error(w, "after must be a sequence number", http.StatusBadRequest)
+			return
+		}
+		after = parsed
+	}
+
+	filter, err := domain.ParseEventFilter(query)
+	if err != nil {
+		h.logger.Warn("Invalid event filter", "error", err, "query", r.URL.RawQuery)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if mine, _ := strconv.ParseBool(query.Get("mine")); mine {
+		if filter.CreatedBy = memberID(r.Context()); filter.CreatedBy == uuid.Nil {
+			h.logger.Warn("Own events requested without a member")
+			http.Error(w, "mine=true needs the X-Member-ID header", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// One extra event tells whether there is a next page
+	events, err := h.filteredEvents(after, limit+1, filter)
+	if err != nil {
+		h.logger.Error("Failed to get expenditure events", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(events) > limit {
+		events = events[:limit]
+		next := strconv.FormatInt(events[limit-1].Seq, 10)
+
+		nextQuery := r.URL.Query()
+		nextQuery.Set("after", next)
+		nextQuery.Set("limit", strconv.Itoa(limit))
+		w.Header().Set(nextCursorHeader, next)
+		w.Header().Set("Link", fmt.Sprintf("<%s?%s>; rel=\"next\"", r.URL.Path, nextQuery.Encode()))
+	}
+	if events == nil {
+		events = []*domain.ExpenditureEvent{}
+	}
+
+	h.logger.Info("Successfully retrieved expenditure events", "count", len(events), "has_next", w.Header().Get(nextCursorHeader) != "")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// filteredEvents returns up to limit events after the given sequence number that pass the
+// filter, reading the log a page at a time until enough of them match
+func (h *EventHandler) filteredEvents(after int64, limit int, filter domain.EventFilter) ([]*domain.ExpenditureEvent, error) {
+	if filter.IsZero() {
+		return h.events.GetExpenditureEvents(after, limit)
+	}
+
+	var matching []*domain.ExpenditureEvent
+	for len(matching) < limit {
+		events, err := h.events.GetExpenditureEvents(after, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range events {
+			state, err := h.eventState(event)
+			if err != nil {
+				return nil, err
+			}
+			if filter.Matches(state) {
+				matching = append(matching, event)
+			}
+		}
+
+		if len(events) < limit {
+			break
+		}
+		after = events[len(events)-1].Seq
+	}
+
+	if len(matching) > limit {
+		matching = matching[:limit]
+	}
+	return matching, nil
+}
+
+// eventState returns the expenditure a filter judges an event by: its state after the event, or
+// before it for deletions and archival
+func (h *EventHandler) eventState(event *domain.ExpenditureEvent) (*domain.Expenditure, error) {
+	if event.Expenditure != nil {
+		return event.Expenditure, nil
+	}
+
+	history, err := h.events.GetExpenditureHistory(event.ExpenditureID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.ExpenditureBefore(history, event.Seq), nil
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *ExpenseReportHandler) GetExpenseReportByID(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get expense report by ID request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/expense-reports/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Getting expense report by ID", "id", id)
+
+	report, err := h.reports.GetExpenseReportByID(id)
+	if err != nil {
+		if err == domain.ErrExpenseReportNotFound {
+			h.logger.Warn("Expense report not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get expense report by ID", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	detail, err := h.detail(report)
+	if err != nil {
+		h.logger.Error("Failed to get expense report expenditures", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully retrieved expense report", "id", id, "title", report.Title)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(detail)
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *ExpenseReportHandler) GetExpenseReportHistory(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get expense report history request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/expense-reports/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Getting expense report history", "id", id)
+
+	report, err := h.reports.GetExpenseReportByID(id)
+	if err != nil {
+		if err == domain.ErrExpenseReportNotFound {
+			h.logger.Warn("Expense report not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get expense report", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully retrieved expense report history", "id", id, "events", len(report.History))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report.History)
+}
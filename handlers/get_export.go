@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+// GetExport handles GET /exports/{id}, the status of an export with a freshly signed download
+// link once it has completed
+func (h *ExportHandler) GetExport(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get export request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/exports/")
+	if !ok {
+		return
+	}
+
+	job, err := h.getExportJob(id)
+	if err != nil {
+		if err == domain.ErrJobNotFound {
+			h.logger.Warn("Export not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get export", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully retrieved export", "id", id, "status", job.Status)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.newExportResponse(job))
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker/domain"
+	"net/http"
+	"strconv"
+)
+
+// GetFailedOutboxMessages lists the outbox messages the relay gave up on, oldest first, with the
+// error of their last attempt
+func (h *OutboxHandler) GetFailedOutboxMessages(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get failed outbox messages request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := defaultPageSize
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxPageSize {
+			h.logger.Warn("Invalid page size", "limit", value)
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", maxPageSize), http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	messages, err := h.outbox.GetFailedOutboxMessages(limit)
+	if err != nil {
+		h.logger.Error("Failed to get failed outbox messages", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if messages == nil {
+		messages = []*domain.OutboxMessage{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(messages)
+	h.logger.Info("Successfully retrieved failed outbox messages", "count", len(messages))
+}
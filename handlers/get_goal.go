@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *GoalHandler) GetGoalByID(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get goal by ID request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/goals/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Getting goal by ID", "id", id)
+
+	goal, err := h.goals.GetGoalByID(id)
+	if err != nil {
+		if err == domain.ErrGoalNotFound {
+			h.logger.Warn("Goal not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get goal by ID", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully retrieved goal", "id", id, "name", goal.Name)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(goal)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+	"time"
+)
+
+func (h *GoalHandler) GetGoalProgress(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get goal progress request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/goals/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Getting goal progress", "id", id)
+
+	goal, err := h.goals.GetGoalByID(id)
+	if err != nil {
+		if err == domain.ErrGoalNotFound {
+			h.logger.Warn("Goal not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get goal", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	expenditures, err := h.expenditures.GetAllExpenditures()
+	if err != nil {
+		h.logger.Error("Failed to get expenditures for goal progress", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	progress := goal.Progress(expenditures, time.Now())
+
+	h.logger.Info("Successfully computed goal progress", "id", id, "saved", progress.Saved, "on_track", progress.OnTrack)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(progress)
+}
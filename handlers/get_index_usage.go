@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// GetIndexUsage lists the database indexes with how often they were used, least used first
+func (h *IndexHandler) GetIndexUsage(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get index usage request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	usage, err := h.indexes.GetIndexUsage()
+	if err != nil {
+		h.logger.Error("Failed to get index usage", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(usage)
+	h.logger.Info("Successfully retrieved index usage", "count", len(usage))
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+func (h *InstallmentHandler) GetAllInstallmentPurchases(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get all installment purchases request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	purchases, err := h.service.List()
+	if err != nil {
+		h.logger.Error("Failed to get all installment purchases", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	response := make([]InstallmentPurchaseResponse, 0, len(purchases))
+	for _, purchase := range purchases {
+		response = append(response, installmentResponse(purchase, now))
+	}
+
+	h.logger.Info("Successfully retrieved all installment purchases", "count", len(response))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *InstallmentHandler) GetInstallmentPurchase(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get installment purchase request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/installments/")
+	if !ok {
+		return
+	}
+	purchase, err := h.service.Get(id)
+	if err != nil {
+		h.logger.Warn("Failed to get installment purchase", "id", id, "error", err)
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully retrieved installment purchase", "id", id)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(installmentResponse(purchase, time.Now()))
+}
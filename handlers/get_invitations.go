@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+	"time"
+)
+
+// GetInvitations handles GET /households/{id}/invitations, the pending invitations to a
+// household, newest first. `?status=all` includes those accepted, revoked or expired
+func (h *HouseholdHandler) GetInvitations(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get invitations request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	householdID, ok := pathID(w, r, "/households/")
+	if !ok {
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = domain.InvitationPending
+	}
+
+	invitations, err := h.service.Invitations(householdID)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	now := time.Now()
+	resp := make([]InvitationResponse, 0, len(invitations))
+	for _, invitation := range invitations {
+		if status == "all" || invitation.Status(now) == status {
+			resp = append(resp, newInvitationResponse(invitation, now))
+		}
+	}
+
+	h.logger.Info("Successfully retrieved invitations", "household_id", householdID, "status", status, "count", len(resp))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
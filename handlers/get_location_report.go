@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/reports"
+	"net/http"
+	"time"
+)
+
+func (h *ReportHandler) GetLocationReport(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get location report request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, to, err := h.parsePeriod(r)
+	if err != nil {
+		h.logger.Warn("Invalid date range", "error", err, "query", r.URL.RawQuery)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	includePending, err := parsePending(r)
+	if err != nil {
+		h.logger.Warn("Invalid pending option", "pending", r.URL.Query().Get("pending"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	original, err := parseAmounts(r)
+	if err != nil {
+		h.logger.Warn("Invalid amounts option", "amounts", r.URL.Query().Get("amounts"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	groupBy := r.URL.Query().Get("group")
+	if groupBy == "" {
+		groupBy = reports.GroupByCity
+	}
+	if groupBy != reports.GroupByCity && groupBy != reports.GroupByPlace {
+		h.logger.Warn("Unsupported location grouping", "group", groupBy)
+		http.Error(w, "Unsupported group, use city or place", http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "geojson" && format != "chart" {
+		h.logger.Warn("Unsupported location report format", "format", format)
+		http.Error(w, "Unsupported format, use json, geojson or chart", http.StatusBadRequest)
+		return
+	}
+
+	render := func(w http.ResponseWriter) error {
+		expenditures, err := h.service.GetAllExpenditures()
+		if err != nil {
+			h.logger.Error("Failed to get expenditures for location report", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return err
+		}
+
+		expenditures = reports.Actual(expenditures, time.Now())
+		if !includePending {
+			expenditures = reports.Cleared(expenditures)
+		}
+
+		totals := []reports.LocationTotal{}
+		for _, group := range reports.InCurrencies(reports.FilterByDate(expenditures, from, to), original) {
+			for _, total := range reports.LocationTotals(group.Expenditures, groupBy) {
+				total.Currency = group.Currency
+				totals = append(totals, total)
+			}
+		}
+
+		h.logger.Info("Successfully computed location report", "locations", len(totals), "group", groupBy, "format", format)
+		if format == "geojson" {
+			w.Header().Set("Content-Type", "application/geo+json")
+			return json.NewEncoder(w).Encode(reports.LocationGeoJSON(totals))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if format == "chart" {
+			return json.NewEncoder(w).Encode(reports.LocationChart(totals))
+		}
+		return json.NewEncoder(w).Encode(totals)
+	}
+
+	deferred, err := h.guard.deferLarge(w, r, h.service, "location-report", from, to, render)
+	if err != nil {
+		h.logger.Error("Failed to count expenditures for location report", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !deferred {
+		render(w)
+	}
+}
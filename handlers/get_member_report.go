@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/reports"
+	"net/http"
+	"time"
+)
+
+func (h *ReportHandler) GetMemberReport(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get member report request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.households == nil {
+		h.logger.Warn("Member report requested without household support")
+		http.Error(w, "Households are not supported by the storage", http.StatusNotFound)
+		return
+	}
+
+	from, to, err := h.parsePeriod(r)
+	if err != nil {
+		h.logger.Warn("Invalid date range", "error", err, "query", r.URL.RawQuery)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	includePending, err := parsePending(r)
+	if err != nil {
+		h.logger.Warn("Invalid pending option", "pending", r.URL.Query().Get("pending"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	original, err := parseAmounts(r)
+	if err != nil {
+		h.logger.Warn("Invalid amounts option", "amounts", r.URL.Query().Get("amounts"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	chart, err := parseChart(r)
+	if err != nil {
+		h.logger.Warn("Invalid format option", "format", r.URL.Query().Get("format"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	households, err := h.households.GetAllHouseholds()
+	if err != nil {
+		h.logger.Error("Failed to get households for member report", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var members []domain.HouseholdMember
+	for _, household := range households {
+		members = append(members, household.Members...)
+	}
+
+	var categories []*domain.Category
+	if h.categories != nil {
+		categories, err = h.categories.GetAllCategories()
+		if err != nil {
+			h.logger.Error("Failed to get categories for member report", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	render := func(w http.ResponseWriter) error {
+		expenditures, err := h.service.GetAllExpenditures()
+		if err != nil {
+			h.logger.Error("Failed to get expenditures for member report", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return err
+		}
+
+		expenditures = reports.Actual(expenditures, time.Now())
+		if !includePending {
+			expenditures = reports.Cleared(expenditures)
+		}
+
+		spending := []reports.MemberSpend{}
+		for _, group := range reports.InCurrencies(reports.FilterByDate(expenditures, from, to), original) {
+			for _, spend := range reports.MemberSpending(group.Expenditures, members, categories) {
+				spend.Currency = group.Currency
+				spending = append(spending, spend)
+			}
+		}
+
+		h.logger.Info("Successfully computed member report", "members", len(spending))
+		w.Header().Set("Content-Type", "application/json")
+		if chart {
+			return json.NewEncoder(w).Encode(reports.MemberChart(spending, categories))
+		}
+		return json.NewEncoder(w).Encode(spending)
+	}
+
+	deferred, err := h.guard.deferLarge(w, r, h.service, "member-report", from, to, render)
+	if err != nil {
+		h.logger.Error("Failed to count expenditures for member report", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !deferred {
+		render(w)
+	}
+}
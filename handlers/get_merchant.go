@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *MerchantHandler) GetMerchantByID(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get merchant by ID request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/merchants/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Getting merchant by ID", "id", id)
+
+	merchant, err := h.merchants.GetMerchantByID(id)
+	if err != nil {
+		if err == domain.ErrMerchantNotFound {
+			h.logger.Warn("Merchant not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get merchant by ID", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully retrieved merchant", "id", id, "name", merchant.Name)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(merchant)
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *MerchantHandler) GetMerchantExpenditures(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get merchant expenditures request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/merchants/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Getting merchant expenditures", "id", id)
+
+	merchant, err := h.merchants.GetMerchantByID(id)
+	if err != nil {
+		if err == domain.ErrMerchantNotFound {
+			h.logger.Warn("Merchant not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get merchant by ID", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	stream := newJSONStream(w, r)
+	err = domain.EachExpenditure(h.expenditures, func(expenditure *domain.Expenditure) error {
+		if expenditure.MerchantId != merchant.ID {
+			return nil
+		}
+		return stream.Write(expenditure)
+	})
+	if err == nil {
+		err = stream.Close()
+	}
+	if err != nil {
+		h.logger.Error("Failed to get merchant expenditures", "id", id, "error", err)
+		if !stream.Started() {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.logger.Info("Successfully retrieved merchant expenditures", "id", id, "count", stream.count)
+}
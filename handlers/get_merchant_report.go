@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/reports"
+	"net/http"
+	"time"
+)
+
+func (h *ReportHandler) GetMerchantReport(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get merchant report request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.merchants == nil {
+		h.logger.Warn("Merchant report requested without merchant support")
+		http.NotFound(w, r)
+		return
+	}
+
+	from, to, err := h.parsePeriod(r)
+	if err != nil {
+		h.logger.Warn("Invalid date range", "error", err, "query", r.URL.RawQuery)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	includePending, err := parsePending(r)
+	if err != nil {
+		h.logger.Warn("Invalid pending option", "pending", r.URL.Query().Get("pending"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	original, err := parseAmounts(r)
+	if err != nil {
+		h.logger.Warn("Invalid amounts option", "amounts", r.URL.Query().Get("amounts"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	chart, err := parseChart(r)
+	if err != nil {
+		h.logger.Warn("Invalid format option", "format", r.URL.Query().Get("format"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	merchants, err := h.merchants.GetAllMerchants()
+	if err != nil {
+		h.logger.Error("Failed to get merchants for merchant report", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	render := func(w http.ResponseWriter) error {
+		expenditures, err := h.service.GetAllExpenditures()
+		if err != nil {
+			h.logger.Error("Failed to get expenditures for merchant report", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return err
+		}
+
+		expenditures = reports.Actual(expenditures, time.Now())
+		if !includePending {
+			expenditures = reports.Cleared(expenditures)
+		}
+
+		totals := []reports.MerchantTotal{}
+		for _, group := range reports.InCurrencies(reports.FilterByDate(expenditures, from, to), original) {
+			for _, total := range reports.MerchantTotals(group.Expenditures, merchants) {
+				total.Currency = group.Currency
+				totals = append(totals, total)
+			}
+		}
+
+		h.logger.Info("Successfully computed merchant report", "merchants", len(totals))
+		w.Header().Set("Content-Type", "application/json")
+		if chart {
+			return json.NewEncoder(w).Encode(reports.MerchantChart(totals))
+		}
+		return json.NewEncoder(w).Encode(totals)
+	}
+
+	deferred, err := h.guard.deferLarge(w, r, h.service, "merchant-report", from, to, render)
+	if err != nil {
+		h.logger.Error("Failed to count expenditures for merchant report", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !deferred {
+		render(w)
+	}
+}
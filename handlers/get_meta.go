@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"go-expense-tracker/meta"
+	"net/http"
+	"strings"
+)
+
+// GetPalette lists the colors categories can be given
+func (h *MetaHandler) GetPalette(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get palette request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if err := writeCacheableJSON(w, r, meta.Palette(), metaCacheControl); err != nil {
+		h.logger.Error("Failed to write palette", "error", err)
+	}
+}
+
+// GetIcons lists the icons categories can be given, with the paths of their SVG files
+func (h *MetaHandler) GetIcons(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get icons request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if err := writeCacheableJSON(w, r, meta.Icons(), metaCacheControl); err != nil {
+		h.logger.Error("Failed to write icons", "error", err)
+	}
+}
+
+// GetIcon serves the SVG file of an icon, such as /meta/icons/car.svg
+func (h *MetaHandler) GetIcon(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get icon request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	name, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, meta.IconsPath+"/"), ".svg")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	svg, found := meta.IconSVG(name)
+	if !found {
+		h.logger.Warn("Icon not found", "name", name)
+		http.NotFound(w, r)
+		return
+	}
+
+	sum := sha256.Sum256(svg)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", metaCacheControl)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Write(svg)
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *OperationHandler) GetOperationByID(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get operation by ID request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/operations/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Getting operation by ID", "id", id)
+
+	operation, err := h.recorder.Get(id)
+	if err != nil {
+		if err == domain.ErrOperationNotFound {
+			h.logger.Warn("Operation not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get operation by ID", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully retrieved operation", "id", id, "kind", operation.Kind)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(operation)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/reports"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetPriceTrendReport handles GET /reports/price-trends, how the unit prices of repeat purchases
+// changed per month. It can be narrowed to a merchant with ?merchant_id= and to a unit with ?unit=
+func (h *ReportHandler) GetPriceTrendReport(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get price trend report request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, to, err := h.parsePeriod(r)
+	if err != nil {
+		h.logger.Warn("Invalid date range", "error", err, "query", r.URL.RawQuery)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	includePending, err := parsePending(r)
+	if err != nil {
+		h.logger.Warn("Invalid pending option", "pending", r.URL.Query().Get("pending"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	original, err := parseAmounts(r)
+	if err != nil {
+		h.logger.Warn("Invalid amounts option", "amounts", r.URL.Query().Get("amounts"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	chart, err := parseChart(r)
+	if err != nil {
+		h.logger.Warn("Invalid format option", "format", r.URL.Query().Get("format"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	var merchantID uuid.UUID
+	if value := query.Get("merchant_id"); value != "" {
+		if merchantID, err = uuid.Parse(value); err != nil {
+			h.logger.Warn("Invalid merchant ID", "merchant_id", value)
+			http.Error(w, "Invalid merchant ID", http.StatusBadRequest)
+			return
+		}
+	}
+	unit := query.Get("unit")
+
+	// Without a merchant directory, trends are told apart by their description alone
+	var merchants []*domain.Merchant
+	if h.merchants != nil {
+		if merchants, err = h.merchants.GetAllMerchants(); err != nil {
+			h.logger.Error("Failed to get merchants for price trend report", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	render := func(w http.ResponseWriter) error {
+		expenditures, err := h.service.GetAllExpenditures()
+		if err != nil {
+			h.logger.Error("Failed to get expenditures for price trend report", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return err
+		}
+
+		expenditures = reports.Actual(expenditures, time.Now())
+		if !includePending {
+			expenditures = reports.Cleared(expenditures)
+		}
+
+		selected := make([]*domain.Expenditure, 0, len(expenditures))
+		for _, expenditure := range reports.FilterByDate(expenditures, from, to) {
+			if (merchantID == uuid.Nil || expenditure.MerchantId == merchantID) && (unit == "" || expenditure.Unit == unit) {
+				selected = append(selected, expenditure)
+			}
+		}
+
+		trends := []reports.PriceTrend{}
+		for _, group := range reports.InCurrencies(selected, original) {
+			for _, trend := range reports.PriceTrends(group.Expenditures, merchants) {
+				trend.Currency = group.Currency
+				trends = append(trends, trend)
+			}
+		}
+
+		h.logger.Info("Successfully computed price trend report", "items", len(trends))
+		w.Header().Set("Content-Type", "application/json")
+		if chart {
+			return json.NewEncoder(w).Encode(reports.PriceTrendChart(trends))
+		}
+		return json.NewEncoder(w).Encode(trends)
+	}
+
+	deferred, err := h.guard.deferLarge(w, r, h.service, "price-trend-report", from, to, render)
+	if err != nil {
+		h.logger.Error("Failed to count expenditures for price trend report", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !deferred {
+		render(w)
+	}
+}
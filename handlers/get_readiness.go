@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// GetReadiness tells orchestrators whether the API is set up and connected to its storage:
+// 200 with {"status": "ready"} once it is, 503 with {"status": "starting"} before
+func (h *ReadinessHandler) GetReadiness(w http.ResponseWriter, r *http.Request) {
+	h.logger.Debug("Handling get readiness request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status, code := "ready", http.StatusOK
+	if !h.ready() {
+		status, code = "starting", http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"status": status})
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"go-expense-tracker/recording"
+	"net/http"
+)
+
+// GetRecordings downloads the recorded exchanges kept in memory as JSON lines, oldest first, the
+// input of the replay command
+func (h *RecordingHandler) GetRecordings(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get recordings request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	exchanges := h.recorder.Exchanges()
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", `attachment; filename="recordings.ndjson"`)
+	w.Header().Set("Cache-Control", "no-store")
+	if err := recording.Write(w, exchanges); err != nil {
+		h.logger.Error("Failed to write recordings", "error", err)
+		return
+	}
+	h.logger.Info("Retrieved recordings", "count", len(exchanges))
+}
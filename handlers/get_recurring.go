@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *RecurringHandler) GetRecurringByID(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get recurring expenditure by ID request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/recurring/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Getting recurring expenditure by ID", "id", id)
+
+	recurring, err := h.recurring.GetRecurringByID(id)
+	if err != nil {
+		if err == domain.ErrRecurringNotFound {
+			h.logger.Warn("Recurring expenditure not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get recurring expenditure by ID", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully retrieved recurring expenditure", "id", id, "description", recurring.Description)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recurring)
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker/domain"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const defaultUpcomingCount = 5
+const maxUpcomingCount = 100
+
+// UpcomingOccurrence is a projected occurrence of a recurring expenditure
+type UpcomingOccurrence struct {
+	RecurringID uuid.UUID `json:"recurring_id"`
+	Date        time.Time `json:"date"`
+	Description string    `json:"description"`
+	Amount      float64   `json:"amount"`
+	CategoryId  uuid.UUID `json:"category_id"`
+}
+
+// GetUpcomingRecurring handles GET /recurring/{id}/upcoming, which lists the next `count`
+// occurrences that will be generated, leaving out skipped ones and those in a pause
+func (h *RecurringHandler) GetUpcomingRecurring(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get upcoming recurring expenditures request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	count := defaultUpcomingCount
+	if value := r.URL.Query().Get("count"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxUpcomingCount {
+			h.logger.Warn("Invalid upcoming count", "count", value)
+			http.Error(w, fmt.Sprintf("count must be between 1 and %d", maxUpcomingCount), http.StatusBadRequest)
+			return
+		}
+		count = parsed
+	}
+
+	id, ok := pathID(w, r, "/recurring/")
+	if !ok {
+		return
+	}
+	recurring, err := h.recurring.GetRecurringByID(id)
+	if err != nil {
+		if err == domain.ErrRecurringNotFound {
+			h.logger.Warn("Recurring expenditure not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get recurring expenditure", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	upcoming := []UpcomingOccurrence{}
+	for _, date := range recurring.Upcoming(count) {
+		upcoming = append(upcoming, UpcomingOccurrence{
+			RecurringID: recurring.ID,
+			Date:        date,
+			Description: recurring.Description,
+			Amount:      recurring.Amount,
+			CategoryId:  recurring.CategoryId,
+		})
+	}
+
+	h.logger.Info("Successfully projected recurring expenditure", "id", id, "count", len(upcoming))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(upcoming)
+}
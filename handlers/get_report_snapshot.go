@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *ReportHandler) GetReportSnapshot(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get report snapshot request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/reports/snapshots/")
+	if !ok {
+		return
+	}
+	snapshot, err := h.snapshots.GetReportSnapshotByID(id)
+	if err != nil {
+		if err == domain.ErrReportSnapshotNotFound {
+			h.logger.Warn("Report snapshot not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get report snapshot", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully retrieved report snapshot", "id", id, "month", snapshot.Month)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(snapshot)
+}
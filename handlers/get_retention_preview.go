@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+	"time"
+)
+
+// GetRetentionPreview handles GET /admin/retention/preview, which lists the records the next
+// retention run would remove without removing them
+func (h *RetentionHandler) GetRetentionPreview(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get retention preview request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	purges, err := h.purger.Preview(time.Now())
+	if err != nil {
+		h.logger.Error("Failed to preview retention run", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if purges == nil {
+		purges = []*domain.RetentionPurge{}
+	}
+
+	h.logger.Info("Successfully previewed retention run", "rules", len(h.purger.Rules()), "purges", len(purges))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(RetentionPreviewResponse{Rules: h.purger.Rules(), Purges: purges})
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *RuleHandler) GetRuleByID(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get rule by ID request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/rules/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Getting rule by ID", "id", id)
+
+	rule, err := h.rules.GetRuleByID(id)
+	if err != nil {
+		if err == domain.ErrRuleNotFound {
+			h.logger.Warn("Rule not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get rule by ID", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully retrieved rule", "id", id, "name", rule.Name)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/i18n"
+	"go-expense-tracker/signedurl"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SharedReport is the read-only report of a fiscal month behind a shared link
+type SharedReport struct {
+	Month      string                    `json:"month"`
+	From       time.Time                 `json:"from"`
+	To         time.Time                 `json:"to"` // Day after the month
+	Count      int                       `json:"count"`
+	Total      float64                   `json:"total"`
+	Categories []domain.SnapshotCategory `json:"categories"`
+	ExpiresAt  time.Time                 `json:"expires_at"` // When the link stops working
+}
+
+var sharedReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 40em; margin: 2em auto; padding: 0 1em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { padding: 0.4em; border-bottom: 1px solid #ddd; text-align: left; }
+.amount { text-align: right; }
+tfoot td { font-weight: bold; }
+small { color: #666; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Period}}</p>
+<table>
+<thead><tr><th>{{.Labels.Category}}</th><th class="amount">{{.Labels.Expenditures}}</th><th class="amount">{{.Labels.Amount}}</th></tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr><td>{{.Name}}</td><td class="amount">{{.Count}}</td><td class="amount">{{.Total}}</td></tr>
+{{- end}}
+</tbody>
+<tfoot><tr><td>{{.Labels.Total}}</td><td class="amount">{{.Count}}</td><td class="amount">{{.Total}}</td></tr></tfoot>
+</table>
+<p><small>{{.Expires}}</small></p>
+</body>
+</html>
+`))
+
+// GetSharedReport handles GET /reports/shared/{month}, the report of a fiscal month behind a
+// link from POST /reports/share: an HTML page, or JSON with `Accept: application/json` or
+// `?format=json`. A missing or wrong signature is answered with 403 Forbidden, an expired link
+// with 410 Gone
+func (h *ReportShareHandler) GetSharedReport(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get shared report request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	month, _, _ := splitPath(strings.TrimSuffix(r.URL.Path, "/"), "/reports/shared/")
+	if err := h.links.Verify(sharedReportPath(month), r.URL.Query(), time.Now()); err != nil {
+		h.logger.Warn("Rejected shared report link", "month", month, "error", err)
+		if err == signedurl.ErrLinkExpired {
+			http.Error(w, err.Error(), http.StatusGone)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	snapshot, err := h.reports.Compute(month)
+	if err != nil {
+		if err == domain.ErrInvalidFiscalPeriod {
+			h.logger.Warn("Invalid shared report month", "month", month)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.logger.Error("Failed to compute shared report", "month", month, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	expires, _ := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+	report := SharedReport{
+		Month:      snapshot.Month,
+		From:       snapshot.From,
+		To:         snapshot.To,
+		Count:      snapshot.Count,
+		Total:      snapshot.Total,
+		Categories: snapshot.Categories,
+		ExpiresAt:  time.Unix(expires, 0).UTC(),
+	}
+
+	// Shared links are meant for one person, not for caches along the way
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+
+	if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		h.logger.Info("Successfully served shared report", "month", month, "format", "json")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+		return
+	}
+
+	locale, err := h.formatting.formatFor(r)
+	if err != nil {
+		h.logger.Warn("Unsupported shared report locale", "locale", r.URL.Query().Get("locale"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Info("Successfully served shared report", "month", month, "format", "html")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := sharedReportTemplate.Execute(w, sharedReportPage(r, report, locale, h.formatting.Currency)); err != nil {
+		h.logger.Error("Failed to write shared report", "month", month, "error", err)
+	}
+}
+
+type sharedReportRow struct {
+	Name  string
+	Count int
+	Total string
+}
+
+// sharedReportPage returns what the HTML page of a shared report shows, translated and
+// formatted for the reader
+func sharedReportPage(r *http.Request, report SharedReport, locale i18n.Format, currency string) any {
+	ctx := r.Context()
+	rows := make([]sharedReportRow, 0, len(report.Categories))
+	for _, category := range report.Categories {
+		name := category.Name
+		if name == "" {
+			name = i18n.T(ctx, "Uncategorized")
+		}
+		rows = append(rows, sharedReportRow{Name: name, Count: category.Count, Total: locale.Amount(category.Total, currency)})
+	}
+
+	return struct {
+		Title   string
+		Period  string
+		Expires string
+		Labels  struct{ Category, Expenditures, Amount, Total string }
+		Rows    []sharedReportRow
+		Count   int
+		Total   string
+	}{
+		Title:   i18n.T(ctx, "Spending Report") + " " + report.Month,
+		Period:  locale.Date(report.From) + " – " + locale.Date(report.To.AddDate(0, 0, -1)),
+		Expires: i18n.T(ctx, "This link expires on") + " " + locale.Date(report.ExpiresAt),
+		Labels: struct{ Category, Expenditures, Amount, Total string }{
+			Category:     i18n.T(ctx, "Category"),
+			Expenditures: i18n.T(ctx, "Expenditures"),
+			Amount:       i18n.T(ctx, "Amount"),
+			Total:        i18n.T(ctx, "Total"),
+		},
+		Rows:  rows,
+		Count: report.Count,
+		Total: locale.Amount(report.Total, currency),
+	}
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+// GetStagedCategorySuggestions suggests categories for an imported entry before it is approved
+func (h *ImportHandler) GetStagedCategorySuggestions(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get staged category suggestions request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.classifier == nil {
+		h.logger.Warn("Category suggestions requested without a classifier")
+		http.Error(w, "category suggestions are not enabled", http.StatusNotFound)
+		return
+	}
+
+	id, ok := pathID(w, r, "/imports/")
+	if !ok {
+		return
+	}
+
+	staged, err := h.imports.GetStagedExpenditureByID(id)
+	if err != nil {
+		if err == domain.ErrStagedExpenditureNotFound {
+			h.logger.Warn("Staged expenditure not found for category suggestions", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get staged expenditure", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeCategorySuggestions(w, r, h.classifier, staged.Description, staged.CategoryId, h.logger)
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *ImportHandler) GetStagedExpenditureByID(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get staged expenditure by ID request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/imports/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Getting staged expenditure by ID", "id", id)
+
+	staged, err := h.imports.GetStagedExpenditureByID(id)
+	if err != nil {
+		if err == domain.ErrStagedExpenditureNotFound {
+			h.logger.Warn("Staged expenditure not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get staged expenditure by ID", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully retrieved staged expenditure", "id", id, "status", staged.Status)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(staged)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/reports"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (h *ReportHandler) GetTaxReport(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get tax report request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	year := time.Now().Year()
+	if s := r.URL.Query().Get("year"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed < 1900 || parsed > 9999 {
+			h.logger.Warn("Invalid tax report year", "year", s)
+			http.Error(w, "Invalid year", http.StatusBadRequest)
+			return
+		}
+		year = parsed
+	}
+
+	includePending, err := parsePending(r)
+	if err != nil {
+		h.logger.Warn("Invalid pending option", "pending", r.URL.Query().Get("pending"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	chart, err := parseChart(r)
+	if err != nil {
+		h.logger.Warn("Invalid format option", "format", r.URL.Query().Get("format"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	deductible := make(map[uuid.UUID]bool)
+	if h.categories != nil {
+		categories, err := h.categories.GetAllCategories()
+		if err != nil {
+			h.logger.Error("Failed to get categories for tax report", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, category := range categories {
+			deductible[category.ID] = category.Deductible
+		}
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	summaries, err := reports.DailySpending(h.summaries, h.service, from, from.AddDate(1, 0, 0))
+	if err != nil {
+		h.logger.Error("Failed to get spending for tax report", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !includePending {
+		summaries = reports.ExcludePending(summaries)
+	}
+
+	summary := reports.TaxSummaryFromSummaries(summaries, deductible, year)
+
+	h.logger.Info("Successfully computed tax report", "year", year, "count", summary.Annual.Count)
+	w.Header().Set("Content-Type", "application/json")
+	if chart {
+		json.NewEncoder(w).Encode(reports.TaxChart(summary))
+		return
+	}
+	json.NewEncoder(w).Encode(summary)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+// GetViewExpenditures runs the filter of a saved view and lists the matching expenditures
+func (h *ViewHandler) GetViewExpenditures(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get view expenditures request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/views/")
+	if !ok {
+		return
+	}
+
+	view, err := h.views.GetViewByID(id)
+	if err != nil {
+		if err == domain.ErrViewNotFound {
+			h.logger.Warn("View not found", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get view", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	pinned, err := pinnedSet(h.pins)
+	if err != nil {
+		h.logger.Error("Failed to get pinned expenditures", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	stream := newJSONStream(w, r)
+	err = domain.EachExpenditure(h.expenditures, func(expenditure *domain.Expenditure) error {
+		if !view.Filter.Match(expenditure, pinned[expenditure.ID]) {
+			return nil
+		}
+		return stream.Write(expenditure)
+	})
+	if err == nil {
+		err = stream.Close()
+	}
+	if err != nil {
+		h.logger.Error("Failed to get view expenditures", "id", id, "error", err)
+		if !stream.Started() {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.logger.Info("Successfully retrieved view expenditures", "id", id, "name", view.Name, "count", stream.count)
+}
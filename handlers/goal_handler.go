@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type GoalHandler struct {
+	goals        domain.GoalRepository
+	expenditures domain.ExpenditureRepository
+	categories   domain.CategoryRepository
+	logger       *slog.Logger
+}
+
+// NewGoalHandler creates a new GoalHandler; categories may be nil when the storage has no
+// category support, in which case linked categories are not checked
+func NewGoalHandler(goals domain.GoalRepository, expenditures domain.ExpenditureRepository, categories domain.CategoryRepository, logger *slog.Logger) *GoalHandler {
+	return &GoalHandler{
+		goals:        goals,
+		expenditures: expenditures,
+		categories:   categories,
+		logger:       logger,
+	}
+}
+
+func GoalRouter(handler *GoalHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/goals" {
+			Methods{
+				http.MethodGet:  handler.GetAllGoals,
+				http.MethodPost: handler.AddGoal,
+			}.ServeHTTP(w, r)
+			return
+		}
+
+		_, sub, ok := splitPath(path, "/goals/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch sub {
+		case "":
+			Methods{
+				http.MethodGet:    handler.GetGoalByID,
+				http.MethodPut:    handler.UpdateGoal,
+				http.MethodDelete: handler.DeleteGoal,
+			}.ServeHTTP(w, r)
+		case "progress":
+			Methods{http.MethodGet: handler.GetGoalProgress}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// checkCategory reports whether the category exists, or true when categories are unavailable
+func (h *GoalHandler) checkCategory(id string) (bool, error) {
+	if h.categories == nil {
+		return true, nil
+	}
+
+	_, err := h.categories.GetCategoryByID(id)
+	if err == domain.ErrCategoryNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
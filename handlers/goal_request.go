@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type GoalRequest struct {
+	Name         string    `json:"name"`
+	TargetAmount float64   `json:"targetAmount"`
+	StartDate    time.Time `json:"startDate"` // Optional, defaults to now on creation
+	Deadline     time.Time `json:"deadline"`
+	CategoryId   uuid.UUID `json:"categoryId"`
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"go-expense-tracker/app"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type HouseholdHandler struct {
+	service    *app.HouseholdService
+	allowances *app.AllowanceService
+	logger     *slog.Logger
+}
+
+// NewHouseholdHandler creates a new HouseholdHandler; allowances may be nil when the storage has no
+// support for envelopes
+func NewHouseholdHandler(service *app.HouseholdService, allowances *app.AllowanceService, logger *slog.Logger) *HouseholdHandler {
+	return &HouseholdHandler{
+		service:    service,
+		allowances: allowances,
+		logger:     logger,
+	}
+}
+
+func HouseholdRouter(handler *HouseholdHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/households" {
+			Methods{
+				http.MethodGet:  handler.GetAllHouseholds,
+				http.MethodPost: handler.AddHousehold,
+			}.ServeHTTP(w, r)
+			return
+		}
+
+		// /households/{id}/invitations/{invitationId} nests one level deeper than other resources
+		if household, _, found := strings.Cut(strings.TrimPrefix(path, "/households/"), "/invitations/"); found {
+			if _, sub, ok := splitPath(path, "/households/"+household+"/invitations/"); !ok || sub != "" {
+				http.NotFound(w, r)
+				return
+			}
+			Methods{http.MethodDelete: handler.RevokeInvitation}.ServeHTTP(w, r)
+			return
+		}
+
+		// /households/{id}/members/{memberId}/allowance is the allowance of a member
+		if household, _, found := strings.Cut(strings.TrimPrefix(path, "/households/"), "/members/"); found {
+			if _, sub, ok := splitPath(path, "/households/"+household+"/members/"); !ok || sub != "allowance" || handler.allowances == nil {
+				http.NotFound(w, r)
+				return
+			}
+			Methods{
+				http.MethodGet:  handler.GetAllowance,
+				http.MethodPut:  handler.SetAllowance,
+				http.MethodPost: handler.AdjustAllowance,
+			}.ServeHTTP(w, r)
+			return
+		}
+
+		_, sub, ok := splitPath(path, "/households/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch sub {
+		case "":
+			Methods{http.MethodGet: handler.GetHousehold}.ServeHTTP(w, r)
+		case "invitations":
+			Methods{
+				http.MethodGet:  handler.GetInvitations,
+				http.MethodPost: handler.AddInvitation,
+			}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// InvitationRouter serves /invitations/{token}/accept, which invitees call without knowing the
+// household's ID
+func InvitationRouter(handler *HouseholdHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, sub, ok := splitPath(strings.TrimSuffix(r.URL.Path, "/"), "/invitations/")
+		if !ok || sub != "accept" {
+			http.NotFound(w, r)
+			return
+		}
+		Methods{http.MethodPost: handler.AcceptInvitation}.ServeHTTP(w, r)
+	})
+}
+
+// InvitationResponse is an invitation with its status. The token is only included when the
+// invitation is created and was not emailed
+type InvitationResponse struct {
+	*domain.Invitation
+	Status    string `json:"status"` // pending, accepted, revoked or expired
+	Token     string `json:"token,omitempty"`
+	AcceptURL string `json:"accept_url,omitempty"`
+	EmailSent bool   `json:"email_sent,omitempty"`
+}
+
+// AllowanceResponse is the allowance of a household member: the balance of its envelope, the
+// adjustments made to it and the expenditures the member recorded
+type AllowanceResponse struct {
+	MemberId uuid.UUID `json:"member_id"`
+	Name     string    `json:"name"`
+	*domain.EnvelopeBalance
+	Adjustments  []*domain.EnvelopeAllocation `json:"adjustments"`
+	Expenditures []*domain.Expenditure        `json:"expenditures"`
+}
+
+func newAllowanceResponse(allowance *app.Allowance) AllowanceResponse {
+	return AllowanceResponse{
+		MemberId:        allowance.Member.ID,
+		Name:            allowance.Member.Name,
+		EnvelopeBalance: allowance.Balance,
+		Adjustments:     allowance.Adjustments,
+		Expenditures:    allowance.Expenditures,
+	}
+}
+
+func newInvitationResponse(invitation *domain.Invitation, now time.Time) InvitationResponse {
+	return InvitationResponse{Invitation: invitation, Status: invitation.Status(now)}
+}
@@ -0,0 +1,119 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"go-expense-tracker/app"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/handlers"
+	"go-expense-tracker/services"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInvitationFlow(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	storage := services.NewMemoryService(logger)
+	householdHandler := handlers.NewHouseholdHandler(app.NewHouseholdService(storage, nil, logger), nil, logger)
+	mux := http.NewServeMux()
+	mux.Handle("/households", handlers.HouseholdRouter(householdHandler))
+	mux.Handle("/households/", handlers.HouseholdRouter(householdHandler))
+	mux.Handle("/invitations/", handlers.InvitationRouter(householdHandler))
+
+	serve := func(method, path, body string, v any) int {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		if v != nil && rec.Code < 300 {
+			if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+				t.Fatalf("%s %s: decoding response: %v", method, path, err)
+			}
+		}
+		return rec.Code
+	}
+
+	var household domain.Household
+	if code := serve(http.MethodPost, "/households", `{"name": "Home", "founder_name": "Alex"}`, &household); code != http.StatusCreated {
+		t.Fatalf("creating household: status = %d, want %d", code, http.StatusCreated)
+	}
+	invitations := "/households/" + household.ID.String() + "/invitations"
+	invite := func() handlers.InvitationResponse {
+		var invitation handlers.InvitationResponse
+		if code := serve(http.MethodPost, invitations, `{"role": "viewer", "valid_days": 2}`, &invitation); code != http.StatusCreated {
+			t.Fatalf("inviting: status = %d, want %d", code, http.StatusCreated)
+		}
+		return invitation
+	}
+	accepted, revoked := invite(), invite()
+	// Invitations cannot be issued expired, so one is stored directly
+	expired, expiredToken, err := domain.NewInvitation(household.ID, "", domain.RoleMember, -time.Minute)
+	if err != nil {
+		t.Fatalf("creating invitation: %v", err)
+	}
+	if err := storage.AddInvitation(expired); err != nil {
+		t.Fatalf("adding invitation: %v", err)
+	}
+
+	accept := func(invitation handlers.InvitationResponse) string { return invitation.AcceptURL }
+	revoke := func(invitation handlers.InvitationResponse) string {
+		return invitations + "/" + invitation.ID.String()
+	}
+
+	// The steps run in order against the same storage
+	steps := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"accept an unknown token", http.MethodPost, "/invitations/guess/accept", `{"name": "Sam"}`, http.StatusNotFound},
+		{"accept without a name", http.MethodPost, accept(accepted), `{"name": ""}`, http.StatusBadRequest},
+		{"accept", http.MethodPost, accept(accepted), `{"name": "Sam"}`, http.StatusCreated},
+		{"accept twice", http.MethodPost, accept(accepted), `{"name": "Kim"}`, http.StatusConflict},
+		{"revoke once accepted", http.MethodDelete, revoke(accepted), "", http.StatusConflict},
+		{"revoke", http.MethodDelete, revoke(revoked), "", http.StatusNoContent},
+		{"revoke twice", http.MethodDelete, revoke(revoked), "", http.StatusGone},
+		{"accept once revoked", http.MethodPost, accept(revoked), `{"name": "Kim"}`, http.StatusGone},
+		{"accept once expired", http.MethodPost, "/invitations/" + expiredToken + "/accept", `{"name": "Kim"}`, http.StatusGone},
+		{"revoke once expired", http.MethodDelete, invitations + "/" + expired.ID.String(), "", http.StatusGone},
+	}
+
+	for _, step := range steps {
+		if code := serve(step.method, step.path, step.body, nil); code != step.wantStatus {
+			t.Errorf("%s: status = %d, want %d", step.name, code, step.wantStatus)
+		}
+	}
+
+	var listed []handlers.InvitationResponse
+	if code := serve(http.MethodGet, invitations+"?status=all", "", &listed); code != http.StatusOK {
+		t.Fatalf("listing invitations: status = %d, want %d", code, http.StatusOK)
+	}
+	statuses := map[string]string{}
+	for _, invitation := range listed {
+		statuses[invitation.ID.String()] = invitation.Status
+		if invitation.Token != "" {
+			t.Errorf("invitation %s lists its token", invitation.ID)
+		}
+	}
+	want := map[string]string{
+		accepted.ID.String(): domain.InvitationAccepted,
+		revoked.ID.String():  domain.InvitationRevoked,
+		expired.ID.String():  domain.InvitationExpired,
+	}
+	for id, status := range want {
+		if statuses[id] != status {
+			t.Errorf("invitation %s is %q, want %q", id, statuses[id], status)
+		}
+	}
+
+	if code := serve(http.MethodGet, invitations, "", &listed); code != http.StatusOK || len(listed) != 0 {
+		t.Errorf("pending invitations: status = %d with %d, want %d with none", code, len(listed), http.StatusOK)
+	}
+	if code := serve(http.MethodGet, "/households/"+household.ID.String(), "", &household); code != http.StatusOK || len(household.Members) != 2 || household.Members[1].Role != domain.RoleViewer {
+		t.Errorf("household: status = %d with members %+v, want %d with the founder and a viewer", code, household.Members, http.StatusOK)
+	}
+}
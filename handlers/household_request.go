@@ -0,0 +1,34 @@
+package handlers
+
+import "github.com/google/uuid"
+
+// HouseholdRequest creates a household owned by its founder
+type HouseholdRequest struct {
+	Name         string `json:"name"`
+	FounderName  string `json:"founder_name"`
+	FounderEmail string `json:"founder_email"`
+}
+
+// InvitationRequest invites someone to a household
+type InvitationRequest struct {
+	Email     string `json:"email"`      // Where the invitation is sent, if emails are configured
+	Role      string `json:"role"`       // owner, member (default), viewer or child
+	ValidDays int    `json:"valid_days"` // Days until the invitation expires, 7 by default and at most 30
+}
+
+// AcceptInvitationRequest names the member joining with an invitation
+type AcceptInvitationRequest struct {
+	Name string `json:"name"`
+}
+
+// AllowanceRequest makes the envelope of a category a member's allowance
+type AllowanceRequest struct {
+	CategoryId uuid.UUID `json:"category_id"`
+}
+
+// AllowanceAdjustmentRequest tops up a member's allowance, or takes money out of it with a
+// negative amount
+type AllowanceAdjustmentRequest struct {
+	Amount float64 `json:"amount"`
+	Note   string  `json:"note"`
+}
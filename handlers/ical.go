@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// icalWriter writes RFC 5545 content lines, escaping text and folding long lines
+type icalWriter struct {
+	w   io.Writer
+	err error
+}
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func (iw *icalWriter) line(name, value string) {
+	if iw.err != nil {
+		return
+	}
+
+	content := name + ":" + value
+
+	// Fold lines longer than 75 octets without splitting UTF-8 sequences
+	var b strings.Builder
+	lineLen := 0
+	for _, r := range content {
+		size := len(string(r))
+		if lineLen+size > 75 {
+			b.WriteString("\r\n ")
+			lineLen = 1
+		}
+		b.WriteRune(r)
+		lineLen += size
+	}
+	b.WriteString("\r\n")
+
+	_, iw.err = io.WriteString(iw.w, b.String())
+}
+
+func (iw *icalWriter) text(name, value string) {
+	iw.line(name, icalEscaper.Replace(value))
+}
+
+// event writes an all-day event on day, shown as free time
+func (iw *icalWriter) event(uid string, stamp, day time.Time, summary string) {
+	iw.line("BEGIN", "VEVENT")
+	iw.line("UID", uid)
+	iw.line("DTSTAMP", icalTimestamp(stamp))
+	iw.line("DTSTART;VALUE=DATE", icalDate(day))
+	iw.line("DTEND;VALUE=DATE", icalDate(day.AddDate(0, 0, 1)))
+	iw.text("SUMMARY", summary)
+	iw.line("TRANSP", "TRANSPARENT")
+	iw.line("END", "VEVENT")
+}
+
+func icalDate(t time.Time) string {
+	return t.Format("20060102")
+}
+
+func icalTimestamp(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func icalUID(kind, id string) string {
+	return fmt.Sprintf("%s-%s@go-expense-tracker", kind, id)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"go-expense-tracker/interchange"
+	"io"
+	"net/http"
+)
+
+// ImportGnuCash handles POST /interchange/gnucash, importing the spending of a GnuCash transaction
+// CSV export or XML book sent as the body; books are recognized by their content, compressed or
+// not. For CSV exports `?date_format=DD/MM/YYYY` reads dates written for other locales and
+// `?expenses=` names the top-level expense account, Expenses by default. `?dry_run=true` reports
+// what would be imported without saving anything
+func (h *InterchangeHandler) ImportGnuCash(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling import GnuCash request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	layout, err := interchange.ParseDateFormat(r.URL.Query().Get("date_format"))
+	if err != nil {
+		h.logger.Warn("Invalid date format", "date_format", r.URL.Query().Get("date_format"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	body := bufio.NewReader(http.MaxBytesReader(w, r.Body, maxInterchangeFile))
+	var transactions []interchange.Transaction
+	var skipped int
+	kind := "GnuCash CSV"
+	if isGnuCashBook(body) {
+		kind = "GnuCash book"
+		transactions, skipped, err = interchange.ReadGnuCashXML(body)
+	} else {
+		transactions, skipped, err = interchange.ReadGnuCashCSV(body, layout, r.URL.Query().Get("expenses"))
+	}
+	if err != nil {
+		h.rejectFile(w, err, kind)
+		return
+	}
+
+	summary, err := h.service.ImportTransactions(string(interchange.FormatGnuCash), transactions, skipped, dryRun)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully imported from GnuCash", "kind", kind, "imported", summary.Imported, "duplicates", summary.Duplicates, "skipped", summary.Skipped, "dry_run", dryRun)
+	h.writeImportSummary(w, summary)
+}
+
+// isGnuCashBook reports whether a file is an XML book, gzipped as GnuCash saves it or plain,
+// rather than a CSV export
+func isGnuCashBook(body *bufio.Reader) bool {
+	start, err := body.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return false
+	}
+	if len(start) >= 2 && start[0] == 0x1f && start[1] == 0x8b {
+		return true
+	}
+	return bytes.HasPrefix(bytes.TrimLeft(start, "\ufeff \t\r\n"), []byte("<"))
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"go-expense-tracker/app"
+	"go-expense-tracker/classifier"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/merchants"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type ImportHandler struct {
+	imports      domain.ImportRepository
+	expenditures *app.ExpenditureService
+	repository   domain.ExpenditureRepository
+	merchants    *merchants.Resolver
+	rules        domain.CategorizationRuleRepository
+	classifier   *classifier.Classifier
+	logger       *slog.Logger
+}
+
+// NewImportHandler creates a new ImportHandler; approved entries are recorded through
+// expenditures, while pending expenditures they settle are looked up and cleared in repository.
+// merchants may be nil when the storage has no merchant support, otherwise approved entries are
+// linked to a merchant, creating it if needed. rules may be nil when the storage keeps no
+// categorization rules and classifier when categories are not suggested
+func NewImportHandler(imports domain.ImportRepository, expenditures *app.ExpenditureService, repository domain.ExpenditureRepository, merchants *merchants.Resolver, rules domain.CategorizationRuleRepository, classifier *classifier.Classifier, logger *slog.Logger) *ImportHandler {
+	return &ImportHandler{
+		imports:      imports,
+		expenditures: expenditures,
+		repository:   repository,
+		merchants:    merchants,
+		rules:        rules,
+		classifier:   classifier,
+		logger:       logger,
+	}
+}
+
+func ImportRouter(handler *ImportHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/imports" {
+			Methods{http.MethodGet: handler.GetAllStagedExpenditures}.ServeHTTP(w, r)
+			return
+		}
+
+		_, sub, ok := splitPath(path, "/imports/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch sub {
+		case "":
+			Methods{http.MethodGet: handler.GetStagedExpenditureByID}.ServeHTTP(w, r)
+		case "approve":
+			Methods{http.MethodPost: handler.ApproveStagedExpenditure}.ServeHTTP(w, r)
+		case "reject":
+			Methods{http.MethodPost: handler.RejectStagedExpenditure}.ServeHTTP(w, r)
+		case "category-suggestions":
+			Methods{http.MethodGet: handler.GetStagedCategorySuggestions}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"go-expense-tracker/interchange"
+	"net/http"
+)
+
+// ImportYNAB handles POST /interchange/ynab, importing the transactions of a YNAB register export
+// sent as the body. `?date_format=DD/MM/YYYY` reads dates written for other locales and
+// `?dry_run=true` reports what would be imported without saving anything
+func (h *InterchangeHandler) ImportYNAB(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling import YNAB register request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	layout, err := interchange.ParseDateFormat(r.URL.Query().Get("date_format"))
+	if err != nil {
+		h.logger.Warn("Invalid date format", "date_format", r.URL.Query().Get("date_format"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	transactions, skipped, err := interchange.ReadYNABRegister(http.MaxBytesReader(w, r.Body, maxInterchangeFile), layout)
+	if err != nil {
+		h.rejectFile(w, err, "YNAB register")
+		return
+	}
+
+	summary, err := h.service.ImportTransactions(string(interchange.FormatYNAB), transactions, skipped, dryRun)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully imported YNAB register", "imported", summary.Imported, "duplicates", summary.Duplicates, "skipped", summary.Skipped, "dry_run", dryRun)
+	h.writeImportSummary(w, summary)
+}
+
+// rejectFile responds to a file that could not be read, too large or malformed
+func (h *InterchangeHandler) rejectFile(w http.ResponseWriter, err error, kind string) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		h.logger.Warn("Imported file too large", "kind", kind, "limit", tooLarge.Limit)
+		http.Error(w, "File too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	h.logger.Warn("Invalid imported file", "kind", kind, "error", err)
+	http.Error(w, "Invalid "+kind+": "+err.Error(), http.StatusBadRequest)
+}
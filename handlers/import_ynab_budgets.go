@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"go-expense-tracker/interchange"
+	"net/http"
+)
+
+// ImportYNABBudgets handles POST /interchange/ynab/budgets, turning the plan export of a YNAB
+// budget sent as the body into monthly budgets on its categories. `?dry_run=true` reports what
+// would be created without saving anything
+func (h *InterchangeHandler) ImportYNABBudgets(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling import YNAB budgets request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.budgets == nil || h.categories == nil {
+		h.logger.Warn("Budgets or categories are not supported by the storage")
+		http.Error(w, "Budgets are not supported by the storage", http.StatusNotFound)
+		return
+	}
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	plans, skipped, err := interchange.ReadYNABPlan(http.MaxBytesReader(w, r.Body, maxInterchangeFile))
+	if err != nil {
+		h.rejectFile(w, err, "YNAB plan")
+		return
+	}
+
+	summary, err := h.service.ImportBudgets(string(interchange.FormatYNAB), plans, skipped, dryRun)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully imported YNAB budgets", "imported", summary.Imported, "duplicates", summary.Duplicates, "dry_run", dryRun)
+	h.writeImportSummary(w, summary)
+}
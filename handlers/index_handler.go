@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+)
+
+type IndexHandler struct {
+	indexes domain.IndexStatsRepository
+	logger  *slog.Logger
+}
+
+func NewIndexHandler(indexes domain.IndexStatsRepository, logger *slog.Logger) *IndexHandler {
+	return &IndexHandler{
+		indexes: indexes,
+		logger:  logger,
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"go-expense-tracker/app"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type InstallmentHandler struct {
+	service *app.InstallmentService
+	logger  *slog.Logger
+}
+
+func NewInstallmentHandler(service *app.InstallmentService, logger *slog.Logger) *InstallmentHandler {
+	return &InstallmentHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func InstallmentRouter(handler *InstallmentHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/installments" {
+			Methods{
+				http.MethodGet:  handler.GetAllInstallmentPurchases,
+				http.MethodPost: handler.AddInstallmentPurchase,
+			}.ServeHTTP(w, r)
+			return
+		}
+
+		_, sub, ok := splitPath(path, "/installments/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch sub {
+		case "":
+			Methods{http.MethodGet: handler.GetInstallmentPurchase}.ServeHTTP(w, r)
+		case "amend":
+			Methods{http.MethodPost: handler.AmendInstallments}.ServeHTTP(w, r)
+		case "pay-off":
+			Methods{http.MethodPost: handler.PayOffInstallments}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// InstallmentPurchaseResponse is an installment purchase with how far it has been paid
+type InstallmentPurchaseResponse struct {
+	*domain.InstallmentPurchase
+	Balance domain.InstallmentBalance `json:"balance"`
+}
+
+func installmentResponse(purchase *domain.InstallmentPurchase, now time.Time) InstallmentPurchaseResponse {
+	return InstallmentPurchaseResponse{InstallmentPurchase: purchase, Balance: purchase.Balance(now)}
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InstallmentRequest struct {
+	Description  string    `json:"description"`
+	TotalAmount  float64   `json:"totalAmount"`
+	Installments int       `json:"installments"` // Number of monthly installments
+	FirstDate    time.Time `json:"firstDate"`    // Optional, defaults to today
+	CategoryId   uuid.UUID `json:"categoryId"`   // Optional, the uncategorized category without it
+	Tags         []string  `json:"tags"`
+}
+
+type AmendInstallmentsRequest struct {
+	Installments    int     `json:"installments"`    // Number of remaining installments
+	RemainingAmount float64 `json:"remainingAmount"` // Amount the remaining installments add up to
+}
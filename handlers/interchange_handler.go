@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/app"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// maxInterchangeFile bounds the size of a file imported from another tool
+const maxInterchangeFile = 64 << 20
+
+type InterchangeHandler struct {
+	service      *app.InterchangeService
+	budgets      domain.BudgetRepository
+	expenditures domain.ExpenditureRepository
+	categories   domain.CategoryRepository
+	summaries    domain.SpendingSummaryRepository
+	calendar     domain.FiscalCalendar
+	logger       *slog.Logger
+}
+
+// NewInterchangeHandler creates a new InterchangeHandler for moving histories in from and out to
+// YNAB and GnuCash; budgets, categories and summaries may be nil when the storage has no support
+// for them. Expenditures are exported through GET /expenditures/export
+func NewInterchangeHandler(service *app.InterchangeService, budgets domain.BudgetRepository, expenditures domain.ExpenditureRepository, categories domain.CategoryRepository, summaries domain.SpendingSummaryRepository, calendar domain.FiscalCalendar, logger *slog.Logger) *InterchangeHandler {
+	return &InterchangeHandler{
+		service:      service,
+		budgets:      budgets,
+		expenditures: expenditures,
+		categories:   categories,
+		summaries:    summaries,
+		calendar:     calendar,
+		logger:       logger,
+	}
+}
+
+func InterchangeRouter(handler *InterchangeHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimSuffix(r.URL.Path, "/") {
+		case "/interchange/ynab":
+			Methods{http.MethodPost: handler.ImportYNAB}.ServeHTTP(w, r)
+		case "/interchange/ynab/budgets":
+			Methods{
+				http.MethodGet:  handler.ExportYNABBudgets,
+				http.MethodPost: handler.ImportYNABBudgets,
+			}.ServeHTTP(w, r)
+		case "/interchange/gnucash":
+			Methods{http.MethodPost: handler.ImportGnuCash}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// writeImportSummary responds with what an import added, 200 for a dry run and 201 otherwise
+func (h *InterchangeHandler) writeImportSummary(w http.ResponseWriter, summary *app.ImportSummary) {
+	w.Header().Set("Content-Type", "application/json")
+	if !summary.DryRun {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(summary)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"go-expense-tracker/jobs"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type JobHandler struct {
+	jobs   *jobs.Runner
+	logger *slog.Logger
+}
+
+func NewJobHandler(runner *jobs.Runner, logger *slog.Logger) *JobHandler {
+	return &JobHandler{
+		jobs:   runner,
+		logger: logger,
+	}
+}
+
+func JobRouter(handler *JobHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/jobs" {
+			Methods{http.MethodGet: handler.GetAllJobs}.ServeHTTP(w, r)
+			return
+		}
+
+		_, sub, ok := splitPath(path, "/jobs/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch sub {
+		case "":
+			Methods{http.MethodGet: handler.GetJobByID}.ServeHTTP(w, r)
+		case "download":
+			Methods{http.MethodGet: handler.DownloadJobResult}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// JobResponse is a job with the link to its result once it has completed
+type JobResponse struct {
+	*domain.Job
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+func newJobResponse(job *domain.Job) JobResponse {
+	resp := JobResponse{Job: job}
+	if job.Status == domain.JobCompleted {
+		resp.DownloadURL = "/jobs/" + job.ID.String() + "/download"
+	}
+	return resp
+}
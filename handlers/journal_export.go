@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"fmt"
+	"go-expense-tracker/anonymize"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/interchange"
+	"go-expense-tracker/ledger"
+	"net/http"
+	"time"
+)
+
+// JournalExport configures exports of expenditures as plain-text accounting journals, whose currency
+// YNAB and GnuCash files share; accounts and merchants may be nil when the storage has no support
+// for them
+type JournalExport struct {
+	Chart     ledger.ChartOfAccounts
+	Accounts  domain.AccountRepository
+	Merchants domain.MerchantRepository
+}
+
+// entryWriter writes expenditures one at a time as a file, a journal or a file of another tool
+type entryWriter interface {
+	Write(expenditure *domain.Expenditure) error
+	Close() error
+	Count() int
+	Started() bool
+}
+
+// bookNames reads the categories, accounts and merchants that name the accounts and payees of an
+// exported file
+func (h *ExpenditureHandler) bookNames() ([]*domain.Category, []*domain.Account, []*domain.Merchant, error) {
+	var categories []*domain.Category
+	var accounts []*domain.Account
+	var merchants []*domain.Merchant
+	var err error
+
+	if h.categories != nil {
+		if categories, err = h.categories.GetAllCategories(); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if h.journals.Accounts != nil {
+		if accounts, err = h.journals.Accounts.GetAllAccounts(); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if h.journals.Merchants != nil {
+		if merchants, err = h.journals.Merchants.GetAllMerchants(); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return categories, accounts, merchants, nil
+}
+
+// newJournal creates a journal booking on the chart of accounts
+func (h *ExpenditureHandler) newJournal(w http.ResponseWriter, format ledger.Format) (entryWriter, error) {
+	categories, accounts, merchants, err := h.bookNames()
+	if err != nil {
+		return nil, err
+	}
+
+	filename := "expenditures-" + time.Now().Format("2006-01-02")
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format.Extension()))
+	return ledger.NewJournal(w, format, h.journals.Chart, categories, accounts, merchants), nil
+}
+
+// newInterchangeWriter creates a writer of a file of another tool, in the currency of journals
+func (h *ExpenditureHandler) newInterchangeWriter(w http.ResponseWriter, format interchange.Format, layout string) (entryWriter, error) {
+	categories, accounts, merchants, err := h.bookNames()
+	if err != nil {
+		return nil, err
+	}
+
+	filename := "expenditures-" + time.Now().Format("2006-01-02") + "-" + string(format)
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format.Extension()))
+	options := interchange.Options{DateLayout: layout, Currency: h.journals.Chart.Currency}
+	return interchange.NewWriter(w, format, options, categories, accounts, merchants), nil
+}
+
+// writeEntries writes the expenditures within [from, to) with a writer created by create. Cancelled
+// expenditures and planned ones not due yet are left out, as no money has moved for them
+func (h *ExpenditureHandler) writeEntries(w http.ResponseWriter, format string, create func(http.ResponseWriter) (entryWriter, error), from, to time.Time, anonymizer *anonymize.Anonymizer) error {
+	writer, err := create(w)
+	if err != nil {
+		h.logger.Error("Failed to read the names of an export", "error", err, "format", format)
+		w.Header().Del("Content-Disposition")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return err
+	}
+
+	now := time.Now()
+	err = domain.EachExpenditure(h.service, func(expenditure *domain.Expenditure) error {
+		if (!from.IsZero() && expenditure.Date.Before(from)) || (!to.IsZero() && !expenditure.Date.Before(to)) {
+			return nil
+		}
+		if expenditure.IsCancelled() || expenditure.IsPlanned(now) {
+			return nil
+		}
+		if anonymizer != nil {
+			expenditure = anonymizer.Expenditure(expenditure)
+		}
+		return writer.Write(expenditure)
+	})
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		h.logger.Error("Failed to export expenditures", "error", err, "format", format)
+		if !writer.Started() {
+			w.Header().Del("Content-Disposition")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return err
+	}
+
+	h.logger.Info("Successfully exported expenditures", "format", format, "count", writer.Count(), "anonymized", anonymizer != nil)
+	return nil
+}
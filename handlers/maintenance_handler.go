@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"go-expense-tracker/maintenance"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type MaintenanceHandler struct {
+	mode   *maintenance.Mode
+	token  string
+	logger *slog.Logger
+}
+
+// NewMaintenanceHandler creates a new MaintenanceHandler; every request must carry token as
+// bearer token
+func NewMaintenanceHandler(mode *maintenance.Mode, token string, logger *slog.Logger) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		mode:   mode,
+		token:  token,
+		logger: logger,
+	}
+}
+
+func MaintenanceRouter(handler *MaintenanceHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(token), []byte(handler.token)) != 1 {
+			handler.logger.Warn("Maintenance request without a valid token", "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+			http.Error(w, "Admin token required", http.StatusUnauthorized)
+			return
+		}
+
+		Methods{
+			http.MethodGet: handler.GetMaintenance,
+			http.MethodPut: handler.SetMaintenance,
+		}.ServeHTTP(w, r)
+	})
+}
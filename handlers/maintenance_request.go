@@ -0,0 +1,7 @@
+package handlers
+
+type MaintenanceRequest struct {
+	ReadOnly   *bool  `json:"read_only"`
+	Reason     string `json:"reason"`      // Why, for admins, e.g. "Nightly backup"
+	RetryAfter string `json:"retry_after"` // Expected duration such as "10m", default 5m
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// MemberHeader names the household member making a request, so expenditures can be attributed
+// to who entered them without separate accounts
+const MemberHeader = "X-Member-ID"
+
+var errUnknownMember = errors.New("unknown household member in X-Member-ID")
+var errMemberRequired = errors.New("X-Member-ID is required once a household has viewers or children")
+
+// credentialPrefixes are the paths that take requests naming no member whatever the roles of the
+// households: they are public, or check a credential of their own such as an admin token, a
+// webhook signature, an invitation token or a signed link. Admin paths checking no token of their
+// own, such as archiving and seeding, are not among them
+var credentialPrefixes = []string{
+	"/admin/maintenance", "/admin/flags", "/admin/outbox/", "/admin/chaos", "/admin/recordings", "/admin/console/",
+	"/integrations/", "/invitations/", "/reports/shared/", "/calendar.ics", "/meta/", "/version",
+}
+
+type memberKey struct{}
+
+// WithMember returns a context carrying the household member making a request
+func WithMember(ctx context.Context, member *domain.HouseholdMember) context.Context {
+	return context.WithValue(ctx, memberKey{}, member)
+}
+
+// Member returns the household member of the context, nil when the request named none
+func Member(ctx context.Context) *domain.HouseholdMember {
+	member, _ := ctx.Value(memberKey{}).(*domain.HouseholdMember)
+	return member
+}
+
+// memberID returns the ID of the household member of the context, uuid.Nil when there is none
+func memberID(ctx context.Context) uuid.UUID {
+	if member := Member(ctx); member != nil {
+		return member.ID
+	}
+	return uuid.Nil
+}
+
+// MemberMiddleware looks up the household member named by the X-Member-ID header and makes it
+// available through Member. Requests naming an unknown member are refused. Requests naming no
+// member pass unchanged until a household has a member whose role restricts them, such as a
+// viewer or a child, who could otherwise leave out the header; then only preflights and paths
+// with credentials of their own take them
+func MemberMiddleware(households domain.HouseholdRepository, logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get(MemberHeader)
+		if header == "" {
+			if r.Method == http.MethodOptions || hasCredential(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			restricted, err := hasRestrictedMembers(households)
+			if err != nil {
+				logger.Error("Failed to get households", "error", err)
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if restricted {
+				logger.Warn("Request names no member", "method", r.Method, "path", r.URL.Path)
+				http.Error(w, errMemberRequired.Error(), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		id, err := uuid.Parse(header)
+		if err != nil {
+			logger.Warn("Invalid member ID", "error", err, "member_id", header)
+			http.Error(w, errUnknownMember.Error(), http.StatusBadRequest)
+			return
+		}
+
+		member, err := households.GetHouseholdMember(id.String())
+		if err != nil {
+			if errors.Is(err, domain.ErrMemberNotFound) {
+				logger.Warn("Unknown member", "member_id", id)
+				http.Error(w, errUnknownMember.Error(), http.StatusBadRequest)
+				return
+			}
+			logger.Error("Failed to get member", "error", err, "member_id", id)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithMember(r.Context(), member)))
+	})
+}
+
+// hasCredential reports whether path takes requests naming no member, see credentialPrefixes.
+// Export downloads are signed links too
+func hasCredential(path string) bool {
+	for _, prefix := range credentialPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return strings.HasPrefix(path, "/exports/") && strings.HasSuffix(strings.TrimSuffix(path, "/"), "/download")
+}
+
+// hasRestrictedMembers reports whether a household has a member who may not record expenditures
+// for everyone
+func hasRestrictedMembers(households domain.HouseholdRepository) (bool, error) {
+	all, err := households.GetAllHouseholds()
+	if err != nil {
+		return false, err
+	}
+	for _, household := range all {
+		for _, member := range household.Members {
+			if !member.Role.Can(domain.PermissionWrite) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type MerchantHandler struct {
+	merchants    domain.MerchantRepository
+	expenditures domain.ExpenditureRepository
+	categories   domain.CategoryRepository
+	logger       *slog.Logger
+}
+
+// NewMerchantHandler creates a new MerchantHandler; categories may be nil when the storage has
+// no category support, in which case default categories are not checked
+func NewMerchantHandler(merchants domain.MerchantRepository, expenditures domain.ExpenditureRepository, categories domain.CategoryRepository, logger *slog.Logger) *MerchantHandler {
+	return &MerchantHandler{
+		merchants:    merchants,
+		expenditures: expenditures,
+		categories:   categories,
+		logger:       logger,
+	}
+}
+
+func MerchantRouter(handler *MerchantHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/merchants" {
+			Methods{
+				http.MethodGet:  handler.GetAllMerchants,
+				http.MethodPost: handler.AddMerchant,
+			}.ServeHTTP(w, r)
+			return
+		}
+
+		_, sub, ok := splitPath(path, "/merchants/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch sub {
+		case "":
+			Methods{
+				http.MethodGet:    handler.GetMerchantByID,
+				http.MethodPut:    handler.UpdateMerchant,
+				http.MethodDelete: handler.DeleteMerchant,
+			}.ServeHTTP(w, r)
+		case "expenditures":
+			Methods{http.MethodGet: handler.GetMerchantExpenditures}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// checkCategory reports whether the merchant's default category exists; an empty default
+// category, or unavailable categories, always pass
+func (h *MerchantHandler) checkCategory(id uuid.UUID) (bool, error) {
+	if h.categories == nil || id == uuid.Nil {
+		return true, nil
+	}
+
+	_, err := h.categories.GetCategoryByID(id.String())
+	if err == domain.ErrCategoryNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
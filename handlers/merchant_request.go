@@ -0,0 +1,11 @@
+package handlers
+
+import (
+	"github.com/google/uuid"
+)
+
+type MerchantRequest struct {
+	Name              string    `json:"name"`
+	Aliases           []string  `json:"aliases"`
+	DefaultCategoryId uuid.UUID `json:"defaultCategoryId"` // Optional
+}
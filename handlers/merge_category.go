@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// MergeCategory handles POST /categories/{id}/merge-into/{targetId}, optionally as a dry run
+func (h *CategoryHandler) MergeCategory(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling merge category request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sourceStr, targetStr, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/categories/"), "/"), "/merge-into/")
+	source, err := uuid.Parse(sourceStr)
+	if err != nil {
+		h.logger.Warn("Invalid source category ID", "id", sourceStr)
+		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		return
+	}
+	target, err := uuid.Parse(targetStr)
+	if err != nil {
+		h.logger.Warn("Invalid target category ID", "id", targetStr)
+		http.Error(w, "Invalid target category ID", http.StatusBadRequest)
+		return
+	}
+
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	h.logger.Debug("Merging category", "source_id", source, "target_id", target, "dry_run", dryRun)
+
+	merge, err := h.categories.MergeCategory(source, target, dryRun)
+	if err != nil {
+		switch err {
+		case domain.ErrCategoryNotFound:
+			h.logger.Warn("Category not found for merge", "source_id", source, "target_id", target)
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case domain.ErrCategoryMergeIntoSubcategory:
+			h.logger.Warn("Invalid category merge", "source_id", source, "target_id", target)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			h.logger.Error("Failed to merge category", "source_id", source, "target_id", target, "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.logger.Info("Successfully merged category", "source_id", source, "target_id", target, "dry_run", dryRun, "expenditures", merge.Expenditures)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(merge)
+}
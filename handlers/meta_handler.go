@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"go-expense-tracker/meta"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// metaCacheControl lets clients keep the palette and icons for a day; they only change with a
+// new release
+const metaCacheControl = "public, max-age=86400"
+
+type MetaHandler struct {
+	logger *slog.Logger
+}
+
+func NewMetaHandler(logger *slog.Logger) *MetaHandler {
+	return &MetaHandler{
+		logger: logger,
+	}
+}
+
+func MetaRouter(handler *MetaHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		switch {
+		case path == "/meta/palette":
+			Methods{http.MethodGet: handler.GetPalette}.ServeHTTP(w, r)
+		case path == meta.IconsPath:
+			Methods{http.MethodGet: handler.GetIcons}.ServeHTTP(w, r)
+		case strings.HasPrefix(path, meta.IconsPath+"/"):
+			Methods{http.MethodGet: handler.GetIcon}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Methods routes the requests of one resource by method. It answers what every resource
+// supports the same way, so handlers don't have to: OPTIONS with the Allow header for clients
+// discovering the API, and Access-Control-Allow-Methods for CORS preflights, whose origin
+// CORSMiddleware allows; HEAD with the headers of GET, the server dropping the body; and
+// methods the resource doesn't support with 405 Method Not Allowed and the Allow header
+type Methods map[string]http.HandlerFunc
+
+// Allow returns the methods of the resource, for the Allow header
+func (m Methods) Allow() string {
+	allowed := []string{http.MethodOptions}
+	for method := range m {
+		allowed = append(allowed, method)
+	}
+	if _, ok := m[http.MethodGet]; ok && m[http.MethodHead] == nil {
+		allowed = append(allowed, http.MethodHead)
+	}
+	slices.Sort(allowed)
+	return strings.Join(allowed, ", ")
+}
+
+func (m Methods) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if handler, ok := m[r.Method]; ok {
+		handler(w, r)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodOptions:
+		w.Header().Set("Allow", m.Allow())
+		w.Header().Set("Access-Control-Allow-Methods", m.Allow())
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodHead && m[http.MethodGet] != nil:
+		// The server knows the request as HEAD and sends no body, whatever the handler writes
+		get := r.Clone(r.Context())
+		get.Method = http.MethodGet
+		m[http.MethodGet](w, get)
+	default:
+		w.Header().Set("Allow", m.Allow())
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// MoveEnvelopeMoney handles POST /envelopes/move, which moves money from one envelope to another.
+// The source envelope must hold at least the amount
+func (h *EnvelopeHandler) MoveEnvelopeMoney(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling move envelope money request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req EnvelopeMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	allocations, err := domain.NewEnvelopeMove(req.FromCategoryId, req.ToCategoryId, req.Amount, req.Note)
+	if err != nil {
+		h.logger.Warn("Invalid envelope move", "error", err, "from", req.FromCategoryId, "to", req.ToCategoryId)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	missing, err := h.checkCategories(req.FromCategoryId, req.ToCategoryId)
+	if err != nil {
+		h.logger.Error("Failed to check envelope categories", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if missing != uuid.Nil {
+		h.logger.Warn("Envelope category not found", "category_id", missing)
+		http.Error(w, domain.ErrCategoryNotFound.Error(), http.StatusBadRequest)
+		return
+	}
+
+	balances, err := envelopeBalances(h.envelopes, h.summaries, h.expenditures)
+	if err != nil {
+		h.logger.Error("Failed to compute envelope balances", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	available := 0.0
+	for _, balance := range balances {
+		if balance.CategoryId == req.FromCategoryId {
+			available = balance.Balance
+		}
+	}
+	if available < req.Amount {
+		h.logger.Warn("Envelope balance too low for move", "category_id", req.FromCategoryId, "balance", available, "amount", req.Amount)
+		http.Error(w, domain.ErrInsufficientEnvelopeBalance.Error(), http.StatusConflict)
+		return
+	}
+
+	if err := h.envelopes.AddEnvelopeAllocations(allocations); err != nil {
+		h.logger.Error("Failed to move envelope money", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully moved envelope money", "from", req.FromCategoryId, "to", req.ToCategoryId, "amount", req.Amount)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(allocations)
+}
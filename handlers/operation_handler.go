@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"go-expense-tracker/operations"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type OperationHandler struct {
+	recorder *operations.Recorder
+	logger   *slog.Logger
+}
+
+func NewOperationHandler(recorder *operations.Recorder, logger *slog.Logger) *OperationHandler {
+	return &OperationHandler{
+		recorder: recorder,
+		logger:   logger,
+	}
+}
+
+func OperationRouter(handler *OperationHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/operations" {
+			Methods{http.MethodGet: handler.GetAllOperations}.ServeHTTP(w, r)
+			return
+		}
+
+		_, sub, ok := splitPath(path, "/operations/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch sub {
+		case "":
+			Methods{http.MethodGet: handler.GetOperationByID}.ServeHTTP(w, r)
+		case "undo":
+			Methods{http.MethodPost: handler.UndoOperation}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
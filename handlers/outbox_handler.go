@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type OutboxHandler struct {
+	outbox domain.OutboxRepository
+	token  string
+	logger *slog.Logger
+}
+
+// NewOutboxHandler creates a new OutboxHandler; every request must carry token as bearer token
+func NewOutboxHandler(outbox domain.OutboxRepository, token string, logger *slog.Logger) *OutboxHandler {
+	return &OutboxHandler{
+		outbox: outbox,
+		token:  token,
+		logger: logger,
+	}
+}
+
+func OutboxRouter(handler *OutboxHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !handler.authorized(r) {
+			handler.logger.Warn("Outbox request without a valid token", "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+			http.Error(w, "Admin token required", http.StatusUnauthorized)
+			return
+		}
+
+		switch path := strings.TrimSuffix(r.URL.Path, "/"); path {
+		case "/admin/outbox/failed":
+			Methods{http.MethodGet: handler.GetFailedOutboxMessages}.ServeHTTP(w, r)
+		case "/admin/outbox/failed/redeliver":
+			Methods{http.MethodPost: handler.RedeliverFailedOutboxMessages}.ServeHTTP(w, r)
+		default:
+			if _, sub, ok := splitPath(path, "/admin/outbox/"); ok && sub == "redeliver" {
+				Methods{http.MethodPost: handler.RedeliverOutboxMessage}.ServeHTTP(w, r)
+				return
+			}
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func (h *OutboxHandler) authorized(r *http.Request) bool {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return found && subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) == 1
+}
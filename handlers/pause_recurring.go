@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"io"
+	"net/http"
+	"time"
+)
+
+// SkipNextRecurring handles POST /recurring/{id}/skip-next, which moves past the next occurrence
+// without generating it
+func (h *RecurringHandler) SkipNextRecurring(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling skip next recurring expenditure request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/recurring/")
+	if !ok {
+		return
+	}
+	recurring, err := h.generator.Change(id, func(recurring *domain.RecurringExpenditure) error {
+		return recurring.SkipNext()
+	})
+	if err != nil {
+		h.logger.Warn("Failed to skip recurring expenditure occurrence", "id", id, "error", err)
+		http.Error(w, err.Error(), recurringStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully skipped recurring expenditure occurrence", "id", id, "next_date", recurring.NextDate)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recurring)
+}
+
+// PauseRecurring handles POST /recurring/{id}/pause, which stops generating occurrences until
+// resumed or, with {"until": ...}, until that day. Occurrences in the pause are skipped
+func (h *RecurringHandler) PauseRecurring(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling pause recurring expenditure request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// The body is optional
+	var req PauseRecurringRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	id, ok := pathID(w, r, "/recurring/")
+	if !ok {
+		return
+	}
+	recurring, err := h.generator.Change(id, func(recurring *domain.RecurringExpenditure) error {
+		return recurring.Pause(req.Until, time.Now())
+	})
+	if err != nil {
+		h.logger.Warn("Failed to pause recurring expenditure", "id", id, "error", err)
+		http.Error(w, err.Error(), recurringStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully paused recurring expenditure", "id", id, "until", recurring.PausedUntil)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recurring)
+}
+
+// ResumeRecurring handles POST /recurring/{id}/resume, which generates occurrences again from
+// today on
+func (h *RecurringHandler) ResumeRecurring(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling resume recurring expenditure request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/recurring/")
+	if !ok {
+		return
+	}
+	recurring, err := h.generator.Change(id, func(recurring *domain.RecurringExpenditure) error {
+		recurring.Resume(time.Now())
+		return nil
+	})
+	if err != nil {
+		h.logger.Warn("Failed to resume recurring expenditure", "id", id, "error", err)
+		http.Error(w, err.Error(), recurringStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully resumed recurring expenditure", "id", id, "next_date", recurring.NextDate)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recurring)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"net/http"
+	"time"
+)
+
+// SetPinned handles PUT and DELETE /expenditures/{id}/pin, which star and unstar an expenditure
+func (h *ExpenditureHandler) SetPinned(w http.ResponseWriter, r *http.Request, pinned bool) {
+	h.logger.Info("Handling pin expenditure request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr, "pinned", pinned)
+
+	if h.pins == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	expenditureID, ok := pathUUID(w, r, "/expenditures/")
+	if !ok {
+		return
+	}
+	id := expenditureID.String()
+
+	var err error
+	if pinned {
+		if _, err := h.service.GetExpenditureByID(id); err != nil {
+			if err == domain.ErrExpenditureNotFound {
+				h.logger.Warn("Expenditure not found for pinning", "id", id)
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			h.logger.Error("Failed to get expenditure for pinning", "id", id, "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		err = h.pins.PinExpenditure(expenditureID)
+	} else {
+		err = h.pins.UnpinExpenditure(expenditureID)
+	}
+	if err != nil {
+		h.logger.Error("Failed to pin expenditure", "id", id, "pinned", pinned, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully pinned expenditure", "id", id, "pinned", pinned)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getPinnedExpenditures lists the pinned expenditures within [from, to), most recently pinned
+// first. Pins of expenditures deleted since are skipped
+func (h *ExpenditureHandler) getPinnedExpenditures(w http.ResponseWriter, r *http.Request, from, to time.Time) {
+	if h.pins == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	ids, err := h.pins.GetPinnedExpenditureIDs()
+	if err != nil {
+		h.logger.Error("Failed to get pinned expenditures", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	stream := newJSONStream(w, r)
+	for _, id := range ids {
+		expenditure, err := h.service.GetExpenditureByID(id.String())
+		if err == domain.ErrExpenditureNotFound {
+			continue
+		}
+		if err == nil && ((!from.IsZero() && expenditure.Date.Before(from)) || (!to.IsZero() && !expenditure.Date.Before(to))) {
+			continue
+		}
+		if err == nil {
+			err = stream.Write(expenditure)
+		}
+		if err != nil {
+			h.logger.Error("Failed to get pinned expenditure", "id", id, "error", err)
+			if !stream.Started() {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+	}
+	if err := stream.Close(); err != nil {
+		h.logger.Error("Failed to write pinned expenditures", "error", err)
+		return
+	}
+
+	h.logger.Info("Successfully retrieved pinned expenditures", "count", stream.count)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-expense-tracker/domain"
+	"io"
+	"net/http"
+)
+
+// PublishDraft turns a draft into an expenditure with the same ID. The optional body fills in
+// what the draft is missing, in the shape of a create request; only the fields it contains
+// replace those of the draft. The result is validated like any new expenditure
+func (h *ExpenditureHandler) PublishDraft(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling publish draft request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.drafts == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	id, ok := pathID(w, r, "/expenditures/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Publishing draft", "id", id)
+
+	draft, err := h.drafts.GetDraftByID(id)
+	if err != nil {
+		if err == domain.ErrDraftNotFound {
+			h.logger.Warn("Draft not found for publishing", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get draft", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Decoding over the draft's fields keeps those the body leaves out
+	req := draftRequest(draft)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	input := req.input(r.Context())
+	input.ID = draft.ID
+	result, err := h.expenditures.Create(input)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+	expenditure := result.Expenditure
+
+	// The expenditure exists now, so a draft left behind is only a stale duplicate
+	if err := h.drafts.DeleteDraft(id); err != nil {
+		h.logger.Error("Failed to delete published draft", "error", err, "id", id)
+	}
+
+	h.logger.Info("Successfully published draft", "id", expenditure.ID, "description", expenditure.Description)
+	flagLimit(w, result)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(expenditure)
+}
+
+// draftRequest turns a draft back into the create request it was saved from
+func draftRequest(draft *domain.Draft) ExpenditureRequest {
+	req := ExpenditureRequest{
+		Description: draft.Description,
+		Amount:      draft.Amount,
+		Date:        draft.Date,
+		CategoryId:  draft.CategoryId,
+		Tags:        draft.Tags,
+		Quantity:    draft.Quantity,
+		UnitPrice:   draft.UnitPrice,
+		Unit:        draft.Unit,
+		TaxRate:     draft.TaxRate,
+		TaxAmount:   draft.TaxAmount,
+		MerchantId:  draft.MerchantId,
+	}
+	if draft.Location != nil {
+		req.Location = &LocationRequest{
+			Latitude:  draft.Location.Latitude,
+			Longitude: draft.Location.Longitude,
+			PlaceName: draft.Location.PlaceName,
+			City:      draft.Location.City,
+		}
+	}
+	return req
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/jobs"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// QueryLimits protect the service from accidental full-history scans; zero values disable a limit
+type QueryLimits struct {
+	MaxExportSpan time.Duration // Longest date range of an unpaginated export, open ranges count as unbounded
+	AsyncRows     int           // Listings and reports reading more expenditures than this run as background jobs
+}
+
+// QueryGuard applies QueryLimits to listings and reports. A nil QueryGuard applies no limits
+type QueryGuard struct {
+	limits QueryLimits
+	jobs   *jobs.Runner
+	logger *slog.Logger
+}
+
+// NewQueryGuard creates a new QueryGuard starting large reports on runner
+func NewQueryGuard(limits QueryLimits, runner *jobs.Runner, logger *slog.Logger) *QueryGuard {
+	return &QueryGuard{
+		limits: limits,
+		jobs:   runner,
+		logger: logger,
+	}
+}
+
+// checkSpan rejects date ranges longer than the export limit
+func (g *QueryGuard) checkSpan(from, to time.Time) error {
+	if g == nil || g.limits.MaxExportSpan <= 0 {
+		return nil
+	}
+
+	if from.IsZero() || to.IsZero() || to.Sub(from) > g.limits.MaxExportSpan {
+		return fmt.Errorf("date range too long, from and to may span at most %d days", int(g.limits.MaxExportSpan.Hours()/24))
+	}
+	return nil
+}
+
+// deferLarge runs render as a background job and replies 202 Accepted pointing at the job when
+// more expenditures than the row limit are dated within [from, to), or when the client sent
+// Prefer: respond-async. It reports whether the request was answered that way
+func (g *QueryGuard) deferLarge(w http.ResponseWriter, r *http.Request, expenditures domain.ExpenditureRepository, kind string, from, to time.Time, render func(w http.ResponseWriter) error) (bool, error) {
+	if g == nil || g.jobs == nil {
+		return false, nil
+	}
+
+	async := prefers(r, "respond-async")
+	if !async && g.limits.AsyncRows <= 0 {
+		return false, nil
+	}
+
+	rows, err := domain.CountExpenditures(expenditures, from, to)
+	if err != nil {
+		return false, err
+	}
+	if !async && rows <= g.limits.AsyncRows {
+		return false, nil
+	}
+
+	job := g.jobs.Start(kind, rows, render)
+	g.logger.Info("Deferred large request to a background job", "id", job.ID, "kind", kind, "rows", rows, "limit", g.limits.AsyncRows)
+
+	if async {
+		w.Header().Set("Preference-Applied", "respond-async")
+	}
+	w.Header().Set("Location", "/jobs/"+job.ID.String())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(newJobResponse(job))
+	return true, nil
+}
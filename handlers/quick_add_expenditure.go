@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/quickentry"
+	"net/http"
+	"time"
+)
+
+type QuickExpenditureResponse struct {
+	Draft       ExpenditureRequest  `json:"draft"`                 // Parsed fields, ready to be submitted to POST /expenditures
+	Expenditure *domain.Expenditure `json:"expenditure,omitempty"` // Created expenditure, only with ?commit=true
+}
+
+func (h *ExpenditureHandler) QuickAddExpenditure(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling quick add expenditure request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req QuickExpenditureRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Debug("Decoded quick add request", "text", req.Text)
+
+	entry, err := quickentry.Parse(req.Text, time.Now())
+	if err != nil {
+		h.logger.Warn("Failed to parse quick add text", "error", err, "text", req.Text)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	categoryID, tags, err := quickentry.Categorize(h.categories, h.rules, entry, h.uncategorized)
+	if err != nil {
+		h.logger.Warn("Failed to detect category", "error", err, "hashtags", entry.Hashtags)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := QuickExpenditureResponse{
+		Draft: ExpenditureRequest{
+			Description: entry.Description,
+			Amount:      entry.Amount,
+			Date:        entry.Date,
+			CategoryId:  categoryID,
+			Tags:        domain.NormalizeTags(tags),
+		},
+	}
+
+	if r.URL.Query().Get("commit") != "true" {
+		h.logger.Info("Successfully parsed quick add draft", "description", entry.Description, "amount", entry.Amount, "date", entry.Date)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	result, err := h.expenditures.Create(resp.Draft.input(r.Context()))
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+	expenditure := result.Expenditure
+	resp.Expenditure = expenditure
+	flagLimit(w, result)
+
+	h.logger.Info("Successfully added quick expenditure", "id", expenditure.ID, "description", expenditure.Description, "date", expenditure.Date)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
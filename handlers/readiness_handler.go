@@ -0,0 +1,16 @@
+package handlers
+
+import "log/slog"
+
+type ReadinessHandler struct {
+	ready  func() bool
+	logger *slog.Logger
+}
+
+// NewReadinessHandler creates a ReadinessHandler reporting what ready tells
+func NewReadinessHandler(ready func() bool, logger *slog.Logger) *ReadinessHandler {
+	return &ReadinessHandler{
+		ready:  ready,
+		logger: logger,
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ReconcileAccount handles POST /accounts/{id}/reconcile, which matches the account's
+// expenditures against a statement and reports how far its balance is off
+func (h *AccountHandler) ReconcileAccount(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling reconcile account request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/accounts/")
+	if !ok {
+		return
+	}
+
+	var req ReconcileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	reconciliation, err := h.service.Reconcile(id, req.EndDate, req.Balance)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully reconciled account", "id", id, "reconciled", reconciliation.Reconciled, "discrepancy", reconciliation.Discrepancy)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reconciliation)
+}
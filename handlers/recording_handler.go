@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"go-expense-tracker/recording"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type RecordingHandler struct {
+	recorder *recording.Recorder
+	token    string
+	logger   *slog.Logger
+}
+
+// NewRecordingHandler creates a new RecordingHandler; every request must carry token as bearer
+// token
+func NewRecordingHandler(recorder *recording.Recorder, token string, logger *slog.Logger) *RecordingHandler {
+	return &RecordingHandler{
+		recorder: recorder,
+		token:    token,
+		logger:   logger,
+	}
+}
+
+func RecordingRouter(handler *RecordingHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(token), []byte(handler.token)) != 1 {
+			handler.logger.Warn("Recording request without a valid token", "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+			http.Error(w, "Admin token required", http.StatusUnauthorized)
+			return
+		}
+
+		Methods{http.MethodGet: handler.GetRecordings}.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/recurring"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type RecurringHandler struct {
+	recurring  domain.RecurringRepository
+	generator  *recurring.Generator
+	categories domain.CategoryRepository
+	logger     *slog.Logger
+}
+
+// NewRecurringHandler creates a new RecurringHandler; categories may be nil when the storage has
+// no category support. Changes to existing recurring expenditures go through the generator so
+// they do not race with a scheduled run
+func NewRecurringHandler(recurring domain.RecurringRepository, generator *recurring.Generator, categories domain.CategoryRepository, logger *slog.Logger) *RecurringHandler {
+	return &RecurringHandler{
+		recurring:  recurring,
+		generator:  generator,
+		categories: categories,
+		logger:     logger,
+	}
+}
+
+func RecurringRouter(handler *RecurringHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/recurring" {
+			Methods{
+				http.MethodGet:  handler.GetAllRecurring,
+				http.MethodPost: handler.AddRecurring,
+			}.ServeHTTP(w, r)
+			return
+		}
+
+		_, sub, ok := splitPath(path, "/recurring/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch sub {
+		case "":
+			Methods{
+				http.MethodGet:    handler.GetRecurringByID,
+				http.MethodPut:    handler.UpdateRecurring,
+				http.MethodDelete: handler.DeleteRecurring,
+			}.ServeHTTP(w, r)
+		case "upcoming":
+			Methods{http.MethodGet: handler.GetUpcomingRecurring}.ServeHTTP(w, r)
+		case "skip-next":
+			Methods{http.MethodPost: handler.SkipNextRecurring}.ServeHTTP(w, r)
+		case "pause":
+			Methods{http.MethodPost: handler.PauseRecurring}.ServeHTTP(w, r)
+		case "resume":
+			Methods{http.MethodPost: handler.ResumeRecurring}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// checkCategory reports whether the category exists; recurring expenditures without a category
+// go to the uncategorized one and categories are not checked when they are unavailable
+func (h *RecurringHandler) checkCategory(id uuid.UUID) (bool, error) {
+	if h.categories == nil || id == uuid.Nil {
+		return true, nil
+	}
+
+	_, err := h.categories.GetCategoryByID(id.String())
+	if err == domain.ErrCategoryNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// recurringStatus returns the status code of an error changing a recurring expenditure
+func recurringStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrRecurringNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrRecurringDescriptionEmpty), errors.Is(err, domain.ErrInvalidRecurringAmount),
+		errors.Is(err, domain.ErrInvalidRecurringFrequency), errors.Is(err, domain.ErrRecurringEndBeforeStart),
+		errors.Is(err, domain.ErrRecurringFinished), errors.Is(err, domain.ErrRecurringPauseInPast),
+		errors.Is(err, domain.ErrCategoryNotFound):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
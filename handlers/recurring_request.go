@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RecurringRequest struct {
+	Description string                    `json:"description"`
+	Amount      float64                   `json:"amount"`
+	CategoryId  uuid.UUID                 `json:"categoryId"` // Optional, the uncategorized category without it
+	Tags        []string                  `json:"tags"`
+	Frequency   domain.RecurringFrequency `json:"frequency"` // Defaults to monthly
+	StartDate   time.Time                 `json:"startDate"` // Optional, defaults to today on creation
+	EndDate     *time.Time                `json:"endDate"`   // Optional last day
+}
+
+type PauseRecurringRequest struct {
+	Until *time.Time `json:"until"` // Optional day to resume on, paused until resumed without it
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-expense-tracker/domain"
+	"net/http"
+	"strconv"
+)
+
+// RedeliverOutboxResponse counts the messages queued for delivery again
+type RedeliverOutboxResponse struct {
+	Redelivered int `json:"redelivered"`
+}
+
+// RedeliverOutboxMessage queues an outbox message the relay gave up on for delivery again. The
+// message keeps its ID, so receivers that handled it after all skip it
+func (h *OutboxHandler) RedeliverOutboxMessage(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling redeliver outbox message request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	value, _, ok := splitPath(r.URL.Path, "/admin/outbox/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID, expected the number of an outbox message", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.outbox.RedeliverOutboxMessage(id); err != nil {
+		h.logger.Warn("Failed to redeliver outbox message", "error", err, "id", id)
+		switch {
+		case errors.Is(err, domain.ErrOutboxMessageNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, domain.ErrOutboxMessageNotFailed):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(RedeliverOutboxResponse{Redelivered: 1})
+	h.logger.Warn("Outbox message queued for redelivery", "id", id, "remote_addr", r.RemoteAddr)
+}
+
+// RedeliverFailedOutboxMessages queues every outbox message the relay gave up on for delivery
+// again, e.g. once the webhook they failed on is back
+func (h *OutboxHandler) RedeliverFailedOutboxMessages(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling redeliver failed outbox messages request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Redelivered messages leave the failed ones, so the first page is read until it is empty
+	redelivered := 0
+	for {
+		messages, err := h.outbox.GetFailedOutboxMessages(maxPageSize)
+		if err != nil {
+			h.logger.Error("Failed to get failed outbox messages", "error", err, "redelivered", redelivered)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if len(messages) == 0 {
+			break
+		}
+
+		for _, message := range messages {
+			err := h.outbox.RedeliverOutboxMessage(message.ID)
+			// Another admin may have redelivered it, or the relay delivered it, in between
+			if errors.Is(err, domain.ErrOutboxMessageNotFound) || errors.Is(err, domain.ErrOutboxMessageNotFailed) {
+				continue
+			}
+			if err != nil {
+				h.logger.Error("Failed to redeliver outbox message", "error", err, "id", message.ID, "redelivered", redelivered)
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			redelivered++
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(RedeliverOutboxResponse{Redelivered: redelivered})
+	h.logger.Warn("Failed outbox messages queued for redelivery", "count", redelivered, "remote_addr", r.RemoteAddr)
+}
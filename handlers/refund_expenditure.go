@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/app"
+	"net/http"
+)
+
+func (h *ExpenditureHandler) RefundExpenditure(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling refund expenditure request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/expenditures/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Refunding expenditure", "id", id)
+
+	var req RefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	refund, err := h.expenditures.Refund(id, app.RefundInput{Amount: req.Amount, Date: req.Date, Description: req.Description})
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully refunded expenditure", "id", refund.ID, "original_id", id, "amount", -refund.Amount)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(refund)
+}
+
+func (h *ExpenditureHandler) GetRefunds(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling get refunds request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodGet {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/expenditures/")
+	if !ok {
+		return
+	}
+
+	refunds, err := h.expenditures.Refunds(id)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully retrieved refunds", "id", id, "count", len(refunds))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(refunds)
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *ImportHandler) RejectStagedExpenditure(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling reject staged expenditure request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/imports/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Rejecting staged expenditure", "id", id)
+
+	staged, err := h.imports.GetStagedExpenditureByID(id)
+	if err != nil {
+		if err == domain.ErrStagedExpenditureNotFound {
+			h.logger.Warn("Staged expenditure not found for rejection", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get staged expenditure", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = staged.Reject()
+	if err != nil {
+		h.logger.Warn("Staged expenditure already reviewed", "id", id, "status", staged.Status)
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	err = h.imports.UpdateStagedExpenditure(staged)
+	if err != nil {
+		h.logger.Error("Failed to update staged expenditure", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully rejected staged expenditure", "id", id)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(staged)
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+// ReorderCategories handles PATCH /categories/reorder; categories missing from the list keep
+// their relative order after the listed ones
+func (h *CategoryHandler) ReorderCategories(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling reorder categories request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPatch {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req CategoryOrderRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode reorder request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.categories.ReorderCategories(req.IDs)
+	if err != nil {
+		switch err {
+		case domain.ErrCategoryNotFound, domain.ErrDuplicateCategoryInOrder:
+			h.logger.Warn("Invalid category order", "error", err, "count", len(req.IDs))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			h.logger.Error("Failed to reorder categories", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	categories, err := h.categories.GetAllCategories()
+	if err != nil {
+		h.logger.Error("Failed to get all categories", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	domain.SortCategories(categories)
+
+	h.logger.Info("Successfully reordered categories", "count", len(req.IDs))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(categories)
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/reports"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var errInvalidDateRange = errors.New("invalid date range, use from and to as YYYY-MM-DD")
+var errMonthWithDateRange = errors.New("use either month or from and to")
+var errInvalidPendingOption = errors.New("invalid pending option, use include or exclude")
+var errInvalidAmountsOption = errors.New("invalid amounts option, use home or original")
+var errInvalidFormatOption = errors.New("invalid format option, use json or chart")
+
+type ReportHandler struct {
+	service    domain.ExpenditureRepository
+	categories domain.CategoryRepository
+	merchants  domain.MerchantRepository
+	households domain.HouseholdRepository
+	summaries  domain.SpendingSummaryRepository
+	guard      *QueryGuard
+	calendar   domain.FiscalCalendar
+	snapshots  domain.ReportSnapshotRepository
+	snapshot   *reports.Snapshotter
+	logger     *slog.Logger
+}
+
+// NewReportHandler creates a new ReportHandler; categories, merchants, households, summaries and
+// snapshots may be nil when the storage has no support for them, and guard when reports are not
+// limited. The calendar decides which days a report for a month covers
+func NewReportHandler(service domain.ExpenditureRepository, categories domain.CategoryRepository, merchants domain.MerchantRepository, households domain.HouseholdRepository, summaries domain.SpendingSummaryRepository, snapshots domain.ReportSnapshotRepository, guard *QueryGuard, calendar domain.FiscalCalendar, logger *slog.Logger) *ReportHandler {
+	var snapshot *reports.Snapshotter
+	if snapshots != nil {
+		snapshot = reports.NewSnapshotter(snapshots, service, categories, summaries, calendar, 0, logger)
+	}
+
+	return &ReportHandler{
+		service:    service,
+		categories: categories,
+		merchants:  merchants,
+		households: households,
+		summaries:  summaries,
+		guard:      guard,
+		calendar:   calendar,
+		snapshots:  snapshots,
+		snapshot:   snapshot,
+		logger:     logger,
+	}
+}
+
+func ReportRouter(handler *ReportHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+		if path == "/reports/snapshots" || strings.HasPrefix(path, "/reports/snapshots/") {
+			if handler.snapshots == nil {
+				http.Error(w, "Report snapshots are not supported by the storage", http.StatusNotFound)
+				return
+			}
+
+			if path == "/reports/snapshots" {
+				Methods{
+					http.MethodGet:  handler.GetAllReportSnapshots,
+					http.MethodPost: handler.TakeReportSnapshot,
+				}.ServeHTTP(w, r)
+				return
+			}
+
+			_, sub, _ := splitPath(path, "/reports/snapshots/")
+			switch sub {
+			case "":
+				Methods{http.MethodGet: handler.GetReportSnapshot}.ServeHTTP(w, r)
+			case "diff":
+				Methods{http.MethodGet: handler.DiffReportSnapshot}.ServeHTTP(w, r)
+			default:
+				http.NotFound(w, r)
+			}
+			return
+		}
+
+		switch path {
+		case "/reports/units":
+			Methods{http.MethodGet: handler.GetUnitReport}.ServeHTTP(w, r)
+		case "/reports/tax":
+			Methods{http.MethodGet: handler.GetTaxReport}.ServeHTTP(w, r)
+		case "/reports/merchants":
+			Methods{http.MethodGet: handler.GetMerchantReport}.ServeHTTP(w, r)
+		case "/reports/price-trends":
+			Methods{http.MethodGet: handler.GetPriceTrendReport}.ServeHTTP(w, r)
+		case "/reports/by-member":
+			Methods{http.MethodGet: handler.GetMemberReport}.ServeHTTP(w, r)
+		case "/reports/by-location":
+			Methods{http.MethodGet: handler.GetLocationReport}.ServeHTTP(w, r)
+		case "/reports/categories":
+			Methods{http.MethodGet: handler.GetCategoryReport}.ServeHTTP(w, r)
+		case "/reports/currencies":
+			Methods{http.MethodGet: handler.GetCurrencyReport}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// parseDateRange reads the optional from and to query parameters; to is inclusive and is
+// returned as the start of the following day
+func parseDateRange(r *http.Request) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+
+	if s := r.URL.Query().Get("from"); s != "" {
+		from, err = time.Parse("2006-01-02", s)
+		if err != nil {
+			return time.Time{}, time.Time{}, errInvalidDateRange
+		}
+	}
+
+	if s := r.URL.Query().Get("to"); s != "" {
+		to, err = time.Parse("2006-01-02", s)
+		if err != nil {
+			return time.Time{}, time.Time{}, errInvalidDateRange
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+
+	if !from.IsZero() && !to.IsZero() && !to.After(from) {
+		return time.Time{}, time.Time{}, errInvalidDateRange
+	}
+
+	return from, to, nil
+}
+
+// parsePending reads the optional pending query parameter and reports whether pending
+// expenditures are included in a report, which they are by default
+func parsePending(r *http.Request) (bool, error) {
+	switch r.URL.Query().Get("pending") {
+	case "", "include":
+		return true, nil
+	case "exclude":
+		return false, nil
+	default:
+		return false, errInvalidPendingOption
+	}
+}
+
+// parseAmounts reads the optional amounts query parameter and reports whether a report shows
+// foreign expenditures in their original currencies instead of the home amounts settled
+func parseAmounts(r *http.Request) (bool, error) {
+	switch r.URL.Query().Get("amounts") {
+	case "", "home":
+		return false, nil
+	case "original":
+		return true, nil
+	default:
+		return false, errInvalidAmountsOption
+	}
+}
+
+// parseChart reads the optional format query parameter and reports whether a report is shaped for
+// chart libraries instead of listed as JSON
+func parseChart(r *http.Request) (bool, error) {
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		return false, nil
+	case "chart":
+		return true, nil
+	default:
+		return false, errInvalidFormatOption
+	}
+}
+
+// parsePeriod reads the optional month query parameter, a fiscal month as YYYY-MM, and falls back
+// to the from and to parameters without it
+func (h *ReportHandler) parsePeriod(r *http.Request) (time.Time, time.Time, error) {
+	query := r.URL.Query()
+	month := query.Get("month")
+	if month == "" {
+		return parseDateRange(r)
+	}
+	if query.Has("from") || query.Has("to") {
+		return time.Time{}, time.Time{}, errMonthWithDateRange
+	}
+	return h.calendar.PeriodOf(month, time.UTC)
+}
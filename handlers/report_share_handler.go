@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"go-expense-tracker/reports"
+	"go-expense-tracker/signedurl"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// ReportShareHandler hands out signed links to the monthly report, which can be opened without
+// any other credentials until they expire, e.g. by a partner or an accountant
+type ReportShareHandler struct {
+	reports    *reports.Snapshotter
+	links      *signedurl.Signer
+	formatting ExportFormatting
+	logger     *slog.Logger
+}
+
+// NewReportShareHandler creates a new ReportShareHandler signing links with links; categories
+// and summaries may be nil when the storage has no support for them. Shared HTML reports are
+// written in formatting
+func NewReportShareHandler(service domain.ExpenditureRepository, categories domain.CategoryRepository, summaries domain.SpendingSummaryRepository, calendar domain.FiscalCalendar, links *signedurl.Signer, formatting ExportFormatting, logger *slog.Logger) *ReportShareHandler {
+	return &ReportShareHandler{
+		reports:    reports.NewSnapshotter(nil, service, categories, summaries, calendar, 0, logger),
+		links:      links,
+		formatting: formatting,
+		logger:     logger,
+	}
+}
+
+func ReportShareRouter(handler *ReportShareHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/reports/share" {
+			Methods{http.MethodPost: handler.ShareReport}.ServeHTTP(w, r)
+			return
+		}
+
+		_, sub, ok := splitPath(path, "/reports/shared/")
+		if !ok || sub != "" {
+			http.NotFound(w, r)
+			return
+		}
+		Methods{http.MethodGet: handler.GetSharedReport}.ServeHTTP(w, r)
+	})
+}
+
+func sharedReportPath(month string) string {
+	return "/reports/shared/" + month
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+)
+
+var errInvalidShareDays = errors.New("valid_days must be between 1 and 30")
+
+// ReportShareRequest asks for a link to the report of a fiscal month
+type ReportShareRequest struct {
+	Month     string `json:"month"`      // Fiscal month, YYYY-MM
+	ValidDays int    `json:"valid_days"` // Days until the link expires, 7 by default and at most 30
+}
+
+// ReportShareResponse is a signed link to a shared report
+type ReportShareResponse struct {
+	Month     string    `json:"month"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,5 @@
+package handlers
+
+type ReportSnapshotRequest struct {
+	Month string `json:"month"` // Fiscal month, YYYY-MM
+}
@@ -0,0 +1,60 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fuzzedRequests are the endpoints whose JSON bodies are fuzzed, picked by the first input
+var fuzzedRequests = []struct {
+	method string
+	path   string
+}{
+	{http.MethodPost, "/expenditures"},
+	{http.MethodPut, "/expenditures/" + lunchID.String()},
+	{http.MethodPost, "/expenditures/bulk"},
+	{http.MethodPost, "/expenditures/categorize"},
+	{http.MethodPost, "/expenditures/quick"},
+	{http.MethodPost, "/categories"},
+	{http.MethodPost, "/budgets"},
+	{http.MethodPost, "/goals"},
+	{http.MethodPost, "/merchants"},
+	{http.MethodPost, "/accounts"},
+}
+
+// FuzzJSONRequests sends untrusted bodies to the decoders of the API: whatever a client sends,
+// the server must answer without panicking and without blaming itself with a 5xx status
+func FuzzJSONRequests(f *testing.F) {
+	seeds := []string{
+		`{"description": "Lunch", "amount": 12.5, "date": "2024-03-05T10:00:00Z", "categoryId": "` + groceriesID.String() + `", "tags": ["work"]}`,
+		`{"description": "Fuel", "quantity": 40, "unitPrice": 1.9, "unit": "l", "date": "2024-03-05T10:00:00Z", "categoryId": "` + transportID.String() + `", "location": {"latitude": 52.5, "longitude": 13.4}}`,
+		`[{"description": "Coffee", "amount": 3.5, "date": "2024-03-05T10:00:00Z", "categoryId": "` + groceriesID.String() + `"}]`,
+		`{"ids": ["` + lunchID.String() + `"], "categoryId": "` + transportID.String() + `"}`,
+		`{"text": "lunch 12.50 yesterday #groceries"}`,
+		`{"name": "Books", "color": "#F28E2B"}`,
+		`{"name": "Food", "amount": 400, "period": "monthly", "categoryId": "` + groceriesID.String() + `", "startDate": "2024-03-01T00:00:00Z"}`,
+		`{"name": "Save", "targetAmount": 100, "startDate": "2024-03-01T00:00:00Z", "deadline": "2024-09-01T00:00:00Z"}`,
+		`{"name": "Corner Shop", "aliases": ["CORNER"]}`,
+		`{"name": "Savings", "openingBalance": 10}`,
+		`{"amount": 1e308, "date": "0001-01-01T00:00:00Z"}`,
+		`null`,
+		``,
+	}
+	for i, seed := range seeds {
+		f.Add(uint8(i), seed)
+	}
+
+	f.Fuzz(func(t *testing.T, endpoint uint8, body string) {
+		target := fuzzedRequests[int(endpoint)%len(fuzzedRequests)]
+		req := httptest.NewRequest(target.method, target.path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		newContractAPI(t).ServeHTTP(rec, req)
+
+		if rec.Code >= http.StatusInternalServerError {
+			t.Errorf("%s %s with %q = %d %q, want a status below 500", target.method, target.path, body, rec.Code, rec.Body.String())
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// splitPath splits a path below prefix, such as "/expenditures/", into the ID of a resource and
+// the sub-resource after it: "/expenditures/{id}/refunds" gives the ID and "refunds",
+// "/expenditures/{id}" the ID alone. A trailing slash is ignored. ok is false when the path is
+// not below prefix, has no ID, or nests deeper than one sub-resource, which routers answer with
+// 404 Not Found
+func splitPath(path, prefix string) (id, sub string, ok bool) {
+	rest, found := strings.CutPrefix(path, prefix)
+	if !found {
+		return "", "", false
+	}
+	id, sub, _ = strings.Cut(strings.TrimSuffix(rest, "/"), "/")
+	if id == "" || strings.Contains(sub, "/") {
+		return "", "", false
+	}
+	return id, sub, true
+}
+
+// pathUUID returns the ID of the resource a request is about, from its path below prefix. A
+// malformed ID is answered with 400 Bad Request and a path without one with 404 Not Found, and
+// false is returned
+func pathUUID(w http.ResponseWriter, r *http.Request, prefix string) (uuid.UUID, bool) {
+	value, _, ok := splitPath(r.URL.Path, prefix)
+	if !ok {
+		http.NotFound(w, r)
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		http.Error(w, "Invalid ID, expected a UUID such as 123e4567-e89b-12d3-a456-426614174000", http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// pathID is pathUUID for storages looking resources up by the ID as a string, which it returns
+// in canonical form, so every storage sees the same ID whatever its case in the path
+func pathID(w http.ResponseWriter, r *http.Request, prefix string) (string, bool) {
+	id, ok := pathUUID(w, r, prefix)
+	if !ok {
+		return "", false
+	}
+	return id.String(), true
+}
+
+// nestedPathIDs returns the IDs of a resource nested below another, such as
+// /households/{id}/invitations/{invitationId} for prefix "/households/" and sub "invitations",
+// answering malformed and missing IDs as pathUUID does
+func nestedPathIDs(w http.ResponseWriter, r *http.Request, prefix, sub string) (parentID, id string, ok bool) {
+	parent, _, found := strings.Cut(strings.TrimPrefix(r.URL.Path, prefix), "/"+sub+"/")
+	if !found || !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return "", "", false
+	}
+	if id, ok = pathID(w, r, prefix+parent+"/"+sub+"/"); !ok {
+		return "", "", false
+	}
+	parentUUID, err := uuid.Parse(parent)
+	if err != nil {
+		http.Error(w, "Invalid ID, expected a UUID such as 123e4567-e89b-12d3-a456-426614174000", http.StatusBadRequest)
+		return "", "", false
+	}
+	return parentUUID.String(), id, true
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"go-expense-tracker/retention"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type RetentionHandler struct {
+	purger *retention.Purger
+	purges domain.RetentionRepository
+	logger *slog.Logger
+}
+
+func NewRetentionHandler(purger *retention.Purger, purges domain.RetentionRepository, logger *slog.Logger) *RetentionHandler {
+	return &RetentionHandler{
+		purger: purger,
+		purges: purges,
+		logger: logger,
+	}
+}
+
+func RetentionRouter(handler *RetentionHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimSuffix(r.URL.Path, "/") {
+		case "/admin/retention/preview":
+			Methods{http.MethodGet: handler.GetRetentionPreview}.ServeHTTP(w, r)
+		case "/admin/retention/purges":
+			Methods{http.MethodGet: handler.GetAllRetentionPurges}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// RetentionPreviewResponse is what the next retention run would remove under the rules
+type RetentionPreviewResponse struct {
+	Rules  []domain.RetentionRule   `json:"rules"`
+	Purges []*domain.RetentionPurge `json:"purges"` // One per rule with anything to remove
+}
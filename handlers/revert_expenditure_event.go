@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+	"strconv"
+)
+
+// RevertExpenditureEvent undoes an event by restoring the expenditure to its state before it:
+// a created expenditure is deleted, an amended one restored and a deleted one added back. Only
+// the latest event of an expenditure can be reverted, and archival cannot be
+func (h *EventHandler) RevertExpenditureEvent(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling revert expenditure event request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	value, _, _ := splitPath(r.URL.Path, "/events/")
+	seq, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seq < 1 {
+		h.logger.Warn("Invalid event sequence number", "seq", value)
+		http.Error(w, domain.ErrExpenditureEventNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	events, err := h.events.GetExpenditureEvents(seq-1, 1)
+	if err != nil {
+		h.logger.Error("Failed to get expenditure event", "seq", seq, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(events) == 0 || events[0].Seq != seq {
+		h.logger.Warn("Expenditure event not found", "seq", seq)
+		http.Error(w, domain.ErrExpenditureEventNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	event := events[0]
+
+	if event.Kind == domain.ExpenditureArchived {
+		h.logger.Warn("Archival cannot be reverted", "seq", seq)
+		http.Error(w, "archived expenditures cannot be restored", http.StatusConflict)
+		return
+	}
+
+	history, err := h.events.GetExpenditureHistory(event.ExpenditureID)
+	if err != nil {
+		h.logger.Error("Failed to get expenditure history", "id", event.ExpenditureID, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if history[len(history)-1].Seq != seq {
+		h.logger.Warn("Expenditure event superseded", "seq", seq, "latest", history[len(history)-1].Seq)
+		http.Error(w, domain.ErrExpenditureEventSuperseded.Error(), http.StatusConflict)
+		return
+	}
+
+	before := domain.ExpenditureBefore(history, seq)
+	switch {
+	case before == nil:
+		err = h.service.DeleteExpenditure(event.ExpenditureID.String())
+	case event.Kind == domain.ExpenditureDeleted:
+		err = h.service.AddExpenditure(before)
+	default:
+		err = h.service.UpdateExpenditure(before)
+	}
+	if err != nil {
+		h.logger.Error("Failed to revert expenditure event", "seq", seq, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if before == nil {
+		h.logger.Info("Successfully reverted expenditure event", "seq", seq, "id", event.ExpenditureID)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	h.logger.Info("Successfully reverted expenditure event", "seq", seq, "id", event.ExpenditureID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(before)
+}
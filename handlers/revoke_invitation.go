@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+)
+
+// RevokeInvitation handles DELETE /households/{id}/invitations/{invitationId}, which withdraws a
+// pending invitation so its token can no longer be used
+func (h *HouseholdHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling revoke invitation request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodDelete {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	householdID, invitationID, ok := nestedPathIDs(w, r, "/households/", "invitations")
+	if !ok {
+		return
+	}
+
+	if _, err := h.service.Revoke(householdID, invitationID); err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully revoked invitation", "id", invitationID, "household_id", householdID)
+	w.WriteHeader(http.StatusNoContent)
+}
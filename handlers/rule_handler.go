@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type RuleHandler struct {
+	rules      domain.CategorizationRuleRepository
+	categories domain.CategoryRepository
+	accounts   domain.AccountRepository
+	merchants  domain.MerchantRepository
+	logger     *slog.Logger
+}
+
+// NewRuleHandler creates a new RuleHandler; categories, accounts and merchants may be nil when
+// the storage has no support for them, in which case rules referring to them are not checked
+func NewRuleHandler(rules domain.CategorizationRuleRepository, categories domain.CategoryRepository, accounts domain.AccountRepository, merchants domain.MerchantRepository, logger *slog.Logger) *RuleHandler {
+	return &RuleHandler{
+		rules:      rules,
+		categories: categories,
+		accounts:   accounts,
+		merchants:  merchants,
+		logger:     logger,
+	}
+}
+
+func RuleRouter(handler *RuleHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		switch path {
+		case "/rules":
+			Methods{
+				http.MethodGet:  handler.GetAllRules,
+				http.MethodPost: handler.AddRule,
+			}.ServeHTTP(w, r)
+			return
+		case "/rules/test":
+			Methods{http.MethodPost: handler.TestRules}.ServeHTTP(w, r)
+			return
+		}
+
+		_, sub, ok := splitPath(path, "/rules/")
+		if !ok || sub != "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		Methods{
+			http.MethodGet:    handler.GetRuleByID,
+			http.MethodPut:    handler.UpdateRule,
+			http.MethodDelete: handler.DeleteRule,
+		}.ServeHTTP(w, r)
+	})
+}
+
+// checkReferences returns the not found error of the first category, account or merchant the
+// rule refers to that does not exist, see isMissingReference. References the storage cannot check
+// always pass
+func (h *RuleHandler) checkReferences(rule *domain.CategorizationRule) error {
+	if h.categories != nil && rule.CategoryId != uuid.Nil {
+		if _, err := h.categories.GetCategoryByID(rule.CategoryId.String()); err != nil {
+			return err
+		}
+	}
+	if h.accounts != nil && rule.AccountId != uuid.Nil {
+		if _, err := h.accounts.GetAccountByID(rule.AccountId.String()); err != nil {
+			return err
+		}
+	}
+	if h.merchants != nil && rule.MerchantId != uuid.Nil {
+		if _, err := h.merchants.GetMerchantByID(rule.MerchantId.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isMissingReference reports whether an error of checkReferences is a client error
+func isMissingReference(err error) bool {
+	return err == domain.ErrCategoryNotFound || err == domain.ErrAccountNotFound || err == domain.ErrMerchantNotFound
+}
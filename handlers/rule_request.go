@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"github.com/google/uuid"
+)
+
+type RuleRequest struct {
+	Name       string    `json:"name"`
+	Contains   string    `json:"contains"`   // Text matching entries contain, e.g. "UBER"
+	CategoryId uuid.UUID `json:"categoryId"` // Optional
+	Tags       []string  `json:"tags"`       // Optional
+	AccountId  uuid.UUID `json:"accountId"`  // Optional
+	MerchantId uuid.UUID `json:"merchantId"` // Optional
+}
+
+// RuleTestRequest is a sample imported row to evaluate the rules against
+type RuleTestRequest struct {
+	Description string `json:"description"`
+	RawText     string `json:"raw_text"` // Optional, e.g. the statement line
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-expense-tracker/domain"
+	"io"
+	"net/http"
+	"time"
+)
+
+func (h *ArchiveHandler) RunArchive(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling run archive request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ArchiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var before time.Time
+	var err error
+	if req.Before != "" {
+		before, err = time.ParseInLocation("2006-01-02", req.Before, time.Local)
+		if err != nil {
+			h.logger.Warn("Invalid archive cutoff", "before", req.Before)
+			http.Error(w, "Invalid before date, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	} else {
+		before, err = domain.ArchiveCutoff(time.Now(), h.retentionYears)
+		if err != nil {
+			h.logger.Error("Invalid archive retention", "retention_years", h.retentionYears, "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	h.logger.Debug("Running archive", "before", before)
+
+	archive, err := h.archives.ArchiveExpenditures(before)
+	if err != nil {
+		if err == domain.ErrNothingToArchive {
+			h.logger.Info("Nothing to archive", "before", before)
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		h.logger.Error("Failed to archive expenditures", "before", before, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully archived expenditures", "id", archive.ID, "count", archive.Count, "before", before)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(archive)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// RunBatch handles POST /batch, which runs an array of read requests in one round trip and
+// returns their responses in the same order, each with its own status. The requests carry the
+// headers of the batch, so they are made as the same member and in the same language
+func (h *BatchHandler) RunBatch(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling run batch request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var reqs []BatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(reqs) == 0 || len(reqs) > maxBatchRequests {
+		h.logger.Warn("Invalid batch size", "count", len(reqs))
+		http.Error(w, errBatchSize.Error(), http.StatusBadRequest)
+		return
+	}
+
+	subRequests := make([]*http.Request, len(reqs))
+	for i, req := range reqs {
+		target, err := url.ParseRequestURI(req.Path)
+		if err == nil {
+			err = req.validate()
+		}
+		if err != nil {
+			h.logger.Warn("Invalid batch request", "error", err, "index", i, "method", req.Method, "path", req.Path)
+			if err != errBatchMethod {
+				err = errBatchPath
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		subRequests[i] = h.subRequest(r, req, target)
+	}
+
+	// The requests only read, so they run side by side
+	responses := make([]BatchResponse, len(reqs))
+	var wg sync.WaitGroup
+	for i, sub := range subRequests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := newBatchRecorder()
+			h.target.ServeHTTP(rec, sub)
+			if sub.Method == http.MethodHead {
+				rec.body.Reset()
+			}
+			responses[i] = rec.response(reqs[i].ID)
+		}()
+	}
+	wg.Wait()
+
+	h.logger.Info("Successfully ran batch", "count", len(responses))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(responses)
+}
+
+// subRequest builds a request of a batch, with the headers of the batch request and its own
+func (h *BatchHandler) subRequest(r *http.Request, req BatchRequest, target *url.URL) *http.Request {
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	sub := r.Clone(r.Context())
+	sub.Method = method
+	sub.URL = target
+	sub.RequestURI = target.RequestURI()
+	sub.Body = http.NoBody
+	sub.ContentLength = 0
+	sub.Header.Del("Content-Type")
+	sub.Header.Del("Content-Length")
+	for name, value := range req.Headers {
+		sub.Header.Set(name, value)
+	}
+	return sub
+}
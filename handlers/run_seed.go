@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/seed"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+func (h *SeedHandler) RunSeed(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling run seed request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req SeedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Months == 0 {
+		req.Months = defaultSeedMonths
+	}
+	if req.Seed == 0 {
+		req.Seed = rand.Uint64()
+	}
+
+	categories, err := h.categories.GetAllCategories()
+	if err != nil {
+		h.logger.Error("Failed to get categories for seeding", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	expenditures, err := seed.Generate(categories, seed.Options{Months: req.Months, Now: time.Now(), Seed: req.Seed})
+	if err != nil {
+		h.logger.Warn("Failed to generate demo data", "months", req.Months, "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := domain.AddExpenditures(h.expenditures, expenditures); err != nil {
+		h.logger.Error("Failed to add demo expenditures", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := SeedResponse{Count: len(expenditures)}
+	for _, expenditure := range expenditures {
+		response.Total += expenditure.Amount
+	}
+	response.Total = math.Round(response.Total*100) / 100
+
+	h.logger.Info("Successfully seeded demo data", "months", req.Months, "seed", req.Seed, "count", response.Count)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+)
+
+// defaultSeedMonths is how many months of demo data are generated when none are asked for
+const defaultSeedMonths = 6
+
+type SeedHandler struct {
+	expenditures domain.ExpenditureRepository
+	categories   domain.CategoryRepository
+	logger       *slog.Logger
+}
+
+// NewSeedHandler creates a new SeedHandler; it is only meant to be exposed in development
+func NewSeedHandler(expenditures domain.ExpenditureRepository, categories domain.CategoryRepository, logger *slog.Logger) *SeedHandler {
+	return &SeedHandler{
+		expenditures: expenditures,
+		categories:   categories,
+		logger:       logger,
+	}
+}
@@ -0,0 +1,13 @@
+package handlers
+
+// SeedRequest is the optional body of a demo data run
+type SeedRequest struct {
+	Months int    `json:"months"` // Defaults to 6
+	Seed   uint64 `json:"seed"`   // Random when 0
+}
+
+// SeedResponse tells how much demo data was generated
+type SeedResponse struct {
+	Count int     `json:"count"`
+	Total float64 `json:"total"`
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// SetAllowance handles PUT /households/{id}/members/{memberId}/allowance, which makes the envelope
+// of a category the member's allowance
+func (h *HouseholdHandler) SetAllowance(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling set allowance request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPut {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	householdID, memberID, ok := nestedPathIDs(w, r, "/households/", "members")
+	if !ok {
+		return
+	}
+
+	var req AllowanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	allowance, err := h.allowances.Assign(householdID, memberID, req.CategoryId)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully set allowance", "household_id", householdID, "member_id", memberID, "category_id", req.CategoryId)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newAllowanceResponse(allowance))
+}
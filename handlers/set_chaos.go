@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/chaos"
+	"net/http"
+)
+
+// SetChaos replaces the rules of the faults injected into requests
+func (h *ChaosHandler) SetChaos(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling set chaos request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	var req ChaosRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Invalid chaos request body", "error", err)
+		http.Error(w, "Invalid request body, expected {\"rules\": [\"/expenditures error=10\"]}", http.StatusBadRequest)
+		return
+	}
+
+	rules := make([]chaos.Rule, 0, len(req.Rules))
+	for _, text := range req.Rules {
+		rule, err := chaos.ParseRule(text)
+		if err != nil {
+			h.logger.Warn("Invalid chaos rule", "error", err)
+			http.Error(w, "Invalid rule: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		rules = append(rules, rule)
+	}
+
+	h.injector.SetRules(rules)
+	response := chaosResponse(rules)
+	h.logger.Warn("Replaced chaos rules", "rules", response.Rules, "remote_addr", r.RemoteAddr)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// SetMaintenance puts the API into read-only mode or lifts it
+func (h *MaintenanceHandler) SetMaintenance(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling set maintenance request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	var req MaintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ReadOnly == nil {
+		h.logger.Warn("Invalid maintenance request body", "error", err)
+		http.Error(w, "Invalid request body, expected {\"read_only\": true} or {\"read_only\": false}", http.StatusBadRequest)
+		return
+	}
+
+	var retryAfter time.Duration
+	if req.RetryAfter != "" {
+		var err error
+		retryAfter, err = time.ParseDuration(req.RetryAfter)
+		if err != nil || retryAfter <= 0 {
+			h.logger.Warn("Invalid maintenance retry after", "error", err, "value", req.RetryAfter)
+			http.Error(w, "Invalid retry_after, use a duration such as 10m", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if *req.ReadOnly {
+		h.mode.Enable(req.Reason, retryAfter)
+		h.logger.Warn("Entered read-only mode", "reason", req.Reason, "retry_after", retryAfter, "remote_addr", r.RemoteAddr)
+	} else {
+		h.mode.Disable()
+		h.logger.Warn("Left read-only mode", "remote_addr", r.RemoteAddr)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.mode.State())
+}
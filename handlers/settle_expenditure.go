@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+func (h *ExpenditureHandler) ClearExpenditure(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling clear expenditure request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/expenditures/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Clearing expenditure", "id", id)
+
+	var req ClearExpenditureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.expenditures.Clear(id, req.Amount, req.Date)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully cleared expenditure", "id", id, "amount", result.Expenditure.Amount)
+	flagLimit(w, result)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result.Expenditure)
+}
+
+func (h *ExpenditureHandler) CancelExpenditure(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling cancel expenditure request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/expenditures/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Cancelling expenditure", "id", id)
+
+	expenditure, err := h.expenditures.Cancel(id)
+	if err != nil {
+		http.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully cancelled expenditure", "id", id)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(expenditure)
+}
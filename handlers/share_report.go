@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+	"time"
+)
+
+// ShareReport handles POST /reports/share, which signs a link to the report of a fiscal month.
+// The report is computed when the link is opened, so it shows later edits to the month
+func (h *ReportShareHandler) ShareReport(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling share report request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ReportShareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.ValidDays == 0 {
+		req.ValidDays = 7
+	}
+	if req.ValidDays < 0 || req.ValidDays > 30 {
+		h.logger.Warn("Invalid share validity", "valid_days", req.ValidDays)
+		http.Error(w, errInvalidShareDays.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Links to months that don't parse would never open
+	month, err := time.Parse("2006-01", req.Month)
+	if err != nil {
+		h.logger.Warn("Invalid shared report month", "month", req.Month)
+		http.Error(w, domain.ErrInvalidFiscalPeriod.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Month = month.Format("2006-01")
+
+	expires := time.Now().AddDate(0, 0, req.ValidDays).Truncate(time.Second)
+	resp := ReportShareResponse{
+		Month:     req.Month,
+		URL:       h.links.Sign(sharedReportPath(req.Month), expires),
+		ExpiresAt: expires,
+	}
+
+	h.logger.Info("Successfully shared report", "month", req.Month, "expires_at", expires)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker/anonymize"
+	"go-expense-tracker/domain"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StartExport handles POST /exports, which exports the expenditures matching the filters in
+// the background and replies 202 Accepted pointing at the export. Unlike GET
+// /expenditures/export, the date range is not limited, as no request waits for the file
+func (h *ExportHandler) StartExport(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling start export request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ExportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	from, to, err := req.validate()
+	if err != nil {
+		h.logger.Warn("Invalid export request", "error", err, "format", req.Format)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rows, err := domain.CountExpenditures(h.service, from, to)
+	if err != nil {
+		h.logger.Error("Failed to count expenditures", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	job := h.jobs.Start(exportJobKind, rows, func(w http.ResponseWriter) error {
+		return h.writeExport(w, req, from, to)
+	})
+
+	h.logger.Info("Successfully started export", "id", job.ID, "format", req.Format, "rows", rows)
+	w.Header().Set("Location", "/exports/"+job.ID.String())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(h.newExportResponse(job))
+}
+
+// writeExport writes the expenditures of an export in its format
+func (h *ExportHandler) writeExport(w http.ResponseWriter, req ExportRequest, from, to time.Time) error {
+	var anonymizer *anonymize.Anonymizer
+	if req.Anonymize {
+		anonymizer = anonymize.New(anonymize.DefaultJitter)
+	}
+
+	filename := "expenditures-" + time.Now().Format("2006-01-02")
+	if anonymizer != nil {
+		filename += "-anonymized"
+	}
+	extension := map[string]string{"json": "json", "ndjson": "jsonl", "csv": "csv"}[req.Format]
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, extension))
+
+	var write func(expenditure *domain.Expenditure) error
+	var finish func() error
+	if req.Format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"id", "date", "description", "amount", "category_id", "tags", "merchant_id", "account_id", "status"})
+		write = func(expenditure *domain.Expenditure) error {
+			return cw.Write(expenditureCSVRecord(expenditure))
+		}
+		finish = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	} else {
+		stream := &jsonStream{w: w, encoder: json.NewEncoder(w), ndjson: req.Format == "ndjson"}
+		write = func(expenditure *domain.Expenditure) error {
+			return stream.Write(expenditure)
+		}
+		finish = stream.Close
+	}
+
+	count := 0
+	err := domain.EachExpenditure(h.service, func(expenditure *domain.Expenditure) error {
+		if !req.matches(expenditure, from, to) {
+			return nil
+		}
+		if anonymizer != nil {
+			expenditure = anonymizer.Expenditure(expenditure)
+		}
+		count++
+		return write(expenditure)
+	})
+	if err == nil {
+		err = finish()
+	}
+	if err != nil {
+		return err
+	}
+
+	h.logger.Info("Successfully exported expenditures", "format", req.Format, "count", count, "anonymized", anonymizer != nil)
+	return nil
+}
+
+// expenditureCSVRecord returns the columns of an expenditure in a CSV export. Amounts and dates
+// are written in a locale-independent format, for other programs to read
+func expenditureCSVRecord(expenditure *domain.Expenditure) []string {
+	optionalID := func(id uuid.UUID) string {
+		if id == uuid.Nil {
+			return ""
+		}
+		return id.String()
+	}
+	return []string{
+		expenditure.ID.String(),
+		expenditure.Date.Format("2006-01-02"),
+		expenditure.Description,
+		strconv.FormatFloat(expenditure.Amount, 'f', -1, 64),
+		optionalID(expenditure.CategoryId),
+		strings.Join(expenditure.Tags, ";"),
+		optionalID(expenditure.MerchantId),
+		optionalID(expenditure.AccountId),
+		string(expenditure.Status),
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// ndjsonContentType is negotiated with the Accept header to stream listings as one JSON document per line
+const ndjsonContentType = "application/x-ndjson"
+
+// streamFlushEvery is the number of items written between two flushes
+const streamFlushEvery = 100
+
+// jsonStream writes a listing item by item, as a JSON array or as NDJSON, so a large listing is
+// never encoded in one piece
+type jsonStream struct {
+	w       http.ResponseWriter
+	encoder *json.Encoder
+	ndjson  bool
+	started bool
+	count   int
+}
+
+func newJSONStream(w http.ResponseWriter, r *http.Request) *jsonStream {
+	return &jsonStream{
+		w:       w,
+		encoder: json.NewEncoder(w),
+		ndjson:  strings.Contains(r.Header.Get("Accept"), ndjsonContentType),
+	}
+}
+
+// Started reports whether the response headers were sent, after which errors can no longer
+// be reported with a status code
+func (s *jsonStream) Started() bool {
+	return s.started
+}
+
+func (s *jsonStream) start() error {
+	s.started = true
+	if s.ndjson {
+		s.w.Header().Set("Content-Type", ndjsonContentType)
+		return nil
+	}
+
+	s.w.Header().Set("Content-Type", "application/json")
+	_, err := s.w.Write([]byte("["))
+	return err
+}
+
+// Write encodes one item, sending the headers with the first one
+func (s *jsonStream) Write(item interface{}) error {
+	if !s.started {
+		if err := s.start(); err != nil {
+			return err
+		}
+	} else if !s.ndjson {
+		if _, err := s.w.Write([]byte(",")); err != nil {
+			return err
+		}
+	}
+
+	if err := s.encoder.Encode(item); err != nil {
+		return err
+	}
+
+	s.count++
+	if s.count%streamFlushEvery == 0 {
+		// Not every writer can flush, the response is then sent when the handler returns
+		http.NewResponseController(s.w).Flush()
+	}
+	return nil
+}
+
+// Close ends the listing; an empty listing is written as []
+func (s *jsonStream) Close() error {
+	if !s.started {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+	if s.ndjson {
+		return nil
+	}
+
+	_, err := s.w.Write([]byte("]\n"))
+	return err
+}
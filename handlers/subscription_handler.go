@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"go-expense-tracker/recurring"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type SubscriptionHandler struct {
+	service   domain.ExpenditureRepository
+	recurring domain.RecurringRepository
+	logger    *slog.Logger
+}
+
+// NewSubscriptionHandler creates a new SubscriptionHandler detecting subscriptions in the
+// expenditures of service and converting them into recurring expenditures
+func NewSubscriptionHandler(service domain.ExpenditureRepository, recurring domain.RecurringRepository, logger *slog.Logger) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		service:   service,
+		recurring: recurring,
+		logger:    logger,
+	}
+}
+
+func SubscriptionRouter(handler *SubscriptionHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/subscriptions/detected" {
+			Methods{http.MethodGet: handler.GetDetectedSubscriptions}.ServeHTTP(w, r)
+			return
+		}
+
+		_, sub, ok := splitPath(path, "/subscriptions/detected/")
+		if !ok || sub != "convert" {
+			http.NotFound(w, r)
+			return
+		}
+		Methods{http.MethodPost: handler.ConvertSubscription}.ServeHTTP(w, r)
+	})
+}
+
+// DetectedSubscriptionsResponse lists the detected subscriptions with what they cost together
+type DetectedSubscriptionsResponse struct {
+	Subscriptions []*domain.DetectedSubscription `json:"subscriptions"`
+	MonthlyCost   float64                        `json:"monthly_cost"`
+}
+
+// detect finds the subscriptions in the history that no recurring expenditure manages yet
+func (h *SubscriptionHandler) detect(now time.Time) ([]*domain.DetectedSubscription, error) {
+	var history []*domain.Expenditure
+	err := domain.EachExpenditure(h.service, func(expenditure *domain.Expenditure) error {
+		history = append(history, expenditure)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	managed, err := h.recurring.GetAllRecurring()
+	if err != nil {
+		return nil, err
+	}
+
+	return recurring.DetectSubscriptions(history, managed, now), nil
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *ConnectionHandler) SyncConnection(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling sync connection request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/connections/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Syncing connection", "id", id)
+
+	connection, err := h.connections.GetBankConnectionByID(id)
+	if err != nil {
+		if err == domain.ErrBankConnectionNotFound {
+			h.logger.Warn("Connection not found for sync", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get connection", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	staged, err := h.syncer.SyncConnection(r.Context(), connection)
+	if err != nil {
+		h.logger.Error("Failed to sync connection", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	h.logger.Info("Successfully synced connection", "id", id, "staged", staged)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"staged": staged})
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *ReportHandler) TakeReportSnapshot(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling take report snapshot request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	var req ReportSnapshotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	snapshot, err := h.snapshot.Take(req.Month)
+	if err != nil {
+		if err == domain.ErrInvalidFiscalPeriod {
+			h.logger.Warn("Invalid report snapshot month", "month", req.Month)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.logger.Error("Failed to take report snapshot", "month", req.Month, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully took report snapshot", "id", snapshot.ID, "month", snapshot.Month)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(snapshot)
+}
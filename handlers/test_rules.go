@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+// TestRules shows which rules a sample imported row would match and what they would set,
+// without importing anything
+func (h *RuleHandler) TestRules(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling test rules request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req RuleTestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	rules, err := h.rules.GetAllRules()
+	if err != nil {
+		h.logger.Error("Failed to get rules to test", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	match := domain.MatchRules(rules, req.Description, req.RawText)
+
+	h.logger.Info("Successfully tested rules", "rules", len(rules), "matched", len(match.Rules))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(match)
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-expense-tracker/features"
+	"net/http"
+)
+
+// ToggleFlag enables or disables a feature flag until the next restart
+func (h *FlagHandler) ToggleFlag(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling toggle feature flag request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPut {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name, _, ok := splitPath(r.URL.Path, "/admin/flags/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	var req FlagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Enabled == nil {
+		h.logger.Warn("Invalid feature flag request body", "error", err, "name", name)
+		http.Error(w, "Invalid request body, expected {\"enabled\": true} or {\"enabled\": false}", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.flags.Toggle(name, *req.Enabled); err != nil {
+		h.logger.Warn("Failed to toggle feature flag", "error", err, "name", name)
+		switch {
+		case errors.Is(err, features.ErrUnknownFlag):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, features.ErrStartupOnlyFlag):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	for _, state := range h.flags.States() {
+		if state.Name == name {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(state)
+		}
+	}
+	h.logger.Warn("Feature flag toggled", "name", name, "enabled", *req.Enabled, "remote_addr", r.RemoteAddr)
+}
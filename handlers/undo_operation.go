@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *OperationHandler) UndoOperation(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling undo operation request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/operations/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Undoing operation", "id", id)
+
+	operation, err := h.recorder.Undo(id)
+	if err != nil {
+		switch {
+		case err == domain.ErrOperationNotFound:
+			h.logger.Warn("Operation not found for undo", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case err == domain.ErrOperationUndoExpired:
+			h.logger.Warn("Operation undo window expired", "id", id)
+			http.Error(w, err.Error(), http.StatusGone)
+		case undoConflict(err):
+			h.logger.Warn("Operation cannot be undone", "id", id, "error", err)
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			h.logger.Error("Failed to undo operation", "id", id, "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.logger.Info("Successfully undid operation", "id", id, "kind", operation.Kind, "expenditure_id", operation.ExpenditureID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(operation)
+}
+
+// undoConflict reports whether an operation cannot be undone because of what happened since, or
+// because undoing it would break the rules for changing the expenditure directly
+func undoConflict(err error) bool {
+	for _, conflict := range []error{
+		domain.ErrOperationAlreadyUndone, domain.ErrOperationSuperseded, domain.ErrExpenditureAlreadyExists, domain.ErrExpenditureNotFound,
+		domain.ErrExpenditureReconciled, domain.ErrExpenditureHasRefunds, domain.ErrAmountBelowRefunded, domain.ErrRefundExceedsOriginal,
+	} {
+		if errors.Is(err, conflict) {
+			return true
+		}
+	}
+	return false
+}
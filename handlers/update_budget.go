@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *BudgetHandler) UpdateBudget(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling update budget request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPut {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/budgets/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Updating budget", "id", id)
+
+	budget, err := h.budgets.GetBudgetByID(id)
+	if err != nil {
+		if err == domain.ErrBudgetNotFound {
+			h.logger.Warn("Budget not found for update", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get budget", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var req BudgetRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode update request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Period == "" {
+		req.Period = budget.Period
+	}
+	if req.StartDate.IsZero() {
+		req.StartDate = budget.StartDate
+	}
+
+	err = budget.Update(req.Name, req.Amount, req.Period, req.CategoryId, req.StartDate, req.EndDate, req.Rollover)
+	if err != nil {
+		h.logger.Warn("Invalid budget update", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	exists, err := h.checkCategory(budget.CategoryId)
+	if err != nil {
+		h.logger.Error("Failed to check budget category", "error", err, "category_id", budget.CategoryId)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		h.logger.Warn("Budget category not found", "category_id", budget.CategoryId)
+		http.Error(w, domain.ErrCategoryNotFound.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.budgets.UpdateBudget(budget)
+	if err != nil {
+		h.logger.Error("Failed to update budget", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully updated budget", "id", id, "name", budget.Name)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(budget)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/meta"
+	"net/http"
+	"strings"
+)
+
+func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling update category request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPut {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/categories/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Updating category", "id", id)
+
+	category, err := h.categories.GetCategoryByID(id)
+	if err != nil {
+		if err == domain.ErrCategoryNotFound {
+			h.logger.Warn("Category not found for update", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get category", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var req CategoryRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode update request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Categories created before the palette keep their colors until they are changed
+	if req.Color != "" && !strings.EqualFold(req.Color, category.Color) && !meta.InPalette(req.Color) {
+		h.logger.Warn("Category color not in the palette", "id", id, "color", req.Color)
+		http.Error(w, domain.ErrCategoryColorNotInPalette.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = category.Update(req.Name, req.Color)
+	if err != nil {
+		h.logger.Warn("Invalid category update", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	category.Deductible = req.Deductible
+
+	err = category.SetIcon(req.Icon)
+	if err != nil {
+		h.logger.Warn("Invalid category icon", "id", id, "icon", req.Icon)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = category.SetLimit(req.TransactionLimit, req.LimitMode)
+	if err != nil {
+		h.logger.Warn("Invalid category limit", "id", id, "limit", req.TransactionLimit, "mode", req.LimitMode)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	status, err := h.checkParent(category.ID, req.ParentId)
+	if err != nil {
+		h.logger.Warn("Invalid parent category", "id", id, "parent_id", req.ParentId, "error", err)
+		http.Error(w, err.Error(), status)
+		return
+	}
+	category.ParentID = req.ParentId
+
+	err = h.categories.UpdateCategory(category)
+	if err != nil {
+		if err == domain.ErrCategoryAlreadyExists {
+			h.logger.Warn("Category name already taken", "id", id, "name", category.Name)
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		h.logger.Error("Failed to update category", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully updated category", "id", id, "name", category.Name, "deductible", category.Deductible, "parent_id", category.ParentID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(category)
+}
@@ -2,11 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
-	"github.com/google/uuid"
-	"go-expense-tracker/domain"
 	"net/http"
-	"strings"
-	"time"
 )
 
 func (h *ExpenditureHandler) UpdateExpenditure(w http.ResponseWriter, r *http.Request) {
@@ -18,23 +14,14 @@ func (h *ExpenditureHandler) UpdateExpenditure(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	id := strings.TrimPrefix(r.URL.Path, "/expenditures/")
-	h.logger.Debug("Updating expenditure", "id", id)
-
-	_, err := h.service.GetExpenditureByID(id)
-	if err != nil {
-		if err == domain.ErrExpenditureNotFound {
-			h.logger.Warn("Expenditure not found for update", "id", id)
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		}
-		h.logger.Error("Failed to check expenditure existence", "id", id, "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	id, ok := pathID(w, r, "/expenditures/")
+	if !ok {
 		return
 	}
+	h.logger.Debug("Updating expenditure", "id", id)
 
 	var req ExpenditureRequest
-	err = json.NewDecoder(r.Body).Decode(&req)
+	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
 		h.logger.Error("Failed to decode update request body", "error", err)
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
@@ -43,47 +30,25 @@ func (h *ExpenditureHandler) UpdateExpenditure(w http.ResponseWriter, r *http.Re
 
 	h.logger.Debug("Decoded update request", "id", id, "description", req.Description, "amount", req.Amount, "date", req.Date)
 
-	if req.Description == "" {
-		h.logger.Warn("Empty description in update request", "id", id)
-		http.Error(w, domain.ErrExpenditureDescriptionEmpty.Error(), http.StatusBadRequest)
-		return
-	}
-
-	if req.Amount <= 0 {
-		h.logger.Warn("Invalid amount in update request", "id", id, "amount", req.Amount)
-		http.Error(w, domain.ErrInvalidExpenditureAmount.Error(), http.StatusBadRequest)
-		return
-	}
-
-	// Check if the date is in the future
-	if req.Date.After(time.Now()) {
-		h.logger.Warn("Future date in update request", "id", id, "date", req.Date)
-		http.Error(w, domain.ErrExpenditureFutureDate.Error(), http.StatusBadRequest)
-		return
-	}
-
-	parsedUUID, err := uuid.Parse(id)
-	if err != nil {
-		h.logger.Error("Failed to parse UUID", "id", id, "error", err)
-		http.Error(w, "Invalid UUID", http.StatusBadRequest)
+	if validateOnly(r) {
+		result, err := h.expenditures.PrepareUpdate(id, req.input(r.Context()))
+		if err != nil {
+			http.Error(w, err.Error(), serviceStatus(err))
+			return
+		}
+		h.writeValidated(w, r, result)
 		return
 	}
 
-	expenditure := &domain.Expenditure{
-		ID:          parsedUUID,
-		Description: req.Description,
-		Amount:      req.Amount,
-		Date:        req.Date,
-	}
-
-	err = h.service.UpdateExpenditure(expenditure)
+	result, err := h.expenditures.Update(id, req.input(r.Context()))
 	if err != nil {
-		h.logger.Error("Failed to update expenditure", "id", id, "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), serviceStatus(err))
 		return
 	}
+	expenditure := result.Expenditure
 
 	h.logger.Info("Successfully updated expenditure", "id", id, "description", expenditure.Description, "date", expenditure.Date)
+	flagLimit(w, result)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(expenditure)
 }
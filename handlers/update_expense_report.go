@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *ExpenseReportHandler) UpdateExpenseReport(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling update expense report request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPut {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/expense-reports/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Updating expense report", "id", id)
+
+	report, err := h.reports.GetExpenseReportByID(id)
+	if err != nil {
+		if err == domain.ErrExpenseReportNotFound {
+			h.logger.Warn("Expense report not found for update", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get expense report", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var req ExpenseReportRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode update request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = report.Update(req.Title, req.Claimant, req.ExpenditureIds)
+	if err != nil {
+		h.logger.Warn("Invalid expense report update", "id", id, "error", err)
+		if err == domain.ErrExpenseReportNotDraft {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	status, err := h.checkExpenditures(report)
+	if err != nil {
+		h.logger.Warn("Invalid expenditures in expense report", "id", id, "error", err)
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	err = h.reports.UpdateExpenseReport(report)
+	if err != nil {
+		h.logger.Error("Failed to update expense report", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully updated expense report", "id", id, "title", report.Title)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *GoalHandler) UpdateGoal(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling update goal request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPut {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/goals/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Updating goal", "id", id)
+
+	goal, err := h.goals.GetGoalByID(id)
+	if err != nil {
+		if err == domain.ErrGoalNotFound {
+			h.logger.Warn("Goal not found for update", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get goal", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var req GoalRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode update request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.StartDate.IsZero() {
+		req.StartDate = goal.StartDate
+	}
+
+	err = goal.Update(req.Name, req.TargetAmount, req.StartDate, req.Deadline, req.CategoryId)
+	if err != nil {
+		h.logger.Warn("Invalid goal update", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	exists, err := h.checkCategory(goal.CategoryId.String())
+	if err != nil {
+		h.logger.Error("Failed to check goal category", "error", err, "category_id", goal.CategoryId)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		h.logger.Warn("Goal category not found", "category_id", goal.CategoryId)
+		http.Error(w, domain.ErrCategoryNotFound.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.goals.UpdateGoal(goal)
+	if err != nil {
+		h.logger.Error("Failed to update goal", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully updated goal", "id", id, "name", goal.Name)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(goal)
+}
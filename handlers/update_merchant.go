@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *MerchantHandler) UpdateMerchant(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling update merchant request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPut {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/merchants/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Updating merchant", "id", id)
+
+	merchant, err := h.merchants.GetMerchantByID(id)
+	if err != nil {
+		if err == domain.ErrMerchantNotFound {
+			h.logger.Warn("Merchant not found for update", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get merchant", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var req MerchantRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode update request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = merchant.Update(req.Name, req.Aliases, req.DefaultCategoryId)
+	if err != nil {
+		h.logger.Warn("Invalid merchant update", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	exists, err := h.checkCategory(merchant.DefaultCategoryId)
+	if err != nil {
+		h.logger.Error("Failed to check merchant default category", "error", err, "category_id", merchant.DefaultCategoryId)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		h.logger.Warn("Merchant default category not found", "category_id", merchant.DefaultCategoryId)
+		http.Error(w, domain.ErrCategoryNotFound.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.merchants.UpdateMerchant(merchant)
+	if err != nil {
+		h.logger.Error("Failed to update merchant", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully updated merchant", "id", id, "name", merchant.Name)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(merchant)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+// UpdateRecurring replaces a recurring expenditure; its occurrences start over from the start date,
+// which is kept unless another is given
+func (h *RecurringHandler) UpdateRecurring(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling update recurring expenditure request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPut {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/recurring/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Updating recurring expenditure", "id", id)
+
+	var req RecurringRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode update request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	recurring, err := h.generator.Change(id, func(recurring *domain.RecurringExpenditure) error {
+		if req.Frequency == "" {
+			req.Frequency = recurring.Frequency
+		}
+		if req.StartDate.IsZero() {
+			req.StartDate = recurring.StartDate
+		}
+
+		exists, err := h.checkCategory(req.CategoryId)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return domain.ErrCategoryNotFound
+		}
+
+		return recurring.Update(req.Description, req.Amount, req.CategoryId, req.Tags, req.Frequency, req.StartDate, req.EndDate)
+	})
+	if err != nil {
+		h.logger.Warn("Failed to update recurring expenditure", "id", id, "error", err)
+		http.Error(w, err.Error(), recurringStatus(err))
+		return
+	}
+
+	h.logger.Info("Successfully updated recurring expenditure", "id", id, "description", recurring.Description)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recurring)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *RuleHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling update rule request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPut {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/rules/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Updating rule", "id", id)
+
+	rule, err := h.rules.GetRuleByID(id)
+	if err != nil {
+		if err == domain.ErrRuleNotFound {
+			h.logger.Warn("Rule not found for update", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get rule", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var req RuleRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode update request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = rule.Update(req.Name, req.Contains, req.CategoryId, req.Tags, req.AccountId, req.MerchantId)
+	if err != nil {
+		h.logger.Warn("Invalid rule update", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.checkReferences(rule); err != nil {
+		if isMissingReference(err) {
+			h.logger.Warn("Rule refers to a missing record", "error", err, "id", id)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.logger.Error("Failed to check rule references", "error", err, "id", id)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = h.rules.UpdateRule(rule)
+	if err != nil {
+		h.logger.Error("Failed to update rule", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully updated rule", "id", id, "name", rule.Name)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-expense-tracker/domain"
+	"net/http"
+)
+
+func (h *ViewHandler) UpdateView(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling update view request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPut {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := pathID(w, r, "/views/")
+	if !ok {
+		return
+	}
+	h.logger.Debug("Updating view", "id", id)
+
+	view, err := h.views.GetViewByID(id)
+	if err != nil {
+		if err == domain.ErrViewNotFound {
+			h.logger.Warn("View not found for update", "id", id)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get view", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var req ViewRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode update request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = view.Update(req.Name, req.Filter.filter())
+	if err != nil {
+		h.logger.Warn("Invalid view update", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.views.UpdateView(view)
+	if err != nil {
+		h.logger.Error("Failed to update view", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Successfully updated view", "id", id, "name", view.Name)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(view)
+}
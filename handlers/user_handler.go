@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"errors"
+	"go-expense-tracker/app"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+var errNoMember = errors.New("name yourself with the X-Member-ID header")
+
+type UserHandler struct {
+	privacy *app.PrivacyService
+	logger  *slog.Logger
+}
+
+func NewUserHandler(privacy *app.PrivacyService, logger *slog.Logger) *UserHandler {
+	return &UserHandler{
+		privacy: privacy,
+		logger:  logger,
+	}
+}
+
+// UserRouter serves /users/me, the household member named by the X-Member-ID header
+func UserRouter(handler *UserHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimSuffix(r.URL.Path, "/") {
+		case "/users/me":
+			Methods{http.MethodDelete: handler.EraseUser}.ServeHTTP(w, r)
+		case "/users/me/data-export":
+			Methods{http.MethodGet: handler.ExportUserData}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
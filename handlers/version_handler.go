@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"go-expense-tracker/buildinfo"
+	"log/slog"
+)
+
+type VersionHandler struct {
+	info   buildinfo.Info
+	logger *slog.Logger
+}
+
+func NewVersionHandler(info buildinfo.Info, logger *slog.Logger) *VersionHandler {
+	return &VersionHandler{
+		info:   info,
+		logger: logger,
+	}
+}
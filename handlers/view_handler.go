@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type ViewHandler struct {
+	views        domain.ViewRepository
+	pins         domain.PinRepository
+	expenditures domain.ExpenditureRepository
+	logger       *slog.Logger
+}
+
+// NewViewHandler creates a new ViewHandler; pins may be nil when the storage cannot pin
+// expenditures, in which case views filtering on pins match nothing
+func NewViewHandler(views domain.ViewRepository, pins domain.PinRepository, expenditures domain.ExpenditureRepository, logger *slog.Logger) *ViewHandler {
+	return &ViewHandler{
+		views:        views,
+		pins:         pins,
+		expenditures: expenditures,
+		logger:       logger,
+	}
+}
+
+func ViewRouter(handler *ViewHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+
+		if path == "/views" {
+			Methods{
+				http.MethodGet:  handler.GetAllViews,
+				http.MethodPost: handler.AddView,
+			}.ServeHTTP(w, r)
+			return
+		}
+
+		_, sub, ok := splitPath(path, "/views/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch sub {
+		case "":
+			Methods{
+				http.MethodGet:    handler.GetViewByID,
+				http.MethodPut:    handler.UpdateView,
+				http.MethodDelete: handler.DeleteView,
+			}.ServeHTTP(w, r)
+		case "expenditures":
+			Methods{http.MethodGet: handler.GetViewExpenditures}.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// pinnedSet returns the pinned expenditures, empty when the storage cannot pin
+func pinnedSet(pins domain.PinRepository) (map[uuid.UUID]bool, error) {
+	pinned := make(map[uuid.UUID]bool)
+	if pins == nil {
+		return pinned, nil
+	}
+
+	ids, err := pins.GetPinnedExpenditureIDs()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		pinned[id] = true
+	}
+	return pinned, nil
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"github.com/google/uuid"
+	"go-expense-tracker/domain"
+)
+
+type ViewRequest struct {
+	Name   string            `json:"name"`
+	Filter ViewFilterRequest `json:"filter"`
+}
+
+// ViewFilterRequest selects expenditures; empty fields do not filter
+type ViewFilterRequest struct {
+	From        string      `json:"from"` // YYYY-MM-DD, inclusive
+	To          string      `json:"to"`   // YYYY-MM-DD, inclusive
+	Tags        []string    `json:"tags"` // All must be present
+	CategoryIds []uuid.UUID `json:"categoryIds"`
+	MerchantId  uuid.UUID   `json:"merchantId"`
+	MinAmount   float64     `json:"minAmount"`
+	MaxAmount   float64     `json:"maxAmount"`
+	Search      string      `json:"search"` // Text in the description
+	Pinned      bool        `json:"pinned"` // Only pinned expenditures
+}
+
+func (f ViewFilterRequest) filter() domain.ExpenditureFilter {
+	return domain.ExpenditureFilter{
+		From:        f.From,
+		To:          f.To,
+		Tags:        f.Tags,
+		CategoryIds: f.CategoryIds,
+		MerchantId:  f.MerchantId,
+		MinAmount:   f.MinAmount,
+		MaxAmount:   f.MaxAmount,
+		Search:      f.Search,
+		Pinned:      f.Pinned,
+	}
+}
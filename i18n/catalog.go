@@ -0,0 +1,119 @@
+// Package i18n translates the messages of the API, such as errors and the labels of generated
+// documents, into the language a client asks for.
+//
+// Messages are keyed by their English text, so code keeps using plain English strings and a
+// message without a translation falls back to it. The catalogs are JSON files in locales, one
+// per language, kept complete by the extract tool:
+//
+//	go generate ./i18n
+package i18n
+
+//go:generate go run ./extract -root .. -locales locales
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage is the language the messages are written in
+const DefaultLanguage = "en"
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// Catalog holds the translations of the messages per language
+type Catalog struct {
+	messages map[string]map[string]string // Language to English message to translation
+}
+
+// Load reads a catalog from the JSON files of a directory, each named after its language such
+// as de.json and mapping English messages to their translations. Empty translations are left out
+func Load(fsys fs.FS) (*Catalog, error) {
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := &Catalog{messages: make(map[string]map[string]string)}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		var entries map[string]string
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("invalid message catalog %s: %w", file, err)
+		}
+
+		messages := make(map[string]string, len(entries))
+		for message, translation := range entries {
+			if translation != "" {
+				messages[message] = translation
+			}
+		}
+		catalog.messages[normalize(strings.TrimSuffix(path.Base(file), ".json"))] = messages
+	}
+	if _, ok := catalog.messages[DefaultLanguage]; !ok {
+		catalog.messages[DefaultLanguage] = map[string]string{}
+	}
+	return catalog, nil
+}
+
+var defaultCatalog = sync.OnceValue(func() *Catalog {
+	sub, err := fs.Sub(locales, "locales")
+	if err != nil {
+		panic(err)
+	}
+	catalog, err := Load(sub)
+	if err != nil {
+		panic(err)
+	}
+	return catalog
+})
+
+// Default returns the catalog of the locales bundled with the application
+func Default() *Catalog {
+	return defaultCatalog()
+}
+
+// Languages returns the languages of the catalog, sorted
+func (c *Catalog) Languages() []string {
+	languages := make([]string, 0, len(c.messages))
+	for language := range c.messages {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Supports reports whether the catalog has the language
+func (c *Catalog) Supports(language string) bool {
+	_, ok := c.messages[normalize(language)]
+	return ok
+}
+
+// Translate returns the message in the language, or the message itself when it has no
+// translation
+func (c *Catalog) Translate(language, message string) string {
+	if translation, ok := c.messages[normalize(language)][message]; ok {
+		return translation
+	}
+	return message
+}
+
+// normalize lower-cases a language tag and drops its region, as catalogs are per language only:
+// "de-AT" is "de"
+func normalize(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	return language
+}
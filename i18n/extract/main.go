@@ -0,0 +1,168 @@
+// Command extract collects the translatable messages of the application into the message
+// catalogs: the literal messages of errors.New, http.Error and i18n.T calls. The English catalog
+// is written afresh; the other catalogs keep their translations, get the new messages with empty
+// translations to fill in and lose the messages no longer used.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// calls are the functions whose argument at the index is a message, by package and name
+var calls = map[string]int{
+	"errors.New": 0,
+	"http.Error": 1,
+	"i18n.T":     1,
+}
+
+func main() {
+	root := flag.String("root", ".", "Root directory of the sources to collect messages from")
+	localesDir := flag.String("locales", "i18n/locales", "Directory of the message catalogs")
+	flag.Parse()
+
+	messages, err := collect(*root)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "extract:", err)
+		os.Exit(1)
+	}
+
+	if err := update(*localesDir, messages); err != nil {
+		fmt.Fprintln(os.Stderr, "extract:", err)
+		os.Exit(1)
+	}
+}
+
+// collect returns the messages of the Go sources under root, tests left out
+func collect(root string) (map[string]bool, error) {
+	messages := make(map[string]bool)
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			if message, ok := messageOf(node); ok {
+				messages[message] = true
+			}
+			return true
+		})
+		return nil
+	})
+	return messages, err
+}
+
+// messageOf returns the message of a call to one of calls with a string literal
+func messageOf(node ast.Node) (string, bool) {
+	call, ok := node.(*ast.CallExpr)
+	if !ok {
+		return "", false
+	}
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	pkg, ok := selector.X.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+
+	index, ok := calls[pkg.Name+"."+selector.Sel.Name]
+	if !ok || index >= len(call.Args) {
+		return "", false
+	}
+	literal, ok := call.Args[index].(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return "", false
+	}
+	message, err := strconv.Unquote(literal.Value)
+	if err != nil || message == "" {
+		return "", false
+	}
+	return message, true
+}
+
+// update writes the messages into the catalogs of the locales directory
+func update(dir string, messages map[string]bool) error {
+	english := make(map[string]string, len(messages))
+	for message := range messages {
+		english[message] = message
+	}
+	if err := write(filepath.Join(dir, "en.json"), english); err != nil {
+		return err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if filepath.Base(file) == "en.json" {
+			continue
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var existing map[string]string
+		if err := json.Unmarshal(data, &existing); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+
+		catalog := make(map[string]string, len(messages))
+		missing, removed := 0, 0
+		for message := range messages {
+			catalog[message] = existing[message]
+			if catalog[message] == "" {
+				missing++
+			}
+		}
+		for message := range existing {
+			if !messages[message] {
+				removed++
+			}
+		}
+		if err := write(file, catalog); err != nil {
+			return err
+		}
+		fmt.Printf("%s: %d messages, %d without translation, %d removed\n", file, len(catalog), missing, removed)
+	}
+	return nil
+}
+
+// write saves a catalog sorted by message, as encoding/json sorts map keys
+func write(file string, catalog map[string]string) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(catalog); err != nil {
+		return err
+	}
+	return os.WriteFile(file, buf.Bytes(), 0644)
+}
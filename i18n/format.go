@@ -0,0 +1,136 @@
+package i18n
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrUnsupportedLocale = errors.New("unsupported locale, use a language tag such as en-US or de-DE")
+
+// Format is how a locale writes numbers, amounts and dates in documents meant for people, such
+// as CSV files opened in a spreadsheet program
+type Format struct {
+	Locale        string
+	Decimal       string // Decimal separator
+	Grouping      string // Thousands separator, none when empty
+	DateLayout    string // Layout of dates in the time package notation
+	CurrencyAfter bool   // Whether the currency symbol follows the amount
+}
+
+// PlainFormat writes numbers with a decimal point and no grouping, and ISO 8601 dates; it is
+// used when the client gives no locale
+var PlainFormat = Format{Decimal: ".", DateLayout: time.DateOnly}
+
+// formats by lower-cased language tag; a tag without one of its own uses that of its language.
+// Separators stay within Latin-1 so PDF exports can show them
+var formats = map[string]Format{
+	"en":    {Locale: "en-US", Decimal: ".", Grouping: ",", DateLayout: "01/02/2006"},
+	"en-gb": {Locale: "en-GB", Decimal: ".", Grouping: ",", DateLayout: "02/01/2006"},
+	"en-ie": {Locale: "en-IE", Decimal: ".", Grouping: ",", DateLayout: "02/01/2006"},
+	"en-au": {Locale: "en-AU", Decimal: ".", Grouping: ",", DateLayout: "02/01/2006"},
+	"en-in": {Locale: "en-IN", Decimal: ".", Grouping: ",", DateLayout: "02/01/2006"},
+	"en-ca": {Locale: "en-CA", Decimal: ".", Grouping: ",", DateLayout: "2006-01-02"},
+	"de":    {Locale: "de-DE", Decimal: ",", Grouping: ".", DateLayout: "02.01.2006", CurrencyAfter: true},
+	"de-ch": {Locale: "de-CH", Decimal: ".", Grouping: "'", DateLayout: "02.01.2006"},
+	"fr":    {Locale: "fr-FR", Decimal: ",", Grouping: "\u00a0", DateLayout: "02/01/2006", CurrencyAfter: true},
+	"fr-ch": {Locale: "fr-CH", Decimal: ".", Grouping: "'", DateLayout: "02.01.2006"},
+	"es":    {Locale: "es-ES", Decimal: ",", Grouping: ".", DateLayout: "02/01/2006", CurrencyAfter: true},
+	"it":    {Locale: "it-IT", Decimal: ",", Grouping: ".", DateLayout: "02/01/2006", CurrencyAfter: true},
+	"nl":    {Locale: "nl-NL", Decimal: ",", Grouping: ".", DateLayout: "02-01-2006"},
+	"pt":    {Locale: "pt-PT", Decimal: ",", Grouping: "\u00a0", DateLayout: "02/01/2006", CurrencyAfter: true},
+	"pt-br": {Locale: "pt-BR", Decimal: ",", Grouping: ".", DateLayout: "02/01/2006"},
+	"sv":    {Locale: "sv-SE", Decimal: ",", Grouping: "\u00a0", DateLayout: "2006-01-02", CurrencyAfter: true},
+	"ja":    {Locale: "ja-JP", Decimal: ".", Grouping: ",", DateLayout: "2006/01/02"},
+}
+
+// currencySymbols are the symbols of common currencies; others are written as their code
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+}
+
+// FormatFor returns the format of a locale given as a language tag such as "de-AT"
+func FormatFor(locale string) (Format, bool) {
+	tag := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(locale)), "_", "-")
+	if format, ok := formats[tag]; ok {
+		return format, true
+	}
+	format, ok := formats[normalize(tag)]
+	return format, ok
+}
+
+// NegotiateFormat returns the format of the locale a client prefers most from an
+// Accept-Language header, PlainFormat when none is known
+func NegotiateFormat(acceptLanguage string) Format {
+	for _, tag := range preferences(acceptLanguage) {
+		if format, ok := FormatFor(tag); ok {
+			return format
+		}
+	}
+	return PlainFormat
+}
+
+// Number writes a value with the given number of decimals
+func (f Format) Number(value float64, decimals int) string {
+	s := strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+	whole, fraction, _ := strings.Cut(s, ".")
+
+	if f.Grouping != "" && len(whole) > 3 {
+		var b strings.Builder
+		for i, digit := range whole {
+			if i > 0 && (len(whole)-i)%3 == 0 {
+				b.WriteString(f.Grouping)
+			}
+			b.WriteRune(digit)
+		}
+		whole = b.String()
+	}
+
+	if fraction != "" {
+		whole += f.Decimal + fraction
+	}
+	if value < 0 && strings.Trim(s, "0.") != "" {
+		whole = "-" + whole
+	}
+	return whole
+}
+
+// Amount writes a sum of money with two decimals, with the symbol of the currency when one is
+// given as an ISO 4217 code
+func (f Format) Amount(value float64, currency string) string {
+	amount := f.Number(value, 2)
+	if currency == "" {
+		return amount
+	}
+
+	symbol, ok := currencySymbols[currency]
+	if !ok {
+		symbol = currency
+	}
+	if f.CurrencyAfter || !ok {
+		return amount + " " + symbol
+	}
+	if strings.HasPrefix(amount, "-") {
+		return "-" + symbol + amount[1:]
+	}
+	return symbol + amount
+}
+
+// Date writes a day
+func (f Format) Date(t time.Time) string {
+	return t.Format(f.DateLayout)
+}
+
+// CSVComma returns the field separator of CSV files for the locale: spreadsheet programs
+// expect a semicolon where the comma is the decimal separator
+func (f Format) CSVComma() rune {
+	if f.Decimal == "," {
+		return ';'
+	}
+	return ','
+}
@@ -0,0 +1,127 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type languageKey struct{}
+
+// WithLanguage returns a context carrying the language messages are translated into
+func WithLanguage(ctx context.Context, language string) context.Context {
+	return context.WithValue(ctx, languageKey{}, language)
+}
+
+// Language returns the language of the context, DefaultLanguage when it has none
+func Language(ctx context.Context) string {
+	if language, ok := ctx.Value(languageKey{}).(string); ok {
+		return language
+	}
+	return DefaultLanguage
+}
+
+// T translates a message into the language of the context with the bundled catalog
+func T(ctx context.Context, message string) string {
+	return Default().Translate(Language(ctx), message)
+}
+
+// Negotiate picks the language of the catalog a client prefers from an Accept-Language header,
+// or fallback when the catalog has none of them
+func (c *Catalog) Negotiate(acceptLanguage, fallback string) string {
+	for _, tag := range preferences(acceptLanguage) {
+		if c.Supports(tag) {
+			return normalize(tag)
+		}
+	}
+	return normalize(fallback)
+}
+
+// preferences returns the language tags of an Accept-Language header, most preferred first
+func preferences(acceptLanguage string) []string {
+	type preference struct {
+		tag     string
+		quality float64
+	}
+
+	var parsed []preference
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			value, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = value
+		}
+		if quality > 0 {
+			parsed = append(parsed, preference{tag, quality})
+		}
+	}
+	sort.SliceStable(parsed, func(i, j int) bool { return parsed[i].quality > parsed[j].quality })
+
+	tags := make([]string, len(parsed))
+	for i, preference := range parsed {
+		tags[i] = preference.tag
+	}
+	return tags
+}
+
+// Middleware picks the language of each request, from the lang query parameter, the
+// Accept-Language header or else fallback, and makes it available through Language. Plain text
+// error responses, as written by http.Error, are translated on the way out
+func Middleware(catalog *Catalog, fallback string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		language := catalog.Negotiate(r.Header.Get("Accept-Language"), fallback)
+		if requested := r.URL.Query().Get("lang"); requested != "" && catalog.Supports(requested) {
+			language = normalize(requested)
+		}
+
+		w.Header().Set("Content-Language", language)
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(&translatingWriter{ResponseWriter: w, catalog: catalog, language: language}, r.WithContext(WithLanguage(r.Context(), language)))
+	})
+}
+
+// translatingWriter translates the body of plain text error responses
+type translatingWriter struct {
+	http.ResponseWriter
+	catalog   *Catalog
+	language  string
+	translate bool
+}
+
+// Unwrap exposes the wrapped ResponseWriter, so handlers can flush streamed responses
+func (tw *translatingWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
+
+func (tw *translatingWriter) WriteHeader(code int) {
+	tw.translate = code >= http.StatusBadRequest && strings.HasPrefix(tw.Header().Get("Content-Type"), "text/plain")
+	tw.ResponseWriter.WriteHeader(code)
+}
+
+// Write translates the message of an error response; http.Error writes it at once, followed by
+// a newline
+func (tw *translatingWriter) Write(b []byte) (int, error) {
+	if !tw.translate || tw.language == DefaultLanguage {
+		return tw.ResponseWriter.Write(b)
+	}
+
+	message, newline := strings.CutSuffix(string(b), "\n")
+	translated := tw.catalog.Translate(tw.language, message)
+	if newline {
+		translated += "\n"
+	}
+	if _, err := tw.ResponseWriter.Write([]byte(translated)); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
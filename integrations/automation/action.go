@@ -0,0 +1,285 @@
+package automation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-expense-tracker/app"
+	"go-expense-tracker/quickentry"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxActionBody bounds the fields of an action
+const maxActionBody = 64 << 10
+
+// Names accepted for each field of an action, the first present wins. value1 to value3 are the
+// ingredients of IFTTT webhooks
+var (
+	descriptionFields = []string{"description", "desc", "name", "title", "memo", "note", "value1"}
+	amountFields      = []string{"amount", "total", "price", "value", "value2"}
+	categoryFields    = []string{"category", "category_id", "category_name", "value3"}
+	dateFields        = []string{"date", "when", "occurred_at", "created_at"}
+	tagFields         = []string{"tags", "tag", "labels"}
+	textFields        = []string{"text", "message", "entry"}
+)
+
+// dateLayouts are the layouts a date may have; the ones after the ISO layouts are IFTTT's and
+// other common ones
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"January 2, 2006 at 03:04PM",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"01/02/2006",
+	"2006/01/02",
+}
+
+// fields are the fields of an action as text, by their lower-case name
+type fields map[string]string
+
+// get returns the first of the names present with a value
+func (f fields) get(names []string) string {
+	for _, name := range names {
+		if value := strings.TrimSpace(f[name]); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// action records an expenditure from loosely typed fields, sent as a JSON object or a form.
+// Amounts may be strings with a currency symbol or a decimal comma, dates any common layout or a
+// unix timestamp, the category an ID or a name and tags a list or a comma-separated string. A
+// `text` field in the quick-entry syntax, e.g. "12.50 lunch #food", stands in for the others. The
+// expenditure is tagged with the name of the integration
+func (h *Handler) action(w http.ResponseWriter, r *http.Request, integration string) {
+	h.logger.Info("Handling automation action request", "integration", integration, "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	f, err := readFields(r)
+	if err != nil {
+		h.logger.Warn("Invalid action fields", "error", err, "integration", integration)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	entry := &quickentry.Entry{Description: f.get(descriptionFields), Date: now}
+	if text := f.get(textFields); text != "" && f.get(amountFields) == "" {
+		parsed, err := quickentry.Parse(text, now)
+		if err != nil {
+			h.logger.Warn("Invalid action text", "error", err, "integration", integration)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		entry = parsed
+	} else {
+		amount, ok := parseAmount(f.get(amountFields))
+		if !ok {
+			h.logger.Warn("Invalid action amount", "amount", f.get(amountFields), "integration", integration)
+			http.Error(w, "amount is required and must be a number, e.g. 12.50", http.StatusBadRequest)
+			return
+		}
+		entry.Amount = amount
+	}
+	if value := f.get(dateFields); value != "" {
+		date, ok := parseDate(value, now)
+		if !ok {
+			h.logger.Warn("Invalid action date", "date", value, "integration", integration)
+			http.Error(w, "date must be a date such as 2024-06-03, today or yesterday", http.StatusBadRequest)
+			return
+		}
+		entry.Date = date
+	}
+
+	var categoryID uuid.UUID
+	var tags []string
+	category := f.get(categoryFields)
+	if id, err := uuid.Parse(category); err == nil {
+		categoryID, tags = id, entry.Hashtags
+	} else if h.categories != nil {
+		// A category unknown by name is kept as a tag rather than failing the action
+		if category != "" {
+			entry.Hashtags = append([]string{category}, entry.Hashtags...)
+		}
+		categoryID, tags, err = quickentry.Categorize(h.categories, h.rules, entry, uuid.Nil)
+		if errors.Is(err, quickentry.ErrNoDefaultCategory) {
+			categoryID, tags = uuid.Nil, entry.Hashtags
+		} else if err != nil {
+			h.logger.Error("Failed to resolve category", "error", err, "integration", integration)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	} else {
+		tags = entry.Hashtags
+	}
+	tags = append(tags, splitTags(f.get(tagFields))...)
+	tags = append(tags, integration)
+
+	result, err := h.expenditures.Create(app.ExpenditureInput{
+		Description: entry.Description,
+		Amount:      entry.Amount,
+		Date:        entry.Date,
+		CategoryId:  categoryID,
+		Tags:        tags,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch app.KindOf(err) {
+		case app.KindInvalid, app.KindNotFound:
+			status = http.StatusBadRequest
+		case app.KindRejected:
+			status = http.StatusUnprocessableEntity
+		case app.KindConflict:
+			status = http.StatusConflict
+		case app.KindUnavailable:
+			status = http.StatusServiceUnavailable
+		}
+		h.logger.Warn("Failed to record expenditure from automation", "error", err, "integration", integration)
+		if status == http.StatusInternalServerError {
+			http.Error(w, "Internal server error", status)
+		} else {
+			http.Error(w, err.Error(), status)
+		}
+		return
+	}
+
+	// The expenditure is saved, so a failure to name its category only leaves the name out
+	categories, err := h.categoryNames()
+	if err != nil {
+		h.logger.Error("Failed to get categories for action", "error", err)
+	}
+	expenditure := result.Expenditure
+	h.logger.Info("Recorded expenditure from automation", "id", expenditure.ID, "integration", integration, "amount", expenditure.Amount)
+	writeJSON(w, http.StatusCreated, record(expenditure, categories, 0, now))
+}
+
+// readFields reads the fields of an action from a JSON object, or from a form when the body is
+// not one. Numbers, booleans and lists are turned into text, nested objects are left out
+func readFields(r *http.Request) (fields, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxActionBody+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading body: %w", err)
+	}
+	if len(body) > maxActionBody {
+		return nil, fmt.Errorf("body is larger than %d bytes", maxActionBody)
+	}
+
+	f := make(fields)
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		var object map[string]any
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		decoder.UseNumber()
+		if err := decoder.Decode(&object); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		for name, value := range object {
+			if text, ok := fieldText(value); ok {
+				f[strings.ToLower(strings.TrimSpace(name))] = text
+			}
+		}
+		return f, nil
+	}
+
+	values, err := url.ParseQuery(string(trimmed))
+	if err != nil {
+		return nil, errors.New("body must be a JSON object or a form")
+	}
+	for name := range values {
+		f[strings.ToLower(strings.TrimSpace(name))] = values.Get(name)
+	}
+	return f, nil
+}
+
+// fieldText returns a JSON value as text
+func fieldText(value any) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", true
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			if text, ok := fieldText(item); ok && text != "" {
+				items = append(items, text)
+			}
+		}
+		return strings.Join(items, ","), true
+	}
+	return "", false
+}
+
+// parseAmount reads an amount with or without a currency symbol or code, thousands separators
+// and a decimal point or comma, e.g. "$1,234.50", "1.234,50 €" or "12.5 USD". Signs are dropped,
+// as bank feeds send spending as negative amounts
+func parseAmount(value string) (float64, bool) {
+	cleaned := strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '.' || r == ',' {
+			return r
+		}
+		return -1
+	}, value)
+
+	// The last separator is the decimal one when both are present
+	if strings.Contains(cleaned, ".") && strings.Contains(cleaned, ",") {
+		if strings.LastIndex(cleaned, ",") < strings.LastIndex(cleaned, ".") {
+			cleaned = strings.ReplaceAll(cleaned, ",", "")
+		} else {
+			cleaned = strings.ReplaceAll(cleaned, ".", "")
+		}
+	}
+	return quickentry.ParseAmount(cleaned)
+}
+
+// parseDate reads today, yesterday, an ISO date or time, a unix timestamp in seconds or
+// milliseconds or a date in one of dateLayouts, taken in the local zone without an offset
+func parseDate(value string, now time.Time) (time.Time, bool) {
+	switch strings.ToLower(value) {
+	case "today", "now":
+		return now, true
+	case "yesterday":
+		return now.AddDate(0, 0, -1), true
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		// Timestamps past the year 5138 in seconds are milliseconds
+		if seconds > 1e11 {
+			return time.UnixMilli(seconds), true
+		}
+		return time.Unix(seconds, 0), true
+	}
+	for _, layout := range dateLayouts {
+		if date, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// splitTags reads tags separated by commas, with or without leading hashes
+func splitTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimPrefix(strings.TrimSpace(tag), "#"); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
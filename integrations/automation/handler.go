@@ -0,0 +1,138 @@
+package automation
+
+import (
+	"encoding/json"
+	"go-expense-tracker/activity"
+	"go-expense-tracker/app"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/handlers"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Record is an expenditure as a flat record of plain values, as automation services map fields
+// one to one and cannot reach into nested objects
+type Record struct {
+	ID          string    `json:"id"`               // Automation services skip records whose ID they have seen
+	Cursor      int64     `json:"cursor,omitempty"` // Pass the highest one back as ?cursor= for what is newer
+	AddedAt     time.Time `json:"added_at"`
+	Description string    `json:"description"`
+	Amount      float64   `json:"amount"`
+	Date        string    `json:"date"` // YYYY-MM-DD
+	CategoryID  string    `json:"category_id"`
+	Category    string    `json:"category"`
+	Tags        string    `json:"tags"` // Comma-separated
+	Status      string    `json:"status"`
+	MerchantID  string    `json:"merchant_id"`
+	AccountID   string    `json:"account_id"`
+	// Amount and currency paid abroad, 0 and empty in the home currency
+	OriginalAmount   float64 `json:"original_amount"`
+	OriginalCurrency string  `json:"original_currency"`
+}
+
+// Handler serves the trigger and action endpoints of the automation integrations
+type Handler struct {
+	keys         Keys
+	expenditures *app.ExpenditureService
+	repository   domain.ExpenditureRepository
+	categories   domain.CategoryRepository
+	rules        domain.CategorizationRuleRepository
+	feed         *activity.Feed
+	logger       *slog.Logger
+}
+
+// NewHandler creates a new Handler; categories may be nil when the storage has no category
+// support and rules when it keeps no categorization rules. New expenditures are found in the
+// activity feed, so the trigger lists those added since the feed began and while they are in it
+func NewHandler(keys Keys, expenditures *app.ExpenditureService, repository domain.ExpenditureRepository, categories domain.CategoryRepository, rules domain.CategorizationRuleRepository, feed *activity.Feed, logger *slog.Logger) *Handler {
+	return &Handler{
+		keys:         keys,
+		expenditures: expenditures,
+		repository:   repository,
+		categories:   categories,
+		rules:        rules,
+		feed:         feed,
+		logger:       logger,
+	}
+}
+
+// ServeHTTP authenticates the integration and routes /integrations/automation/me, which services
+// call to test their key, and /integrations/automation/expenditures, the trigger on GET and the
+// action on POST. OPTIONS needs no key, as browsers send CORS preflights without one
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var integration string
+	if r.Method != http.MethodOptions {
+		var ok bool
+		if integration, ok = h.keys.Authenticate(r); !ok {
+			h.logger.Warn("Rejected automation request without a valid API key", "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+			http.Error(w, "Invalid or missing API key", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	switch strings.TrimSuffix(r.URL.Path, "/") {
+	case "/integrations/automation/me":
+		handlers.Methods{http.MethodGet: func(w http.ResponseWriter, r *http.Request) {
+			h.logger.Info("Authenticated automation integration", "integration", integration)
+			writeJSON(w, http.StatusOK, map[string]string{"integration": integration})
+		}}.ServeHTTP(w, r)
+	case "/integrations/automation/expenditures":
+		handlers.Methods{
+			http.MethodGet:  func(w http.ResponseWriter, r *http.Request) { h.trigger(w, r, integration) },
+			http.MethodPost: func(w http.ResponseWriter, r *http.Request) { h.action(w, r, integration) },
+		}.ServeHTTP(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// categoryNames returns the names of the categories by ID, empty when categories are unsupported
+func (h *Handler) categoryNames() (map[uuid.UUID]string, error) {
+	names := make(map[uuid.UUID]string)
+	if h.categories == nil {
+		return names, nil
+	}
+	categories, err := h.categories.GetAllCategories()
+	if err != nil {
+		return nil, err
+	}
+	for _, category := range categories {
+		names[category.ID] = category.Name
+	}
+	return names, nil
+}
+
+// record flattens an expenditure
+func record(expenditure *domain.Expenditure, categories map[uuid.UUID]string, cursor int64, addedAt time.Time) Record {
+	r := Record{
+		ID:               expenditure.ID.String(),
+		Cursor:           cursor,
+		AddedAt:          addedAt,
+		Description:      expenditure.Description,
+		Amount:           expenditure.Amount,
+		Date:             expenditure.Date.Format("2006-01-02"),
+		CategoryID:       expenditure.CategoryId.String(),
+		Category:         categories[expenditure.CategoryId],
+		Tags:             strings.Join(expenditure.Tags, ", "),
+		Status:           string(expenditure.Status),
+		OriginalAmount:   expenditure.OriginalAmount,
+		OriginalCurrency: expenditure.OriginalCurrency,
+	}
+	if expenditure.MerchantId != uuid.Nil {
+		r.MerchantID = expenditure.MerchantId.String()
+	}
+	if expenditure.AccountId != uuid.Nil {
+		r.AccountID = expenditure.AccountId.String()
+	}
+	return r
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,76 @@
+package automation
+
+import (
+	"go-expense-tracker/handlers"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandlerMethods(t *testing.T) {
+	const key = "0123456789abcdef"
+	handler := NewHandler(Keys{"zapier": key}, nil, nil, nil, nil, nil, slog.New(slog.DiscardHandler))
+
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		key       string
+		want      int
+		wantAllow string
+	}{
+		{"preflight without a key", http.MethodOptions, "/integrations/automation/expenditures", "", http.StatusNoContent, "GET, HEAD, OPTIONS, POST"},
+		{"preflight of me", http.MethodOptions, "/integrations/automation/me", "", http.StatusNoContent, "GET, HEAD, OPTIONS"},
+		{"get without a key", http.MethodGet, "/integrations/automation/me", "", http.StatusUnauthorized, ""},
+		{"get", http.MethodGet, "/integrations/automation/me", key, http.StatusOK, ""},
+		{"head", http.MethodHead, "/integrations/automation/me", key, http.StatusOK, ""},
+		{"unsupported method", http.MethodDelete, "/integrations/automation/expenditures", key, http.StatusMethodNotAllowed, "GET, HEAD, OPTIONS, POST"},
+		{"unsupported method without a key", http.MethodDelete, "/integrations/automation/expenditures", "", http.StatusUnauthorized, ""},
+		{"unknown path", http.MethodGet, "/integrations/automation/unknown", key, http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+		})
+	}
+}
+
+func TestHandlerPreflight(t *testing.T) {
+	handler := handlers.CORSMiddleware([]string{"https://zapier.com"}, NewHandler(Keys{"zapier": "0123456789abcdef"}, nil, nil, nil, nil, nil, slog.New(slog.DiscardHandler)))
+
+	// Browsers send preflights without the key they are asking to send
+	req := httptest.NewRequest(http.MethodOptions, "/integrations/automation/expenditures", nil)
+	req.Header.Set("Origin", "https://zapier.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "Content-Type, X-API-Key")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	want := map[string]string{
+		"Access-Control-Allow-Origin":  "https://zapier.com",
+		"Access-Control-Allow-Methods": "GET, HEAD, OPTIONS, POST",
+		"Access-Control-Allow-Headers": "Content-Type, X-API-Key",
+	}
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	for header, value := range want {
+		if got := rec.Header().Get(header); got != value {
+			t.Errorf("%s = %q, want %q", header, got, value)
+		}
+	}
+}
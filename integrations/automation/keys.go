@@ -0,0 +1,66 @@
+// Package automation serves a simple integration surface for automation services such as Zapier
+// and IFTTT: a polling trigger listing the expenditures added since a cursor as flat records, and
+// a forgiving action recording an expenditure from loosely typed fields. Every integration has an
+// API key of its own, so one can be revoked without breaking the others.
+package automation
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var ErrInvalidKeys = errors.New("automation API keys are name=key, e.g. zapier=3f9a2c...,ifttt=77c1e0...")
+
+// minKeyLength keeps guessable keys out of the configuration
+const minKeyLength = 16
+
+// Keys are the API keys of the integrations, by the name of the integration
+type Keys map[string]string
+
+// ParseKeys reads comma-separated name=key pairs such as "zapier=3f9a2c...,ifttt=77c1e0...";
+// names are lower-cased and keys must be at least 16 characters long
+func ParseKeys(config string) (Keys, error) {
+	keys := make(Keys)
+	for _, entry := range strings.Split(config, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+
+		name, key, found := strings.Cut(entry, "=")
+		name, key = strings.ToLower(strings.TrimSpace(name)), strings.TrimSpace(key)
+		if !found || name == "" || len(key) < minKeyLength || strings.ContainsAny(name, " \t") {
+			return nil, ErrInvalidKeys
+		}
+		if _, ok := keys[name]; ok {
+			return nil, ErrInvalidKeys
+		}
+		keys[name] = key
+	}
+	return keys, nil
+}
+
+// Authenticate returns the integration whose key the request carries, in the X-API-Key header, as
+// a bearer token or, for services that cannot set headers, in the api_key query parameter. All
+// keys are compared in constant time
+func (k Keys) Authenticate(r *http.Request) (string, bool) {
+	key := r.Header.Get("X-API-Key")
+	if key == "" {
+		key, _ = strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	}
+	if key == "" {
+		key = r.URL.Query().Get("api_key")
+	}
+	if key == "" {
+		return "", false
+	}
+
+	integration := ""
+	for name, expected := range k {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(expected)) == 1 {
+			integration = name
+		}
+	}
+	return integration, integration != ""
+}
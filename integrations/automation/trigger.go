@@ -0,0 +1,69 @@
+package automation
+
+import (
+	"fmt"
+	"go-expense-tracker/domain"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultTriggerSize = 50
+	maxTriggerSize     = 100 // Zapier reads no more than the first 100 records of a poll
+)
+
+// trigger lists the expenditures added since `cursor`, newest first, as a bare array of flat
+// records, the shape polling triggers expect. Without a cursor it lists the latest additions,
+// which services sample when a trigger is set up. Expenditures added by imports are not listed,
+// nor those deleted since
+func (h *Handler) trigger(w http.ResponseWriter, r *http.Request, integration string) {
+	h.logger.Info("Handling automation trigger request", "integration", integration, "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	query := r.URL.Query()
+
+	limit := defaultTriggerSize
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxTriggerSize {
+			h.logger.Warn("Invalid trigger size", "limit", value)
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", maxTriggerSize), http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	var cursor int64
+	if value := query.Get("cursor"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 0 {
+			h.logger.Warn("Invalid cursor", "cursor", value)
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+		cursor = parsed
+	}
+
+	categories, err := h.categoryNames()
+	if err != nil {
+		h.logger.Error("Failed to get categories for trigger", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	records := []Record{}
+	for _, activity := range h.feed.After(cursor, domain.ActivityExpenditureAdded, limit) {
+		expenditure, err := h.repository.GetExpenditureByID(activity.SubjectID.String())
+		if err == domain.ErrExpenditureNotFound {
+			continue
+		}
+		if err != nil {
+			h.logger.Error("Failed to get expenditure for trigger", "error", err, "id", activity.SubjectID)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		records = append(records, record(expenditure, categories, activity.Seq, activity.At))
+	}
+
+	h.logger.Info("Successfully polled expenditures", "integration", integration, "cursor", cursor, "count", len(records))
+	writeJSON(w, http.StatusOK, records)
+}
@@ -0,0 +1,25 @@
+// Package banking pulls transactions from bank and open-banking providers into the
+// import review queue.
+package banking
+
+import (
+	"context"
+	"go-expense-tracker/domain"
+	"time"
+)
+
+// Transaction is a bank transaction as reported by a connector
+type Transaction struct {
+	ID          string    // Provider transaction ID, used to avoid staging the same transaction twice
+	Description string    // Merchant or transaction name
+	Amount      float64   // Positive for money spent, negative for money received
+	Date        time.Time // Booking or authorization date
+	Pending     bool      // Whether the transaction has not settled yet
+}
+
+// BankConnector fetches transactions for a bank connection. Implementations are incremental:
+// they receive the cursor returned by the previous call and return the next one
+type BankConnector interface {
+	Name() string
+	FetchTransactions(ctx context.Context, connection *domain.BankConnection) (transactions []Transaction, nextCursor string, err error)
+}
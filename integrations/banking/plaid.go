@@ -0,0 +1,148 @@
+package banking
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker/domain"
+	"net/http"
+	"time"
+)
+
+var plaidEnvironments = map[string]string{
+	"sandbox":     "https://sandbox.plaid.com",
+	"development": "https://development.plaid.com",
+	"production":  "https://production.plaid.com",
+}
+
+// PlaidConnector pulls transactions through the Plaid /transactions/sync API
+type PlaidConnector struct {
+	clientID string
+	secret   string
+	baseURL  string
+	client   *http.Client
+}
+
+// NewPlaidConnector creates a PlaidConnector for the given environment (sandbox, development or production)
+func NewPlaidConnector(clientID, secret, environment string) (*PlaidConnector, error) {
+	baseURL, ok := plaidEnvironments[environment]
+	if !ok {
+		return nil, fmt.Errorf("unknown plaid environment %q", environment)
+	}
+
+	return &PlaidConnector{
+		clientID: clientID,
+		secret:   secret,
+		baseURL:  baseURL,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (p *PlaidConnector) Name() string {
+	return "plaid"
+}
+
+type plaidSyncRequest struct {
+	ClientID    string `json:"client_id"`
+	Secret      string `json:"secret"`
+	AccessToken string `json:"access_token"`
+	Cursor      string `json:"cursor,omitempty"`
+	Count       int    `json:"count"`
+}
+
+type plaidTransaction struct {
+	TransactionID string  `json:"transaction_id"`
+	Name          string  `json:"name"`
+	MerchantName  string  `json:"merchant_name"`
+	Amount        float64 `json:"amount"`
+	Date          string  `json:"date"`
+	Pending       bool    `json:"pending"`
+}
+
+type plaidSyncResponse struct {
+	Added      []plaidTransaction `json:"added"`
+	NextCursor string             `json:"next_cursor"`
+	HasMore    bool               `json:"has_more"`
+}
+
+type plaidError struct {
+	ErrorCode    string `json:"error_code"`
+	ErrorMessage string `json:"error_message"`
+}
+
+// FetchTransactions pages through /transactions/sync until no more updates are available.
+// Only added transactions are returned, modifications and removals are left to the review queue
+func (p *PlaidConnector) FetchTransactions(ctx context.Context, connection *domain.BankConnection) ([]Transaction, string, error) {
+	var transactions []Transaction
+	cursor := connection.Cursor
+
+	for {
+		resp, err := p.sync(ctx, connection.AccessToken, cursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		for _, t := range resp.Added {
+			date, err := time.Parse("2006-01-02", t.Date)
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid plaid transaction date %q: %w", t.Date, err)
+			}
+
+			description := t.MerchantName
+			if description == "" {
+				description = t.Name
+			}
+
+			transactions = append(transactions, Transaction{
+				ID:          t.TransactionID,
+				Description: description,
+				Amount:      t.Amount, // Plaid reports money leaving the account as positive
+				Date:        date,
+				Pending:     t.Pending,
+			})
+		}
+
+		cursor = resp.NextCursor
+		if !resp.HasMore {
+			return transactions, cursor, nil
+		}
+	}
+}
+
+func (p *PlaidConnector) sync(ctx context.Context, accessToken, cursor string) (*plaidSyncResponse, error) {
+	body, err := json.Marshal(plaidSyncRequest{
+		ClientID:    p.clientID,
+		Secret:      p.secret,
+		AccessToken: accessToken,
+		Cursor:      cursor,
+		Count:       500,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding plaid request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/transactions/sync", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating plaid request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling plaid: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var perr plaidError
+		json.NewDecoder(resp.Body).Decode(&perr)
+		return nil, fmt.Errorf("plaid error (status %d): %s %s", resp.StatusCode, perr.ErrorCode, perr.ErrorMessage)
+	}
+
+	var syncResp plaidSyncResponse
+	if err := json.NewDecoder(resp.Body).Decode(&syncResp); err != nil {
+		return nil, fmt.Errorf("error decoding plaid response: %w", err)
+	}
+	return &syncResp, nil
+}
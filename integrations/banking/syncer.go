@@ -0,0 +1,164 @@
+package banking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var ErrUnknownConnector = errors.New("unknown bank connector")
+
+// Syncer periodically pulls transactions of every bank connection into the import review queue
+type Syncer struct {
+	connectors  map[string]BankConnector
+	connections domain.BankConnectionRepository
+	imports     domain.ImportRepository
+	interval    time.Duration
+	logger      *slog.Logger
+	enabled     func() bool // Scheduled syncs are skipped while it returns false, nil when always on
+	mu          sync.Mutex  // Serializes scheduled and manual syncs
+}
+
+// NewSyncer creates a new Syncer running every interval with the given connectors
+func NewSyncer(connections domain.BankConnectionRepository, imports domain.ImportRepository, interval time.Duration, logger *slog.Logger, connectors ...BankConnector) *Syncer {
+	byName := make(map[string]BankConnector, len(connectors))
+	for _, connector := range connectors {
+		byName[connector.Name()] = connector
+	}
+
+	return &Syncer{
+		connectors:  byName,
+		connections: connections,
+		imports:     imports,
+		interval:    interval,
+		logger:      logger,
+	}
+}
+
+// HasConnector reports whether a connector with the given name is configured
+func (s *Syncer) HasConnector(name string) bool {
+	_, ok := s.connectors[name]
+	return ok
+}
+
+// SetEnabled makes scheduled syncs skip while enabled returns false, e.g. while a feature flag
+// is off; manual syncs are not affected
+func (s *Syncer) SetEnabled(enabled func() bool) {
+	s.enabled = enabled
+}
+
+// Run syncs all connections every interval until the context is cancelled
+func (s *Syncer) Run(ctx context.Context) {
+	s.logger.Info("Starting bank sync scheduler", "interval", s.interval.String(), "connectors", len(s.connectors))
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if s.enabled == nil || s.enabled() {
+			s.SyncAll(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Stopping bank sync scheduler")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncAll syncs every bank connection, logging failures per connection
+func (s *Syncer) SyncAll(ctx context.Context) {
+	connections, err := s.connections.GetAllBankConnections()
+	if err != nil {
+		s.logger.Error("Failed to get bank connections for sync", "error", err)
+		return
+	}
+
+	for _, connection := range connections {
+		if _, err := s.SyncConnection(ctx, connection); err != nil {
+			s.logger.Error("Bank connection sync failed", "error", err, "id", connection.ID)
+		}
+	}
+}
+
+// SyncConnection pulls new transactions of one connection into the import review queue
+// and returns how many were staged
+func (s *Syncer) SyncConnection(ctx context.Context, connection *domain.BankConnection) (int, error) {
+	s.logger.Debug("Syncing bank connection", "id", connection.ID, "connector", connection.Connector)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	connector, ok := s.connectors[connection.Connector]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownConnector, connection.Connector)
+	}
+
+	transactions, cursor, err := connector.FetchTransactions(ctx, connection)
+	if err != nil {
+		connection.LastError = err.Error()
+		if updateErr := s.connections.UpdateBankConnection(connection); updateErr != nil {
+			s.logger.Error("Failed to record bank sync error", "error", updateErr, "id", connection.ID)
+		}
+		return 0, err
+	}
+
+	staged, err := s.stage(connection, transactions)
+	if err != nil {
+		return staged, err
+	}
+
+	now := time.Now()
+	connection.Cursor = cursor
+	connection.LastSyncedAt = &now
+	connection.LastError = ""
+	if err := s.connections.UpdateBankConnection(connection); err != nil {
+		return staged, fmt.Errorf("error saving bank connection cursor: %w", err)
+	}
+
+	s.logger.Info("Bank connection synced", "id", connection.ID, "fetched", len(transactions), "staged", staged)
+	return staged, nil
+}
+
+// stage adds spending transactions to the import review queue, skipping ones already staged
+func (s *Syncer) stage(connection *domain.BankConnection, transactions []Transaction) (int, error) {
+	existing, err := s.imports.GetAllStagedExpenditures()
+	if err != nil {
+		return 0, fmt.Errorf("error getting staged expenditures: %w", err)
+	}
+
+	seen := make(map[string]bool, len(existing))
+	for _, staged := range existing {
+		if staged.Source == connection.Connector {
+			seen[staged.Reference] = true
+		}
+	}
+
+	count := 0
+	for _, t := range transactions {
+		// Incoming money is not an expenditure
+		if t.Amount <= 0 || seen[t.ID] {
+			continue
+		}
+
+		staged, err := domain.NewStagedExpenditure(connection.Connector, t.ID, t.Description, t.Amount, t.Date, t.Description)
+		if err != nil {
+			return count, err
+		}
+
+		if err := s.imports.AddStagedExpenditure(staged); err != nil {
+			return count, fmt.Errorf("error staging transaction %s: %w", t.ID, err)
+		}
+
+		seen[t.ID] = true
+		count++
+	}
+
+	return count, nil
+}
@@ -0,0 +1,58 @@
+package email
+
+import (
+	"fmt"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// InvitationMailer emails household invitations through an SMTP server
+type InvitationMailer struct {
+	addr   string
+	auth   smtp.Auth
+	from   string
+	logger *slog.Logger
+}
+
+// NewInvitationMailer creates a new InvitationMailer sending from the address from through the
+// SMTP server at host:port, authenticating when a username is given
+func NewInvitationMailer(host, port, username, password, from string, logger *slog.Logger) *InvitationMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &InvitationMailer{
+		addr:   net.JoinHostPort(host, port),
+		auth:   auth,
+		from:   from,
+		logger: logger,
+	}
+}
+
+// SendInvitation emails the token of an invitation to the address it was issued for
+func (m *InvitationMailer) SendInvitation(household *domain.Household, invitation *domain.Invitation, token string) error {
+	if strings.ContainsAny(invitation.Email, "\r\n") || strings.ContainsAny(household.Name, "\r\n") {
+		return fmt.Errorf("invalid invitation email address or household name")
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", m.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", invitation.Email)
+	fmt.Fprintf(&msg, "Subject: You are invited to join %s\r\n", household.Name)
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&msg, "You are invited to join the household %q as %s.\r\n\r\n", household.Name, invitation.Role)
+	fmt.Fprintf(&msg, "Accept the invitation with POST /invitations/%s/accept and your name, e.g. {\"name\": \"Alex\"}.\r\n", token)
+	fmt.Fprintf(&msg, "The invitation expires on %s.\r\n", invitation.ExpiresAt.UTC().Format("2006-01-02 15:04 MST"))
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{invitation.Email}, []byte(msg.String())); err != nil {
+		return fmt.Errorf("sending invitation email: %w", err)
+	}
+
+	m.logger.Info("Sent invitation email", "id", invitation.ID, "household_id", household.ID)
+	return nil
+}
@@ -0,0 +1,128 @@
+package email
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Source is recorded on staged expenditures created from inbound email
+const Source = "email"
+
+// maxRawTextLength bounds how much of an email body is kept for review
+const maxRawTextLength = 4000
+
+// MailgunHandler receives inbound emails forwarded by a Mailgun route and stages them
+// as draft expenditures in the import review queue
+type MailgunHandler struct {
+	signingKey string
+	imports    domain.ImportRepository
+	logger     *slog.Logger
+}
+
+// NewMailgunHandler creates a new MailgunHandler verifying webhooks with the given signing key
+func NewMailgunHandler(signingKey string, imports domain.ImportRepository, logger *slog.Logger) *MailgunHandler {
+	return &MailgunHandler{
+		signingKey: signingKey,
+		imports:    imports,
+		logger:     logger,
+	}
+}
+
+func (h *MailgunHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling inbound email request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Mailgun posts multipart forms when attachments are present and urlencoded forms otherwise
+	err := r.ParseMultipartForm(10 << 20)
+	if err != nil && err != http.ErrNotMultipart {
+		h.logger.Error("Failed to parse inbound email form", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if !h.verify(r.FormValue("timestamp"), r.FormValue("token"), r.FormValue("signature")) {
+		h.logger.Warn("Rejected inbound email with invalid signature", "remote_addr", r.RemoteAddr)
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	messageID := r.FormValue("Message-Id")
+	subject := r.FormValue("subject")
+	from := r.FormValue("from")
+	body := r.FormValue("body-plain")
+
+	h.logger.Debug("Decoded inbound email", "message_id", messageID, "from", from, "subject", subject)
+
+	// Mailgun retries deliveries, so skip emails that are already queued
+	if messageID != "" {
+		existing, err := h.imports.GetAllStagedExpenditures()
+		if err != nil {
+			h.logger.Error("Failed to check for duplicate email", "error", err, "message_id", messageID)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, staged := range existing {
+			if staged.Source == Source && staged.Reference == messageID {
+				h.logger.Info("Ignoring duplicate inbound email", "message_id", messageID, "id", staged.ID)
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+		}
+	}
+
+	date := time.Now()
+	if header := r.FormValue("Date"); header != "" {
+		if parsed, err := mail.ParseDate(header); err == nil && parsed.Before(date) {
+			date = parsed
+		}
+	}
+
+	receipt := ParseReceipt(subject, from, body)
+
+	rawText := body
+	if len(rawText) > maxRawTextLength {
+		rawText = strings.ToValidUTF8(rawText[:maxRawTextLength], "")
+	}
+
+	staged, err := domain.NewStagedExpenditure(Source, messageID, receipt.Description, receipt.Amount, date, rawText)
+	if err != nil {
+		h.logger.Error("Failed to create staged expenditure", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.imports.AddStagedExpenditure(staged)
+	if err != nil {
+		h.logger.Error("Failed to stage inbound email", "error", err, "message_id", messageID)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Staged expenditure from inbound email", "id", staged.ID, "message_id", messageID, "amount", staged.Amount)
+	w.WriteHeader(http.StatusOK)
+}
+
+// verify checks the Mailgun webhook signature, HMAC-SHA256(timestamp + token)
+func (h *MailgunHandler) verify(timestamp, token, signature string) bool {
+	if timestamp == "" || token == "" || signature == "" {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(h.signingKey))
+	mac.Write([]byte(timestamp + token))
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,136 @@
+// Package email turns inbound emails such as forwarded receipts and order confirmations
+// into entries of the import review queue.
+package email
+
+import (
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Receipt is what could be recognized in a receipt email
+type Receipt struct {
+	Description string
+	Amount      float64 // 0 when no amount could be found
+}
+
+// totalKeywords are tried in order, the most specific first
+var totalKeywords = []string{
+	"grand total",
+	"order total",
+	"total charged",
+	"amount charged",
+	"amount paid",
+	"total paid",
+	"total",
+	"amount",
+}
+
+var amountPattern = regexp.MustCompile(`(?:[$€£]|USD|EUR|GBP)?\s*(\d{1,3}(?:[.,]\d{3})+(?:[.,]\d{2})?|\d+(?:[.,]\d{1,2})?)`)
+
+var subjectPrefixPattern = regexp.MustCompile(`(?i)^\s*((fwd?|fw|re|aw|wg)\s*:\s*)+`)
+
+// ParseReceipt extracts a description and the total amount from a receipt email
+func ParseReceipt(subject, from, body string) Receipt {
+	description := strings.TrimSpace(subjectPrefixPattern.ReplaceAllString(subject, ""))
+	if description == "" {
+		description = senderName(from)
+	}
+
+	return Receipt{
+		Description: description,
+		Amount:      findTotal(body),
+	}
+}
+
+// findTotal looks for the amount on the line carrying the most specific total keyword
+func findTotal(body string) float64 {
+	lines := strings.Split(body, "\n")
+
+	for _, keyword := range totalKeywords {
+		for _, line := range lines {
+			idx := keywordIndex(line, keyword)
+			if idx < 0 {
+				continue
+			}
+
+			// Only consider amounts after the keyword, e.g. "Total (2 items): $12.50"
+			if amount, ok := firstAmount(line[idx+len(keyword):]); ok {
+				return amount
+			}
+		}
+	}
+
+	return 0
+}
+
+// keywordIndex finds keyword in line in any case, at the start of a word so "total" does not
+// match "subtotal". The index is one of line itself: lowercasing may change the length of a
+// line, so it is not searched in a lowercased copy
+func keywordIndex(line, keyword string) int {
+	for idx := 0; idx+len(keyword) <= len(line); idx++ {
+		if !strings.EqualFold(line[idx:idx+len(keyword)], keyword) {
+			continue
+		}
+		if before, _ := utf8.DecodeLastRuneInString(line[:idx]); idx == 0 || !unicode.IsLetter(before) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// firstAmount prefers amounts that look like money (currency or cents) over bare numbers,
+// so "Total (2 items): $12.50" yields 12.50 rather than 2
+func firstAmount(s string) (float64, bool) {
+	var fallback float64
+	for _, match := range amountPattern.FindAllStringSubmatch(s, -1) {
+		amount, ok := normalizeAmount(match[1])
+		if !ok || amount <= 0 {
+			continue
+		}
+
+		hasCurrency := strings.TrimSpace(match[0]) != match[1]
+		hasCents := strings.LastIndexAny(match[1], ".,") == len(match[1])-3
+		if hasCurrency || hasCents {
+			return amount, true
+		}
+
+		if fallback == 0 {
+			fallback = amount
+		}
+	}
+	return fallback, fallback > 0
+}
+
+// normalizeAmount handles both 1,234.56 and 1.234,56 styles
+func normalizeAmount(s string) (float64, bool) {
+	lastSep := strings.LastIndexAny(s, ".,")
+	if lastSep >= 0 && len(s)-lastSep-1 <= 2 {
+		// The last separator is the decimal point
+		integer := strings.NewReplacer(".", "", ",", "").Replace(s[:lastSep])
+		s = integer + "." + s[lastSep+1:]
+	} else {
+		s = strings.NewReplacer(".", "", ",", "").Replace(s)
+	}
+
+	amount, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return amount, true
+}
+
+// senderName returns the display name of an address, or the address itself
+func senderName(from string) string {
+	addr, err := mail.ParseAddress(from)
+	if err != nil {
+		return strings.TrimSpace(from)
+	}
+	if addr.Name != "" {
+		return addr.Name
+	}
+	return addr.Address
+}
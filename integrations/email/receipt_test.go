@@ -0,0 +1,42 @@
+package email
+
+import "testing"
+
+func TestParseReceipt(t *testing.T) {
+	tests := []struct {
+		name       string
+		subject    string
+		body       string
+		wantAmount float64
+	}{
+		{"total with currency", "Fwd: Your order", "Subtotal: $10.00\nTotal (2 items): $12.50", 12.50},
+		{"specific keyword first", "Receipt", "Total: 3.00\nGrand total: EUR 1.234,56", 1234.56},
+		{"keyword in other case", "Receipt", "TOTAL 8,90", 8.90},
+		{"subtotal is not a total", "Receipt", "Subtotal 4.00", 0},
+		// Lowercasing Ⱥ makes it a byte longer, which once shifted the index of the keyword
+		{"letters growing when lowercased", "Receipt", "ȺȺȺȺȺȺȺȺ total 12.50", 12.50},
+		{"letter before the keyword", "Receipt", "Ⱥtotal 5.00", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseReceipt(tt.subject, "Shop <shop@example.com>", tt.body)
+			if got.Amount != tt.wantAmount {
+				t.Errorf("amount = %v, want %v", got.Amount, tt.wantAmount)
+			}
+		})
+	}
+}
+
+// FuzzParseReceipt parses arbitrary emails: whatever a sender writes, the parser must not
+// panic and must not find a negative amount
+func FuzzParseReceipt(f *testing.F) {
+	f.Add("Fwd: Your order", "Shop <shop@example.com>", "Subtotal: $10.00\nTotal (2 items): $12.50")
+	f.Add("Receipt", "shop@example.com", "Grand total: EUR 1.234,56")
+	f.Add("", "", "")
+
+	f.Fuzz(func(t *testing.T, subject, from, body string) {
+		if receipt := ParseReceipt(subject, from, body); receipt.Amount < 0 {
+			t.Errorf("ParseReceipt(%q, %q, %q) amount = %v, want at least 0", subject, from, body, receipt.Amount)
+		}
+	})
+}
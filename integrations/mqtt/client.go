@@ -0,0 +1,357 @@
+// Package mqtt publishes changes to expenditures and budgets to an MQTT broker, for home
+// automation and dashboards built around MQTT. It speaks the part of MQTT 3.1.1 a publisher
+// needs: connecting with credentials, publishing at QoS 0 or 1 and keeping the connection alive.
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidBroker   = errors.New("MQTT broker URL must be mqtt://host:port or mqtts://host:port")
+	ErrUnsupportedQoS  = errors.New("MQTT QoS must be 0 or 1")
+	ErrInvalidTopic    = errors.New("MQTT topic must not be empty or contain the wildcards + and #")
+	ErrConnectRefused  = errors.New("MQTT broker refused the connection")
+	ErrUnexpectedReply = errors.New("unexpected MQTT packet")
+)
+
+// Packet types of MQTT 3.1.1, in the upper four bits of the first byte
+const (
+	packetConnect    = 1
+	packetConnAck    = 2
+	packetPublish    = 3
+	packetPubAck     = 4
+	packetPingReq    = 12
+	packetPingResp   = 13
+	packetDisconnect = 14
+)
+
+// replyTimeout bounds the wait for the broker to acknowledge a connection, publish or ping
+const replyTimeout = 10 * time.Second
+
+// connectRefusals are the reasons of the return codes of a refused connection
+var connectRefusals = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "client identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+// Config is the connection to the broker and how events are published
+type Config struct {
+	Broker    string        // mqtt://host:1883, or mqtts://host:8883 over TLS
+	Username  string        // Optional, also taken from the broker URL
+	Password  string        // Optional, also taken from the broker URL
+	ClientID  string        // Unique per connection to the broker
+	QoS       byte          // 0 delivers at most once, 1 at least once
+	Retain    bool          // Whether the broker keeps the last event of every topic for new subscribers
+	Topic     string        // Template of the topics, {event} is replaced, e.g. expense-tracker/{event}
+	KeepAlive time.Duration // How long the connection may be idle before the client pings
+}
+
+// Client publishes messages over one connection, which is opened on the first publish and
+// opened again after it fails
+type Client struct {
+	config   Config
+	address  string
+	host     string // Name the TLS certificate of the broker must have
+	useTLS   bool
+	mu       sync.Mutex
+	conn     net.Conn
+	reader   *bufio.Reader
+	packetID uint16
+	lastSent time.Time
+	logger   *slog.Logger
+}
+
+// NewClient validates the configuration and creates a new Client; it does not connect yet
+func NewClient(config Config, logger *slog.Logger) (*Client, error) {
+	broker, err := url.Parse(config.Broker)
+	if err != nil || broker.Hostname() == "" {
+		return nil, ErrInvalidBroker
+	}
+	c := &Client{config: config, host: broker.Hostname(), logger: logger}
+	switch broker.Scheme {
+	case "mqtt", "tcp":
+		c.address = net.JoinHostPort(broker.Hostname(), portOr(broker.Port(), "1883"))
+	case "mqtts", "ssl", "tls":
+		c.address = net.JoinHostPort(broker.Hostname(), portOr(broker.Port(), "8883"))
+		c.useTLS = true
+	default:
+		return nil, ErrInvalidBroker
+	}
+	if broker.User != nil && c.config.Username == "" {
+		c.config.Username = broker.User.Username()
+		c.config.Password, _ = broker.User.Password()
+	}
+
+	if config.QoS > 1 {
+		return nil, ErrUnsupportedQoS
+	}
+	if config.Topic == "" || strings.ContainsAny(config.Topic, "+#") {
+		return nil, ErrInvalidTopic
+	}
+	if c.config.ClientID == "" {
+		c.config.ClientID = "expense-tracker"
+	}
+	if c.config.KeepAlive <= 0 {
+		c.config.KeepAlive = time.Minute
+	}
+	return c, nil
+}
+
+func portOr(port, fallback string) string {
+	if port == "" {
+		return fallback
+	}
+	return port
+}
+
+// Topic returns the topic of an event such as expenditure.created, e.g.
+// expense-tracker/expenditure/created
+func (c *Client) Topic(event string) string {
+	return strings.ReplaceAll(c.config.Topic, "{event}", strings.ReplaceAll(event, ".", "/"))
+}
+
+// Publish sends a message to a topic and, at QoS 1, waits for the broker to acknowledge it. A
+// connection found broken is opened again and the message sent once more
+func (c *Client) Publish(topic string, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reused := c.conn != nil
+	err := c.publish(topic, payload)
+	if err != nil && reused {
+		c.logger.Warn("MQTT connection lost, reconnecting", "error", err, "broker", c.address)
+		err = c.publish(topic, payload)
+	}
+	return err
+}
+
+// publish sends a message over the connection, opening it when needed; callers hold the lock
+func (c *Client) publish(topic string, payload []byte) error {
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			return err
+		}
+	}
+
+	header := byte(packetPublish<<4) | c.config.QoS<<1
+	if c.config.Retain {
+		header |= 1
+	}
+	body := appendString(nil, topic)
+	if c.config.QoS > 0 {
+		c.packetID++
+		if c.packetID == 0 {
+			c.packetID = 1
+		}
+		body = binary.BigEndian.AppendUint16(body, c.packetID)
+	}
+	body = append(body, payload...)
+
+	if err := c.send(header, body); err != nil {
+		return err
+	}
+	if c.config.QoS == 0 {
+		return nil
+	}
+
+	for {
+		packet, body, err := c.receive()
+		if err != nil {
+			return err
+		}
+		// Replies to an earlier ping may still arrive
+		if packet == packetPingResp {
+			continue
+		}
+		if packet != packetPubAck || len(body) != 2 {
+			c.close()
+			return fmt.Errorf("%w: type %d waiting for PUBACK", ErrUnexpectedReply, packet)
+		}
+		if binary.BigEndian.Uint16(body) == c.packetID {
+			return nil
+		}
+	}
+}
+
+// connect opens the connection and waits for the broker to accept it; callers hold the lock
+func (c *Client) connect() error {
+	dialer := &net.Dialer{Timeout: replyTimeout}
+	var conn net.Conn
+	var err error
+	if c.useTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", c.address, &tls.Config{ServerName: c.host, MinVersion: tls.VersionTLS12})
+	} else {
+		conn, err = dialer.Dial("tcp", c.address)
+	}
+	if err != nil {
+		return fmt.Errorf("error connecting to MQTT broker: %w", err)
+	}
+	c.conn, c.reader = conn, bufio.NewReader(conn)
+
+	// Protocol name and level 4 for 3.1.1, a clean session as nothing is subscribed
+	body := appendString(nil, "MQTT")
+	flags := byte(0x02)
+	if c.config.Username != "" {
+		flags |= 0x80
+	}
+	if c.config.Password != "" {
+		flags |= 0x40
+	}
+	body = append(body, 4, flags)
+	body = binary.BigEndian.AppendUint16(body, uint16(min(c.config.KeepAlive/time.Second, 0xffff)))
+	body = appendString(body, c.config.ClientID)
+	if c.config.Username != "" {
+		body = appendString(body, c.config.Username)
+	}
+	if c.config.Password != "" {
+		body = appendString(body, c.config.Password)
+	}
+
+	if err := c.send(packetConnect<<4, body); err != nil {
+		return err
+	}
+	packet, reply, err := c.receive()
+	if err != nil {
+		return err
+	}
+	if packet != packetConnAck || len(reply) != 2 {
+		c.close()
+		return fmt.Errorf("%w: type %d waiting for CONNACK", ErrUnexpectedReply, packet)
+	}
+	if code := reply[1]; code != 0 {
+		c.close()
+		return fmt.Errorf("%w: %s", ErrConnectRefused, connectRefusals[code])
+	}
+
+	c.logger.Info("Connected to MQTT broker", "broker", c.address, "client_id", c.config.ClientID)
+	return nil
+}
+
+// Run pings the broker whenever the connection has been idle for half the keep-alive, so the
+// broker does not drop it between events, until the context is cancelled; then it disconnects
+func (c *Client) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.KeepAlive / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.mu.Lock()
+			if c.conn != nil {
+				c.send(packetDisconnect<<4, nil)
+				c.close()
+			}
+			c.mu.Unlock()
+			return
+		case <-ticker.C:
+			c.ping()
+		}
+	}
+}
+
+// ping keeps an idle connection alive; a connection that does not answer is closed and opened
+// again on the next publish
+func (c *Client) ping() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil || time.Since(c.lastSent) < c.config.KeepAlive/2 {
+		return
+	}
+	if err := c.send(packetPingReq<<4, nil); err != nil {
+		c.logger.Warn("Failed to ping MQTT broker", "error", err, "broker", c.address)
+		return
+	}
+	if packet, _, err := c.receive(); err != nil || packet != packetPingResp {
+		c.logger.Warn("MQTT broker did not answer a ping", "error", err, "packet", packet, "broker", c.address)
+		c.close()
+	}
+}
+
+// send writes a packet; a failure closes the connection
+func (c *Client) send(header byte, body []byte) error {
+	packet := []byte{header}
+	for length := len(body); ; {
+		digit := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			digit |= 0x80
+		}
+		packet = append(packet, digit)
+		if length == 0 {
+			break
+		}
+	}
+	packet = append(packet, body...)
+
+	c.conn.SetWriteDeadline(time.Now().Add(replyTimeout))
+	if _, err := c.conn.Write(packet); err != nil {
+		c.close()
+		return fmt.Errorf("error writing to MQTT broker: %w", err)
+	}
+	c.lastSent = time.Now()
+	return nil
+}
+
+// receive reads a packet and returns its type and body; a failure closes the connection
+func (c *Client) receive() (byte, []byte, error) {
+	c.conn.SetReadDeadline(time.Now().Add(replyTimeout))
+	header, err := c.reader.ReadByte()
+	if err != nil {
+		c.close()
+		return 0, nil, fmt.Errorf("error reading from MQTT broker: %w", err)
+	}
+
+	length, shift := 0, 0
+	for {
+		digit, err := c.reader.ReadByte()
+		if err != nil {
+			c.close()
+			return 0, nil, fmt.Errorf("error reading from MQTT broker: %w", err)
+		}
+		length |= int(digit&0x7f) << shift
+		if digit&0x80 == 0 {
+			break
+		}
+		if shift += 7; shift > 21 {
+			c.close()
+			return 0, nil, fmt.Errorf("%w: invalid remaining length", ErrUnexpectedReply)
+		}
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, body); err != nil {
+		c.close()
+		return 0, nil, fmt.Errorf("error reading from MQTT broker: %w", err)
+	}
+	return header >> 4, body, nil
+}
+
+func (c *Client) close() {
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn, c.reader = nil, nil
+	}
+}
+
+// appendString appends a string prefixed with its length, as MQTT encodes strings
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
@@ -0,0 +1,148 @@
+package mqtt
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readPacket reads one packet as sent, with its fixed header and remaining length
+func readPacket(r *bufio.Reader) ([]byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	packet := []byte{header}
+	length, shift := 0, 0
+	for {
+		digit, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		packet = append(packet, digit)
+		length |= int(digit&0x7f) << shift
+		if digit&0x80 == 0 {
+			break
+		}
+		shift += 7
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return append(packet, body...), nil
+}
+
+// fakeBroker accepts one connection, acknowledges the CONNECT and, at QoS 1, the PUBLISH, and
+// sends both packets as received on the channel
+func fakeBroker(t *testing.T, qos byte) (string, <-chan [][]byte) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan [][]byte, 1)
+	go func() {
+		defer close(received)
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		reader := bufio.NewReader(conn)
+
+		connect, err := readPacket(reader)
+		if err != nil {
+			return
+		}
+		conn.Write([]byte{packetConnAck << 4, 2, 0, 0})
+		publish, err := readPacket(reader)
+		if err != nil {
+			return
+		}
+		if qos > 0 {
+			// The packet ID follows the topic
+			topicLength := int(publish[2])<<8 | int(publish[3])
+			id := publish[4+topicLength : 6+topicLength]
+			conn.Write([]byte{packetPubAck << 4, 2, id[0], id[1]})
+		}
+		received <- [][]byte{connect, publish}
+	}()
+	return listener.Addr().String(), received
+}
+
+func TestClientWireFormat(t *testing.T) {
+	long := strings.Repeat("x", 200)
+	tests := []struct {
+		name        string
+		config      Config
+		payload     string
+		wantConnect []byte
+		wantPublish []byte
+	}{
+		{
+			"QoS 0 without credentials",
+			Config{ClientID: "tracker", KeepAlive: time.Minute},
+			"hi",
+			[]byte{0x10, 19, 0, 4, 'M', 'Q', 'T', 'T', 4, 0x02, 0, 60, 0, 7, 't', 'r', 'a', 'c', 'k', 'e', 'r'},
+			[]byte{0x30, 7, 0, 3, 'a', '/', 'b', 'h', 'i'},
+		},
+		{
+			"QoS 1 retained with credentials",
+			Config{Username: "user", Password: "pw", ClientID: "tracker", QoS: 1, Retain: true, KeepAlive: 30 * time.Second},
+			"hi",
+			[]byte{0x10, 29, 0, 4, 'M', 'Q', 'T', 'T', 4, 0xc2, 0, 30, 0, 7, 't', 'r', 'a', 'c', 'k', 'e', 'r', 0, 4, 'u', 's', 'e', 'r', 0, 2, 'p', 'w'},
+			[]byte{0x33, 9, 0, 3, 'a', '/', 'b', 0, 1, 'h', 'i'},
+		},
+		{
+			"user name without a password and the default client ID",
+			Config{Username: "user", KeepAlive: time.Minute},
+			"",
+			[]byte{0x10, 33, 0, 4, 'M', 'Q', 'T', 'T', 4, 0x82, 0, 60, 0, 15, 'e', 'x', 'p', 'e', 'n', 's', 'e', '-', 't', 'r', 'a', 'c', 'k', 'e', 'r', 0, 4, 'u', 's', 'e', 'r'},
+			[]byte{0x30, 5, 0, 3, 'a', '/', 'b'},
+		},
+		{
+			// 205 bytes remain, which takes two bytes to encode
+			"long payload",
+			Config{ClientID: "tracker", KeepAlive: time.Minute},
+			long,
+			[]byte{0x10, 19, 0, 4, 'M', 'Q', 'T', 'T', 4, 0x02, 0, 60, 0, 7, 't', 'r', 'a', 'c', 'k', 'e', 'r'},
+			append([]byte{0x30, 0xcd, 0x01, 0, 3, 'a', '/', 'b'}, long...),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, received := fakeBroker(t, tt.config.QoS)
+			tt.config.Broker = "mqtt://" + address
+			tt.config.Topic = "a/{event}"
+			client, err := NewClient(tt.config, slog.New(slog.DiscardHandler))
+			if err != nil {
+				t.Fatalf("creating client: %v", err)
+			}
+			defer client.close()
+
+			if err := client.Publish("a/b", []byte(tt.payload)); err != nil {
+				t.Fatalf("publishing: %v", err)
+			}
+			packets := <-received
+			if len(packets) != 2 {
+				t.Fatalf("broker received %d packets, want CONNECT and PUBLISH", len(packets))
+			}
+			if !bytes.Equal(packets[0], tt.wantConnect) {
+				t.Errorf("CONNECT = % x, want % x", packets[0], tt.wantConnect)
+			}
+			if !bytes.Equal(packets[1], tt.wantPublish) {
+				t.Errorf("PUBLISH = % x, want % x", packets[1], tt.wantPublish)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"time"
+)
+
+// Budget events, named like the outbox topics of expenditures
+const (
+	BudgetCreated = "budget.created"
+	BudgetUpdated = "budget.updated"
+	BudgetDeleted = "budget.deleted"
+)
+
+// Event is the JSON payload published for every change. Expenditure events carry the ID of their
+// outbox message, which subscribers may see twice at QoS 1 and should skip when already handled
+type Event struct {
+	ID          int64               `json:"id,omitempty"`
+	Event       string              `json:"event"`
+	Expenditure *domain.Expenditure `json:"expenditure,omitempty"`
+	Budget      *domain.Budget      `json:"budget,omitempty"`
+	OccurredAt  time.Time           `json:"occurred_at"`
+}
+
+// Publisher publishes the changes to expenditures relayed from the outbox and the changes to
+// budgets to the topics of their events
+type Publisher struct {
+	client *Client
+	logger *slog.Logger
+}
+
+// NewPublisher creates a new Publisher sending through client
+func NewPublisher(client *Client, logger *slog.Logger) *Publisher {
+	return &Publisher{
+		client: client,
+		logger: logger,
+	}
+}
+
+// DeliverOutboxMessage publishes the message and, at QoS 1, waits for the broker to accept it
+func (p *Publisher) DeliverOutboxMessage(message *domain.OutboxMessage) error {
+	return p.publish(Event{
+		ID:          message.ID,
+		Event:       message.Topic,
+		Expenditure: message.Expenditure,
+		OccurredAt:  message.CreatedAt,
+	})
+}
+
+// PublishBudget publishes a change to a budget without blocking the caller; budget events are
+// not kept in the outbox, so one the broker cannot take is only logged
+func (p *Publisher) PublishBudget(event string, budget *domain.Budget) {
+	go func() {
+		if err := p.publish(Event{Event: event, Budget: budget, OccurredAt: time.Now()}); err != nil {
+			p.logger.Error("Failed to publish budget event", "error", err, "event", event, "budget_id", budget.ID)
+		}
+	}()
+}
+
+func (p *Publisher) publish(event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	topic := p.client.Topic(event.Event)
+	if err := p.client.Publish(topic, payload); err != nil {
+		return err
+	}
+	p.logger.Info("Published MQTT event", "topic", topic, "event", event.Event)
+	return nil
+}
+
+// BudgetRepository wraps a BudgetRepository and publishes every change to a budget
+type BudgetRepository struct {
+	domain.BudgetRepository
+	publisher *Publisher
+}
+
+// NewBudgetRepository creates a new BudgetRepository around the given repository
+func NewBudgetRepository(inner domain.BudgetRepository, publisher *Publisher) *BudgetRepository {
+	return &BudgetRepository{
+		BudgetRepository: inner,
+		publisher:        publisher,
+	}
+}
+
+// AddBudget adds the budget and publishes it
+func (r *BudgetRepository) AddBudget(budget *domain.Budget) error {
+	if err := r.BudgetRepository.AddBudget(budget); err != nil {
+		return err
+	}
+
+	r.publisher.PublishBudget(BudgetCreated, budget.Clone())
+	return nil
+}
+
+// UpdateBudget updates the budget and publishes it
+func (r *BudgetRepository) UpdateBudget(budget *domain.Budget) error {
+	if err := r.BudgetRepository.UpdateBudget(budget); err != nil {
+		return err
+	}
+
+	r.publisher.PublishBudget(BudgetUpdated, budget.Clone())
+	return nil
+}
+
+// DeleteBudget deletes the budget and publishes it as it was
+func (r *BudgetRepository) DeleteBudget(id string) error {
+	budget, err := r.BudgetRepository.GetBudgetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.BudgetRepository.DeleteBudget(id); err != nil {
+		return err
+	}
+
+	r.publisher.PublishBudget(BudgetDeleted, budget)
+	return nil
+}
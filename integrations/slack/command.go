@@ -0,0 +1,149 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker/app"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/quickentry"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const usageText = "Usage: `/spend 12.40 lunch` or `/spend 30 taxi #Transportation`"
+
+// maxCommandBodySize bounds the slash-command payload read before signature verification
+const maxCommandBodySize = 64 << 10
+
+// CommandHandler serves the Slack slash-command endpoint that records expenditures
+type CommandHandler struct {
+	signingSecret string
+	workspaces    map[string]Workspace
+	expenditures  *app.ExpenditureService
+	categories    domain.CategoryRepository
+	rules         domain.CategorizationRuleRepository
+	logger        *slog.Logger
+}
+
+// NewCommandHandler creates a new CommandHandler; when workspaces is empty any workspace is accepted.
+// Expenditures are recorded through expenditures like those of the API. rules may be nil when the
+// storage keeps no categorization rules
+func NewCommandHandler(signingSecret string, workspaces []Workspace, expenditures *app.ExpenditureService, categories domain.CategoryRepository, rules domain.CategorizationRuleRepository, logger *slog.Logger) *CommandHandler {
+	byTeam := make(map[string]Workspace, len(workspaces))
+	for _, workspace := range workspaces {
+		byTeam[workspace.TeamID] = workspace
+	}
+
+	return &CommandHandler{
+		signingSecret: signingSecret,
+		workspaces:    byTeam,
+		expenditures:  expenditures,
+		categories:    categories,
+		rules:         rules,
+		logger:        logger,
+	}
+}
+
+type commandResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+func (h *CommandHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Handling slack command request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		h.logger.Warn("Method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxCommandBodySize))
+	if err != nil {
+		h.logger.Error("Failed to read slack command body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = verifySignature(h.signingSecret, r.Header.Get("X-Slack-Request-Timestamp"), body, r.Header.Get("X-Slack-Signature"), time.Now())
+	if err != nil {
+		h.logger.Warn("Rejected slack command", "error", err, "remote_addr", r.RemoteAddr)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		h.logger.Error("Failed to parse slack command form", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	teamID := form.Get("team_id")
+	workspace, known := h.workspaces[teamID]
+	if len(h.workspaces) > 0 && !known {
+		h.logger.Warn("Slack command from unconfigured workspace", "team_id", teamID)
+		h.respond(w, "This workspace is not configured for the expense tracker.")
+		return
+	}
+
+	text := strings.TrimSpace(form.Get("text"))
+	h.logger.Debug("Decoded slack command", "team_id", teamID, "user_id", form.Get("user_id"), "command", form.Get("command"), "text", text)
+
+	if text == "" || strings.EqualFold(text, "help") {
+		h.respond(w, usageText)
+		return
+	}
+
+	e, err := quickentry.Parse(text, time.Now())
+	if err != nil {
+		h.respond(w, fmt.Sprintf("%s\n%s", err.Error(), usageText))
+		return
+	}
+
+	categoryID, tags, err := quickentry.Categorize(h.categories, h.rules, e, workspace.DefaultCategoryID)
+	if err != nil {
+		h.logger.Warn("Failed to resolve category", "error", err, "hashtags", e.Hashtags, "team_id", teamID)
+		h.respond(w, err.Error())
+		return
+	}
+
+	result, err := h.expenditures.Create(app.ExpenditureInput{
+		Description: e.Description,
+		Amount:      e.Amount,
+		Date:        e.Date,
+		CategoryId:  categoryID,
+		Tags:        tags,
+	})
+	if err != nil {
+		if app.KindOf(err) == app.KindInternal {
+			h.logger.Error("Failed to add expenditure from slack", "error", err, "team_id", teamID)
+			h.respond(w, "Sorry, the expenditure could not be saved.")
+			return
+		}
+		h.logger.Warn("Slack expenditure refused", "error", err, "team_id", teamID)
+		h.respond(w, err.Error())
+		return
+	}
+
+	expenditure := result.Expenditure
+	h.logger.Info("Recorded expenditure from slack", "id", expenditure.ID, "team_id", teamID, "amount", expenditure.Amount)
+	reply := fmt.Sprintf("Recorded _%s_ for %.2f.", expenditure.Description, expenditure.Amount)
+	if category := result.OverLimit; category != nil {
+		reply += fmt.Sprintf(" That is over the %.2f limit of %s.", category.TransactionLimit, category.Name)
+	}
+	h.respond(w, reply)
+}
+
+// respond replies with an ephemeral message visible only to the command's author
+func (h *CommandHandler) respond(w http.ResponseWriter, text string) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(commandResponse{
+		ResponseType: "ephemeral",
+		Text:         text,
+	})
+}
@@ -0,0 +1,45 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+)
+
+// AlertRules configures which expenditures trigger an outgoing Slack notification
+type AlertRules struct {
+	AmountAbove       float64 `json:"amount_above"`        // Notify for single expenditures above this amount, 0 disables
+	MonthlyTotalAbove float64 `json:"monthly_total_above"` // Notify when the month total crosses this amount, 0 disables
+	Anomalies         bool    `json:"anomalies"`           // Notify about unusual amounts, possible duplicates and daily spikes
+}
+
+// Workspace is the per-workspace Slack configuration, keyed by the Slack team ID
+type Workspace struct {
+	TeamID            string     `json:"team_id"`
+	WebhookURL        string     `json:"webhook_url"`         // Incoming webhook used for notifications
+	DefaultCategoryID uuid.UUID  `json:"default_category_id"` // Category used when /spend has no #category
+	Alerts            AlertRules `json:"alerts"`
+}
+
+// LoadWorkspaces reads the workspace configuration from a JSON file
+func LoadWorkspaces(path string) ([]Workspace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading slack workspaces file: %w", err)
+	}
+
+	var workspaces []Workspace
+	if err := json.Unmarshal(data, &workspaces); err != nil {
+		return nil, fmt.Errorf("error parsing slack workspaces file: %w", err)
+	}
+
+	for i, workspace := range workspaces {
+		if workspace.TeamID == "" {
+			return nil, fmt.Errorf("slack workspace %d has no team_id", i)
+		}
+	}
+
+	return workspaces, nil
+}
@@ -0,0 +1,133 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// NotifyingRepository wraps an ExpenditureRepository and posts Slack notifications
+// whenever a new expenditure matches a workspace's alert rules
+type NotifyingRepository struct {
+	domain.ExpenditureWrapper
+	workspaces []Workspace
+	calendar   domain.FiscalCalendar
+	client     *http.Client
+	logger     *slog.Logger
+}
+
+// NewNotifyingRepository creates a new NotifyingRepository around the given repository; monthly
+// thresholds apply to the fiscal months of calendar
+func NewNotifyingRepository(inner domain.ExpenditureRepository, workspaces []Workspace, calendar domain.FiscalCalendar, logger *slog.Logger) *NotifyingRepository {
+	return &NotifyingRepository{
+		ExpenditureWrapper: domain.ExpenditureWrapper{ExpenditureRepository: inner},
+		workspaces:         workspaces,
+		calendar:           calendar,
+		client:             &http.Client{Timeout: 10 * time.Second},
+		logger:             logger,
+	}
+}
+
+// AddExpenditure adds the expenditure and evaluates the alert rules of every workspace
+func (n *NotifyingRepository) AddExpenditure(expenditure *domain.Expenditure) error {
+	monthStart, _ := n.calendar.Period(time.Now())
+
+	// Month total before adding, to detect when a monthly threshold is crossed
+	before, err := n.monthTotal(monthStart)
+	if err != nil {
+		n.logger.Error("Failed to compute month total for slack alerts", "error", err)
+	}
+
+	if err := n.ExpenditureRepository.AddExpenditure(expenditure); err != nil {
+		return err
+	}
+
+	after := before
+	if !expenditure.Date.Before(monthStart) {
+		after += expenditure.Amount
+	}
+
+	for _, workspace := range n.workspaces {
+		if workspace.WebhookURL == "" {
+			continue
+		}
+
+		rules := workspace.Alerts
+		if rules.AmountAbove > 0 && expenditure.Amount > rules.AmountAbove {
+			n.post(workspace, fmt.Sprintf(":warning: Large expenditure: _%s_ for %.2f (alert threshold %.2f)",
+				expenditure.Description, expenditure.Amount, rules.AmountAbove))
+		}
+
+		if err == nil && rules.MonthlyTotalAbove > 0 && before <= rules.MonthlyTotalAbove && after > rules.MonthlyTotalAbove {
+			n.post(workspace, fmt.Sprintf(":rotating_light: Monthly spending is now %.2f, above the alert threshold of %.2f",
+				after, rules.MonthlyTotalAbove))
+		}
+	}
+
+	return nil
+}
+
+// NotifyAnomaly posts an anomaly to the workspaces alerting about anomalies
+func (n *NotifyingRepository) NotifyAnomaly(anomaly *domain.Anomaly) {
+	for _, workspace := range n.workspaces {
+		if workspace.WebhookURL == "" || !workspace.Alerts.Anomalies {
+			continue
+		}
+		n.post(workspace, fmt.Sprintf(":mag: Unusual spending: %s", anomaly.Reason))
+	}
+}
+
+func (n *NotifyingRepository) monthTotal(monthStart time.Time) (float64, error) {
+	expenditures, err := n.ExpenditureRepository.GetAllExpenditures()
+	if err != nil {
+		return 0, err
+	}
+
+	var total float64
+	for _, expenditure := range expenditures {
+		if !expenditure.Date.Before(monthStart) {
+			total += expenditure.Amount
+		}
+	}
+	return total, nil
+}
+
+// post delivers a message to the workspace's incoming webhook without blocking the caller
+func (n *NotifyingRepository) post(workspace Workspace, text string) {
+	go func() {
+		body, err := json.Marshal(map[string]string{"text": text})
+		if err != nil {
+			n.logger.Error("Failed to encode slack notification", "error", err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, workspace.WebhookURL, bytes.NewReader(body))
+		if err != nil {
+			n.logger.Error("Failed to create slack notification request", "error", err, "team_id", workspace.TeamID)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := n.client.Do(req)
+		if err != nil {
+			n.logger.Error("Failed to send slack notification", "error", err, "team_id", workspace.TeamID)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			n.logger.Error("Slack webhook rejected notification", "status", resp.StatusCode, "team_id", workspace.TeamID)
+			return
+		}
+
+		n.logger.Info("Sent slack notification", "team_id", workspace.TeamID)
+	}()
+}
@@ -0,0 +1,54 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// sign signs a request the way Slack does
+func sign(secret, timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	const secret = "8f742231b10e8888abcd99yyyzzz85a5"
+	const body = "token=xyzz0WbapA4vBCDEFasx0q6G&command=%2Fspent&text=12.50+lunch"
+	now := time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)
+	at := func(age time.Duration) string { return strconv.FormatInt(now.Add(-age).Unix(), 10) }
+
+	tests := []struct {
+		name      string
+		timestamp string
+		body      string
+		signature string
+		wantErr   error
+	}{
+		{"valid", at(0), body, sign(secret, at(0), body), nil},
+		{"valid near the limit", at(4 * time.Minute), body, sign(secret, at(4*time.Minute), body), nil},
+		{"valid with the clock behind", at(-4 * time.Minute), body, sign(secret, at(-4*time.Minute), body), nil},
+		{"stale", at(6 * time.Minute), body, sign(secret, at(6*time.Minute), body), ErrStaleRequest},
+		{"from the future", at(-6 * time.Minute), body, sign(secret, at(-6*time.Minute), body), ErrStaleRequest},
+		{"tampered body", at(0), body + "0", sign(secret, at(0), body), ErrInvalidSignature},
+		{"tampered timestamp", at(time.Second), body, sign(secret, at(0), body), ErrInvalidSignature},
+		{"other secret", at(0), body, sign("other", at(0), body), ErrInvalidSignature},
+		{"missing signature", at(0), body, "", ErrInvalidSignature},
+		{"signature without version", at(0), body, sign(secret, at(0), body)[len("v0="):], ErrInvalidSignature},
+		{"missing timestamp", "", body, sign(secret, "", body), ErrInvalidSignature},
+		{"invalid timestamp", "noon", body, sign(secret, "noon", body), ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifySignature(secret, tt.timestamp, []byte(tt.body), tt.signature, now); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
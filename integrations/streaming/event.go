@@ -0,0 +1,52 @@
+// Package streaming publishes the changes to expenditures relayed from the outbox to event
+// streaming platforms, NATS JetStream and Kafka, so analytics pipelines can consume spending
+// data without polling the API. Every sink is an outbox subscriber, so events arrive in order
+// and at least once.
+package streaming
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker/domain"
+	"time"
+)
+
+// SchemaVersion is the version of the Event payload; it changes whenever a field is removed or
+// changes meaning, so consumers can tell the payloads they understand
+const SchemaVersion = 1
+
+// Schema names the payload, for consumers reading several streams
+const Schema = "expense-tracker.expenditure-event"
+
+// Event is the JSON payload of every change. ID is that of the outbox message, which consumers
+// may see twice and should skip when already handled
+type Event struct {
+	Schema        string              `json:"schema"`
+	SchemaVersion int                 `json:"schema_version"`
+	ID            int64               `json:"id"`
+	Event         string              `json:"event"`
+	ExpenditureID string              `json:"expenditure_id"`
+	Expenditure   *domain.Expenditure `json:"expenditure"` // State after the change, before it for deletions
+	OccurredAt    time.Time           `json:"occurred_at"`
+}
+
+// NewEvent creates the event of an outbox message
+func NewEvent(message *domain.OutboxMessage) Event {
+	return Event{
+		Schema:        Schema,
+		SchemaVersion: SchemaVersion,
+		ID:            message.ID,
+		Event:         message.Topic,
+		ExpenditureID: message.ExpenditureID.String(),
+		Expenditure:   message.Expenditure,
+		OccurredAt:    message.CreatedAt,
+	}
+}
+
+func encodeEvent(message *domain.OutboxMessage) ([]byte, error) {
+	payload, err := json.Marshal(NewEvent(message))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+	return payload, nil
+}
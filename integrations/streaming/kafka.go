@@ -0,0 +1,119 @@
+package streaming
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidKafkaURL   = errors.New("Kafka REST Proxy URL must be http:// or https://")
+	ErrInvalidKafkaTopic = errors.New("Kafka topic must not be empty or contain slashes or spaces")
+	ErrKafkaRejected     = errors.New("Kafka rejected the event")
+)
+
+// KafkaConfig is the Kafka REST Proxy events are produced through and the topic they go to
+type KafkaConfig struct {
+	RestURL  string // Base URL of the REST Proxy, e.g. http://kafka-rest:8082
+	Topic    string
+	Username string // Optional, for basic auth
+	Password string // Optional, for basic auth
+}
+
+// KafkaSink produces every event to a Kafka topic through the REST Proxy (API v2), keyed by the
+// expenditure so the changes to one expenditure land in one partition, in order
+type KafkaSink struct {
+	endpoint string
+	config   KafkaConfig
+	client   *http.Client
+	logger   *slog.Logger
+}
+
+// kafkaRecords is the body of a produce request with JSON-embedded records
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaRecord struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// kafkaOffsets is the answer to a produce request, with an error per record that failed
+type kafkaOffsets struct {
+	Offsets []struct {
+		Partition int    `json:"partition"`
+		Offset    int64  `json:"offset"`
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+// NewKafkaSink validates the configuration and creates a new KafkaSink
+func NewKafkaSink(config KafkaConfig, logger *slog.Logger) (*KafkaSink, error) {
+	base, err := url.Parse(config.RestURL)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, ErrInvalidKafkaURL
+	}
+	if config.Topic == "" || strings.ContainsAny(config.Topic, "/ ") {
+		return nil, ErrInvalidKafkaTopic
+	}
+
+	return &KafkaSink{
+		endpoint: strings.TrimSuffix(base.String(), "/") + "/topics/" + url.PathEscape(config.Topic),
+		config:   config,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		logger:   logger,
+	}, nil
+}
+
+// DeliverOutboxMessage produces the event of the message and waits for Kafka to acknowledge it
+func (s *KafkaSink) DeliverOutboxMessage(message *domain.OutboxMessage) error {
+	payload, err := encodeEvent(message)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(kafkaRecords{Records: []kafkaRecord{{Key: message.ExpenditureID.String(), Value: payload}}})
+	if err != nil {
+		return fmt.Errorf("failed to encode records: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+	if s.config.Username != "" {
+		req.SetBasicAuth(s.config.Username, s.config.Password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error producing to Kafka: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: Kafka REST Proxy returned status %d", ErrKafkaRejected, resp.StatusCode)
+	}
+	var offsets kafkaOffsets
+	if err := json.NewDecoder(resp.Body).Decode(&offsets); err != nil {
+		return fmt.Errorf("error decoding Kafka REST Proxy response: %w", err)
+	}
+	for _, offset := range offsets.Offsets {
+		if offset.ErrorCode != nil {
+			return fmt.Errorf("%w: %s (code %d)", ErrKafkaRejected, offset.Error, *offset.ErrorCode)
+		}
+	}
+
+	s.logger.Debug("Produced event to Kafka", "topic", s.config.Topic, "id", message.ID, "event", message.Topic)
+	return nil
+}
@@ -0,0 +1,98 @@
+package streaming
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewKafkaSink(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  KafkaConfig
+		wantErr error
+	}{
+		{"valid", KafkaConfig{RestURL: "http://kafka-rest:8082", Topic: "expenditures"}, nil},
+		{"without a scheme", KafkaConfig{RestURL: "kafka-rest:8082", Topic: "expenditures"}, ErrInvalidKafkaURL},
+		{"other scheme", KafkaConfig{RestURL: "kafka://kafka-rest:9092", Topic: "expenditures"}, ErrInvalidKafkaURL},
+		{"without a host", KafkaConfig{RestURL: "http://", Topic: "expenditures"}, ErrInvalidKafkaURL},
+		{"without a topic", KafkaConfig{RestURL: "http://kafka-rest:8082"}, ErrInvalidKafkaTopic},
+		{"topic with a slash", KafkaConfig{RestURL: "http://kafka-rest:8082", Topic: "a/b"}, ErrInvalidKafkaTopic},
+		{"topic with a space", KafkaConfig{RestURL: "http://kafka-rest:8082", Topic: "a b"}, ErrInvalidKafkaTopic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewKafkaSink(tt.config, slog.New(slog.DiscardHandler)); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKafkaDeliverOutboxMessage(t *testing.T) {
+	id := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	message := &domain.OutboxMessage{ID: 7, Topic: "expenditure.created", ExpenditureID: id, CreatedAt: time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)}
+	wantBody := `{"records":[{"key":"` + id.String() + `","value":{"schema":"expense-tracker.expenditure-event","schema_version":1,"id":7,"event":"expenditure.created","expenditure_id":"` + id.String() + `","expenditure":null,"occurred_at":"2024-02-29T12:00:00Z"}}]}`
+	produced := `{"offsets":[{"partition":2,"offset":41,"error_code":null,"error":null}]}`
+
+	tests := []struct {
+		name     string
+		username string
+		status   int
+		answer   string
+		wantAuth bool
+		wantErr  error
+	}{
+		{"produced", "", http.StatusOK, produced, false, nil},
+		{"produced with basic auth", "user", http.StatusOK, produced, true, nil},
+		{"topic missing", "", http.StatusNotFound, `{"error_code":40401,"message":"Topic not found."}`, false, ErrKafkaRejected},
+		{"record failed", "", http.StatusOK, `{"offsets":[{"partition":null,"offset":null,"error_code":50002,"error":"Kafka error"}]}`, false, ErrKafkaRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			var body []byte
+			proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+				body, _ = io.ReadAll(r.Body)
+				w.Header().Set("Content-Type", "application/vnd.kafka.v2+json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.answer))
+			}))
+			defer proxy.Close()
+
+			sink, err := NewKafkaSink(KafkaConfig{RestURL: proxy.URL + "/", Topic: "expenditures", Username: tt.username, Password: "pw"}, slog.New(slog.DiscardHandler))
+			if err != nil {
+				t.Fatalf("creating sink: %v", err)
+			}
+			if err := sink.DeliverOutboxMessage(message); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if got == nil {
+				t.Fatal("nothing produced")
+			}
+			if got.Method != http.MethodPost || got.URL.Path != "/topics/expenditures" {
+				t.Errorf("request = %s %s, want POST /topics/expenditures", got.Method, got.URL.Path)
+			}
+			if got.Header.Get("Content-Type") != "application/vnd.kafka.json.v2+json" || got.Header.Get("Accept") != "application/vnd.kafka.v2+json" {
+				t.Errorf("Content-Type %q and Accept %q, want the JSON embedded format of API v2", got.Header.Get("Content-Type"), got.Header.Get("Accept"))
+			}
+			if username, password, ok := got.BasicAuth(); ok != tt.wantAuth || (ok && (username != "user" || password != "pw")) {
+				t.Errorf("basic auth = %q:%q (%v), want %v", username, password, ok, tt.wantAuth)
+			}
+			if string(body) != wantBody {
+				t.Errorf("body = %s, want %s", body, wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package streaming
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidNATSServer = errors.New("NATS server URL must be nats://host:port or tls://host:port")
+	ErrInvalidSubject    = errors.New("NATS subject must not be empty or contain spaces or the wildcards * and >")
+	ErrNATSRejected      = errors.New("NATS rejected the event")
+)
+
+// natsReplyTimeout bounds the wait for the server to accept a connection or JetStream to
+// acknowledge an event
+const natsReplyTimeout = 10 * time.Second
+
+// NATSConfig is the NATS server events are published to and the subjects they go to
+type NATSConfig struct {
+	Server   string // nats://host:4222, or tls://host:4222 to require TLS
+	Username string // Optional, also taken from the server URL
+	Password string // Optional, also taken from the server URL
+	Token    string // Optional, instead of a username and password
+	Subject  string // Template of the subjects, {event} is replaced, e.g. expense-tracker.{event}
+}
+
+// natsInfo is the part of the INFO the server greets clients with that the sink needs
+type natsInfo struct {
+	TLSRequired bool `json:"tls_required"`
+	Headers     bool `json:"headers"`
+}
+
+// natsAck is the acknowledgement of JetStream for a published message
+type natsAck struct {
+	Stream    string `json:"stream"`
+	Sequence  uint64 `json:"seq"`
+	Duplicate bool   `json:"duplicate"`
+	Error     *struct {
+		Code        int    `json:"code"`
+		Description string `json:"description"`
+	} `json:"error"`
+}
+
+// NATSSink publishes every event to a subject captured by a JetStream stream and waits for the
+// stream to store it. The ID of the outbox message is sent as Nats-Msg-Id, so JetStream drops an
+// event relayed twice within the duplicate window of the stream
+type NATSSink struct {
+	config  NATSConfig
+	address string
+	host    string // Name the TLS certificate of the server must have
+	useTLS  bool
+	inbox   string // Prefix of the subjects JetStream acknowledges on
+	mu      sync.Mutex
+	conn    net.Conn
+	reader  *bufio.Reader
+	logger  *slog.Logger
+}
+
+// NewNATSSink validates the configuration and creates a new NATSSink; it does not connect yet
+func NewNATSSink(config NATSConfig, logger *slog.Logger) (*NATSSink, error) {
+	server, err := url.Parse(config.Server)
+	if err != nil || server.Hostname() == "" {
+		return nil, ErrInvalidNATSServer
+	}
+	s := &NATSSink{config: config, host: server.Hostname(), logger: logger}
+	switch server.Scheme {
+	case "nats":
+	case "tls":
+		s.useTLS = true
+	default:
+		return nil, ErrInvalidNATSServer
+	}
+	port := server.Port()
+	if port == "" {
+		port = "4222"
+	}
+	s.address = net.JoinHostPort(server.Hostname(), port)
+	if server.User != nil && s.config.Username == "" && s.config.Token == "" {
+		if password, ok := server.User.Password(); ok {
+			s.config.Username, s.config.Password = server.User.Username(), password
+		} else {
+			s.config.Token = server.User.Username()
+		}
+	}
+
+	if config.Subject == "" || strings.ContainsAny(config.Subject, " \t*>") {
+		return nil, ErrInvalidSubject
+	}
+
+	id := make([]byte, 8)
+	rand.Read(id)
+	s.inbox = "_INBOX." + hex.EncodeToString(id)
+	return s, nil
+}
+
+// Subject returns the subject of an event such as expenditure.created, e.g.
+// expense-tracker.expenditure.created
+func (s *NATSSink) Subject(event string) string {
+	return strings.ReplaceAll(s.config.Subject, "{event}", event)
+}
+
+// DeliverOutboxMessage publishes the event of the message and waits for JetStream to store it. A
+// connection found broken is opened again and the event sent once more
+func (s *NATSSink) DeliverOutboxMessage(message *domain.OutboxMessage) error {
+	payload, err := encodeEvent(message)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reused := s.conn != nil
+	err = s.publish(message, payload)
+	if err != nil && reused && !errors.Is(err, ErrNATSRejected) {
+		s.logger.Warn("NATS connection lost, reconnecting", "error", err, "server", s.address)
+		err = s.publish(message, payload)
+	}
+	return err
+}
+
+// publish sends the event over the connection, opening it when needed; callers hold the lock
+func (s *NATSSink) publish(message *domain.OutboxMessage, payload []byte) error {
+	if s.conn == nil {
+		if err := s.connect(); err != nil {
+			return err
+		}
+	}
+
+	subject := s.Subject(message.Topic)
+	reply := s.inbox + "." + strconv.FormatInt(message.ID, 10)
+	headers := "NATS/1.0\r\nNats-Msg-Id: " + strconv.FormatInt(message.ID, 10) + "\r\n\r\n"
+	command := fmt.Sprintf("HPUB %s %s %d %d\r\n%s%s\r\n", subject, reply, len(headers), len(headers)+len(payload), headers, payload)
+	if err := s.send(command); err != nil {
+		return err
+	}
+
+	for {
+		msgSubject, body, err := s.receive()
+		if err != nil {
+			return err
+		}
+		// Acknowledgements of earlier attempts that timed out may still arrive
+		if msgSubject != reply {
+			continue
+		}
+
+		var ack natsAck
+		if err := json.Unmarshal(body, &ack); err != nil {
+			return fmt.Errorf("%w: unexpected acknowledgement %q", ErrNATSRejected, body)
+		}
+		if ack.Error != nil {
+			return fmt.Errorf("%w: %s (code %d)", ErrNATSRejected, ack.Error.Description, ack.Error.Code)
+		}
+		if ack.Stream == "" {
+			// A plain subscriber answered, or no stream captures the subject
+			return fmt.Errorf("%w: no JetStream stream captures subject %s", ErrNATSRejected, subject)
+		}
+		s.logger.Debug("Published event to NATS", "subject", subject, "stream", ack.Stream, "seq", ack.Sequence, "duplicate", ack.Duplicate, "id", message.ID)
+		return nil
+	}
+}
+
+// connect opens the connection, subscribes to the inbox of acknowledgements and waits for the
+// server to accept both; callers hold the lock
+func (s *NATSSink) connect() error {
+	conn, err := net.DialTimeout("tcp", s.address, natsReplyTimeout)
+	if err != nil {
+		return fmt.Errorf("error connecting to NATS server: %w", err)
+	}
+	s.conn, s.reader = conn, bufio.NewReader(conn)
+
+	s.conn.SetReadDeadline(time.Now().Add(natsReplyTimeout))
+	line, err := s.reader.ReadString('\n')
+	if err != nil {
+		s.close()
+		return fmt.Errorf("error reading from NATS server: %w", err)
+	}
+	var info natsInfo
+	if !strings.HasPrefix(line, "INFO ") || json.Unmarshal([]byte(strings.TrimPrefix(line, "INFO ")), &info) != nil {
+		s.close()
+		return fmt.Errorf("%w: unexpected greeting %q", ErrNATSRejected, strings.TrimSpace(line))
+	}
+	if !info.Headers {
+		s.close()
+		return fmt.Errorf("%w: server does not support headers, which JetStream needs", ErrNATSRejected)
+	}
+
+	// The server upgrades to TLS after its greeting
+	if s.useTLS || info.TLSRequired {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: s.host, MinVersion: tls.VersionTLS12})
+		tlsConn.SetDeadline(time.Now().Add(natsReplyTimeout))
+		if err := tlsConn.Handshake(); err != nil {
+			s.close()
+			return fmt.Errorf("error connecting to NATS server: %w", err)
+		}
+		s.conn, s.reader = tlsConn, bufio.NewReader(tlsConn)
+	}
+
+	options := map[string]any{
+		"verbose":  false,
+		"pedantic": false,
+		"headers":  true,
+		"name":     "expense-tracker",
+		"lang":     "go",
+		"version":  "1",
+		"protocol": 1,
+	}
+	if s.config.Token != "" {
+		options["auth_token"] = s.config.Token
+	} else if s.config.Username != "" {
+		options["user"], options["pass"] = s.config.Username, s.config.Password
+	}
+	connect, err := json.Marshal(options)
+	if err != nil {
+		s.close()
+		return fmt.Errorf("failed to encode connect options: %w", err)
+	}
+
+	// The server answers the ping once it handled the connect and the subscription, or an error
+	if err := s.send("CONNECT " + string(connect) + "\r\nSUB " + s.inbox + ".* 1\r\nPING\r\n"); err != nil {
+		return err
+	}
+	for {
+		line, err := s.readLine()
+		if err != nil {
+			return err
+		}
+		if line == "PONG" {
+			break
+		}
+		if strings.HasPrefix(line, "-ERR") {
+			s.close()
+			return fmt.Errorf("%w: %s", ErrNATSRejected, strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+
+	s.logger.Info("Connected to NATS server", "server", s.address)
+	return nil
+}
+
+// Run disconnects from the server once the context is cancelled
+func (s *NATSSink) Run(ctx context.Context) {
+	<-ctx.Done()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.close()
+}
+
+// receive reads until the next message and returns its subject and payload, answering pings of
+// the server on the way; a failure closes the connection
+func (s *NATSSink) receive() (string, []byte, error) {
+	for {
+		line, err := s.readLine()
+		if err != nil {
+			return "", nil, err
+		}
+
+		switch {
+		case line == "PING":
+			if err := s.send("PONG\r\n"); err != nil {
+				return "", nil, err
+			}
+		case line == "PONG", line == "+OK", strings.HasPrefix(line, "INFO "):
+		case strings.HasPrefix(line, "-ERR"):
+			s.close()
+			return "", nil, fmt.Errorf("%w: %s", ErrNATSRejected, strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		case strings.HasPrefix(line, "MSG "), strings.HasPrefix(line, "HMSG "):
+			return s.readMessage(line)
+		default:
+			s.close()
+			return "", nil, fmt.Errorf("%w: unexpected line %q", ErrNATSRejected, line)
+		}
+	}
+}
+
+// readMessage reads the payload of a MSG or HMSG line, skipping the headers of an HMSG:
+// MSG <subject> <sid> [reply] <bytes> or HMSG <subject> <sid> [reply] <header bytes> <bytes>
+func (s *NATSSink) readMessage(line string) (string, []byte, error) {
+	fields := strings.Fields(line)
+	headerLength, total := 0, 0
+	var err error
+	if fields[0] == "HMSG" && len(fields) >= 5 {
+		headerLength, err = strconv.Atoi(fields[len(fields)-2])
+		if err == nil {
+			total, err = strconv.Atoi(fields[len(fields)-1])
+		}
+	} else if fields[0] == "MSG" && len(fields) >= 4 {
+		total, err = strconv.Atoi(fields[len(fields)-1])
+	} else {
+		err = errors.New("missing fields")
+	}
+	if err != nil || headerLength > total {
+		s.close()
+		return "", nil, fmt.Errorf("%w: invalid message %q", ErrNATSRejected, line)
+	}
+
+	body := make([]byte, total+2)
+	if _, err := io.ReadFull(s.reader, body); err != nil {
+		s.close()
+		return "", nil, fmt.Errorf("error reading from NATS server: %w", err)
+	}
+	return fields[1], body[headerLength:total], nil
+}
+
+// readLine reads a protocol line without its CRLF; a failure closes the connection
+func (s *NATSSink) readLine() (string, error) {
+	s.conn.SetReadDeadline(time.Now().Add(natsReplyTimeout))
+	line, err := s.reader.ReadString('\n')
+	if err != nil {
+		s.close()
+		return "", fmt.Errorf("error reading from NATS server: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// send writes protocol commands; a failure closes the connection
+func (s *NATSSink) send(command string) error {
+	s.conn.SetWriteDeadline(time.Now().Add(natsReplyTimeout))
+	if _, err := io.WriteString(s.conn, command); err != nil {
+		s.close()
+		return fmt.Errorf("error writing to NATS server: %w", err)
+	}
+	return nil
+}
+
+func (s *NATSSink) close() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn, s.reader = nil, nil
+	}
+}
@@ -0,0 +1,122 @@
+package streaming
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeNATSServer accepts one connection, greets it, answers its ping and the HPUB with ack on
+// the reply subject, and sends everything the client wrote on the channel
+func fakeNATSServer(t *testing.T, ack string) (string, <-chan string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		defer close(received)
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		reader := bufio.NewReader(conn)
+		io.WriteString(conn, `INFO {"server_id":"test","headers":true,"max_payload":1048576}`+"\r\n")
+
+		var written strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			written.WriteString(line)
+			if line == "PING\r\n" {
+				break
+			}
+		}
+		io.WriteString(conn, "PONG\r\n")
+
+		// HPUB <subject> <reply> <header bytes> <total bytes>
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		written.WriteString(line)
+		fields := strings.Fields(line)
+		if len(fields) != 5 {
+			received <- written.String()
+			return
+		}
+		total, _ := strconv.Atoi(fields[4])
+		frame := make([]byte, total+2)
+		if _, err := io.ReadFull(reader, frame); err != nil {
+			return
+		}
+		written.Write(frame)
+		fmt.Fprintf(conn, "MSG %s 1 %d\r\n%s\r\n", fields[2], len(ack), ack)
+		received <- written.String()
+	}()
+	return listener.Addr().String(), received
+}
+
+func TestNATSWireFormat(t *testing.T) {
+	id := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	message := &domain.OutboxMessage{ID: 7, Topic: "expenditure.created", ExpenditureID: id, CreatedAt: time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)}
+	payload := `{"schema":"expense-tracker.expenditure-event","schema_version":1,"id":7,"event":"expenditure.created","expenditure_id":"` + id.String() + `","expenditure":null,"occurred_at":"2024-02-29T12:00:00Z"}`
+	// 28 bytes of headers: the version line, the message ID and the empty line ending them
+	headers := "NATS/1.0\r\nNats-Msg-Id: 7\r\n\r\n"
+	hpub := fmt.Sprintf("HPUB expense-tracker.expenditure.created _INBOX.test.7 28 %d\r\n", 28+len(payload)) + headers + payload + "\r\n"
+	stored := `{"stream":"EXPENDITURES","seq":3}`
+	anonymous := `{"headers":true,"lang":"go","name":"expense-tracker","pedantic":false,"protocol":1,"verbose":false,"version":"1"}`
+
+	tests := []struct {
+		name        string
+		config      NATSConfig
+		ack         string
+		wantConnect string // Options of the CONNECT
+		wantErr     error
+	}{
+		{"without credentials", NATSConfig{}, stored, anonymous, nil},
+		{"with a user", NATSConfig{Username: "user", Password: "pw"}, stored, `{"headers":true,"lang":"go","name":"expense-tracker","pass":"pw","pedantic":false,"protocol":1,"user":"user","verbose":false,"version":"1"}`, nil},
+		{"with a token", NATSConfig{Token: "s3cret"}, stored, `{"auth_token":"s3cret","headers":true,"lang":"go","name":"expense-tracker","pedantic":false,"protocol":1,"verbose":false,"version":"1"}`, nil},
+		{"refused by the stream", NATSConfig{}, `{"error":{"code":503,"description":"insufficient resources"}}`, anonymous, ErrNATSRejected},
+		{"answered by a plain subscriber", NATSConfig{}, `{}`, anonymous, ErrNATSRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, received := fakeNATSServer(t, tt.ack)
+			tt.config.Server = "nats://" + address
+			tt.config.Subject = "expense-tracker.{event}"
+			sink, err := NewNATSSink(tt.config, slog.New(slog.DiscardHandler))
+			if err != nil {
+				t.Fatalf("creating sink: %v", err)
+			}
+			sink.inbox = "_INBOX.test"
+			defer sink.close()
+
+			if err := sink.DeliverOutboxMessage(message); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %v, want %v", err, tt.wantErr)
+			}
+			want := "CONNECT " + tt.wantConnect + "\r\nSUB _INBOX.test.* 1\r\nPING\r\n" + hpub
+			if got := <-received; got != want {
+				t.Errorf("server received\n%q\nwant\n%q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const defaultAPIURL = "https://api.telegram.org"
+
+// Update is the subset of a Telegram update the bot cares about
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message"`
+}
+
+// Message is an incoming Telegram chat message
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	Text      string `json:"text"`
+	Chat      Chat   `json:"chat"`
+}
+
+// Chat identifies the conversation a message belongs to
+type Chat struct {
+	ID int64 `json:"id"`
+}
+
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Description string          `json:"description"`
+	Result      json.RawMessage `json:"result"`
+}
+
+// getUpdates long-polls Telegram for updates newer than offset
+func (b *Bot) getUpdates(ctx context.Context, offset int64) ([]Update, error) {
+	params := url.Values{}
+	params.Set("offset", strconv.FormatInt(offset, 10))
+	params.Set("timeout", strconv.Itoa(int(b.pollTimeout.Seconds())))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.methodURL("getUpdates")+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating getUpdates request: %w", err)
+	}
+
+	var updates []Update
+	if err := b.do(req, &updates); err != nil {
+		return nil, fmt.Errorf("error calling getUpdates: %w", err)
+	}
+	return updates, nil
+}
+
+// sendMessage posts a plain text reply to the given chat
+func (b *Bot) sendMessage(ctx context.Context, chatID int64, text string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"chat_id": chatID,
+		"text":    text,
+	})
+	if err != nil {
+		return fmt.Errorf("error encoding sendMessage body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.methodURL("sendMessage"), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating sendMessage request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if err := b.do(req, nil); err != nil {
+		return fmt.Errorf("error calling sendMessage: %w", err)
+	}
+	return nil
+}
+
+func (b *Bot) methodURL(method string) string {
+	return fmt.Sprintf("%s/bot%s/%s", b.apiURL, b.token, method)
+}
+
+func (b *Bot) do(req *http.Request, result interface{}) error {
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var apiResp apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("error decoding response (status %d): %w", resp.StatusCode, err)
+	}
+
+	if !apiResp.OK {
+		return fmt.Errorf("telegram API error (status %d): %s", resp.StatusCode, apiResp.Description)
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(apiResp.Result, result); err != nil {
+			return fmt.Errorf("error decoding result: %w", err)
+		}
+	}
+	return nil
+}
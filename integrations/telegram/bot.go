@@ -0,0 +1,242 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-expense-tracker/app"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/quickentry"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTokenEmpty     = errors.New("telegram bot token cannot be empty")
+	ErrNoAllowedChats = errors.New("telegram bot needs the IDs of the chats allowed to use it")
+)
+
+const helpText = `Send an expenditure like "coffee 3.50" or "taxi 12 #Transportation".
+Ask "today" or "this month" for your totals.`
+
+// Config holds the settings for the Telegram bot
+type Config struct {
+	Token             string
+	APIURL            string                // Defaults to the public Telegram Bot API
+	AllowedChatIDs    []int64               // Chats allowed to use the bot, at least one
+	DefaultCategoryID uuid.UUID             // Category used when a message carries no #category
+	MonthlyBudget     float64               // Monthly total above which an alert is sent, 0 disables alerts
+	Calendar          domain.FiscalCalendar // Where months begin for totals and the budget
+	PollTimeout       time.Duration         // Long-polling timeout for getUpdates
+}
+
+// Bot records expenditures sent as Telegram chat messages
+type Bot struct {
+	token             string
+	apiURL            string
+	client            *http.Client
+	pollTimeout       time.Duration
+	allowedChats      map[int64]bool
+	defaultCategoryID uuid.UUID
+	monthlyBudget     float64
+	calendar          domain.FiscalCalendar
+	expenditures      *app.ExpenditureService
+	repository        domain.ExpenditureRepository
+	categories        domain.CategoryRepository
+	rules             domain.CategorizationRuleRepository
+	logger            *slog.Logger
+}
+
+// NewBot creates a new Bot recording expenditures through expenditures like those of the API and
+// totalling them from repository; categories may be nil when the storage has no category support
+// and rules when it keeps no categorization rules. Anyone can message a bot, so it only answers the
+// chats it is given and needs at least one
+func NewBot(cfg Config, expenditures *app.ExpenditureService, repository domain.ExpenditureRepository, categories domain.CategoryRepository, rules domain.CategorizationRuleRepository, logger *slog.Logger) (*Bot, error) {
+	if cfg.Token == "" {
+		return nil, ErrTokenEmpty
+	}
+	if len(cfg.AllowedChatIDs) == 0 {
+		return nil, ErrNoAllowedChats
+	}
+
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+	}
+
+	pollTimeout := cfg.PollTimeout
+	if pollTimeout <= 0 {
+		pollTimeout = 30 * time.Second
+	}
+
+	allowedChats := make(map[int64]bool, len(cfg.AllowedChatIDs))
+	for _, id := range cfg.AllowedChatIDs {
+		allowedChats[id] = true
+	}
+
+	return &Bot{
+		token:             cfg.Token,
+		apiURL:            strings.TrimSuffix(apiURL, "/"),
+		client:            &http.Client{Timeout: pollTimeout + 10*time.Second},
+		pollTimeout:       pollTimeout,
+		allowedChats:      allowedChats,
+		defaultCategoryID: cfg.DefaultCategoryID,
+		monthlyBudget:     cfg.MonthlyBudget,
+		calendar:          cfg.Calendar,
+		expenditures:      expenditures,
+		repository:        repository,
+		categories:        categories,
+		rules:             rules,
+		logger:            logger,
+	}, nil
+}
+
+// Run polls Telegram for messages until the context is cancelled
+func (b *Bot) Run(ctx context.Context) {
+	b.logger.Info("Starting Telegram bot", "allowed_chats", len(b.allowedChats))
+
+	var offset int64
+	for {
+		updates, err := b.getUpdates(ctx, offset)
+		if err != nil {
+			if ctx.Err() != nil {
+				b.logger.Info("Stopping Telegram bot")
+				return
+			}
+			b.logger.Error("Failed to get Telegram updates", "error", err)
+
+			// Back off before polling again
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			if update.Message == nil || update.Message.Text == "" {
+				continue
+			}
+			b.handleMessage(ctx, update.Message)
+		}
+	}
+}
+
+func (b *Bot) handleMessage(ctx context.Context, msg *Message) {
+	chatID := msg.Chat.ID
+	if !b.allowedChats[chatID] {
+		b.logger.Warn("Ignoring message from unauthorized chat", "chat_id", chatID)
+		return
+	}
+
+	b.logger.Debug("Handling Telegram message", "chat_id", chatID, "message_id", msg.MessageID)
+
+	var reply string
+	switch text := strings.ToLower(strings.TrimSpace(msg.Text)); text {
+	case "/start", "/help", "help":
+		reply = helpText
+	case "today", "/today":
+		reply = b.totalReply("Today", startOfDay(time.Now()))
+	case "this month", "month", "/month":
+		monthStart, _ := b.calendar.Period(time.Now())
+		reply = b.totalReply("This month", monthStart)
+	default:
+		reply = b.recordExpenditure(ctx, chatID, msg.Text)
+	}
+
+	if err := b.sendMessage(ctx, chatID, reply); err != nil {
+		b.logger.Error("Failed to send Telegram reply", "error", err, "chat_id", chatID)
+	}
+}
+
+func (b *Bot) recordExpenditure(ctx context.Context, chatID int64, text string) string {
+	e, err := quickentry.Parse(text, time.Now())
+	if err != nil {
+		return err.Error()
+	}
+
+	categoryID, tags, err := quickentry.Categorize(b.categories, b.rules, e, b.defaultCategoryID)
+	if err != nil {
+		b.logger.Warn("Failed to resolve category", "error", err, "hashtags", e.Hashtags)
+		return err.Error()
+	}
+
+	// Month total before recording, to detect when the budget is crossed
+	monthStart, _ := b.calendar.Period(time.Now())
+	before, err := b.totalSince(monthStart)
+	if err != nil {
+		b.logger.Error("Failed to compute month total", "error", err)
+		return "Sorry, something went wrong."
+	}
+
+	result, err := b.expenditures.Create(app.ExpenditureInput{
+		Description: e.Description,
+		Amount:      e.Amount,
+		Date:        e.Date,
+		CategoryId:  categoryID,
+		Tags:        tags,
+	})
+	if err != nil {
+		if app.KindOf(err) == app.KindInternal {
+			b.logger.Error("Failed to add expenditure from Telegram", "error", err, "chat_id", chatID)
+			return "Sorry, the expenditure could not be saved."
+		}
+		b.logger.Warn("Telegram expenditure refused", "error", err, "chat_id", chatID)
+		return err.Error()
+	}
+	expenditure := result.Expenditure
+
+	b.logger.Info("Recorded expenditure from Telegram", "id", expenditure.ID, "chat_id", chatID, "amount", expenditure.Amount)
+
+	after := before
+	if !expenditure.Date.Before(monthStart) {
+		after += expenditure.Amount
+	}
+	if b.monthlyBudget > 0 && before <= b.monthlyBudget && after > b.monthlyBudget {
+		b.logger.Info("Monthly budget exceeded", "budget", b.monthlyBudget, "total", after)
+		alert := fmt.Sprintf("Budget alert: you have spent %.2f this month, over your budget of %.2f.", after, b.monthlyBudget)
+		if err := b.sendMessage(ctx, chatID, alert); err != nil {
+			b.logger.Error("Failed to send budget alert", "error", err, "chat_id", chatID)
+		}
+	}
+
+	reply := fmt.Sprintf("Recorded %q for %.2f.", expenditure.Description, expenditure.Amount)
+	if category := result.OverLimit; category != nil {
+		reply += fmt.Sprintf(" That is over the %.2f limit of %s.", category.TransactionLimit, category.Name)
+	}
+	return reply
+}
+
+func (b *Bot) totalReply(label string, since time.Time) string {
+	total, err := b.totalSince(since)
+	if err != nil {
+		b.logger.Error("Failed to compute total", "error", err, "since", since)
+		return "Sorry, something went wrong."
+	}
+	return fmt.Sprintf("%s: %.2f", label, total)
+}
+
+func (b *Bot) totalSince(since time.Time) (float64, error) {
+	expenditures, err := b.repository.GetAllExpenditures()
+	if err != nil {
+		return 0, err
+	}
+
+	var total float64
+	for _, expenditure := range expenditures {
+		if !expenditure.Date.Before(since) {
+			total += expenditure.Amount
+		}
+	}
+	return total, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
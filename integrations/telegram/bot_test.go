@@ -0,0 +1,102 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-expense-tracker/app"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/services"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewBotRequiresAllowedChats(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	if _, err := NewBot(Config{Token: "token"}, nil, nil, nil, nil, logger); !errors.Is(err, ErrNoAllowedChats) {
+		t.Errorf("without allowed chats: got %v, want %v", err, ErrNoAllowedChats)
+	}
+	if _, err := NewBot(Config{AllowedChatIDs: []int64{1}}, nil, nil, nil, nil, logger); !errors.Is(err, ErrTokenEmpty) {
+		t.Errorf("without a token: got %v, want %v", err, ErrTokenEmpty)
+	}
+}
+
+func TestHandleMessageAllowedChats(t *testing.T) {
+	var replied []int64
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ChatID int64 `json:"chat_id"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		replied = append(replied, body.ChatID)
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer api.Close()
+
+	bot, err := NewBot(Config{Token: "token", APIURL: api.URL, AllowedChatIDs: []int64{42}}, nil, nil, nil, nil, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("creating bot: %v", err)
+	}
+	for _, chat := range []int64{7, 42} {
+		bot.handleMessage(context.Background(), &Message{Text: "/help", Chat: Chat{ID: chat}})
+	}
+
+	if len(replied) != 1 || replied[0] != 42 {
+		t.Errorf("replied to %v, want only the allowed chat 42", replied)
+	}
+}
+
+func TestRecordExpenditure(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	storage := services.NewMemoryService(logger)
+	categories, err := storage.GetAllCategories()
+	if err != nil {
+		t.Fatalf("getting categories: %v", err)
+	}
+	limits := map[string]domain.CategoryLimitMode{"Transportation": domain.CategoryLimitReject, "Food & Dining": domain.CategoryLimitWarn}
+	for _, category := range categories {
+		if mode, ok := limits[category.Name]; ok {
+			if err := category.SetLimit(20, mode); err != nil {
+				t.Fatalf("setting limit: %v", err)
+			}
+			if err := storage.UpdateCategory(category); err != nil {
+				t.Fatalf("updating category: %v", err)
+			}
+		}
+	}
+
+	expenditures := app.NewExpenditureService(storage, storage, uuid.Nil, nil, nil, domain.RejectFutureDates, logger)
+	bot, err := NewBot(Config{Token: "token", AllowedChatIDs: []int64{42}}, expenditures, storage, storage, nil, logger)
+	if err != nil {
+		t.Fatalf("creating bot: %v", err)
+	}
+
+	tests := []struct {
+		text      string
+		wantReply string
+		wantSaved bool
+	}{
+		{"taxi 12 #Transportation", `Recorded "taxi" for 12.00.`, true},
+		{"taxi 50 #Transportation", domain.ErrCategoryLimitExceeded.Error(), false},
+		{"dinner 35 #Food", `Recorded "dinner" for 35.00. That is over the 20.00 limit of Food & Dining.`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			before, _ := storage.GetAllExpenditures()
+			reply := bot.recordExpenditure(context.Background(), 42, tt.text)
+			if !strings.Contains(reply, tt.wantReply) {
+				t.Errorf("reply = %q, want %q", reply, tt.wantReply)
+			}
+			after, _ := storage.GetAllExpenditures()
+			if saved := len(after) > len(before); saved != tt.wantSaved {
+				t.Errorf("saved = %v, want %v", saved, tt.wantSaved)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+// Package webhook posts JSON notifications about expense report status changes, expenditures
+// above their category's limit, anomalies and relayed expenditure changes to an HTTP endpoint,
+// e.g. a chat integration or an automation service.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExpenseReportNotification is the JSON body posted for every status change
+type ExpenseReportNotification struct {
+	ReportID   uuid.UUID                  `json:"report_id"`
+	Title      string                     `json:"title"`
+	Claimant   string                     `json:"claimant"`
+	Status     domain.ExpenseReportStatus `json:"status"`
+	Event      domain.ExpenseReportEvent  `json:"event"`
+	Expenses   int                        `json:"expenses"`
+	OccurredAt time.Time                  `json:"occurred_at"`
+}
+
+// Notifier delivers expense report notifications to a webhook URL
+type Notifier struct {
+	url    string
+	client *http.Client
+	logger *slog.Logger
+}
+
+// NewNotifier creates a new Notifier posting to url
+func NewNotifier(url string, logger *slog.Logger) *Notifier {
+	return &Notifier{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+		logger: logger,
+	}
+}
+
+// NotifyExpenseReport posts the notification without blocking the caller
+func (n *Notifier) NotifyExpenseReport(report *domain.ExpenseReport, event domain.ExpenseReportEvent) {
+	notification := ExpenseReportNotification{
+		ReportID:   report.ID,
+		Title:      report.Title,
+		Claimant:   report.Claimant,
+		Status:     report.Status,
+		Event:      event,
+		Expenses:   len(report.ExpenditureIDs),
+		OccurredAt: event.At,
+	}
+
+	go n.post("expense report", notification, "report_id", notification.ReportID, "action", event.Action)
+}
+
+// CategoryLimitNotification is the JSON body posted for an expenditure above its category's limit
+type CategoryLimitNotification struct {
+	Event         string    `json:"event"`
+	ExpenditureID uuid.UUID `json:"expenditure_id"`
+	Description   string    `json:"description"`
+	Amount        float64   `json:"amount"`
+	CategoryID    uuid.UUID `json:"category_id"`
+	Category      string    `json:"category"`
+	Limit         float64   `json:"limit"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+// NotifyCategoryLimit posts the notification without blocking the caller
+func (n *Notifier) NotifyCategoryLimit(expenditure *domain.Expenditure, category *domain.Category) {
+	notification := CategoryLimitNotification{
+		Event:         "category_limit_exceeded",
+		ExpenditureID: expenditure.ID,
+		Description:   expenditure.Description,
+		Amount:        expenditure.Amount,
+		CategoryID:    category.ID,
+		Category:      category.Name,
+		Limit:         category.TransactionLimit,
+		OccurredAt:    time.Now(),
+	}
+
+	go n.post("category limit", notification, "expenditure_id", expenditure.ID, "category_id", category.ID)
+}
+
+// AnomalyNotification is the JSON body posted for an anomaly caused by a new expenditure
+type AnomalyNotification struct {
+	Event      string          `json:"event"`
+	Anomaly    *domain.Anomaly `json:"anomaly"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// NotifyAnomaly posts the notification without blocking the caller
+func (n *Notifier) NotifyAnomaly(anomaly *domain.Anomaly) {
+	notification := AnomalyNotification{
+		Event:      "anomaly_detected",
+		Anomaly:    anomaly,
+		OccurredAt: time.Now(),
+	}
+
+	go n.post("anomaly", notification, "kind", anomaly.Kind, "expenditure_id", anomaly.ExpenditureID)
+}
+
+// post delivers one notification; attrs identify it in the logs
+func (n *Notifier) post(kind string, notification interface{}, attrs ...any) {
+	if err := n.send(notification, ""); err != nil {
+		n.logger.Error("Failed to send "+kind+" notification", append([]any{"error", err}, attrs...)...)
+		return
+	}
+
+	n.logger.Info("Sent "+kind+" notification", attrs...)
+}
+
+// OutboxNotification is the JSON body posted for every change to an expenditure relayed from
+// the outbox. Receivers may see a message twice and should skip IDs they already handled
+type OutboxNotification struct {
+	ID          int64               `json:"id"`
+	Event       string              `json:"event"`
+	Expenditure *domain.Expenditure `json:"expenditure"`
+	OccurredAt  time.Time           `json:"occurred_at"`
+}
+
+// DeliverOutboxMessage posts the message and waits for the webhook to accept it. The message ID
+// is sent as the Idempotency-Key header too
+func (n *Notifier) DeliverOutboxMessage(message *domain.OutboxMessage) error {
+	notification := OutboxNotification{
+		ID:          message.ID,
+		Event:       message.Topic,
+		Expenditure: message.Expenditure,
+		OccurredAt:  message.CreatedAt,
+	}
+	return n.send(notification, strconv.FormatInt(message.ID, 10))
+}
+
+// send posts the notification, with an optional idempotency key, and fails unless the webhook
+// answers with a 2xx status
+func (n *Notifier) send(notification interface{}, idempotencyKey string) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered with status %d", resp.StatusCode)
+	}
+	return nil
+}
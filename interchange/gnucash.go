@@ -0,0 +1,318 @@
+package interchange
+
+import (
+	"encoding/csv"
+	"fmt"
+	"go-expense-tracker/domain"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// gnuCashHeader is the header of GnuCash's transaction export, one row per split
+var gnuCashHeader = []string{"Date", "Transaction ID", "Number", "Description", "Notes", "Commodity/Currency", "Void Reason", "Action", "Memo", "Full Account Name", "Account Name", "Amount With Sym", "Amount Num.", "Value With Sym", "Value Num.", "Reconcile", "Reconcile Date", "Rate/Price"}
+
+// gnuCashSplit is a split of a GnuCash transaction: the amount it moves on one account
+type gnuCashSplit struct {
+	account  string   // Full name, e.g. Expenses:Food:Groceries
+	expense  bool     // Whether the account is an expense account
+	category []string // Accounts below the top-level expense account, for expense splits
+	memo     string
+	amount   float64
+}
+
+// gnuCashTransaction is a GnuCash transaction with its splits
+type gnuCashTransaction struct {
+	id          string
+	date        string
+	description string
+	splits      []gnuCashSplit
+}
+
+// spending turns the expense splits of a transaction into transactions to import, paid from the
+// first account the money came out of. Splits crediting an expense account, such as refunds, are
+// skipped and counted
+func (t gnuCashTransaction) spending(reference string, date time.Time) ([]Transaction, int) {
+	account := ""
+	for _, split := range t.splits {
+		if !split.expense && split.amount < 0 {
+			parts := strings.Split(split.account, ":")
+			account = parts[len(parts)-1]
+			break
+		}
+	}
+
+	var transactions []Transaction
+	skipped := 0
+	for i, split := range t.splits {
+		if !split.expense {
+			continue
+		}
+		if split.amount <= 0 {
+			skipped++
+			continue
+		}
+
+		transactions = append(transactions, Transaction{
+			Reference:   fmt.Sprintf("%s:%d", reference, i),
+			Date:        date,
+			Description: describe(t.description, split.memo),
+			Amount:      split.amount,
+			Category:    split.category,
+			Account:     account,
+		})
+	}
+	return transactions, skipped
+}
+
+// expenseCategory returns the accounts of an expense account below the top-level expense
+// account, which become the category path
+func expenseCategory(account, expenses string) []string {
+	if account == expenses {
+		return nil
+	}
+	return strings.Split(strings.TrimPrefix(account, expenses+":"), ":")
+}
+
+// ReadGnuCashCSV reads the spending of a GnuCash transaction export: every split on an account
+// below expenses, by default Expenses, becomes a transaction. Transactions without an expense split,
+// such as transfers and income, are skipped and counted. Dates are read in the layout, or in the
+// unambiguous formats when it is empty
+func ReadGnuCashCSV(r io.Reader, layout, expenses string) ([]Transaction, int, error) {
+	if expenses == "" {
+		expenses = DefaultExpenses
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, 0, fmt.Errorf("header: %w", err)
+	}
+	c := newColumns(header)
+	date, err := c.require("date")
+	if err != nil {
+		return nil, 0, err
+	}
+	account, err := c.require("full account name", "account")
+	if err != nil {
+		return nil, 0, err
+	}
+	amount, err := c.require("amount num", "value num", "amount")
+	if err != nil {
+		return nil, 0, err
+	}
+	id := c.index("transaction id")
+	description := c.index("description")
+	memo := c.index("memo")
+
+	var transactions []Transaction
+	skipped := 0
+	var current *gnuCashTransaction
+	seen := make(map[string]int)
+	flush := func(line int) error {
+		if current == nil {
+			return nil
+		}
+
+		reference := current.id
+		if reference == "" {
+			// Exports of old versions have no IDs: identical transactions are told apart by how
+			// often they occurred
+			key := current.date + "\x00" + current.description
+			seen[key]++
+			reference = fmt.Sprintf("%s\x00%d", key, seen[key])
+		}
+		day, err := parseDate(current.date, layout)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		spending, skips := current.spending(reference, day)
+		if len(spending) == 0 && skips == 0 {
+			skipped++
+		}
+		transactions = append(transactions, spending...)
+		skipped += skips
+		current = nil
+		return nil
+	}
+
+	line := 1
+	for {
+		line++
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", line, err)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		// A row starts a new transaction when it names one, else it is a further split of the
+		// transaction above, as GnuCash writes them in its layout of one transaction per line
+		rowID, rowDate := field(record, id), field(record, date)
+		if current == nil || (rowID != "" && rowID != current.id) || (rowID == "" && rowDate != "") {
+			if err := flush(line - 1); err != nil {
+				return nil, 0, err
+			}
+			current = &gnuCashTransaction{id: rowID, date: rowDate, description: field(record, description)}
+		}
+
+		name := field(record, account)
+		if name == "" {
+			continue
+		}
+		value, err := parseAmount(field(record, amount))
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", line, err)
+		}
+		split := gnuCashSplit{account: name, memo: field(record, memo), amount: value}
+		if name == expenses || strings.HasPrefix(name, expenses+":") {
+			split.expense = true
+			split.category = expenseCategory(name, expenses)
+		}
+		current.splits = append(current.splits, split)
+	}
+	if err := flush(line - 1); err != nil {
+		return nil, 0, err
+	}
+	return transactions, skipped, nil
+}
+
+// gnuCashAccounts names the GnuCash accounts of categories, below Expenses, and of the accounts
+// expenditures are paid from, below Assets
+type gnuCashAccounts struct {
+	names *names
+}
+
+// expense returns the full name of the expense account of a category
+func (a gnuCashAccounts) expense(categoryID uuid.UUID) string {
+	path := a.names.category(categoryID)
+	if len(path) == 0 {
+		return DefaultExpenses + ":Uncategorized"
+	}
+	for i, name := range path {
+		path[i] = gnuCashName(name)
+	}
+	return DefaultExpenses + ":" + strings.Join(path, ":")
+}
+
+// funding returns the full name of the account an expenditure was paid from
+func (a gnuCashAccounts) funding(accountID uuid.UUID) string {
+	if name := a.names.accounts[accountID]; name != "" {
+		return "Assets:" + gnuCashName(name)
+	}
+	return DefaultFunding
+}
+
+// gnuCashName keeps a name from being read as a path, as GnuCash separates accounts with colons
+func gnuCashName(name string) string {
+	return strings.ReplaceAll(name, ":", "-")
+}
+
+// gnuCashReconcile returns the reconciliation state of an expenditure: not cleared (n), cleared (c)
+// or reconciled (y)
+func gnuCashReconcile(expenditure *domain.Expenditure) string {
+	switch {
+	case expenditure.Reconciled:
+		return "y"
+	case expenditure.IsPending():
+		return "n"
+	default:
+		return "c"
+	}
+}
+
+// gnuCashGUID writes an ID the way GnuCash does, as 32 hexadecimal digits
+func gnuCashGUID(id uuid.UUID) string {
+	return strings.ReplaceAll(id.String(), "-", "")
+}
+
+// gnuCashCSV writes expenditures as a GnuCash transaction export: a split debiting the expense
+// account of the category and one crediting the account paid from
+type gnuCashCSV struct {
+	w        *csv.Writer
+	options  Options
+	names    *names
+	accounts gnuCashAccounts
+	count    int
+	started  bool // Whether the header is written
+	flushed  bool // Whether anything has been passed on to the writer
+}
+
+func newGnuCashCSV(w io.Writer, options Options, names *names) *gnuCashCSV {
+	return &gnuCashCSV{w: csv.NewWriter(w), options: options, names: names, accounts: gnuCashAccounts{names}}
+}
+
+func (g *gnuCashCSV) Count() int {
+	return g.count
+}
+
+func (g *gnuCashCSV) Started() bool {
+	return g.flushed
+}
+
+func (g *gnuCashCSV) Write(expenditure *domain.Expenditure) error {
+	if err := g.header(); err != nil {
+		return err
+	}
+
+	currency := ""
+	if g.options.Currency != "" {
+		currency = "CURRENCY::" + g.options.Currency
+	}
+	description, memo := g.names.payee(expenditure)
+	date := expenditure.Date.Format(g.options.DateLayout)
+	id := gnuCashGUID(expenditure.ID)
+	reconcile := gnuCashReconcile(expenditure)
+
+	for _, split := range []struct {
+		account string
+		amount  float64
+	}{
+		{g.accounts.expense(expenditure.CategoryId), expenditure.Amount},
+		{g.accounts.funding(expenditure.AccountId), -expenditure.Amount},
+	} {
+		parts := strings.Split(split.account, ":")
+		amount := formatAmount(split.amount)
+		err := g.w.Write([]string{
+			date, id, "", description, "", currency, "", "", memo, split.account, parts[len(parts)-1],
+			amount, amount, amount, amount, reconcile, "", "1.00",
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	g.count++
+	if g.count%1000 == 0 {
+		g.flushed = true
+		g.w.Flush()
+		return g.w.Error()
+	}
+	return nil
+}
+
+func (g *gnuCashCSV) Close() error {
+	if err := g.header(); err != nil {
+		return err
+	}
+	g.flushed = true
+	g.w.Flush()
+	return g.w.Error()
+}
+
+func (g *gnuCashCSV) header() error {
+	if g.started {
+		return nil
+	}
+	g.started = true
+	return g.w.Write(gnuCashHeader)
+}
@@ -0,0 +1,283 @@
+package interchange
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidGnuCashBook = errors.New("invalid GnuCash book")
+
+// gnuCashNamespace derives the IDs of the accounts of exported books from their names, so the same
+// category keeps its account in every export
+var gnuCashNamespace = uuid.MustParse("6f1c3a5e-8d2b-4f7a-9c61-2e4b8a0d5f93")
+
+// gnuCashXMLBook is the part of a GnuCash XML file read: the accounts and transactions of its book.
+// Elements are matched by their names without the namespace prefixes
+type gnuCashXMLBook struct {
+	Accounts     []gnuCashXMLAccount     `xml:"book>account"`
+	Transactions []gnuCashXMLTransaction `xml:"book>transaction"`
+}
+
+type gnuCashXMLAccount struct {
+	Name   string `xml:"name"`
+	ID     string `xml:"id"`
+	Type   string `xml:"type"`
+	Parent string `xml:"parent"`
+}
+
+type gnuCashXMLTransaction struct {
+	ID          string            `xml:"id"`
+	Posted      string            `xml:"date-posted>date"`
+	Description string            `xml:"description"`
+	Splits      []gnuCashXMLSplit `xml:"splits>split"`
+}
+
+type gnuCashXMLSplit struct {
+	Memo    string `xml:"memo"`
+	Value   string `xml:"value"`
+	Account string `xml:"account"`
+}
+
+// ReadGnuCashXML reads the spending of a GnuCash XML book, compressed as GnuCash saves it or not:
+// every split on an expense account becomes a transaction, with the accounts below the top-level
+// expense account as its category. Transactions without an expense split, such as transfers and
+// income, and refunds crediting an expense account are skipped and counted
+func ReadGnuCashXML(r io.Reader) ([]Transaction, int, error) {
+	buffered := bufio.NewReader(r)
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		unzipped, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: %v", ErrInvalidGnuCashBook, err)
+		}
+		defer unzipped.Close()
+		r = unzipped
+	} else {
+		r = buffered
+	}
+
+	var book gnuCashXMLBook
+	if err := xml.NewDecoder(r).Decode(&book); err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrInvalidGnuCashBook, err)
+	}
+
+	accounts := make(map[string]gnuCashXMLAccount, len(book.Accounts))
+	for _, account := range book.Accounts {
+		accounts[account.ID] = account
+	}
+	// path returns the names of an account and its parents below the root account
+	path := func(id string) []string {
+		var names []string
+		seen := make(map[string]bool)
+		for account, ok := accounts[id]; ok && account.Type != "ROOT" && !seen[account.ID]; account, ok = accounts[account.Parent] {
+			seen[account.ID] = true
+			names = append([]string{account.Name}, names...)
+		}
+		return names
+	}
+
+	var transactions []Transaction
+	skipped := 0
+	for _, xmlTransaction := range book.Transactions {
+		posted, err := time.Parse("2006-01-02 15:04:05 -0700", strings.TrimSpace(xmlTransaction.Posted))
+		if err != nil {
+			return nil, 0, fmt.Errorf("transaction %s: %w", xmlTransaction.ID, ErrInvalidDate)
+		}
+		transaction := gnuCashTransaction{id: xmlTransaction.ID, description: xmlTransaction.Description}
+		for _, xmlSplit := range xmlTransaction.Splits {
+			value, err := parseGnuCashValue(xmlSplit.Value)
+			if err != nil {
+				return nil, 0, fmt.Errorf("transaction %s: %w", xmlTransaction.ID, err)
+			}
+			names := path(xmlSplit.Account)
+			split := gnuCashSplit{account: strings.Join(names, ":"), memo: xmlSplit.Memo, amount: value}
+			if accounts[xmlSplit.Account].Type == "EXPENSE" && len(names) > 0 {
+				split.expense = true
+				split.category = names[1:]
+			}
+			transaction.splits = append(transaction.splits, split)
+		}
+
+		spending, skips := transaction.spending(transaction.id, time.Date(posted.Year(), posted.Month(), posted.Day(), 0, 0, 0, 0, time.UTC))
+		if len(spending) == 0 && skips == 0 {
+			skipped++
+		}
+		transactions = append(transactions, spending...)
+		skipped += skips
+	}
+	return transactions, skipped, nil
+}
+
+// parseGnuCashValue reads an amount as GnuCash stores it, a fraction such as 1234/100
+func parseGnuCashValue(value string) (float64, error) {
+	numerator, denominator, found := strings.Cut(strings.TrimSpace(value), "/")
+	n, err := strconv.ParseInt(numerator, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidAmount
+	}
+	d := int64(1)
+	if found {
+		if d, err = strconv.ParseInt(denominator, 10, 64); err != nil || d == 0 {
+			return 0, ErrInvalidAmount
+		}
+	}
+	return float64(n) / float64(d), nil
+}
+
+// gnuCashBookEntry is an expenditure kept for a GnuCash book, as books list their accounts before
+// their transactions
+type gnuCashBookEntry struct {
+	id          uuid.UUID
+	date        time.Time
+	description string
+	memo        string
+	expense     string
+	funding     string
+	cents       int64
+	reconcile   string
+}
+
+// gnuCashBook writes expenditures as a GnuCash XML book with an account per category and per
+// account paid from. The book is written on Close, once all accounts are known
+type gnuCashBook struct {
+	w        *bufio.Writer
+	options  Options
+	names    *names
+	accounts gnuCashAccounts
+	entries  []gnuCashBookEntry
+	flushed  bool // Whether anything has been passed on to the writer
+}
+
+func newGnuCashBook(w io.Writer, options Options, names *names) *gnuCashBook {
+	if options.Currency == "" {
+		options.Currency = DefaultCurrency
+	}
+	return &gnuCashBook{w: bufio.NewWriter(w), options: options, names: names, accounts: gnuCashAccounts{names}}
+}
+
+func (g *gnuCashBook) Count() int {
+	return len(g.entries)
+}
+
+func (g *gnuCashBook) Started() bool {
+	return g.flushed
+}
+
+func (g *gnuCashBook) Write(expenditure *domain.Expenditure) error {
+	description, memo := g.names.payee(expenditure)
+	g.entries = append(g.entries, gnuCashBookEntry{
+		id:          expenditure.ID,
+		date:        expenditure.Date,
+		description: description,
+		memo:        memo,
+		expense:     g.accounts.expense(expenditure.CategoryId),
+		funding:     g.accounts.funding(expenditure.AccountId),
+		cents:       int64(math.Round(expenditure.Amount * 100)),
+		reconcile:   gnuCashReconcile(expenditure),
+	})
+	return nil
+}
+
+func (g *gnuCashBook) Close() error {
+	// Every account is listed after its parent, below the root account
+	accounts := map[string]bool{}
+	for _, entry := range g.entries {
+		for _, name := range []string{entry.expense, entry.funding} {
+			for parts := strings.Split(name, ":"); len(parts) > 0; parts = parts[:len(parts)-1] {
+				accounts[strings.Join(parts, ":")] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(accounts))
+	for name := range accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := g.w
+	commodity := "<cmdty:space>CURRENCY</cmdty:space><cmdty:id>" + escape(g.options.Currency) + "</cmdty:id>"
+	root := gnuCashAccountID("")
+
+	fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8" ?>`+"\n")
+	fmt.Fprint(w, `<gnc-v2 xmlns:gnc="http://www.gnucash.org/XML/gnc" xmlns:act="http://www.gnucash.org/XML/act" xmlns:book="http://www.gnucash.org/XML/book" xmlns:cd="http://www.gnucash.org/XML/cd" xmlns:cmdty="http://www.gnucash.org/XML/cmdty" xmlns:trn="http://www.gnucash.org/XML/trn" xmlns:split="http://www.gnucash.org/XML/split" xmlns:ts="http://www.gnucash.org/XML/ts">`+"\n")
+	fmt.Fprint(w, `<gnc:count-data cd:type="book">1</gnc:count-data>`+"\n")
+	fmt.Fprint(w, `<gnc:book version="2.0.0">`+"\n")
+	fmt.Fprintf(w, "<book:id type=\"guid\">%s</book:id>\n", gnuCashGUID(uuid.New()))
+	fmt.Fprint(w, `<gnc:count-data cd:type="commodity">1</gnc:count-data>`+"\n")
+	fmt.Fprintf(w, "<gnc:count-data cd:type=\"account\">%d</gnc:count-data>\n", len(names)+1)
+	fmt.Fprintf(w, "<gnc:count-data cd:type=\"transaction\">%d</gnc:count-data>\n", len(g.entries))
+	fmt.Fprintf(w, "<gnc:commodity version=\"2.0.0\">%s</gnc:commodity>\n", commodity)
+
+	fmt.Fprintf(w, "<gnc:account version=\"2.0.0\"><act:name>Root Account</act:name><act:id type=\"guid\">%s</act:id><act:type>ROOT</act:type></gnc:account>\n", root)
+	for _, name := range names {
+		parent := root
+		leaf := name
+		if i := strings.LastIndex(name, ":"); i >= 0 {
+			parent = gnuCashAccountID(name[:i])
+			leaf = name[i+1:]
+		}
+		fmt.Fprintf(w, "<gnc:account version=\"2.0.0\"><act:name>%s</act:name><act:id type=\"guid\">%s</act:id><act:type>%s</act:type><act:commodity>%s</act:commodity><act:commodity-scu>100</act:commodity-scu><act:parent type=\"guid\">%s</act:parent></gnc:account>\n",
+			escape(leaf), gnuCashAccountID(name), gnuCashAccountType(name), commodity, parent)
+	}
+
+	for _, entry := range g.entries {
+		date := entry.date.Format("2006-01-02") + " 00:00:00 +0000"
+		fmt.Fprintf(w, "<gnc:transaction version=\"2.0.0\"><trn:id type=\"guid\">%s</trn:id><trn:currency>%s</trn:currency>", gnuCashGUID(entry.id), commodity)
+		fmt.Fprintf(w, "<trn:date-posted><ts:date>%s</ts:date></trn:date-posted><trn:date-entered><ts:date>%s</ts:date></trn:date-entered>", date, date)
+		fmt.Fprintf(w, "<trn:description>%s</trn:description><trn:splits>", escape(entry.description))
+		for _, split := range []struct {
+			account string
+			cents   int64
+			memo    string
+		}{{entry.expense, entry.cents, entry.memo}, {entry.funding, -entry.cents, ""}} {
+			fmt.Fprintf(w, "<trn:split><split:id type=\"guid\">%s</split:id>", gnuCashGUID(uuid.NewSHA1(entry.id, []byte(split.account))))
+			if split.memo != "" {
+				fmt.Fprintf(w, "<split:memo>%s</split:memo>", escape(split.memo))
+			}
+			fmt.Fprintf(w, "<split:reconciled-state>%s</split:reconciled-state><split:value>%d/100</split:value><split:quantity>%d/100</split:quantity><split:account type=\"guid\">%s</split:account></trn:split>",
+				entry.reconcile, split.cents, split.cents, gnuCashAccountID(split.account))
+		}
+		fmt.Fprint(w, "</trn:splits></gnc:transaction>\n")
+		if w.Available() < 1024 {
+			g.flushed = true
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	fmt.Fprint(w, "</gnc:book>\n</gnc-v2>\n")
+	g.flushed = true
+	return w.Flush()
+}
+
+// gnuCashAccountID returns the ID of an account by its full name, the root account for ""
+func gnuCashAccountID(name string) string {
+	return gnuCashGUID(uuid.NewSHA1(gnuCashNamespace, []byte(name)))
+}
+
+// gnuCashAccountType returns the type of an account of an exported book by its top-level account
+func gnuCashAccountType(name string) string {
+	if name == DefaultExpenses || strings.HasPrefix(name, DefaultExpenses+":") {
+		return "EXPENSE"
+	}
+	return "ASSET"
+}
+
+// escape writes text as XML character data
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
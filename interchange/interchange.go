@@ -0,0 +1,311 @@
+// Package interchange reads and writes the files of other personal finance tools, so a history can
+// be moved in from them and out to them: YNAB's register and plan CSVs, and GnuCash's transaction
+// CSV and XML book. Categories travel as paths from their top-level category, which become YNAB
+// category groups and categories, or GnuCash expense accounts below Expenses.
+package interchange
+
+import (
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidFormat = errors.New("invalid interchange format, use ynab, gnucash or gnucash-xml")
+var ErrInvalidDateFormat = errors.New("invalid date format, use YYYY-MM-DD, MM/DD/YYYY, DD/MM/YYYY or DD.MM.YYYY")
+var ErrMissingColumn = errors.New("missing column")
+var ErrInvalidAmount = errors.New("invalid amount")
+var ErrInvalidDate = errors.New("invalid date")
+
+// Format is the file layout of another tool
+type Format string
+
+const (
+	FormatYNAB       Format = "ynab"        // YNAB register CSV
+	FormatGnuCash    Format = "gnucash"     // GnuCash transaction CSV
+	FormatGnuCashXML Format = "gnucash-xml" // GnuCash XML book, gzipped or not
+)
+
+// Default names of the GnuCash accounts expenditures are booked on
+const (
+	DefaultExpenses = "Expenses"
+	DefaultFunding  = "Assets:Cash" // Pays expenditures recorded without an account
+)
+
+// DefaultCurrency is the currency of GnuCash books without a currency configured, as GnuCash
+// requires one on every account and transaction
+const DefaultCurrency = "USD"
+
+// ParseFormat reads the format of a file
+func ParseFormat(format string) (Format, error) {
+	switch Format(format) {
+	case FormatYNAB, FormatGnuCash, FormatGnuCashXML:
+		return Format(format), nil
+	default:
+		return "", ErrInvalidFormat
+	}
+}
+
+// Extension returns the file extension of files in the format
+func (f Format) Extension() string {
+	if f == FormatGnuCashXML {
+		return "gnucash"
+	}
+	return "csv"
+}
+
+// ContentType returns the media type of files in the format
+func (f Format) ContentType() string {
+	if f == FormatGnuCashXML {
+		return "application/xml; charset=utf-8"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// dateFormats are the date formats files may be written in, to the Go layouts reading them
+var dateFormats = map[string]string{
+	"YYYY-MM-DD": "2006-01-02",
+	"MM/DD/YYYY": "01/02/2006",
+	"DD/MM/YYYY": "02/01/2006",
+	"DD.MM.YYYY": "02.01.2006",
+}
+
+// ParseDateFormat returns the Go layout of a date format such as DD/MM/YYYY; an empty format
+// returns an empty layout, which lets readers recognize the unambiguous formats on their own
+func ParseDateFormat(format string) (string, error) {
+	if format == "" {
+		return "", nil
+	}
+	layout, ok := dateFormats[strings.ToUpper(format)]
+	if !ok {
+		return "", ErrInvalidDateFormat
+	}
+	return layout, nil
+}
+
+// Transaction is a purchase read from another tool, ready to become an expenditure
+type Transaction struct {
+	Reference   string    // Identifies the transaction within its file, the same on every import
+	Date        time.Time // Day of the transaction in UTC
+	Description string
+	Amount      float64  // Spent, always positive
+	Category    []string // Names of the category and its parents from the top level, empty when uncategorized
+	Account     string   // Name of the account paid from, empty when unknown
+	Pending     bool     // Whether the transaction has not cleared yet
+}
+
+// Options configures the files written
+type Options struct {
+	DateLayout string // Go layout of the dates, YYYY-MM-DD when empty
+	Currency   string // Currency of the amounts, DefaultCurrency for GnuCash books when empty
+}
+
+// Writer writes expenditures as a file of another tool, one at a time
+type Writer interface {
+	Write(expenditure *domain.Expenditure) error
+	// Close finishes the file
+	Close() error
+	// Count returns the number of expenditures written
+	Count() int
+	// Started reports whether anything has been passed on to the writer, after which errors can
+	// no longer be reported as a response of their own
+	Started() bool
+}
+
+// NewWriter creates a Writer of the format writing to w; categories, accounts and merchants name the
+// categories, accounts and payees and may be nil when the storage has no support for them
+func NewWriter(w io.Writer, format Format, options Options, categories []*domain.Category, accounts []*domain.Account, merchants []*domain.Merchant) Writer {
+	if options.DateLayout == "" {
+		options.DateLayout = "2006-01-02"
+	}
+	names := newNames(categories, accounts, merchants)
+
+	switch format {
+	case FormatGnuCash:
+		return newGnuCashCSV(w, options, names)
+	case FormatGnuCashXML:
+		return newGnuCashBook(w, options, names)
+	default:
+		return newYNABRegister(w, options, names)
+	}
+}
+
+// names resolves the category paths, accounts and merchants of expenditures
+type names struct {
+	categories map[uuid.UUID]*domain.Category
+	accounts   map[uuid.UUID]string
+	merchants  map[uuid.UUID]string
+}
+
+func newNames(categories []*domain.Category, accounts []*domain.Account, merchants []*domain.Merchant) *names {
+	n := &names{
+		categories: make(map[uuid.UUID]*domain.Category, len(categories)),
+		accounts:   make(map[uuid.UUID]string, len(accounts)),
+		merchants:  make(map[uuid.UUID]string, len(merchants)),
+	}
+	for _, category := range categories {
+		n.categories[category.ID] = category
+	}
+	for _, account := range accounts {
+		n.accounts[account.ID] = account.Name
+	}
+	for _, merchant := range merchants {
+		n.merchants[merchant.ID] = merchant.Name
+	}
+	return n
+}
+
+// category returns the names of a category and its parents from the top level, nil when the
+// category is unknown
+func (n *names) category(id uuid.UUID) []string {
+	var path []string
+	seen := make(map[uuid.UUID]bool)
+	for category := n.categories[id]; category != nil && !seen[category.ID]; category = n.categories[category.ParentID] {
+		seen[category.ID] = true
+		path = append([]string{category.Name}, path...)
+	}
+	return path
+}
+
+// payee returns who was paid and what for: the merchant and the description, or the description
+// alone for expenditures without a merchant
+func (n *names) payee(expenditure *domain.Expenditure) (string, string) {
+	if merchant := n.merchants[expenditure.MerchantId]; merchant != "" {
+		return merchant, expenditure.Description
+	}
+	return expenditure.Description, ""
+}
+
+// describe joins a payee and a memo into the description of an expenditure
+func describe(payee, memo string) string {
+	payee, memo = strings.TrimSpace(payee), strings.TrimSpace(memo)
+	switch {
+	case payee == "":
+		return memo
+	case memo == "" || strings.EqualFold(payee, memo):
+		return payee
+	default:
+		return payee + ": " + memo
+	}
+}
+
+// columns finds the columns of a CSV header, by their lower-case names without a trailing period
+type columns map[string]int
+
+func newColumns(header []string) columns {
+	c := make(columns, len(header))
+	for i, name := range header {
+		name = strings.TrimPrefix(name, "\ufeff") // Byte order mark of files saved by spreadsheets
+		name = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+		if _, ok := c[name]; !ok {
+			c[name] = i
+		}
+	}
+	return c
+}
+
+// index returns the column of the first of the names found, -1 when none is
+func (c columns) index(names ...string) int {
+	for _, name := range names {
+		if i, ok := c[name]; ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// require returns the column of the first of the names found, failing when none is
+func (c columns) require(names ...string) (int, error) {
+	i := c.index(names...)
+	if i < 0 {
+		return 0, fmt.Errorf("%w %q", ErrMissingColumn, names[0])
+	}
+	return i, nil
+}
+
+// field returns the trimmed value of a column of a record, empty when the column is missing
+func field(record []string, i int) string {
+	if i < 0 || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// parseAmount reads an amount as tools format it for their locale, e.g. "$1,234.56", "1.234,56 €"
+// or "(12.00)"; empty amounts are 0. Of a comma and a period the last one is the decimal
+// separator, and a lone separator followed by three digits groups thousands
+func parseAmount(value string) (float64, error) {
+	negative := strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")")
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsDigit(r) || r == '.' || r == ',':
+			return r
+		case r == '-' || r == '−':
+			negative = true
+		}
+		return -1
+	}, value)
+	if cleaned == "" {
+		return 0, nil
+	}
+
+	comma, period := strings.LastIndex(cleaned, ","), strings.LastIndex(cleaned, ".")
+	decimal := byte(0)
+	switch {
+	case comma >= 0 && period >= 0:
+		decimal = cleaned[max(comma, period)]
+	case comma >= 0 && (strings.Count(cleaned, ",") > 1 || len(cleaned)-comma == 4):
+	case comma >= 0:
+		decimal = ','
+	case period >= 0 && (strings.Count(cleaned, ".") > 1 || len(cleaned)-period == 4):
+	case period >= 0:
+		decimal = '.'
+	}
+
+	var digits strings.Builder
+	for i := 0; i < len(cleaned); i++ {
+		switch c := cleaned[i]; {
+		case c == decimal:
+			digits.WriteByte('.')
+		case c != ',' && c != '.':
+			digits.WriteByte(c)
+		}
+	}
+	amount, err := strconv.ParseFloat(digits.String(), 64)
+	if err != nil {
+		return 0, ErrInvalidAmount
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// parseDate reads a date in the layout, or in one of the unambiguous formats when the layout is
+// empty; MM/DD/YYYY is taken for slashes, as it is the default of both YNAB and GnuCash
+func parseDate(value, layout string) (time.Time, error) {
+	layouts := []string{layout}
+	if layout == "" {
+		layouts = []string{"2006-01-02", "01/02/2006", "02.01.2006", "2006/01/02", "1/2/2006", "2.1.2006"}
+	}
+	for _, layout := range layouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, ErrInvalidDate
+}
+
+// formatAmount writes an amount with two decimals, without the sign of a negative zero
+func formatAmount(amount float64) string {
+	if amount == 0 {
+		amount = 0
+	}
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
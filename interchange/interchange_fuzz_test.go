@@ -0,0 +1,79 @@
+package interchange_test
+
+import (
+	"bytes"
+	"go-expense-tracker/interchange"
+	"math"
+	"testing"
+)
+
+// checkTransactions asserts what importers rely on of every transaction read from a file
+func checkTransactions(t *testing.T, transactions []interchange.Transaction, skipped int) {
+	t.Helper()
+	if skipped < 0 {
+		t.Errorf("skipped = %d, want at least 0", skipped)
+	}
+	for _, tx := range transactions {
+		if math.IsNaN(tx.Amount) || math.IsInf(tx.Amount, 0) || tx.Amount <= 0 {
+			t.Errorf("transaction %q has amount %v, want a positive number", tx.Reference, tx.Amount)
+		}
+		if tx.Date.IsZero() {
+			t.Errorf("transaction %q has no date", tx.Reference)
+		}
+	}
+}
+
+func FuzzReadYNABRegister(f *testing.F) {
+	f.Add([]byte("Account,Flag,Date,Payee,Category Group/Category,Category Group,Category,Memo,Outflow,Inflow,Cleared\n"+
+		"Checking,,03/04/2024,Corner Shop,Food: Groceries,Food,Groceries,,$12.50,$0.00,Cleared\n"+
+		"Checking,,03/05/2024,Transfer : Savings,,,,,$100.00,$0.00,Cleared\n"), "01/02/2006")
+	f.Add([]byte("Date,Payee,Outflow\n2024-03-04,\"Shop, \"\"The\"\"\",1.234,56\n"), "")
+	f.Fuzz(func(t *testing.T, data []byte, layout string) {
+		transactions, skipped, err := interchange.ReadYNABRegister(bytes.NewReader(data), layout)
+		if err == nil {
+			checkTransactions(t, transactions, skipped)
+		}
+	})
+}
+
+func FuzzReadYNABPlan(f *testing.F) {
+	f.Add([]byte("Month,Category Group/Category,Category Group,Category,Budgeted,Activity,Available\n" +
+		"Mar 2024,Food: Groceries,Food,Groceries,$400.00,-$12.50,$387.50\n"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		plans, skipped, err := interchange.ReadYNABPlan(bytes.NewReader(data))
+		if err == nil && skipped < 0 {
+			t.Errorf("skipped = %d for %d plans, want at least 0", skipped, len(plans))
+		}
+	})
+}
+
+func FuzzReadGnuCashCSV(f *testing.F) {
+	f.Add([]byte("Date,Transaction ID,Number,Description,Notes,Commodity/Currency,Void Reason,Action,Memo,Full Account Name,Account Name,Amount With Sym,Amount Num.,Reconcile,Reconcile Date,Rate/Price\n"+
+		"2024-03-04,abc123,,Corner Shop,,CURRENCY::EUR,,,,Expenses:Food:Groceries,Groceries,€12.50,12.50,n,,1.00\n"+
+		",,,,,,,,,Assets:Checking,Checking,-€12.50,-12.50,n,,1.00\n"), "", "")
+	f.Fuzz(func(t *testing.T, data []byte, layout, expenses string) {
+		transactions, skipped, err := interchange.ReadGnuCashCSV(bytes.NewReader(data), layout, expenses)
+		if err == nil {
+			checkTransactions(t, transactions, skipped)
+		}
+	})
+}
+
+func FuzzReadGnuCashXML(f *testing.F) {
+	f.Add([]byte(`<?xml version="1.0" encoding="utf-8" ?>
+<gnc-v2 xmlns:gnc="http://www.gnucash.org/XML/gnc" xmlns:act="http://www.gnucash.org/XML/act" xmlns:trn="http://www.gnucash.org/XML/trn" xmlns:split="http://www.gnucash.org/XML/split" xmlns:ts="http://www.gnucash.org/XML/ts">
+<gnc:book>
+<gnc:account><act:name>Expenses</act:name><act:id>e1</act:id><act:type>EXPENSE</act:type></gnc:account>
+<gnc:account><act:name>Food</act:name><act:id>e2</act:id><act:type>EXPENSE</act:type><act:parent>e1</act:parent></gnc:account>
+<gnc:transaction><trn:id>t1</trn:id><trn:date-posted><ts:date>2024-03-04 10:59:00 +0000</ts:date></trn:date-posted><trn:description>Corner Shop</trn:description>
+<trn:splits><trn:split><split:value>1250/100</split:value><split:account>e2</split:account></trn:split></trn:splits></gnc:transaction>
+</gnc:book>
+</gnc-v2>`))
+	f.Add([]byte{0x1f, 0x8b, 0x08, 0x00})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		transactions, skipped, err := interchange.ReadGnuCashXML(bytes.NewReader(data))
+		if err == nil {
+			checkTransactions(t, transactions, skipped)
+		}
+	})
+}
@@ -0,0 +1,335 @@
+package interchange
+
+import (
+	"encoding/csv"
+	"fmt"
+	"go-expense-tracker/domain"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ynabHeader is the header of YNAB's register export
+var ynabHeader = []string{"Account", "Flag", "Date", "Payee", "Category Group/Category", "Category Group", "Category", "Memo", "Outflow", "Inflow", "Cleared"}
+
+// ynabPlanHeader is the header of YNAB's plan export, the budget of every category per month
+var ynabPlanHeader = []string{"Month", "Category Group/Category", "Category Group", "Category", "Assigned", "Activity", "Available"}
+
+// ynabTransfer starts the payee of transfers between accounts, which are not spending
+const ynabTransfer = "transfer : "
+
+// ynabIncomeGroups are the category groups of money coming in and of paying off credit cards,
+// which YNAB keeps next to the spending categories
+var ynabIncomeGroups = map[string]bool{"inflow": true, "credit card payments": true}
+
+// ReadYNABRegister reads the transactions of a YNAB register export. Only outflows are spending:
+// inflows, transfers between accounts and outflows categorized as income are skipped and counted.
+// Category groups and categories become top-level categories and their subcategories, except
+// that a category named like its group stays top-level. Dates are read in the layout, or in the
+// unambiguous formats when it is empty
+func ReadYNABRegister(r io.Reader, layout string) ([]Transaction, int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, 0, fmt.Errorf("header: %w", err)
+	}
+	c := newColumns(header)
+	date, err := c.require("date")
+	if err != nil {
+		return nil, 0, err
+	}
+	payee, err := c.require("payee")
+	if err != nil {
+		return nil, 0, err
+	}
+	outflow, err := c.require("outflow")
+	if err != nil {
+		return nil, 0, err
+	}
+	inflow := c.index("inflow")
+	account := c.index("account")
+	memo := c.index("memo")
+	cleared := c.index("cleared")
+	group := c.index("category group", "master category")
+	category := c.index("category", "sub category")
+	combined := c.index("category group/category")
+
+	var transactions []Transaction
+	skipped := 0
+	seen := make(map[string]int)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", line, err)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		spent, err := parseAmount(field(record, outflow))
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", line, err)
+		}
+		received, err := parseAmount(field(record, inflow))
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", line, err)
+		}
+		path := ynabCategory(field(record, group), field(record, category), field(record, combined))
+		if spent-received <= 0 || strings.HasPrefix(strings.ToLower(field(record, payee)), ynabTransfer) ||
+			(len(path) > 0 && ynabIncomeGroups[strings.ToLower(path[0])]) {
+			skipped++
+			continue
+		}
+
+		day, err := parseDate(field(record, date), layout)
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		transaction := Transaction{
+			Date:        day,
+			Description: describe(field(record, payee), field(record, memo)),
+			Amount:      spent - received,
+			Category:    path,
+			Account:     field(record, account),
+			Pending:     strings.EqualFold(field(record, cleared), "uncleared"),
+		}
+
+		// The register has no IDs: identical rows are told apart by how often they occurred
+		key := strings.Join(append([]string{transaction.Account, day.Format("2006-01-02"), transaction.Description, formatAmount(transaction.Amount)}, path...), "\x00")
+		seen[key]++
+		transaction.Reference = fmt.Sprintf("%s\x00%d", key, seen[key])
+		transactions = append(transactions, transaction)
+	}
+	return transactions, skipped, nil
+}
+
+// ynabCategory returns the category path of a register or plan row, from the group and category
+// columns or else from the combined one, "Group: Category"
+func ynabCategory(group, category, combined string) []string {
+	if group == "" && category == "" && combined != "" {
+		group, category, _ = strings.Cut(combined, ":")
+		group, category = strings.TrimSpace(group), strings.TrimSpace(category)
+	}
+	switch {
+	case group == "" && category == "":
+		return nil
+	case group == "":
+		return []string{category}
+	case category == "" || strings.EqualFold(group, category):
+		return []string{group}
+	default:
+		return []string{group, category}
+	}
+}
+
+// ynabGroup splits a category path into a YNAB category group and category. YNAB has no deeper
+// nesting, so subcategories below the second level are named after their whole path, and
+// top-level categories are both group and category
+func ynabGroup(path []string) (string, string) {
+	switch len(path) {
+	case 0:
+		return "", ""
+	case 1:
+		return path[0], path[0]
+	default:
+		return path[0], strings.Join(path[1:], " / ")
+	}
+}
+
+// ynabRegister writes expenditures as a YNAB register export
+type ynabRegister struct {
+	w       *csv.Writer
+	options Options
+	names   *names
+	count   int
+	started bool // Whether the header is written
+	flushed bool // Whether anything has been passed on to the writer
+}
+
+func newYNABRegister(w io.Writer, options Options, names *names) *ynabRegister {
+	return &ynabRegister{w: csv.NewWriter(w), options: options, names: names}
+}
+
+func (y *ynabRegister) Count() int {
+	return y.count
+}
+
+func (y *ynabRegister) Started() bool {
+	return y.flushed
+}
+
+func (y *ynabRegister) Write(expenditure *domain.Expenditure) error {
+	if err := y.header(); err != nil {
+		return err
+	}
+
+	account := y.names.accounts[expenditure.AccountId]
+	if account == "" {
+		account = "Cash"
+	}
+	payee, memo := y.names.payee(expenditure)
+	group, category := ynabGroup(y.names.category(expenditure.CategoryId))
+	combined := ""
+	if group != "" {
+		combined = group + ": " + category
+	}
+	outflow, inflow := expenditure.Amount, 0.0
+	if outflow < 0 { // Refunds
+		outflow, inflow = 0, -outflow
+	}
+	cleared := "Cleared"
+	switch {
+	case expenditure.Reconciled:
+		cleared = "Reconciled"
+	case expenditure.IsPending():
+		cleared = "Uncleared"
+	}
+
+	err := y.w.Write([]string{
+		account, "", expenditure.Date.Format(y.options.DateLayout), payee, combined, group, category, memo,
+		formatAmount(outflow), formatAmount(inflow), cleared,
+	})
+	if err != nil {
+		return err
+	}
+	y.count++
+	if y.count%1000 == 0 {
+		y.flushed = true
+		y.w.Flush()
+		return y.w.Error()
+	}
+	return nil
+}
+
+func (y *ynabRegister) Close() error {
+	if err := y.header(); err != nil {
+		return err
+	}
+	y.flushed = true
+	y.w.Flush()
+	return y.w.Error()
+}
+
+func (y *ynabRegister) header() error {
+	if y.started {
+		return nil
+	}
+	y.started = true
+	return y.w.Write(ynabHeader)
+}
+
+// BudgetPlan is the budget of a category in one month, read from a YNAB plan export
+type BudgetPlan struct {
+	Month     time.Time // First day of the month
+	Category  []string  // Names of the category and its parents from the top level
+	Assigned  float64   // Amount budgeted for the month
+	Activity  float64   // Spent in the month, negative
+	Available float64   // Left at the end of the month, with what carried over
+}
+
+// ReadYNABPlan reads the budgets of a YNAB plan export, once called the budget export. Rows of
+// the income and credit card payment groups are skipped and counted
+func ReadYNABPlan(r io.Reader) ([]BudgetPlan, int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, 0, fmt.Errorf("header: %w", err)
+	}
+	c := newColumns(header)
+	month, err := c.require("month")
+	if err != nil {
+		return nil, 0, err
+	}
+	assigned, err := c.require("assigned", "budgeted")
+	if err != nil {
+		return nil, 0, err
+	}
+	activity := c.index("activity", "outflows")
+	available := c.index("available", "category balance")
+	group := c.index("category group", "master category")
+	category := c.index("category", "sub category")
+	combined := c.index("category group/category")
+
+	var plans []BudgetPlan
+	skipped := 0
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", line, err)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		path := ynabCategory(field(record, group), field(record, category), field(record, combined))
+		if len(path) == 0 || ynabIncomeGroups[strings.ToLower(path[0])] {
+			skipped++
+			continue
+		}
+
+		first, err := parseMonth(field(record, month))
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", line, err)
+		}
+		plan := BudgetPlan{Month: first, Category: path}
+		for _, amount := range []struct {
+			column int
+			value  *float64
+		}{{assigned, &plan.Assigned}, {activity, &plan.Activity}, {available, &plan.Available}} {
+			if *amount.value, err = parseAmount(field(record, amount.column)); err != nil {
+				return nil, 0, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		plans = append(plans, plan)
+	}
+	return plans, skipped, nil
+}
+
+// parseMonth reads the month of a plan row, e.g. "Jun 2024", "June 2024" or "2024-06"
+func parseMonth(value string) (time.Time, error) {
+	for _, layout := range []string{"Jan 2006", "January 2006", "2006-01", "01/2006", "2006-01-02"} {
+		if month, err := time.Parse(layout, value); err == nil {
+			return time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+	return time.Time{}, ErrInvalidDate
+}
+
+// WriteYNABPlan writes the statuses of budgets on categories as a YNAB plan export: the limit is
+// assigned, the spending is the activity and the remaining amount is available. Statuses are those
+// of the budgets, in order; budgets on all spending have no category to be planned on and are left out
+func WriteYNABPlan(w io.Writer, statuses []domain.BudgetStatus, budgets []*domain.Budget, categories []*domain.Category) error {
+	writer := csv.NewWriter(w)
+	writer.Write(ynabPlanHeader)
+	names := newNames(categories, nil, nil)
+	for i, status := range statuses {
+		if budgets[i].CategoryId == uuid.Nil {
+			continue
+		}
+		group, category := ynabGroup(names.category(budgets[i].CategoryId))
+		if group == "" {
+			continue
+		}
+		writer.Write([]string{
+			status.From.Format("Jan 2006"), group + ": " + category, group, category,
+			formatAmount(status.Limit), formatAmount(-status.Spent), formatAmount(status.Remaining),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,173 @@
+// Package jobs runs reports and exports in the background and keeps their results in memory
+// for download, so large results are not tied to a long-lived HTTP response.
+package jobs
+
+import (
+	"bytes"
+	"fmt"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	job    domain.Job
+	header http.Header
+	result []byte
+}
+
+// response collects what a job writes, in the same way a handler writes its response
+type response struct {
+	header http.Header
+	body   bytes.Buffer
+	status int
+}
+
+func (r *response) Header() http.Header {
+	return r.header
+}
+
+func (r *response) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(p)
+}
+
+func (r *response) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+// Runner runs jobs in background goroutines. Jobs and their results are kept in memory until
+// they expire, so they do not survive a restart
+type Runner struct {
+	expiry time.Duration
+	jobs   map[string]*entry
+	logger *slog.Logger
+	mu     sync.Mutex
+}
+
+// NewRunner creates a new Runner keeping jobs for expiry after they were started
+func NewRunner(expiry time.Duration, logger *slog.Logger) *Runner {
+	return &Runner{
+		expiry: expiry,
+		jobs:   make(map[string]*entry),
+		logger: logger,
+	}
+}
+
+// Start runs produce in the background. produce writes the job's result like a handler writes
+// its response: the body, Content-Type and Content-Disposition are kept for download, an error
+// status fails the job
+func (r *Runner) Start(kind string, rows int, produce func(w http.ResponseWriter) error) *domain.Job {
+	job := domain.NewJob(kind, rows)
+
+	r.mu.Lock()
+	r.prune(job.CreatedAt)
+	r.jobs[job.ID.String()] = &entry{job: *job}
+	r.mu.Unlock()
+
+	r.logger.Info("Started job", "id", job.ID, "kind", kind, "rows", rows)
+	go r.run(job.ID.String(), produce)
+	return job
+}
+
+func (r *Runner) run(id string, produce func(w http.ResponseWriter) error) {
+	resp := &response{header: make(http.Header)}
+	err := func() (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = fmt.Errorf("job panicked: %v", p)
+			}
+		}()
+		return produce(resp)
+	}()
+	if err == nil && resp.status >= http.StatusBadRequest {
+		err = fmt.Errorf("%s", bytes.TrimSpace(resp.body.Bytes()))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.jobs[id]
+	if !ok {
+		return
+	}
+	now := time.Now()
+	e.job.CompletedAt = &now
+	if err != nil {
+		e.job.Status = domain.JobFailed
+		e.job.Error = err.Error()
+		r.logger.Error("Job failed", "id", id, "kind", e.job.Kind, "error", err)
+		return
+	}
+	e.job.Status = domain.JobCompleted
+	e.header = make(http.Header)
+	for _, key := range []string{"Content-Type", "Content-Disposition"} {
+		if value := resp.header.Get(key); value != "" {
+			e.header.Set(key, value)
+		}
+	}
+	e.result = resp.body.Bytes()
+	r.logger.Info("Job completed", "id", id, "kind", e.job.Kind, "bytes", len(e.result), "duration", now.Sub(e.job.CreatedAt).String())
+}
+
+// Get returns a copy of the job
+func (r *Runner) Get(id string) (*domain.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.jobs[id]
+	if !ok {
+		return nil, domain.ErrJobNotFound
+	}
+	job := e.job
+	return &job, nil
+}
+
+// Recent returns the jobs that have not expired yet, newest first
+func (r *Runner) Recent() []*domain.Job {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune(time.Now())
+	jobs := make([]*domain.Job, 0, len(r.jobs))
+	for _, e := range r.jobs {
+		job := e.job
+		jobs = append(jobs, &job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+	return jobs
+}
+
+// Result returns the output of a completed job with its Content-Type and Content-Disposition
+// headers
+func (r *Runner) Result(id string) ([]byte, http.Header, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.jobs[id]
+	if !ok {
+		return nil, nil, domain.ErrJobNotFound
+	}
+	if e.job.Status != domain.JobCompleted {
+		return nil, nil, domain.ErrJobNotCompleted
+	}
+	return e.result, e.header.Clone(), nil
+}
+
+// prune drops expired jobs; the caller holds the lock
+func (r *Runner) prune(now time.Time) {
+	for id, e := range r.jobs {
+		if now.Sub(e.job.CreatedAt) > r.expiry {
+			delete(r.jobs, id)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package leader runs the background subsystems of the server, such as the schedulers and the
+// outbox relay, on one replica at a time: the replica holding the leader lock of the storage
+// runs them, and another takes over once it lets go of the lock or loses it.
+package leader
+
+import (
+	"context"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Subsystem runs in the background until its context is cancelled. It is run again from scratch
+// whenever the replica becomes the leader once more
+type Subsystem interface {
+	Run(ctx context.Context)
+}
+
+type subsystem struct {
+	name string
+	Subsystem
+}
+
+// Elector competes for the leader lock and runs the subsystems while it holds it
+type Elector struct {
+	locker     domain.LeaderLocker
+	name       string
+	interval   time.Duration
+	subsystems []subsystem
+	leading    atomic.Bool
+	logger     *slog.Logger
+}
+
+// Status is whether this replica runs the background subsystems
+type Status struct {
+	Elected    bool     `json:"elected"` // False when every replica runs the subsystems
+	Lock       string   `json:"lock,omitempty"`
+	Leading    bool     `json:"leading"`
+	Subsystems []string `json:"subsystems"`
+}
+
+// NewElector creates a new Elector trying to take the lock of name, and checking it is still
+// held, every interval. A nil locker, for a storage only one replica can use, runs the
+// subsystems at once
+func NewElector(locker domain.LeaderLocker, name string, interval time.Duration, logger *slog.Logger) *Elector {
+	return &Elector{
+		locker:   locker,
+		name:     name,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Add registers a subsystem to run on the leader; subsystems are added before Run
+func (e *Elector) Add(name string, s Subsystem) {
+	e.subsystems = append(e.subsystems, subsystem{name: name, Subsystem: s})
+}
+
+// Status returns whether this replica leads and the subsystems run by the leader
+func (e *Elector) Status() Status {
+	status := Status{Elected: e.locker != nil, Leading: e.leading.Load(), Subsystems: e.names()}
+	if status.Elected {
+		status.Lock = e.name
+	}
+	return status
+}
+
+// Run competes for the lock until the context is cancelled, leading whenever it holds it
+func (e *Elector) Run(ctx context.Context) {
+	if len(e.subsystems) == 0 {
+		return
+	}
+	if e.locker == nil {
+		e.lead(ctx, nil)
+		return
+	}
+
+	e.logger.Info("Starting leader election", "lock", e.name, "interval", e.interval.String(), "subsystems", len(e.subsystems))
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for ctx.Err() == nil {
+		lock, err := e.locker.TryLeaderLock(ctx, e.name)
+		if err != nil {
+			e.logger.Warn("Failed to take the leader lock", "error", err, "lock", e.name)
+		} else if lock != nil {
+			e.lead(ctx, lock)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+	}
+	e.logger.Info("Stopping leader election")
+}
+
+// lead runs the subsystems until the context is cancelled or the lock is lost, then stops them
+// and waits for them to return before letting go of the lock, so two replicas never run them
+// at once
+func (e *Elector) lead(ctx context.Context, lock domain.LeaderLock) {
+	e.leading.Store(true)
+	defer e.leading.Store(false)
+
+	leadCtx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	for _, s := range e.subsystems {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Run(leadCtx)
+		}()
+	}
+
+	if lock == nil {
+		<-ctx.Done()
+	} else {
+		e.logger.Info("Became the leader, starting background subsystems", "lock", e.name, "subsystems", e.names())
+		ticker := time.NewTicker(e.interval)
+	check:
+		for {
+			select {
+			case <-ctx.Done():
+				break check
+			case <-ticker.C:
+			}
+
+			checkCtx, cancelCheck := context.WithTimeout(ctx, e.interval)
+			err := lock.Check(checkCtx)
+			cancelCheck()
+			if err != nil {
+				e.logger.Error("Lost the leader lock, stopping background subsystems", "error", err, "lock", e.name)
+				break check
+			}
+		}
+		ticker.Stop()
+	}
+
+	cancel()
+	wg.Wait()
+	if lock != nil {
+		if err := lock.Release(); err != nil {
+			e.logger.Warn("Failed to release the leader lock", "error", err, "lock", e.name)
+		}
+	}
+}
+
+func (e *Elector) names() []string {
+	names := make([]string, len(e.subsystems))
+	for i, s := range e.subsystems {
+		names[i] = s.name
+	}
+	return names
+}
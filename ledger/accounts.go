@@ -0,0 +1,140 @@
+// Package ledger writes expenditures as plain-text accounting journals, for Ledger and hledger or
+// for Beancount, so their double-entry reports can be run over the spending. Every expenditure
+// becomes a transaction moving its amount from the account it was paid from to the expense
+// account of its category.
+package ledger
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidAccountMapping = errors.New("account mappings are name=Account, e.g. Groceries=Expenses:Food:Groceries")
+
+// Default names of the accounts expenditures are booked on when the chart of accounts sets none
+const (
+	DefaultExpenses      = "Expenses"
+	DefaultFunding       = "Assets:Cash"
+	DefaultUncategorized = "Uncategorized"
+)
+
+// ChartOfAccounts decides the accounts of a journal. A category is booked on the expense account
+// named after it and its parents, e.g. Expenses:Food:Groceries, and an account expenditures are paid
+// from on Assets:{name}, unless the mapping names another account for it
+type ChartOfAccounts struct {
+	Expenses string            // Root of the expense accounts of categories
+	Funding  string            // Account paying expenditures recorded without one
+	Currency string            // Commodity of the amounts in the home currency, may be empty for Ledger
+	Mapping  map[string]string // Category and account names to the journal accounts they are booked on
+}
+
+// ParseAccountMapping reads comma-separated mappings such as
+// "Groceries=Expenses:Food:Groceries,Visa=Liabilities:Visa"; names are matched case-insensitively
+func ParseAccountMapping(config string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	for _, entry := range strings.Split(config, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+
+		name, account, found := strings.Cut(entry, "=")
+		name, account = strings.TrimSpace(name), strings.TrimSpace(account)
+		if !found || name == "" || account == "" || strings.ContainsAny(account, " \t") {
+			return nil, ErrInvalidAccountMapping
+		}
+		mapping[strings.ToLower(name)] = account
+	}
+	return mapping, nil
+}
+
+// accountNames resolves the journal accounts of categories and of the accounts paid from
+type accountNames struct {
+	chart      ChartOfAccounts
+	categories map[uuid.UUID]*domain.Category
+	accounts   map[uuid.UUID]*domain.Account
+	resolved   map[uuid.UUID]string
+}
+
+func newAccountNames(chart ChartOfAccounts, categories []*domain.Category, accounts []*domain.Account) *accountNames {
+	if chart.Expenses == "" {
+		chart.Expenses = DefaultExpenses
+	}
+	if chart.Funding == "" {
+		chart.Funding = DefaultFunding
+	}
+
+	names := &accountNames{
+		chart:      chart,
+		categories: make(map[uuid.UUID]*domain.Category, len(categories)),
+		accounts:   make(map[uuid.UUID]*domain.Account, len(accounts)),
+		resolved:   make(map[uuid.UUID]string),
+	}
+	for _, category := range categories {
+		names.categories[category.ID] = category
+	}
+	for _, account := range accounts {
+		names.accounts[account.ID] = account
+	}
+	return names
+}
+
+// expense returns the expense account of a category, below the account of its parent
+func (n *accountNames) expense(categoryID uuid.UUID) string {
+	if name, ok := n.resolved[categoryID]; ok {
+		return name
+	}
+
+	category := n.categories[categoryID]
+	if category == nil {
+		return n.mapped(DefaultUncategorized, n.chart.Expenses+":"+DefaultUncategorized)
+	}
+
+	// Resolving the parent first; marking the category guards against a cycle of parents
+	n.resolved[categoryID] = n.chart.Expenses + ":" + segment(category.Name)
+	parent := n.chart.Expenses
+	if category.ParentID != uuid.Nil && category.ParentID != categoryID && n.categories[category.ParentID] != nil {
+		parent = n.expense(category.ParentID)
+	}
+	name := n.mapped(category.Name, parent+":"+segment(category.Name))
+	n.resolved[categoryID] = name
+	return name
+}
+
+// funding returns the account an expenditure was paid from
+func (n *accountNames) funding(accountID uuid.UUID) string {
+	account := n.accounts[accountID]
+	if account == nil {
+		return n.chart.Funding
+	}
+	return n.mapped(account.Name, "Assets:"+segment(account.Name))
+}
+
+func (n *accountNames) mapped(name, fallback string) string {
+	if account, ok := n.chart.Mapping[strings.ToLower(name)]; ok {
+		return account
+	}
+	return fallback
+}
+
+// segment turns a name into a component of an account name that both Ledger and Beancount accept:
+// capitalized words of letters and digits joined by dashes, e.g. "food & drink" becomes Food-Drink
+func segment(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+
+	s := strings.Join(words, "-")
+	if s == "" || !unicode.IsUpper([]rune(s)[0]) {
+		s = "X" + s // Beancount requires components to start with a capital letter
+	}
+	return s
+}
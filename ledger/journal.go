@@ -0,0 +1,243 @@
+package ledger
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidFormat = errors.New("invalid journal format, use ledger or beancount")
+
+// Format is the plain-text accounting syntax of a journal
+type Format string
+
+const (
+	FormatLedger    Format = "ledger"    // Ledger and hledger
+	FormatBeancount Format = "beancount" // Beancount
+)
+
+// DefaultBeancountCurrency is the commodity of Beancount journals without a currency configured,
+// as Beancount requires one on every amount
+const DefaultBeancountCurrency = "USD"
+
+// ParseFormat reads the format of a journal
+func ParseFormat(format string) (Format, error) {
+	switch Format(format) {
+	case FormatLedger, FormatBeancount:
+		return Format(format), nil
+	default:
+		return "", ErrInvalidFormat
+	}
+}
+
+// Extension returns the file extension of journals in the format
+func (f Format) Extension() string {
+	if f == FormatBeancount {
+		return "beancount"
+	}
+	return "ledger"
+}
+
+// Journal writes expenditures as transactions, one at a time. Cleared expenditures are marked
+// cleared (*) and pending ones pending (!); foreign ones keep their original amount, priced at
+// the home amount paid
+type Journal struct {
+	w         *bufio.Writer
+	format    Format
+	currency  string
+	names     *accountNames
+	merchants map[uuid.UUID]string
+	opened    map[string]time.Time // Accounts used, with the first day they were, for Beancount
+	count     int
+	started   bool // Whether the header is written
+	flushed   bool // Whether anything has been passed on to the writer
+}
+
+// NewJournal creates a Journal writing to w; categories, accounts and merchants name the accounts
+// and payees and may be nil when the storage has no support for them
+func NewJournal(w io.Writer, format Format, chart ChartOfAccounts, categories []*domain.Category, accounts []*domain.Account, merchants []*domain.Merchant) *Journal {
+	currency := chart.Currency
+	if currency == "" && format == FormatBeancount {
+		currency = DefaultBeancountCurrency
+	}
+
+	journal := &Journal{
+		w:         bufio.NewWriter(w),
+		format:    format,
+		currency:  currency,
+		names:     newAccountNames(chart, categories, accounts),
+		merchants: make(map[uuid.UUID]string, len(merchants)),
+		opened:    make(map[string]time.Time),
+	}
+	for _, merchant := range merchants {
+		journal.merchants[merchant.ID] = merchant.Name
+	}
+	return journal
+}
+
+// Count returns the number of transactions written
+func (j *Journal) Count() int {
+	return j.count
+}
+
+// Started reports whether anything has been passed on to the writer, after which errors can no
+// longer be reported as a response of their own
+func (j *Journal) Started() bool {
+	return j.flushed
+}
+
+// Write adds the transaction of an expenditure
+func (j *Journal) Write(expenditure *domain.Expenditure) error {
+	j.header()
+
+	expense := j.names.expense(expenditure.CategoryId)
+	funding := j.names.funding(expenditure.AccountId)
+	j.open(expense, expenditure.Date)
+	j.open(funding, expenditure.Date)
+
+	flag := "*"
+	if expenditure.IsPending() {
+		flag = "!"
+	}
+	date := expenditure.Date.Format("2006-01-02")
+	spent := j.amount(expenditure.Amount, j.currency)
+	if expenditure.IsForeign() {
+		spent = j.amount(expenditure.OriginalAmount, expenditure.OriginalCurrency) + " @@ " + j.amount(abs(expenditure.Amount), j.currency)
+	}
+
+	if j.format == FormatBeancount {
+		fmt.Fprintf(j.w, "%s %s", date, flag)
+		if merchant := j.merchants[expenditure.MerchantId]; merchant != "" {
+			fmt.Fprintf(j.w, " %s", quote(merchant))
+		}
+		fmt.Fprintf(j.w, " %s", quote(expenditure.Description))
+		for _, tag := range expenditure.Tags {
+			if tag = beancountTag(tag); tag != "" {
+				fmt.Fprintf(j.w, " #%s", tag)
+			}
+		}
+		fmt.Fprintf(j.w, "\n  id: %s\n", quote(expenditure.ID.String()))
+		fmt.Fprintf(j.w, "  %s  %s\n", expense, spent)
+		fmt.Fprintf(j.w, "  %s  %s\n\n", funding, j.amount(-expenditure.Amount, j.currency))
+	} else {
+		payee := expenditure.Description
+		if merchant := j.merchants[expenditure.MerchantId]; merchant != "" {
+			payee = merchant + " | " + expenditure.Description
+		}
+		fmt.Fprintf(j.w, "%s %s %s\n", date, flag, oneLine(payee))
+		fmt.Fprintf(j.w, "    ; id: %s\n", expenditure.ID)
+		if len(expenditure.Tags) > 0 {
+			fmt.Fprintf(j.w, "    ; :%s:\n", strings.Join(ledgerTags(expenditure.Tags), ":"))
+		}
+		fmt.Fprintf(j.w, "    %s  %s\n", expense, spent)
+		fmt.Fprintf(j.w, "    %s  %s\n\n", funding, j.amount(-expenditure.Amount, j.currency))
+	}
+
+	j.count++
+	return j.flushIfFull()
+}
+
+// Close finishes the journal; Beancount journals get the opening of every account used, dated the
+// day it was first used on
+func (j *Journal) Close() error {
+	j.header()
+
+	if j.format == FormatBeancount && len(j.opened) > 0 {
+		accounts := make([]string, 0, len(j.opened))
+		for account := range j.opened {
+			accounts = append(accounts, account)
+		}
+		sort.Strings(accounts)
+		for _, account := range accounts {
+			fmt.Fprintf(j.w, "%s open %s\n", j.opened[account].Format("2006-01-02"), account)
+		}
+	}
+	j.flushed = true
+	return j.w.Flush()
+}
+
+// header starts the journal with a comment, and the operating currency for Beancount
+func (j *Journal) header() {
+	if j.started {
+		return
+	}
+	j.started = true
+
+	if j.format == FormatBeancount {
+		fmt.Fprintf(j.w, "; Expenditures exported on %s\n", time.Now().Format("2006-01-02"))
+		fmt.Fprintf(j.w, "option \"operating_currency\" %s\n\n", quote(j.currency))
+	} else {
+		fmt.Fprintf(j.w, "; Expenditures exported on %s\n\n", time.Now().Format("2006-01-02"))
+	}
+}
+
+// open records the first day an account is used
+func (j *Journal) open(account string, date time.Time) {
+	if first, ok := j.opened[account]; !ok || date.Before(first) {
+		j.opened[account] = date
+	}
+}
+
+// flushIfFull passes the buffered transactions on once the buffer is nearly full, so that write
+// errors surface while exporting
+func (j *Journal) flushIfFull() error {
+	if j.w.Available() < 1024 {
+		j.flushed = true
+		return j.w.Flush()
+	}
+	return nil
+}
+
+// amount formats an amount with its commodity, which Ledger allows to be empty
+func (j *Journal) amount(amount float64, currency string) string {
+	if currency == "" {
+		return fmt.Sprintf("%.2f", amount)
+	}
+	return fmt.Sprintf("%.2f %s", amount, currency)
+}
+
+func abs(amount float64) float64 {
+	if amount < 0 {
+		return -amount
+	}
+	return amount
+}
+
+// quote writes a Beancount string
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ").Replace(s) + `"`
+}
+
+// oneLine keeps a payee on its line, as Ledger reads a transaction line by line
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// beancountTag keeps the characters Beancount allows in tags
+func beancountTag(tag string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_/.", r) {
+			return r
+		}
+		return '-'
+	}, tag)
+}
+
+// ledgerTags keeps tags from breaking the tag list, which is separated by colons
+func ledgerTags(tags []string) []string {
+	cleaned := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = strings.NewReplacer(":", "-", " ", "-").Replace(tag); tag != "" {
+			cleaned = append(cleaned, tag)
+		}
+	}
+	return cleaned
+}
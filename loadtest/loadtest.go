@@ -0,0 +1,271 @@
+// Package loadtest exercises a running instance of the API with concurrent requests to create,
+// list and report on expenditures, measures their latency and checks it against a performance
+// budget, so regressions in the repository layer show up before they reach production.
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrInvalidBudget = errors.New("invalid budget, use endpoint=duration pairs such as create=200ms,list=300ms")
+
+// Description marks the expenditures created by a load test, which are deleted afterwards
+const Description = "Load test expenditure"
+
+// Endpoints exercised by a load test
+const (
+	Create = "create" // POST /expenditures
+	List   = "list"   // GET /expenditures, one page
+	Report = "report" // GET /reports/categories of the current month
+)
+
+// Endpoints lists the endpoints in the order they are reported
+var Endpoints = []string{Create, List, Report}
+
+// DefaultBudget is the p99 latency each endpoint must stay within
+var DefaultBudget = Budget{Create: 200 * time.Millisecond, List: 300 * time.Millisecond, Report: 500 * time.Millisecond}
+
+// Budget is the maximum p99 latency per endpoint
+type Budget map[string]time.Duration
+
+// ParseBudget reads a budget such as "create=200ms,list=300ms"; endpoints left out keep their
+// value of base
+func ParseBudget(s string, base Budget) (Budget, error) {
+	budget := make(Budget, len(base))
+	for endpoint, limit := range base {
+		budget[endpoint] = limit
+	}
+	if strings.TrimSpace(s) == "" {
+		return budget, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		endpoint, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || !slices.Contains(Endpoints, endpoint) {
+			return nil, ErrInvalidBudget
+		}
+		limit, err := time.ParseDuration(value)
+		if err != nil || limit <= 0 {
+			return nil, ErrInvalidBudget
+		}
+		budget[endpoint] = limit
+	}
+	return budget, nil
+}
+
+// Options configures a load test
+type Options struct {
+	BaseURL     string        // Base URL of the running instance, e.g. http://localhost:8080
+	Duration    time.Duration // How long requests are sent
+	Concurrency int           // Number of clients sending requests at once
+	PageSize    int           // Page size of the list requests
+	Keep        bool          // Keep the created expenditures instead of deleting them afterwards
+}
+
+// Result holds the latencies measured for an endpoint
+type Result struct {
+	Endpoint  string
+	Requests  int
+	Errors    int   // Requests that failed or got a status other than 2xx
+	LastError error // Error of the last failed request
+	latencies []time.Duration
+}
+
+// Percentile returns the latency p percent of the successful requests stayed within, by the
+// nearest-rank method
+func (r *Result) Percentile(p float64) time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(r.latencies))))
+	return r.latencies[min(max(rank, 1), len(r.latencies))-1]
+}
+
+// Max returns the slowest successful request
+func (r *Result) Max() time.Duration {
+	return r.Percentile(100)
+}
+
+// Summary is the outcome of a load test
+type Summary struct {
+	Duration time.Duration
+	Results  []*Result // In the order of Endpoints
+	Deleted  int       // Created expenditures deleted afterwards
+}
+
+// Violation is an endpoint whose p99 latency exceeded its budget
+type Violation struct {
+	Endpoint string
+	P99      time.Duration
+	Budget   time.Duration
+}
+
+// Check returns the endpoints over budget
+func (s *Summary) Check(budget Budget) []Violation {
+	var violations []Violation
+	for _, result := range s.Results {
+		limit, ok := budget[result.Endpoint]
+		if ok && result.Percentile(99) > limit {
+			violations = append(violations, Violation{result.Endpoint, result.Percentile(99), limit})
+		}
+	}
+	return violations
+}
+
+// Write prints a table of the latencies per endpoint
+func (s *Summary) Write(w io.Writer) {
+	fmt.Fprintf(w, "%-8s %9s %7s %10s %10s %10s %10s %9s\n", "endpoint", "requests", "errors", "p50", "p90", "p99", "max", "req/s")
+	for _, result := range s.Results {
+		rate := float64(result.Requests) / s.Duration.Seconds()
+		fmt.Fprintf(w, "%-8s %9d %7d %10v %10v %10v %10v %9.1f\n", result.Endpoint, result.Requests, result.Errors,
+			result.Percentile(50).Round(time.Microsecond), result.Percentile(90).Round(time.Microsecond),
+			result.Percentile(99).Round(time.Microsecond), result.Max().Round(time.Microsecond), rate)
+	}
+}
+
+// Run sends requests to the instance until the duration is over or ctx is done, cycling
+// through create, list and report requests, and deletes the expenditures it created
+func Run(ctx context.Context, client *http.Client, opts Options) (*Summary, error) {
+	base := strings.TrimSuffix(opts.BaseURL, "/")
+	// Check the instance is up, so a wrong URL fails at once rather than as a wall of errors
+	if err := ping(ctx, client, base); err != nil {
+		return nil, err
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		results = make(map[string]*Result, len(Endpoints))
+		created []string
+		wg      sync.WaitGroup
+	)
+	for _, endpoint := range Endpoints {
+		results[endpoint] = &Result{Endpoint: endpoint}
+	}
+
+	start := time.Now()
+	for worker := range opts.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Workers start at different endpoints, so each is busy from the first request
+			for i := worker; runCtx.Err() == nil; i++ {
+				endpoint := Endpoints[i%len(Endpoints)]
+				began := time.Now()
+				id, err := send(runCtx, client, base, endpoint, opts.PageSize)
+				elapsed := time.Since(began)
+				if runCtx.Err() != nil {
+					return // Cut short by the end of the run, not measured
+				}
+
+				mu.Lock()
+				result := results[endpoint]
+				result.Requests++
+				if err != nil {
+					result.Errors++
+					result.LastError = err
+				} else {
+					result.latencies = append(result.latencies, elapsed)
+				}
+				if id != "" {
+					created = append(created, id)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	summary := &Summary{Duration: time.Since(start)}
+	for _, endpoint := range Endpoints {
+		result := results[endpoint]
+		sort.Slice(result.latencies, func(i, j int) bool { return result.latencies[i] < result.latencies[j] })
+		summary.Results = append(summary.Results, result)
+	}
+
+	if !opts.Keep {
+		for _, id := range created {
+			if err := do(ctx, client, http.MethodDelete, base+"/expenditures/"+id, nil, nil); err != nil {
+				return summary, fmt.Errorf("error deleting load test expenditure %s: %w", id, err)
+			}
+			summary.Deleted++
+		}
+	}
+	return summary, nil
+}
+
+func ping(ctx context.Context, client *http.Client, base string) error {
+	if err := do(ctx, client, http.MethodGet, base+"/expenditures?limit=1", nil, nil); err != nil {
+		return fmt.Errorf("instance at %s is not reachable: %w", base, err)
+	}
+	return nil
+}
+
+// send makes one request to the endpoint and returns the ID of the expenditure it created, if any
+func send(ctx context.Context, client *http.Client, base, endpoint string, pageSize int) (string, error) {
+	now := time.Now().UTC()
+	switch endpoint {
+	case Create:
+		body, err := json.Marshal(map[string]any{
+			"description": Description,
+			"amount":      12.34,
+			"date":        now.Add(-time.Minute).Format(time.RFC3339),
+			"tags":        []string{"loadtest"},
+		})
+		if err != nil {
+			return "", err
+		}
+		var expenditure struct {
+			ID string `json:"id"`
+		}
+		err = do(ctx, client, http.MethodPost, base+"/expenditures", body, &expenditure)
+		return expenditure.ID, err
+	case List:
+		return "", do(ctx, client, http.MethodGet, fmt.Sprintf("%s/expenditures?limit=%d", base, pageSize), nil, nil)
+	default:
+		return "", do(ctx, client, http.MethodGet, base+"/reports/categories?month="+now.Format("2006-01"), nil, nil)
+	}
+}
+
+// do sends a request and decodes the JSON response into out when given; statuses other than
+// 2xx are errors
+func do(ctx context.Context, client *http.Client, method, url string, body []byte, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: %s: %s", method, url, resp.Status, strings.TrimSpace(string(message)))
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	// Read the whole body, so the connection is reused and the transfer is measured
+	_, err = io.Copy(io.Discard, resp.Body)
+	return err
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"go-expense-tracker/loadtest"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// runLoadTestCommand exercises a running instance, prints the latencies per endpoint and
+// returns a failing exit code when a p99 latency exceeds its budget or requests failed
+func runLoadTestCommand(args []string, logger *slog.Logger) int {
+	flags := flag.NewFlagSet("loadtest", flag.ContinueOnError)
+	url := flags.String("url", "http://localhost:8080", "Base URL of the running instance")
+	duration := flags.Duration("duration", 30*time.Second, "How long requests are sent")
+	concurrency := flags.Int("concurrency", 8, "Number of clients sending requests at once")
+	pageSize := flags.Int("page-size", 100, "Page size of the list requests")
+	budgetValue := flags.String("budget", os.Getenv("LOADTEST_BUDGET"), "p99 budget per endpoint, e.g. create=200ms,list=300ms,report=500ms")
+	keep := flags.Bool("keep", false, "Keep the created expenditures instead of deleting them afterwards")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	budget, err := loadtest.ParseBudget(*budgetValue, loadtest.DefaultBudget)
+	if err != nil {
+		logger.Error("Invalid -budget value", "error", err, "value", *budgetValue)
+		return 2
+	}
+	if *duration <= 0 || *concurrency <= 0 || *pageSize <= 0 {
+		logger.Error("Invalid load test options", "duration", *duration, "concurrency", *concurrency, "page_size", *pageSize)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	logger.Info("Running load test", "url", *url, "duration", *duration, "concurrency", *concurrency)
+	client := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{MaxIdleConnsPerHost: *concurrency},
+	}
+	summary, err := loadtest.Run(ctx, client, loadtest.Options{
+		BaseURL:     *url,
+		Duration:    *duration,
+		Concurrency: *concurrency,
+		PageSize:    *pageSize,
+		Keep:        *keep,
+	})
+	if summary != nil {
+		summary.Write(os.Stdout)
+	}
+	if err != nil {
+		logger.Error("Load test failed", "error", err)
+		return 1
+	}
+
+	code := 0
+	for _, result := range summary.Results {
+		if result.Errors > 0 {
+			logger.Error("Requests failed", "endpoint", result.Endpoint, "errors", result.Errors, "requests", result.Requests, "last_error", result.LastError)
+			code = 1
+		}
+	}
+	for _, violation := range summary.Check(budget) {
+		logger.Error("Performance budget exceeded", "endpoint", violation.Endpoint, "p99", violation.P99, "budget", violation.Budget)
+		code = 1
+	}
+
+	logger.Info("Load test finished", "duration", summary.Duration, "deleted", summary.Deleted, "passed", code == 0)
+	return code
+}
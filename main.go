@@ -1,19 +1,68 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	_ "embed"
+	"errors"
 	"flag"
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
+	"go-expense-tracker/activity"
+	"go-expense-tracker/anomalies"
+	"go-expense-tracker/app"
+	"go-expense-tracker/breaker"
+	"go-expense-tracker/chaos"
+	"go-expense-tracker/buildinfo"
+	"go-expense-tracker/classifier"
+	"go-expense-tracker/console"
+	"go-expense-tracker/devconsole"
 	"go-expense-tracker/domain"
+	"go-expense-tracker/eventsourcing"
+	"go-expense-tracker/features"
 	"go-expense-tracker/handlers"
+	"go-expense-tracker/i18n"
+	"go-expense-tracker/integrations/automation"
+	"go-expense-tracker/integrations/banking"
+	"go-expense-tracker/integrations/email"
+	"go-expense-tracker/integrations/mqtt"
+	"go-expense-tracker/integrations/slack"
+	"go-expense-tracker/integrations/streaming"
+	"go-expense-tracker/integrations/telegram"
+	"go-expense-tracker/integrations/webhook"
+	"go-expense-tracker/jobs"
+	"go-expense-tracker/leader"
+	"go-expense-tracker/ledger"
+	"go-expense-tracker/maintenance"
+	"go-expense-tracker/merchants"
+	"go-expense-tracker/migration"
+	"go-expense-tracker/operations"
+	"go-expense-tracker/outbox"
+	"go-expense-tracker/readiness"
+	"go-expense-tracker/recording"
+	"go-expense-tracker/recurring"
+	"go-expense-tracker/reports"
+	"go-expense-tracker/retention"
+	"go-expense-tracker/retry"
 	"go-expense-tracker/services"
+	"go-expense-tracker/signedurl"
+	"go-expense-tracker/storage"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// requestsFile holds the example requests of the API, offered by the API console in development mode
+//
+//go:embed requests.http
+var requestsFile []byte
+
 // LoggingMiddleware adds request logging to all HTTP requests
 func LoggingMiddleware(logger *slog.Logger, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -49,6 +98,11 @@ func NewResponseWriter(w http.ResponseWriter) *ResponseWriter {
 	return &ResponseWriter{w, http.StatusOK}
 }
 
+// Unwrap exposes the wrapped ResponseWriter, so handlers can flush streamed responses
+func (rw *ResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // WriteHeader captures the status code and passes it to the wrapped ResponseWriter
 func (rw *ResponseWriter) WriteHeader(code int) {
 	rw.statusCode = code
@@ -64,75 +118,526 @@ func main() {
 	}
 
 	// Parse command line flags
-	useDB := flag.Bool("db", false, "Use PostgreSQL database instead of in-memory storage")
+	storageName := flag.String("storage", "memory", "Storage driver: "+strings.Join(storage.Drivers(), ", "))
+	storageDSN := flag.String("dsn", "", "Connection string of the storage, overrides STORAGE_DSN")
+	dataDir := flag.String("data-dir", "", "Data directory of embedded storage drivers such as bolt, same as -dsn")
+	useDB := flag.Bool("db", false, "Use PostgreSQL database, same as -storage postgres")
 	flag.Parse()
 
 	// Configure structured logger
 	logHandler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelDebug,
 	})
-	logger := slog.New(logHandler)
+	// The API console of development mode shows the records written while it sends a request;
+	// records pass through untouched otherwise
+	logCapture := devconsole.NewCapture(logHandler)
+	// Every record names the build, so logs attached to a bug report identify it
+	build := buildinfo.Get()
+	logger := slog.New(logCapture).With("version", build.Version)
 	slog.SetDefault(logger)
 
-	logger.Info("Starting expense tracker application")
+	// `loadtest` exercises a running instance instead of starting one
+	if flag.Arg(0) == "loadtest" {
+		os.Exit(runLoadTestCommand(flag.Args()[1:], logger))
+	}
+	// `replay` re-sends recorded requests to a running instance
+	if flag.Arg(0) == "replay" {
+		os.Exit(runReplayCommand(flag.Args()[1:], logger))
+	}
 
-	// Initialize the appropriate service
-	var service domain.ExpenditureRepository
-	var err error
+	logger.Info("Starting expense tracker application", "commit", build.Commit, "build_date", build.Date, "go_version", build.GoVersion)
 
+	// Open the storage of the chosen driver; -db is kept for existing setups
+	storageDriver := *storageName
 	if *useDB {
-		// Get database parameters from environment variables
-		dbHost := os.Getenv("DB_HOST")
-		if dbHost == "" {
-			dbHost = "localhost" // Default value
+		storageDriver = "postgres"
+	}
+	dsn := *storageDSN
+	if dsn == "" {
+		dsn = *dataDir
+	}
+	if dsn == "" {
+		dsn = os.Getenv("STORAGE_DSN")
+	}
+
+	// The server can answer before the storage is up, e.g. when started alongside the database:
+	// requests get 503 and the readiness probe reports "starting" until the API is set up
+	serveEarly := false
+	if earlyStr := os.Getenv("STORAGE_LAZY_CONNECT"); earlyStr != "" {
+		var err error
+		serveEarly, err = strconv.ParseBool(earlyStr)
+		if err != nil {
+			logger.Error("Invalid STORAGE_LAZY_CONNECT value", "error", err, "value", earlyStr)
+			os.Exit(1)
+		}
+	}
+	gate := readiness.NewGate(http.DefaultServeMux)
+	http.Handle(readiness.Path, handlers.Methods{http.MethodGet: handlers.NewReadinessHandler(gate.Ready, logger).GetReadiness})
+	http.Handle("/version", LoggingMiddleware(logger, handlers.Methods{http.MethodGet: handlers.NewVersionHandler(build, logger).GetVersion}))
+
+	// The category palette and icons are embedded, so clients render categories alike
+	http.Handle("/meta/", LoggingMiddleware(logger, handlers.MetaRouter(handlers.NewMetaHandler(logger))))
+
+	serverAddr := fmt.Sprintf(":%d", port)
+	serveEarly = serveEarly && flag.Arg(0) == ""
+	if serveEarly {
+		logger.Info("Starting HTTP server before the storage", "address", serverAddr)
+		go func() {
+			err := http.ListenAndServe(serverAddr, gate)
+			logger.Error("Server failed to start", "error", err)
+			os.Exit(1)
+		}()
+	}
+
+	// A database still starting is connected to again, with a growing delay, for up to
+	// STORAGE_CONNECT_TIMEOUT, and for as long as it takes when the server is already answering
+	connectTimeout := time.Duration(0) // Default value
+	if timeoutStr := os.Getenv("STORAGE_CONNECT_TIMEOUT"); timeoutStr != "" {
+		var err error
+		connectTimeout, err = time.ParseDuration(timeoutStr)
+		if err != nil || connectTimeout < 0 {
+			logger.Error("Invalid STORAGE_CONNECT_TIMEOUT value", "error", err, "value", timeoutStr)
+			os.Exit(1)
+		}
+	}
+	if serveEarly {
+		connectTimeout = -1
+	}
+	connectPolicy := retry.Policy{Delay: time.Second, MaxDelay: 30 * time.Second}
+	knownDriver := func(err error) bool { return !errors.Is(err, storage.ErrUnknownDriver) }
+
+	var service domain.ExpenditureRepository
+	err := retry.For(connectTimeout, connectPolicy, knownDriver, logger, "open storage", func() (err error) {
+		service, err = storage.Open(storageDriver, dsn, logger)
+		return err
+	})
+	if err != nil {
+		logger.Error("Failed to open storage", "error", err, "driver", storageDriver)
+		os.Exit(1)
+	}
+	if closer, ok := service.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	// `migrate-storage` copies the data to another storage instead of starting the server
+	if flag.Arg(0) == "migrate-storage" {
+		os.Exit(runMigrateCommand(service, flag.Args()[1:], logger))
+	}
+
+	// Category lookup is only available when the storage supports it
+	var categories domain.CategoryRepository
+	if repo, ok := service.(domain.CategoryRepository); ok {
+		categories = services.NewCategoryCache(repo, logger)
+	}
+	imports, _ := service.(domain.ImportRepository)
+	connections, _ := service.(domain.BankConnectionRepository)
+	goals, _ := service.(domain.GoalRepository)
+	budgets, _ := service.(domain.BudgetRepository)
+	recurringStore, _ := service.(domain.RecurringRepository)
+	installments, _ := service.(domain.InstallmentRepository)
+	accounts, _ := service.(domain.AccountRepository)
+	envelopes, _ := service.(domain.EnvelopeRepository)
+	expenseReports, _ := service.(domain.ExpenseReportRepository)
+	merchantDirectory, _ := service.(domain.MerchantRepository)
+	rules, _ := service.(domain.CategorizationRuleRepository)
+	archives, _ := service.(domain.ArchiveRepository)
+	summaries, _ := service.(domain.SpendingSummaryRepository)
+	reportSnapshots, _ := service.(domain.ReportSnapshotRepository)
+	retentionStore, _ := service.(domain.RetentionRepository)
+	households, _ := service.(domain.HouseholdRepository)
+	outboxStore, _ := service.(domain.OutboxRepository)
+	indexStats, _ := service.(domain.IndexStatsRepository)
+	drafts, _ := service.(domain.DraftRepository)
+	views, _ := service.(domain.ViewRepository)
+	pins, _ := service.(domain.PinRepository)
+	storageHealth, _ := service.(domain.HealthChecker)
+	transientErrors, _ := service.(domain.TransientErrorChecker)
+	leaderLocker, _ := service.(domain.LeaderLocker)
+	merchantResolver := merchants.NewResolver(merchantDirectory, logger)
+
+	// Schedulers, the outbox relay and other background subsystems run on one replica only: the
+	// one holding the leader lock of the storage. Storages without one run them on every replica
+	leaderEnabled := true // Default value
+	if enabledStr := os.Getenv("LEADER_ELECTION"); enabledStr != "" {
+		leaderEnabled, err = strconv.ParseBool(enabledStr)
+		if err != nil {
+			logger.Error("Invalid LEADER_ELECTION value", "error", err, "value", enabledStr)
+			os.Exit(1)
+		}
+	}
+	if !leaderEnabled {
+		leaderLocker = nil
+	}
+	leaderLockName := "expense-tracker" // Default value
+	if nameStr := os.Getenv("LEADER_LOCK_NAME"); nameStr != "" {
+		leaderLockName = nameStr
+	}
+	leaderInterval := 10 * time.Second // Default value
+	if intervalStr := os.Getenv("LEADER_CHECK_INTERVAL"); intervalStr != "" {
+		leaderInterval, err = time.ParseDuration(intervalStr)
+		if err != nil || leaderInterval <= 0 {
+			logger.Error("Invalid LEADER_CHECK_INTERVAL value", "error", err, "value", intervalStr)
+			os.Exit(1)
 		}
+	}
+	elector := leader.NewElector(leaderLocker, leaderLockName, leaderInterval, logger)
+
+	// Integrations are listed in the admin console as they are configured, without their secrets
+	var integrations []console.Integration
 
-		dbPortStr := os.Getenv("DB_PORT")
-		dbPort := 5432 // Default value
-		if dbPortStr != "" {
-			var err error
-			dbPort, err = strconv.Atoi(dbPortStr)
+	// Features are switched on and off per deployment, e.g. FEATURES=bank-sync=false;
+	// EVENT_SOURCING predates the flags and still works
+	var featureConfig []string
+	if sourcingStr := os.Getenv("EVENT_SOURCING"); sourcingStr != "" {
+		featureConfig = append(featureConfig, features.EventSourcing+"="+sourcingStr)
+	}
+	if featuresStr := os.Getenv("FEATURES"); featuresStr != "" {
+		featureConfig = append(featureConfig, featuresStr)
+	}
+	featureFlags, err := features.Parse(strings.Join(featureConfig, ","))
+	if err != nil {
+		logger.Error("Invalid FEATURES value", "error", err, "value", strings.Join(featureConfig, ","))
+		os.Exit(1)
+	}
+	for _, state := range featureFlags.States() {
+		logger.Info("Feature flag", "name", state.Name, "enabled", state.Enabled)
+	}
+
+	// In event-sourced mode every change to expenditures is stored as an event, and the stored
+	// expenditures are only the projection of the events
+	var eventStore domain.ExpenditureEventStore
+	if featureFlags.Enabled(features.EventSourcing) {
+		store, ok := service.(domain.ExpenditureEventStore)
+		if !ok {
+			logger.Error("Event sourcing is not supported by the storage")
+			os.Exit(1)
+		}
+
+		eventSourced := eventsourcing.NewExpenditureRepository(service, store, logger)
+		if err := eventSourced.Project(); err != nil {
+			logger.Error("Failed to project expenditure events", "error", err)
+			os.Exit(1)
+		}
+		service = eventSourced
+		if categories != nil {
+			categories = eventsourcing.NewCategoryRepository(categories, eventSourced)
+		}
+		if archives != nil {
+			archives = eventsourcing.NewArchiveRepository(archives, eventSourced)
+		}
+		eventStore = store
+		logger.Info("Using event-sourced expenditures")
+	}
+
+	// During a cutover to another storage every change is written to it as well, until it takes
+	// over; migrate-storage copies the data beforehand and verifies the storages match afterwards
+	if dualDriver := os.Getenv("DUAL_WRITE_STORAGE"); dualDriver != "" {
+		secondary, err := storage.Open(dualDriver, os.Getenv("DUAL_WRITE_DSN"), logger)
+		if err != nil {
+			logger.Error("Failed to open dual-write storage", "error", err, "driver", dualDriver)
+			os.Exit(1)
+		}
+		if closer, ok := secondary.(io.Closer); ok {
+			defer closer.Close()
+		}
+
+		mapping := migration.CategoryMapping{}
+		secondaryCategories, _ := secondary.(domain.CategoryRepository)
+		if categories != nil && secondaryCategories != nil {
+			mapping, err = migration.MapCategories(categories, secondaryCategories)
 			if err != nil {
-				logger.Error("Invalid DB_PORT value", "error", err, "value", dbPortStr)
+				logger.Error("Failed to match categories of the dual-write storage", "error", err)
 				os.Exit(1)
 			}
+			categories = migration.NewDualWriteCategoryRepository(categories, secondaryCategories, mapping, logger)
 		}
+		service = migration.NewDualWriteRepository(service, secondary, mapping, logger)
+		logger.Info("Dual-writing changes to second storage", "driver", dualDriver)
+	}
 
-		dbUser := os.Getenv("DB_USER")
-		if dbUser == "" {
-			dbUser = "postgres" // Default value
+	// Calls failing with transient errors, such as a connection reset or a serialization failure,
+	// are made again after a growing delay before the breaker counts them as failures
+	if transientErrors != nil {
+		policy := retry.DefaultPolicy
+		if attemptsStr := os.Getenv("STORAGE_RETRY_ATTEMPTS"); attemptsStr != "" {
+			policy.Attempts, err = strconv.Atoi(attemptsStr)
+			if err != nil || policy.Attempts < 1 {
+				logger.Error("Invalid STORAGE_RETRY_ATTEMPTS value", "error", err, "value", attemptsStr)
+				os.Exit(1)
+			}
+		}
+		if delayStr := os.Getenv("STORAGE_RETRY_DELAY"); delayStr != "" {
+			policy.Delay, err = time.ParseDuration(delayStr)
+			if err != nil || policy.Delay < 0 {
+				logger.Error("Invalid STORAGE_RETRY_DELAY value", "error", err, "value", delayStr)
+				os.Exit(1)
+			}
 		}
+		if maxDelayStr := os.Getenv("STORAGE_RETRY_MAX_DELAY"); maxDelayStr != "" {
+			policy.MaxDelay, err = time.ParseDuration(maxDelayStr)
+			if err != nil || policy.MaxDelay < 0 {
+				logger.Error("Invalid STORAGE_RETRY_MAX_DELAY value", "error", err, "value", maxDelayStr)
+				os.Exit(1)
+			}
+		}
+		if policy.Attempts > 1 {
+			service = retry.NewRepository(service, transientErrors, policy, logger)
+			logger.Info("Retrying transient storage errors", "attempts", policy.Attempts, "delay", policy.Delay.String(), "max_delay", policy.MaxDelay.String())
+		}
+	}
 
-		dbPassword := os.Getenv("DB_PASSWORD")
-		if dbPassword == "" {
-			dbPassword = "postgres" // Default value
+	// When the database becomes unreachable a circuit breaker turns writes away with 503 and
+	// serves reads from the last known expenditures, until a health probe finds it back
+	var storageBreaker *breaker.Breaker
+	if storageHealth != nil {
+		threshold := 5 // Default value
+		if thresholdStr := os.Getenv("STORAGE_BREAKER_THRESHOLD"); thresholdStr != "" {
+			threshold, err = strconv.Atoi(thresholdStr)
+			if err != nil || threshold < 0 {
+				logger.Error("Invalid STORAGE_BREAKER_THRESHOLD value", "error", err, "value", thresholdStr)
+				os.Exit(1)
+			}
+		}
+		healthInterval := 5 * time.Second // Default value
+		if intervalStr := os.Getenv("STORAGE_HEALTH_INTERVAL"); intervalStr != "" {
+			healthInterval, err = time.ParseDuration(intervalStr)
+			if err != nil || healthInterval <= 0 {
+				logger.Error("Invalid STORAGE_HEALTH_INTERVAL value", "error", err, "value", intervalStr)
+				os.Exit(1)
+			}
+		}
+		if threshold > 0 {
+			storageBreaker = breaker.New(storageHealth, threshold, healthInterval, logger)
+			service = breaker.NewRepository(service, storageBreaker)
+			go storageBreaker.Run(context.Background())
 		}
+	}
 
-		dbName := os.Getenv("DB_NAME")
-		if dbName == "" {
-			dbName = "expense_tracker" // Default value
+	// Expenditures created without a category go to the uncategorized category
+	var uncategorized uuid.UUID
+	if categories != nil {
+		name := os.Getenv("UNCATEGORIZED_CATEGORY")
+		if name == "" {
+			name = domain.UncategorizedCategoryName // Default value
 		}
+		category, err := domain.EnsureCategory(categories, name, domain.DefaultCategories[domain.UncategorizedCategoryName])
+		if err != nil {
+			logger.Error("Failed to set up the uncategorized category", "error", err, "name", name)
+			os.Exit(1)
+		}
+		uncategorized = category.ID
+	}
 
-		logger.Info("Using PostgreSQL database for storage",
-			"host", dbHost,
-			"port", dbPort,
-			"user", dbUser,
-			"database", dbName)
+	// `seed` fills the storage with demo data instead of starting the server
+	if flag.Arg(0) == "seed" {
+		os.Exit(runSeedCommand(service, categories, flag.Args()[1:], logger))
+	}
 
-		dbService, err := services.NewDBService(dbHost, dbPort, dbUser, dbPassword, dbName, logger)
+	// Budgets and monthly reports follow calendar months unless configured otherwise
+	calendar, err := domain.ParseFiscalCalendar(os.Getenv("FISCAL_MONTH"))
+	if err != nil {
+		logger.Error("Invalid FISCAL_MONTH value", "error", err, "value", os.Getenv("FISCAL_MONTH"))
+		os.Exit(1)
+	}
+	logger.Info("Using fiscal calendar", "fiscal_month", calendar)
+
+	// Record changes to expenditures so they can be undone for a while
+	undoWindow := 10 * time.Minute // Default value
+	if windowStr := os.Getenv("OPERATION_UNDO_WINDOW"); windowStr != "" {
+		undoWindow, err = time.ParseDuration(windowStr)
+		if err != nil || undoWindow <= 0 {
+			logger.Error("Invalid OPERATION_UNDO_WINDOW value", "error", err, "value", windowStr)
+			os.Exit(1)
+		}
+	}
+	// Erasures go around the undo log, so they cannot be undone
+	unrecorded := service
+	recorder := operations.NewRecorder(service, undoWindow, logger)
+	service = recorder
+
+	// Keep a feed of recent changes for the household
+	activitySize := 1000 // Default value
+	if sizeStr := os.Getenv("ACTIVITY_FEED_SIZE"); sizeStr != "" {
+		activitySize, err = strconv.Atoi(sizeStr)
+		if err != nil || activitySize < 1 {
+			logger.Error("Invalid ACTIVITY_FEED_SIZE value", "error", err, "value", sizeStr)
+			os.Exit(1)
+		}
+	}
+	feed := activity.NewFeed(activitySize, logger)
+	service = activity.NewExpenditureRepository(service, feed)
+	if categories != nil {
+		categories = activity.NewCategoryRepository(categories, feed)
+	}
+	if budgets != nil {
+		budgets = activity.NewBudgetRepository(budgets, feed)
+	}
+
+	// Publish changes to an MQTT broker: budgets right away, expenditures through the outbox along
+	// with its webhook
+	var mqttPublisher *mqtt.Publisher
+	if broker := os.Getenv("MQTT_BROKER_URL"); broker != "" {
+		mqttConfig := mqtt.Config{
+			Broker:   broker,
+			Username: os.Getenv("MQTT_USERNAME"),
+			Password: os.Getenv("MQTT_PASSWORD"),
+			ClientID: os.Getenv("MQTT_CLIENT_ID"),
+			QoS:      1,                         // Default value
+			Topic:    "expense-tracker/{event}", // Default value
+		}
+		if qosStr := os.Getenv("MQTT_QOS"); qosStr != "" {
+			qos, err := strconv.ParseUint(qosStr, 10, 8)
+			if err != nil {
+				logger.Error("Invalid MQTT_QOS value", "error", err, "value", qosStr)
+				os.Exit(1)
+			}
+			mqttConfig.QoS = byte(qos)
+		}
+		if retainStr := os.Getenv("MQTT_RETAIN"); retainStr != "" {
+			mqttConfig.Retain, err = strconv.ParseBool(retainStr)
+			if err != nil {
+				logger.Error("Invalid MQTT_RETAIN value", "error", err, "value", retainStr)
+				os.Exit(1)
+			}
+		}
+		if topic := os.Getenv("MQTT_TOPIC"); topic != "" {
+			mqttConfig.Topic = topic
+		}
+
+		mqttClient, err := mqtt.NewClient(mqttConfig, logger)
+		if err != nil {
+			logger.Error("Invalid MQTT configuration", "error", err)
+			os.Exit(1)
+		}
+		go mqttClient.Run(context.Background())
+		integrations = append(integrations, console.Integration{Kind: "mqtt", Target: console.Target(broker), Detail: "topics " + mqttConfig.Topic})
+		mqttPublisher = mqtt.NewPublisher(mqttClient, logger)
+		if budgets != nil {
+			budgets = mqtt.NewBudgetRepository(budgets, mqttPublisher)
+		}
+	}
+
+	// Categories can be suggested by a classifier trained on the categorized expenditures, which
+	// learns from every change in between trainings
+	var suggester *classifier.Classifier
+	if featureFlags.Enabled(features.CategorySuggestions) {
+		trainInterval := time.Hour // Default value
+		if intervalStr := os.Getenv("CLASSIFIER_TRAIN_INTERVAL"); intervalStr != "" {
+			trainInterval, err = time.ParseDuration(intervalStr)
+			if err != nil || trainInterval <= 0 {
+				logger.Error("Invalid CLASSIFIER_TRAIN_INTERVAL value", "error", err, "value", intervalStr)
+				os.Exit(1)
+			}
+		}
+		suggester = classifier.NewClassifier(service, uncategorized, trainInterval, logger)
+		service = classifier.NewLearningRepository(service, suggester)
+		go suggester.Run(context.Background())
+	}
+
+	// Load the per-workspace Slack configuration and wrap the service for alert notifications
+	var slackWorkspaces []slack.Workspace
+	var slackNotifier *slack.NotifyingRepository
+	if path := os.Getenv("SLACK_WORKSPACES_FILE"); path != "" {
+		slackWorkspaces, err = slack.LoadWorkspaces(path)
+		if err != nil {
+			logger.Error("Failed to load slack workspaces", "error", err, "path", path)
+			os.Exit(1)
+		}
+		logger.Info("Loaded slack workspaces", "count", len(slackWorkspaces))
+		for _, workspace := range slackWorkspaces {
+			integrations = append(integrations, console.Integration{Kind: "slack", Target: workspace.TeamID, Detail: "notifies " + console.Target(workspace.WebhookURL)})
+		}
+		slackNotifier = slack.NewNotifyingRepository(service, slackWorkspaces, calendar, logger)
+		service = slackNotifier
+	}
+
+	// Unusual spending is listed on GET /anomalies and, as new expenditures cause it, sent to the
+	// anomaly webhook and the Slack workspaces alerting about anomalies
+	var anomalyOptions anomalies.Options
+	if thresholdStr := os.Getenv("ANOMALY_THRESHOLD"); thresholdStr != "" {
+		anomalyOptions.Threshold, err = strconv.ParseFloat(thresholdStr, 64)
+		if err != nil || anomalyOptions.Threshold <= 0 {
+			logger.Error("Invalid ANOMALY_THRESHOLD value", "error", err, "value", thresholdStr)
+			os.Exit(1)
+		}
+	}
+	detector := anomalies.NewDetector(anomalyOptions)
+	var anomalyNotifiers []anomalies.Notifier
+	if url := os.Getenv("ANOMALY_WEBHOOK_URL"); url != "" {
+		anomalyNotifiers = append(anomalyNotifiers, webhook.NewNotifier(url, logger))
+		integrations = append(integrations, console.Integration{Kind: "anomaly-webhook", Target: console.Target(url)})
+	}
+	if slackNotifier != nil {
+		anomalyNotifiers = append(anomalyNotifiers, slackNotifier)
+	}
+	if len(anomalyNotifiers) > 0 {
+		service = anomalies.NewAlertingRepository(service, detector, logger, anomalyNotifiers...)
+	}
+
+	// Expenditures above a category's transaction limit can be reported to a webhook
+	var limitNotifier app.CategoryLimitNotifier
+	if url := os.Getenv("CATEGORY_LIMIT_WEBHOOK_URL"); url != "" {
+		limitNotifier = webhook.NewNotifier(url, logger)
+		integrations = append(integrations, console.Integration{Kind: "category-limit-webhook", Target: console.Target(url)})
+	}
+
+	// Limit how much a single listing or report may read; larger ones run as background jobs
+	var queryLimits handlers.QueryLimits
+	if daysStr := os.Getenv("EXPORT_MAX_SPAN_DAYS"); daysStr != "" {
+		days, err := strconv.Atoi(daysStr)
+		if err != nil || days < 0 {
+			logger.Error("Invalid EXPORT_MAX_SPAN_DAYS value", "error", err, "value", daysStr)
+			os.Exit(1)
+		}
+		queryLimits.MaxExportSpan = time.Duration(days) * 24 * time.Hour
+	}
+	queryLimits.AsyncRows = 100000 // Default value
+	if rowsStr := os.Getenv("REPORT_ASYNC_ROWS"); rowsStr != "" {
+		queryLimits.AsyncRows, err = strconv.Atoi(rowsStr)
+		if err != nil || queryLimits.AsyncRows < 0 {
+			logger.Error("Invalid REPORT_ASYNC_ROWS value", "error", err, "value", rowsStr)
+			os.Exit(1)
+		}
+	}
+	jobRunner := jobs.NewRunner(time.Hour, logger)
+	queryGuard := handlers.NewQueryGuard(queryLimits, jobRunner, logger)
+
+	// Expenditures dated in the future are rejected unless allowed, up to a number of days ahead
+	futureDays := 0 // Default value, no bound
+	if daysStr := os.Getenv("FUTURE_DATE_WINDOW_DAYS"); daysStr != "" {
+		futureDays, err = strconv.Atoi(daysStr)
 		if err != nil {
-			logger.Error("Failed to initialize database service", "error", err)
+			logger.Error("Invalid FUTURE_DATE_WINDOW_DAYS value", "error", err, "value", daysStr)
 			os.Exit(1)
 		}
-		defer dbService.Close()
+	}
+	futureDates, err := domain.ParseFutureDatePolicy(os.Getenv("FUTURE_DATE_POLICY"), futureDays)
+	if err != nil {
+		logger.Error("Invalid FUTURE_DATE_POLICY value", "error", err, "value", os.Getenv("FUTURE_DATE_POLICY"))
+		os.Exit(1)
+	}
+	logger.Info("Using future date policy", "mode", futureDates.Mode, "window", futureDates.Window)
 
-		service = dbService
-	} else {
-		logger.Info("Using in-memory storage")
-		service = services.NewMemoryService(logger)
+	// Changes to expenditures go through the application service shared by all transports
+	expenditureService := app.NewExpenditureService(service, categories, uncategorized, merchantResolver, limitNotifier, futureDates, logger)
+
+	// Plain-text accounting exports book categories and accounts on a configurable chart of accounts
+	accountMapping, err := ledger.ParseAccountMapping(os.Getenv("LEDGER_ACCOUNTS"))
+	if err != nil {
+		logger.Error("Invalid LEDGER_ACCOUNTS value", "error", err, "value", os.Getenv("LEDGER_ACCOUNTS"))
+		os.Exit(1)
+	}
+	journals := handlers.JournalExport{
+		Chart: ledger.ChartOfAccounts{
+			Funding:  os.Getenv("LEDGER_FUNDING_ACCOUNT"),
+			Currency: strings.ToUpper(os.Getenv("EXPORT_CURRENCY")),
+			Mapping:  accountMapping,
+		},
+		Accounts:  accounts,
+		Merchants: merchantDirectory,
 	}
 
-	handler := handlers.NewExpenditureHandler(service, logger)
+	handler := handlers.NewExpenditureHandler(service, expenditureService, categories, rules, uncategorized, drafts, pins, queryGuard, suggester, journals, logger)
 
 	// Set up the routes
 	router := handlers.ExpenditureRouter(handler)
@@ -143,10 +648,653 @@ func main() {
 	http.Handle("/expenditures", loggedRouter)
 	http.Handle("/expenditures/", loggedRouter)
 
-	// Start the server
-	serverAddr := fmt.Sprintf(":%d", port)
-	logger.Info("Starting HTTP server", "address", serverAddr)
-	err = http.ListenAndServe(serverAddr, nil)
+	importRouter := LoggingMiddleware(logger, handlers.ImportRouter(handlers.NewImportHandler(imports, expenditureService, service, merchantResolver, rules, suggester, logger)))
+	http.Handle("/imports", importRouter)
+	http.Handle("/imports/", importRouter)
+
+	// The home screen's dashboard is kept for a while and dropped with every write through the API
+	dashboardTTL := 30 * time.Second // Default value
+	if ttlStr := os.Getenv("DASHBOARD_CACHE_TTL"); ttlStr != "" {
+		dashboardTTL, err = time.ParseDuration(ttlStr)
+		if err != nil || dashboardTTL < 0 {
+			logger.Error("Invalid DASHBOARD_CACHE_TTL value", "error", err, "value", ttlStr)
+			os.Exit(1)
+		}
+	}
+	dashboard := reports.NewDashboardCache(service, categories, budgets, summaries, calendar, dashboardTTL, logger)
+	http.Handle("/dashboard", LoggingMiddleware(logger, handlers.Methods{http.MethodGet: handlers.NewDashboardHandler(dashboard, logger).GetDashboard}))
+	http.Handle("/anomalies", LoggingMiddleware(logger, handlers.Methods{http.MethodGet: handlers.NewAnomalyHandler(service, detector, logger).GetAnomalies}))
+	http.Handle("/calendar", LoggingMiddleware(logger, handlers.Methods{http.MethodGet: handlers.NewCashFlowHandler(service, summaries, budgets, recurringStore, calendar, logger).GetCashFlowCalendar}))
+
+	http.Handle("/reports/", LoggingMiddleware(logger, handlers.ReportRouter(handlers.NewReportHandler(service, categories, merchantDirectory, households, summaries, reportSnapshots, queryGuard, calendar, logger))))
+
+	if categories != nil {
+		categoryRouter := LoggingMiddleware(logger, handlers.CategoryRouter(handlers.NewCategoryHandler(categories, service, summaries, calendar, logger)))
+		http.Handle("/categories", categoryRouter)
+		http.Handle("/categories/", categoryRouter)
+	}
+
+	goalRouter := LoggingMiddleware(logger, handlers.GoalRouter(handlers.NewGoalHandler(goals, service, categories, logger)))
+	http.Handle("/goals", goalRouter)
+	http.Handle("/goals/", goalRouter)
+
+	budgetRouter := LoggingMiddleware(logger, handlers.BudgetRouter(handlers.NewBudgetHandler(budgets, envelopes, service, categories, summaries, calendar, logger)))
+	http.Handle("/budgets", budgetRouter)
+	http.Handle("/budgets/", budgetRouter)
+
+	// Histories move in from and out to YNAB and GnuCash; their expenditures export through /expenditures/export
+	interchangeService := app.NewInterchangeService(expenditureService, service, categories, accounts, budgets, logger)
+	http.Handle("/interchange/", LoggingMiddleware(logger, handlers.InterchangeRouter(handlers.NewInterchangeHandler(interchangeService, budgets, service, categories, summaries, calendar, logger))))
+
+	if installments != nil {
+		installmentService := app.NewInstallmentService(installments, expenditureService, logger)
+		installmentRouter := LoggingMiddleware(logger, handlers.InstallmentRouter(handlers.NewInstallmentHandler(installmentService, logger)))
+		http.Handle("/installments", installmentRouter)
+		http.Handle("/installments/", installmentRouter)
+	}
+
+	if accounts != nil {
+		accountService := app.NewAccountService(accounts, service, logger)
+		accountRouter := LoggingMiddleware(logger, handlers.AccountRouter(handlers.NewAccountHandler(accountService, logger)))
+		http.Handle("/accounts", accountRouter)
+		http.Handle("/accounts/", accountRouter)
+	}
+
+	var householdService *app.HouseholdService
+	var privacyService *app.PrivacyService
+	if households != nil {
+		// Invitations are emailed when an SMTP server is configured, else returned to the inviter
+		var invitationSender app.InvitationSender
+		if host := os.Getenv("SMTP_HOST"); host != "" {
+			port := os.Getenv("SMTP_PORT")
+			if port == "" {
+				port = "587"
+			}
+			invitationSender = email.NewInvitationMailer(host, port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), os.Getenv("SMTP_FROM"), logger)
+		}
+		// Allowances of members such as children are kept in envelopes
+		var allowances *app.AllowanceService
+		if envelopes != nil {
+			allowances = app.NewAllowanceService(households, envelopes, service, categories, logger)
+		}
+		householdService = app.NewHouseholdService(households, invitationSender, logger)
+		householdHandler := handlers.NewHouseholdHandler(householdService, allowances, logger)
+		householdRouter := LoggingMiddleware(logger, handlers.HouseholdRouter(householdHandler))
+		http.Handle("/households", householdRouter)
+		http.Handle("/households/", householdRouter)
+		// Not logged by path, which holds the invitation's token
+		http.Handle("/invitations/", handlers.InvitationRouter(householdHandler))
+
+		// Members export or erase their own data, e.g. for GDPR requests
+		privacyService = app.NewPrivacyService(households, unrecorded, drafts, eventStore, archives, outboxStore, []app.MemberScrubber{recorder, feed}, logger)
+		userRouter := LoggingMiddleware(logger, handlers.UserRouter(handlers.NewUserHandler(privacyService, logger)))
+		http.Handle("/users/me", userRouter)
+		http.Handle("/users/me/", userRouter)
+	}
+
+	// Generate the expenditures of recurring payments as they come due
+	if recurringStore != nil {
+		recurringInterval := time.Hour // Default value
+		if intervalStr := os.Getenv("RECURRING_INTERVAL"); intervalStr != "" {
+			recurringInterval, err = time.ParseDuration(intervalStr)
+			if err != nil || recurringInterval <= 0 {
+				logger.Error("Invalid RECURRING_INTERVAL value", "error", err, "value", intervalStr)
+				os.Exit(1)
+			}
+		}
+		generator := recurring.NewGenerator(recurringStore, expenditureService, recurringInterval, logger)
+		elector.Add("recurring", generator)
+
+		recurringRouter := LoggingMiddleware(logger, handlers.RecurringRouter(handlers.NewRecurringHandler(recurringStore, generator, categories, logger)))
+		http.Handle("/recurring", recurringRouter)
+		http.Handle("/recurring/", recurringRouter)
+
+		subscriptionRouter := LoggingMiddleware(logger, handlers.SubscriptionRouter(handlers.NewSubscriptionHandler(service, recurringStore, logger)))
+		http.Handle("/subscriptions/", subscriptionRouter)
+	}
+
+	if envelopes != nil {
+		envelopeRouter := LoggingMiddleware(logger, handlers.EnvelopeRouter(handlers.NewEnvelopeHandler(envelopes, service, categories, summaries, logger)))
+		http.Handle("/envelopes", envelopeRouter)
+		http.Handle("/envelopes/", envelopeRouter)
+	}
+
+	if merchantDirectory != nil {
+		merchantRouter := LoggingMiddleware(logger, handlers.MerchantRouter(handlers.NewMerchantHandler(merchantDirectory, service, categories, logger)))
+		http.Handle("/merchants", merchantRouter)
+		http.Handle("/merchants/", merchantRouter)
+	}
+
+	if rules != nil {
+		ruleRouter := LoggingMiddleware(logger, handlers.RuleRouter(handlers.NewRuleHandler(rules, categories, accounts, merchantDirectory, logger)))
+		http.Handle("/rules", ruleRouter)
+		http.Handle("/rules/", ruleRouter)
+	}
+
+	jobRouter := LoggingMiddleware(logger, handlers.JobRouter(handlers.NewJobHandler(jobRunner, logger)))
+	http.Handle("/jobs", jobRouter)
+	http.Handle("/jobs/", jobRouter)
+
+	// Download links of exports and shared reports are signed; without a configured key, links
+	// issued before a restart stop working
+	linkKey := []byte(os.Getenv("LINK_SIGNING_KEY"))
+	if len(linkKey) == 0 {
+		linkKey = make([]byte, 32)
+		rand.Read(linkKey)
+	}
+	links := signedurl.New(linkKey)
+	exportLinkTTL := 15 * time.Minute // Default value
+	if ttlStr := os.Getenv("EXPORT_LINK_TTL"); ttlStr != "" {
+		exportLinkTTL, err = time.ParseDuration(ttlStr)
+		if err != nil || exportLinkTTL <= 0 {
+			logger.Error("Invalid EXPORT_LINK_TTL value", "error", err, "value", ttlStr)
+			os.Exit(1)
+		}
+	}
+	exportRouter := LoggingMiddleware(logger, handlers.ExportRouter(handlers.NewExportHandler(service, jobRunner, links, exportLinkTTL, logger)))
+	http.Handle("/exports", exportRouter)
+	http.Handle("/exports/", exportRouter)
+
+	if views != nil {
+		viewRouter := LoggingMiddleware(logger, handlers.ViewRouter(handlers.NewViewHandler(views, pins, service, logger)))
+		http.Handle("/views", viewRouter)
+		http.Handle("/views/", viewRouter)
+	}
+
+	http.Handle("/activity", LoggingMiddleware(logger, handlers.ActivityRouter(handlers.NewActivityHandler(feed, logger))))
+	if eventStore != nil {
+		eventRouter := LoggingMiddleware(logger, handlers.EventRouter(handlers.NewEventHandler(eventStore, service, logger)))
+		http.Handle("/events", eventRouter)
+		http.Handle("/events/", eventRouter)
+	}
+
+	operationRouter := LoggingMiddleware(logger, handlers.OperationRouter(handlers.NewOperationHandler(recorder, logger)))
+	http.Handle("/operations", operationRouter)
+	http.Handle("/operations/", operationRouter)
+
+	// Reviewers approve or reject expense reports, configured as name:token pairs
+	reviewers := make(map[string]string)
+	if list := os.Getenv("EXPENSE_REVIEWERS"); list != "" {
+		for _, entry := range strings.Split(list, ",") {
+			name, token, found := strings.Cut(strings.TrimSpace(entry), ":")
+			if !found || name == "" || token == "" {
+				logger.Error("Invalid reviewer in EXPENSE_REVIEWERS, expected name:token", "entry", entry)
+				os.Exit(1)
+			}
+			reviewers[token] = name
+		}
+		logger.Info("Loaded expense report reviewers", "count", len(reviewers))
+	}
+
+	var reportNotifier handlers.ExpenseReportNotifier
+	if url := os.Getenv("EXPENSE_REPORT_WEBHOOK_URL"); url != "" {
+		reportNotifier = webhook.NewNotifier(url, logger)
+		integrations = append(integrations, console.Integration{Kind: "expense-report-webhook", Target: console.Target(url)})
+	}
+
+	// Expense report exports follow the locale of the client unless one is configured
+	exportFormatting := handlers.ExportFormatting{
+		Locale:   os.Getenv("EXPORT_LOCALE"),
+		Currency: strings.ToUpper(os.Getenv("EXPORT_CURRENCY")),
+	}
+	if _, ok := i18n.FormatFor(exportFormatting.Locale); exportFormatting.Locale != "" && !ok {
+		logger.Error("Unsupported EXPORT_LOCALE value", "value", exportFormatting.Locale)
+		os.Exit(1)
+	}
+
+	shareRouter := LoggingMiddleware(logger, handlers.ReportShareRouter(handlers.NewReportShareHandler(service, categories, summaries, calendar, links, exportFormatting, logger)))
+	http.Handle("/reports/share", shareRouter)
+	http.Handle("/reports/shared/", shareRouter)
+
+	expenseReportRouter := LoggingMiddleware(logger, featureFlags.Require(features.ExpenseReports, handlers.ExpenseReportRouter(handlers.NewExpenseReportHandler(expenseReports, service, reviewers, reportNotifier, exportFormatting, logger))))
+	http.Handle("/expense-reports", expenseReportRouter)
+	http.Handle("/expense-reports/", expenseReportRouter)
+
+	if archives != nil {
+		retentionYears := 2 // Default value
+		if retentionStr := os.Getenv("ARCHIVE_RETENTION_YEARS"); retentionStr != "" {
+			retentionYears, err = strconv.Atoi(retentionStr)
+			if err != nil || retentionYears < 1 {
+				logger.Error("Invalid ARCHIVE_RETENTION_YEARS value", "error", err, "value", retentionStr)
+				os.Exit(1)
+			}
+		}
+
+		archiveRouter := LoggingMiddleware(logger, handlers.ArchiveRouter(handlers.NewArchiveHandler(archives, retentionYears, logger)))
+		http.Handle("/admin/archive", archiveRouter)
+		http.Handle("/admin/archive/", archiveRouter)
+	}
+
+	// Retention rules purge old records, e.g. RETENTION_RULES=expenditures=7y,drafts=90d, keeping
+	// an audit entry of every purge
+	if rulesStr := os.Getenv("RETENTION_RULES"); rulesStr != "" {
+		rules, err := domain.ParseRetentionRules(rulesStr)
+		if err != nil {
+			logger.Error("Invalid RETENTION_RULES value", "error", err, "value", rulesStr)
+			os.Exit(1)
+		}
+		if retentionStore == nil {
+			logger.Error("Retention rules are not supported by the storage")
+			os.Exit(1)
+		}
+
+		retentionInterval := 24 * time.Hour // Default value
+		if intervalStr := os.Getenv("RETENTION_INTERVAL"); intervalStr != "" {
+			retentionInterval, err = time.ParseDuration(intervalStr)
+			if err != nil || retentionInterval <= 0 {
+				logger.Error("Invalid RETENTION_INTERVAL value", "error", err, "value", intervalStr)
+				os.Exit(1)
+			}
+		}
+
+		// Purges go around the undo log, like erasures
+		purger, err := retention.NewPurger(rules, retentionStore, unrecorded, drafts, archives, retentionInterval, logger)
+		if err != nil {
+			logger.Error("Invalid RETENTION_RULES value", "error", err, "value", rulesStr)
+			os.Exit(1)
+		}
+		elector.Add("retention", purger)
+
+		retentionRouter := LoggingMiddleware(logger, handlers.RetentionRouter(handlers.NewRetentionHandler(purger, retentionStore, logger)))
+		http.Handle("/admin/retention/", retentionRouter)
+	}
+
+	// Development helpers that must never be reachable in production
+	devMode := false // Default value
+	if devStr := os.Getenv("DEV_MODE"); devStr != "" {
+		devMode, err = strconv.ParseBool(devStr)
+		if err != nil {
+			logger.Error("Invalid DEV_MODE value", "error", err, "value", devStr)
+			os.Exit(1)
+		}
+	}
+	if devMode && categories != nil {
+		seedHandler := handlers.NewSeedHandler(service, categories, logger)
+		http.Handle("/admin/seed", LoggingMiddleware(logger, handlers.Methods{http.MethodPost: seedHandler.RunSeed}))
+		logger.Warn("Development mode is enabled")
+	}
+
+	// Read-only mode turns changes away during backups and migrations; it can be entered at
+	// startup and toggled by admins holding the token
+	maintenanceMode := maintenance.New()
+	if maintenanceStr := os.Getenv("MAINTENANCE_MODE"); maintenanceStr != "" {
+		readOnly, err := strconv.ParseBool(maintenanceStr)
+		if err != nil {
+			logger.Error("Invalid MAINTENANCE_MODE value", "error", err, "value", maintenanceStr)
+			os.Exit(1)
+		}
+		if readOnly {
+			maintenanceMode.Enable("Started in maintenance mode", 0)
+			logger.Warn("Starting in read-only mode")
+		}
+	}
+	if token := os.Getenv("MAINTENANCE_TOKEN"); token != "" {
+		http.Handle(maintenance.Path, LoggingMiddleware(logger, handlers.MaintenanceRouter(handlers.NewMaintenanceHandler(maintenanceMode, token, logger))))
+	}
+
+	// Feature flags can be toggled at runtime by admins holding the token
+	if token := os.Getenv("FEATURE_FLAGS_TOKEN"); token != "" {
+		flagRouter := LoggingMiddleware(logger, handlers.FlagRouter(handlers.NewFlagHandler(featureFlags, token, logger)))
+		http.Handle("/admin/flags", flagRouter)
+		http.Handle("/admin/flags/", flagRouter)
+	}
+
+	if indexStats != nil {
+		indexHandler := handlers.NewIndexHandler(indexStats, logger)
+		http.Handle("/admin/indexes", LoggingMiddleware(logger, handlers.Methods{http.MethodGet: indexHandler.GetIndexUsage}))
+	}
+
+	// Rebuild the spending summaries now and then, in case the incremental updates drifted
+	if summaries != nil {
+		refreshInterval := 24 * time.Hour // Default value
+		if intervalStr := os.Getenv("SUMMARY_REFRESH_INTERVAL"); intervalStr != "" {
+			refreshInterval, err = time.ParseDuration(intervalStr)
+			if err != nil || refreshInterval <= 0 {
+				logger.Error("Invalid SUMMARY_REFRESH_INTERVAL value", "error", err, "value", intervalStr)
+				os.Exit(1)
+			}
+		}
+		elector.Add("summary-refresher", reports.NewSummaryRefresher(summaries, refreshInterval, logger))
+	}
+
+	// Stream every change to expenditures to NATS JetStream and Kafka for analytics pipelines
+	var streamSinks outbox.Subscribers
+	if server := os.Getenv("NATS_URL"); server != "" {
+		subject := "expense-tracker.{event}" // Default value
+		if subjectStr := os.Getenv("NATS_SUBJECT"); subjectStr != "" {
+			subject = subjectStr
+		}
+		sink, err := streaming.NewNATSSink(streaming.NATSConfig{
+			Server:   server,
+			Username: os.Getenv("NATS_USERNAME"),
+			Password: os.Getenv("NATS_PASSWORD"),
+			Token:    os.Getenv("NATS_TOKEN"),
+			Subject:  subject,
+		}, logger)
+		if err != nil {
+			logger.Error("Invalid NATS configuration", "error", err)
+			os.Exit(1)
+		}
+		go sink.Run(context.Background())
+		streamSinks = append(streamSinks, sink)
+		integrations = append(integrations, console.Integration{Kind: "nats", Target: console.Target(server), Detail: "subjects " + subject})
+	}
+	if restURL := os.Getenv("KAFKA_REST_URL"); restURL != "" {
+		topic := "expense-tracker.expenditures" // Default value
+		if topicStr := os.Getenv("KAFKA_TOPIC"); topicStr != "" {
+			topic = topicStr
+		}
+		sink, err := streaming.NewKafkaSink(streaming.KafkaConfig{
+			RestURL:  restURL,
+			Topic:    topic,
+			Username: os.Getenv("KAFKA_USERNAME"),
+			Password: os.Getenv("KAFKA_PASSWORD"),
+		}, logger)
+		if err != nil {
+			logger.Error("Invalid Kafka configuration", "error", err)
+			os.Exit(1)
+		}
+		streamSinks = append(streamSinks, sink)
+		integrations = append(integrations, console.Integration{Kind: "kafka", Target: console.Target(restURL), Detail: "topic " + topic})
+	}
+
+	// Relay every change to expenditures to a webhook, an MQTT broker and the streaming sinks
+	// through the outbox, which the storage writes together with the change so a crash cannot lose it
+	if outboxStore != nil {
+		var subscribers outbox.Subscribers
+		outboxURL := os.Getenv("OUTBOX_WEBHOOK_URL")
+		if outboxURL != "" {
+			// The webhook can subscribe to some changes only, e.g. "category={id}&amount_above=100"
+			var subscriber outbox.Subscriber = webhook.NewNotifier(outboxURL, logger)
+			if filterStr := os.Getenv("OUTBOX_WEBHOOK_FILTER"); filterStr != "" {
+				query, err := url.ParseQuery(filterStr)
+				if err != nil {
+					logger.Error("Invalid OUTBOX_WEBHOOK_FILTER value", "error", err, "value", filterStr)
+					os.Exit(1)
+				}
+				filter, err := domain.ParseEventFilter(query)
+				if err != nil {
+					logger.Error("Invalid OUTBOX_WEBHOOK_FILTER value", "error", err, "value", filterStr)
+					os.Exit(1)
+				}
+				subscriber = outbox.Filtered{Subscriber: subscriber, Filter: filter}
+			}
+			subscribers = append(subscribers, subscriber)
+			integrations = append(integrations, console.Integration{Kind: "outbox-webhook", Target: console.Target(outboxURL), Detail: os.Getenv("OUTBOX_WEBHOOK_FILTER")})
+		}
+		if mqttPublisher != nil {
+			subscribers = append(subscribers, mqttPublisher)
+		}
+		subscribers = append(subscribers, streamSinks...)
+
+		if err := outboxStore.EnableOutbox(len(subscribers) > 0); err != nil {
+			logger.Error("Failed to set up the outbox", "error", err)
+			os.Exit(1)
+		}
+
+		if len(subscribers) > 0 {
+			relayInterval := 5 * time.Second // Default value
+			if intervalStr := os.Getenv("OUTBOX_RELAY_INTERVAL"); intervalStr != "" {
+				relayInterval, err = time.ParseDuration(intervalStr)
+				if err != nil || relayInterval <= 0 {
+					logger.Error("Invalid OUTBOX_RELAY_INTERVAL value", "error", err, "value", intervalStr)
+					os.Exit(1)
+				}
+			}
+			elector.Add("outbox-relay", outbox.NewRelay(outboxStore, subscribers, relayInterval, logger))
+		}
+
+		// Admins holding the token can list the messages given up on and queue them again, on any
+		// replica as the outbox is shared
+		if token := os.Getenv("OUTBOX_ADMIN_TOKEN"); token != "" {
+			http.Handle("/admin/outbox/", LoggingMiddleware(logger, handlers.OutboxRouter(handlers.NewOutboxHandler(outboxStore, token, logger))))
+		}
+	} else if mqttPublisher != nil || len(streamSinks) > 0 {
+		logger.Warn("Storage has no outbox, changes to expenditures are not published to MQTT, NATS or Kafka")
+	}
+
+	// Keep a snapshot of the report of every month once it closes
+	if reportSnapshots != nil {
+		snapshotInterval := time.Hour // Default value
+		if intervalStr := os.Getenv("REPORT_SNAPSHOT_INTERVAL"); intervalStr != "" {
+			snapshotInterval, err = time.ParseDuration(intervalStr)
+			if err != nil || snapshotInterval <= 0 {
+				logger.Error("Invalid REPORT_SNAPSHOT_INTERVAL value", "error", err, "value", intervalStr)
+				os.Exit(1)
+			}
+		}
+		elector.Add("report-snapshots", reports.NewSnapshotter(reportSnapshots, service, categories, summaries, calendar, snapshotInterval, logger))
+	}
+
+	// Set up bank connectors and the scheduled transaction sync
+	var connectors []banking.BankConnector
+	if clientID := os.Getenv("PLAID_CLIENT_ID"); clientID != "" {
+		plaidEnv := os.Getenv("PLAID_ENV")
+		if plaidEnv == "" {
+			plaidEnv = "sandbox" // Default value
+		}
+
+		plaid, err := banking.NewPlaidConnector(clientID, os.Getenv("PLAID_SECRET"), plaidEnv)
+		if err != nil {
+			logger.Error("Failed to initialize Plaid connector", "error", err)
+			os.Exit(1)
+		}
+		connectors = append(connectors, plaid)
+	}
+
+	syncInterval := 6 * time.Hour // Default value
+	if intervalStr := os.Getenv("BANK_SYNC_INTERVAL"); intervalStr != "" {
+		syncInterval, err = time.ParseDuration(intervalStr)
+		if err != nil || syncInterval <= 0 {
+			logger.Error("Invalid BANK_SYNC_INTERVAL value", "error", err, "value", intervalStr)
+			os.Exit(1)
+		}
+	}
+
+	syncer := banking.NewSyncer(connections, imports, syncInterval, logger, connectors...)
+	syncer.SetEnabled(func() bool {
+		return featureFlags.Enabled(features.BankSync) && !maintenanceMode.State().ReadOnly
+	})
+	if len(connectors) > 0 {
+		elector.Add("bank-sync", syncer)
+	}
+
+	connectionRouter := LoggingMiddleware(logger, featureFlags.Require(features.BankSync, handlers.ConnectionRouter(handlers.NewConnectionHandler(connections, syncer, logger))))
+	http.Handle("/connections", connectionRouter)
+	http.Handle("/connections/", connectionRouter)
+
+	if token := os.Getenv("CALENDAR_FEED_TOKEN"); token != "" {
+		calendarHandler := handlers.NewCalendarHandler(service, budgets, recurringStore, calendar, token, logger)
+		http.Handle("/calendar.ics", LoggingMiddleware(logger, handlers.Methods{http.MethodGet: calendarHandler.GetCalendarFeed}))
+	}
+
+	if key := os.Getenv("MAILGUN_SIGNING_KEY"); key != "" {
+		mailgunHandler := email.NewMailgunHandler(key, imports, logger)
+		http.Handle("/integrations/email/mailgun", LoggingMiddleware(logger, mailgunHandler))
+	}
+
+	if secret := os.Getenv("SLACK_SIGNING_SECRET"); secret != "" {
+		slackHandler := slack.NewCommandHandler(secret, slackWorkspaces, expenditureService, categories, rules, logger)
+		http.Handle("/integrations/slack/commands", LoggingMiddleware(logger, slackHandler))
+	}
+
+	if config := os.Getenv("AUTOMATION_API_KEYS"); config != "" {
+		keys, err := automation.ParseKeys(config)
+		if err != nil {
+			logger.Error("Invalid AUTOMATION_API_KEYS value", "error", err)
+			os.Exit(1)
+		}
+		automationHandler := automation.NewHandler(keys, expenditureService, service, categories, rules, feed, logger)
+		for name := range keys {
+			integrations = append(integrations, console.Integration{Kind: "automation", Target: name})
+		}
+		http.Handle("/integrations/automation/", LoggingMiddleware(logger, automationHandler))
+	}
+
+	// Start the Telegram bot if a token is configured
+	if token := os.Getenv("TELEGRAM_BOT_TOKEN"); token != "" {
+		botConfig := telegram.Config{Token: token, Calendar: calendar}
+
+		for _, idStr := range strings.Split(os.Getenv("TELEGRAM_ALLOWED_CHAT_IDS"), ",") {
+			if idStr = strings.TrimSpace(idStr); idStr == "" {
+				continue
+			}
+			chatID, err := strconv.ParseInt(idStr, 10, 64)
+			if err != nil {
+				logger.Error("Invalid TELEGRAM_ALLOWED_CHAT_IDS value", "error", err, "value", idStr)
+				os.Exit(1)
+			}
+			botConfig.AllowedChatIDs = append(botConfig.AllowedChatIDs, chatID)
+		}
+
+		if categoryStr := os.Getenv("TELEGRAM_DEFAULT_CATEGORY_ID"); categoryStr != "" {
+			botConfig.DefaultCategoryID, err = uuid.Parse(categoryStr)
+			if err != nil {
+				logger.Error("Invalid TELEGRAM_DEFAULT_CATEGORY_ID value", "error", err, "value", categoryStr)
+				os.Exit(1)
+			}
+		}
+
+		if budgetStr := os.Getenv("TELEGRAM_MONTHLY_BUDGET"); budgetStr != "" {
+			botConfig.MonthlyBudget, err = strconv.ParseFloat(budgetStr, 64)
+			if err != nil {
+				logger.Error("Invalid TELEGRAM_MONTHLY_BUDGET value", "error", err, "value", budgetStr)
+				os.Exit(1)
+			}
+		}
+
+		bot, err := telegram.NewBot(botConfig, expenditureService, service, categories, rules, logger)
+		if err != nil {
+			logger.Error("Failed to initialize Telegram bot", "error", err)
+			os.Exit(1)
+		}
+		elector.Add("telegram", bot)
+		integrations = append(integrations, console.Integration{Kind: "telegram", Target: "api.telegram.org"})
+	}
+
+	// Messages are translated into the language each client asks for, or the default one
+	catalog := i18n.Default()
+	language := i18n.DefaultLanguage
+	if languageStr := os.Getenv("DEFAULT_LANGUAGE"); languageStr != "" {
+		if !catalog.Supports(languageStr) {
+			logger.Error("Unsupported DEFAULT_LANGUAGE value", "value", languageStr, "languages", catalog.Languages())
+			os.Exit(1)
+		}
+		language = languageStr
+	}
+
+	// The admin console lets operators of the console operators file in, with a role each
+	if path := os.Getenv("ADMIN_CONSOLE_OPERATORS_FILE"); path != "" {
+		operators, err := console.LoadOperators(path)
+		if err != nil {
+			logger.Error("Failed to load admin console operators", "error", err, "path", path)
+			os.Exit(1)
+		}
+		adminConsole := console.New(operators, build, featureFlags, maintenanceMode, householdService, privacyService, outboxStore, jobRunner, elector, integrations, logger)
+		http.Handle(console.Path, LoggingMiddleware(logger, adminConsole.Handler()))
+		maintenanceMode.Exempt(console.MaintenancePath)
+		logger.Info("Admin console enabled", "path", console.Path, "operators", len(operators))
+	}
+
+	// Start the server, or let requests through when it is already answering
+	var server http.Handler = handlers.InvalidateDashboard(dashboard, http.DefaultServeMux)
+	if storageBreaker != nil {
+		server = storageBreaker.Middleware(server)
+	}
+	if households != nil {
+		// Expenditures are attributed to the household member named in the X-Member-ID header,
+		// whose role decides what the request may do
+		server = handlers.MemberMiddleware(households, logger, handlers.AccessMiddleware(logger, server))
+	}
+	api := i18n.Middleware(catalog, language, maintenanceMode.Middleware(server))
+
+	// Batches sit in front of the middleware, which checks each of their requests as if sent alone;
+	// they only read, so neither roles nor read-only modes turn the batch itself away
+	batchHandler := handlers.NewBatchHandler(api, logger)
+	withBatch := http.NewServeMux()
+	withBatch.Handle("/batch", LoggingMiddleware(logger, handlers.Methods{http.MethodPost: batchHandler.RunBatch}))
+	withBatch.Handle("/", api)
+	if devMode {
+		// The API console sends its requests through the same middleware as any client
+		apiConsole := devconsole.New(withBatch, devconsole.ParseSamples(requestsFile), logCapture, logger)
+		withBatch.Handle(devconsole.Path, apiConsole.Handler())
+		logger.Info("API console enabled", "path", devconsole.Path)
+	}
+
+	// Fault injection makes configured routes slow or unavailable for resilience testing; it is
+	// only available in development mode
+	var served http.Handler = withBatch
+	chaosRules, chaosToken := os.Getenv("CHAOS_RULES"), os.Getenv("CHAOS_TOKEN")
+	if (chaosRules != "" || chaosToken != "") && !devMode {
+		logger.Error("CHAOS_RULES and CHAOS_TOKEN require DEV_MODE=true")
+		os.Exit(1)
+	}
+	if chaosRules != "" || chaosToken != "" {
+		rules, err := chaos.ParseRules(chaosRules)
+		if err != nil {
+			logger.Error("Invalid CHAOS_RULES value", "error", err, "value", chaosRules)
+			os.Exit(1)
+		}
+		injector := chaos.New(rules, logger)
+		if chaosToken != "" {
+			withBatch.Handle(chaos.Path, LoggingMiddleware(logger, handlers.ChaosRouter(handlers.NewChaosHandler(injector, chaosToken, logger))))
+		}
+		served = injector.Middleware(withBatch)
+		logger.Warn("Fault injection is enabled", "rules", chaosRules)
+	}
+
+	// Browsers may call the API from the allowed origins; without any, only from its own
+	if origins := handlers.ParseCORSOrigins(os.Getenv("CORS_ALLOWED_ORIGINS")); len(origins) > 0 {
+		served = handlers.CORSMiddleware(origins, served)
+		logger.Info("CORS enabled", "origins", origins)
+	}
+
+	// Debug recording keeps what clients sent and got, redacted, to replay against another instance
+	recordingEnabled := false // Default value
+	if recordingStr := os.Getenv("DEBUG_RECORDING"); recordingStr != "" {
+		recordingEnabled, err = strconv.ParseBool(recordingStr)
+		if err != nil {
+			logger.Error("Invalid DEBUG_RECORDING value", "error", err, "value", recordingStr)
+			os.Exit(1)
+		}
+	}
+	if recordingEnabled {
+		options := recording.Options{Size: 200, File: os.Getenv("DEBUG_RECORDING_FILE"), MaxBody: 64 * 1024} // Default values
+		if sizeStr := os.Getenv("DEBUG_RECORDING_SIZE"); sizeStr != "" {
+			options.Size, err = strconv.Atoi(sizeStr)
+			if err != nil || options.Size <= 0 {
+				logger.Error("Invalid DEBUG_RECORDING_SIZE value", "error", err, "value", sizeStr)
+				os.Exit(1)
+			}
+		}
+		if maxBodyStr := os.Getenv("DEBUG_RECORDING_MAX_BODY"); maxBodyStr != "" {
+			options.MaxBody, err = strconv.Atoi(maxBodyStr)
+			if err != nil || options.MaxBody <= 0 {
+				logger.Error("Invalid DEBUG_RECORDING_MAX_BODY value", "error", err, "value", maxBodyStr)
+				os.Exit(1)
+			}
+		}
+		recorder, err := recording.NewRecorder(options)
+		if err != nil {
+			logger.Error("Failed to start debug recording", "error", err, "file", options.File)
+			os.Exit(1)
+		}
+		defer recorder.Close()
+		if token := os.Getenv("DEBUG_RECORDING_TOKEN"); token != "" {
+			withBatch.Handle(recording.Path, LoggingMiddleware(logger, handlers.RecordingRouter(handlers.NewRecordingHandler(recorder, token, logger))))
+		}
+		served = recorder.Middleware(served)
+		logger.Warn("Debug recording is enabled", "size", options.Size, "file", options.File, "max_body", options.MaxBody)
+	}
+
+	go elector.Run(context.Background())
+	gate.Open(served)
+	if serveEarly {
+		logger.Info("API is ready", "address", serverAddr, "default_language", language)
+		select {}
+	}
+	logger.Info("Starting HTTP server", "address", serverAddr, "default_language", language)
+	err = http.ListenAndServe(serverAddr, gate)
 	if err != nil {
 		logger.Error("Server failed to start", "error", err)
 		os.Exit(1)
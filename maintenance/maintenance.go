@@ -0,0 +1,100 @@
+// Package maintenance puts the API into read-only mode, e.g. during backups or storage
+// migrations: reads keep working while changes are turned away until the mode is lifted.
+package maintenance
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Path is where the mode is managed; it stays writable so the mode can be lifted
+const Path = "/admin/maintenance"
+
+// DefaultRetryAfter is how long clients are told to wait when no estimate is given
+const DefaultRetryAfter = 5 * time.Minute
+
+// State describes the current mode
+type State struct {
+	ReadOnly   bool       `json:"read_only"`
+	Reason     string     `json:"reason,omitempty"`      // Why, for admins, e.g. "Nightly backup"
+	RetryAfter int        `json:"retry_after,omitempty"` // Seconds clients are told to wait
+	Since      *time.Time `json:"since,omitempty"`       // When read-only mode was entered
+}
+
+// Mode holds whether the API is read-only. It is safe for concurrent use
+type Mode struct {
+	mu     sync.RWMutex
+	state  State
+	exempt []string
+}
+
+// New creates a Mode that lets changes through
+func New() *Mode {
+	return &Mode{}
+}
+
+// Enable makes the API read-only; clients are asked to retry after retryAfter, or
+// DefaultRetryAfter when it is not positive
+func (m *Mode) Enable(reason string, retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		retryAfter = DefaultRetryAfter
+	}
+	since := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state = State{
+		ReadOnly:   true,
+		Reason:     reason,
+		RetryAfter: int(retryAfter.Round(time.Second) / time.Second),
+		Since:      &since,
+	}
+}
+
+// Disable lets changes through again
+func (m *Mode) Disable() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state = State{}
+}
+
+// State returns the current mode
+func (m *Mode) State() State {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.state
+}
+
+// Exempt keeps other endpoints managing the mode writable, such as that of the admin console;
+// it is called before the middleware serves requests
+func (m *Mode) Exempt(paths ...string) {
+	m.exempt = append(m.exempt, paths...)
+}
+
+// Middleware answers requests that change data with 503 Service Unavailable and a Retry-After
+// header while the API is read-only. Reads, and the maintenance endpoints themselves, go through
+func (m *Mode) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.URL.Path == Path || slices.Contains(m.exempt, r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		state := m.State()
+		if !state.ReadOnly {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Retry-After", strconv.Itoa(state.RetryAfter))
+		http.Error(w, "The API is read-only for maintenance, try again later", http.StatusServiceUnavailable)
+	})
+}
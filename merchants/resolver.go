@@ -0,0 +1,78 @@
+// Package merchants links expenditures to entries of the merchant directory.
+package merchants
+
+import (
+	"fmt"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Resolver finds the merchant of an expenditure from its description
+type Resolver struct {
+	merchants domain.MerchantRepository
+	logger    *slog.Logger
+	mu        sync.Mutex // Serializes MatchOrCreate so concurrent imports do not create duplicates
+}
+
+// NewResolver creates a new Resolver over the merchant directory
+func NewResolver(merchants domain.MerchantRepository, logger *slog.Logger) *Resolver {
+	return &Resolver{
+		merchants: merchants,
+		logger:    logger,
+	}
+}
+
+// Match returns the merchant the description belongs to, or nil when none matches or the
+// storage has no merchant directory
+func (r *Resolver) Match(description string) (*domain.Merchant, error) {
+	if r.merchants == nil {
+		return nil, nil
+	}
+
+	all, err := r.merchants.GetAllMerchants()
+	if err != nil {
+		return nil, fmt.Errorf("error getting merchants: %w", err)
+	}
+	return domain.MatchMerchant(all, description), nil
+}
+
+// MatchOrCreate returns the merchant the description belongs to and adds a new merchant to
+// the directory when none matches. It is used for imported transactions, whose
+// descriptions are merchant names; it returns nil when the description has no usable name
+func (r *Resolver) MatchOrCreate(description string) (*domain.Merchant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	merchant, err := r.Match(description)
+	if err != nil || merchant != nil {
+		return merchant, err
+	}
+
+	key := domain.NormalizeMerchantName(description)
+	if key == "" || r.merchants == nil {
+		return nil, nil
+	}
+
+	merchant, err = domain.NewMerchant(domain.MerchantDisplayName(key), []string{description}, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.merchants.AddMerchant(merchant); err != nil {
+		return nil, fmt.Errorf("error adding merchant: %w", err)
+	}
+
+	r.logger.Info("Created merchant from description", "id", merchant.ID, "name", merchant.Name, "description", description)
+	return merchant, nil
+}
+
+// Get returns a merchant by ID
+func (r *Resolver) Get(id uuid.UUID) (*domain.Merchant, error) {
+	if r.merchants == nil {
+		return nil, domain.ErrMerchantNotFound
+	}
+	return r.merchants.GetMerchantByID(id.String())
+}
@@ -0,0 +1,87 @@
+// Package meta holds the curated color palette and icon set of categories, embedded in the
+// binary, so every client renders categories alike without shipping assets of its own.
+//
+// The icons are SVG files in icons, drawn with currentColor so clients can tint them with the
+// color of their category; their names are those of domain.CategoryIcons.
+package meta
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// IconsPath is where the icon set is listed; each icon is served below it as name.svg
+const IconsPath = "/meta/icons"
+
+//go:embed palette.json icons/*.svg
+var assets embed.FS
+
+// Color is a color of the palette
+type Color struct {
+	Name string `json:"name"`
+	Hex  string `json:"hex"` // Such as #FF6B6B
+}
+
+// Icon is an icon of the set
+type Icon struct {
+	Name string `json:"name"`
+	URL  string `json:"url"` // Path of the SVG file
+}
+
+var (
+	palette []Color
+	icons   []Icon
+)
+
+func init() {
+	data, err := assets.ReadFile("palette.json")
+	if err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal(data, &palette); err != nil {
+		panic("invalid embedded palette: " + err.Error())
+	}
+
+	files, err := fs.Glob(assets, "icons/*.svg")
+	if err != nil {
+		panic(err)
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(path.Base(file), ".svg")
+		icons = append(icons, Icon{Name: name, URL: IconsPath + "/" + name + ".svg"})
+	}
+	sort.Slice(icons, func(i, j int) bool { return icons[i].Name < icons[j].Name })
+}
+
+// Palette returns the colors categories are given, in the order clients should offer them
+func Palette() []Color {
+	return append([]Color{}, palette...)
+}
+
+// InPalette reports whether hex is a color of the palette, in any case
+func InPalette(hex string) bool {
+	for _, color := range palette {
+		if strings.EqualFold(color.Hex, hex) {
+			return true
+		}
+	}
+	return false
+}
+
+// Icons returns the icons of the set, ordered by name
+func Icons() []Icon {
+	return append([]Icon{}, icons...)
+}
+
+// IconSVG returns the SVG file of the icon name
+func IconSVG(name string) ([]byte, bool) {
+	if name == "" || strings.ContainsAny(name, "/.") {
+		return nil, false
+	}
+	data, err := assets.ReadFile("icons/" + name + ".svg")
+	return data, err == nil
+}
@@ -0,0 +1,41 @@
+package meta_test
+
+import (
+	"bytes"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/meta"
+	"testing"
+)
+
+// TestIconsMatchCategoryIcons checks that every icon a category accepts has an SVG file, and
+// that no file is left without a name categories accept
+func TestIconsMatchCategoryIcons(t *testing.T) {
+	files := make(map[string]bool)
+	for _, icon := range meta.Icons() {
+		files[icon.Name] = true
+		svg, ok := meta.IconSVG(icon.Name)
+		if !ok || !bytes.HasPrefix(svg, []byte("<svg ")) {
+			t.Errorf("icon %s: not an SVG file", icon.Name)
+		}
+		if !domain.CategoryIcons[icon.Name] {
+			t.Errorf("icon %s is not in domain.CategoryIcons", icon.Name)
+		}
+	}
+	for name := range domain.CategoryIcons {
+		if !files[name] {
+			t.Errorf("category icon %s has no SVG file", name)
+		}
+	}
+}
+
+// TestPaletteHoldsDefaultColors checks that the default categories are given colors of the palette
+func TestPaletteHoldsDefaultColors(t *testing.T) {
+	for name, color := range domain.DefaultCategories {
+		if !meta.InPalette(color) {
+			t.Errorf("color %s of the default category %s is not in the palette", color, name)
+		}
+	}
+	if meta.InPalette("") {
+		t.Error("the empty color is in the palette")
+	}
+}
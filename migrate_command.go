@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/migration"
+	"go-expense-tracker/storage"
+	"io"
+	"log/slog"
+)
+
+// runMigrateCommand copies the data of the configured storage to another one and verifies the
+// copy; it returns the exit code of the process
+func runMigrateCommand(service domain.ExpenditureRepository, args []string, logger *slog.Logger) int {
+	flags := flag.NewFlagSet("migrate-storage", flag.ContinueOnError)
+	to := flags.String("to", "", "Storage driver to copy the data to")
+	toDSN := flags.String("to-dsn", "", "Connection string of the storage to copy the data to")
+	batchSize := flags.Int("batch-size", migration.DefaultBatchSize, "Number of expenditures added at a time")
+	verifyOnly := flags.Bool("verify-only", false, "Only compare the storages, e.g. after a dual-write cutover")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *to == "" {
+		logger.Error("Missing -to storage driver to migrate to")
+		return 2
+	}
+	if *batchSize <= 0 {
+		logger.Error("Invalid -batch-size value", "value", *batchSize)
+		return 2
+	}
+
+	target, err := storage.Open(*to, *toDSN, logger)
+	if err != nil {
+		logger.Error("Failed to open target storage", "error", err, "driver", *to)
+		return 1
+	}
+	if closer, ok := target.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	var report *migration.Report
+	if *verifyOnly {
+		report, err = migration.Verify(service, target)
+	} else {
+		report, err = migration.Copy(service, target, *batchSize, logger)
+	}
+	if err != nil {
+		logger.Error("Failed to migrate storage", "error", err, "driver", *to)
+		return 1
+	}
+
+	if !report.Verified() {
+		logger.Error("Storages differ after migration",
+			"source_count", report.SourceCount, "target_count", report.TargetCount,
+			"source_checksum", report.SourceChecksum, "target_checksum", report.TargetChecksum)
+		return 1
+	}
+
+	logger.Info("Migrated storage", "driver", *to,
+		"categories", report.Categories, "categories_existing", report.CategoriesExisting,
+		"expenditures", report.Expenditures, "expenditures_existing", report.ExpendituresExisting,
+		"count", report.TargetCount, "checksum", report.TargetChecksum)
+	return 0
+}
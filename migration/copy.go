@@ -0,0 +1,236 @@
+// Package migration moves the data of one storage to another: it copies and verifies it, and
+// writes changes to both storages while clients are cut over.
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultBatchSize is the number of expenditures added to the target storage at a time
+const DefaultBatchSize = 500
+
+// Report describes a copy and how its verification went
+type Report struct {
+	Categories           int    // Categories added to the target
+	CategoriesExisting   int    // Categories the target had already, by ID or name
+	Expenditures         int    // Expenditures added to the target
+	ExpendituresExisting int    // Expenditures the target had already, by ID
+	SourceCount          int    // Expenditures in the source
+	TargetCount          int    // Expenditures in the target after the copy
+	SourceChecksum       string // Checksum of the expenditures in the source
+	TargetChecksum       string // Checksum of the expenditures in the target
+}
+
+// Verified reports whether the target holds the same expenditures as the source
+func (r *Report) Verified() bool {
+	return r.SourceCount == r.TargetCount && r.SourceChecksum == r.TargetChecksum
+}
+
+// Copy copies the categories and expenditures of source to target and verifies the result.
+// Records the target has already are skipped, so an interrupted copy can be run again
+func Copy(source, target domain.ExpenditureRepository, batchSize int, logger *slog.Logger) (*Report, error) {
+	report := &Report{}
+
+	mapping, err := copyCategories(source, target, report, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := domain.CountExpenditures(source, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("error counting source expenditures: %w", err)
+	}
+	logger.Info("Copying expenditures", "total", total, "batch_size", batchSize)
+
+	batch := make([]*domain.Expenditure, 0, batchSize)
+	flush := func() error {
+		if err := addBatch(target, batch, report); err != nil {
+			return err
+		}
+		batch = batch[:0]
+		logger.Info("Copied expenditures", "copied", report.Expenditures, "existing", report.ExpendituresExisting, "total", total)
+		return nil
+	}
+
+	// Refunds are copied after every original, which they reference
+	var refunds []*domain.Expenditure
+	add := func(expenditure *domain.Expenditure) error {
+		batch = append(batch, mapping.Expenditure(expenditure))
+		if len(batch) < batchSize {
+			return nil
+		}
+		return flush()
+	}
+	err = domain.EachExpenditure(source, func(expenditure *domain.Expenditure) error {
+		if expenditure.IsRefund() {
+			refunds = append(refunds, expenditure)
+			return nil
+		}
+		return add(expenditure)
+	})
+	for _, refund := range refunds {
+		if err != nil {
+			break
+		}
+		err = add(refund)
+	}
+	if err == nil && len(batch) > 0 {
+		err = flush()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error copying expenditures: %w", err)
+	}
+
+	if err := verify(source, target, mapping, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Verify compares the expenditures of source and target without copying anything
+func Verify(source, target domain.ExpenditureRepository) (*Report, error) {
+	mapping := CategoryMapping{}
+	sourceCategories, sourceOk := source.(domain.CategoryRepository)
+	targetCategories, targetOk := target.(domain.CategoryRepository)
+	if sourceOk && targetOk {
+		var err error
+		if mapping, err = MapCategories(sourceCategories, targetCategories); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &Report{}
+	if err := verify(source, target, mapping, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// addBatch adds the expenditures in bulk and, when some of them exist in the target already,
+// one by one skipping those
+func addBatch(target domain.ExpenditureRepository, batch []*domain.Expenditure, report *Report) error {
+	err := domain.AddExpenditures(target, batch)
+	if err == nil {
+		report.Expenditures += len(batch)
+		return nil
+	}
+	if !errors.Is(err, domain.ErrExpenditureAlreadyExists) {
+		return err
+	}
+
+	for _, expenditure := range batch {
+		if _, err := target.GetExpenditureByID(expenditure.ID.String()); err == nil {
+			report.ExpendituresExisting++
+			continue
+		}
+		if err := target.AddExpenditure(expenditure); err != nil {
+			return err
+		}
+		report.Expenditures++
+	}
+	return nil
+}
+
+// copyCategories adds the categories of source the target does not have, parents before their
+// subcategories. Categories the target has under another ID, such as the default categories
+// every storage seeds with its own IDs, are matched by name
+func copyCategories(source, target domain.ExpenditureRepository, report *Report, logger *slog.Logger) (CategoryMapping, error) {
+	sourceCategories, sourceOk := source.(domain.CategoryRepository)
+	targetCategories, targetOk := target.(domain.CategoryRepository)
+	if !sourceOk || !targetOk {
+		logger.Warn("Not copying categories, a storage does not support them", "source", sourceOk, "target", targetOk)
+		return CategoryMapping{}, nil
+	}
+
+	categories, err := sourceCategories.GetAllCategories()
+	if err != nil {
+		return nil, fmt.Errorf("error getting source categories: %w", err)
+	}
+	existing, err := targetCategories.GetAllCategories()
+	if err != nil {
+		return nil, fmt.Errorf("error getting target categories: %w", err)
+	}
+	mapping := mapCategories(categories, existing)
+
+	added := make(map[uuid.UUID]bool)
+	for _, category := range existing {
+		added[category.ID] = true
+	}
+	for len(categories) > 0 {
+		var waiting []*domain.Category
+		for _, category := range categories {
+			if _, ok := mapping[category.ID]; ok || added[category.ID] {
+				report.CategoriesExisting++
+				continue
+			}
+			parent := mapping.ID(category.ParentID)
+			if parent != uuid.Nil && !added[parent] {
+				waiting = append(waiting, category)
+				continue
+			}
+
+			copied := *category
+			copied.ParentID = parent
+			if err := targetCategories.AddCategory(&copied); err != nil {
+				return nil, fmt.Errorf("error adding category %q: %w", category.Name, err)
+			}
+			added[category.ID] = true
+			report.Categories++
+		}
+
+		// Subcategories of a parent that is missing from the source cannot be placed
+		if len(waiting) == len(categories) {
+			return nil, fmt.Errorf("error adding categories: parent of %q not found", waiting[0].Name)
+		}
+		categories = waiting
+	}
+
+	logger.Info("Copied categories", "copied", report.Categories, "existing", report.CategoriesExisting, "renamed_ids", len(mapping))
+	return mapping, nil
+}
+
+// verify counts and checksums the expenditures of both storages
+func verify(source, target domain.ExpenditureRepository, mapping CategoryMapping, report *Report) error {
+	var err error
+	if report.SourceCount, report.SourceChecksum, err = checksum(source, mapping); err != nil {
+		return fmt.Errorf("error checksumming source expenditures: %w", err)
+	}
+	if report.TargetCount, report.TargetChecksum, err = checksum(target, nil); err != nil {
+		return fmt.Errorf("error checksumming target expenditures: %w", err)
+	}
+	return nil
+}
+
+// checksum counts the expenditures and combines the hashes of their fields in a way that does
+// not depend on their order. Only fields every storage keeps are hashed, at the precision the
+// SQL storages keep them: amounts in cents and dates in microseconds
+func checksum(repo domain.ExpenditureRepository, mapping CategoryMapping) (int, string, error) {
+	count := 0
+	var sum [sha256.Size]byte
+	err := domain.EachExpenditure(repo, func(expenditure *domain.Expenditure) error {
+		tags := slices.Clone(expenditure.Tags)
+		slices.Sort(tags)
+
+		line := fmt.Sprintf("%s|%s|%.2f|%s|%s|%s",
+			expenditure.ID, expenditure.Description, expenditure.Amount,
+			expenditure.Date.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+			mapping.ID(expenditure.CategoryId), strings.Join(tags, ","))
+		hash := sha256.Sum256([]byte(line))
+		for i := range sum {
+			sum[i] ^= hash[i]
+		}
+		count++
+		return nil
+	})
+	return count, hex.EncodeToString(sum[:]), err
+}
@@ -0,0 +1,168 @@
+package migration
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+// DualWriteRepository wraps the storage in use and writes every change to expenditures to a
+// second storage as well, so the second one stays current while clients are cut over to it.
+// Reads and the result of writes come from the primary storage; failures of the secondary are
+// logged and left for the verification of migrate-storage to find
+type DualWriteRepository struct {
+	domain.ExpenditureWrapper
+	secondary domain.ExpenditureRepository
+	mapping   CategoryMapping
+	logger    *slog.Logger
+}
+
+// NewDualWriteRepository creates a new DualWriteRepository writing to primary and secondary
+func NewDualWriteRepository(primary, secondary domain.ExpenditureRepository, mapping CategoryMapping, logger *slog.Logger) *DualWriteRepository {
+	return &DualWriteRepository{
+		ExpenditureWrapper: domain.ExpenditureWrapper{ExpenditureRepository: primary},
+		secondary:          secondary,
+		mapping:            mapping,
+		logger:             logger,
+	}
+}
+
+// AddExpenditure adds the expenditure to both storages
+func (r *DualWriteRepository) AddExpenditure(expenditure *domain.Expenditure) error {
+	if err := r.ExpenditureRepository.AddExpenditure(expenditure); err != nil {
+		return err
+	}
+
+	if err := r.secondary.AddExpenditure(r.mapping.Expenditure(expenditure)); err != nil {
+		r.logger.Error("Failed to dual-write added expenditure", "error", err, "id", expenditure.ID)
+	}
+	return nil
+}
+
+// AddExpenditures adds the expenditures in bulk to both storages
+func (r *DualWriteRepository) AddExpenditures(expenditures []*domain.Expenditure) error {
+	if err := domain.AddExpenditures(r.ExpenditureRepository, expenditures); err != nil {
+		return err
+	}
+
+	mapped := make([]*domain.Expenditure, len(expenditures))
+	for i, expenditure := range expenditures {
+		mapped[i] = r.mapping.Expenditure(expenditure)
+	}
+	if err := domain.AddExpenditures(r.secondary, mapped); err != nil {
+		r.logger.Error("Failed to dual-write added expenditures", "error", err, "count", len(expenditures))
+	}
+	return nil
+}
+
+// UpdateExpenditure updates the expenditure in both storages, adding it to the secondary when
+// it was missing there
+func (r *DualWriteRepository) UpdateExpenditure(expenditure *domain.Expenditure) error {
+	if err := r.ExpenditureRepository.UpdateExpenditure(expenditure); err != nil {
+		return err
+	}
+
+	mapped := r.mapping.Expenditure(expenditure)
+	err := r.secondary.UpdateExpenditure(mapped)
+	if errors.Is(err, domain.ErrExpenditureNotFound) {
+		err = r.secondary.AddExpenditure(mapped)
+	}
+	if err != nil {
+		r.logger.Error("Failed to dual-write updated expenditure", "error", err, "id", expenditure.ID)
+	}
+	return nil
+}
+
+// DeleteExpenditure deletes the expenditure from both storages
+func (r *DualWriteRepository) DeleteExpenditure(id string) error {
+	if err := r.ExpenditureRepository.DeleteExpenditure(id); err != nil {
+		return err
+	}
+
+	if err := r.secondary.DeleteExpenditure(id); err != nil && !errors.Is(err, domain.ErrExpenditureNotFound) {
+		r.logger.Error("Failed to dual-write deleted expenditure", "error", err, "id", id)
+	}
+	return nil
+}
+
+// DualWriteCategoryRepository writes the changes to categories to a second storage as well
+type DualWriteCategoryRepository struct {
+	domain.CategoryRepository
+	secondary domain.CategoryRepository
+	mapping   CategoryMapping
+	logger    *slog.Logger
+}
+
+// NewDualWriteCategoryRepository creates a new DualWriteCategoryRepository writing to primary and
+// secondary
+func NewDualWriteCategoryRepository(primary, secondary domain.CategoryRepository, mapping CategoryMapping, logger *slog.Logger) *DualWriteCategoryRepository {
+	return &DualWriteCategoryRepository{
+		CategoryRepository: primary,
+		secondary:          secondary,
+		mapping:            mapping,
+		logger:             logger,
+	}
+}
+
+// AddCategory adds the category to both storages
+func (r *DualWriteCategoryRepository) AddCategory(category *domain.Category) error {
+	if err := r.CategoryRepository.AddCategory(category); err != nil {
+		return err
+	}
+
+	if err := r.secondary.AddCategory(r.category(category)); err != nil {
+		r.logger.Error("Failed to dual-write added category", "error", err, "id", category.ID)
+	}
+	return nil
+}
+
+// UpdateCategory updates the category in both storages
+func (r *DualWriteCategoryRepository) UpdateCategory(category *domain.Category) error {
+	if err := r.CategoryRepository.UpdateCategory(category); err != nil {
+		return err
+	}
+
+	if err := r.secondary.UpdateCategory(r.category(category)); err != nil {
+		r.logger.Error("Failed to dual-write updated category", "error", err, "id", category.ID)
+	}
+	return nil
+}
+
+// ReorderCategories reorders the categories in both storages
+func (r *DualWriteCategoryRepository) ReorderCategories(ids []uuid.UUID) error {
+	if err := r.CategoryRepository.ReorderCategories(ids); err != nil {
+		return err
+	}
+
+	mapped := make([]uuid.UUID, len(ids))
+	for i, id := range ids {
+		mapped[i] = r.mapping.ID(id)
+	}
+	if err := r.secondary.ReorderCategories(mapped); err != nil {
+		r.logger.Error("Failed to dual-write category order", "error", err)
+	}
+	return nil
+}
+
+// MergeCategory merges the category in both storages; dry runs only ask the primary
+func (r *DualWriteCategoryRepository) MergeCategory(source, target uuid.UUID, dryRun bool) (*domain.CategoryMerge, error) {
+	merge, err := r.CategoryRepository.MergeCategory(source, target, dryRun)
+	if err != nil || dryRun {
+		return merge, err
+	}
+
+	if _, err := r.secondary.MergeCategory(r.mapping.ID(source), r.mapping.ID(target), false); err != nil {
+		r.logger.Error("Failed to dual-write category merge", "error", err, "source_id", source, "target_id", target)
+	}
+	return merge, nil
+}
+
+// category returns the category with the IDs the secondary storage uses
+func (r *DualWriteCategoryRepository) category(category *domain.Category) *domain.Category {
+	copied := *category
+	copied.ID = r.mapping.ID(category.ID)
+	copied.ParentID = r.mapping.ID(category.ParentID)
+	return &copied
+}
@@ -0,0 +1,67 @@
+package migration
+
+import (
+	"fmt"
+	"go-expense-tracker/domain"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// CategoryMapping maps the IDs of source categories to the IDs of the target categories with the
+// same name. Categories missing from it have the same ID in both storages
+type CategoryMapping map[uuid.UUID]uuid.UUID
+
+// ID returns the target ID of a source category
+func (m CategoryMapping) ID(id uuid.UUID) uuid.UUID {
+	if mapped, ok := m[id]; ok {
+		return mapped
+	}
+	return id
+}
+
+// Expenditure returns the expenditure as stored in the target, a copy when its category is mapped
+func (m CategoryMapping) Expenditure(expenditure *domain.Expenditure) *domain.Expenditure {
+	mapped := m.ID(expenditure.CategoryId)
+	if mapped == expenditure.CategoryId {
+		return expenditure
+	}
+
+	copied := *expenditure
+	copied.CategoryId = mapped
+	return &copied
+}
+
+// MapCategories matches the categories of source and target by name, ignoring case, when they
+// have different IDs
+func MapCategories(source, target domain.CategoryRepository) (CategoryMapping, error) {
+	categories, err := source.GetAllCategories()
+	if err != nil {
+		return nil, fmt.Errorf("error getting source categories: %w", err)
+	}
+	existing, err := target.GetAllCategories()
+	if err != nil {
+		return nil, fmt.Errorf("error getting target categories: %w", err)
+	}
+	return mapCategories(categories, existing), nil
+}
+
+func mapCategories(source, target []*domain.Category) CategoryMapping {
+	byID := make(map[uuid.UUID]bool, len(target))
+	byName := make(map[string]uuid.UUID, len(target))
+	for _, category := range target {
+		byID[category.ID] = true
+		byName[strings.ToLower(category.Name)] = category.ID
+	}
+
+	mapping := CategoryMapping{}
+	for _, category := range source {
+		if byID[category.ID] {
+			continue
+		}
+		if id, ok := byName[strings.ToLower(category.Name)]; ok {
+			mapping[category.ID] = id
+		}
+	}
+	return mapping
+}
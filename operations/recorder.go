@@ -0,0 +1,226 @@
+// Package operations keeps a short-lived log of the changes made to expenditures so that
+// recent mistakes, such as a fat-fingered delete, can be undone.
+package operations
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Recorder wraps an ExpenditureRepository and records every create, update and delete with
+// snapshots of the expenditure before and after it. Operations are kept in memory only, so
+// they do not survive a restart
+type Recorder struct {
+	domain.ExpenditureWrapper
+	window     time.Duration
+	operations map[string]*domain.Operation
+	logger     *slog.Logger
+	mu         sync.Mutex
+}
+
+// NewRecorder creates a new Recorder around the given repository, allowing undo for window
+func NewRecorder(inner domain.ExpenditureRepository, window time.Duration, logger *slog.Logger) *Recorder {
+	return &Recorder{
+		ExpenditureWrapper: domain.ExpenditureWrapper{ExpenditureRepository: inner},
+		window:             window,
+		operations:         make(map[string]*domain.Operation),
+		logger:             logger,
+	}
+}
+
+// AddExpenditure adds the expenditure and records the creation
+func (r *Recorder) AddExpenditure(expenditure *domain.Expenditure) error {
+	if err := r.ExpenditureRepository.AddExpenditure(expenditure); err != nil {
+		return err
+	}
+
+	r.record(domain.NewOperation(domain.OperationCreate, expenditure.ID, nil, snapshot(expenditure)))
+	return nil
+}
+
+// UpdateExpenditure updates the expenditure and records its previous state
+func (r *Recorder) UpdateExpenditure(expenditure *domain.Expenditure) error {
+	before, err := r.ExpenditureRepository.GetExpenditureByID(expenditure.ID.String())
+	if err != nil {
+		return err
+	}
+	before = snapshot(before)
+
+	if err := r.ExpenditureRepository.UpdateExpenditure(expenditure); err != nil {
+		return err
+	}
+
+	r.record(domain.NewOperation(domain.OperationUpdate, expenditure.ID, before, snapshot(expenditure)))
+	return nil
+}
+
+// DeleteExpenditure deletes the expenditure and records its last state
+func (r *Recorder) DeleteExpenditure(id string) error {
+	before, err := r.ExpenditureRepository.GetExpenditureByID(id)
+	if err != nil {
+		return err
+	}
+	before = snapshot(before)
+
+	if err := r.ExpenditureRepository.DeleteExpenditure(id); err != nil {
+		return err
+	}
+
+	r.record(domain.NewOperation(domain.OperationDelete, before.ID, before, nil))
+	return nil
+}
+
+// AddExpenditures keeps the bulk fast path; bulk imports are not recorded for undo
+func (r *Recorder) AddExpenditures(expenditures []*domain.Expenditure) error {
+	return domain.AddExpenditures(r.ExpenditureRepository, expenditures)
+}
+
+// Get returns a recorded operation
+func (r *Recorder) Get(id string) (*domain.Operation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune()
+	operation, ok := r.operations[id]
+	if !ok {
+		return nil, domain.ErrOperationNotFound
+	}
+	return operation, nil
+}
+
+// Recent returns the recorded operations, newest first
+func (r *Recorder) Recent() []*domain.Operation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune()
+	operations := make([]*domain.Operation, 0, len(r.operations))
+	for _, operation := range r.operations {
+		operations = append(operations, operation)
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].At.After(operations[j].At)
+	})
+	return operations
+}
+
+// Undo reverses an operation: a create is deleted, an update restores the previous state and a
+// delete adds the expenditure back. Only the last operation on an expenditure can be undone, and
+// the same rules hold as for changing it directly: a reconciled expenditure stays as it is,
+// one with refunds cannot be deleted or brought below them, and refunds cannot come back beyond
+// their original or without it, each failing with the error of the domain package
+func (r *Recorder) Undo(id string) (*domain.Operation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	operation, ok := r.operations[id]
+	if !ok {
+		return nil, domain.ErrOperationNotFound
+	}
+	if operation.UndoneAt != nil {
+		return nil, domain.ErrOperationAlreadyUndone
+	}
+	if time.Since(operation.At) > r.window {
+		return nil, domain.ErrOperationUndoExpired
+	}
+
+	for _, other := range r.operations {
+		if other.ExpenditureID == operation.ExpenditureID && other.UndoneAt == nil && other.At.After(operation.At) {
+			return nil, domain.ErrOperationSuperseded
+		}
+	}
+
+	// Reconciliation locks the expenditure as it is now; the storage checks the refunds
+	if operation.Kind != domain.OperationDelete {
+		current, err := r.ExpenditureRepository.GetExpenditureByID(operation.ExpenditureID.String())
+		if err != nil {
+			return nil, err
+		}
+		if current.Reconciled {
+			r.logger.Warn("Reconciled expenditure cannot be undone", "id", id, "expenditure_id", operation.ExpenditureID)
+			return nil, domain.ErrExpenditureReconciled
+		}
+	}
+
+	// The inner repository is used directly, so undoing is not itself recorded
+	var err error
+	switch operation.Kind {
+	case domain.OperationCreate:
+		err = r.ExpenditureRepository.DeleteExpenditure(operation.ExpenditureID.String())
+	case domain.OperationUpdate:
+		err = r.ExpenditureRepository.UpdateExpenditure(snapshot(operation.Before))
+	case domain.OperationDelete:
+		err = r.ExpenditureRepository.AddExpenditure(snapshot(operation.Before))
+	}
+	if err != nil {
+		r.logger.Error("Failed to undo operation", "id", id, "kind", operation.Kind, "error", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	operation.UndoneAt = &now
+	r.logger.Info("Undid operation", "id", id, "kind", operation.Kind, "expenditure_id", operation.ExpenditureID)
+	return operation, nil
+}
+
+// ScrubMember removes personal data from the log when a member is erased: the operations on
+// the erased expenditures are forgotten, so they cannot be brought back by undoing, and the
+// member is removed from the snapshots of the others they recorded. Returns the number of
+// operations changed
+func (r *Recorder) ScrubMember(member uuid.UUID, erased []uuid.UUID) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	scrubbed := 0
+	for id, operation := range r.operations {
+		if slices.Contains(erased, operation.ExpenditureID) {
+			delete(r.operations, id)
+			scrubbed++
+			continue
+		}
+		changed := false
+		for _, state := range []*domain.Expenditure{operation.Before, operation.After} {
+			if state != nil && member != uuid.Nil && state.CreatedBy == member {
+				state.CreatedBy = uuid.Nil
+				changed = true
+			}
+		}
+		if changed {
+			scrubbed++
+		}
+	}
+
+	r.logger.Info("Scrubbed operations of erased member", "member_id", member, "count", scrubbed)
+	return scrubbed
+}
+
+func (r *Recorder) record(operation *domain.Operation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune()
+	r.operations[operation.ID.String()] = operation
+	r.logger.Debug("Recorded operation", "id", operation.ID, "kind", operation.Kind, "expenditure_id", operation.ExpenditureID)
+}
+
+// prune forgets old operations; they are kept for an hour, or the undo window when longer, so
+// clients asking too late learn that the operation expired. The caller holds the lock
+func (r *Recorder) prune() {
+	retention := max(r.window, time.Hour)
+	for id, operation := range r.operations {
+		if time.Since(operation.At) > retention {
+			delete(r.operations, id)
+		}
+	}
+}
+
+// snapshot copies an expenditure so later changes to the stored one do not alter the log
+func snapshot(expenditure *domain.Expenditure) *domain.Expenditure {
+	return expenditure.Clone()
+}
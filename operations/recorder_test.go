@@ -0,0 +1,117 @@
+package operations
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/services"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestUndoKeepsRules(t *testing.T) {
+	storage := services.NewMemoryService(slog.New(slog.DiscardHandler))
+	recorder := NewRecorder(storage, time.Hour, slog.New(slog.DiscardHandler))
+	date := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+
+	// Changes made directly to the storage are not recorded, as if made after the operation
+	newExpenditure := func(amount float64) *domain.Expenditure {
+		expenditure, err := domain.NewExpenditure("Lunch", amount, date, uuid.New())
+		if err != nil {
+			t.Fatalf("creating expenditure: %v", err)
+		}
+		return expenditure
+	}
+	refund := func(original *domain.Expenditure, amount, refunded float64) *domain.Expenditure {
+		refund, err := original.NewRefund(amount, date, "", refunded, date)
+		if err != nil {
+			t.Fatalf("creating refund: %v", err)
+		}
+		return refund
+	}
+	must := func(err error) {
+		if err != nil {
+			t.Fatalf("preparing: %v", err)
+		}
+	}
+	// operationOn returns the one operation recorded on the expenditure
+	operationOn := func(expenditure *domain.Expenditure) string {
+		for _, operation := range recorder.Recent() {
+			if operation.ExpenditureID == expenditure.ID {
+				return operation.ID.String()
+			}
+		}
+		t.Fatalf("no operation on %s", expenditure.ID)
+		return ""
+	}
+
+	tests := []struct {
+		name string
+		// prepare makes an operation and changes the expenditure since, returning the operation
+		prepare func() string
+		wantErr error
+	}{
+		{"update of an expenditure reconciled since", func() string {
+			expenditure := newExpenditure(10)
+			must(storage.AddExpenditure(expenditure))
+			expenditure.Amount = 12
+			must(recorder.UpdateExpenditure(expenditure))
+			id := operationOn(expenditure)
+			expenditure.Reconciled = true
+			must(storage.UpdateExpenditure(expenditure))
+			return id
+		}, domain.ErrExpenditureReconciled},
+		{"create of an expenditure reconciled since", func() string {
+			expenditure := newExpenditure(10)
+			must(recorder.AddExpenditure(expenditure))
+			id := operationOn(expenditure)
+			expenditure.Reconciled = true
+			must(storage.UpdateExpenditure(expenditure))
+			return id
+		}, domain.ErrExpenditureReconciled},
+		{"create of an expenditure refunded since", func() string {
+			expenditure := newExpenditure(10)
+			must(recorder.AddExpenditure(expenditure))
+			id := operationOn(expenditure)
+			must(storage.AddExpenditure(refund(expenditure, 5, 0)))
+			return id
+		}, domain.ErrExpenditureHasRefunds},
+		{"update back below the refunds since", func() string {
+			expenditure := newExpenditure(10)
+			must(storage.AddExpenditure(expenditure))
+			expenditure.Amount = 100
+			must(recorder.UpdateExpenditure(expenditure))
+			id := operationOn(expenditure)
+			must(storage.AddExpenditure(refund(expenditure, 80, 0)))
+			return id
+		}, domain.ErrAmountBelowRefunded},
+		{"delete of a refund beyond the refunds since", func() string {
+			expenditure := newExpenditure(10)
+			must(storage.AddExpenditure(expenditure))
+			first := refund(expenditure, 5, 0)
+			must(storage.AddExpenditure(first))
+			must(recorder.DeleteExpenditure(first.ID.String()))
+			id := operationOn(first)
+			must(storage.AddExpenditure(refund(expenditure, 8, 0)))
+			return id
+		}, domain.ErrRefundExceedsOriginal},
+		{"update", func() string {
+			expenditure := newExpenditure(10)
+			must(storage.AddExpenditure(expenditure))
+			expenditure.Amount = 12
+			must(recorder.UpdateExpenditure(expenditure))
+			return operationOn(expenditure)
+		}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := tt.prepare()
+			if _, err := recorder.Undo(id); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
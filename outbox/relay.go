@@ -0,0 +1,117 @@
+// Package outbox relays the messages a storage writes to its outbox, together with every change
+// to an expenditure, to a subscriber such as a webhook.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"time"
+)
+
+// batchSize is the number of messages read from the outbox at a time
+const batchSize = 100
+
+// maxAttempts is the number of failed deliveries after which a message is given up on
+const maxAttempts = 12
+
+// maxBackoff caps the wait between two attempts to deliver a message
+const maxBackoff = time.Hour
+
+// Subscriber receives the relayed messages; it returns an error when the message should be retried
+type Subscriber interface {
+	DeliverOutboxMessage(message *domain.OutboxMessage) error
+}
+
+// Relay delivers the outbox messages in order. A message is removed from the outbox only once
+// the subscriber accepted it, so a crash in between delivers it again: at least once, and
+// exactly once for subscribers skipping message IDs they have seen
+type Relay struct {
+	outbox     domain.OutboxRepository
+	subscriber Subscriber
+	interval   time.Duration
+	logger     *slog.Logger
+}
+
+// NewRelay creates a new Relay polling the outbox every interval
+func NewRelay(outbox domain.OutboxRepository, subscriber Subscriber, interval time.Duration, logger *slog.Logger) *Relay {
+	return &Relay{
+		outbox:     outbox,
+		subscriber: subscriber,
+		interval:   interval,
+		logger:     logger,
+	}
+}
+
+// Run delivers the pending messages now and then every interval until the context is cancelled
+func (r *Relay) Run(ctx context.Context) {
+	r.logger.Info("Starting outbox relay", "interval", r.interval.String())
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.relay(ctx, time.Now()); err != nil {
+			r.logger.Error("Failed to relay outbox messages", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Stopping outbox relay")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay delivers pending messages until the outbox is empty or a message fails. Later messages
+// wait for a failed one, so subscribers see the changes to an expenditure in order
+func (r *Relay) relay(ctx context.Context, now time.Time) error {
+	for ctx.Err() == nil {
+		messages, err := r.outbox.GetPendingOutboxMessages(batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			if message.NextAttemptAt.After(now) {
+				return nil
+			}
+
+			if err := r.subscriber.DeliverOutboxMessage(message); err != nil {
+				return r.fail(message, err, now)
+			}
+
+			// A message another replica relayed and removed in between was delivered all the same
+			if err := r.outbox.MarkOutboxMessageDelivered(message.ID); err != nil && !errors.Is(err, domain.ErrOutboxMessageNotFound) {
+				return err
+			}
+			r.logger.Info("Relayed outbox message", "id", message.ID, "topic", message.Topic, "expenditure_id", message.ExpenditureID)
+		}
+
+		if len(messages) < batchSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+// fail schedules the next attempt to deliver a message with exponential backoff, or gives up on
+// it after maxAttempts so it no longer holds up the messages behind it
+func (r *Relay) fail(message *domain.OutboxMessage, cause error, now time.Time) error {
+	attempts := message.Attempts + 1
+	retryAt := time.Time{}
+	if attempts >= maxAttempts {
+		r.logger.Error("Giving up on outbox message", "id", message.ID, "topic", message.Topic, "attempts", attempts, "error", cause)
+	} else {
+		backoff := min(r.interval<<attempts, maxBackoff)
+		retryAt = now.Add(backoff)
+		r.logger.Warn("Failed to deliver outbox message", "id", message.ID, "topic", message.Topic, "attempts", attempts, "retry_in", backoff.String(), "error", cause)
+	}
+
+	if err := r.outbox.MarkOutboxMessageFailed(message.ID, cause.Error(), retryAt); err != nil && !errors.Is(err, domain.ErrOutboxMessageNotFound) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package outbox
+
+import "go-expense-tracker/domain"
+
+// Subscribers relays every message to several subscribers in turn, e.g. a webhook and an MQTT
+// broker. A message failing for one subscriber is retried for all of them, so the others see it
+// again and should skip IDs they already handled
+type Subscribers []Subscriber
+
+// DeliverOutboxMessage delivers the message to every subscriber, stopping at the first failure
+func (s Subscribers) DeliverOutboxMessage(message *domain.OutboxMessage) error {
+	for _, subscriber := range s {
+		if err := subscriber.DeliverOutboxMessage(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Filtered relays to a subscriber only the messages about changes passing its filter, for a
+// subscriber among others with a filter of its own
+type Filtered struct {
+	Subscriber Subscriber
+	Filter     domain.EventFilter
+}
+
+// DeliverOutboxMessage delivers the message when it passes the filter and accepts it otherwise
+func (f Filtered) DeliverOutboxMessage(message *domain.OutboxMessage) error {
+	if !f.Filter.Matches(message.Expenditure) {
+		return nil
+	}
+	return f.Subscriber.DeliverOutboxMessage(message)
+}
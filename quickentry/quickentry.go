@@ -0,0 +1,245 @@
+// Package quickentry parses short free-text expenditures such as "lunch 12.50 yesterday #food"
+// sent through the quick-add endpoint and chat integrations.
+package quickentry
+
+import (
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrNoAmount = errors.New("no amount found, try something like \"coffee 3.50\"")
+var ErrNoDescription = errors.New("no description found, try something like \"coffee 3.50\"")
+var ErrNoDefaultCategory = errors.New("no default category configured")
+
+// DefaultCategoryName is used when no category is detected and no default category ID is given
+const DefaultCategoryName = "Miscellaneous"
+
+// Entry is a quick expenditure parsed from a message such as "lunch 12.50 yesterday #food"
+type Entry struct {
+	Description string
+	Amount      float64
+	Date        time.Time
+	Hashtags    []string
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// Parse extracts the description, amount, date and #hashtags from a message. Relative dates
+// ("today", "yesterday", "3 days ago", weekday names) are resolved against now
+func Parse(text string, now time.Time) (*Entry, error) {
+	e := Entry{Date: now}
+	var words []string
+	amountFound := false
+	dateFound := false
+
+	fields := strings.Fields(text)
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+
+		if strings.HasPrefix(field, "#") && len(field) > 1 {
+			e.Hashtags = append(e.Hashtags, strings.TrimPrefix(field, "#"))
+			continue
+		}
+
+		if !dateFound {
+			if date, consumed, ok := parseDate(fields[i:], now); ok {
+				e.Date = date
+				dateFound = true
+				i += consumed - 1
+				continue
+			}
+		}
+
+		if !amountFound {
+			if amount, ok := ParseAmount(field); ok {
+				e.Amount = amount
+				amountFound = true
+				continue
+			}
+		}
+
+		words = append(words, field)
+	}
+
+	if !amountFound {
+		return nil, ErrNoAmount
+	}
+
+	e.Description = strings.Join(words, " ")
+	if e.Description == "" {
+		return nil, ErrNoDescription
+	}
+
+	return &e, nil
+}
+
+// parseDate recognizes a date at the start of fields and returns how many fields it used
+func parseDate(fields []string, now time.Time) (time.Time, int, bool) {
+	word := strings.ToLower(fields[0])
+
+	switch word {
+	case "today":
+		return now, 1, true
+	case "yesterday":
+		return now.AddDate(0, 0, -1), 1, true
+	}
+
+	// "3 days ago"
+	if len(fields) >= 3 && strings.EqualFold(fields[2], "ago") {
+		if n, err := strconv.Atoi(fields[0]); err == nil && n >= 0 {
+			switch strings.ToLower(fields[1]) {
+			case "day", "days":
+				return now.AddDate(0, 0, -n), 3, true
+			case "week", "weeks":
+				return now.AddDate(0, 0, -7*n), 3, true
+			}
+		}
+	}
+
+	// "last monday" or "monday" both mean the most recent one, today included
+	consumed := 1
+	if word == "last" && len(fields) >= 2 {
+		word = strings.ToLower(fields[1])
+		consumed = 2
+	}
+	if weekday, ok := weekdays[word]; ok {
+		daysBack := (int(now.Weekday()) - int(weekday) + 7) % 7
+		return now.AddDate(0, 0, -daysBack), consumed, true
+	}
+
+	if date, err := time.ParseInLocation("2006-01-02", fields[0], now.Location()); err == nil {
+		return date, 1, true
+	}
+
+	return time.Time{}, 0, false
+}
+
+// ParseAmount accepts amounts like 3.50, 3,50 or €3.50
+func ParseAmount(s string) (float64, bool) {
+	s = strings.TrimLeft(s, "$€£")
+	s = strings.Replace(s, ",", ".", 1)
+
+	// Reject words ParseFloat would otherwise accept, such as "inf" or "nan"
+	if s == "" || !strings.ContainsAny(s[:1], "0123456789.") {
+		return 0, false
+	}
+
+	amount, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return amount, true
+}
+
+// Categorize picks the category of an entry and returns the remaining hashtags as tags.
+// The categorization rules come first: the first one matching the description and setting an
+// active category wins, and the tags of all matching ones are added. Then the first hashtag
+// naming a category wins, then a description word naming one, then defaultID, then
+// DefaultCategoryName; archived categories are never picked by name. categories may be nil
+// when the storage has no category support, in which case only rules and defaultID can be
+// used, and rules when it keeps no categorization rules
+func Categorize(categories domain.CategoryRepository, rules domain.CategorizationRuleRepository, e *Entry, defaultID uuid.UUID) (uuid.UUID, []string, error) {
+	match := &domain.RuleMatch{}
+	if rules != nil {
+		all, err := rules.GetAllRules()
+		if err != nil {
+			return uuid.Nil, nil, fmt.Errorf("error getting categorization rules: %w", err)
+		}
+		match = domain.MatchRules(all, e.Description)
+	}
+	tags := match.Tags
+
+	if categories == nil {
+		categoryID := match.CategoryId
+		if categoryID == uuid.Nil {
+			categoryID = defaultID
+		}
+		if categoryID == uuid.Nil {
+			return uuid.Nil, nil, ErrNoDefaultCategory
+		}
+		return categoryID, append(tags, e.Hashtags...), nil
+	}
+
+	all, err := categories.GetAllCategories()
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("error getting categories: %w", err)
+	}
+	all = domain.ActiveCategories(all)
+
+	categoryID := uuid.Nil
+	if match.CategoryId != uuid.Nil && slices.ContainsFunc(all, func(c *domain.Category) bool { return c.ID == match.CategoryId }) {
+		categoryID = match.CategoryId
+	}
+
+	for _, hashtag := range e.Hashtags {
+		if categoryID == uuid.Nil {
+			if category := findCategory(all, hashtag, true); category != nil {
+				categoryID = category.ID
+				continue
+			}
+		}
+		tags = append(tags, hashtag)
+	}
+
+	if categoryID == uuid.Nil {
+		for _, word := range strings.Fields(e.Description) {
+			if category := findCategory(all, word, true); category != nil {
+				categoryID = category.ID
+				break
+			}
+		}
+	}
+
+	if categoryID == uuid.Nil {
+		categoryID = defaultID
+	}
+
+	if categoryID == uuid.Nil {
+		category := findCategory(all, DefaultCategoryName, false)
+		if category == nil {
+			return uuid.Nil, nil, ErrNoDefaultCategory
+		}
+		categoryID = category.ID
+	}
+
+	return categoryID, tags, nil
+}
+
+// findCategory matches a category by its full name (ignoring case and spaces) or, when
+// matchWords is set, by any word of its name such as "food" for "Food & Dining"
+func findCategory(categories []*domain.Category, name string, matchWords bool) *domain.Category {
+	for _, category := range categories {
+		if strings.EqualFold(category.Name, name) || strings.EqualFold(strings.ReplaceAll(category.Name, " ", ""), name) {
+			return category
+		}
+	}
+
+	if !matchWords || len(name) < 3 {
+		return nil
+	}
+
+	for _, category := range categories {
+		for _, word := range strings.Fields(category.Name) {
+			if len(word) >= 3 && strings.EqualFold(word, name) {
+				return category
+			}
+		}
+	}
+
+	return nil
+}
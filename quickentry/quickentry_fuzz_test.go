@@ -0,0 +1,50 @@
+package quickentry_test
+
+import (
+	"go-expense-tracker/quickentry"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func FuzzParse(f *testing.F) {
+	for _, text := range []string{
+		"lunch 12.50 yesterday #food",
+		"coffee 3,50 3 days ago",
+		"taxi 1.234,56 monday #work #travel",
+		"12 apples",
+		"#only #hashtags",
+		"rent -900 today",
+		"",
+	} {
+		f.Add(text)
+	}
+	now := time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC)
+
+	f.Fuzz(func(t *testing.T, text string) {
+		entry, err := quickentry.Parse(text, now)
+		if err != nil {
+			return
+		}
+		// Zero amounts and future dates are parsed; creating the expenditure turns them away
+		if math.IsNaN(entry.Amount) || math.IsInf(entry.Amount, 0) || entry.Amount < 0 {
+			t.Errorf("Parse(%q) amount = %v, want a finite number of at least 0", text, entry.Amount)
+		}
+		if strings.TrimSpace(entry.Description) == "" {
+			t.Errorf("Parse(%q) has no description", text)
+		}
+	})
+}
+
+func FuzzParseAmount(f *testing.F) {
+	for _, s := range []string{"12.50", "3,50", "1.234,56", "1,234.56", "€4", "-3", "NaN", "1e400"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		amount, ok := quickentry.ParseAmount(s)
+		if ok && (math.IsNaN(amount) || math.IsInf(amount, 0)) {
+			t.Errorf("ParseAmount(%q) = %v, want a finite number", s, amount)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package quickentry_test
+
+import (
+	"go-expense-tracker/domain"
+	"go-expense-tracker/quickentry"
+	"go-expense-tracker/services"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCategorize(t *testing.T) {
+	storage := services.NewMemoryService(slog.New(slog.DiscardHandler))
+	// The storage starts with the default categories
+	all, err := storage.GetAllCategories()
+	if err != nil {
+		t.Fatalf("getting categories: %v", err)
+	}
+	named := func(name string) *domain.Category {
+		for _, c := range all {
+			if c.Name == name {
+				return c
+			}
+		}
+		t.Fatalf("no default category %q", name)
+		return nil
+	}
+	food, transport, misc := named("Food & Dining"), named("Transportation"), named(quickentry.DefaultCategoryName)
+	archived, err := domain.NewCategory("Legacy", "#F28E2B")
+	if err != nil {
+		t.Fatalf("creating category: %v", err)
+	}
+	archived.Active = false
+	if err := storage.AddCategory(archived); err != nil {
+		t.Fatalf("adding category: %v", err)
+	}
+
+	rule := func(contains string, categoryID uuid.UUID, tags ...string) {
+		r, err := domain.NewCategorizationRule(contains, contains, categoryID, tags, uuid.Nil, uuid.Nil)
+		if err != nil {
+			t.Fatalf("creating rule: %v", err)
+		}
+		if err := storage.AddRule(r); err != nil {
+			t.Fatalf("adding rule: %v", err)
+		}
+	}
+	rule("uber", transport.ID, "rideshare")
+	rule("uber eats", food.ID, "delivery")
+	rule("legacy", archived.ID)
+	rule("lunch", uuid.Nil, "meal")
+	defaultID := uuid.New()
+
+	tests := []struct {
+		name        string
+		rules       domain.CategorizationRuleRepository
+		description string
+		hashtags    []string
+		defaultID   uuid.UUID
+		want        uuid.UUID
+		wantTags    []string
+	}{
+		{"rule", storage, "uber to airport", nil, uuid.Nil, transport.ID, []string{"rideshare"}},
+		{"first matching rule wins the category", storage, "Uber Eats food", nil, uuid.Nil, transport.ID, []string{"delivery", "rideshare"}},
+		{"rule before hashtag", storage, "uber home", []string{"food"}, uuid.Nil, transport.ID, []string{"rideshare", "food"}},
+		{"rule setting only tags", storage, "lunch", []string{"food", "work"}, uuid.Nil, food.ID, []string{"meal", "work"}},
+		{"rule with an archived category", storage, "legacy fees", nil, defaultID, defaultID, nil},
+		{"no rules", nil, "uber to airport", []string{"food"}, uuid.Nil, food.ID, nil},
+		{"no matching rule", storage, "food market", nil, uuid.Nil, food.ID, nil},
+		{"fallback", storage, "something", nil, uuid.Nil, misc.ID, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := &quickentry.Entry{Description: tt.description, Amount: 1, Hashtags: tt.hashtags}
+			got, tags, err := quickentry.Categorize(storage, tt.rules, entry, tt.defaultID)
+			if err != nil {
+				t.Fatalf("categorizing: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("category = %s, want %s", got, tt.want)
+			}
+			if !slices.Equal(tags, tt.wantTags) {
+				t.Errorf("tags = %q, want %q", tags, tt.wantTags)
+			}
+		})
+	}
+
+	t.Run("without categories", func(t *testing.T) {
+		got, tags, err := quickentry.Categorize(nil, storage, &quickentry.Entry{Description: "uber", Hashtags: []string{"work"}}, defaultID)
+		if err != nil {
+			t.Fatalf("categorizing: %v", err)
+		}
+		if got != transport.ID || !slices.Equal(tags, []string{"rideshare", "work"}) {
+			t.Errorf("got %s with tags %q, want %s with [rideshare work]", got, tags, transport.ID)
+		}
+	})
+}
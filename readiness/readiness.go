@@ -0,0 +1,47 @@
+// Package readiness lets the server answer before it is set up, e.g. while the database is
+// still starting: requests are turned away with 503 Service Unavailable until the API is
+// ready, except the probes orchestrators use to tell when that is.
+package readiness
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// Path is the readiness probe, answered with 200 once the API is ready and 503 before
+const Path = "/readyz"
+
+// Gate holds requests back until Open is called. It is safe for concurrent use
+type Gate struct {
+	probes  http.Handler // Serves Path and /version before the API is ready
+	handler atomic.Pointer[http.Handler]
+}
+
+// NewGate creates a closed Gate serving the probes with probes until it is opened
+func NewGate(probes http.Handler) *Gate {
+	return &Gate{probes: probes}
+}
+
+// Ready reports whether the API is set up
+func (g *Gate) Ready() bool {
+	return g.handler.Load() != nil
+}
+
+// Open lets requests through to handler
+func (g *Gate) Open(handler http.Handler) {
+	g.handler.Store(&handler)
+}
+
+func (g *Gate) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if handler := g.handler.Load(); handler != nil {
+		(*handler).ServeHTTP(w, r)
+		return
+	}
+	if r.URL.Path == Path || r.URL.Path == "/version" {
+		g.probes.ServeHTTP(w, r)
+		return
+	}
+
+	w.Header().Set("Retry-After", "5")
+	http.Error(w, "The API is starting, try again later", http.StatusServiceUnavailable)
+}
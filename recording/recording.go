@@ -0,0 +1,223 @@
+// Package recording keeps the requests clients send and the responses they got, with
+// credentials and personal secrets redacted, so a bug a client reports can be reproduced by
+// replaying its requests against another instance, e.g. one running a fix branch.
+package recording
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Path is where the recorded exchanges are downloaded; requests to it are not recorded
+const Path = "/admin/recordings"
+
+// Redacted replaces the values of secret headers, query parameters and body fields
+const Redacted = "[REDACTED]"
+
+// Exchange is a request and the response it got
+type Exchange struct {
+	ID              uuid.UUID           `json:"id"`
+	Time            time.Time           `json:"time"`
+	Method          string              `json:"method"`
+	Target          string              `json:"target"` // Path and query
+	RequestHeaders  map[string][]string `json:"request_headers"`
+	RequestBody     string              `json:"request_body,omitempty"`
+	Status          int                 `json:"status"`
+	ResponseHeaders map[string][]string `json:"response_headers"`
+	ResponseBody    string              `json:"response_body,omitempty"`
+	DurationMS      int64               `json:"duration_ms"`
+	// Bodies exceeding the size limit are cut off
+	RequestTruncated  bool `json:"request_truncated,omitempty"`
+	ResponseTruncated bool `json:"response_truncated,omitempty"`
+}
+
+// Options configure a Recorder
+type Options struct {
+	Size    int    // Exchanges kept in memory, the oldest are dropped first
+	File    string // Appends every exchange as a JSON line when set
+	MaxBody int    // Bytes of a body kept, the rest is cut off
+}
+
+// Recorder records the exchanges passing through its middleware. It is safe for concurrent use
+type Recorder struct {
+	options Options
+	mu      sync.Mutex
+	ring    []Exchange
+	next    int
+	full    bool
+	file    *os.File
+}
+
+// NewRecorder creates a new Recorder, opening the file of the options for appending
+func NewRecorder(options Options) (*Recorder, error) {
+	if options.Size <= 0 {
+		return nil, fmt.Errorf("invalid recording size %d, expected a positive number", options.Size)
+	}
+	r := &Recorder{options: options, ring: make([]Exchange, options.Size)}
+	if options.File != "" {
+		file, err := os.OpenFile(options.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open recording file: %w", err)
+		}
+		r.file = file
+	}
+	return r, nil
+}
+
+// Middleware records the exchanges of next, except downloads of the recordings
+func (r *Recorder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == Path {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		exchange := Exchange{
+			ID:             uuid.New(),
+			Time:           time.Now().UTC(),
+			Method:         req.Method,
+			Target:         RedactTarget(req.URL.RequestURI()),
+			RequestHeaders: RedactHeaders(req.Header),
+		}
+		if req.Body != nil && req.Body != http.NoBody {
+			// Only what is kept is read up front, one byte past the limit for keep to notice the
+			// body was cut off; the handler reads it again followed by the rest
+			var reader io.Reader = req.Body
+			if r.options.MaxBody > 0 {
+				reader = io.LimitReader(req.Body, int64(r.options.MaxBody)+1)
+			}
+			body, err := io.ReadAll(reader)
+			req.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(body), req.Body), req.Body}
+			if err == nil {
+				exchange.RequestBody, exchange.RequestTruncated = r.keep(body)
+			}
+		}
+
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK, limit: r.options.MaxBody}
+		next.ServeHTTP(rw, req)
+
+		exchange.Status = rw.status
+		exchange.ResponseHeaders = RedactHeaders(w.Header())
+		exchange.ResponseBody, exchange.ResponseTruncated = r.keep(rw.body.Bytes())
+		exchange.DurationMS = time.Since(exchange.Time).Milliseconds()
+		r.add(exchange)
+	})
+}
+
+// keep redacts a body and cuts it to the size limit
+func (r *Recorder) keep(body []byte) (string, bool) {
+	body = RedactBody(body)
+	if r.options.MaxBody > 0 && len(body) > r.options.MaxBody {
+		return string(body[:r.options.MaxBody]), true
+	}
+	return string(body), false
+}
+
+func (r *Recorder) add(exchange Exchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ring[r.next] = exchange
+	r.next = (r.next + 1) % len(r.ring)
+	if r.next == 0 {
+		r.full = true
+	}
+	if r.file != nil {
+		line, err := json.Marshal(exchange)
+		if err == nil {
+			r.file.Write(append(line, '\n'))
+		}
+	}
+}
+
+// Exchanges returns the exchanges kept in memory, oldest first
+func (r *Recorder) Exchanges() []Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]Exchange{}, r.ring[:r.next]...)
+	}
+	return append(append([]Exchange{}, r.ring[r.next:]...), r.ring[:r.next]...)
+}
+
+// Close closes the recording file
+func (r *Recorder) Close() error {
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Close()
+}
+
+// Write writes exchanges as JSON lines, the format of the recording file
+func Write(w io.Writer, exchanges []Exchange) error {
+	encoder := json.NewEncoder(w)
+	for _, exchange := range exchanges {
+		if err := encoder.Encode(exchange); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Read reads exchanges written as JSON lines
+func Read(r io.Reader) ([]Exchange, error) {
+	var exchanges []Exchange
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var exchange Exchange
+		if err := json.Unmarshal(scanner.Bytes(), &exchange); err != nil {
+			return nil, fmt.Errorf("invalid exchange on line %d: %w", line, err)
+		}
+		exchanges = append(exchanges, exchange)
+	}
+	return exchanges, scanner.Err()
+}
+
+// responseWriter passes the response on while keeping a copy of its body up to the limit
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	limit       int
+	body        bytes.Buffer
+}
+
+// Unwrap exposes the wrapped ResponseWriter, so handlers can flush streamed responses
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	// One byte past the limit is kept, for keep to notice the body was cut off
+	if room := w.limit + 1 - w.body.Len(); w.limit <= 0 || room >= len(p) {
+		w.body.Write(p)
+	} else if room > 0 {
+		w.body.Write(p[:room])
+	}
+	return w.ResponseWriter.Write(p)
+}
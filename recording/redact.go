@@ -0,0 +1,84 @@
+package recording
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// secretWords mark header, query parameter and field names whose values are redacted
+var secretWords = []string{"authorization", "cookie", "password", "passcode", "secret", "token", "signature", "apikey", "api_key", "api-key", "iban", "card_number", "cardnumber"}
+
+// secretField matches a JSON field with a secret name and its value; a string value cut off by
+// the size limit is matched up to the end of the body
+var secretField = regexp.MustCompile(`("[^"]*(?i:` + strings.Join(quoteAll(secretWords), "|") + `)[^"]*"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\]\s]+)`)
+
+// secretPath matches the part of a path that is a secret itself, the token of an invitation
+// being accepted
+var secretPath = regexp.MustCompile(`(/invitations/)[^/?"\s\\]+(/accept)`)
+
+// signedParam matches the signature and expiry of a signed URL, and other secret query
+// parameters, in a URL kept in a JSON value, where & may be escaped as \u0026
+var signedParam = regexp.MustCompile(`((?:[?&]|\\u0026)[\w.-]*(?i:expires|` + strings.Join(quoteAll(secretWords), "|") + `)[\w.-]*=)[^&"\s\\]*`)
+
+func quoteAll(words []string) []string {
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = regexp.QuoteMeta(word)
+	}
+	return quoted
+}
+
+// isSecret reports whether a header, query parameter or field name holds a secret
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, word := range secretWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactHeaders returns a copy of headers with the values of secret ones redacted
+func RedactHeaders(headers http.Header) map[string][]string {
+	redacted := make(map[string][]string, len(headers))
+	for name, values := range headers {
+		if isSecret(name) {
+			values = []string{Redacted}
+		}
+		redacted[name] = append([]string{}, values...)
+	}
+	return redacted
+}
+
+// RedactTarget redacts the values of secret query parameters of a path and query, such as the
+// token of the calendar feed and the signature of shared links, and the invitation tokens of
+// paths
+func RedactTarget(target string) string {
+	path, rawQuery, ok := strings.Cut(target, "?")
+	path = secretPath.ReplaceAllString(path, "${1}"+Redacted+"${2}")
+	if !ok {
+		return path
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return path + "?" + Redacted
+	}
+	for name := range query {
+		if isSecret(name) {
+			query[name] = []string{Redacted}
+		}
+	}
+	return path + "?" + query.Encode()
+}
+
+// RedactBody redacts the values of the secret fields of a JSON body, and the secrets of the
+// links in its values, such as signed download and share links and invitation links; other
+// bodies are kept as they are
+func RedactBody(body []byte) []byte {
+	body = secretField.ReplaceAll(body, []byte(`${1}"`+Redacted+`"`))
+	body = signedParam.ReplaceAll(body, []byte("${1}"+Redacted))
+	return secretPath.ReplaceAll(body, []byte("${1}"+Redacted+"${2}"))
+}
@@ -0,0 +1,121 @@
+package recording
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// skippedHeaders are not replayed: the client sets them for the request it sends
+var skippedHeaders = []string{"Content-Length", "Connection", "Accept-Encoding", "Keep-Alive", "Transfer-Encoding", "Upgrade"}
+
+// Result is the outcome of replaying an exchange
+type Result struct {
+	Exchange Exchange
+	Status   int // Zero when the request failed or was skipped
+	Body     string
+	Duration time.Duration
+	Skipped  string // Why the exchange was not replayed
+	Err      error
+}
+
+// Changed reports whether the replayed request was answered with another status than recorded
+func (r Result) Changed() bool {
+	return r.Skipped == "" && (r.Err != nil || r.Status != r.Exchange.Status)
+}
+
+// Replay sends the requests of exchanges to the instance at baseURL, one after the other in
+// their recorded order. Redacted headers are left out unless headers replaces them, e.g. with
+// a token valid on that instance; exchanges whose request body was cut off, or whose body,
+// path or query holds redacted values, are skipped
+func Replay(ctx context.Context, client *http.Client, baseURL string, exchanges []Exchange, headers http.Header) []Result {
+	base := strings.TrimSuffix(baseURL, "/")
+	results := make([]Result, 0, len(exchanges))
+	for _, exchange := range exchanges {
+		if ctx.Err() != nil {
+			break
+		}
+		results = append(results, replay(ctx, client, base, exchange, headers))
+	}
+	return results
+}
+
+func replay(ctx context.Context, client *http.Client, base string, exchange Exchange, headers http.Header) Result {
+	result := Result{Exchange: exchange}
+	if exchange.RequestTruncated {
+		result.Skipped = "request body was cut off"
+		return result
+	}
+	if strings.Contains(exchange.RequestBody, Redacted) {
+		result.Skipped = "request body holds redacted values"
+		return result
+	}
+	if strings.Contains(exchange.Target, url.QueryEscape(Redacted)) {
+		result.Skipped = "query holds redacted values"
+		return result
+	}
+	if strings.Contains(exchange.Target, Redacted) {
+		result.Skipped = "path holds redacted values"
+		return result
+	}
+
+	req, err := http.NewRequestWithContext(ctx, exchange.Method, base+exchange.Target, strings.NewReader(exchange.RequestBody))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	for name, values := range exchange.RequestHeaders {
+		if isSkipped(name) || (len(values) == 1 && values[0] == Redacted) {
+			continue
+		}
+		req.Header[name] = values
+	}
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	result.Duration = time.Since(start)
+	result.Status = resp.StatusCode
+	result.Body = string(body)
+	result.Err = err
+	return result
+}
+
+func isSkipped(name string) bool {
+	for _, skipped := range skippedHeaders {
+		if strings.EqualFold(name, skipped) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteResults prints a line per replayed exchange, with the status recorded and the one replayed
+func WriteResults(w io.Writer, results []Result) {
+	fmt.Fprintf(w, "%-36s %-7s %8s %8s %10s  %s\n", "id", "method", "recorded", "replayed", "duration", "target")
+	for _, result := range results {
+		replayed := fmt.Sprint(result.Status)
+		switch {
+		case result.Skipped != "":
+			replayed = "skipped"
+		case result.Err != nil:
+			replayed = "error"
+		case result.Changed():
+			replayed += " !"
+		}
+		fmt.Fprintf(w, "%-36s %-7s %8d %8s %10v  %s\n", result.Exchange.ID, result.Exchange.Method, result.Exchange.Status,
+			replayed, result.Duration.Round(time.Microsecond), result.Exchange.Target)
+	}
+}
@@ -0,0 +1,132 @@
+package recurring
+
+import (
+	"go-expense-tracker/domain"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// subscriptionNamespace derives the IDs of detected subscriptions
+var subscriptionNamespace = uuid.MustParse("9f1c3f5e-1b5a-4d0e-8c47-3e0f2b7a6d10")
+
+// cadence is the range of days between charges of a frequency, allowing for short months and
+// charges booked a few days late
+type cadence struct {
+	frequency  domain.RecurringFrequency
+	minDays    int
+	maxDays    int
+	minCharges int     // Charges needed before a cadence is trusted
+	perMonth   float64 // Charges in an average month
+}
+
+var cadences = []cadence{
+	{frequency: domain.RecurringWeekly, minDays: 6, maxDays: 8, minCharges: 4, perMonth: 52.0 / 12},
+	{frequency: domain.RecurringMonthly, minDays: 27, maxDays: 34, minCharges: 3, perMonth: 1},
+	{frequency: domain.RecurringYearly, minDays: 358, maxDays: 373, minCharges: 2, perMonth: 1.0 / 12},
+}
+
+// DetectSubscriptions finds charges of the same amount at the same merchant repeating on a
+// weekly, monthly or yearly cadence, most expensive per month first. Subscriptions that missed
+// two charges by now are over and left out, as are the ones managed already by a recurring
+// expenditure of the same description and amount
+func DetectSubscriptions(expenditures []*domain.Expenditure, managed []*domain.RecurringExpenditure, now time.Time) []*domain.DetectedSubscription {
+	type charge struct {
+		merchant string
+		cents    int64
+	}
+	groups := make(map[charge][]*domain.Expenditure)
+	for _, expenditure := range expenditures {
+		if expenditure.IsRefund() || expenditure.IsCancelled() || expenditure.IsPlanned(now) {
+			continue
+		}
+		merchant := expenditure.MerchantId.String()
+		if expenditure.MerchantId == uuid.Nil {
+			merchant = domain.NormalizeMerchantName(expenditure.Description)
+		}
+		if merchant == "" {
+			continue
+		}
+		key := charge{merchant: merchant, cents: cents(expenditure.Amount)}
+		groups[key] = append(groups[key], expenditure)
+	}
+
+	isManaged := make(map[charge]bool, len(managed))
+	for _, recurring := range managed {
+		isManaged[charge{merchant: domain.NormalizeMerchantName(recurring.Description), cents: cents(recurring.Amount)}] = true
+	}
+
+	detected := []*domain.DetectedSubscription{}
+	for key, charges := range groups {
+		sort.Slice(charges, func(i, j int) bool {
+			return charges[i].Date.Before(charges[j].Date)
+		})
+		latest := charges[len(charges)-1]
+		if isManaged[charge{merchant: domain.NormalizeMerchantName(latest.Description), cents: key.cents}] {
+			continue
+		}
+
+		cadence, ok := cadenceOf(charges)
+		if !ok {
+			continue
+		}
+
+		last := domain.SpendingDay(latest.Date)
+		next := cadence.frequency.After(last)
+		if domain.SpendingDay(now).After(cadence.frequency.After(next)) {
+			continue
+		}
+
+		subscription := &domain.DetectedSubscription{
+			ID:          uuid.NewSHA1(subscriptionNamespace, []byte(key.merchant+"/"+strconv.FormatInt(key.cents, 10))),
+			Description: latest.Description,
+			MerchantId:  latest.MerchantId,
+			Amount:      latest.Amount,
+			CategoryId:  latest.CategoryId,
+			Frequency:   cadence.frequency,
+			Charges:     len(charges),
+			FirstDate:   domain.SpendingDay(charges[0].Date),
+			LastDate:    last,
+			NextDate:    next,
+			MonthlyCost: math.Round(latest.Amount*cadence.perMonth*100) / 100,
+		}
+		for _, charge := range charges {
+			subscription.ExpenditureIDs = append(subscription.ExpenditureIDs, charge.ID)
+		}
+		detected = append(detected, subscription)
+	}
+
+	sort.Slice(detected, func(i, j int) bool {
+		if detected[i].MonthlyCost != detected[j].MonthlyCost {
+			return detected[i].MonthlyCost > detected[j].MonthlyCost
+		}
+		return detected[i].Description < detected[j].Description
+	})
+	return detected
+}
+
+// cadenceOf returns the cadence every interval between the charges, oldest first, falls in
+func cadenceOf(charges []*domain.Expenditure) (cadence, bool) {
+	for _, cadence := range cadences {
+		if len(charges) < cadence.minCharges {
+			continue
+		}
+
+		regular := true
+		for i := 1; i < len(charges) && regular; i++ {
+			days := int(math.Round(domain.SpendingDay(charges[i].Date).Sub(domain.SpendingDay(charges[i-1].Date)).Hours() / 24))
+			regular = days >= cadence.minDays && days <= cadence.maxDays
+		}
+		if regular {
+			return cadence, true
+		}
+	}
+	return cadence{}, false
+}
+
+func cents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
@@ -0,0 +1,130 @@
+// Package recurring generates the expenditures of recurring payments, such as rent or a gym
+// membership, on each of their occurrences.
+package recurring
+
+import (
+	"context"
+	"go-expense-tracker/app"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Generator periodically saves an expenditure for every occurrence of a recurring expenditure
+// that has come due, through the expenditure service so the usual validation applies
+type Generator struct {
+	recurring    domain.RecurringRepository
+	expenditures *app.ExpenditureService
+	interval     time.Duration
+	logger       *slog.Logger
+	mu           sync.Mutex // Serializes scheduled runs and changes through the API
+}
+
+// NewGenerator creates a new Generator checking for due occurrences every interval
+func NewGenerator(recurring domain.RecurringRepository, expenditures *app.ExpenditureService, interval time.Duration, logger *slog.Logger) *Generator {
+	return &Generator{
+		recurring:    recurring,
+		expenditures: expenditures,
+		interval:     interval,
+		logger:       logger,
+	}
+}
+
+// Run generates the due occurrences every interval until the context is cancelled
+func (g *Generator) Run(ctx context.Context) {
+	g.logger.Info("Starting recurring expenditure scheduler", "interval", g.interval.String())
+
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		g.GenerateAll(time.Now())
+
+		select {
+		case <-ctx.Done():
+			g.logger.Info("Stopping recurring expenditure scheduler")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GenerateAll saves the expenditures of every occurrence due at now, logging failures per
+// recurring expenditure
+func (g *Generator) GenerateAll(now time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	all, err := g.recurring.GetAllRecurring()
+	if err != nil {
+		g.logger.Error("Failed to get recurring expenditures", "error", err)
+		return
+	}
+
+	for _, recurring := range all {
+		if err := g.generate(recurring, now); err != nil {
+			g.logger.Error("Failed to generate recurring expenditure", "error", err, "id", recurring.ID)
+		}
+	}
+}
+
+// Change applies a change to a recurring expenditure and saves it, so it does not interleave
+// with a scheduled run
+func (g *Generator) Change(id string, change func(recurring *domain.RecurringExpenditure) error) (*domain.RecurringExpenditure, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	recurring, err := g.recurring.GetRecurringByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := change(recurring); err != nil {
+		return nil, err
+	}
+	if err := g.recurring.UpdateRecurring(recurring); err != nil {
+		return nil, err
+	}
+	return recurring, nil
+}
+
+// generate saves the due occurrences of one recurring expenditure. Occurrences the service
+// refuses, e.g. because the category was deleted, are skipped; storage failures are retried on
+// the next run
+func (g *Generator) generate(recurring *domain.RecurringExpenditure, now time.Time) error {
+	occurrences, paused := recurring.Occurrences, recurring.Paused
+
+	var err error
+	for date := recurring.Due(now); date != nil; date = recurring.Due(now) {
+		_, err = g.expenditures.Create(app.ExpenditureInput{
+			ID:          recurring.OccurrenceID(),
+			Description: recurring.Description,
+			Amount:      recurring.Amount,
+			Date:        *date,
+			CategoryId:  recurring.CategoryId,
+			Tags:        recurring.Tags,
+		})
+		switch {
+		case err == nil:
+			g.logger.Info("Generated recurring expenditure", "id", recurring.ID, "date", *date)
+		case app.KindOf(err) == app.KindConflict:
+			// Saved before, but the progress was not
+			err = nil
+		case app.KindOf(err) != app.KindInternal && app.KindOf(err) != app.KindUnavailable:
+			g.logger.Warn("Skipped recurring expenditure occurrence", "id", recurring.ID, "date", *date, "error", err)
+			err = nil
+		}
+		if err != nil {
+			break
+		}
+		recurring.Generated()
+	}
+
+	if recurring.Occurrences == occurrences && recurring.Paused == paused {
+		return err
+	}
+	if updateErr := g.recurring.UpdateRecurring(recurring); updateErr != nil {
+		return updateErr
+	}
+	return err
+}
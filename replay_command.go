@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"go-expense-tracker/recording"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+// headerFlags collects the -header flags of the replay command
+type headerFlags http.Header
+
+func (h headerFlags) String() string {
+	return ""
+}
+
+func (h headerFlags) Set(value string) error {
+	name, v, ok := strings.Cut(value, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return errInvalidHeader
+	}
+	http.Header(h).Add(strings.TrimSpace(name), strings.TrimSpace(v))
+	return nil
+}
+
+var errInvalidHeader = errors.New("invalid header, expected \"Name: value\"")
+
+// runReplayCommand re-sends recorded requests to a running instance and prints how each was
+// answered next to the recorded status; it returns a failing exit code when a status changed
+func runReplayCommand(args []string, logger *slog.Logger) int {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	url := flags.String("url", "http://localhost:8080", "Base URL of the instance to replay against")
+	file := flags.String("file", "recordings.ndjson", "Recorded exchanges as JSON lines, from DEBUG_RECORDING_FILE or GET /admin/recordings")
+	id := flags.String("id", "", "Replay only the exchange of this ID")
+	path := flags.String("path", "", "Replay only the exchanges whose path starts with this prefix")
+	verbose := flags.Bool("verbose", false, "Print the response bodies of the exchanges whose status changed")
+	headers := headerFlags{}
+	flags.Var(headers, "header", "Header sent with every request, e.g. \"Authorization: Bearer ...\" for a redacted one; repeatable")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		logger.Error("Failed to open recording file", "error", err, "file", *file)
+		return 1
+	}
+	exchanges, err := recording.Read(f)
+	f.Close()
+	if err != nil {
+		logger.Error("Failed to read recording file", "error", err, "file", *file)
+		return 1
+	}
+
+	var selected []recording.Exchange
+	for _, exchange := range exchanges {
+		if *id != "" && exchange.ID.String() != *id {
+			continue
+		}
+		if *path != "" && !strings.HasPrefix(exchange.Target, *path) {
+			continue
+		}
+		selected = append(selected, exchange)
+	}
+	if len(selected) == 0 {
+		logger.Error("No recorded exchanges to replay", "file", *file, "recorded", len(exchanges))
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	logger.Info("Replaying recorded requests", "url", *url, "exchanges", len(selected))
+	client := &http.Client{Timeout: 30 * time.Second}
+	results := recording.Replay(ctx, client, *url, selected, http.Header(headers))
+	recording.WriteResults(os.Stdout, results)
+
+	code := 0
+	for _, result := range results {
+		switch {
+		case result.Skipped != "":
+			logger.Warn("Exchange skipped", "id", result.Exchange.ID, "reason", result.Skipped)
+		case result.Err != nil:
+			logger.Error("Replayed request failed", "id", result.Exchange.ID, "error", result.Err)
+			code = 1
+		case result.Changed():
+			logger.Warn("Replayed request answered differently", "id", result.Exchange.ID, "recorded", result.Exchange.Status, "replayed", result.Status)
+			if *verbose {
+				os.Stdout.WriteString("\n" + result.Exchange.ID.String() + ":\n" + result.Body + "\n")
+			}
+			code = 1
+		}
+	}
+	logger.Info("Replay finished", "replayed", len(results), "unchanged", code == 0)
+	return code
+}
@@ -0,0 +1,37 @@
+package reports
+
+import (
+	"go-expense-tracker/domain"
+	"time"
+)
+
+// BudgetStatuses computes the status of every budget in its period containing at, reading the
+// spending once over the periods of all of them; summaries may be nil
+func BudgetStatuses(budgets []*domain.Budget, summaries domain.SpendingSummaryRepository, expenditures domain.ExpenditureRepository, at time.Time, calendar domain.FiscalCalendar) ([]domain.BudgetStatus, error) {
+	statuses := make([]domain.BudgetStatus, 0, len(budgets))
+	if len(budgets) == 0 {
+		return statuses, nil
+	}
+
+	var from, to time.Time
+	for i, budget := range budgets {
+		spendingFrom := budget.SpendingFrom(at, calendar)
+		_, periodTo := budget.PeriodAt(at, calendar)
+		if i == 0 || spendingFrom.Before(from) {
+			from = spendingFrom
+		}
+		if i == 0 || periodTo.After(to) {
+			to = periodTo
+		}
+	}
+
+	spending, err := DailySpending(summaries, expenditures, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, budget := range budgets {
+		statuses = append(statuses, budget.Status(spending, at, calendar))
+	}
+	return statuses, nil
+}
@@ -0,0 +1,117 @@
+package reports
+
+import (
+	"go-expense-tracker/domain"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CashFlowCalendar is a fiscal month day by day, ready to render as a calendar heatmap
+type CashFlowCalendar struct {
+	Month     string        `json:"month"` // YYYY-MM
+	From      time.Time     `json:"from"`
+	To        time.Time     `json:"to"`
+	Spent     float64       `json:"spent"`     // Total of the days so far, pending expenditures included
+	Scheduled float64       `json:"scheduled"` // Total of the recurring charges still to come
+	Days      []CashFlowDay `json:"days"`
+}
+
+// CashFlowDay is the money going out on one day of a cash-flow calendar
+type CashFlowDay struct {
+	Date      time.Time           `json:"date"`
+	Spent     float64             `json:"spent"`
+	Count     int                 `json:"count"`
+	Intensity float64             `json:"intensity"` // Spent relative to the month's largest day, from 0 to 1
+	Scheduled float64             `json:"scheduled"` // Total of the charges
+	Charges   []ScheduledCharge   `json:"charges"`   // Recurring charges falling on the day, not generated yet
+	Budgets   []domain.BudgetPace `json:"budgets"`   // Pace of the budgets covering the day
+}
+
+// ScheduledCharge is an occurrence of a recurring expenditure still to be generated
+type ScheduledCharge struct {
+	RecurringID uuid.UUID `json:"recurring_id"`
+	Description string    `json:"description"`
+	Amount      float64   `json:"amount"`
+}
+
+// CashFlow lays out the fiscal month [from, to) day by day: the spending of every day, the recurring
+// charges still to come and how each budget is paced. Budget paces of days after now show the
+// spending so far against the pace the budget allows by then; summaries may be nil
+func CashFlow(summaries domain.SpendingSummaryRepository, expenditures domain.ExpenditureRepository, budgets []*domain.Budget, recurring []*domain.RecurringExpenditure, from, to, now time.Time, calendar domain.FiscalCalendar) (*CashFlowCalendar, error) {
+	// Budget periods may start before the month, and rollover budgets need every period since theirs
+	spendingFrom := from
+	for _, budget := range budgets {
+		if start := budget.SpendingFrom(from, calendar); start.Before(spendingFrom) {
+			spendingFrom = start
+		}
+	}
+	spending, err := DailySpending(summaries, expenditures, spendingFrom, to)
+	if err != nil {
+		return nil, err
+	}
+
+	cashFlow := &CashFlowCalendar{
+		Month: calendar.Label(from),
+		From:  from,
+		To:    to,
+		Days:  []CashFlowDay{},
+	}
+	index := make(map[time.Time]int)
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		index[day] = len(cashFlow.Days)
+		cashFlow.Days = append(cashFlow.Days, CashFlowDay{
+			Date:    day,
+			Charges: []ScheduledCharge{},
+			Budgets: []domain.BudgetPace{},
+		})
+	}
+
+	for _, spent := range spending {
+		if i, ok := index[spent.Day]; ok {
+			cashFlow.Days[i].Spent += spent.Total
+			cashFlow.Days[i].Count += spent.Count
+		}
+	}
+
+	for _, charge := range recurring {
+		for _, date := range charge.Between(from, to) {
+			day := &cashFlow.Days[index[date]]
+			day.Charges = append(day.Charges, ScheduledCharge{RecurringID: charge.ID, Description: charge.Description, Amount: charge.Amount})
+			day.Scheduled += charge.Amount
+		}
+	}
+
+	var largest float64
+	for i := range cashFlow.Days {
+		day := &cashFlow.Days[i]
+		day.Spent = round2(day.Spent)
+		day.Scheduled = round2(day.Scheduled)
+		largest = math.Max(largest, day.Spent)
+		cashFlow.Spent += day.Spent
+		cashFlow.Scheduled += day.Scheduled
+
+		sort.SliceStable(day.Charges, func(a, b int) bool {
+			return day.Charges[a].Amount > day.Charges[b].Amount
+		})
+
+		for _, budget := range budgets {
+			if budget.Covers(day.Date) {
+				pace := budget.Pace(spending, day.Date, calendar)
+				pace.OverPace = pace.OverPace && !day.Date.After(now)
+				day.Budgets = append(day.Budgets, pace)
+			}
+		}
+	}
+	cashFlow.Spent = round2(cashFlow.Spent)
+	cashFlow.Scheduled = round2(cashFlow.Scheduled)
+
+	if largest > 0 {
+		for i := range cashFlow.Days {
+			cashFlow.Days[i].Intensity = round2(cashFlow.Days[i].Spent / largest)
+		}
+	}
+	return cashFlow, nil
+}
@@ -0,0 +1,65 @@
+package reports
+
+import (
+	"go-expense-tracker/domain"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// CategorySpend is a category with the spending recorded in it. The rollup totals add the
+// spending of all its subcategories
+type CategorySpend struct {
+	*domain.Category
+	Count       int     `json:"count"`
+	Total       float64 `json:"total"`
+	RollupCount int     `json:"rollup_count"`
+	RollupTotal float64 `json:"rollup_total"`
+}
+
+// CategorySpending sums the spending of every category, including those without any, in the
+// order chosen by the user. Spending in a subcategory also counts towards the rollup of its ancestors
+func CategorySpending(expenditures []*domain.Expenditure, categories []*domain.Category) []CategorySpend {
+	return CategorySpendingFromSummaries(domain.SummarizeSpending(expenditures), categories)
+}
+
+// CategorySpendingFromSummaries is CategorySpending over spending already summarized per day
+func CategorySpendingFromSummaries(summaries []*domain.DailySpending, categories []*domain.Category) []CategorySpend {
+	byCategory := make(map[uuid.UUID]*CategorySpend, len(categories))
+	spending := make([]*CategorySpend, 0, len(categories))
+	for _, category := range categories {
+		spend := &CategorySpend{Category: category}
+		byCategory[category.ID] = spend
+		spending = append(spending, spend)
+	}
+
+	ancestors := domain.CategoryAncestors(categories)
+	for _, summary := range summaries {
+		spend, ok := byCategory[summary.CategoryId]
+		if !ok {
+			continue
+		}
+		spend.Count += summary.Count
+		spend.Total += summary.Total
+
+		for _, id := range ancestors[summary.CategoryId] {
+			byCategory[id].RollupCount += summary.Count
+			byCategory[id].RollupTotal += summary.Total
+		}
+	}
+
+	sort.Slice(spending, func(i, j int) bool {
+		if spending[i].SortOrder != spending[j].SortOrder {
+			return spending[i].SortOrder < spending[j].SortOrder
+		}
+		return spending[i].Name < spending[j].Name
+	})
+
+	result := make([]CategorySpend, 0, len(spending))
+	for _, spend := range spending {
+		spend.Total = round2(spend.Total)
+		spend.RollupTotal = round2(spend.RollupTotal)
+		result = append(result, *spend)
+	}
+	return result
+}
@@ -0,0 +1,270 @@
+package reports
+
+import (
+	"fmt"
+	"go-expense-tracker/domain"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// chartPalette colours the points and datasets that have no colour of their own, in turn
+var chartPalette = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac"}
+
+// Chart is a report shaped for chart libraries: one label per point on the axis and one dataset per
+// series, in the layout Chart.js takes as its data
+type Chart struct {
+	Labels   []string       `json:"labels"`
+	Datasets []ChartDataset `json:"datasets"`
+}
+
+// ChartDataset is a series of a chart, with a value and a colour for every label. Values missing
+// from a series are null, which chart libraries draw as gaps
+type ChartDataset struct {
+	Label           string     `json:"label"`
+	Data            []*float64 `json:"data"`
+	BackgroundColor []string   `json:"backgroundColor"` // Named as chart libraries expect
+}
+
+// chartBuilder collects the points of a chart in the order they are added
+type chartBuilder struct {
+	labels   []string
+	index    map[string]int
+	datasets []*chartSeries
+	series   map[string]*chartSeries
+	zero     bool // Whether missing values are zero instead of gaps
+}
+
+type chartSeries struct {
+	label  string
+	color  string // Of the whole series, empty to colour each point
+	values map[int]float64
+	colors map[int]string
+}
+
+func newChartBuilder(zero bool) *chartBuilder {
+	return &chartBuilder{index: make(map[string]int), series: make(map[string]*chartSeries), zero: zero}
+}
+
+// label returns the position of the label, adding it when new
+func (b *chartBuilder) label(label string) int {
+	i, ok := b.index[label]
+	if !ok {
+		i = len(b.labels)
+		b.index[label] = i
+		b.labels = append(b.labels, label)
+	}
+	return i
+}
+
+// add sets the value of the series at the label, adding both when new; color may be empty
+func (b *chartBuilder) add(series, label string, value float64, color string) {
+	i := b.label(label)
+	s := b.dataset(series, "")
+	s.values[i] += value
+	if color != "" {
+		s.colors[i] = color
+	}
+}
+
+// dataset returns the series, adding it with the colour of all its points when new
+func (b *chartBuilder) dataset(label, color string) *chartSeries {
+	s, ok := b.series[label]
+	if !ok {
+		s = &chartSeries{label: label, color: color, values: make(map[int]float64), colors: make(map[int]string)}
+		b.series[label] = s
+		b.datasets = append(b.datasets, s)
+	}
+	return s
+}
+
+// chart lays the series out over all labels. A chart of one series colours each point, while
+// several series get a colour each so they can be told apart
+func (b *chartBuilder) chart() Chart {
+	chart := Chart{Labels: b.labels, Datasets: make([]ChartDataset, 0, len(b.datasets))}
+	if chart.Labels == nil {
+		chart.Labels = []string{}
+	}
+
+	for d, s := range b.datasets {
+		dataset := ChartDataset{
+			Label:           s.label,
+			Data:            make([]*float64, len(b.labels)),
+			BackgroundColor: make([]string, len(b.labels)),
+		}
+		for i := range b.labels {
+			if value, ok := s.values[i]; ok || b.zero {
+				value = round2(value)
+				dataset.Data[i] = &value
+			}
+
+			switch {
+			case s.color != "":
+				dataset.BackgroundColor[i] = s.color
+			case len(b.datasets) > 1:
+				dataset.BackgroundColor[i] = chartPalette[d%len(chartPalette)]
+			case s.colors[i] != "":
+				dataset.BackgroundColor[i] = s.colors[i]
+			default:
+				dataset.BackgroundColor[i] = chartPalette[i%len(chartPalette)]
+			}
+		}
+		chart.Datasets = append(chart.Datasets, dataset)
+	}
+	return chart
+}
+
+// currencySeries names the series of a total by its currency, for reports of original amounts
+func currencySeries(currency string) string {
+	if currency == "" {
+		return "Total"
+	}
+	return currency
+}
+
+// CategoryChart charts the spending of the top-level categories, their subcategories included, in
+// the colours of the categories. Categories without spending are left out
+func CategoryChart(spending []CategorySpend) Chart {
+	b := newChartBuilder(true)
+	b.dataset("Total", "")
+	for _, spend := range spending {
+		if spend.ParentID == uuid.Nil && spend.RollupTotal > 0 {
+			b.add("Total", spend.Name, spend.RollupTotal, spend.Color)
+		}
+	}
+	return b.chart()
+}
+
+// MerchantChart charts the spending per merchant, one series per currency
+func MerchantChart(totals []MerchantTotal) Chart {
+	b := newChartBuilder(true)
+	for _, total := range totals {
+		b.add(currencySeries(total.Currency), total.Name, total.Total, "")
+	}
+	return b.chart()
+}
+
+// UnitChart charts the amount spent per unit, one series per currency
+func UnitChart(totals []UnitTotal) Chart {
+	b := newChartBuilder(true)
+	for _, total := range totals {
+		b.add(currencySeries(total.Currency), total.Unit, total.Amount, "")
+	}
+	return b.chart()
+}
+
+// LocationChart charts the spending per location, one series per currency; unnamed locations are
+// labelled with their coordinates
+func LocationChart(totals []LocationTotal) Chart {
+	b := newChartBuilder(true)
+	for _, total := range totals {
+		label := total.Name
+		if label == "" {
+			label = fmt.Sprintf("%.3f, %.3f", total.Latitude, total.Longitude)
+		}
+		b.add(currencySeries(total.Currency), label, total.Total, "")
+	}
+	return b.chart()
+}
+
+// CurrencyChart charts the home amounts paid in each foreign currency
+func CurrencyChart(totals []CurrencyTotal) Chart {
+	b := newChartBuilder(true)
+	b.dataset("Home total", "")
+	for _, total := range totals {
+		b.add("Home total", total.Currency, total.HomeTotal, "")
+	}
+	return b.chart()
+}
+
+// MemberChart charts the spending of every member as a stack of their categories, in the colours
+// of the categories; categories may be nil
+func MemberChart(spending []MemberSpend, categories []*domain.Category) Chart {
+	colors := make(map[uuid.UUID]string, len(categories))
+	for _, category := range categories {
+		colors[category.ID] = category.Color
+	}
+
+	b := newChartBuilder(true)
+	for _, spend := range spending {
+		member := spend.Name
+		if member == "" {
+			member = "Unattributed"
+		}
+		if spend.Currency != "" {
+			member += " (" + spend.Currency + ")"
+		}
+		b.label(member) // Members without spending still get their point
+
+		for _, total := range spend.Categories {
+			name := total.Name
+			if name == "" {
+				name = "Uncategorized"
+			}
+			b.dataset(name, colors[total.CategoryID])
+			b.add(name, member, total.Total, "")
+		}
+	}
+	return b.chart()
+}
+
+// TaxChart charts the quarters of a tax summary: the spending, the tax paid, the deductible
+// spending and the tax that can be reclaimed
+func TaxChart(summary TaxSummary) Chart {
+	b := newChartBuilder(true)
+	for _, quarter := range summary.Quarters {
+		b.add("Spend", quarter.Period, quarter.Spend, "")
+		b.add("Tax paid", quarter.Period, quarter.TaxPaid, "")
+		b.add("Deductible spend", quarter.Period, quarter.DeductibleSpend, "")
+		b.add("Deductible tax", quarter.Period, quarter.DeductibleTax, "")
+	}
+	return b.chart()
+}
+
+// PriceTrendChart charts the average unit price of every item per month, with gaps in the months an
+// item was not bought
+func PriceTrendChart(trends []PriceTrend) Chart {
+	var months []string
+	seen := make(map[string]bool)
+	for _, trend := range trends {
+		for _, point := range trend.Points {
+			if !seen[point.Month] {
+				seen[point.Month] = true
+				months = append(months, point.Month)
+			}
+		}
+	}
+	sort.Strings(months)
+
+	b := newChartBuilder(false)
+	for _, month := range months {
+		b.label(month)
+	}
+	for _, trend := range trends {
+		series := trend.Item + " (" + trend.Unit + ")"
+		if trend.Merchant != "" {
+			series = trend.Merchant + ": " + series
+		}
+		if trend.Currency != "" {
+			series += " " + trend.Currency
+		}
+		for _, point := range trend.Points {
+			b.add(series, point.Month, point.AverageUnitPrice, "")
+		}
+	}
+	return b.chart()
+}
+
+// CashFlowChart charts a cash-flow calendar day by day: the spending and the recurring charges
+// still to come
+func CashFlowChart(cashFlow *CashFlowCalendar) Chart {
+	b := newChartBuilder(true)
+	b.dataset("Spent", "")
+	b.dataset("Scheduled", "")
+	for _, day := range cashFlow.Days {
+		label := day.Date.Format("2006-01-02")
+		b.add("Spent", label, day.Spent, "")
+		b.add("Scheduled", label, day.Scheduled, "")
+	}
+	return b.chart()
+}
@@ -0,0 +1,104 @@
+package reports
+
+import (
+	"go-expense-tracker/domain"
+	"math"
+	"sort"
+)
+
+// CurrencyGroup is the expenditures of a report in one currency
+type CurrencyGroup struct {
+	Currency     string // ISO 4217 code, empty for the home currency
+	Expenditures []*domain.Expenditure
+}
+
+// InCurrencies returns the expenditures to report on, in the home currency as settled or, when
+// original is set, grouped by the currency they were paid in with their original amounts; the
+// home currency comes first. The stored expenditures are left unchanged
+func InCurrencies(expenditures []*domain.Expenditure, original bool) []CurrencyGroup {
+	if !original {
+		return []CurrencyGroup{{Expenditures: expenditures}}
+	}
+
+	byCurrency := make(map[string]*CurrencyGroup)
+	var groups []*CurrencyGroup
+	for _, expenditure := range expenditures {
+		group, ok := byCurrency[expenditure.OriginalCurrency]
+		if !ok {
+			group = &CurrencyGroup{Currency: expenditure.OriginalCurrency}
+			byCurrency[expenditure.OriginalCurrency] = group
+			groups = append(groups, group)
+		}
+		if expenditure.IsForeign() {
+			expenditure = inOriginalCurrency(expenditure)
+		}
+		group.Expenditures = append(group.Expenditures, expenditure)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Currency < groups[j].Currency
+	})
+	result := make([]CurrencyGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	return result
+}
+
+// inOriginalCurrency returns a copy of a foreign expenditure with its amounts in the currency
+// it was paid in
+func inOriginalCurrency(expenditure *domain.Expenditure) *domain.Expenditure {
+	converted := *expenditure
+	rate := expenditure.ExchangeRate()
+	converted.Amount = expenditure.OriginalAmount
+	converted.TaxAmount = round2(expenditure.TaxAmount / rate)
+	converted.UnitPrice = expenditure.UnitPrice / rate
+	return &converted
+}
+
+// CurrencyTotal is the spending paid in one foreign currency, in it and as settled at home
+type CurrencyTotal struct {
+	Currency      string  `json:"currency"`
+	Count         int     `json:"count"`
+	OriginalTotal float64 `json:"original_total"`
+	HomeTotal     float64 `json:"home_total"`
+	AverageRate   float64 `json:"average_rate"` // Home currency paid per unit of the currency, fees included
+}
+
+// CurrencyTotals sums the foreign expenditures per currency, largest home total first;
+// expenditures in the home currency are left out
+func CurrencyTotals(expenditures []*domain.Expenditure) []CurrencyTotal {
+	byCurrency := make(map[string]*CurrencyTotal)
+	for _, expenditure := range expenditures {
+		if !expenditure.IsForeign() {
+			continue
+		}
+
+		total, ok := byCurrency[expenditure.OriginalCurrency]
+		if !ok {
+			total = &CurrencyTotal{Currency: expenditure.OriginalCurrency}
+			byCurrency[expenditure.OriginalCurrency] = total
+		}
+		total.Count++
+		total.OriginalTotal += expenditure.OriginalAmount
+		total.HomeTotal += expenditure.Amount
+	}
+
+	totals := make([]CurrencyTotal, 0, len(byCurrency))
+	for _, total := range byCurrency {
+		if total.OriginalTotal != 0 {
+			total.AverageRate = math.Round(total.HomeTotal/total.OriginalTotal*10000) / 10000
+		}
+		total.OriginalTotal = round2(total.OriginalTotal)
+		total.HomeTotal = round2(total.HomeTotal)
+		totals = append(totals, *total)
+	}
+
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].HomeTotal != totals[j].HomeTotal {
+			return totals[i].HomeTotal > totals[j].HomeTotal
+		}
+		return totals[i].Currency < totals[j].Currency
+	})
+	return totals
+}
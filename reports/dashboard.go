@@ -0,0 +1,185 @@
+package reports
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	dashboardRecent        = 10 // Expenditures listed as the most recent
+	dashboardTopCategories = 5  // Categories listed as the largest this month
+	dashboardTrendMonths   = 6  // Months of the sparkline, the current one included
+)
+
+// Dashboard is everything the home screen shows, computed in one go
+type Dashboard struct {
+	Month         string                `json:"month"` // The current fiscal month, YYYY-MM
+	From          time.Time             `json:"from"`
+	To            time.Time             `json:"to"`
+	Total         float64               `json:"total"` // Spent this month so far, pending expenditures included
+	Count         int                   `json:"count"`
+	Budgets       []domain.BudgetStatus `json:"budgets"`
+	Recent        []*domain.Expenditure `json:"recent"`         // The last expenditures, newest first, without planned ones
+	TopCategories []CategorySpend       `json:"top_categories"` // The categories spent most in this month, largest first
+	Trend         []MonthTotal          `json:"trend"`          // The totals of the last months, oldest first
+	GeneratedAt   time.Time             `json:"generated_at"`
+}
+
+// MonthTotal is the spending of one fiscal month
+type MonthTotal struct {
+	Month string  `json:"month"`
+	Count int     `json:"count"`
+	Total float64 `json:"total"`
+}
+
+// DashboardCache computes the dashboard and keeps it for a while, so the home screen polling it
+// does not read all spending every time. Writes through the API drop it early, see Invalidate
+type DashboardCache struct {
+	expenditures domain.ExpenditureRepository
+	categories   domain.CategoryRepository
+	budgets      domain.BudgetRepository
+	summaries    domain.SpendingSummaryRepository
+	calendar     domain.FiscalCalendar
+	ttl          time.Duration
+	logger       *slog.Logger
+
+	mu        sync.Mutex
+	dashboard *Dashboard // nil until computed or after Invalidate
+	expires   time.Time
+}
+
+// NewDashboardCache creates a new DashboardCache keeping the dashboard for ttl, or not at all when
+// ttl is zero; categories, budgets and summaries may be nil when the storage has no support for them
+func NewDashboardCache(expenditures domain.ExpenditureRepository, categories domain.CategoryRepository, budgets domain.BudgetRepository, summaries domain.SpendingSummaryRepository, calendar domain.FiscalCalendar, ttl time.Duration, logger *slog.Logger) *DashboardCache {
+	return &DashboardCache{
+		expenditures: expenditures,
+		categories:   categories,
+		budgets:      budgets,
+		summaries:    summaries,
+		calendar:     calendar,
+		ttl:          ttl,
+		logger:       logger,
+	}
+}
+
+// Get returns the dashboard as of now, computing it when the cached one expired or belongs to
+// another month
+func (c *DashboardCache) Get(now time.Time) (*Dashboard, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.dashboard != nil && now.Before(c.expires) && c.dashboard.Month == c.calendar.Label(now.UTC()) {
+		return c.dashboard, nil
+	}
+
+	dashboard, err := c.compute(now)
+	if err != nil {
+		return nil, err
+	}
+	c.dashboard, c.expires = dashboard, now.Add(c.ttl)
+
+	c.logger.Debug("Dashboard computed", "month", dashboard.Month, "total", dashboard.Total, "expires", c.expires)
+	return dashboard, nil
+}
+
+// Invalidate drops the cached dashboard, so the next Get computes it anew
+func (c *DashboardCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dashboard = nil
+}
+
+func (c *DashboardCache) compute(now time.Time) (*Dashboard, error) {
+	now = now.UTC()
+	from, to := c.calendar.Period(now)
+	dashboard := &Dashboard{
+		Month:       c.calendar.Label(now),
+		From:        from,
+		To:          to,
+		GeneratedAt: now,
+	}
+
+	// One read of the spending serves the trend, the month's total and its categories
+	months := make([]MonthTotal, dashboardTrendMonths)
+	trendFrom := from
+	for i := len(months) - 1; i >= 0; i-- {
+		months[i].Month = c.calendar.Label(trendFrom)
+		if i > 0 {
+			trendFrom, _ = c.calendar.Period(trendFrom.AddDate(0, 0, -1))
+		}
+	}
+	spending, err := DailySpending(c.summaries, c.expenditures, trendFrom, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var current []*domain.DailySpending
+	index := make(map[string]int, len(months))
+	for i, month := range months {
+		index[month.Month] = i
+	}
+	for _, day := range spending {
+		i, ok := index[c.calendar.Label(day.Day)]
+		if !ok {
+			continue
+		}
+		months[i].Count += day.Count
+		months[i].Total += day.Total
+		if !day.Day.Before(from) {
+			current = append(current, day)
+		}
+	}
+	for i := range months {
+		months[i].Total = round2(months[i].Total)
+	}
+	dashboard.Trend = months
+	dashboard.Count = months[len(months)-1].Count
+	dashboard.Total = months[len(months)-1].Total
+
+	dashboard.TopCategories = []CategorySpend{}
+	if c.categories != nil {
+		categories, err := c.categories.GetAllCategories()
+		if err != nil {
+			return nil, err
+		}
+		for _, spend := range CategorySpendingFromSummaries(current, categories) {
+			if spend.Total > 0 {
+				dashboard.TopCategories = append(dashboard.TopCategories, spend)
+			}
+		}
+		sort.SliceStable(dashboard.TopCategories, func(i, j int) bool {
+			return dashboard.TopCategories[i].Total > dashboard.TopCategories[j].Total
+		})
+		if len(dashboard.TopCategories) > dashboardTopCategories {
+			dashboard.TopCategories = dashboard.TopCategories[:dashboardTopCategories]
+		}
+	}
+
+	dashboard.Budgets = []domain.BudgetStatus{}
+	if c.budgets != nil {
+		budgets, err := c.budgets.GetAllBudgets()
+		if err != nil {
+			return nil, err
+		}
+		if dashboard.Budgets, err = BudgetStatuses(budgets, c.summaries, c.expenditures, now, c.calendar); err != nil {
+			return nil, err
+		}
+	}
+
+	// Planned expenditures dated after today are not recent
+	dashboard.Recent, err = domain.GetExpenditurePage(c.expenditures, domain.ExpenditurePageQuery{
+		To:    domain.SpendingDay(now).AddDate(0, 0, 1),
+		Limit: dashboardRecent,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if dashboard.Recent == nil {
+		dashboard.Recent = []*domain.Expenditure{}
+	}
+	return dashboard, nil
+}
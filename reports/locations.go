@@ -0,0 +1,131 @@
+package reports
+
+import (
+	"fmt"
+	"go-expense-tracker/domain"
+	"math"
+	"sort"
+)
+
+// Location groupings supported by LocationTotals
+const (
+	GroupByCity  = "city"
+	GroupByPlace = "place"
+)
+
+// gridSize is the size in degrees of the cells clustering unnamed locations, about 1 km
+const gridSize = 0.01
+
+// LocationTotal is the spending at a city or place
+type LocationTotal struct {
+	Name      string  `json:"name"`      // City or place name, empty for unnamed clusters
+	Latitude  float64 `json:"latitude"`  // Centroid of the clustered expenditures
+	Longitude float64 `json:"longitude"` // Centroid of the clustered expenditures
+	Count     int     `json:"count"`
+	Total     float64 `json:"total"`
+	Currency  string  `json:"currency,omitempty"` // Set when reporting original amounts, empty for the home currency
+}
+
+// LocationTotals clusters the expenditures that have a location by city or by place name,
+// largest total first. Locations without the requested name fall back to the other name, and
+// unnamed locations are clustered on a grid of about a kilometre
+func LocationTotals(expenditures []*domain.Expenditure, groupBy string) []LocationTotal {
+	type cluster struct {
+		total                     LocationTotal
+		sumLatitude, sumLongitude float64
+	}
+
+	clusters := make(map[string]*cluster)
+	for _, expenditure := range expenditures {
+		location := expenditure.Location
+		if location == nil {
+			continue
+		}
+
+		name := location.PlaceName
+		if groupBy == GroupByCity || name == "" {
+			name = location.City
+		}
+		if name == "" {
+			name = location.PlaceName
+		}
+
+		key := "name:" + name
+		if name == "" {
+			key = fmt.Sprintf("grid:%.0f,%.0f", math.Floor(location.Latitude/gridSize), math.Floor(location.Longitude/gridSize))
+		}
+
+		c, ok := clusters[key]
+		if !ok {
+			c = &cluster{total: LocationTotal{Name: name}}
+			clusters[key] = c
+		}
+		c.total.Count++
+		c.total.Total += expenditure.Amount
+		c.sumLatitude += location.Latitude
+		c.sumLongitude += location.Longitude
+	}
+
+	totals := make([]LocationTotal, 0, len(clusters))
+	for _, c := range clusters {
+		total := c.total
+		total.Latitude = math.Round(c.sumLatitude/float64(total.Count)*1e6) / 1e6
+		total.Longitude = math.Round(c.sumLongitude/float64(total.Count)*1e6) / 1e6
+		total.Total = round2(total.Total)
+		totals = append(totals, total)
+	}
+
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Total != totals[j].Total {
+			return totals[i].Total > totals[j].Total
+		}
+		return totals[i].Name < totals[j].Name
+	})
+	return totals
+}
+
+// GeoJSONFeatureCollection is a GeoJSON (RFC 7946) document of point features
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+type GeoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   GeoJSONPoint           `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type GeoJSONPoint struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"` // Longitude first, as GeoJSON requires
+}
+
+// LocationGeoJSON turns location totals into point features for map visualizations
+func LocationGeoJSON(totals []LocationTotal) GeoJSONFeatureCollection {
+	collection := GeoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]GeoJSONFeature, 0, len(totals)),
+	}
+
+	for _, total := range totals {
+		properties := map[string]interface{}{
+			"name":  total.Name,
+			"count": total.Count,
+			"total": total.Total,
+		}
+		if total.Currency != "" {
+			properties["currency"] = total.Currency
+		}
+		collection.Features = append(collection.Features, GeoJSONFeature{
+			Type: "Feature",
+			Geometry: GeoJSONPoint{
+				Type:        "Point",
+				Coordinates: []float64{total.Longitude, total.Latitude},
+			},
+			Properties: properties,
+		})
+	}
+
+	return collection
+}
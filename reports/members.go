@@ -0,0 +1,103 @@
+package reports
+
+import (
+	"go-expense-tracker/domain"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// MemberSpend is the spending recorded by a single household member, split by category
+type MemberSpend struct {
+	MemberID   uuid.UUID             `json:"member_id,omitzero"` // Empty for spending recorded without a member
+	Name       string                `json:"name"`
+	Count      int                   `json:"count"`
+	Total      float64               `json:"total"`
+	Share      float64               `json:"share"`              // Percent of the spending of everyone
+	Categories []MemberCategoryTotal `json:"categories"`         // Largest first
+	Currency   string                `json:"currency,omitempty"` // Set when reporting original amounts, empty for the home currency
+}
+
+// MemberCategoryTotal is the spending of a member in a single category
+type MemberCategoryTotal struct {
+	CategoryID uuid.UUID `json:"category_id"`
+	Name       string    `json:"name"`
+	Count      int       `json:"count"`
+	Total      float64   `json:"total"`
+}
+
+// MemberSpending sums the spending recorded by every member, including those without any,
+// largest first. Expenditures recorded without a member, or by one who is no longer known, are
+// summed last as unattributed spending
+func MemberSpending(expenditures []*domain.Expenditure, members []domain.HouseholdMember, categories []*domain.Category) []MemberSpend {
+	categoryNames := make(map[uuid.UUID]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+	}
+
+	byMember := make(map[uuid.UUID]*MemberSpend, len(members))
+	spending := make([]*MemberSpend, 0, len(members)+1)
+	for _, member := range members {
+		spend := &MemberSpend{MemberID: member.ID, Name: member.Name}
+		byMember[member.ID] = spend
+		spending = append(spending, spend)
+	}
+
+	unattributed := &MemberSpend{}
+	byCategory := make(map[*MemberSpend]map[uuid.UUID]*MemberCategoryTotal)
+	var total float64
+	for _, expenditure := range expenditures {
+		spend, ok := byMember[expenditure.CreatedBy]
+		if !ok {
+			spend = unattributed
+		}
+		spend.Count++
+		spend.Total += expenditure.Amount
+		total += expenditure.Amount
+
+		categoryTotals, ok := byCategory[spend]
+		if !ok {
+			categoryTotals = make(map[uuid.UUID]*MemberCategoryTotal)
+			byCategory[spend] = categoryTotals
+		}
+		categoryTotal, ok := categoryTotals[expenditure.CategoryId]
+		if !ok {
+			categoryTotal = &MemberCategoryTotal{CategoryID: expenditure.CategoryId, Name: categoryNames[expenditure.CategoryId]}
+			categoryTotals[expenditure.CategoryId] = categoryTotal
+		}
+		categoryTotal.Count++
+		categoryTotal.Total += expenditure.Amount
+	}
+
+	sort.SliceStable(spending, func(i, j int) bool {
+		if spending[i].Total != spending[j].Total {
+			return spending[i].Total > spending[j].Total
+		}
+		return spending[i].Name < spending[j].Name
+	})
+	if unattributed.Count > 0 {
+		spending = append(spending, unattributed)
+	}
+
+	result := make([]MemberSpend, 0, len(spending))
+	for _, spend := range spending {
+		spend.Categories = make([]MemberCategoryTotal, 0, len(byCategory[spend]))
+		for _, categoryTotal := range byCategory[spend] {
+			categoryTotal.Total = round2(categoryTotal.Total)
+			spend.Categories = append(spend.Categories, *categoryTotal)
+		}
+		sort.Slice(spend.Categories, func(i, j int) bool {
+			if spend.Categories[i].Total != spend.Categories[j].Total {
+				return spend.Categories[i].Total > spend.Categories[j].Total
+			}
+			return spend.Categories[i].Name < spend.Categories[j].Name
+		})
+
+		if total > 0 {
+			spend.Share = round2(spend.Total / total * 100)
+		}
+		spend.Total = round2(spend.Total)
+		result = append(result, *spend)
+	}
+	return result
+}
@@ -0,0 +1,58 @@
+package reports
+
+import (
+	"go-expense-tracker/domain"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// MerchantTotal is the spending at a single merchant
+type MerchantTotal struct {
+	MerchantID uuid.UUID `json:"merchant_id"`
+	Name       string    `json:"name"`
+	Count      int       `json:"count"`
+	Total      float64   `json:"total"`
+	Average    float64   `json:"average"`
+	Currency   string    `json:"currency,omitempty"` // Set when reporting original amounts, empty for the home currency
+}
+
+// MerchantTotals sums the spending per merchant, largest first. Expenditures without a
+// merchant, or whose merchant was deleted, are left out
+func MerchantTotals(expenditures []*domain.Expenditure, merchants []*domain.Merchant) []MerchantTotal {
+	names := make(map[uuid.UUID]string, len(merchants))
+	for _, merchant := range merchants {
+		names[merchant.ID] = merchant.Name
+	}
+
+	byMerchant := make(map[uuid.UUID]*MerchantTotal)
+	for _, expenditure := range expenditures {
+		name, ok := names[expenditure.MerchantId]
+		if !ok {
+			continue
+		}
+
+		total, ok := byMerchant[expenditure.MerchantId]
+		if !ok {
+			total = &MerchantTotal{MerchantID: expenditure.MerchantId, Name: name}
+			byMerchant[expenditure.MerchantId] = total
+		}
+		total.Count++
+		total.Total += expenditure.Amount
+	}
+
+	totals := make([]MerchantTotal, 0, len(byMerchant))
+	for _, total := range byMerchant {
+		total.Average = round2(total.Total / float64(total.Count))
+		total.Total = round2(total.Total)
+		totals = append(totals, *total)
+	}
+
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Total != totals[j].Total {
+			return totals[i].Total > totals[j].Total
+		}
+		return totals[i].Name < totals[j].Name
+	})
+	return totals
+}
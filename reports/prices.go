@@ -0,0 +1,120 @@
+package reports
+
+import (
+	"go-expense-tracker/domain"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// PricePoint is the price paid for an item in one month
+type PricePoint struct {
+	Month            string  `json:"month"` // YYYY-MM
+	Quantity         float64 `json:"quantity"`
+	Amount           float64 `json:"amount"`
+	Count            int     `json:"count"`
+	AverageUnitPrice float64 `json:"average_unit_price"` // Amount divided by quantity
+}
+
+// PriceTrend is how the unit price of a repeat purchase changed over time, e.g. fuel in liters
+// at the same station
+type PriceTrend struct {
+	MerchantID  uuid.UUID    `json:"merchant_id,omitzero"`
+	Merchant    string       `json:"merchant,omitempty"` // Name in the merchant directory
+	Item        string       `json:"item"`               // Description of the first purchase
+	Unit        string       `json:"unit"`
+	Count       int          `json:"count"`
+	FirstPrice  float64      `json:"first_price"`        // Average unit price of the first month
+	LatestPrice float64      `json:"latest_price"`       // Average unit price of the latest month
+	Change      float64      `json:"change"`             // Percentage from the first to the latest price
+	Points      []PricePoint `json:"points"`             // Oldest month first
+	Currency    string       `json:"currency,omitempty"` // Set when reporting original amounts, empty for the home currency
+}
+
+// PriceTrends follows the unit price of per-unit expenditures bought more than once. Purchases
+// are the same item when they have the same merchant, description and unit; descriptions are
+// compared like merchant names, so "Diesel 14/03" and "DIESEL" are one item. Expenditures
+// without a quantity and refunds are left out. Trends are ordered by the size of their change,
+// largest first
+func PriceTrends(expenditures []*domain.Expenditure, merchants []*domain.Merchant) []PriceTrend {
+	names := make(map[uuid.UUID]string, len(merchants))
+	for _, merchant := range merchants {
+		names[merchant.ID] = merchant.Name
+	}
+
+	type item struct {
+		merchant    uuid.UUID
+		description string
+		unit        string
+	}
+
+	spent := make([]*domain.Expenditure, 0, len(expenditures))
+	for _, expenditure := range expenditures {
+		if expenditure.Unit != "" && expenditure.Quantity > 0 && expenditure.RefundOf == uuid.Nil {
+			spent = append(spent, expenditure)
+		}
+	}
+	sort.SliceStable(spent, func(i, j int) bool {
+		return spent[i].Date.Before(spent[j].Date)
+	})
+
+	byItem := make(map[item]*PriceTrend)
+	var order []item
+	for _, expenditure := range spent {
+		description := domain.NormalizeMerchantName(expenditure.Description)
+		if description == "" {
+			description = strings.ToLower(strings.TrimSpace(expenditure.Description))
+		}
+		key := item{merchant: expenditure.MerchantId, description: description, unit: expenditure.Unit}
+
+		trend, ok := byItem[key]
+		if !ok {
+			trend = &PriceTrend{
+				MerchantID: expenditure.MerchantId,
+				Merchant:   names[expenditure.MerchantId],
+				Item:       expenditure.Description,
+				Unit:       expenditure.Unit,
+			}
+			byItem[key] = trend
+			order = append(order, key)
+		}
+		trend.Count++
+
+		month := expenditure.Date.Format("2006-01")
+		if n := len(trend.Points); n == 0 || trend.Points[n-1].Month != month {
+			trend.Points = append(trend.Points, PricePoint{Month: month})
+		}
+		point := &trend.Points[len(trend.Points)-1]
+		point.Quantity += expenditure.Quantity
+		point.Amount += expenditure.Amount
+		point.Count++
+	}
+
+	trends := []PriceTrend{}
+	for _, key := range order {
+		trend := byItem[key]
+		if trend.Count < 2 {
+			continue
+		}
+
+		for i := range trend.Points {
+			point := &trend.Points[i]
+			point.AverageUnitPrice = math.Round(point.Amount/point.Quantity*10000) / 10000
+			point.Quantity = math.Round(point.Quantity*10000) / 10000
+			point.Amount = round2(point.Amount)
+		}
+		trend.FirstPrice = trend.Points[0].AverageUnitPrice
+		trend.LatestPrice = trend.Points[len(trend.Points)-1].AverageUnitPrice
+		if trend.FirstPrice > 0 {
+			trend.Change = round2((trend.LatestPrice - trend.FirstPrice) / trend.FirstPrice * 100)
+		}
+		trends = append(trends, *trend)
+	}
+
+	sort.SliceStable(trends, func(i, j int) bool {
+		return math.Abs(trends[i].Change) > math.Abs(trends[j].Change)
+	})
+	return trends
+}
@@ -0,0 +1,47 @@
+package reports
+
+import (
+	"context"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"time"
+)
+
+// SummaryRefresher periodically rebuilds the spending summaries of a storage, correcting any
+// drift of the incrementally maintained totals
+type SummaryRefresher struct {
+	summaries domain.SpendingSummaryRepository
+	interval  time.Duration
+	logger    *slog.Logger
+}
+
+// NewSummaryRefresher creates a new SummaryRefresher running every interval
+func NewSummaryRefresher(summaries domain.SpendingSummaryRepository, interval time.Duration, logger *slog.Logger) *SummaryRefresher {
+	return &SummaryRefresher{
+		summaries: summaries,
+		interval:  interval,
+		logger:    logger,
+	}
+}
+
+// Run refreshes the summaries every interval until the context is cancelled. The storage
+// builds them on startup, so the first refresh happens after one interval
+func (r *SummaryRefresher) Run(ctx context.Context) {
+	r.logger.Info("Starting spending summary refresh scheduler", "interval", r.interval.String())
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Stopping spending summary refresh scheduler")
+			return
+		case <-ticker.C:
+		}
+
+		if err := r.summaries.RefreshSpendingSummaries(); err != nil {
+			r.logger.Error("Failed to refresh spending summaries", "error", err)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Package reports aggregates expenditures into the summaries served under /reports.
+package reports
+
+import (
+	"go-expense-tracker/domain"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FilterByDate returns the expenditures dated within [from, to); zero bounds are open
+func FilterByDate(expenditures []*domain.Expenditure, from, to time.Time) []*domain.Expenditure {
+	if from.IsZero() && to.IsZero() {
+		return expenditures
+	}
+
+	filtered := make([]*domain.Expenditure, 0, len(expenditures))
+	for _, expenditure := range expenditures {
+		if !from.IsZero() && expenditure.Date.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !expenditure.Date.Before(to) {
+			continue
+		}
+		filtered = append(filtered, expenditure)
+	}
+	return filtered
+}
+
+// Actual returns the expenditures spent by now, leaving out planned ones dated after it and
+// cancelled ones
+func Actual(expenditures []*domain.Expenditure, now time.Time) []*domain.Expenditure {
+	actual := make([]*domain.Expenditure, 0, len(expenditures))
+	for _, expenditure := range expenditures {
+		if !expenditure.IsPlanned(now) && !expenditure.IsCancelled() {
+			actual = append(actual, expenditure)
+		}
+	}
+	return actual
+}
+
+// Cleared returns the expenditures that are not pending
+func Cleared(expenditures []*domain.Expenditure) []*domain.Expenditure {
+	cleared := make([]*domain.Expenditure, 0, len(expenditures))
+	for _, expenditure := range expenditures {
+		if !expenditure.IsPending() {
+			cleared = append(cleared, expenditure)
+		}
+	}
+	return cleared
+}
+
+// ExcludePending takes the pending expenditures out of the spending per day and category,
+// leaving out days and categories with cleared spending only
+func ExcludePending(spending []*domain.DailySpending) []*domain.DailySpending {
+	cleared := make([]*domain.DailySpending, 0, len(spending))
+	for _, summary := range spending {
+		if summary.Count == summary.PendingCount {
+			continue
+		}
+		cleared = append(cleared, &domain.DailySpending{
+			Day:        summary.Day,
+			CategoryId: summary.CategoryId,
+			Count:      summary.Count - summary.PendingCount,
+			Total:      round2(summary.Total - summary.PendingTotal),
+			TaxAmount:  round2(summary.TaxAmount - summary.PendingTaxAmount),
+		})
+	}
+	return cleared
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// DailySpending returns the spending per day and category in [from, to), read from the storage's
+// summaries when it keeps them and aggregated from the expenditures otherwise; summaries may be nil.
+// Planned expenditures are left out until their date has passed and cancelled ones altogether;
+// pending ones are included, see ExcludePending
+func DailySpending(summaries domain.SpendingSummaryRepository, expenditures domain.ExpenditureRepository, from, to time.Time) ([]*domain.DailySpending, error) {
+	now := time.Now()
+	if summaries != nil {
+		spending, err := summaries.GetDailySpending(from, to)
+		if err != nil {
+			return nil, err
+		}
+		return withoutPlanned(spending, expenditures, from, to, now)
+	}
+
+	var matching []*domain.Expenditure
+	err := domain.EachExpenditure(expenditures, func(expenditure *domain.Expenditure) error {
+		if expenditure.IsPlanned(now) || expenditure.IsCancelled() {
+			return nil
+		}
+		day := domain.SpendingDay(expenditure.Date)
+		if (from.IsZero() || !day.Before(domain.SpendingDay(from))) && (to.IsZero() || day.Before(domain.SpendingDay(to))) {
+			matching = append(matching, expenditure)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return domain.SummarizeSpending(matching), nil
+}
+
+// plannedPageSize is the number of expenditures read at a time when looking for planned ones
+const plannedPageSize = 500
+
+// withoutPlanned takes the planned expenditures dated after now out of the stored summaries,
+// which include every expenditure but cancelled ones. Only the days from today on are read again
+func withoutPlanned(spending []*domain.DailySpending, expenditures domain.ExpenditureRepository, from, to, now time.Time) ([]*domain.DailySpending, error) {
+	start := domain.SpendingDay(now)
+	if !from.IsZero() && domain.SpendingDay(from).After(start) {
+		start = domain.SpendingDay(from)
+	}
+	end := to
+	if !end.IsZero() {
+		end = domain.SpendingDay(to)
+		if !end.After(start) {
+			return spending, nil
+		}
+	}
+
+	type key struct {
+		day        time.Time
+		categoryID uuid.UUID
+	}
+	byKey := make(map[key]*domain.DailySpending, len(spending))
+	for _, summary := range spending {
+		byKey[key{summary.Day, summary.CategoryId}] = summary
+	}
+
+	query := domain.ExpenditurePageQuery{From: start, To: end, Limit: plannedPageSize}
+	for {
+		page, err := domain.GetExpenditurePage(expenditures, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, expenditure := range page {
+			if !expenditure.IsPlanned(now) || expenditure.IsCancelled() {
+				continue
+			}
+			if summary, ok := byKey[key{domain.SpendingDay(expenditure.Date), expenditure.CategoryId}]; ok {
+				summary.Count--
+				summary.Total -= expenditure.Amount
+				summary.TaxAmount -= expenditure.TaxAmount
+				if expenditure.IsPending() {
+					summary.PendingCount--
+					summary.PendingTotal -= expenditure.Amount
+					summary.PendingTaxAmount -= expenditure.TaxAmount
+				}
+			}
+		}
+		if len(page) < query.Limit {
+			break
+		}
+		cursor := domain.CursorAfter(page[len(page)-1])
+		query.After = &cursor
+	}
+
+	actual := make([]*domain.DailySpending, 0, len(spending))
+	for _, summary := range spending {
+		if summary.Count > 0 {
+			actual = append(actual, summary)
+		}
+	}
+	return actual, nil
+}
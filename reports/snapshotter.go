@@ -0,0 +1,120 @@
+package reports
+
+import (
+	"context"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Snapshotter stores the monthly report of every fiscal month once it has closed, so later edits
+// to its expenditures can be told apart from what was reported at the time
+type Snapshotter struct {
+	snapshots    domain.ReportSnapshotRepository
+	expenditures domain.ExpenditureRepository
+	categories   domain.CategoryRepository
+	summaries    domain.SpendingSummaryRepository
+	calendar     domain.FiscalCalendar
+	interval     time.Duration
+	logger       *slog.Logger
+}
+
+// NewSnapshotter creates a new Snapshotter checking for closed months every interval; categories
+// and summaries may be nil when the storage has no support for them, and snapshots when reports
+// are only computed
+func NewSnapshotter(snapshots domain.ReportSnapshotRepository, expenditures domain.ExpenditureRepository, categories domain.CategoryRepository, summaries domain.SpendingSummaryRepository, calendar domain.FiscalCalendar, interval time.Duration, logger *slog.Logger) *Snapshotter {
+	return &Snapshotter{
+		snapshots:    snapshots,
+		expenditures: expenditures,
+		categories:   categories,
+		summaries:    summaries,
+		calendar:     calendar,
+		interval:     interval,
+		logger:       logger,
+	}
+}
+
+// Run snapshots the last closed month on startup and then every interval until the context is
+// cancelled. A month that already has a snapshot is left alone
+func (s *Snapshotter) Run(ctx context.Context) {
+	s.logger.Info("Starting report snapshot scheduler", "interval", s.interval.String())
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.snapshotClosedMonth(time.Now()); err != nil {
+			s.logger.Error("Failed to snapshot the closed month", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Stopping report snapshot scheduler")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// snapshotClosedMonth takes a snapshot of the fiscal month before the one containing now, unless
+// it already has one
+func (s *Snapshotter) snapshotClosedMonth(now time.Time) error {
+	from, _ := s.calendar.Period(now.UTC())
+	month := s.calendar.Label(from.AddDate(0, 0, -1))
+
+	snapshots, err := s.snapshots.GetAllReportSnapshots()
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.Month == month {
+			return nil
+		}
+	}
+
+	_, err = s.Take(month)
+	return err
+}
+
+// Take stores a snapshot of the report of a fiscal month as it is now
+func (s *Snapshotter) Take(month string) (*domain.ReportSnapshot, error) {
+	snapshot, err := s.Compute(month)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.snapshots.AddReportSnapshot(snapshot); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Report snapshot taken", "id", snapshot.ID, "month", month, "count", snapshot.Count, "total", snapshot.Total)
+	return snapshot, nil
+}
+
+// Compute builds the report of a fiscal month as it is now without storing it
+func (s *Snapshotter) Compute(month string) (*domain.ReportSnapshot, error) {
+	from, to, err := s.calendar.PeriodOf(month, time.UTC)
+	if err != nil {
+		return nil, err
+	}
+
+	spending, err := DailySpending(s.summaries, s.expenditures, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[uuid.UUID]string)
+	if s.categories != nil {
+		categories, err := s.categories.GetAllCategories()
+		if err != nil {
+			return nil, err
+		}
+		for _, category := range categories {
+			names[category.ID] = category.Name
+		}
+	}
+
+	return domain.NewReportSnapshot(month, from, to, spending, names), nil
+}
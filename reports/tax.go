@@ -0,0 +1,83 @@
+package reports
+
+import (
+	"fmt"
+	"go-expense-tracker/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaxPeriod summarizes spending and tax paid over a quarter or a year
+type TaxPeriod struct {
+	Period          string    `json:"period"` // "2024-Q1" or "2024"
+	From            time.Time `json:"from"`
+	To              time.Time `json:"to"` // Exclusive
+	Count           int       `json:"count"`
+	Spend           float64   `json:"spend"`            // All spending, tax included
+	TaxPaid         float64   `json:"tax_paid"`         // VAT included in all spending
+	DeductibleSpend float64   `json:"deductible_spend"` // Spending in deductible categories, tax included
+	DeductibleNet   float64   `json:"deductible_net"`   // Deductible spending excluding tax
+	DeductibleTax   float64   `json:"deductible_tax"`   // VAT paid on deductible spending, reclaimable as input tax
+}
+
+// TaxSummary is the tax report of a calendar year with its quarters
+type TaxSummary struct {
+	Year     int         `json:"year"`
+	Quarters []TaxPeriod `json:"quarters"`
+	Annual   TaxPeriod   `json:"annual"`
+}
+
+// TaxSummaryForYear summarizes the year's spending for quarterly and annual filings.
+// deductible reports whether a category is tax deductible
+func TaxSummaryForYear(expenditures []*domain.Expenditure, deductible map[uuid.UUID]bool, year int) TaxSummary {
+	return TaxSummaryFromSummaries(domain.SummarizeSpending(expenditures), deductible, year)
+}
+
+// TaxSummaryFromSummaries is TaxSummaryForYear over spending already summarized per day
+func TaxSummaryFromSummaries(summaries []*domain.DailySpending, deductible map[uuid.UUID]bool, year int) TaxSummary {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	summary := TaxSummary{
+		Year:     year,
+		Quarters: make([]TaxPeriod, 4),
+		Annual:   TaxPeriod{Period: fmt.Sprint(year), From: start, To: start.AddDate(1, 0, 0)},
+	}
+	for q := range summary.Quarters {
+		from := start.AddDate(0, 3*q, 0)
+		summary.Quarters[q] = TaxPeriod{Period: fmt.Sprintf("%d-Q%d", year, q+1), From: from, To: from.AddDate(0, 3, 0)}
+	}
+
+	for _, day := range summaries {
+		if day.Day.Year() != year {
+			continue
+		}
+
+		quarter := &summary.Quarters[(int(day.Day.Month())-1)/3]
+		for _, period := range []*TaxPeriod{quarter, &summary.Annual} {
+			period.Count += day.Count
+			period.Spend += day.Total
+			period.TaxPaid += day.TaxAmount
+			if deductible[day.CategoryId] {
+				period.DeductibleSpend += day.Total
+				period.DeductibleNet += day.Total - day.TaxAmount
+				period.DeductibleTax += day.TaxAmount
+			}
+		}
+	}
+
+	summary.Annual.round()
+	for q := range summary.Quarters {
+		summary.Quarters[q].round()
+	}
+
+	return summary
+}
+
+func (p *TaxPeriod) round() {
+	p.Spend = round2(p.Spend)
+	p.TaxPaid = round2(p.TaxPaid)
+	p.DeductibleSpend = round2(p.DeductibleSpend)
+	p.DeductibleNet = round2(p.DeductibleNet)
+	p.DeductibleTax = round2(p.DeductibleTax)
+}
@@ -0,0 +1,51 @@
+package reports
+
+import (
+	"go-expense-tracker/domain"
+	"math"
+	"sort"
+)
+
+// UnitTotal sums the per-unit expenditures recorded with the same unit, e.g. all mileage in km
+type UnitTotal struct {
+	Unit             string  `json:"unit"`
+	Quantity         float64 `json:"quantity"`
+	Amount           float64 `json:"amount"`
+	Count            int     `json:"count"`
+	AverageUnitPrice float64 `json:"average_unit_price"` // Amount divided by quantity
+	Currency         string  `json:"currency,omitempty"` // Set when reporting original amounts, empty for the home currency
+}
+
+// UnitTotals groups per-unit expenditures by unit; expenditures without a quantity are skipped
+func UnitTotals(expenditures []*domain.Expenditure) []UnitTotal {
+	byUnit := make(map[string]*UnitTotal)
+	for _, expenditure := range expenditures {
+		if expenditure.Unit == "" {
+			continue
+		}
+
+		total, ok := byUnit[expenditure.Unit]
+		if !ok {
+			total = &UnitTotal{Unit: expenditure.Unit}
+			byUnit[expenditure.Unit] = total
+		}
+		total.Quantity += expenditure.Quantity
+		total.Amount += expenditure.Amount
+		total.Count++
+	}
+
+	totals := make([]UnitTotal, 0, len(byUnit))
+	for _, total := range byUnit {
+		if total.Quantity > 0 {
+			total.AverageUnitPrice = math.Round(total.Amount/total.Quantity*10000) / 10000
+		}
+		total.Quantity = math.Round(total.Quantity*10000) / 10000
+		total.Amount = round2(total.Amount)
+		totals = append(totals, *total)
+	}
+
+	sort.Slice(totals, func(i, j int) bool {
+		return totals[i].Unit < totals[j].Unit
+	})
+	return totals
+}
@@ -0,0 +1,219 @@
+// Package retention purges records once they are older than the retention rules allow, e.g.
+// expenditures after seven years, and keeps an audit entry of every purge.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Purger periodically removes the records the retention rules no longer keep
+type Purger struct {
+	rules        []domain.RetentionRule
+	purges       domain.RetentionRepository
+	expenditures domain.ExpenditureRepository
+	drafts       domain.DraftRepository
+	archives     domain.ArchiveRepository
+	interval     time.Duration
+	logger       *slog.Logger
+	mu           sync.Mutex // Serializes runs, so a preview matches the next run
+}
+
+// NewPurger creates a new Purger applying the rules every interval; drafts and archives may be
+// nil when the storage has no support for them, in which case no rule may target them
+func NewPurger(rules []domain.RetentionRule, purges domain.RetentionRepository, expenditures domain.ExpenditureRepository, drafts domain.DraftRepository, archives domain.ArchiveRepository, interval time.Duration, logger *slog.Logger) (*Purger, error) {
+	for _, rule := range rules {
+		if (rule.Target == domain.RetainDrafts && drafts == nil) || (rule.Target == domain.RetainArchives && archives == nil) {
+			return nil, fmt.Errorf("the storage keeps no %s to purge", rule.Target)
+		}
+	}
+
+	return &Purger{
+		rules:        rules,
+		purges:       purges,
+		expenditures: expenditures,
+		drafts:       drafts,
+		archives:     archives,
+		interval:     interval,
+		logger:       logger,
+	}, nil
+}
+
+// Rules returns the rules the purger applies
+func (p *Purger) Rules() []domain.RetentionRule {
+	return p.rules
+}
+
+// Run purges on startup and then every interval until the context is cancelled
+func (p *Purger) Run(ctx context.Context) {
+	p.logger.Info("Starting retention scheduler", "interval", p.interval.String(), "rules", len(p.rules))
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.Purge(time.Now()); err != nil {
+			p.logger.Error("Failed to purge expired records", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			p.logger.Info("Stopping retention scheduler")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Preview returns what a run at now would remove, one entry per rule with anything to remove
+func (p *Purger) Preview(now time.Time) ([]*domain.RetentionPurge, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.plan(now)
+}
+
+// Purge removes the expired records of every rule and saves an audit entry for each rule that
+// removed any. A rule failing part way is audited with what it removed before the failure
+func (p *Purger) Purge(now time.Time) ([]*domain.RetentionPurge, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	planned, err := p.plan(now)
+	if err != nil {
+		return nil, err
+	}
+
+	var purged []*domain.RetentionPurge
+	for _, purge := range planned {
+		removed, removeErr := p.remove(purge.Target, purge.IDs)
+
+		purge.ID = uuid.New()
+		purge.IDs = removed
+		purge.Count = len(removed)
+		purge.PurgedAt = time.Now()
+		if purge.Count > 0 {
+			if err := p.purges.AddRetentionPurge(purge); err != nil {
+				p.logger.Error("Failed to audit retention purge", "error", err, "target", purge.Target, "count", purge.Count)
+				return purged, err
+			}
+			purged = append(purged, purge)
+			p.logger.Info("Purged expired records", "id", purge.ID, "target", purge.Target, "age", purge.Age, "before", purge.Before, "count", purge.Count)
+		}
+
+		if removeErr != nil {
+			return purged, removeErr
+		}
+	}
+	return purged, nil
+}
+
+// plan collects the records each rule would remove at now
+func (p *Purger) plan(now time.Time) ([]*domain.RetentionPurge, error) {
+	var planned []*domain.RetentionPurge
+	for _, rule := range p.rules {
+		before := rule.Cutoff(now)
+		ids, err := p.expired(rule.Target, before)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		planned = append(planned, &domain.RetentionPurge{
+			Target: rule.Target,
+			Age:    rule.Age,
+			Before: before,
+			Count:  len(ids),
+			IDs:    ids,
+		})
+	}
+	return planned, nil
+}
+
+// expired returns the IDs of the records of target older than before
+func (p *Purger) expired(target domain.RetentionTarget, before time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	switch target {
+	case domain.RetainExpenditures:
+		// An expenditure goes with its refunds, even ones dated later, and after them: it cannot
+		// be deleted while they point to it
+		var expired, refunds []*domain.Expenditure
+		err := domain.EachExpenditure(p.expenditures, func(expenditure *domain.Expenditure) error {
+			switch {
+			case expenditure.IsRefund():
+				refunds = append(refunds, expenditure)
+			case expenditure.Date.Before(before):
+				expired = append(expired, expenditure)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		originals := make(map[uuid.UUID]bool, len(expired))
+		for _, expenditure := range expired {
+			originals[expenditure.ID] = true
+		}
+		for _, refund := range refunds {
+			if originals[refund.RefundOf] {
+				ids = append(ids, refund.ID)
+			}
+		}
+		for _, expenditure := range expired {
+			ids = append(ids, expenditure.ID)
+		}
+
+	case domain.RetainDrafts:
+		drafts, err := p.drafts.GetAllDrafts()
+		if err != nil {
+			return nil, err
+		}
+		for _, draft := range drafts {
+			if draft.CreatedAt.Before(before) {
+				ids = append(ids, draft.ID)
+			}
+		}
+
+	case domain.RetainArchives:
+		// An archive holds expenditures dated before its cutoff, so it expires as a whole
+		archives, err := p.archives.GetAllArchives()
+		if err != nil {
+			return nil, err
+		}
+		for _, archive := range archives {
+			if !archive.Before.After(before) {
+				ids = append(ids, archive.ID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// remove deletes the records of target, returning those removed until the first failure
+func (p *Purger) remove(target domain.RetentionTarget, ids []uuid.UUID) ([]uuid.UUID, error) {
+	remove := p.expenditures.DeleteExpenditure
+	switch target {
+	case domain.RetainDrafts:
+		remove = p.drafts.DeleteDraft
+	case domain.RetainArchives:
+		remove = p.archives.DeleteArchive
+	}
+
+	removed := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if err := remove(id.String()); err != nil {
+			p.logger.Error("Failed to purge expired record", "error", err, "target", target, "id", id)
+			return removed, err
+		}
+		removed = append(removed, id)
+	}
+	return removed, nil
+}
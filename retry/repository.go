@@ -0,0 +1,128 @@
+package retry
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"time"
+)
+
+// Repository makes the calls to an expenditure repository again while they fail with transient
+// errors, following a Policy. Errors the storage answered with, such as a missing expenditure,
+// are returned at once
+type Repository struct {
+	repo        domain.ExpenditureRepository
+	policy      Policy
+	isTransient func(error) bool
+	logger      *slog.Logger
+}
+
+// NewRepository wraps repo, whose transient errors are told apart by checker
+func NewRepository(repo domain.ExpenditureRepository, checker domain.TransientErrorChecker, policy Policy, logger *slog.Logger) *Repository {
+	return &Repository{
+		repo:        repo,
+		policy:      policy,
+		isTransient: checker.IsTransient,
+		logger:      logger,
+	}
+}
+
+func (r *Repository) do(op string, fn func() error) (int, error) {
+	return Do(r.policy, r.isTransient, r.logger, op, fn)
+}
+
+func (r *Repository) AddExpenditure(expenditure *domain.Expenditure) error {
+	attempts, err := r.do("add", func() error { return r.repo.AddExpenditure(expenditure) })
+	// A connection lost while the insert was committed hides that it went through
+	if attempts > 1 && errors.Is(err, domain.ErrExpenditureAlreadyExists) {
+		return nil
+	}
+	return err
+}
+
+func (r *Repository) AddExpenditures(expenditures []*domain.Expenditure) error {
+	attempts, err := r.do("add many", func() error { return domain.AddExpenditures(r.repo, expenditures) })
+	if attempts > 1 && errors.Is(err, domain.ErrExpenditureAlreadyExists) {
+		// Storages without bulk support keep the expenditures added before a failure, which
+		// the next attempt finds; add the rest one by one
+		for _, expenditure := range expenditures {
+			if err := r.AddExpenditure(expenditure); err != nil && !errors.Is(err, domain.ErrExpenditureAlreadyExists) {
+				return err
+			}
+		}
+		return nil
+	}
+	return err
+}
+
+func (r *Repository) UpdateExpenditure(expenditure *domain.Expenditure) error {
+	_, err := r.do("update", func() error { return r.repo.UpdateExpenditure(expenditure) })
+	return err
+}
+
+func (r *Repository) DeleteExpenditure(id string) error {
+	attempts, err := r.do("delete", func() error { return r.repo.DeleteExpenditure(id) })
+	// As with adding, an earlier attempt may have gone through
+	if attempts > 1 && errors.Is(err, domain.ErrExpenditureNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (r *Repository) GetExpenditureByID(id string) (*domain.Expenditure, error) {
+	var expenditure *domain.Expenditure
+	_, err := r.do("get", func() (err error) {
+		expenditure, err = r.repo.GetExpenditureByID(id)
+		return err
+	})
+	return expenditure, err
+}
+
+func (r *Repository) GetAllExpenditures() ([]*domain.Expenditure, error) {
+	var expenditures []*domain.Expenditure
+	_, err := r.do("list", func() (err error) {
+		expenditures, err = r.repo.GetAllExpenditures()
+		return err
+	})
+	return expenditures, err
+}
+
+func (r *Repository) StreamExpenditures(fn func(*domain.Expenditure) error) error {
+	// What was handed to fn cannot be taken back, so only a stream that fails before handing
+	// out anything is started again
+	streamed := false
+	var streamErr error
+	_, err := r.do("stream", func() error {
+		err := domain.EachExpenditure(r.repo, func(expenditure *domain.Expenditure) error {
+			streamed = true
+			return fn(expenditure)
+		})
+		if streamed {
+			streamErr = err
+			return nil
+		}
+		return err
+	})
+	if streamErr != nil {
+		return streamErr
+	}
+	return err
+}
+
+func (r *Repository) GetExpenditurePage(query domain.ExpenditurePageQuery) ([]*domain.Expenditure, error) {
+	var expenditures []*domain.Expenditure
+	_, err := r.do("page", func() (err error) {
+		expenditures, err = domain.GetExpenditurePage(r.repo, query)
+		return err
+	})
+	return expenditures, err
+}
+
+func (r *Repository) CountExpenditures(from, to time.Time) (int, error) {
+	var count int
+	_, err := r.do("count", func() (err error) {
+		count, err = domain.CountExpenditures(r.repo, from, to)
+		return err
+	})
+	return count, err
+}
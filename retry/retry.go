@@ -0,0 +1,84 @@
+// Package retry makes storage calls again when they fail for a moment, such as on a connection
+// reset or a serialization failure, waiting longer after each attempt, so brief network blips
+// don't surface as errors.
+package retry
+
+import (
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+// DefaultPolicy makes up to three attempts, the second after up to 50ms and the third after up
+// to 100ms
+var DefaultPolicy = Policy{Attempts: 3, Delay: 50 * time.Millisecond, MaxDelay: time.Second}
+
+// The clock, the waits and the jitter, replaced in tests
+var (
+	now    = time.Now
+	sleep  = time.Sleep
+	jitter = func(limit time.Duration) time.Duration { return rand.N(limit) + 1 }
+)
+
+// Policy configures how often and after how long failed calls are made again
+type Policy struct {
+	Attempts int           // Attempts in total, including the first; 1 never retries
+	Delay    time.Duration // Longest wait before the second attempt, doubled for each further one
+	MaxDelay time.Duration // Cap on the wait between two attempts
+}
+
+// backoff returns the wait before the given attempt, counted from 1 for the first retry, with
+// full jitter so clients failing together don't retry together
+func (p Policy) backoff(attempt int) time.Duration {
+	limit := p.Delay
+	for i := 1; i < attempt && limit < p.MaxDelay; i++ {
+		limit *= 2
+	}
+	limit = min(limit, p.MaxDelay)
+	if limit <= 0 {
+		return 0
+	}
+	return jitter(limit)
+}
+
+// Do calls fn until it succeeds, fails with an error isTransient rejects, or the attempts are
+// used up, and returns the error of the last attempt. It reports the attempts made
+func Do(policy Policy, isTransient func(error) bool, logger *slog.Logger, op string, fn func() error) (int, error) {
+	attempt := 1
+	for {
+		err := fn()
+		if err == nil || attempt >= policy.Attempts || !isTransient(err) {
+			return attempt, err
+		}
+
+		delay := policy.backoff(attempt)
+		logger.Warn("Retrying storage call", "operation", op, "attempt", attempt+1, "delay", delay.String(), "error", err)
+		sleep(delay)
+		attempt++
+	}
+}
+
+// For calls fn until it succeeds, fails with an error isTransient rejects, or timeout has passed,
+// waiting between the attempts as policy says; its Attempts are ignored. A negative timeout
+// never gives up and a zero one makes a single attempt
+func For(timeout time.Duration, policy Policy, isTransient func(error) bool, logger *slog.Logger, op string, fn func() error) error {
+	deadline := now().Add(timeout)
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !isTransient(err) {
+			return err
+		}
+
+		delay := policy.backoff(attempt)
+		if timeout >= 0 {
+			// The last attempt is made when the time is up
+			remaining := deadline.Sub(now())
+			if remaining <= 0 {
+				return err
+			}
+			delay = min(delay, remaining)
+		}
+		logger.Warn("Retrying", "operation", op, "attempt", attempt+1, "delay", delay.String(), "error", err)
+		sleep(delay)
+	}
+}
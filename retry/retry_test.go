@@ -0,0 +1,213 @@
+package retry
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/services"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	errReset     = errors.New("connection reset by peer")
+	errViolation = errors.New("check constraint violated")
+)
+
+func isTransient(err error) bool { return errors.Is(err, errReset) }
+
+// fakeClock replaces the clock and the waits for the test, so waiting moves the clock at once,
+// and the jitter, so every wait is as long as allowed. It returns the waits made
+func fakeClock(t *testing.T) *[]time.Duration {
+	t.Helper()
+	clock := time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)
+	waits := []time.Duration{}
+
+	realNow, realSleep, realJitter := now, sleep, jitter
+	t.Cleanup(func() { now, sleep, jitter = realNow, realSleep, realJitter })
+	now = func() time.Time { return clock }
+	sleep = func(d time.Duration) {
+		waits = append(waits, d)
+		clock = clock.Add(d)
+	}
+	jitter = func(limit time.Duration) time.Duration { return limit }
+	return &waits
+}
+
+// failing returns a call failing with errs in turn, then succeeding, and the calls made
+func failing(errs ...error) (func() error, *int) {
+	calls := 0
+	return func() error {
+		calls++
+		if calls <= len(errs) {
+			return errs[calls-1]
+		}
+		return nil
+	}, &calls
+}
+
+func TestBackoff(t *testing.T) {
+	fakeClock(t)
+	tests := []struct {
+		name   string
+		policy Policy
+		want   []time.Duration // Waits before the retries
+	}{
+		{"default", DefaultPolicy, []time.Duration{50 * time.Millisecond, 100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}},
+		{"capped below the delay", Policy{Delay: time.Second, MaxDelay: 300 * time.Millisecond}, []time.Duration{300 * time.Millisecond, 300 * time.Millisecond}},
+		{"no delay", Policy{MaxDelay: time.Second}, []time.Duration{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, want := range tt.want {
+				if got := tt.policy.backoff(i + 1); got != want {
+					t.Errorf("retry %d: wait %s, want %s", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestJitter(t *testing.T) {
+	limit := 10 * time.Nanosecond
+	for range 1000 {
+		if got := jitter(limit); got < 1 || got > limit {
+			t.Fatalf("jitter = %s, want between 1ns and %s", got, limit)
+		}
+	}
+}
+
+func TestDo(t *testing.T) {
+	policy := Policy{Attempts: 3, Delay: 50 * time.Millisecond, MaxDelay: time.Second}
+	tests := []struct {
+		name         string
+		errs         []error
+		policy       Policy
+		wantAttempts int
+		wantErr      error
+		wantWaits    []time.Duration
+	}{
+		{"first attempt", nil, policy, 1, nil, []time.Duration{}},
+		{"after a blip", []error{errReset}, policy, 2, nil, []time.Duration{50 * time.Millisecond}},
+		{"on the last attempt", []error{errReset, errReset}, policy, 3, nil, []time.Duration{50 * time.Millisecond, 100 * time.Millisecond}},
+		{"attempts used up", []error{errReset, errReset, errReset}, policy, 3, errReset, []time.Duration{50 * time.Millisecond, 100 * time.Millisecond}},
+		{"permanent error", []error{errViolation}, policy, 1, errViolation, []time.Duration{}},
+		{"permanent after a blip", []error{errReset, errViolation}, policy, 2, errViolation, []time.Duration{50 * time.Millisecond}},
+		{"retries disabled", []error{errReset}, Policy{Attempts: 1, Delay: time.Second, MaxDelay: time.Second}, 1, errReset, []time.Duration{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waits := fakeClock(t)
+			fn, calls := failing(tt.errs...)
+			attempts, err := Do(tt.policy, isTransient, slog.New(slog.DiscardHandler), "test", fn)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts || *calls != tt.wantAttempts {
+				t.Errorf("%d attempts reported and %d made, want %d", attempts, *calls, tt.wantAttempts)
+			}
+			if !slices.Equal(*waits, tt.wantWaits) {
+				t.Errorf("waits = %v, want %v", *waits, tt.wantWaits)
+			}
+		})
+	}
+}
+
+func TestFor(t *testing.T) {
+	policy := Policy{Delay: time.Second, MaxDelay: 4 * time.Second}
+	down := []error{errReset, errReset, errReset, errReset, errReset, errReset, errReset, errReset}
+	tests := []struct {
+		name      string
+		timeout   time.Duration
+		errs      []error
+		wantCalls int
+		wantErr   error
+		wantWaits []time.Duration
+	}{
+		{"single attempt", 0, down, 1, errReset, []time.Duration{}},
+		{"up in time", 10 * time.Second, []error{errReset, errReset}, 3, nil, []time.Duration{time.Second, 2 * time.Second}},
+		// The last wait is cut short, so the last attempt is made when the time is up
+		{"time up", 10 * time.Second, down, 5, errReset, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 3 * time.Second}},
+		{"never gives up", -1, down, 9, nil, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second, 4 * time.Second, 4 * time.Second, 4 * time.Second, 4 * time.Second}},
+		{"permanent error", 10 * time.Second, []error{errReset, errViolation}, 2, errViolation, []time.Duration{time.Second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waits := fakeClock(t)
+			fn, calls := failing(tt.errs...)
+			err := For(tt.timeout, policy, isTransient, slog.New(slog.DiscardHandler), "test", fn)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if *calls != tt.wantCalls {
+				t.Errorf("%d attempts, want %d", *calls, tt.wantCalls)
+			}
+			if !slices.Equal(*waits, tt.wantWaits) {
+				t.Errorf("waits = %v, want %v", *waits, tt.wantWaits)
+			}
+		})
+	}
+}
+
+// lossyStorage loses the connection after the next changes went through
+type lossyStorage struct {
+	*services.MemoryService
+	lose int
+}
+
+func (s *lossyStorage) IsTransient(err error) bool { return isTransient(err) }
+
+func (s *lossyStorage) lost(err error) error {
+	if err == nil && s.lose > 0 {
+		s.lose--
+		return errReset
+	}
+	return err
+}
+
+func (s *lossyStorage) AddExpenditure(expenditure *domain.Expenditure) error {
+	return s.lost(s.MemoryService.AddExpenditure(expenditure))
+}
+
+func (s *lossyStorage) DeleteExpenditure(id string) error {
+	return s.lost(s.MemoryService.DeleteExpenditure(id))
+}
+
+func TestRepository(t *testing.T) {
+	fakeClock(t)
+	storage := &lossyStorage{MemoryService: services.NewMemoryService(slog.New(slog.DiscardHandler))}
+	repo := NewRepository(storage, storage, DefaultPolicy, slog.New(slog.DiscardHandler))
+
+	expenditure, err := domain.NewExpenditure("Lunch", 12.5, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), uuid.New())
+	if err != nil {
+		t.Fatalf("creating expenditure: %v", err)
+	}
+	id := expenditure.ID.String()
+
+	// The steps run in order against the same repository
+	steps := []struct {
+		name    string
+		lose    int
+		call    func() error
+		wantErr error
+	}{
+		{"add going through with the connection lost", 1, func() error { return repo.AddExpenditure(expenditure) }, nil},
+		{"add again", 0, func() error { return repo.AddExpenditure(expenditure) }, domain.ErrExpenditureAlreadyExists},
+		{"delete going through with the connection lost", 1, func() error { return repo.DeleteExpenditure(id) }, nil},
+		{"delete again", 0, func() error { return repo.DeleteExpenditure(id) }, domain.ErrExpenditureNotFound},
+		{"update missing", 0, func() error { return repo.UpdateExpenditure(expenditure) }, domain.ErrExpenditureNotFound},
+	}
+
+	for _, step := range steps {
+		storage.lose = step.lose
+		if err := step.call(); !errors.Is(err, step.wantErr) {
+			t.Errorf("%s: got %v, want %v", step.name, err, step.wantErr)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Package seed generates realistic demo expenditures so that new installations have
+// something to explore in the listings and reports.
+package seed
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+)
+
+var ErrNoCategories = errors.New("no active categories to seed expenditures into")
+var ErrInvalidMonths = errors.New("months must be between 1 and 120")
+
+// Options controls what Generate produces
+type Options struct {
+	Months int       // Number of months, the current one included
+	Now    time.Time // No expenditure is dated after it
+	Seed   uint64    // The same seed and categories always produce the same amounts and dates
+}
+
+// profile describes the typical spending in a category
+type profile struct {
+	perMonth     float64  // Average number of expenditures per month
+	median       float64  // Median amount
+	spread       float64  // Standard deviation of the log of the amount
+	fixedDay     int      // Day of month of recurring payments, 0 when spread over the month
+	descriptions []string // Picked at random
+}
+
+// profiles are keyed by the names of the default categories; others use genericProfile
+var profiles = map[string]profile{
+	"Food & Dining":      {perMonth: 22, median: 14, spread: 0.6, descriptions: []string{"Groceries", "Lunch", "Coffee", "Dinner out", "Bakery", "Takeaway"}},
+	"Transportation":     {perMonth: 8, median: 18, spread: 0.7, descriptions: []string{"Fuel", "Bus ticket", "Taxi", "Parking", "Train ticket"}},
+	"Housing":            {perMonth: 1, median: 1200, spread: 0.02, fixedDay: 1, descriptions: []string{"Rent"}},
+	"Utilities":          {perMonth: 3, median: 60, spread: 0.3, fixedDay: 15, descriptions: []string{"Electricity", "Internet", "Water", "Mobile phone"}},
+	"Health & Fitness":   {perMonth: 2, median: 35, spread: 0.6, descriptions: []string{"Gym membership", "Pharmacy", "Doctor visit"}},
+	"Entertainment":      {perMonth: 4, median: 20, spread: 0.6, descriptions: []string{"Cinema", "Streaming subscription", "Concert", "Books"}},
+	"Shopping":           {perMonth: 4, median: 45, spread: 0.8, descriptions: []string{"Clothes", "Electronics", "Household items", "Shoes"}},
+	"Travel":             {perMonth: 0.3, median: 400, spread: 0.6, descriptions: []string{"Flight", "Hotel", "Car rental"}},
+	"Education":          {perMonth: 0.5, median: 80, spread: 0.5, descriptions: []string{"Online course", "Textbook", "Workshop"}},
+	"Financial Services": {perMonth: 1, median: 8, spread: 0.3, fixedDay: 28, descriptions: []string{"Bank fee", "Card fee"}},
+	"Personal Care":      {perMonth: 2, median: 25, spread: 0.5, descriptions: []string{"Haircut", "Toiletries", "Cosmetics"}},
+	"Gifts & Donations":  {perMonth: 0.7, median: 40, spread: 0.7, descriptions: []string{"Birthday gift", "Charity donation", "Flowers"}},
+	"Miscellaneous":      {perMonth: 1.5, median: 15, spread: 0.8, descriptions: []string{"Post office", "Stationery", "Laundry"}},
+	"Uncategorized":      {perMonth: 1, median: 20, spread: 0.8, descriptions: []string{"Card payment", "Cash withdrawal"}},
+}
+
+var genericProfile = profile{perMonth: 2, median: 25, spread: 0.7}
+
+// Generate creates demo expenditures in the active categories over the last opts.Months months,
+// sorted by date. Counts per month follow a Poisson distribution and amounts a log-normal one
+// around each category's typical values; recurring payments such as rent fall on a fixed day
+func Generate(categories []*domain.Category, opts Options) ([]*domain.Expenditure, error) {
+	if opts.Months < 1 || opts.Months > 120 {
+		return nil, ErrInvalidMonths
+	}
+	categories = domain.ActiveCategories(categories)
+	if len(categories) == 0 {
+		return nil, ErrNoCategories
+	}
+
+	rng := rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x9e3779b97f4a7c15))
+	now := opts.Now
+	firstMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, 1-opts.Months, 0)
+
+	var expenditures []*domain.Expenditure
+	for month := 0; month < opts.Months; month++ {
+		start := firstMonth.AddDate(0, month, 0)
+		end := start.AddDate(0, 1, 0)
+
+		for _, category := range categories {
+			p, known := profiles[category.Name]
+			if !known {
+				p = genericProfile
+				p.descriptions = []string{category.Name}
+			}
+
+			count := poisson(rng, p.perMonth)
+			if p.fixedDay > 0 {
+				count = len(p.descriptions)
+			}
+			for i := 0; i < count; i++ {
+				var date time.Time
+				description := p.descriptions[rng.IntN(len(p.descriptions))]
+				if p.fixedDay > 0 {
+					date = start.AddDate(0, 0, p.fixedDay-1).Add(9 * time.Hour)
+					description = p.descriptions[i]
+				} else {
+					date = start.Add(time.Duration(rng.Int64N(int64(end.Sub(start)))))
+				}
+				if date.After(now) {
+					continue
+				}
+
+				amount := math.Round(p.median*math.Exp(p.spread*rng.NormFloat64())*100) / 100
+				expenditure, err := domain.NewExpenditure(description, math.Max(amount, 0.01), date, category.ID)
+				if err != nil {
+					return nil, err
+				}
+				expenditure.SetTags([]string{"demo"})
+				expenditures = append(expenditures, expenditure)
+			}
+		}
+	}
+
+	sort.Slice(expenditures, func(i, j int) bool {
+		return expenditures[i].Date.Before(expenditures[j].Date)
+	})
+	return expenditures, nil
+}
+
+// poisson draws from a Poisson distribution with the given mean (Knuth's method, fine for
+// the small means used here)
+func poisson(rng *rand.Rand, mean float64) int {
+	limit := math.Exp(-mean)
+	count := 0
+	for p := rng.Float64(); p > limit; p *= rng.Float64() {
+		count++
+	}
+	return count
+}
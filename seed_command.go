@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/seed"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+// runSeedCommand generates demo expenditures into the storage and returns the exit code
+func runSeedCommand(service domain.ExpenditureRepository, categories domain.CategoryRepository, args []string, logger *slog.Logger) int {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	months := flags.Int("months", 6, "Number of months of demo data, the current one included")
+	seedValue := flags.Uint64("seed", 0, "Random seed for reproducible data (random when 0)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if categories == nil {
+		logger.Error("The storage does not support categories, cannot seed demo data")
+		return 1
+	}
+	if *seedValue == 0 {
+		*seedValue = rand.Uint64()
+	}
+
+	all, err := categories.GetAllCategories()
+	if err != nil {
+		logger.Error("Failed to get categories", "error", err)
+		return 1
+	}
+
+	expenditures, err := seed.Generate(all, seed.Options{Months: *months, Now: time.Now(), Seed: *seedValue})
+	if err != nil {
+		logger.Error("Failed to generate demo data", "error", err, "months", *months)
+		return 1
+	}
+
+	if err := domain.AddExpenditures(service, expenditures); err != nil {
+		logger.Error("Failed to add demo expenditures", "error", err)
+		return 1
+	}
+
+	logger.Info("Seeded demo data", "months", *months, "seed", *seedValue, "count", len(expenditures))
+	return 0
+}
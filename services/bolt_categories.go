@@ -0,0 +1,281 @@
+//go:build bolt
+
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker/domain"
+	"strings"
+
+	"github.com/google/uuid"
+	bolt "go.etcd.io/bbolt"
+)
+
+// seedBoltCategories adds the default categories when the database has none, i.e. on first use
+func seedBoltCategories(tx *bolt.Tx) error {
+	if key, _ := tx.Bucket(boltCategories).Cursor().First(); key != nil {
+		return nil
+	}
+
+	categories, err := setupCategories()
+	if err != nil {
+		return err
+	}
+	for _, category := range categories {
+		if err := putBoltCategory(tx, category); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddCategory adds a new category; names are unique ignoring case
+func (s *BoltService) AddCategory(category *domain.Category) error {
+	s.logger.Debug("Adding category to bolt", "id", category.ID, "name", category.Name)
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(boltCategories).Get([]byte(category.ID.String())) != nil {
+			return domain.ErrCategoryAlreadyExists
+		}
+		if err := checkBoltCategoryName(tx, category); err != nil {
+			return err
+		}
+		return putBoltCategory(tx, category)
+	})
+	if err != nil {
+		if err == domain.ErrCategoryAlreadyExists {
+			s.logger.Warn("Category already exists", "id", category.ID, "name", category.Name)
+			return err
+		}
+		s.logger.Error("Error inserting category", "error", err, "id", category.ID)
+		return fmt.Errorf("error inserting category: %w", err)
+	}
+
+	s.logger.Info("Category added successfully", "id", category.ID)
+	return nil
+}
+
+// GetCategoryByID retrieves a category by its ID
+func (s *BoltService) GetCategoryByID(id string) (*domain.Category, error) {
+	s.logger.Debug("Getting category by ID", "id", id)
+
+	var category *domain.Category
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		category, err = getBoltCategory(tx, []byte(id))
+		return err
+	})
+	if err != nil {
+		if err == domain.ErrCategoryNotFound {
+			s.logger.Warn("Category not found", "id", id)
+			return nil, err
+		}
+		s.logger.Error("Error reading category", "error", err, "id", id)
+		return nil, fmt.Errorf("error reading category: %w", err)
+	}
+
+	return category, nil
+}
+
+// GetAllCategories retrieves all categories ordered by their sort order and name
+func (s *BoltService) GetAllCategories() ([]*domain.Category, error) {
+	s.logger.Debug("Getting all categories")
+
+	var categories []*domain.Category
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		categories, err = getBoltCategories(tx)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Error reading all categories", "error", err)
+		return nil, fmt.Errorf("error reading categories: %w", err)
+	}
+	domain.SortCategories(categories)
+
+	s.logger.Info("Retrieved all categories", "count", len(categories))
+	return categories, nil
+}
+
+// UpdateCategory updates an existing category
+func (s *BoltService) UpdateCategory(category *domain.Category) error {
+	s.logger.Debug("Updating category", "id", category.ID, "name", category.Name)
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		if _, err := getBoltCategory(tx, []byte(category.ID.String())); err != nil {
+			return err
+		}
+		if err := checkBoltCategoryName(tx, category); err != nil {
+			return err
+		}
+		return putBoltCategory(tx, category)
+	})
+	if err != nil {
+		if err == domain.ErrCategoryNotFound || err == domain.ErrCategoryAlreadyExists {
+			s.logger.Warn("Category not updated", "id", category.ID, "name", category.Name, "error", err)
+			return err
+		}
+		s.logger.Error("Error updating category", "error", err, "id", category.ID)
+		return fmt.Errorf("error updating category: %w", err)
+	}
+
+	s.logger.Info("Category updated successfully", "id", category.ID)
+	return nil
+}
+
+// ReorderCategories renumbers the sort order of all categories in one transaction
+func (s *BoltService) ReorderCategories(ids []uuid.UUID) error {
+	s.logger.Debug("Reordering categories", "count", len(ids))
+
+	count := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		categories, err := getBoltCategories(tx)
+		if err != nil {
+			return err
+		}
+		if err := domain.OrderCategories(categories, ids); err != nil {
+			return err
+		}
+		for _, category := range categories {
+			if err := putBoltCategory(tx, category); err != nil {
+				return err
+			}
+		}
+		count = len(categories)
+		return nil
+	})
+	if err != nil {
+		s.logger.Warn("Categories not reordered", "error", err)
+		return err
+	}
+
+	s.logger.Info("Categories reordered successfully", "count", count)
+	return nil
+}
+
+// MergeCategory moves the expenditures and subcategories of source over to target and deletes
+// source in one transaction. A dry run counts them without writing anything
+func (s *BoltService) MergeCategory(source, target uuid.UUID, dryRun bool) (*domain.CategoryMerge, error) {
+	s.logger.Debug("Merging category", "source_id", source, "target_id", target, "dry_run", dryRun)
+
+	merge := &domain.CategoryMerge{SourceID: source, TargetID: target, DryRun: dryRun}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		categories, err := getBoltCategories(tx)
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for _, category := range categories {
+			found = found || category.ID == source
+		}
+		if !found {
+			return domain.ErrCategoryNotFound
+		}
+		if err := domain.CheckCategoryMerge(categories, source, target); err != nil {
+			return err
+		}
+
+		// The category index lists the expenditures of source without reading the others
+		var ids [][]byte
+		prefix := []byte(source.String())
+		c := tx.Bucket(boltExpendituresByCat).Cursor()
+		for key, _ := c.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, _ = c.Next() {
+			ids = append(ids, append([]byte(nil), key[len(key)-36:]...))
+		}
+		merge.Expenditures = len(ids)
+		for _, category := range categories {
+			if category.ParentID == source {
+				merge.Subcategories++
+			}
+		}
+		if dryRun {
+			return nil
+		}
+
+		for _, id := range ids {
+			expenditure, err := getBoltExpenditure(tx, id)
+			if err != nil {
+				return err
+			}
+			if err := deleteBoltIndexes(tx, expenditure); err != nil {
+				return err
+			}
+			expenditure.CategoryId = target
+			if err := putBoltExpenditure(tx, expenditure); err != nil {
+				return err
+			}
+		}
+		for _, category := range categories {
+			if category.ParentID == source {
+				category.ParentID = target
+				if err := putBoltCategory(tx, category); err != nil {
+					return err
+				}
+			}
+		}
+		return tx.Bucket(boltCategories).Delete([]byte(source.String()))
+	})
+	if err != nil {
+		if err == domain.ErrCategoryNotFound || err == domain.ErrCategoryMergeIntoSubcategory {
+			s.logger.Warn("Invalid category merge", "source_id", source, "target_id", target, "error", err)
+			return nil, err
+		}
+		s.logger.Error("Error merging category", "error", err, "source_id", source)
+		return nil, fmt.Errorf("error merging category: %w", err)
+	}
+
+	s.logger.Info("Category merged successfully", "source_id", source, "target_id", target, "expenditures", merge.Expenditures, "dry_run", dryRun)
+	return merge, nil
+}
+
+func getBoltCategory(tx *bolt.Tx, id []byte) (*domain.Category, error) {
+	value := tx.Bucket(boltCategories).Get(id)
+	if value == nil {
+		return nil, domain.ErrCategoryNotFound
+	}
+
+	var category domain.Category
+	if err := json.Unmarshal(value, &category); err != nil {
+		return nil, fmt.Errorf("error decoding category: %w", err)
+	}
+	return &category, nil
+}
+
+func getBoltCategories(tx *bolt.Tx) ([]*domain.Category, error) {
+	var categories []*domain.Category
+	err := tx.Bucket(boltCategories).ForEach(func(_, value []byte) error {
+		var category domain.Category
+		if err := json.Unmarshal(value, &category); err != nil {
+			return fmt.Errorf("error decoding category: %w", err)
+		}
+		categories = append(categories, &category)
+		return nil
+	})
+	return categories, err
+}
+
+func putBoltCategory(tx *bolt.Tx, category *domain.Category) error {
+	value, err := json.Marshal(category)
+	if err != nil {
+		return fmt.Errorf("error encoding category: %w", err)
+	}
+	return tx.Bucket(boltCategories).Put([]byte(category.ID.String()), value)
+}
+
+// checkBoltCategoryName fails when another category has the name, ignoring case like the
+// memory storage
+func checkBoltCategoryName(tx *bolt.Tx, category *domain.Category) error {
+	categories, err := getBoltCategories(tx)
+	if err != nil {
+		return err
+	}
+	for _, other := range categories {
+		if other.ID != category.ID && strings.EqualFold(other.Name, category.Name) {
+			return domain.ErrCategoryAlreadyExists
+		}
+	}
+	return nil
+}
@@ -0,0 +1,442 @@
+//go:build bolt
+
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/storage"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Buckets of the bolt storage. Expenditures and categories are stored as JSON by ID; the index
+// buckets hold empty values under keys that sort expenditures by date, optionally per category
+var (
+	boltExpenditures       = []byte("expenditures")
+	boltExpendituresByDate = []byte("expenditures_by_date")     // date key + ID
+	boltExpendituresByCat  = []byte("expenditures_by_category") // category ID + date key + ID
+	boltCategories         = []byte("categories")
+)
+
+func init() {
+	storage.Register("bolt", openBolt)
+}
+
+// openBolt opens the bolt storage kept in the data directory given as DSN
+func openBolt(dsn string, logger *slog.Logger) (domain.ExpenditureRepository, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("the bolt storage needs a data directory, set -data-dir")
+	}
+
+	logger.Info("Using bolt database for storage", "data_dir", dsn)
+	boltService, err := NewBoltService(dsn, logger)
+	if err != nil {
+		return nil, err
+	}
+	return boltService, nil
+}
+
+// BoltService implements the ExpenditureRepository and CategoryRepository interfaces with an
+// embedded bbolt database, for single-binary deployments without a database server
+type BoltService struct {
+	db     *bolt.DB
+	logger *slog.Logger
+}
+
+// NewBoltService opens or creates the database in dataDir and seeds the default categories
+func NewBoltService(dataDir string, logger *slog.Logger) (*BoltService, error) {
+	if err := os.MkdirAll(dataDir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	// Another process holding the file blocks opening it, so give up after a while
+	db, err := bolt.Open(filepath.Join(dataDir, "expenses.db"), 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt database: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{boltExpenditures, boltExpendituresByDate, boltExpendituresByCat, boltCategories} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return seedBoltCategories(tx)
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to set up bolt database: %w", err)
+	}
+
+	return &BoltService{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+// Close closes the database file
+func (s *BoltService) Close() error {
+	return s.db.Close()
+}
+
+// AddExpenditure adds a new expenditure together with its index entries
+func (s *BoltService) AddExpenditure(expenditure *domain.Expenditure) error {
+	s.logger.Debug("Adding expenditure to bolt", "id", expenditure.ID, "description", expenditure.Description, "amount", expenditure.Amount)
+
+	// Bolt runs one write transaction at a time, so refunds are summed one after the other
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		if expenditure.IsRefund() {
+			original, err := getBoltExpenditure(tx, []byte(expenditure.RefundOf.String()))
+			if err != nil {
+				return err
+			}
+			refunded, err := boltRefunded(tx, original.ID)
+			if err != nil {
+				return err
+			}
+			if !domain.RefundsWithin(original.Amount, refunded-expenditure.Amount) {
+				return domain.ErrRefundExceedsOriginal
+			}
+		}
+		return putNewBoltExpenditure(tx, expenditure)
+	})
+	if err != nil {
+		switch err {
+		case domain.ErrExpenditureAlreadyExists, domain.ErrExpenditureNotFound, domain.ErrRefundExceedsOriginal:
+			s.logger.Warn("Expenditure rejected", "error", err, "id", expenditure.ID)
+			return err
+		}
+		s.logger.Error("Error inserting expenditure", "error", err, "id", expenditure.ID)
+		return fmt.Errorf("error inserting expenditure: %w", err)
+	}
+
+	s.logger.Info("Expenditure added successfully", "id", expenditure.ID)
+	return nil
+}
+
+// AddExpenditures adds the expenditures in one transaction, none of them when one exists already
+func (s *BoltService) AddExpenditures(expenditures []*domain.Expenditure) error {
+	s.logger.Debug("Adding expenditures in bulk to bolt", "count", len(expenditures))
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for _, expenditure := range expenditures {
+			if err := putNewBoltExpenditure(tx, expenditure); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if err == domain.ErrExpenditureAlreadyExists {
+			s.logger.Warn("Expenditure already exists in bulk insert")
+			return err
+		}
+		s.logger.Error("Error inserting expenditures", "error", err)
+		return fmt.Errorf("error inserting expenditures: %w", err)
+	}
+
+	s.logger.Info("Expenditures added successfully", "count", len(expenditures))
+	return nil
+}
+
+// GetExpenditureByID retrieves an expenditure by its ID
+func (s *BoltService) GetExpenditureByID(id string) (*domain.Expenditure, error) {
+	s.logger.Debug("Getting expenditure by ID", "id", id)
+
+	var expenditure *domain.Expenditure
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		expenditure, err = getBoltExpenditure(tx, []byte(id))
+		return err
+	})
+	if err != nil {
+		if err == domain.ErrExpenditureNotFound {
+			s.logger.Warn("Expenditure not found", "id", id)
+			return nil, err
+		}
+		s.logger.Error("Error reading expenditure", "error", err, "id", id)
+		return nil, fmt.Errorf("error reading expenditure: %w", err)
+	}
+
+	return expenditure, nil
+}
+
+// GetAllExpenditures retrieves all expenditures
+func (s *BoltService) GetAllExpenditures() ([]*domain.Expenditure, error) {
+	s.logger.Debug("Getting all expenditures")
+
+	var expenditures []*domain.Expenditure
+	err := s.StreamExpenditures(func(expenditure *domain.Expenditure) error {
+		expenditures = append(expenditures, expenditure)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Retrieved all expenditures", "count", len(expenditures))
+	return expenditures, nil
+}
+
+// StreamExpenditures passes the expenditures to fn one at a time within one read transaction
+func (s *BoltService) StreamExpenditures(fn func(*domain.Expenditure) error) error {
+	s.logger.Debug("Streaming all expenditures")
+
+	return s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltExpenditures).ForEach(func(_, value []byte) error {
+			var expenditure domain.Expenditure
+			if err := json.Unmarshal(value, &expenditure); err != nil {
+				return fmt.Errorf("error decoding expenditure: %w", err)
+			}
+			return fn(&expenditure)
+		})
+	})
+}
+
+// CountExpenditures counts the expenditures dated within [from, to) on the date index
+func (s *BoltService) CountExpenditures(from, to time.Time) (int, error) {
+	s.logger.Debug("Counting expenditures", "from", from, "to", to)
+
+	count := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(boltExpendituresByDate).Cursor()
+
+		var key []byte
+		if from.IsZero() {
+			key, _ = c.First()
+		} else {
+			key, _ = c.Seek(boltDateKey(from))
+		}
+		var end []byte
+		if !to.IsZero() {
+			end = boltDateKey(to)
+		}
+
+		for ; key != nil && (end == nil || bytes.Compare(key, end) < 0); key, _ = c.Next() {
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Error counting expenditures", "error", err)
+		return 0, fmt.Errorf("error counting expenditures: %w", err)
+	}
+	return count, nil
+}
+
+// GetExpenditurePage walks the date index, or the category index when filtering by category,
+// backwards from the end of the page range, reading only the expenditures on the page
+func (s *BoltService) GetExpenditurePage(query domain.ExpenditurePageQuery) ([]*domain.Expenditure, error) {
+	s.logger.Debug("Getting expenditure page", "from", query.From, "to", query.To, "category_id", query.CategoryId, "limit", query.Limit)
+
+	// Keys of the category index start with the category, those of the date index do not
+	var prefix []byte
+	bucket := boltExpendituresByDate
+	if query.CategoryId != uuid.Nil {
+		prefix = []byte(query.CategoryId.String())
+		bucket = boltExpendituresByCat
+	}
+
+	// The page ends before the upper date bound and before the cursor, whichever comes first
+	end := append(bytes.Clone(prefix), 0xff)
+	if !query.To.IsZero() {
+		end = append(bytes.Clone(prefix), boltDateKey(query.To)...)
+	}
+	if query.After != nil {
+		after := append(append(bytes.Clone(prefix), boltDateKey(query.After.Date)...), query.After.ID.String()...)
+		if bytes.Compare(after, end) < 0 {
+			end = after
+		}
+	}
+	start := bytes.Clone(prefix)
+	if !query.From.IsZero() {
+		start = append(start, boltDateKey(query.From)...)
+	}
+
+	var expenditures []*domain.Expenditure
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucket).Cursor()
+
+		key, _ := c.Seek(end)
+		if key == nil {
+			key, _ = c.Last()
+		} else {
+			key, _ = c.Prev()
+		}
+
+		for ; key != nil && len(expenditures) < query.Limit; key, _ = c.Prev() {
+			if bytes.Compare(key, start) < 0 || !bytes.HasPrefix(key, prefix) {
+				break
+			}
+			expenditure, err := getBoltExpenditure(tx, key[len(key)-36:])
+			if err != nil {
+				return err
+			}
+			expenditures = append(expenditures, expenditure)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Error reading expenditure page", "error", err)
+		return nil, fmt.Errorf("error reading expenditure page: %w", err)
+	}
+
+	s.logger.Info("Retrieved expenditure page", "count", len(expenditures))
+	return expenditures, nil
+}
+
+// UpdateExpenditure updates an existing expenditure and moves its index entries
+func (s *BoltService) UpdateExpenditure(expenditure *domain.Expenditure) error {
+	s.logger.Debug("Updating expenditure", "id", expenditure.ID, "description", expenditure.Description, "amount", expenditure.Amount)
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		old, err := getBoltExpenditure(tx, []byte(expenditure.ID.String()))
+		if err != nil {
+			return err
+		}
+		refunded, err := boltRefunded(tx, expenditure.ID)
+		if err != nil {
+			return err
+		}
+		if !domain.RefundsWithin(expenditure.Amount, refunded) {
+			return domain.ErrAmountBelowRefunded
+		}
+		if err := deleteBoltIndexes(tx, old); err != nil {
+			return err
+		}
+		return putBoltExpenditure(tx, expenditure)
+	})
+	if err != nil {
+		switch err {
+		case domain.ErrExpenditureNotFound, domain.ErrAmountBelowRefunded:
+			s.logger.Warn("Expenditure not updated", "error", err, "id", expenditure.ID)
+			return err
+		}
+		s.logger.Error("Error updating expenditure", "error", err, "id", expenditure.ID)
+		return fmt.Errorf("error updating expenditure: %w", err)
+	}
+
+	s.logger.Info("Expenditure updated successfully", "id", expenditure.ID)
+	return nil
+}
+
+// DeleteExpenditure deletes an expenditure and its index entries
+func (s *BoltService) DeleteExpenditure(id string) error {
+	s.logger.Debug("Deleting expenditure", "id", id)
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		old, err := getBoltExpenditure(tx, []byte(id))
+		if err != nil {
+			return err
+		}
+		refunded, err := boltRefunded(tx, old.ID)
+		if err != nil {
+			return err
+		}
+		if refunded != 0 {
+			return domain.ErrExpenditureHasRefunds
+		}
+		if err := deleteBoltIndexes(tx, old); err != nil {
+			return err
+		}
+		return tx.Bucket(boltExpenditures).Delete([]byte(id))
+	})
+	if err != nil {
+		switch err {
+		case domain.ErrExpenditureNotFound, domain.ErrExpenditureHasRefunds:
+			s.logger.Warn("Expenditure not deleted", "error", err, "id", id)
+			return err
+		}
+		s.logger.Error("Error deleting expenditure", "error", err, "id", id)
+		return fmt.Errorf("error deleting expenditure: %w", err)
+	}
+
+	s.logger.Info("Expenditure deleted successfully", "id", id)
+	return nil
+}
+
+// boltDateKey encodes a date so that keys sort by date: nanoseconds since the epoch as a big
+// endian integer with the sign bit flipped, so dates before 1970 sort first too
+func boltDateKey(date time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(date.UnixNano())^(1<<63))
+	return key
+}
+
+// boltIndexKeys returns the keys of an expenditure in the date and category index
+func boltIndexKeys(expenditure *domain.Expenditure) ([]byte, []byte) {
+	id := expenditure.ID.String()
+	byDate := append(boltDateKey(expenditure.Date), id...)
+	byCategory := append([]byte(expenditure.CategoryId.String()), byDate...)
+	return byDate, byCategory
+}
+
+func getBoltExpenditure(tx *bolt.Tx, id []byte) (*domain.Expenditure, error) {
+	value := tx.Bucket(boltExpenditures).Get(id)
+	if value == nil {
+		return nil, domain.ErrExpenditureNotFound
+	}
+
+	var expenditure domain.Expenditure
+	if err := json.Unmarshal(value, &expenditure); err != nil {
+		return nil, fmt.Errorf("error decoding expenditure: %w", err)
+	}
+	return &expenditure, nil
+}
+
+// boltRefunded returns the positive sum of the refunds of an expenditure
+func boltRefunded(tx *bolt.Tx, id uuid.UUID) (float64, error) {
+	var refunded float64
+	err := tx.Bucket(boltExpenditures).ForEach(func(_, value []byte) error {
+		var expenditure domain.Expenditure
+		if err := json.Unmarshal(value, &expenditure); err != nil {
+			return fmt.Errorf("error decoding expenditure: %w", err)
+		}
+		if expenditure.RefundOf == id {
+			refunded -= expenditure.Amount
+		}
+		return nil
+	})
+	return refunded, err
+}
+
+func putNewBoltExpenditure(tx *bolt.Tx, expenditure *domain.Expenditure) error {
+	if tx.Bucket(boltExpenditures).Get([]byte(expenditure.ID.String())) != nil {
+		return domain.ErrExpenditureAlreadyExists
+	}
+	return putBoltExpenditure(tx, expenditure)
+}
+
+// putBoltExpenditure writes the expenditure and its index entries
+func putBoltExpenditure(tx *bolt.Tx, expenditure *domain.Expenditure) error {
+	value, err := json.Marshal(expenditure)
+	if err != nil {
+		return fmt.Errorf("error encoding expenditure: %w", err)
+	}
+	if err := tx.Bucket(boltExpenditures).Put([]byte(expenditure.ID.String()), value); err != nil {
+		return err
+	}
+
+	byDate, byCategory := boltIndexKeys(expenditure)
+	if err := tx.Bucket(boltExpendituresByDate).Put(byDate, []byte{}); err != nil {
+		return err
+	}
+	return tx.Bucket(boltExpendituresByCat).Put(byCategory, []byte{})
+}
+
+func deleteBoltIndexes(tx *bolt.Tx, expenditure *domain.Expenditure) error {
+	byDate, byCategory := boltIndexKeys(expenditure)
+	if err := tx.Bucket(boltExpendituresByDate).Delete(byDate); err != nil {
+		return err
+	}
+	return tx.Bucket(boltExpendituresByCat).Delete(byCategory)
+}
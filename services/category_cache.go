@@ -0,0 +1,126 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"log/slog"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// CategoryCache keeps the categories of another CategoryRepository in memory. Categories change
+// rarely but are read on every page load, so any update simply drops the whole cache
+type CategoryCache struct {
+	inner      domain.CategoryRepository
+	categories map[string]domain.Category // nil until loaded
+	logger     *slog.Logger
+	mu         sync.RWMutex
+}
+
+// NewCategoryCache creates a new CategoryCache in front of inner
+func NewCategoryCache(inner domain.CategoryRepository, logger *slog.Logger) *CategoryCache {
+	return &CategoryCache{
+		inner:  inner,
+		logger: logger,
+	}
+}
+
+// GetCategoryByID returns a copy of the cached category, so callers may change it freely
+func (c *CategoryCache) GetCategoryByID(id string) (*domain.Category, error) {
+	categories, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	category, ok := categories[id]
+	if !ok {
+		return nil, domain.ErrCategoryNotFound
+	}
+	return &category, nil
+}
+
+// GetAllCategories returns copies of the cached categories
+func (c *CategoryCache) GetAllCategories() ([]*domain.Category, error) {
+	categories, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	all := make([]*domain.Category, 0, len(categories))
+	for _, category := range categories {
+		category := category
+		all = append(all, &category)
+	}
+	return all, nil
+}
+
+// AddCategory adds the category to the underlying storage and drops the cache
+func (c *CategoryCache) AddCategory(category *domain.Category) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.categories = nil
+	c.logger.Debug("Invalidated category cache", "id", category.ID)
+	return c.inner.AddCategory(category)
+}
+
+// UpdateCategory updates the category in the underlying storage and drops the cache
+func (c *CategoryCache) UpdateCategory(category *domain.Category) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.categories = nil
+	c.logger.Debug("Invalidated category cache", "id", category.ID)
+	return c.inner.UpdateCategory(category)
+}
+
+// ReorderCategories reorders the categories in the underlying storage and drops the cache
+func (c *CategoryCache) ReorderCategories(ids []uuid.UUID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.categories = nil
+	c.logger.Debug("Invalidated category cache", "count", len(ids))
+	return c.inner.ReorderCategories(ids)
+}
+
+// MergeCategory merges the categories in the underlying storage and drops the cache
+func (c *CategoryCache) MergeCategory(source, target uuid.UUID, dryRun bool) (*domain.CategoryMerge, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !dryRun {
+		c.categories = nil
+		c.logger.Debug("Invalidated category cache", "id", source)
+	}
+	return c.inner.MergeCategory(source, target, dryRun)
+}
+
+func (c *CategoryCache) load() (map[string]domain.Category, error) {
+	c.mu.RLock()
+	categories := c.categories
+	c.mu.RUnlock()
+	if categories != nil {
+		return categories, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.categories != nil {
+		return c.categories, nil
+	}
+
+	all, err := c.inner.GetAllCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	categories = make(map[string]domain.Category, len(all))
+	for _, category := range all {
+		categories[category.ID.String()] = *category
+	}
+	c.categories = categories
+
+	c.logger.Debug("Loaded category cache", "count", len(categories))
+	return categories, nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"database/sql"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/storage"
+	"go-expense-tracker/storagetest"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"testing"
+)
+
+// Run with: go test ./services -run TestConformance
+// Every storage backend runs the same checks of storagetest. PostgreSQL runs when
+// BENCHMARK_DB_NAME names a scratch database as for the benchmarks, MySQL when MYSQL_TEST_DSN
+// names one. This file builds without tags, but the MySQL driver is only linked in with
+// -tags mysql, so the MySQL run is skipped without it. bolt runs in a temporary directory when
+// the tests are built with -tags bolt
+func TestConformance(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		storagetest.CheckConformance(t, NewMemoryService(slog.New(slog.DiscardHandler)))
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		storagetest.CheckConformance(t, openScratchDB(t))
+	})
+
+	t.Run("mysql", func(t *testing.T) {
+		storagetest.CheckConformance(t, openScratchMySQL(t))
+	})
+
+	t.Run("bolt", func(t *testing.T) {
+		storagetest.CheckConformance(t, openScratchBolt(t))
+	})
+}
+
+// openScratchBolt opens a bolt database in a temporary directory, skipping the test when the
+// storage is not linked in
+func openScratchBolt(tb testing.TB) domain.ExpenditureRepository {
+	if !slices.Contains(storage.Drivers(), "bolt") {
+		tb.Skip("the bolt storage is only linked in with -tags bolt")
+	}
+
+	repo, err := storage.Open("bolt", tb.TempDir(), slog.New(slog.DiscardHandler))
+	if err != nil {
+		tb.Fatalf("opening scratch database: %v", err)
+	}
+	tb.Cleanup(func() {
+		repo.(io.Closer).Close()
+	})
+	return repo
+}
+
+// openScratchMySQL connects to the scratch database named by MYSQL_TEST_DSN, skipping the test
+// when it is not set or the driver is not linked in
+func openScratchMySQL(tb testing.TB) *MySQLService {
+	dsn := os.Getenv("MYSQL_TEST_DSN")
+	if dsn == "" {
+		tb.Skip("MYSQL_TEST_DSN is not set")
+	}
+	if !slices.Contains(sql.Drivers(), "mysql") {
+		tb.Skip("the MySQL driver is only linked in with -tags mysql")
+	}
+
+	service, err := NewMySQLService(dsn, slog.New(slog.DiscardHandler))
+	if err != nil {
+		tb.Fatalf("connecting to scratch database: %v", err)
+	}
+	tb.Cleanup(func() {
+		service.Close()
+	})
+	return service
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+)
+
+const accountColumns = "id, name, opening_balance, reconciled_through, reconciled_balance, created_at"
+
+// setupAccounts creates the accounts table
+func setupAccounts(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS accounts (
+			id UUID PRIMARY KEY,
+			name TEXT NOT NULL,
+			opening_balance DECIMAL(14, 2) NOT NULL DEFAULT 0,
+			reconciled_through DATE,
+			reconciled_balance DECIMAL(14, 2) NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create accounts table: %w", err)
+	}
+	return nil
+}
+
+// AddAccount adds a new account to the database
+func (s *DBService) AddAccount(account *domain.Account) error {
+	s.logger.Debug("Adding account to database", "id", account.ID, "name", account.Name)
+
+	_, err := s.db.Exec(
+		"INSERT INTO accounts ("+accountColumns+") VALUES ($1, $2, $3, $4, $5, $6)",
+		account.ID, account.Name, account.OpeningBalance, account.ReconciledThrough, account.ReconciledBalance, account.CreatedAt,
+	)
+	if err != nil {
+		s.logger.Error("Error inserting account", "error", err, "id", account.ID)
+		return fmt.Errorf("error inserting account: %w", err)
+	}
+
+	s.logger.Info("Account added successfully", "id", account.ID)
+	return nil
+}
+
+// GetAccountByID retrieves an account by its ID
+func (s *DBService) GetAccountByID(id string) (*domain.Account, error) {
+	s.logger.Debug("Getting account by ID", "id", id)
+
+	accountID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	account, err := scanAccount(s.db.QueryRow("SELECT "+accountColumns+" FROM accounts WHERE id = $1", accountID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Account not found", "id", id)
+			return nil, domain.ErrAccountNotFound
+		}
+		s.logger.Error("Error querying account", "error", err, "id", id)
+		return nil, fmt.Errorf("error querying account: %w", err)
+	}
+
+	return account, nil
+}
+
+// GetAllAccounts retrieves all accounts in the order they were created
+func (s *DBService) GetAllAccounts() ([]*domain.Account, error) {
+	s.logger.Debug("Getting all accounts")
+
+	rows, err := s.db.Query("SELECT " + accountColumns + " FROM accounts ORDER BY created_at")
+	if err != nil {
+		s.logger.Error("Error querying all accounts", "error", err)
+		return nil, fmt.Errorf("error querying all accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []*domain.Account
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			s.logger.Error("Error scanning account row", "error", err)
+			return nil, fmt.Errorf("error scanning account row: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating account rows", "error", err)
+		return nil, fmt.Errorf("error iterating account rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved all accounts", "count", len(accounts))
+	return accounts, nil
+}
+
+// UpdateAccount updates an existing account
+func (s *DBService) UpdateAccount(account *domain.Account) error {
+	s.logger.Debug("Updating account", "id", account.ID, "name", account.Name)
+
+	result, err := s.db.Exec(
+		`UPDATE accounts SET name = $1, opening_balance = $2, reconciled_through = $3, reconciled_balance = $4 WHERE id = $5`,
+		account.Name, account.OpeningBalance, account.ReconciledThrough, account.ReconciledBalance, account.ID,
+	)
+	if err != nil {
+		s.logger.Error("Error updating account", "error", err, "id", account.ID)
+		return fmt.Errorf("error updating account: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Account not found for update", "id", account.ID)
+		return domain.ErrAccountNotFound
+	}
+
+	s.logger.Info("Account updated successfully", "id", account.ID)
+	return nil
+}
+
+func scanAccount(row rowScanner) (*domain.Account, error) {
+	var account domain.Account
+	var reconciledThrough sql.NullTime
+	err := row.Scan(&account.ID, &account.Name, &account.OpeningBalance, &reconciledThrough, &account.ReconciledBalance, &account.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if reconciledThrough.Valid {
+		account.ReconciledThrough = &reconciledThrough.Time
+	}
+	return &account, nil
+}
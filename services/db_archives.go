@@ -0,0 +1,275 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const archiveColumns = "id, cutoff, count, total, summaries, created_at"
+
+// archivedExpenditures selects the expenditures dated before $1 and the refunds of those
+const archivedExpenditures = "date < $1 OR refund_of IN (SELECT id FROM expenditures WHERE date < $1)"
+
+// ArchiveExpenditures moves the expenditures dated before the cutoff into a new archive in one transaction
+func (s *DBService) ArchiveExpenditures(before time.Time) (*domain.Archive, error) {
+	s.logger.Debug("Archiving expenditures", "before", before)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("Error starting archive transaction", "error", err)
+		return nil, fmt.Errorf("error starting archive transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Refunds dated later go with their originals, which they reference
+	rows, err := tx.Query("SELECT "+expenditureColumns+" FROM expenditures WHERE "+archivedExpenditures+" ORDER BY date FOR UPDATE", before)
+	if err != nil {
+		s.logger.Error("Error querying expenditures to archive", "error", err)
+		return nil, fmt.Errorf("error querying expenditures to archive: %w", err)
+	}
+
+	var expenditures []*domain.Expenditure
+	for rows.Next() {
+		expenditure, err := scanExpenditure(rows)
+		if err != nil {
+			rows.Close()
+			s.logger.Error("Error scanning expenditure row", "error", err)
+			return nil, fmt.Errorf("error scanning expenditure row: %w", err)
+		}
+		expenditures = append(expenditures, expenditure)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating expenditure rows", "error", err)
+		return nil, fmt.Errorf("error iterating expenditure rows: %w", err)
+	}
+
+	if len(expenditures) == 0 {
+		s.logger.Info("No expenditures to archive", "before", before)
+		return nil, domain.ErrNothingToArchive
+	}
+
+	archive := domain.NewArchive(before, expenditures)
+	summaries, err := json.Marshal(archive.Summaries)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding archive summaries: %w", err)
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO archives ("+archiveColumns+") VALUES ($1, $2, $3, $4, $5, $6)",
+		archive.ID, archive.Before, archive.Count, archive.Total, summaries, archive.CreatedAt,
+	)
+	if err != nil {
+		s.logger.Error("Error inserting archive", "error", err, "id", archive.ID)
+		return nil, fmt.Errorf("error inserting archive: %w", err)
+	}
+
+	for _, expenditure := range expenditures {
+		data, err := json.Marshal(expenditure)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding archived expenditure: %w", err)
+		}
+
+		_, err = tx.Exec(
+			"INSERT INTO archived_expenditures (id, archive_id, date, data) VALUES ($1, $2, $3, $4)",
+			expenditure.ID, archive.ID, expenditure.Date, data,
+		)
+		if err != nil {
+			s.logger.Error("Error inserting archived expenditure", "error", err, "id", expenditure.ID)
+			return nil, fmt.Errorf("error inserting archived expenditure: %w", err)
+		}
+	}
+
+	_, err = tx.Exec("DELETE FROM expenditures WHERE "+archivedExpenditures, before)
+	if err != nil {
+		s.logger.Error("Error deleting archived expenditures", "error", err)
+		return nil, fmt.Errorf("error deleting archived expenditures: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.logger.Error("Error committing archive transaction", "error", err, "id", archive.ID)
+		return nil, fmt.Errorf("error committing archive transaction: %w", err)
+	}
+
+	s.logger.Info("Expenditures archived successfully", "id", archive.ID, "count", archive.Count)
+	return archive, nil
+}
+
+// GetArchiveByID retrieves an archive by its ID
+func (s *DBService) GetArchiveByID(id string) (*domain.Archive, error) {
+	s.logger.Debug("Getting archive by ID", "id", id)
+
+	archiveID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	archive, err := scanArchive(s.db.QueryRow("SELECT "+archiveColumns+" FROM archives WHERE id = $1", archiveID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Archive not found", "id", id)
+			return nil, domain.ErrArchiveNotFound
+		}
+		s.logger.Error("Error querying archive", "error", err, "id", id)
+		return nil, fmt.Errorf("error querying archive: %w", err)
+	}
+
+	return archive, nil
+}
+
+// GetAllArchives retrieves all archives, oldest first
+func (s *DBService) GetAllArchives() ([]*domain.Archive, error) {
+	s.logger.Debug("Getting all archives")
+
+	rows, err := s.db.Query("SELECT " + archiveColumns + " FROM archives ORDER BY created_at")
+	if err != nil {
+		s.logger.Error("Error querying all archives", "error", err)
+		return nil, fmt.Errorf("error querying all archives: %w", err)
+	}
+	defer rows.Close()
+
+	archives := make([]*domain.Archive, 0)
+	for rows.Next() {
+		archive, err := scanArchive(rows)
+		if err != nil {
+			s.logger.Error("Error scanning archive row", "error", err)
+			return nil, fmt.Errorf("error scanning archive row: %w", err)
+		}
+		archives = append(archives, archive)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating archive rows", "error", err)
+		return nil, fmt.Errorf("error iterating archive rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved all archives", "count", len(archives))
+	return archives, nil
+}
+
+// GetArchivedExpenditures retrieves the expenditures moved into an archive, oldest first
+func (s *DBService) GetArchivedExpenditures(archiveID string) ([]*domain.Expenditure, error) {
+	s.logger.Debug("Getting archived expenditures", "archive_id", archiveID)
+
+	// Distinguish an unknown archive from an empty one
+	archive, err := s.GetArchiveByID(archiveID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query("SELECT data FROM archived_expenditures WHERE archive_id = $1 ORDER BY date", archive.ID)
+	if err != nil {
+		s.logger.Error("Error querying archived expenditures", "error", err, "archive_id", archiveID)
+		return nil, fmt.Errorf("error querying archived expenditures: %w", err)
+	}
+	defer rows.Close()
+
+	var expenditures []*domain.Expenditure
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			s.logger.Error("Error scanning archived expenditure row", "error", err)
+			return nil, fmt.Errorf("error scanning archived expenditure row: %w", err)
+		}
+
+		var expenditure domain.Expenditure
+		if err := json.Unmarshal(data, &expenditure); err != nil {
+			return nil, fmt.Errorf("error decoding archived expenditure: %w", err)
+		}
+		expenditures = append(expenditures, &expenditure)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating archived expenditure rows", "error", err)
+		return nil, fmt.Errorf("error iterating archived expenditure rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved archived expenditures", "archive_id", archiveID, "count", len(expenditures))
+	return expenditures, nil
+}
+
+func scanArchive(row rowScanner) (*domain.Archive, error) {
+	var archive domain.Archive
+	var summaries []byte
+
+	err := row.Scan(&archive.ID, &archive.Before, &archive.Count, &archive.Total, &summaries, &archive.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(summaries, &archive.Summaries); err != nil {
+		return nil, fmt.Errorf("error decoding archive summaries: %w", err)
+	}
+	return &archive, nil
+}
+
+// DeleteArchive deletes an archive with its expenditures in one transaction
+func (s *DBService) DeleteArchive(id string) error {
+	s.logger.Debug("Deleting archive from database", "id", id)
+
+	archiveID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("Error starting archive transaction", "error", err)
+		return fmt.Errorf("error starting archive transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec("DELETE FROM archived_expenditures WHERE archive_id = $1", archiveID); err != nil {
+		s.logger.Error("Error deleting archived expenditures", "error", err, "id", id)
+		return fmt.Errorf("error deleting archived expenditures: %w", err)
+	}
+
+	result, err := tx.Exec("DELETE FROM archives WHERE id = $1", archiveID)
+	if err != nil {
+		s.logger.Error("Error deleting archive", "error", err, "id", id)
+		return fmt.Errorf("error deleting archive: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Archive not found for deletion", "id", id)
+		return domain.ErrArchiveNotFound
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.logger.Error("Error committing archive deletion", "error", err, "id", id)
+		return fmt.Errorf("error committing archive deletion: %w", err)
+	}
+
+	s.logger.Info("Archive deleted successfully", "id", id)
+	return nil
+}
+
+// ScrubArchivedExpenditures removes the member from the archived expenditures they recorded, or
+// with erase deletes them
+func (s *DBService) ScrubArchivedExpenditures(member uuid.UUID, erase bool) (int, error) {
+	s.logger.Debug("Scrubbing archived expenditures in database", "member_id", member, "erase", erase)
+
+	if member == uuid.Nil {
+		return 0, nil
+	}
+	query := "UPDATE archived_expenditures SET data = data - 'created_by' WHERE data->>'created_by' = $1"
+	if erase {
+		query = "DELETE FROM archived_expenditures WHERE data->>'created_by' = $1"
+	}
+	result, err := s.db.Exec(query, member.String())
+	if err != nil {
+		s.logger.Error("Error scrubbing archived expenditures", "error", err, "member_id", member)
+		return 0, fmt.Errorf("error scrubbing archived expenditures: %w", err)
+	}
+	affected, _ := result.RowsAffected()
+
+	s.logger.Info("Archived expenditures scrubbed successfully", "member_id", member, "count", affected)
+	return int(affected), nil
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+)
+
+const bankConnectionColumns = "id, connector, name, access_token, cursor, last_synced_at, last_error, created_at"
+
+// AddBankConnection adds a new bank connection to the database
+func (s *DBService) AddBankConnection(connection *domain.BankConnection) error {
+	s.logger.Debug("Adding bank connection to database", "id", connection.ID, "connector", connection.Connector, "name", connection.Name)
+
+	_, err := s.db.Exec(
+		"INSERT INTO bank_connections ("+bankConnectionColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		connection.ID, connection.Connector, connection.Name, connection.AccessToken, connection.Cursor,
+		connection.LastSyncedAt, connection.LastError, connection.CreatedAt,
+	)
+	if err != nil {
+		s.logger.Error("Error inserting bank connection", "error", err, "id", connection.ID)
+		return fmt.Errorf("error inserting bank connection: %w", err)
+	}
+
+	s.logger.Info("Bank connection added successfully", "id", connection.ID)
+	return nil
+}
+
+// GetBankConnectionByID retrieves a bank connection by its ID
+func (s *DBService) GetBankConnectionByID(id string) (*domain.BankConnection, error) {
+	s.logger.Debug("Getting bank connection by ID", "id", id)
+
+	connectionID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	row := s.db.QueryRow("SELECT "+bankConnectionColumns+" FROM bank_connections WHERE id = $1", connectionID)
+	connection, err := scanBankConnection(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Bank connection not found", "id", id)
+			return nil, domain.ErrBankConnectionNotFound
+		}
+		s.logger.Error("Error querying bank connection", "error", err, "id", id)
+		return nil, fmt.Errorf("error querying bank connection: %w", err)
+	}
+
+	return connection, nil
+}
+
+// GetAllBankConnections retrieves all bank connections, oldest first
+func (s *DBService) GetAllBankConnections() ([]*domain.BankConnection, error) {
+	s.logger.Debug("Getting all bank connections")
+
+	rows, err := s.db.Query("SELECT " + bankConnectionColumns + " FROM bank_connections ORDER BY created_at")
+	if err != nil {
+		s.logger.Error("Error querying all bank connections", "error", err)
+		return nil, fmt.Errorf("error querying all bank connections: %w", err)
+	}
+	defer rows.Close()
+
+	var connections []*domain.BankConnection
+	for rows.Next() {
+		connection, err := scanBankConnection(rows)
+		if err != nil {
+			s.logger.Error("Error scanning bank connection row", "error", err)
+			return nil, fmt.Errorf("error scanning bank connection row: %w", err)
+		}
+		connections = append(connections, connection)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating bank connection rows", "error", err)
+		return nil, fmt.Errorf("error iterating bank connection rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved all bank connections", "count", len(connections))
+	return connections, nil
+}
+
+// UpdateBankConnection updates an existing bank connection
+func (s *DBService) UpdateBankConnection(connection *domain.BankConnection) error {
+	s.logger.Debug("Updating bank connection", "id", connection.ID)
+
+	result, err := s.db.Exec(
+		`UPDATE bank_connections SET name = $1, access_token = $2, cursor = $3, last_synced_at = $4,
+			last_error = $5 WHERE id = $6`,
+		connection.Name, connection.AccessToken, connection.Cursor, connection.LastSyncedAt,
+		connection.LastError, connection.ID,
+	)
+	if err != nil {
+		s.logger.Error("Error updating bank connection", "error", err, "id", connection.ID)
+		return fmt.Errorf("error updating bank connection: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Bank connection not found for update", "id", connection.ID)
+		return domain.ErrBankConnectionNotFound
+	}
+
+	s.logger.Info("Bank connection updated successfully", "id", connection.ID)
+	return nil
+}
+
+// DeleteBankConnection deletes a bank connection by its ID
+func (s *DBService) DeleteBankConnection(id string) error {
+	s.logger.Debug("Deleting bank connection", "id", id)
+
+	connectionID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	result, err := s.db.Exec("DELETE FROM bank_connections WHERE id = $1", connectionID)
+	if err != nil {
+		s.logger.Error("Error deleting bank connection", "error", err, "id", id)
+		return fmt.Errorf("error deleting bank connection: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Bank connection not found for deletion", "id", id)
+		return domain.ErrBankConnectionNotFound
+	}
+
+	s.logger.Info("Bank connection deleted successfully", "id", id)
+	return nil
+}
+
+func scanBankConnection(row rowScanner) (*domain.BankConnection, error) {
+	var connection domain.BankConnection
+	var lastSyncedAt sql.NullTime
+
+	err := row.Scan(&connection.ID, &connection.Connector, &connection.Name, &connection.AccessToken, &connection.Cursor,
+		&lastSyncedAt, &connection.LastError, &connection.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastSyncedAt.Valid {
+		connection.LastSyncedAt = &lastSyncedAt.Time
+	}
+	return &connection, nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+)
+
+const budgetColumns = "id, name, amount, period, category_id, start_date, end_date, rollover, created_at"
+
+// setupBudgets creates the budgets table; budgets on all spending have the nil category ID
+func setupBudgets(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS budgets (
+			id UUID PRIMARY KEY,
+			name TEXT NOT NULL,
+			amount DECIMAL(10, 2) NOT NULL,
+			period TEXT NOT NULL,
+			category_id UUID NOT NULL,
+			start_date DATE NOT NULL,
+			end_date DATE,
+			created_at TIMESTAMP NOT NULL
+		);
+
+		ALTER TABLE budgets ADD COLUMN IF NOT EXISTS rollover BOOLEAN NOT NULL DEFAULT FALSE
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create budgets table: %w", err)
+	}
+	return nil
+}
+
+// AddBudget adds a new budget to the database
+func (s *DBService) AddBudget(budget *domain.Budget) error {
+	s.logger.Debug("Adding budget to database", "id", budget.ID, "name", budget.Name, "amount", budget.Amount, "period", budget.Period)
+
+	_, err := s.db.Exec(
+		"INSERT INTO budgets ("+budgetColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+		budget.ID, budget.Name, budget.Amount, budget.Period, budget.CategoryId, budget.StartDate, budget.EndDate, budget.Rollover, budget.CreatedAt,
+	)
+	if err != nil {
+		s.logger.Error("Error inserting budget", "error", err, "id", budget.ID)
+		return fmt.Errorf("error inserting budget: %w", err)
+	}
+
+	s.logger.Info("Budget added successfully", "id", budget.ID)
+	return nil
+}
+
+// GetBudgetByID retrieves a budget by its ID
+func (s *DBService) GetBudgetByID(id string) (*domain.Budget, error) {
+	s.logger.Debug("Getting budget by ID", "id", id)
+
+	budgetID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	budget, err := scanBudget(s.db.QueryRow("SELECT "+budgetColumns+" FROM budgets WHERE id = $1", budgetID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Budget not found", "id", id)
+			return nil, domain.ErrBudgetNotFound
+		}
+		s.logger.Error("Error querying budget", "error", err, "id", id)
+		return nil, fmt.Errorf("error querying budget: %w", err)
+	}
+
+	return budget, nil
+}
+
+// GetAllBudgets retrieves all budgets ordered by name
+func (s *DBService) GetAllBudgets() ([]*domain.Budget, error) {
+	s.logger.Debug("Getting all budgets")
+
+	rows, err := s.db.Query("SELECT " + budgetColumns + " FROM budgets ORDER BY name")
+	if err != nil {
+		s.logger.Error("Error querying all budgets", "error", err)
+		return nil, fmt.Errorf("error querying all budgets: %w", err)
+	}
+	defer rows.Close()
+
+	var budgets []*domain.Budget
+	for rows.Next() {
+		budget, err := scanBudget(rows)
+		if err != nil {
+			s.logger.Error("Error scanning budget row", "error", err)
+			return nil, fmt.Errorf("error scanning budget row: %w", err)
+		}
+		budgets = append(budgets, budget)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating budget rows", "error", err)
+		return nil, fmt.Errorf("error iterating budget rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved all budgets", "count", len(budgets))
+	return budgets, nil
+}
+
+// UpdateBudget updates an existing budget
+func (s *DBService) UpdateBudget(budget *domain.Budget) error {
+	s.logger.Debug("Updating budget", "id", budget.ID, "name", budget.Name, "amount", budget.Amount)
+
+	result, err := s.db.Exec(
+		"UPDATE budgets SET name = $1, amount = $2, period = $3, category_id = $4, start_date = $5, end_date = $6, rollover = $7 WHERE id = $8",
+		budget.Name, budget.Amount, budget.Period, budget.CategoryId, budget.StartDate, budget.EndDate, budget.Rollover, budget.ID,
+	)
+	if err != nil {
+		s.logger.Error("Error updating budget", "error", err, "id", budget.ID)
+		return fmt.Errorf("error updating budget: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Budget not found for update", "id", budget.ID)
+		return domain.ErrBudgetNotFound
+	}
+
+	s.logger.Info("Budget updated successfully", "id", budget.ID)
+	return nil
+}
+
+// DeleteBudget deletes a budget by its ID
+func (s *DBService) DeleteBudget(id string) error {
+	s.logger.Debug("Deleting budget", "id", id)
+
+	budgetID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	result, err := s.db.Exec("DELETE FROM budgets WHERE id = $1", budgetID)
+	if err != nil {
+		s.logger.Error("Error deleting budget", "error", err, "id", id)
+		return fmt.Errorf("error deleting budget: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Budget not found for deletion", "id", id)
+		return domain.ErrBudgetNotFound
+	}
+
+	s.logger.Info("Budget deleted successfully", "id", id)
+	return nil
+}
+
+func scanBudget(row rowScanner) (*domain.Budget, error) {
+	var budget domain.Budget
+	var endDate sql.NullTime
+	err := row.Scan(&budget.ID, &budget.Name, &budget.Amount, &budget.Period, &budget.CategoryId, &budget.StartDate, &endDate, &budget.Rollover, &budget.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if endDate.Valid {
+		budget.EndDate = &endDate.Time
+	}
+	return &budget, nil
+}
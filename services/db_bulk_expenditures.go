@@ -0,0 +1,69 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the PostgreSQL error code of a duplicate key
+const uniqueViolation = "23505"
+
+// AddExpenditures adds expenditures in one transaction with COPY, which is orders of magnitude
+// faster than one INSERT per expenditure for large imports
+func (s *DBService) AddExpenditures(expenditures []*domain.Expenditure) error {
+	s.logger.Debug("Adding expenditures in bulk to database", "count", len(expenditures))
+
+	if len(expenditures) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("Error starting bulk insert transaction", "error", err)
+		return fmt.Errorf("error starting bulk insert transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(pq.CopyIn("expenditures", strings.Split(expenditureColumns, ", ")...))
+	if err != nil {
+		s.logger.Error("Error preparing bulk insert", "error", err)
+		return fmt.Errorf("error preparing bulk insert: %w", err)
+	}
+
+	for _, expenditure := range expenditures {
+		if _, err = stmt.Exec(expenditureValues(expenditure)...); err != nil {
+			stmt.Close()
+			s.logger.Error("Error copying expenditure", "error", err, "id", expenditure.ID)
+			return fmt.Errorf("error copying expenditure: %w", err)
+		}
+	}
+
+	// The buffered rows are sent, and constraints checked, when the copy is flushed
+	if _, err = stmt.Exec(); err != nil {
+		stmt.Close()
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			s.logger.Warn("Expenditure already exists in bulk insert", "detail", pqErr.Detail)
+			return domain.ErrExpenditureAlreadyExists
+		}
+		s.logger.Error("Error flushing bulk insert", "error", err)
+		return fmt.Errorf("error flushing bulk insert: %w", err)
+	}
+
+	if err = stmt.Close(); err != nil {
+		s.logger.Error("Error closing bulk insert", "error", err)
+		return fmt.Errorf("error closing bulk insert: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.logger.Error("Error committing bulk insert", "error", err)
+		return fmt.Errorf("error committing bulk insert: %w", err)
+	}
+
+	s.logger.Info("Expenditures added successfully", "count", len(expenditures))
+	return nil
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const categoryColumns = "id, name, color, deductible, parent_id, icon, sort_order, active, transaction_limit, limit_mode"
+
+// seedCategories inserts the default categories that do not exist yet
+func seedCategories(db *sql.DB) error {
+	for name, color := range domain.DefaultCategories {
+		_, err := db.Exec(
+			"INSERT INTO categories (id, name, color, deductible, icon) VALUES ($1, $2, $3, FALSE, $4) ON CONFLICT (name) DO NOTHING",
+			uuid.New(), name, color, domain.DefaultCategoryIcons[name],
+		)
+		if err != nil {
+			return fmt.Errorf("error seeding category %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// AddCategory adds a new category; names are unique
+func (s *DBService) AddCategory(category *domain.Category) error {
+	s.logger.Debug("Adding category to database", "id", category.ID, "name", category.Name)
+
+	_, err := s.db.Exec(
+		"INSERT INTO categories ("+categoryColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+		category.ID, category.Name, category.Color, category.Deductible, nullUUID(category.ParentID),
+		category.Icon, category.SortOrder, category.Active, category.TransactionLimit, category.LimitMode,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			s.logger.Warn("Category already exists", "id", category.ID, "name", category.Name)
+			return domain.ErrCategoryAlreadyExists
+		}
+		s.logger.Error("Error inserting category", "error", err, "id", category.ID)
+		return fmt.Errorf("error inserting category: %w", err)
+	}
+
+	s.logger.Info("Category added successfully", "id", category.ID)
+	return nil
+}
+
+// GetCategoryByID retrieves a category by its ID
+func (s *DBService) GetCategoryByID(id string) (*domain.Category, error) {
+	s.logger.Debug("Getting category by ID", "id", id)
+
+	categoryID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	category, err := scanCategory(s.db.QueryRow("SELECT "+categoryColumns+" FROM categories WHERE id = $1", categoryID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Category not found", "id", id)
+			return nil, domain.ErrCategoryNotFound
+		}
+		s.logger.Error("Error querying category", "error", err, "id", id)
+		return nil, fmt.Errorf("error querying category: %w", err)
+	}
+
+	return category, nil
+}
+
+// GetAllCategories retrieves all categories ordered by their sort order and name
+func (s *DBService) GetAllCategories() ([]*domain.Category, error) {
+	s.logger.Debug("Getting all categories")
+
+	rows, err := s.db.Query("SELECT " + categoryColumns + " FROM categories ORDER BY sort_order, name")
+	if err != nil {
+		s.logger.Error("Error querying all categories", "error", err)
+		return nil, fmt.Errorf("error querying all categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []*domain.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			s.logger.Error("Error scanning category row", "error", err)
+			return nil, fmt.Errorf("error scanning category row: %w", err)
+		}
+		categories = append(categories, category)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating category rows", "error", err)
+		return nil, fmt.Errorf("error iterating category rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved all categories", "count", len(categories))
+	return categories, nil
+}
+
+// UpdateCategory updates an existing category
+func (s *DBService) UpdateCategory(category *domain.Category) error {
+	s.logger.Debug("Updating category", "id", category.ID, "name", category.Name)
+
+	result, err := s.db.Exec(
+		`UPDATE categories SET name = $1, color = $2, deductible = $3, parent_id = $4, icon = $5, sort_order = $6,
+			active = $7, transaction_limit = $8, limit_mode = $9 WHERE id = $10`,
+		category.Name, category.Color, category.Deductible, nullUUID(category.ParentID), category.Icon, category.SortOrder,
+		category.Active, category.TransactionLimit, category.LimitMode, category.ID,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			s.logger.Warn("Category name already taken", "id", category.ID, "name", category.Name)
+			return domain.ErrCategoryAlreadyExists
+		}
+		s.logger.Error("Error updating category", "error", err, "id", category.ID)
+		return fmt.Errorf("error updating category: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Category not found for update", "id", category.ID)
+		return domain.ErrCategoryNotFound
+	}
+
+	s.logger.Info("Category updated successfully", "id", category.ID)
+	return nil
+}
+
+// ReorderCategories renumbers the sort order of all categories in one transaction
+func (s *DBService) ReorderCategories(ids []uuid.UUID) error {
+	s.logger.Debug("Reordering categories", "count", len(ids))
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("Error starting category reorder transaction", "error", err)
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT " + categoryColumns + " FROM categories FOR UPDATE")
+	if err != nil {
+		s.logger.Error("Error locking categories", "error", err)
+		return fmt.Errorf("error locking categories: %w", err)
+	}
+
+	var categories []*domain.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			rows.Close()
+			s.logger.Error("Error scanning category row", "error", err)
+			return fmt.Errorf("error scanning category row: %w", err)
+		}
+		categories = append(categories, category)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating category rows", "error", err)
+		return fmt.Errorf("error iterating category rows: %w", err)
+	}
+
+	if err := domain.OrderCategories(categories, ids); err != nil {
+		s.logger.Warn("Invalid category order", "error", err)
+		return err
+	}
+
+	for _, category := range categories {
+		_, err := tx.Exec("UPDATE categories SET sort_order = $1 WHERE id = $2", category.SortOrder, category.ID)
+		if err != nil {
+			s.logger.Error("Error updating category sort order", "error", err, "id", category.ID)
+			return fmt.Errorf("error updating category sort order: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.logger.Error("Error committing category reorder", "error", err)
+		return fmt.Errorf("error committing category reorder: %w", err)
+	}
+
+	s.logger.Info("Categories reordered successfully", "count", len(categories))
+	return nil
+}
+
+func scanCategory(row rowScanner) (*domain.Category, error) {
+	var category domain.Category
+	var parentID uuid.NullUUID
+	err := row.Scan(&category.ID, &category.Name, &category.Color, &category.Deductible, &parentID,
+		&category.Icon, &category.SortOrder, &category.Active, &category.TransactionLimit, &category.LimitMode)
+	if err != nil {
+		return nil, err
+	}
+	category.ParentID = parentID.UUID
+	return &category, nil
+}
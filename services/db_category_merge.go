@@ -0,0 +1,105 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+)
+
+// MergeCategory moves every reference to source over to target and deletes source in one
+// transaction. A dry run performs the same updates to count them and rolls them back
+func (s *DBService) MergeCategory(source, target uuid.UUID, dryRun bool) (*domain.CategoryMerge, error) {
+	s.logger.Debug("Merging category", "source_id", source, "target_id", target, "dry_run", dryRun)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("Error starting category merge transaction", "error", err)
+		return nil, fmt.Errorf("error starting category merge transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the categories so the hierarchy cannot change while it is checked
+	rows, err := tx.Query("SELECT " + categoryColumns + " FROM categories FOR UPDATE")
+	if err != nil {
+		s.logger.Error("Error querying categories for merge", "error", err)
+		return nil, fmt.Errorf("error querying categories for merge: %w", err)
+	}
+	var categories []*domain.Category
+	found := false
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning category row: %w", err)
+		}
+		found = found || category.ID == source
+		categories = append(categories, category)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating category rows: %w", err)
+	}
+
+	if !found {
+		s.logger.Warn("Category not found for merge", "id", source)
+		return nil, domain.ErrCategoryNotFound
+	}
+	if err := domain.CheckCategoryMerge(categories, source, target); err != nil {
+		s.logger.Warn("Invalid category merge", "source_id", source, "target_id", target, "error", err)
+		return nil, err
+	}
+
+	merge := &domain.CategoryMerge{SourceID: source, TargetID: target, DryRun: dryRun}
+	updates := []struct {
+		query string
+		count *int
+	}{
+		{"UPDATE expenditures SET category_id = $1 WHERE category_id = $2", &merge.Expenditures},
+		{"UPDATE goals SET category_id = $1 WHERE category_id = $2", &merge.Goals},
+		{"UPDATE budgets SET category_id = $1 WHERE category_id = $2", &merge.Budgets},
+		{"UPDATE recurring_expenditures SET category_id = $1 WHERE category_id = $2", &merge.Recurring},
+		{"UPDATE installment_purchases SET category_id = $1 WHERE category_id = $2", &merge.Installments},
+		{"UPDATE envelope_allocations SET category_id = $1 WHERE category_id = $2", &merge.Envelopes},
+		{"UPDATE merchants SET default_category_id = $1 WHERE default_category_id = $2", &merge.Merchants},
+		{"UPDATE staged_expenditures SET category_id = $1 WHERE category_id = $2", &merge.StagedExpenditures},
+		{"UPDATE expenditure_drafts SET category_id = $1 WHERE category_id = $2", &merge.Drafts},
+		{viewCategoryMerge, &merge.Views},
+		{"UPDATE categories SET parent_id = $1 WHERE parent_id = $2", &merge.Subcategories},
+	}
+	for _, update := range updates {
+		*update.count, err = execCount(tx, update.query, target, source)
+		if err != nil {
+			s.logger.Error("Error reassigning category", "error", err, "source_id", source)
+			return nil, fmt.Errorf("error reassigning category: %w", err)
+		}
+	}
+
+	if _, err = tx.Exec("DELETE FROM categories WHERE id = $1", source); err != nil {
+		s.logger.Error("Error deleting merged category", "error", err, "id", source)
+		return nil, fmt.Errorf("error deleting merged category: %w", err)
+	}
+
+	if dryRun {
+		s.logger.Info("Category merge dry run", "source_id", source, "target_id", target, "expenditures", merge.Expenditures)
+		return merge, nil
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.logger.Error("Error committing category merge", "error", err)
+		return nil, fmt.Errorf("error committing category merge: %w", err)
+	}
+
+	s.logger.Info("Category merged successfully", "source_id", source, "target_id", target, "expenditures", merge.Expenditures)
+	return merge, nil
+}
+
+func execCount(tx *sql.Tx, query string, args ...interface{}) (int, error) {
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
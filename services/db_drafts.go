@@ -0,0 +1,190 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// setupDrafts creates the expenditure_drafts table. It has the columns of expenditures, without
+// their constraints since drafts may be incomplete
+func setupDrafts(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS expenditure_drafts (
+			id UUID PRIMARY KEY,
+			description TEXT NOT NULL,
+			amount DECIMAL(10, 2) NOT NULL,
+			date TIMESTAMP NOT NULL,
+			category_id UUID,
+			tags TEXT[] NOT NULL DEFAULT '{}',
+			quantity DECIMAL(12, 4) NOT NULL DEFAULT 0,
+			unit_price DECIMAL(12, 4) NOT NULL DEFAULT 0,
+			unit TEXT NOT NULL DEFAULT '',
+			tax_rate DECIMAL(5, 2) NOT NULL DEFAULT 0,
+			tax_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+			merchant_id UUID,
+			latitude DOUBLE PRECISION,
+			longitude DOUBLE PRECISION,
+			place_name TEXT NOT NULL DEFAULT '',
+			city TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create expenditure_drafts table: %w", err)
+	}
+
+	_, err = db.Exec(`ALTER TABLE expenditure_drafts ADD COLUMN IF NOT EXISTS planned BOOLEAN NOT NULL DEFAULT FALSE`)
+	if err != nil {
+		return fmt.Errorf("failed to add planned column to expenditure_drafts: %w", err)
+	}
+
+	_, err = db.Exec(`ALTER TABLE expenditure_drafts ADD COLUMN IF NOT EXISTS refund_of UUID`)
+	if err != nil {
+		return fmt.Errorf("failed to add refund_of column to expenditure_drafts: %w", err)
+	}
+
+	_, err = db.Exec(`ALTER TABLE expenditure_drafts ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'cleared'`)
+	if err != nil {
+		return fmt.Errorf("failed to add status column to expenditure_drafts: %w", err)
+	}
+
+	_, err = db.Exec(`ALTER TABLE expenditure_drafts
+		ADD COLUMN IF NOT EXISTS account_id UUID,
+		ADD COLUMN IF NOT EXISTS reconciled BOOLEAN NOT NULL DEFAULT FALSE`)
+	if err != nil {
+		return fmt.Errorf("failed to add account columns to expenditure_drafts: %w", err)
+	}
+
+	_, err = db.Exec(`ALTER TABLE expenditure_drafts
+		ADD COLUMN IF NOT EXISTS original_amount DECIMAL(12, 2) NOT NULL DEFAULT 0,
+		ADD COLUMN IF NOT EXISTS original_currency TEXT NOT NULL DEFAULT ''`)
+	if err != nil {
+		return fmt.Errorf("failed to add original amount columns to expenditure_drafts: %w", err)
+	}
+
+	_, err = db.Exec(`ALTER TABLE expenditure_drafts ADD COLUMN IF NOT EXISTS created_by UUID`)
+	if err != nil {
+		return fmt.Errorf("failed to add created_by column to expenditure_drafts: %w", err)
+	}
+	return nil
+}
+
+// AddDraft saves a new expenditure draft
+func (s *DBService) AddDraft(draft *domain.Draft) error {
+	s.logger.Debug("Adding draft to database", "id", draft.ID, "description", draft.Description, "amount", draft.Amount)
+
+	_, err := s.db.Exec(
+		"INSERT INTO expenditure_drafts ("+expenditureColumns+", created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25)",
+		append(expenditureValues(&draft.Expenditure), draft.CreatedAt)...,
+	)
+	if err != nil {
+		s.logger.Error("Error inserting draft", "error", err, "id", draft.ID)
+		return fmt.Errorf("error inserting draft: %w", err)
+	}
+
+	s.logger.Info("Draft added successfully", "id", draft.ID)
+	return nil
+}
+
+// GetDraftByID retrieves an expenditure draft by its ID
+func (s *DBService) GetDraftByID(id string) (*domain.Draft, error) {
+	s.logger.Debug("Getting draft by ID", "id", id)
+
+	draftID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	row := s.db.QueryRow("SELECT "+expenditureColumns+", created_at FROM expenditure_drafts WHERE id = $1", draftID)
+	draft, err := scanDraft(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Draft not found", "id", id)
+			return nil, domain.ErrDraftNotFound
+		}
+		s.logger.Error("Error querying draft", "error", err, "id", id)
+		return nil, fmt.Errorf("error querying draft: %w", err)
+	}
+
+	return draft, nil
+}
+
+// GetAllDrafts retrieves all expenditure drafts, oldest first
+func (s *DBService) GetAllDrafts() ([]*domain.Draft, error) {
+	s.logger.Debug("Getting all drafts")
+
+	rows, err := s.db.Query("SELECT " + expenditureColumns + ", created_at FROM expenditure_drafts ORDER BY created_at")
+	if err != nil {
+		s.logger.Error("Error querying all drafts", "error", err)
+		return nil, fmt.Errorf("error querying all drafts: %w", err)
+	}
+	defer rows.Close()
+
+	var drafts []*domain.Draft
+	for rows.Next() {
+		draft, err := scanDraft(rows)
+		if err != nil {
+			s.logger.Error("Error scanning draft row", "error", err)
+			return nil, fmt.Errorf("error scanning draft row: %w", err)
+		}
+		drafts = append(drafts, draft)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating draft rows", "error", err)
+		return nil, fmt.Errorf("error iterating draft rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved all drafts", "count", len(drafts))
+	return drafts, nil
+}
+
+// DeleteDraft deletes an expenditure draft by its ID
+func (s *DBService) DeleteDraft(id string) error {
+	s.logger.Debug("Deleting draft", "id", id)
+
+	draftID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	result, err := s.db.Exec("DELETE FROM expenditure_drafts WHERE id = $1", draftID)
+	if err != nil {
+		s.logger.Error("Error deleting draft", "error", err, "id", id)
+		return fmt.Errorf("error deleting draft: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Draft not found for deletion", "id", id)
+		return domain.ErrDraftNotFound
+	}
+
+	s.logger.Info("Draft deleted successfully", "id", id)
+	return nil
+}
+
+// draftRow scans the created_at column following the expenditure columns of a draft
+type draftRow struct {
+	row       rowScanner
+	createdAt *time.Time
+}
+
+func (r draftRow) Scan(dest ...interface{}) error {
+	return r.row.Scan(append(dest, r.createdAt)...)
+}
+
+func scanDraft(row rowScanner) (*domain.Draft, error) {
+	var createdAt time.Time
+	expenditure, err := scanExpenditure(draftRow{row: row, createdAt: &createdAt})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Draft{Expenditure: *expenditure, CreatedAt: createdAt}, nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"go-expense-tracker/domain"
+)
+
+const envelopeAllocationColumns = "id, category_id, amount, note, transfer_id, created_at"
+
+// setupEnvelopes creates the table of envelope allocations
+func setupEnvelopes(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS envelope_allocations (
+			id UUID PRIMARY KEY,
+			category_id UUID NOT NULL,
+			amount DECIMAL(10, 2) NOT NULL,
+			note TEXT NOT NULL DEFAULT '',
+			transfer_id UUID NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create envelope allocations table: %w", err)
+	}
+	return nil
+}
+
+// AddEnvelopeAllocations adds the allocations in one transaction
+func (s *DBService) AddEnvelopeAllocations(allocations []*domain.EnvelopeAllocation) error {
+	s.logger.Debug("Adding envelope allocations to database", "count", len(allocations))
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("Error starting envelope allocation transaction", "error", err)
+		return fmt.Errorf("error starting envelope allocation transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, allocation := range allocations {
+		_, err = tx.Exec(
+			"INSERT INTO envelope_allocations ("+envelopeAllocationColumns+") VALUES ($1, $2, $3, $4, $5, $6)",
+			allocation.ID, allocation.CategoryId, allocation.Amount, allocation.Note, allocation.TransferID, allocation.At,
+		)
+		if err != nil {
+			s.logger.Error("Error inserting envelope allocation", "error", err, "id", allocation.ID)
+			return fmt.Errorf("error inserting envelope allocation: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.logger.Error("Error committing envelope allocations", "error", err)
+		return fmt.Errorf("error committing envelope allocations: %w", err)
+	}
+
+	s.logger.Info("Envelope allocations added successfully", "count", len(allocations))
+	return nil
+}
+
+// GetAllEnvelopeAllocations retrieves all envelope allocations, oldest first
+func (s *DBService) GetAllEnvelopeAllocations() ([]*domain.EnvelopeAllocation, error) {
+	s.logger.Debug("Getting all envelope allocations")
+
+	rows, err := s.db.Query("SELECT " + envelopeAllocationColumns + " FROM envelope_allocations ORDER BY created_at, id")
+	if err != nil {
+		s.logger.Error("Error querying envelope allocations", "error", err)
+		return nil, fmt.Errorf("error querying envelope allocations: %w", err)
+	}
+	defer rows.Close()
+
+	var allocations []*domain.EnvelopeAllocation
+	for rows.Next() {
+		var allocation domain.EnvelopeAllocation
+		err := rows.Scan(&allocation.ID, &allocation.CategoryId, &allocation.Amount, &allocation.Note, &allocation.TransferID, &allocation.At)
+		if err != nil {
+			s.logger.Error("Error scanning envelope allocation row", "error", err)
+			return nil, fmt.Errorf("error scanning envelope allocation row: %w", err)
+		}
+		allocations = append(allocations, &allocation)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating envelope allocation rows", "error", err)
+		return nil, fmt.Errorf("error iterating envelope allocation rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved all envelope allocations", "count", len(allocations))
+	return allocations, nil
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const expenditureEventColumns = "seq, kind, expenditure_id, expenditure, at"
+
+// setupExpenditureEvents creates the append-only log of expenditure events. The state after each
+// event is kept as JSON so the log does not need migrating with the expenditures table
+func setupExpenditureEvents(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS expenditure_events (
+			seq BIGSERIAL PRIMARY KEY,
+			kind TEXT NOT NULL,
+			expenditure_id UUID NOT NULL,
+			expenditure JSONB,
+			at TIMESTAMP NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS expenditure_events_expenditure_id ON expenditure_events (expenditure_id, seq)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create expenditure events table: %w", err)
+	}
+	return nil
+}
+
+// AppendExpenditureEvents appends the events in one transaction
+func (s *DBService) AppendExpenditureEvents(events []*domain.ExpenditureEvent) error {
+	s.logger.Debug("Appending expenditure events to database", "count", len(events))
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("Error starting expenditure event transaction", "error", err)
+		return fmt.Errorf("error starting expenditure event transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	seqs := make([]int64, len(events))
+	for i, event := range events {
+		var state []byte
+		if event.Expenditure != nil {
+			state, err = json.Marshal(event.Expenditure)
+			if err != nil {
+				return fmt.Errorf("error encoding expenditure event: %w", err)
+			}
+		}
+
+		err = tx.QueryRow(
+			"INSERT INTO expenditure_events (kind, expenditure_id, expenditure, at) VALUES ($1, $2, $3, $4) RETURNING seq",
+			event.Kind, event.ExpenditureID, state, event.At,
+		).Scan(&seqs[i])
+		if err != nil {
+			s.logger.Error("Error inserting expenditure event", "error", err, "expenditure_id", event.ExpenditureID)
+			return fmt.Errorf("error inserting expenditure event: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.logger.Error("Error committing expenditure events", "error", err)
+		return fmt.Errorf("error committing expenditure events: %w", err)
+	}
+
+	for i, event := range events {
+		event.Seq = seqs[i]
+	}
+
+	s.logger.Info("Expenditure events appended successfully", "count", len(events))
+	return nil
+}
+
+// GetExpenditureEvents retrieves up to limit events after the given sequence number, oldest first
+func (s *DBService) GetExpenditureEvents(after int64, limit int) ([]*domain.ExpenditureEvent, error) {
+	s.logger.Debug("Getting expenditure events", "after", after, "limit", limit)
+
+	query := "SELECT " + expenditureEventColumns + " FROM expenditure_events WHERE seq > $1 ORDER BY seq"
+	args := []interface{}{after}
+	if limit > 0 {
+		query += " LIMIT $2"
+		args = append(args, limit)
+	}
+
+	return s.queryExpenditureEvents(query, args...)
+}
+
+// GetExpenditureHistory retrieves the events of one expenditure, oldest first
+func (s *DBService) GetExpenditureHistory(id uuid.UUID) ([]*domain.ExpenditureEvent, error) {
+	s.logger.Debug("Getting expenditure history", "id", id)
+
+	return s.queryExpenditureEvents("SELECT "+expenditureEventColumns+" FROM expenditure_events WHERE expenditure_id = $1 ORDER BY seq", id)
+}
+
+func (s *DBService) queryExpenditureEvents(query string, args ...interface{}) ([]*domain.ExpenditureEvent, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		s.logger.Error("Error querying expenditure events", "error", err)
+		return nil, fmt.Errorf("error querying expenditure events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.ExpenditureEvent
+	for rows.Next() {
+		var event domain.ExpenditureEvent
+		var state []byte
+		if err := rows.Scan(&event.Seq, &event.Kind, &event.ExpenditureID, &state, &event.At); err != nil {
+			s.logger.Error("Error scanning expenditure event row", "error", err)
+			return nil, fmt.Errorf("error scanning expenditure event row: %w", err)
+		}
+		if state != nil {
+			if err := json.Unmarshal(state, &event.Expenditure); err != nil {
+				return nil, fmt.Errorf("error decoding expenditure event: %w", err)
+			}
+		}
+		events = append(events, &event)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating expenditure event rows", "error", err)
+		return nil, fmt.Errorf("error iterating expenditure event rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved expenditure events", "count", len(events))
+	return events, nil
+}
+
+// ScrubExpenditureEvents drops the states of the erased expenditures and removes the member from
+// the states of the others they recorded, in one transaction
+func (s *DBService) ScrubExpenditureEvents(member uuid.UUID, erased []uuid.UUID) (int, error) {
+	s.logger.Debug("Scrubbing expenditure events in database", "member_id", member, "erased_count", len(erased))
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("Error starting expenditure event transaction", "error", err)
+		return 0, fmt.Errorf("error starting expenditure event transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	scrubbed := 0
+	if len(erased) > 0 {
+		result, err := tx.Exec("UPDATE expenditure_events SET expenditure = NULL WHERE expenditure_id = ANY($1::uuid[]) AND expenditure IS NOT NULL", pq.Array(uuidStrings(erased)))
+		if err != nil {
+			s.logger.Error("Error dropping erased expenditure events", "error", err)
+			return 0, fmt.Errorf("error dropping erased expenditure events: %w", err)
+		}
+		affected, _ := result.RowsAffected()
+		scrubbed += int(affected)
+	}
+	if member != uuid.Nil {
+		result, err := tx.Exec("UPDATE expenditure_events SET expenditure = expenditure - 'created_by' WHERE expenditure->>'created_by' = $1", member.String())
+		if err != nil {
+			s.logger.Error("Error removing member from expenditure events", "error", err, "member_id", member)
+			return 0, fmt.Errorf("error removing member from expenditure events: %w", err)
+		}
+		affected, _ := result.RowsAffected()
+		scrubbed += int(affected)
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.logger.Error("Error committing scrubbed expenditure events", "error", err)
+		return 0, fmt.Errorf("error committing scrubbed expenditure events: %w", err)
+	}
+
+	s.logger.Info("Expenditure events scrubbed successfully", "member_id", member, "count", scrubbed)
+	return scrubbed, nil
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+)
+
+// GetExpenditurePage reads one page of expenditures, newest first, seeking straight to the
+// cursor through the expenditures_date_id index
+func (s *DBService) GetExpenditurePage(query domain.ExpenditurePageQuery) ([]*domain.Expenditure, error) {
+	s.logger.Debug("Getting expenditure page", "from", query.From, "to", query.To, "limit", query.Limit)
+
+	sqlQuery := "SELECT " + expenditureColumns + " FROM expenditures WHERE TRUE"
+	var args []interface{}
+	if !query.From.IsZero() {
+		args = append(args, query.From)
+		sqlQuery += fmt.Sprintf(" AND date >= $%d", len(args))
+	}
+	if !query.To.IsZero() {
+		args = append(args, query.To)
+		sqlQuery += fmt.Sprintf(" AND date < $%d", len(args))
+	}
+	if query.CategoryId != uuid.Nil {
+		args = append(args, query.CategoryId)
+		sqlQuery += fmt.Sprintf(" AND category_id = $%d", len(args))
+	}
+	if query.After != nil {
+		args = append(args, query.After.Date, query.After.ID)
+		sqlQuery += fmt.Sprintf(" AND (date, id) < ($%d, $%d)", len(args)-1, len(args))
+	}
+	args = append(args, query.Limit)
+	sqlQuery += fmt.Sprintf(" ORDER BY date DESC, id DESC LIMIT $%d", len(args))
+
+	rows, err := s.db.Query(sqlQuery, args...)
+	if err != nil {
+		s.logger.Error("Error querying expenditure page", "error", err)
+		return nil, fmt.Errorf("error querying expenditure page: %w", err)
+	}
+	defer rows.Close()
+
+	var expenditures []*domain.Expenditure
+	for rows.Next() {
+		expenditure, err := scanExpenditure(rows)
+		if err != nil {
+			s.logger.Error("Error scanning expenditure row", "error", err)
+			return nil, fmt.Errorf("error scanning expenditure row: %w", err)
+		}
+		expenditures = append(expenditures, expenditure)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating expenditure rows", "error", err)
+		return nil, fmt.Errorf("error iterating expenditure rows: %w", err)
+	}
+
+	return expenditures, nil
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const expenseReportColumns = "id, title, claimant, expenditure_ids, status, created_at, submitted_at, approved_at, reimbursed_at, history"
+
+// AddExpenseReport adds a new expense report to the database
+func (s *DBService) AddExpenseReport(report *domain.ExpenseReport) error {
+	s.logger.Debug("Adding expense report to database", "id", report.ID, "title", report.Title, "status", report.Status)
+
+	history, err := json.Marshal(report.History)
+	if err != nil {
+		return fmt.Errorf("error encoding expense report history: %w", err)
+	}
+
+	_, err = s.db.Exec(
+		"INSERT INTO expense_reports ("+expenseReportColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+		report.ID, report.Title, report.Claimant, pq.Array(uuidStrings(report.ExpenditureIDs)), report.Status,
+		report.CreatedAt, report.SubmittedAt, report.ApprovedAt, report.ReimbursedAt, history,
+	)
+	if err != nil {
+		s.logger.Error("Error inserting expense report", "error", err, "id", report.ID)
+		return fmt.Errorf("error inserting expense report: %w", err)
+	}
+
+	s.logger.Info("Expense report added successfully", "id", report.ID)
+	return nil
+}
+
+// GetExpenseReportByID retrieves an expense report by its ID
+func (s *DBService) GetExpenseReportByID(id string) (*domain.ExpenseReport, error) {
+	s.logger.Debug("Getting expense report by ID", "id", id)
+
+	reportID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	report, err := scanExpenseReport(s.db.QueryRow("SELECT "+expenseReportColumns+" FROM expense_reports WHERE id = $1", reportID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Expense report not found", "id", id)
+			return nil, domain.ErrExpenseReportNotFound
+		}
+		s.logger.Error("Error querying expense report", "error", err, "id", id)
+		return nil, fmt.Errorf("error querying expense report: %w", err)
+	}
+
+	return report, nil
+}
+
+// GetAllExpenseReports retrieves all expense reports, oldest first
+func (s *DBService) GetAllExpenseReports() ([]*domain.ExpenseReport, error) {
+	s.logger.Debug("Getting all expense reports")
+
+	rows, err := s.db.Query("SELECT " + expenseReportColumns + " FROM expense_reports ORDER BY created_at")
+	if err != nil {
+		s.logger.Error("Error querying all expense reports", "error", err)
+		return nil, fmt.Errorf("error querying all expense reports: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []*domain.ExpenseReport
+	for rows.Next() {
+		report, err := scanExpenseReport(rows)
+		if err != nil {
+			s.logger.Error("Error scanning expense report row", "error", err)
+			return nil, fmt.Errorf("error scanning expense report row: %w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating expense report rows", "error", err)
+		return nil, fmt.Errorf("error iterating expense report rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved all expense reports", "count", len(reports))
+	return reports, nil
+}
+
+// UpdateExpenseReport updates an existing expense report
+func (s *DBService) UpdateExpenseReport(report *domain.ExpenseReport) error {
+	s.logger.Debug("Updating expense report", "id", report.ID, "title", report.Title, "status", report.Status)
+
+	history, err := json.Marshal(report.History)
+	if err != nil {
+		return fmt.Errorf("error encoding expense report history: %w", err)
+	}
+
+	result, err := s.db.Exec(
+		`UPDATE expense_reports SET title = $1, claimant = $2, expenditure_ids = $3, status = $4,
+			submitted_at = $5, approved_at = $6, reimbursed_at = $7, history = $8 WHERE id = $9`,
+		report.Title, report.Claimant, pq.Array(uuidStrings(report.ExpenditureIDs)), report.Status,
+		report.SubmittedAt, report.ApprovedAt, report.ReimbursedAt, history, report.ID,
+	)
+	if err != nil {
+		s.logger.Error("Error updating expense report", "error", err, "id", report.ID)
+		return fmt.Errorf("error updating expense report: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Expense report not found for update", "id", report.ID)
+		return domain.ErrExpenseReportNotFound
+	}
+
+	s.logger.Info("Expense report updated successfully", "id", report.ID, "status", report.Status)
+	return nil
+}
+
+// DeleteExpenseReport deletes an expense report by its ID
+func (s *DBService) DeleteExpenseReport(id string) error {
+	s.logger.Debug("Deleting expense report", "id", id)
+
+	reportID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	result, err := s.db.Exec("DELETE FROM expense_reports WHERE id = $1", reportID)
+	if err != nil {
+		s.logger.Error("Error deleting expense report", "error", err, "id", id)
+		return fmt.Errorf("error deleting expense report: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Expense report not found for deletion", "id", id)
+		return domain.ErrExpenseReportNotFound
+	}
+
+	s.logger.Info("Expense report deleted successfully", "id", id)
+	return nil
+}
+
+func scanExpenseReport(row rowScanner) (*domain.ExpenseReport, error) {
+	var report domain.ExpenseReport
+	var expenditureIDs []string
+	var history []byte
+
+	err := row.Scan(&report.ID, &report.Title, &report.Claimant, pq.Array(&expenditureIDs), &report.Status,
+		&report.CreatedAt, &report.SubmittedAt, &report.ApprovedAt, &report.ReimbursedAt, &history)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(history, &report.History); err != nil {
+		return nil, fmt.Errorf("invalid expense report history: %w", err)
+	}
+
+	report.ExpenditureIDs = make([]uuid.UUID, 0, len(expenditureIDs))
+	for _, s := range expenditureIDs {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expenditure ID in expense report: %w", err)
+		}
+		report.ExpenditureIDs = append(report.ExpenditureIDs, id)
+	}
+
+	return &report, nil
+}
+
+// uuidStrings converts IDs for storage in a UUID[] column
+func uuidStrings(ids []uuid.UUID) []string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = id.String()
+	}
+	return s
+}
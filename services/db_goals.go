@@ -0,0 +1,138 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+)
+
+const goalColumns = "id, name, target_amount, start_date, deadline, category_id"
+
+// AddGoal adds a new goal to the database
+func (s *DBService) AddGoal(goal *domain.Goal) error {
+	s.logger.Debug("Adding goal to database", "id", goal.ID, "name", goal.Name, "target_amount", goal.TargetAmount)
+
+	_, err := s.db.Exec(
+		"INSERT INTO goals ("+goalColumns+") VALUES ($1, $2, $3, $4, $5, $6)",
+		goal.ID, goal.Name, goal.TargetAmount, goal.StartDate, goal.Deadline, goal.CategoryId,
+	)
+	if err != nil {
+		s.logger.Error("Error inserting goal", "error", err, "id", goal.ID)
+		return fmt.Errorf("error inserting goal: %w", err)
+	}
+
+	s.logger.Info("Goal added successfully", "id", goal.ID)
+	return nil
+}
+
+// GetGoalByID retrieves a goal by its ID
+func (s *DBService) GetGoalByID(id string) (*domain.Goal, error) {
+	s.logger.Debug("Getting goal by ID", "id", id)
+
+	goalID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	goal, err := scanGoal(s.db.QueryRow("SELECT "+goalColumns+" FROM goals WHERE id = $1", goalID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Goal not found", "id", id)
+			return nil, domain.ErrGoalNotFound
+		}
+		s.logger.Error("Error querying goal", "error", err, "id", id)
+		return nil, fmt.Errorf("error querying goal: %w", err)
+	}
+
+	return goal, nil
+}
+
+// GetAllGoals retrieves all goals ordered by deadline
+func (s *DBService) GetAllGoals() ([]*domain.Goal, error) {
+	s.logger.Debug("Getting all goals")
+
+	rows, err := s.db.Query("SELECT " + goalColumns + " FROM goals ORDER BY deadline")
+	if err != nil {
+		s.logger.Error("Error querying all goals", "error", err)
+		return nil, fmt.Errorf("error querying all goals: %w", err)
+	}
+	defer rows.Close()
+
+	var goals []*domain.Goal
+	for rows.Next() {
+		goal, err := scanGoal(rows)
+		if err != nil {
+			s.logger.Error("Error scanning goal row", "error", err)
+			return nil, fmt.Errorf("error scanning goal row: %w", err)
+		}
+		goals = append(goals, goal)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating goal rows", "error", err)
+		return nil, fmt.Errorf("error iterating goal rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved all goals", "count", len(goals))
+	return goals, nil
+}
+
+// UpdateGoal updates an existing goal
+func (s *DBService) UpdateGoal(goal *domain.Goal) error {
+	s.logger.Debug("Updating goal", "id", goal.ID, "name", goal.Name, "target_amount", goal.TargetAmount)
+
+	result, err := s.db.Exec(
+		"UPDATE goals SET name = $1, target_amount = $2, start_date = $3, deadline = $4, category_id = $5 WHERE id = $6",
+		goal.Name, goal.TargetAmount, goal.StartDate, goal.Deadline, goal.CategoryId, goal.ID,
+	)
+	if err != nil {
+		s.logger.Error("Error updating goal", "error", err, "id", goal.ID)
+		return fmt.Errorf("error updating goal: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Goal not found for update", "id", goal.ID)
+		return domain.ErrGoalNotFound
+	}
+
+	s.logger.Info("Goal updated successfully", "id", goal.ID)
+	return nil
+}
+
+// DeleteGoal deletes a goal by its ID
+func (s *DBService) DeleteGoal(id string) error {
+	s.logger.Debug("Deleting goal", "id", id)
+
+	goalID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	result, err := s.db.Exec("DELETE FROM goals WHERE id = $1", goalID)
+	if err != nil {
+		s.logger.Error("Error deleting goal", "error", err, "id", id)
+		return fmt.Errorf("error deleting goal: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Goal not found for deletion", "id", id)
+		return domain.ErrGoalNotFound
+	}
+
+	s.logger.Info("Goal deleted successfully", "id", id)
+	return nil
+}
+
+func scanGoal(row rowScanner) (*domain.Goal, error) {
+	var goal domain.Goal
+	err := row.Scan(&goal.ID, &goal.Name, &goal.TargetAmount, &goal.StartDate, &goal.Deadline, &goal.CategoryId)
+	if err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
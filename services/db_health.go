@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/lib/pq"
+)
+
+// Codes of server errors that mean the server cannot take statements at the moment, rather
+// than that one was wrong
+const (
+	adminShutdown      = "57P01"
+	crashShutdown      = "57P02"
+	cannotConnectNow   = "57P03"
+	tooManyConnections = "53300"
+)
+
+// Codes of server errors that go away when the transaction is run again
+const (
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+// Ping checks the database answers
+func (s *DBService) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// IsUnavailable reports whether err means the database could not be reached, such as a refused
+// or dropped connection or a server shutting down, as opposed to an error it answered with
+func (s *DBService) IsUnavailable(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// Class 08 holds the connection exceptions
+		return strings.HasPrefix(string(pqErr.Code), "08") || pqErr.Code == adminShutdown ||
+			pqErr.Code == crashShutdown || pqErr.Code == cannotConnectNow || pqErr.Code == tooManyConnections
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// IsTransient reports whether err may go away when the call is made again: the database could
+// not be reached, or it aborted the transaction for a serialization failure or a deadlock
+func (s *DBService) IsTransient(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && (pqErr.Code == serializationFailure || pqErr.Code == deadlockDetected) {
+		return true
+	}
+	return s.IsUnavailable(err)
+}
@@ -0,0 +1,473 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const foreignKeyViolation = "23503"
+
+const householdMemberColumns = "id, name, email, role, joined_at, allowance_category_id"
+const invitationColumns = "id, household_id, email, role, token_hash, created_at, expires_at, accepted_at, member_id, revoked_at"
+
+// setupHouseholds creates the tables of households, their members and invitations. Invitations
+// are looked up by the hash of their token, which is unique
+func setupHouseholds(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS households (
+			id UUID PRIMARY KEY,
+			name TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS household_members (
+			id UUID PRIMARY KEY,
+			household_id UUID NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+			name TEXT NOT NULL,
+			email TEXT NOT NULL DEFAULT '',
+			role TEXT NOT NULL,
+			joined_at TIMESTAMP NOT NULL
+		);
+
+		ALTER TABLE household_members ADD COLUMN IF NOT EXISTS allowance_category_id UUID;
+
+		CREATE INDEX IF NOT EXISTS household_members_household ON household_members (household_id, joined_at);
+
+		CREATE TABLE IF NOT EXISTS household_invitations (
+			id UUID PRIMARY KEY,
+			household_id UUID NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+			email TEXT NOT NULL DEFAULT '',
+			role TEXT NOT NULL,
+			token_hash TEXT NOT NULL UNIQUE,
+			created_at TIMESTAMP NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			accepted_at TIMESTAMP,
+			member_id UUID,
+			revoked_at TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS household_invitations_household ON household_invitations (household_id, created_at DESC)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create household tables: %w", err)
+	}
+	return nil
+}
+
+// AddHousehold saves a new household with its members
+func (s *DBService) AddHousehold(household *domain.Household) error {
+	s.logger.Debug("Adding household to database", "id", household.ID, "name", household.Name)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("Error starting household transaction", "error", err)
+		return fmt.Errorf("error starting household transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("INSERT INTO households (id, name, created_at) VALUES ($1, $2, $3)", household.ID, household.Name, household.CreatedAt)
+	if err != nil {
+		s.logger.Error("Error inserting household", "error", err, "id", household.ID)
+		return fmt.Errorf("error inserting household: %w", err)
+	}
+	for _, member := range household.Members {
+		if err := insertHouseholdMember(tx, household.ID, &member); err != nil {
+			s.logger.Error("Error inserting household member", "error", err, "id", member.ID)
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.logger.Error("Error committing household", "error", err)
+		return fmt.Errorf("error committing household: %w", err)
+	}
+
+	s.logger.Info("Household added successfully", "id", household.ID, "name", household.Name)
+	return nil
+}
+
+func insertHouseholdMember(tx *sql.Tx, householdID uuid.UUID, member *domain.HouseholdMember) error {
+	_, err := tx.Exec(
+		"INSERT INTO household_members (household_id, "+householdMemberColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		householdID, member.ID, member.Name, member.Email, member.Role, member.JoinedAt, nullUUID(member.AllowanceCategoryId),
+	)
+	if err != nil {
+		return fmt.Errorf("error inserting household member: %w", err)
+	}
+	return nil
+}
+
+// GetHouseholdByID retrieves a household with its members
+func (s *DBService) GetHouseholdByID(id string) (*domain.Household, error) {
+	s.logger.Debug("Getting household by ID", "id", id)
+
+	householdID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	var household domain.Household
+	err = s.db.QueryRow("SELECT id, name, created_at FROM households WHERE id = $1", householdID).Scan(&household.ID, &household.Name, &household.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Household not found", "id", id)
+			return nil, domain.ErrHouseholdNotFound
+		}
+		s.logger.Error("Error querying household", "error", err, "id", id)
+		return nil, fmt.Errorf("error querying household: %w", err)
+	}
+
+	members, err := s.getHouseholdMembers("WHERE household_id = $1", householdID)
+	if err != nil {
+		return nil, err
+	}
+	household.Members = members[household.ID]
+	if household.Members == nil {
+		household.Members = []domain.HouseholdMember{}
+	}
+
+	return &household, nil
+}
+
+// GetAllHouseholds retrieves all households with their members, oldest first
+func (s *DBService) GetAllHouseholds() ([]*domain.Household, error) {
+	s.logger.Debug("Getting all households")
+
+	rows, err := s.db.Query("SELECT id, name, created_at FROM households ORDER BY created_at, id")
+	if err != nil {
+		s.logger.Error("Error querying households", "error", err)
+		return nil, fmt.Errorf("error querying households: %w", err)
+	}
+	defer rows.Close()
+
+	var households []*domain.Household
+	for rows.Next() {
+		var household domain.Household
+		if err := rows.Scan(&household.ID, &household.Name, &household.CreatedAt); err != nil {
+			s.logger.Error("Error scanning household row", "error", err)
+			return nil, fmt.Errorf("error scanning household row: %w", err)
+		}
+		households = append(households, &household)
+	}
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating household rows", "error", err)
+		return nil, fmt.Errorf("error iterating household rows: %w", err)
+	}
+
+	members, err := s.getHouseholdMembers("")
+	if err != nil {
+		return nil, err
+	}
+	for _, household := range households {
+		household.Members = members[household.ID]
+		if household.Members == nil {
+			household.Members = []domain.HouseholdMember{}
+		}
+	}
+
+	s.logger.Info("Retrieved all households", "count", len(households))
+	return households, nil
+}
+
+// GetHouseholdMember retrieves a member of any household
+func (s *DBService) GetHouseholdMember(id string) (*domain.HouseholdMember, error) {
+	s.logger.Debug("Getting household member", "id", id)
+
+	memberID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	members, err := s.getHouseholdMembers("WHERE id = $1", memberID)
+	if err != nil {
+		return nil, err
+	}
+	for _, found := range members {
+		return &found[0], nil
+	}
+
+	s.logger.Warn("Household member not found", "id", id)
+	return nil, domain.ErrMemberNotFound
+}
+
+// SetHouseholdMemberAllowance sets the category whose envelope holds a member's allowance
+func (s *DBService) SetHouseholdMemberAllowance(id string, categoryId uuid.UUID) error {
+	s.logger.Debug("Setting household member allowance", "id", id, "category_id", categoryId)
+
+	memberID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	result, err := s.db.Exec("UPDATE household_members SET allowance_category_id = $1 WHERE id = $2", nullUUID(categoryId), memberID)
+	if err != nil {
+		s.logger.Error("Error updating household member allowance", "error", err, "id", id)
+		return fmt.Errorf("error updating household member allowance: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Household member not found", "id", id)
+		return domain.ErrMemberNotFound
+	}
+
+	s.logger.Info("Household member allowance set successfully", "id", id, "category_id", categoryId)
+	return nil
+}
+
+// getHouseholdMembers returns the members matching the where clause by household, in the order
+// they joined
+func (s *DBService) getHouseholdMembers(where string, args ...any) (map[uuid.UUID][]domain.HouseholdMember, error) {
+	rows, err := s.db.Query("SELECT household_id, "+householdMemberColumns+" FROM household_members "+where+" ORDER BY joined_at, id", args...)
+	if err != nil {
+		s.logger.Error("Error querying household members", "error", err)
+		return nil, fmt.Errorf("error querying household members: %w", err)
+	}
+	defer rows.Close()
+
+	members := make(map[uuid.UUID][]domain.HouseholdMember)
+	for rows.Next() {
+		var householdID uuid.UUID
+		var member domain.HouseholdMember
+		var allowanceCategoryID uuid.NullUUID
+		if err := rows.Scan(&householdID, &member.ID, &member.Name, &member.Email, &member.Role, &member.JoinedAt, &allowanceCategoryID); err != nil {
+			s.logger.Error("Error scanning household member row", "error", err)
+			return nil, fmt.Errorf("error scanning household member row: %w", err)
+		}
+		member.AllowanceCategoryId = allowanceCategoryID.UUID
+		members[householdID] = append(members[householdID], member)
+	}
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating household member rows", "error", err)
+		return nil, fmt.Errorf("error iterating household member rows: %w", err)
+	}
+	return members, nil
+}
+
+// AddInvitation saves a new invitation
+func (s *DBService) AddInvitation(invitation *domain.Invitation) error {
+	s.logger.Debug("Adding invitation to database", "id", invitation.ID, "household_id", invitation.HouseholdId)
+
+	_, err := s.db.Exec(
+		"INSERT INTO household_invitations ("+invitationColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+		invitation.ID, invitation.HouseholdId, invitation.Email, invitation.Role, invitation.TokenHash,
+		invitation.CreatedAt, invitation.ExpiresAt, invitation.AcceptedAt, nullUUID(invitation.MemberId), invitation.RevokedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			s.logger.Warn("Household not found for invitation", "household_id", invitation.HouseholdId)
+			return domain.ErrHouseholdNotFound
+		}
+		s.logger.Error("Error inserting invitation", "error", err, "id", invitation.ID)
+		return fmt.Errorf("error inserting invitation: %w", err)
+	}
+
+	s.logger.Info("Invitation added successfully", "id", invitation.ID, "household_id", invitation.HouseholdId)
+	return nil
+}
+
+// GetInvitationByID retrieves an invitation by its ID
+func (s *DBService) GetInvitationByID(id string) (*domain.Invitation, error) {
+	s.logger.Debug("Getting invitation by ID", "id", id)
+
+	invitationID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	return s.getInvitation("id = $1", invitationID)
+}
+
+// GetInvitationByTokenHash retrieves the invitation with the hash of a token
+func (s *DBService) GetInvitationByTokenHash(hash string) (*domain.Invitation, error) {
+	s.logger.Debug("Getting invitation by token")
+	return s.getInvitation("token_hash = $1", hash)
+}
+
+func (s *DBService) getInvitation(where string, arg any) (*domain.Invitation, error) {
+	invitation, err := scanInvitation(s.db.QueryRow("SELECT "+invitationColumns+" FROM household_invitations WHERE "+where, arg))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Invitation not found")
+			return nil, domain.ErrInvitationNotFound
+		}
+		s.logger.Error("Error querying invitation", "error", err)
+		return nil, fmt.Errorf("error querying invitation: %w", err)
+	}
+	return invitation, nil
+}
+
+// GetInvitations retrieves the invitations to a household, newest first
+func (s *DBService) GetInvitations(householdId string) ([]*domain.Invitation, error) {
+	s.logger.Debug("Getting invitations", "household_id", householdId)
+
+	householdID, err := uuid.Parse(householdId)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "household_id", householdId)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	rows, err := s.db.Query("SELECT "+invitationColumns+" FROM household_invitations WHERE household_id = $1 ORDER BY created_at DESC, id", householdID)
+	if err != nil {
+		s.logger.Error("Error querying invitations", "error", err)
+		return nil, fmt.Errorf("error querying invitations: %w", err)
+	}
+	defer rows.Close()
+
+	var invitations []*domain.Invitation
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			s.logger.Error("Error scanning invitation row", "error", err)
+			return nil, fmt.Errorf("error scanning invitation row: %w", err)
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating invitation rows", "error", err)
+		return nil, fmt.Errorf("error iterating invitation rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved invitations", "household_id", householdId, "count", len(invitations))
+	return invitations, nil
+}
+
+// RevokeInvitation saves a revoked invitation unless it was accepted meanwhile
+func (s *DBService) RevokeInvitation(invitation *domain.Invitation) error {
+	s.logger.Debug("Revoking invitation in database", "id", invitation.ID)
+
+	result, err := s.db.Exec("UPDATE household_invitations SET revoked_at = $2 WHERE id = $1 AND accepted_at IS NULL", invitation.ID, invitation.RevokedAt)
+	if err != nil {
+		s.logger.Error("Error revoking invitation", "error", err, "id", invitation.ID)
+		return fmt.Errorf("error revoking invitation: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		if _, err := s.GetInvitationByID(invitation.ID.String()); err != nil {
+			return err
+		}
+		return domain.ErrInvitationAccepted
+	}
+
+	s.logger.Info("Invitation revoked successfully", "id", invitation.ID)
+	return nil
+}
+
+// AcceptInvitation marks an invitation accepted and adds the member to its household in one
+// transaction, unless the invitation was accepted or revoked meanwhile
+func (s *DBService) AcceptInvitation(invitation *domain.Invitation, member *domain.HouseholdMember) error {
+	s.logger.Debug("Accepting invitation in database", "id", invitation.ID, "member_id", member.ID)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("Error starting invitation transaction", "error", err)
+		return fmt.Errorf("error starting invitation transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var acceptedAt, revokedAt sql.NullTime
+	err = tx.QueryRow("SELECT accepted_at, revoked_at FROM household_invitations WHERE id = $1 FOR UPDATE", invitation.ID).Scan(&acceptedAt, &revokedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Invitation not found for accepting", "id", invitation.ID)
+			return domain.ErrInvitationNotFound
+		}
+		s.logger.Error("Error locking invitation", "error", err, "id", invitation.ID)
+		return fmt.Errorf("error locking invitation: %w", err)
+	}
+	if acceptedAt.Valid {
+		return domain.ErrInvitationAccepted
+	}
+	if revokedAt.Valid {
+		return domain.ErrInvitationRevoked
+	}
+
+	if err := insertHouseholdMember(tx, invitation.HouseholdId, member); err != nil {
+		s.logger.Error("Error inserting household member", "error", err, "id", member.ID)
+		return err
+	}
+	_, err = tx.Exec("UPDATE household_invitations SET accepted_at = $2, member_id = $3 WHERE id = $1", invitation.ID, invitation.AcceptedAt, member.ID)
+	if err != nil {
+		s.logger.Error("Error accepting invitation", "error", err, "id", invitation.ID)
+		return fmt.Errorf("error accepting invitation: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.logger.Error("Error committing invitation", "error", err)
+		return fmt.Errorf("error committing invitation: %w", err)
+	}
+
+	s.logger.Info("Invitation accepted successfully", "id", invitation.ID, "household_id", invitation.HouseholdId, "member_id", member.ID)
+	return nil
+}
+
+func scanInvitation(row rowScanner) (*domain.Invitation, error) {
+	var invitation domain.Invitation
+	var memberID uuid.NullUUID
+	err := row.Scan(&invitation.ID, &invitation.HouseholdId, &invitation.Email, &invitation.Role, &invitation.TokenHash,
+		&invitation.CreatedAt, &invitation.ExpiresAt, &invitation.AcceptedAt, &memberID, &invitation.RevokedAt)
+	if err != nil {
+		return nil, err
+	}
+	invitation.MemberId = memberID.UUID
+	return &invitation, nil
+}
+
+// RemoveHouseholdMember removes a member, clears the email of the invitation they joined with and
+// removes their household when no members are left, in one transaction
+func (s *DBService) RemoveHouseholdMember(id string) error {
+	s.logger.Debug("Removing household member from database", "id", id)
+
+	memberID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("Error starting household member transaction", "error", err)
+		return fmt.Errorf("error starting household member transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var householdID uuid.UUID
+	err = tx.QueryRow("DELETE FROM household_members WHERE id = $1 RETURNING household_id", memberID).Scan(&householdID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Household member not found", "id", id)
+			return domain.ErrMemberNotFound
+		}
+		s.logger.Error("Error deleting household member", "error", err, "id", id)
+		return fmt.Errorf("error deleting household member: %w", err)
+	}
+
+	if _, err = tx.Exec("UPDATE household_invitations SET email = '' WHERE member_id = $1", memberID); err != nil {
+		s.logger.Error("Error clearing invitation email", "error", err, "member_id", id)
+		return fmt.Errorf("error clearing invitation email: %w", err)
+	}
+
+	// Invitations go with the household
+	_, err = tx.Exec("DELETE FROM households WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM household_members WHERE household_id = $1)", householdID)
+	if err != nil {
+		s.logger.Error("Error deleting empty household", "error", err, "household_id", householdID)
+		return fmt.Errorf("error deleting empty household: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.logger.Error("Error committing household member removal", "error", err)
+		return fmt.Errorf("error committing household member removal: %w", err)
+	}
+
+	s.logger.Info("Household member removed successfully", "id", id, "household_id", householdID)
+	return nil
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"go-expense-tracker/domain"
+)
+
+// indexes are created on startup, in addition to the primary keys and unique constraints
+var indexes = []struct {
+	name       string
+	definition string
+}{
+	// Serves keyset pagination as well as date-range filters, which scan it backwards
+	{"expenditures_date_id", "expenditures (date DESC, id DESC)"},
+	{"expenditures_category_date", "expenditures (category_id, date)"},
+}
+
+// setupIndexes creates the missing indexes
+func setupIndexes(db *sql.DB) error {
+	for _, index := range indexes {
+		_, err := db.Exec("CREATE INDEX IF NOT EXISTS " + index.name + " ON " + index.definition)
+		if err != nil {
+			return fmt.Errorf("failed to create index %s: %w", index.name, err)
+		}
+	}
+	return nil
+}
+
+// GetIndexUsage reports how often each index of the database has been used since the
+// statistics were last reset, least used first
+func (s *DBService) GetIndexUsage() ([]*domain.IndexUsage, error) {
+	s.logger.Debug("Getting index usage")
+
+	rows, err := s.db.Query(`
+		SELECT stats.relname, stats.indexrelname, indexes.indexdef, stats.idx_scan, stats.idx_tup_read,
+			stats.idx_tup_fetch, pg_relation_size(stats.indexrelid)
+		FROM pg_stat_user_indexes stats
+		JOIN pg_indexes indexes ON indexes.schemaname = stats.schemaname AND indexes.indexname = stats.indexrelname
+		ORDER BY stats.idx_scan, stats.relname, stats.indexrelname
+	`)
+	if err != nil {
+		s.logger.Error("Error querying index usage", "error", err)
+		return nil, fmt.Errorf("error querying index usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []*domain.IndexUsage
+	for rows.Next() {
+		var index domain.IndexUsage
+		err := rows.Scan(&index.Table, &index.Name, &index.Definition, &index.Scans, &index.TuplesRead,
+			&index.TuplesFetched, &index.SizeBytes)
+		if err != nil {
+			s.logger.Error("Error scanning index usage row", "error", err)
+			return nil, fmt.Errorf("error scanning index usage row: %w", err)
+		}
+		usage = append(usage, &index)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating index usage rows", "error", err)
+		return nil, fmt.Errorf("error iterating index usage rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved index usage", "count", len(usage))
+	return usage, nil
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const installmentColumns = "id, description, total_amount, category_id, tags, first_date, installments, paid_off_at, created_at"
+
+// setupInstallments creates the installment purchases table; the schedule is kept as JSON, the
+// installments themselves are rows of expenditures
+func setupInstallments(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS installment_purchases (
+			id UUID PRIMARY KEY,
+			description TEXT NOT NULL,
+			total_amount DECIMAL(12, 2) NOT NULL,
+			category_id UUID,
+			tags TEXT[] NOT NULL DEFAULT '{}',
+			first_date DATE NOT NULL,
+			installments JSONB NOT NULL DEFAULT '[]',
+			paid_off_at TIMESTAMP,
+			created_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create installment_purchases table: %w", err)
+	}
+	return nil
+}
+
+// AddInstallmentPurchase adds a new installment purchase to the database
+func (s *DBService) AddInstallmentPurchase(purchase *domain.InstallmentPurchase) error {
+	s.logger.Debug("Adding installment purchase to database", "id", purchase.ID, "description", purchase.Description, "total_amount", purchase.TotalAmount)
+
+	installments, err := json.Marshal(purchase.Installments)
+	if err != nil {
+		return fmt.Errorf("error encoding installments: %w", err)
+	}
+
+	_, err = s.db.Exec(
+		"INSERT INTO installment_purchases ("+installmentColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+		purchase.ID, purchase.Description, purchase.TotalAmount, nullUUID(purchase.CategoryId), pq.Array(purchase.Tags),
+		purchase.FirstDate, installments, purchase.PaidOffAt, purchase.CreatedAt,
+	)
+	if err != nil {
+		s.logger.Error("Error inserting installment purchase", "error", err, "id", purchase.ID)
+		return fmt.Errorf("error inserting installment purchase: %w", err)
+	}
+
+	s.logger.Info("Installment purchase added successfully", "id", purchase.ID)
+	return nil
+}
+
+// GetInstallmentPurchaseByID retrieves an installment purchase by its ID
+func (s *DBService) GetInstallmentPurchaseByID(id string) (*domain.InstallmentPurchase, error) {
+	s.logger.Debug("Getting installment purchase by ID", "id", id)
+
+	purchaseID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	purchase, err := scanInstallmentPurchase(s.db.QueryRow("SELECT "+installmentColumns+" FROM installment_purchases WHERE id = $1", purchaseID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Installment purchase not found", "id", id)
+			return nil, domain.ErrInstallmentPurchaseNotFound
+		}
+		s.logger.Error("Error querying installment purchase", "error", err, "id", id)
+		return nil, fmt.Errorf("error querying installment purchase: %w", err)
+	}
+
+	return purchase, nil
+}
+
+// GetAllInstallmentPurchases retrieves all installment purchases in the order they were created
+func (s *DBService) GetAllInstallmentPurchases() ([]*domain.InstallmentPurchase, error) {
+	s.logger.Debug("Getting all installment purchases")
+
+	rows, err := s.db.Query("SELECT " + installmentColumns + " FROM installment_purchases ORDER BY created_at")
+	if err != nil {
+		s.logger.Error("Error querying all installment purchases", "error", err)
+		return nil, fmt.Errorf("error querying all installment purchases: %w", err)
+	}
+	defer rows.Close()
+
+	var purchases []*domain.InstallmentPurchase
+	for rows.Next() {
+		purchase, err := scanInstallmentPurchase(rows)
+		if err != nil {
+			s.logger.Error("Error scanning installment purchase row", "error", err)
+			return nil, fmt.Errorf("error scanning installment purchase row: %w", err)
+		}
+		purchases = append(purchases, purchase)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating installment purchase rows", "error", err)
+		return nil, fmt.Errorf("error iterating installment purchase rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved all installment purchases", "count", len(purchases))
+	return purchases, nil
+}
+
+// UpdateInstallmentPurchase updates an existing installment purchase
+func (s *DBService) UpdateInstallmentPurchase(purchase *domain.InstallmentPurchase) error {
+	s.logger.Debug("Updating installment purchase", "id", purchase.ID, "total_amount", purchase.TotalAmount)
+
+	installments, err := json.Marshal(purchase.Installments)
+	if err != nil {
+		return fmt.Errorf("error encoding installments: %w", err)
+	}
+
+	result, err := s.db.Exec(
+		`UPDATE installment_purchases SET description = $1, total_amount = $2, category_id = $3, tags = $4,
+			first_date = $5, installments = $6, paid_off_at = $7 WHERE id = $8`,
+		purchase.Description, purchase.TotalAmount, nullUUID(purchase.CategoryId), pq.Array(purchase.Tags),
+		purchase.FirstDate, installments, purchase.PaidOffAt, purchase.ID,
+	)
+	if err != nil {
+		s.logger.Error("Error updating installment purchase", "error", err, "id", purchase.ID)
+		return fmt.Errorf("error updating installment purchase: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Installment purchase not found for update", "id", purchase.ID)
+		return domain.ErrInstallmentPurchaseNotFound
+	}
+
+	s.logger.Info("Installment purchase updated successfully", "id", purchase.ID)
+	return nil
+}
+
+func scanInstallmentPurchase(row rowScanner) (*domain.InstallmentPurchase, error) {
+	var purchase domain.InstallmentPurchase
+	var categoryID uuid.NullUUID
+	var installments []byte
+	var paidOffAt sql.NullTime
+	err := row.Scan(&purchase.ID, &purchase.Description, &purchase.TotalAmount, &categoryID, pq.Array(&purchase.Tags),
+		&purchase.FirstDate, &installments, &paidOffAt, &purchase.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(installments, &purchase.Installments); err != nil {
+		return nil, fmt.Errorf("invalid installments: %w", err)
+	}
+	purchase.CategoryId = categoryID.UUID
+	if paidOffAt.Valid {
+		purchase.PaidOffAt = &paidOffAt.Time
+	}
+	if purchase.Tags == nil {
+		purchase.Tags = []string{}
+	}
+	return &purchase, nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+	"hash/fnv"
+)
+
+// dbLeaderLock is a session-level advisory lock, held by the connection that took it for as long
+// as the connection lives
+type dbLeaderLock struct {
+	conn *sql.Conn
+	key  int64
+}
+
+// TryLeaderLock takes the advisory lock of name on a connection of its own, which is taken out
+// of the pool until the lock is released
+func (s *DBService) TryLeaderLock(ctx context.Context, name string) (domain.LeaderLock, error) {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get a connection for the leader lock: %w", err)
+	}
+
+	key := advisoryLockKey(name)
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to take the leader lock: %w", err)
+	}
+	if !locked {
+		conn.Close()
+		return nil, nil
+	}
+	return &dbLeaderLock{conn: conn, key: key}, nil
+}
+
+// Check makes sure the connection still holds the lock
+func (l *dbLeaderLock) Check(ctx context.Context) error {
+	var held bool
+	err := l.conn.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM pg_locks
+			WHERE locktype = 'advisory' AND objsubid = 1 AND pid = pg_backend_pid() AND granted
+				AND ((classid::bigint << 32) | objid::bigint) = $1
+		)
+	`, l.key).Scan(&held)
+	if err != nil {
+		return fmt.Errorf("failed to check the leader lock: %w", err)
+	}
+	if !held {
+		return errors.New("leader lock is no longer held")
+	}
+	return nil
+}
+
+// Release unlocks the lock and returns the connection; closing it releases the lock as well
+// should the unlock fail
+func (l *dbLeaderLock) Release() error {
+	_, err := l.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", l.key)
+	l.conn.Close()
+	if err != nil {
+		return fmt.Errorf("failed to release the leader lock: %w", err)
+	}
+	return nil
+}
+
+// advisoryLockKey turns a lock name into the 64-bit key of an advisory lock
+func advisoryLockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
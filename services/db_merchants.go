@@ -0,0 +1,146 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const merchantColumns = "id, name, aliases, default_category_id, created_at"
+
+// AddMerchant adds a new merchant to the database
+func (s *DBService) AddMerchant(merchant *domain.Merchant) error {
+	s.logger.Debug("Adding merchant to database", "id", merchant.ID, "name", merchant.Name)
+
+	_, err := s.db.Exec(
+		"INSERT INTO merchants ("+merchantColumns+") VALUES ($1, $2, $3, $4, $5)",
+		merchant.ID, merchant.Name, pq.Array(merchant.Aliases), nullUUID(merchant.DefaultCategoryId), merchant.CreatedAt,
+	)
+	if err != nil {
+		s.logger.Error("Error inserting merchant", "error", err, "id", merchant.ID)
+		return fmt.Errorf("error inserting merchant: %w", err)
+	}
+
+	s.logger.Info("Merchant added successfully", "id", merchant.ID)
+	return nil
+}
+
+// GetMerchantByID retrieves a merchant by its ID
+func (s *DBService) GetMerchantByID(id string) (*domain.Merchant, error) {
+	s.logger.Debug("Getting merchant by ID", "id", id)
+
+	merchantID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	merchant, err := scanMerchant(s.db.QueryRow("SELECT "+merchantColumns+" FROM merchants WHERE id = $1", merchantID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Merchant not found", "id", id)
+			return nil, domain.ErrMerchantNotFound
+		}
+		s.logger.Error("Error querying merchant", "error", err, "id", id)
+		return nil, fmt.Errorf("error querying merchant: %w", err)
+	}
+
+	return merchant, nil
+}
+
+// GetAllMerchants retrieves all merchants ordered by name
+func (s *DBService) GetAllMerchants() ([]*domain.Merchant, error) {
+	s.logger.Debug("Getting all merchants")
+
+	rows, err := s.db.Query("SELECT " + merchantColumns + " FROM merchants ORDER BY name")
+	if err != nil {
+		s.logger.Error("Error querying all merchants", "error", err)
+		return nil, fmt.Errorf("error querying all merchants: %w", err)
+	}
+	defer rows.Close()
+
+	var merchants []*domain.Merchant
+	for rows.Next() {
+		merchant, err := scanMerchant(rows)
+		if err != nil {
+			s.logger.Error("Error scanning merchant row", "error", err)
+			return nil, fmt.Errorf("error scanning merchant row: %w", err)
+		}
+		merchants = append(merchants, merchant)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating merchant rows", "error", err)
+		return nil, fmt.Errorf("error iterating merchant rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved all merchants", "count", len(merchants))
+	return merchants, nil
+}
+
+// UpdateMerchant updates an existing merchant
+func (s *DBService) UpdateMerchant(merchant *domain.Merchant) error {
+	s.logger.Debug("Updating merchant", "id", merchant.ID, "name", merchant.Name)
+
+	result, err := s.db.Exec(
+		"UPDATE merchants SET name = $1, aliases = $2, default_category_id = $3 WHERE id = $4",
+		merchant.Name, pq.Array(merchant.Aliases), nullUUID(merchant.DefaultCategoryId), merchant.ID,
+	)
+	if err != nil {
+		s.logger.Error("Error updating merchant", "error", err, "id", merchant.ID)
+		return fmt.Errorf("error updating merchant: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Merchant not found for update", "id", merchant.ID)
+		return domain.ErrMerchantNotFound
+	}
+
+	s.logger.Info("Merchant updated successfully", "id", merchant.ID)
+	return nil
+}
+
+// DeleteMerchant deletes a merchant by its ID
+func (s *DBService) DeleteMerchant(id string) error {
+	s.logger.Debug("Deleting merchant", "id", id)
+
+	merchantID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	result, err := s.db.Exec("DELETE FROM merchants WHERE id = $1", merchantID)
+	if err != nil {
+		s.logger.Error("Error deleting merchant", "error", err, "id", id)
+		return fmt.Errorf("error deleting merchant: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Merchant not found for deletion", "id", id)
+		return domain.ErrMerchantNotFound
+	}
+
+	s.logger.Info("Merchant deleted successfully", "id", id)
+	return nil
+}
+
+func scanMerchant(row rowScanner) (*domain.Merchant, error) {
+	var merchant domain.Merchant
+	var defaultCategoryID uuid.NullUUID
+
+	err := row.Scan(&merchant.ID, &merchant.Name, pq.Array(&merchant.Aliases), &defaultCategoryID, &merchant.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	merchant.DefaultCategoryId = defaultCategoryID.UUID
+	if merchant.Aliases == nil {
+		merchant.Aliases = []string{}
+	}
+	return &merchant, nil
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const outboxColumns = "id, topic, expenditure_id, payload, created_at, attempts, next_attempt_at, last_error"
+
+// setupOutbox creates the outbox table and the function the outbox trigger runs. The function
+// writes the expenditure in its JSON form, so the relay does not need to know the columns
+func setupOutbox(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS outbox (
+			id BIGSERIAL PRIMARY KEY,
+			topic TEXT NOT NULL,
+			expenditure_id UUID NOT NULL,
+			payload JSONB NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at TIMESTAMPTZ DEFAULT now(),
+			last_error TEXT NOT NULL DEFAULT ''
+		);
+
+		CREATE OR REPLACE FUNCTION write_expenditure_outbox() RETURNS trigger AS $$
+		DECLARE
+			r expenditures;
+			topic TEXT;
+		BEGIN
+			IF TG_OP = 'DELETE' THEN
+				r := OLD;
+				topic := 'expenditure.deleted';
+			ELSIF TG_OP = 'UPDATE' THEN
+				r := NEW;
+				topic := 'expenditure.updated';
+			ELSE
+				r := NEW;
+				topic := 'expenditure.created';
+			END IF;
+
+			INSERT INTO outbox (topic, expenditure_id, payload) VALUES (topic, r.id, jsonb_build_object(
+				'id', r.id,
+				'description', r.description,
+				'amount', r.amount,
+				'date', to_char(r.date, 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"'),
+				'category_id', COALESCE(r.category_id, '00000000-0000-0000-0000-000000000000'),
+				'tags', r.tags,
+				'quantity', r.quantity,
+				'unit_price', r.unit_price,
+				'unit', r.unit,
+				'tax_rate', r.tax_rate,
+				'tax_amount', r.tax_amount,
+				'merchant_id', COALESCE(r.merchant_id, '00000000-0000-0000-0000-000000000000'),
+				'location', CASE WHEN r.latitude IS NOT NULL AND r.longitude IS NOT NULL THEN jsonb_build_object(
+					'latitude', r.latitude,
+					'longitude', r.longitude,
+					'place_name', r.place_name,
+					'city', r.city
+				) END,
+				'planned', r.planned,
+				'refund_of', COALESCE(r.refund_of, '00000000-0000-0000-0000-000000000000'),
+				'status', r.status,
+				'account_id', COALESCE(r.account_id, '00000000-0000-0000-0000-000000000000'),
+				'reconciled', r.reconciled,
+				'original_amount', r.original_amount,
+				'original_currency', r.original_currency,
+				'created_by', COALESCE(r.created_by, '00000000-0000-0000-0000-000000000000')
+			));
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create outbox: %w", err)
+	}
+	return nil
+}
+
+// EnableOutbox creates or drops the trigger writing an outbox message with every change to
+// expenditures, in the same transaction as the change
+func (s *DBService) EnableOutbox(enabled bool) error {
+	s.logger.Debug("Setting outbox", "enabled", enabled)
+
+	statement := "DROP TRIGGER IF EXISTS expenditures_outbox ON expenditures"
+	if enabled {
+		statement += `;
+			CREATE TRIGGER expenditures_outbox
+				AFTER INSERT OR UPDATE OR DELETE ON expenditures
+				FOR EACH ROW EXECUTE FUNCTION write_expenditure_outbox()`
+	}
+
+	if _, err := s.db.Exec(statement); err != nil {
+		s.logger.Error("Error setting outbox trigger", "error", err, "enabled", enabled)
+		return fmt.Errorf("error setting outbox trigger: %w", err)
+	}
+
+	s.logger.Info("Outbox set successfully", "enabled", enabled)
+	return nil
+}
+
+// GetPendingOutboxMessages retrieves up to limit undelivered messages, oldest first
+func (s *DBService) GetPendingOutboxMessages(limit int) ([]*domain.OutboxMessage, error) {
+	s.logger.Debug("Getting pending outbox messages", "limit", limit)
+
+	messages, err := s.queryOutboxMessages("SELECT "+outboxColumns+" FROM outbox WHERE next_attempt_at IS NOT NULL ORDER BY id LIMIT $1", limit)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug("Retrieved pending outbox messages", "count", len(messages))
+	return messages, nil
+}
+
+// GetFailedOutboxMessages retrieves up to limit messages given up on, oldest first
+func (s *DBService) GetFailedOutboxMessages(limit int) ([]*domain.OutboxMessage, error) {
+	s.logger.Debug("Getting failed outbox messages", "limit", limit)
+
+	messages, err := s.queryOutboxMessages("SELECT "+outboxColumns+" FROM outbox WHERE next_attempt_at IS NULL ORDER BY id LIMIT $1", limit)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug("Retrieved failed outbox messages", "count", len(messages))
+	return messages, nil
+}
+
+func (s *DBService) queryOutboxMessages(query string, args ...any) ([]*domain.OutboxMessage, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		s.logger.Error("Error querying outbox messages", "error", err)
+		return nil, fmt.Errorf("error querying outbox messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*domain.OutboxMessage
+	for rows.Next() {
+		var message domain.OutboxMessage
+		var payload []byte
+		var nextAttemptAt sql.NullTime
+		err := rows.Scan(&message.ID, &message.Topic, &message.ExpenditureID, &payload, &message.CreatedAt,
+			&message.Attempts, &nextAttemptAt, &message.LastError)
+		if err != nil {
+			s.logger.Error("Error scanning outbox message row", "error", err)
+			return nil, fmt.Errorf("error scanning outbox message row: %w", err)
+		}
+		if err := json.Unmarshal(payload, &message.Expenditure); err != nil {
+			return nil, fmt.Errorf("error decoding outbox message: %w", err)
+		}
+		message.NextAttemptAt = nextAttemptAt.Time
+		messages = append(messages, &message)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating outbox message rows", "error", err)
+		return nil, fmt.Errorf("error iterating outbox message rows: %w", err)
+	}
+	return messages, nil
+}
+
+// MarkOutboxMessageDelivered removes a delivered message from the outbox
+func (s *DBService) MarkOutboxMessageDelivered(id int64) error {
+	s.logger.Debug("Marking outbox message delivered", "id", id)
+
+	result, err := s.db.Exec("DELETE FROM outbox WHERE id = $1", id)
+	if err != nil {
+		s.logger.Error("Error deleting outbox message", "error", err, "id", id)
+		return fmt.Errorf("error deleting outbox message: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Outbox message not found", "id", id)
+		return domain.ErrOutboxMessageNotFound
+	}
+
+	s.logger.Info("Outbox message delivered", "id", id)
+	return nil
+}
+
+// MarkOutboxMessageFailed records a failed delivery and when to try again
+func (s *DBService) MarkOutboxMessageFailed(id int64, reason string, retryAt time.Time) error {
+	s.logger.Debug("Marking outbox message failed", "id", id, "reason", reason)
+
+	var next sql.NullTime
+	if !retryAt.IsZero() {
+		next = sql.NullTime{Time: retryAt, Valid: true}
+	}
+
+	result, err := s.db.Exec("UPDATE outbox SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2 WHERE id = $3", reason, next, id)
+	if err != nil {
+		s.logger.Error("Error updating outbox message", "error", err, "id", id)
+		return fmt.Errorf("error updating outbox message: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Outbox message not found", "id", id)
+		return domain.ErrOutboxMessageNotFound
+	}
+
+	s.logger.Info("Outbox message failed", "id", id, "retry_at", retryAt)
+	return nil
+}
+
+// RedeliverOutboxMessage queues a message given up on for delivery again, with a fresh count of
+// attempts
+func (s *DBService) RedeliverOutboxMessage(id int64) error {
+	s.logger.Debug("Redelivering outbox message", "id", id)
+
+	var failed bool
+	err := s.db.QueryRow("SELECT next_attempt_at IS NULL FROM outbox WHERE id = $1", id).Scan(&failed)
+	if errors.Is(err, sql.ErrNoRows) {
+		s.logger.Warn("Outbox message not found", "id", id)
+		return domain.ErrOutboxMessageNotFound
+	}
+	if err != nil {
+		s.logger.Error("Error querying outbox message", "error", err, "id", id)
+		return fmt.Errorf("error querying outbox message: %w", err)
+	}
+	if !failed {
+		return domain.ErrOutboxMessageNotFailed
+	}
+
+	// The condition keeps a message the relay picked up in between as it is
+	result, err := s.db.Exec("UPDATE outbox SET attempts = 0, next_attempt_at = now() WHERE id = $1 AND next_attempt_at IS NULL", id)
+	if err != nil {
+		s.logger.Error("Error updating outbox message", "error", err, "id", id)
+		return fmt.Errorf("error updating outbox message: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return domain.ErrOutboxMessageNotFailed
+	}
+
+	s.logger.Info("Outbox message queued for redelivery", "id", id)
+	return nil
+}
+
+// ScrubOutboxMessages removes the member from the expenditure states of the messages not
+// delivered yet
+func (s *DBService) ScrubOutboxMessages(member uuid.UUID) (int, error) {
+	s.logger.Debug("Scrubbing outbox messages", "member_id", member)
+
+	if member == uuid.Nil {
+		return 0, nil
+	}
+	result, err := s.db.Exec("UPDATE outbox SET payload = payload - 'created_by' WHERE payload->>'created_by' = $1", member.String())
+	if err != nil {
+		s.logger.Error("Error scrubbing outbox messages", "error", err, "member_id", member)
+		return 0, fmt.Errorf("error scrubbing outbox messages: %w", err)
+	}
+	affected, _ := result.RowsAffected()
+
+	s.logger.Info("Outbox messages scrubbed successfully", "member_id", member, "count", affected)
+	return int(affected), nil
+}
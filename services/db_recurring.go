@@ -0,0 +1,187 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const recurringColumns = "id, description, amount, category_id, tags, frequency, start_date, end_date, occurrences, next_date, paused, paused_until, created_at"
+
+// setupRecurring creates the recurring expenditures table; next_date is NULL once the end date
+// has been reached
+func setupRecurring(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS recurring_expenditures (
+			id UUID PRIMARY KEY,
+			description TEXT NOT NULL,
+			amount DECIMAL(10, 2) NOT NULL,
+			category_id UUID,
+			tags TEXT[] NOT NULL DEFAULT '{}',
+			frequency TEXT NOT NULL,
+			start_date DATE NOT NULL,
+			end_date DATE,
+			occurrences INTEGER NOT NULL DEFAULT 0,
+			next_date DATE,
+			paused BOOLEAN NOT NULL DEFAULT FALSE,
+			paused_until DATE,
+			created_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create recurring_expenditures table: %w", err)
+	}
+	return nil
+}
+
+// AddRecurring adds a new recurring expenditure to the database
+func (s *DBService) AddRecurring(recurring *domain.RecurringExpenditure) error {
+	s.logger.Debug("Adding recurring expenditure to database", "id", recurring.ID, "description", recurring.Description, "frequency", recurring.Frequency)
+
+	_, err := s.db.Exec(
+		"INSERT INTO recurring_expenditures ("+recurringColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)",
+		recurring.ID, recurring.Description, recurring.Amount, nullUUID(recurring.CategoryId), pq.Array(recurring.Tags),
+		recurring.Frequency, recurring.StartDate, recurring.EndDate, recurring.Occurrences, recurring.NextDate,
+		recurring.Paused, recurring.PausedUntil, recurring.CreatedAt,
+	)
+	if err != nil {
+		s.logger.Error("Error inserting recurring expenditure", "error", err, "id", recurring.ID)
+		return fmt.Errorf("error inserting recurring expenditure: %w", err)
+	}
+
+	s.logger.Info("Recurring expenditure added successfully", "id", recurring.ID)
+	return nil
+}
+
+// GetRecurringByID retrieves a recurring expenditure by its ID
+func (s *DBService) GetRecurringByID(id string) (*domain.RecurringExpenditure, error) {
+	s.logger.Debug("Getting recurring expenditure by ID", "id", id)
+
+	recurringID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	recurring, err := scanRecurring(s.db.QueryRow("SELECT "+recurringColumns+" FROM recurring_expenditures WHERE id = $1", recurringID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Recurring expenditure not found", "id", id)
+			return nil, domain.ErrRecurringNotFound
+		}
+		s.logger.Error("Error querying recurring expenditure", "error", err, "id", id)
+		return nil, fmt.Errorf("error querying recurring expenditure: %w", err)
+	}
+
+	return recurring, nil
+}
+
+// GetAllRecurring retrieves all recurring expenditures in the order they were created
+func (s *DBService) GetAllRecurring() ([]*domain.RecurringExpenditure, error) {
+	s.logger.Debug("Getting all recurring expenditures")
+
+	rows, err := s.db.Query("SELECT " + recurringColumns + " FROM recurring_expenditures ORDER BY created_at")
+	if err != nil {
+		s.logger.Error("Error querying all recurring expenditures", "error", err)
+		return nil, fmt.Errorf("error querying all recurring expenditures: %w", err)
+	}
+	defer rows.Close()
+
+	var recurring []*domain.RecurringExpenditure
+	for rows.Next() {
+		r, err := scanRecurring(rows)
+		if err != nil {
+			s.logger.Error("Error scanning recurring expenditure row", "error", err)
+			return nil, fmt.Errorf("error scanning recurring expenditure row: %w", err)
+		}
+		recurring = append(recurring, r)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating recurring expenditure rows", "error", err)
+		return nil, fmt.Errorf("error iterating recurring expenditure rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved all recurring expenditures", "count", len(recurring))
+	return recurring, nil
+}
+
+// UpdateRecurring updates an existing recurring expenditure
+func (s *DBService) UpdateRecurring(recurring *domain.RecurringExpenditure) error {
+	s.logger.Debug("Updating recurring expenditure", "id", recurring.ID, "occurrences", recurring.Occurrences, "paused", recurring.Paused)
+
+	result, err := s.db.Exec(
+		`UPDATE recurring_expenditures SET description = $1, amount = $2, category_id = $3, tags = $4, frequency = $5,
+			start_date = $6, end_date = $7, occurrences = $8, next_date = $9, paused = $10, paused_until = $11 WHERE id = $12`,
+		recurring.Description, recurring.Amount, nullUUID(recurring.CategoryId), pq.Array(recurring.Tags), recurring.Frequency,
+		recurring.StartDate, recurring.EndDate, recurring.Occurrences, recurring.NextDate, recurring.Paused, recurring.PausedUntil, recurring.ID,
+	)
+	if err != nil {
+		s.logger.Error("Error updating recurring expenditure", "error", err, "id", recurring.ID)
+		return fmt.Errorf("error updating recurring expenditure: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Recurring expenditure not found for update", "id", recurring.ID)
+		return domain.ErrRecurringNotFound
+	}
+
+	s.logger.Info("Recurring expenditure updated successfully", "id", recurring.ID)
+	return nil
+}
+
+// DeleteRecurring deletes a recurring expenditure by its ID; the expenditures it generated stay
+func (s *DBService) DeleteRecurring(id string) error {
+	s.logger.Debug("Deleting recurring expenditure", "id", id)
+
+	recurringID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	result, err := s.db.Exec("DELETE FROM recurring_expenditures WHERE id = $1", recurringID)
+	if err != nil {
+		s.logger.Error("Error deleting recurring expenditure", "error", err, "id", id)
+		return fmt.Errorf("error deleting recurring expenditure: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Recurring expenditure not found for deletion", "id", id)
+		return domain.ErrRecurringNotFound
+	}
+
+	s.logger.Info("Recurring expenditure deleted successfully", "id", id)
+	return nil
+}
+
+func scanRecurring(row rowScanner) (*domain.RecurringExpenditure, error) {
+	var recurring domain.RecurringExpenditure
+	var categoryID uuid.NullUUID
+	var endDate, nextDate, pausedUntil sql.NullTime
+	err := row.Scan(&recurring.ID, &recurring.Description, &recurring.Amount, &categoryID, pq.Array(&recurring.Tags),
+		&recurring.Frequency, &recurring.StartDate, &endDate, &recurring.Occurrences, &nextDate,
+		&recurring.Paused, &pausedUntil, &recurring.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	recurring.CategoryId = categoryID.UUID
+	if endDate.Valid {
+		recurring.EndDate = &endDate.Time
+	}
+	if nextDate.Valid {
+		recurring.NextDate = &nextDate.Time
+	}
+	if pausedUntil.Valid {
+		recurring.PausedUntil = &pausedUntil.Time
+	}
+	if recurring.Tags == nil {
+		recurring.Tags = []string{}
+	}
+	return &recurring, nil
+}
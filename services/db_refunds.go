@@ -0,0 +1,69 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+)
+
+// refundQueries lock an expenditure and sum its refunds within a transaction, in the placeholder
+// style of a database. Refunds recorded at the same time then cannot together exceed their
+// original, nor an update lower it below them
+type refundQueries struct {
+	lock string // Locks the expenditure and reads its amount
+	sum  string // Sums the refunds of the expenditure as a positive amount
+}
+
+var pgRefundQueries = refundQueries{
+	lock: "SELECT amount FROM expenditures WHERE id = $1 FOR UPDATE",
+	sum:  "SELECT COALESCE(SUM(-amount), 0) FROM expenditures WHERE refund_of = $1",
+}
+
+var mysqlRefundQueries = refundQueries{
+	lock: "SELECT amount FROM expenditures WHERE id = ? FOR UPDATE",
+	sum:  "SELECT COALESCE(SUM(-amount), 0) FROM expenditures WHERE refund_of = ?",
+}
+
+// lockRefunded locks the expenditure id until tx ends and returns its amount and the sum of its
+// refunds; domain.ErrExpenditureNotFound when it does not exist
+func lockRefunded(tx *sql.Tx, queries refundQueries, id uuid.UUID) (amount, refunded float64, err error) {
+	if err := tx.QueryRow(queries.lock, id).Scan(&amount); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, 0, domain.ErrExpenditureNotFound
+		}
+		return 0, 0, fmt.Errorf("error locking expenditure: %w", err)
+	}
+	if err := tx.QueryRow(queries.sum, id).Scan(&refunded); err != nil {
+		return 0, 0, fmt.Errorf("error summing refunds: %w", err)
+	}
+	return amount, refunded, nil
+}
+
+// checkRefund locks the original of a refund and checks that, with the refunds recorded before,
+// the refund does not exceed it
+func checkRefund(tx *sql.Tx, queries refundQueries, refund *domain.Expenditure) error {
+	amount, refunded, err := lockRefunded(tx, queries, refund.RefundOf)
+	if err != nil {
+		return err
+	}
+	if !domain.RefundsWithin(amount, refunded-refund.Amount) {
+		return domain.ErrRefundExceedsOriginal
+	}
+	return nil
+}
+
+// checkRefunded locks an expenditure about to change and checks its new amount still covers
+// its refunds
+func checkRefunded(tx *sql.Tx, queries refundQueries, expenditure *domain.Expenditure) error {
+	_, refunded, err := lockRefunded(tx, queries, expenditure.ID)
+	if err != nil {
+		return err
+	}
+	if !domain.RefundsWithin(expenditure.Amount, refunded) {
+		return domain.ErrAmountBelowRefunded
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+)
+
+const reportSnapshotColumns = "id, month, from_date, to_date, taken_at, count, total, categories"
+
+// setupReportSnapshots creates the table of report snapshots. The spending per category is kept
+// as JSON, with the category names at the time, and is never updated
+func setupReportSnapshots(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS report_snapshots (
+			id UUID PRIMARY KEY,
+			month TEXT NOT NULL,
+			from_date DATE NOT NULL,
+			to_date DATE NOT NULL,
+			taken_at TIMESTAMP NOT NULL,
+			count INTEGER NOT NULL,
+			total DECIMAL(12, 2) NOT NULL,
+			categories JSONB NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS report_snapshots_month ON report_snapshots (month DESC, taken_at DESC)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create report snapshots table: %w", err)
+	}
+	return nil
+}
+
+// AddReportSnapshot saves a new report snapshot
+func (s *DBService) AddReportSnapshot(snapshot *domain.ReportSnapshot) error {
+	s.logger.Debug("Adding report snapshot to database", "id", snapshot.ID, "month", snapshot.Month)
+
+	categories, err := json.Marshal(snapshot.Categories)
+	if err != nil {
+		return fmt.Errorf("error encoding report snapshot categories: %w", err)
+	}
+
+	_, err = s.db.Exec(
+		"INSERT INTO report_snapshots ("+reportSnapshotColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		snapshot.ID, snapshot.Month, snapshot.From, snapshot.To, snapshot.TakenAt, snapshot.Count, snapshot.Total, categories,
+	)
+	if err != nil {
+		s.logger.Error("Error inserting report snapshot", "error", err, "id", snapshot.ID)
+		return fmt.Errorf("error inserting report snapshot: %w", err)
+	}
+
+	s.logger.Info("Report snapshot added successfully", "id", snapshot.ID, "month", snapshot.Month)
+	return nil
+}
+
+// GetReportSnapshotByID retrieves a report snapshot by its ID
+func (s *DBService) GetReportSnapshotByID(id string) (*domain.ReportSnapshot, error) {
+	s.logger.Debug("Getting report snapshot by ID", "id", id)
+
+	snapshotID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	snapshot, err := scanReportSnapshot(s.db.QueryRow("SELECT "+reportSnapshotColumns+" FROM report_snapshots WHERE id = $1", snapshotID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Report snapshot not found", "id", id)
+			return nil, domain.ErrReportSnapshotNotFound
+		}
+		s.logger.Error("Error querying report snapshot", "error", err, "id", id)
+		return nil, fmt.Errorf("error querying report snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// GetAllReportSnapshots retrieves all report snapshots, latest month first
+func (s *DBService) GetAllReportSnapshots() ([]*domain.ReportSnapshot, error) {
+	s.logger.Debug("Getting all report snapshots")
+
+	rows, err := s.db.Query("SELECT " + reportSnapshotColumns + " FROM report_snapshots ORDER BY month DESC, taken_at DESC")
+	if err != nil {
+		s.logger.Error("Error querying report snapshots", "error", err)
+		return nil, fmt.Errorf("error querying report snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []*domain.ReportSnapshot
+	for rows.Next() {
+		snapshot, err := scanReportSnapshot(rows)
+		if err != nil {
+			s.logger.Error("Error scanning report snapshot row", "error", err)
+			return nil, fmt.Errorf("error scanning report snapshot row: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating report snapshot rows", "error", err)
+		return nil, fmt.Errorf("error iterating report snapshot rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved all report snapshots", "count", len(snapshots))
+	return snapshots, nil
+}
+
+func scanReportSnapshot(row rowScanner) (*domain.ReportSnapshot, error) {
+	var snapshot domain.ReportSnapshot
+	var categories []byte
+	err := row.Scan(&snapshot.ID, &snapshot.Month, &snapshot.From, &snapshot.To, &snapshot.TakenAt, &snapshot.Count, &snapshot.Total, &categories)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(categories, &snapshot.Categories); err != nil {
+		return nil, fmt.Errorf("error decoding report snapshot categories: %w", err)
+	}
+	return &snapshot, nil
+}
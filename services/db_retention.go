@@ -0,0 +1,90 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const retentionPurgeColumns = "id, target, age, cutoff, count, ids, purged_at"
+
+// setupRetentionPurges creates the audit log of retention purges, which is only ever appended to
+func setupRetentionPurges(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS retention_purges (
+			id UUID PRIMARY KEY,
+			target TEXT NOT NULL,
+			age TEXT NOT NULL,
+			cutoff TIMESTAMP NOT NULL,
+			count INTEGER NOT NULL,
+			ids UUID[] NOT NULL,
+			purged_at TIMESTAMP NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS retention_purges_purged_at ON retention_purges (purged_at DESC)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create retention purges table: %w", err)
+	}
+	return nil
+}
+
+// AddRetentionPurge saves the audit entry of a purge
+func (s *DBService) AddRetentionPurge(purge *domain.RetentionPurge) error {
+	s.logger.Debug("Adding retention purge to database", "id", purge.ID, "target", purge.Target)
+
+	_, err := s.db.Exec(
+		"INSERT INTO retention_purges ("+retentionPurgeColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		purge.ID, purge.Target, purge.Age, purge.Before, purge.Count, pq.Array(uuidStrings(purge.IDs)), purge.PurgedAt,
+	)
+	if err != nil {
+		s.logger.Error("Error inserting retention purge", "error", err, "id", purge.ID)
+		return fmt.Errorf("error inserting retention purge: %w", err)
+	}
+
+	s.logger.Info("Retention purge added successfully", "id", purge.ID, "target", purge.Target, "count", purge.Count)
+	return nil
+}
+
+// GetAllRetentionPurges retrieves the audit log of purges, latest first
+func (s *DBService) GetAllRetentionPurges() ([]*domain.RetentionPurge, error) {
+	s.logger.Debug("Getting all retention purges")
+
+	rows, err := s.db.Query("SELECT " + retentionPurgeColumns + " FROM retention_purges ORDER BY purged_at DESC, id")
+	if err != nil {
+		s.logger.Error("Error querying retention purges", "error", err)
+		return nil, fmt.Errorf("error querying retention purges: %w", err)
+	}
+	defer rows.Close()
+
+	purges := []*domain.RetentionPurge{}
+	for rows.Next() {
+		var purge domain.RetentionPurge
+		var ids []string
+		if err := rows.Scan(&purge.ID, &purge.Target, &purge.Age, &purge.Before, &purge.Count, pq.Array(&ids), &purge.PurgedAt); err != nil {
+			s.logger.Error("Error scanning retention purge row", "error", err)
+			return nil, fmt.Errorf("error scanning retention purge row: %w", err)
+		}
+
+		purge.IDs = make([]uuid.UUID, 0, len(ids))
+		for _, value := range ids {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ID in retention purge: %w", err)
+			}
+			purge.IDs = append(purge.IDs, id)
+		}
+		purges = append(purges, &purge)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating retention purge rows", "error", err)
+		return nil, fmt.Errorf("error iterating retention purge rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved all retention purges", "count", len(purges))
+	return purges, nil
+}
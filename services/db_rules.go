@@ -0,0 +1,170 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const ruleColumns = "id, name, contains, category_id, tags, account_id, merchant_id, created_at"
+
+// setupCategorizationRules creates the table of rules applied to imported entries
+func setupCategorizationRules(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS categorization_rules (
+			id UUID PRIMARY KEY,
+			name TEXT NOT NULL,
+			contains TEXT NOT NULL,
+			category_id UUID,
+			tags TEXT[] NOT NULL DEFAULT '{}',
+			account_id UUID,
+			merchant_id UUID,
+			created_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create categorization rules table: %w", err)
+	}
+	return nil
+}
+
+// AddRule adds a new categorization rule to the database
+func (s *DBService) AddRule(rule *domain.CategorizationRule) error {
+	s.logger.Debug("Adding rule to database", "id", rule.ID, "name", rule.Name)
+
+	_, err := s.db.Exec(
+		"INSERT INTO categorization_rules ("+ruleColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		rule.ID, rule.Name, rule.Contains, nullUUID(rule.CategoryId), pq.Array(rule.Tags),
+		nullUUID(rule.AccountId), nullUUID(rule.MerchantId), rule.CreatedAt,
+	)
+	if err != nil {
+		s.logger.Error("Error inserting rule", "error", err, "id", rule.ID)
+		return fmt.Errorf("error inserting rule: %w", err)
+	}
+
+	s.logger.Info("Rule added successfully", "id", rule.ID)
+	return nil
+}
+
+// GetRuleByID retrieves a categorization rule by its ID
+func (s *DBService) GetRuleByID(id string) (*domain.CategorizationRule, error) {
+	s.logger.Debug("Getting rule by ID", "id", id)
+
+	ruleID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	rule, err := scanRule(s.db.QueryRow("SELECT "+ruleColumns+" FROM categorization_rules WHERE id = $1", ruleID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Rule not found", "id", id)
+			return nil, domain.ErrRuleNotFound
+		}
+		s.logger.Error("Error querying rule", "error", err, "id", id)
+		return nil, fmt.Errorf("error querying rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// GetAllRules retrieves all categorization rules in evaluation order, oldest first
+func (s *DBService) GetAllRules() ([]*domain.CategorizationRule, error) {
+	s.logger.Debug("Getting all rules")
+
+	rows, err := s.db.Query("SELECT " + ruleColumns + " FROM categorization_rules ORDER BY created_at, id")
+	if err != nil {
+		s.logger.Error("Error querying all rules", "error", err)
+		return nil, fmt.Errorf("error querying all rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []*domain.CategorizationRule{}
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			s.logger.Error("Error scanning rule row", "error", err)
+			return nil, fmt.Errorf("error scanning rule row: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating rule rows", "error", err)
+		return nil, fmt.Errorf("error iterating rule rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved all rules", "count", len(rules))
+	return rules, nil
+}
+
+// UpdateRule updates an existing categorization rule
+func (s *DBService) UpdateRule(rule *domain.CategorizationRule) error {
+	s.logger.Debug("Updating rule", "id", rule.ID, "name", rule.Name)
+
+	result, err := s.db.Exec(
+		"UPDATE categorization_rules SET name = $1, contains = $2, category_id = $3, tags = $4, account_id = $5, merchant_id = $6 WHERE id = $7",
+		rule.Name, rule.Contains, nullUUID(rule.CategoryId), pq.Array(rule.Tags),
+		nullUUID(rule.AccountId), nullUUID(rule.MerchantId), rule.ID,
+	)
+	if err != nil {
+		s.logger.Error("Error updating rule", "error", err, "id", rule.ID)
+		return fmt.Errorf("error updating rule: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Rule not found for update", "id", rule.ID)
+		return domain.ErrRuleNotFound
+	}
+
+	s.logger.Info("Rule updated successfully", "id", rule.ID)
+	return nil
+}
+
+// DeleteRule deletes a categorization rule by its ID
+func (s *DBService) DeleteRule(id string) error {
+	s.logger.Debug("Deleting rule", "id", id)
+
+	ruleID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	result, err := s.db.Exec("DELETE FROM categorization_rules WHERE id = $1", ruleID)
+	if err != nil {
+		s.logger.Error("Error deleting rule", "error", err, "id", id)
+		return fmt.Errorf("error deleting rule: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Rule not found for deletion", "id", id)
+		return domain.ErrRuleNotFound
+	}
+
+	s.logger.Info("Rule deleted successfully", "id", id)
+	return nil
+}
+
+func scanRule(row rowScanner) (*domain.CategorizationRule, error) {
+	var rule domain.CategorizationRule
+	var categoryID, accountID, merchantID uuid.NullUUID
+
+	err := row.Scan(&rule.ID, &rule.Name, &rule.Contains, &categoryID, pq.Array(&rule.Tags), &accountID, &merchantID, &rule.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.CategoryId = categoryID.UUID
+	rule.AccountId = accountID.UUID
+	rule.MerchantId = merchantID.UUID
+	if rule.Tags == nil {
+		rule.Tags = []string{}
+	}
+	return &rule, nil
+}
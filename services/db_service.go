@@ -6,21 +6,25 @@ import (
 	"fmt"
 	"go-expense-tracker/domain"
 	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
-	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/lib/pq" // PostgreSQL driver
 )
 
+const expenditureColumns = "id, description, amount, date, category_id, tags, quantity, unit_price, unit, tax_rate, tax_amount, merchant_id, latitude, longitude, place_name, city, planned, refund_of, status, account_id, reconciled, original_amount, original_currency, created_by"
+
 // DBService implements the ExpenditureRepository interface using PostgreSQL
 type DBService struct {
 	db     *sql.DB
 	logger *slog.Logger
 }
 
-// NewDBService creates a new DBService with the given connection parameters
-func NewDBService(host string, port int, user, password, dbname string, logger *slog.Logger) (*DBService, error) {
-	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-		host, port, user, password, dbname)
+// NewDBService creates a new DBService with the given connection parameters. Statements running
+// longer than statementTimeout are cancelled by the server; 0 disables the timeout
+func NewDBService(host string, port int, user, password, dbname string, statementTimeout time.Duration, logger *slog.Logger) (*DBService, error) {
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable statement_timeout=%d",
+		host, port, user, password, dbname, statementTimeout.Milliseconds())
 
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
@@ -48,6 +52,317 @@ func NewDBService(host string, port int, user, password, dbname string, logger *
 		return nil, fmt.Errorf("failed to create expenditures table: %w", err)
 	}
 
+	// Add the columns introduced after the table was first created
+	_, err = db.Exec(`
+		ALTER TABLE expenditures
+			ADD COLUMN IF NOT EXISTS category_id UUID,
+			ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}',
+			ADD COLUMN IF NOT EXISTS quantity DECIMAL(12, 4) NOT NULL DEFAULT 0,
+			ADD COLUMN IF NOT EXISTS unit_price DECIMAL(12, 4) NOT NULL DEFAULT 0,
+			ADD COLUMN IF NOT EXISTS unit TEXT NOT NULL DEFAULT '',
+			ADD COLUMN IF NOT EXISTS tax_rate DECIMAL(5, 2) NOT NULL DEFAULT 0,
+			ADD COLUMN IF NOT EXISTS tax_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+			ADD COLUMN IF NOT EXISTS merchant_id UUID,
+			ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION,
+			ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION,
+			ADD COLUMN IF NOT EXISTS place_name TEXT NOT NULL DEFAULT '',
+			ADD COLUMN IF NOT EXISTS city TEXT NOT NULL DEFAULT '',
+			ADD COLUMN IF NOT EXISTS planned BOOLEAN NOT NULL DEFAULT FALSE,
+			ADD COLUMN IF NOT EXISTS refund_of UUID,
+			ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'cleared',
+			ADD COLUMN IF NOT EXISTS account_id UUID,
+			ADD COLUMN IF NOT EXISTS reconciled BOOLEAN NOT NULL DEFAULT FALSE,
+			ADD COLUMN IF NOT EXISTS original_amount DECIMAL(12, 2) NOT NULL DEFAULT 0,
+			ADD COLUMN IF NOT EXISTS original_currency TEXT NOT NULL DEFAULT '',
+			ADD COLUMN IF NOT EXISTS created_by UUID
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate expenditures table: %w", err)
+	}
+
+	// Refunds must point to the expenditure they refund, which cannot be deleted before them.
+	// Existing rows are not validated, so a database holding refunds of deleted expenditures
+	// still starts
+	_, err = db.Exec(`
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'expenditures_refund_of_fkey') THEN
+				ALTER TABLE expenditures ADD CONSTRAINT expenditures_refund_of_fkey
+					FOREIGN KEY (refund_of) REFERENCES expenditures (id) NOT VALID;
+			END IF;
+		END
+		$$
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to add refund foreign key to expenditures table: %w", err)
+	}
+
+	// Create the staged_expenditures table backing the import review queue
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS staged_expenditures (
+			id UUID PRIMARY KEY,
+			source TEXT NOT NULL,
+			reference TEXT NOT NULL,
+			description TEXT NOT NULL,
+			amount DECIMAL(10, 2) NOT NULL,
+			date TIMESTAMP NOT NULL,
+			category_id UUID,
+			raw_text TEXT NOT NULL,
+			status TEXT NOT NULL,
+			expenditure_id UUID,
+			created_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create staged_expenditures table: %w", err)
+	}
+
+	// Create the bank_connections table holding connector credentials
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS bank_connections (
+			id UUID PRIMARY KEY,
+			connector TEXT NOT NULL,
+			name TEXT NOT NULL,
+			access_token TEXT NOT NULL,
+			cursor TEXT NOT NULL DEFAULT '',
+			last_synced_at TIMESTAMP,
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create bank_connections table: %w", err)
+	}
+
+	// Create the goals table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS goals (
+			id UUID PRIMARY KEY,
+			name TEXT NOT NULL,
+			target_amount DECIMAL(10, 2) NOT NULL,
+			start_date TIMESTAMP NOT NULL,
+			deadline TIMESTAMP NOT NULL,
+			category_id UUID NOT NULL
+		)
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create goals table: %w", err)
+	}
+
+	// Create the expense reports table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS expense_reports (
+			id UUID PRIMARY KEY,
+			title TEXT NOT NULL,
+			claimant TEXT NOT NULL DEFAULT '',
+			expenditure_ids UUID[] NOT NULL DEFAULT '{}',
+			status TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			submitted_at TIMESTAMP,
+			approved_at TIMESTAMP,
+			reimbursed_at TIMESTAMP
+		)
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create expense reports table: %w", err)
+	}
+
+	// Add the audit trail used by the approval workflow
+	_, err = db.Exec(`ALTER TABLE expense_reports ADD COLUMN IF NOT EXISTS history JSONB NOT NULL DEFAULT '[]'`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to add history column to expense reports table: %w", err)
+	}
+
+	// Create the categories table and add the default categories
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS categories (
+			id UUID PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE,
+			color TEXT NOT NULL,
+			deductible BOOLEAN NOT NULL DEFAULT FALSE
+		)
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create categories table: %w", err)
+	}
+
+	// Add the parent of nested categories
+	_, err = db.Exec(`ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES categories (id)`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to add parent column to categories table: %w", err)
+	}
+
+	// Add the icon and the user-chosen position of categories
+	_, err = db.Exec(`
+		ALTER TABLE categories
+			ADD COLUMN IF NOT EXISTS icon TEXT NOT NULL DEFAULT '',
+			ADD COLUMN IF NOT EXISTS sort_order INTEGER NOT NULL DEFAULT 0
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to add icon and sort order columns to categories table: %w", err)
+	}
+
+	// Add the active flag of categories, existing categories stay active
+	_, err = db.Exec(`ALTER TABLE categories ADD COLUMN IF NOT EXISTS active BOOLEAN NOT NULL DEFAULT TRUE`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to add active column to categories table: %w", err)
+	}
+
+	// Add the per-transaction limit of categories
+	_, err = db.Exec(`
+		ALTER TABLE categories
+			ADD COLUMN IF NOT EXISTS transaction_limit DECIMAL(10, 2) NOT NULL DEFAULT 0,
+			ADD COLUMN IF NOT EXISTS limit_mode TEXT NOT NULL DEFAULT ''
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to add limit columns to categories table: %w", err)
+	}
+
+	if err = seedCategories(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Create the merchants table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS merchants (
+			id UUID PRIMARY KEY,
+			name TEXT NOT NULL,
+			aliases TEXT[] NOT NULL DEFAULT '{}',
+			default_category_id UUID,
+			created_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create merchants table: %w", err)
+	}
+
+	// Create the archive tables; archived expenditures are kept as JSON so they survive
+	// later changes to the expenditures table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS archives (
+			id UUID PRIMARY KEY,
+			cutoff TIMESTAMP NOT NULL,
+			count INTEGER NOT NULL,
+			total DECIMAL(14, 2) NOT NULL,
+			summaries JSONB NOT NULL DEFAULT '[]',
+			created_at TIMESTAMP NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS archived_expenditures (
+			id UUID PRIMARY KEY,
+			archive_id UUID NOT NULL REFERENCES archives (id),
+			date TIMESTAMP NOT NULL,
+			data JSONB NOT NULL
+		)
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create archive tables: %w", err)
+	}
+
+	// Create the indexes behind date-range and category filters
+	if err = setupIndexes(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Create the table keeping expenditure drafts apart from expenditures
+	if err = setupDrafts(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Create the tables of saved views and pinned expenditures
+	if err = setupViews(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Create the budgets table
+	if err = setupBudgets(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Create the recurring expenditures table
+	if err = setupRecurring(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Create the installment purchases table
+	if err = setupInstallments(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Create the accounts table
+	if err = setupAccounts(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Create the envelope allocations table
+	if err = setupEnvelopes(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Create the report snapshots table
+	if err = setupReportSnapshots(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Create the tables of households and their invitations
+	if err = setupHouseholds(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Create the categorization rules applied to imported entries
+	if err = setupCategorizationRules(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Create the audit log of retention purges
+	if err = setupRetentionPurges(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Create the expenditure event log
+	if err = setupExpenditureEvents(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Create the outbox, written by a trigger once enabled
+	if err = setupOutbox(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Keep the spending per day and category up to date for the reports
+	if err = setupSpendingSummaries(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
 	return &DBService{
 		db:     db,
 		logger: logger,
@@ -67,9 +382,16 @@ func (s *DBService) AddExpenditure(expenditure *domain.Expenditure) error {
 		"amount", expenditure.Amount, 
 		"date", expenditure.Date)
 
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("Error starting transaction", "error", err, "id", expenditure.ID)
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	// Check if expenditure with this ID already exists
 	var exists bool
-	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM expenditures WHERE id = $1)", expenditure.ID).Scan(&exists)
+	err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM expenditures WHERE id = $1)", expenditure.ID).Scan(&exists)
 	if err != nil {
 		s.logger.Error("Error checking if expenditure exists", "error", err, "id", expenditure.ID)
 		return fmt.Errorf("error checking if expenditure exists: %w", err)
@@ -80,16 +402,29 @@ func (s *DBService) AddExpenditure(expenditure *domain.Expenditure) error {
 		return domain.ErrExpenditureAlreadyExists
 	}
 
+	// A refund locks its original, so concurrent refunds are summed one after the other
+	if expenditure.IsRefund() {
+		if err := checkRefund(tx, pgRefundQueries, expenditure); err != nil {
+			s.logger.Warn("Refund rejected", "error", err, "id", expenditure.ID, "refund_of", expenditure.RefundOf)
+			return err
+		}
+	}
+
 	// Insert the expenditure
-	_, err = s.db.Exec(
-		"INSERT INTO expenditures (id, description, amount, date) VALUES ($1, $2, $3, $4)",
-		expenditure.ID, expenditure.Description, expenditure.Amount, expenditure.Date,
+	_, err = tx.Exec(
+		"INSERT INTO expenditures ("+expenditureColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)",
+		expenditureValues(expenditure)...,
 	)
 	if err != nil {
 		s.logger.Error("Error inserting expenditure", "error", err, "id", expenditure.ID)
 		return fmt.Errorf("error inserting expenditure: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("Error committing expenditure", "error", err, "id", expenditure.ID)
+		return fmt.Errorf("error committing expenditure: %w", err)
+	}
+
 	s.logger.Info("Expenditure added successfully", "id", expenditure.ID)
 	return nil
 }
@@ -106,11 +441,10 @@ func (s *DBService) GetExpenditureByID(id string) (*domain.Expenditure, error) {
 	}
 
 	// Query the expenditure
-	var expenditure domain.Expenditure
-	err = s.db.QueryRow(
-		"SELECT id, description, amount, date FROM expenditures WHERE id = $1",
+	expenditure, err := scanExpenditure(s.db.QueryRow(
+		"SELECT "+expenditureColumns+" FROM expenditures WHERE id = $1",
 		expenditureID,
-	).Scan(&expenditure.ID, &expenditure.Description, &expenditure.Amount, &expenditure.Date)
+	))
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -126,7 +460,7 @@ func (s *DBService) GetExpenditureByID(id string) (*domain.Expenditure, error) {
 		"description", expenditure.Description, 
 		"amount", expenditure.Amount, 
 		"date", expenditure.Date)
-	return &expenditure, nil
+	return expenditure, nil
 }
 
 // GetAllExpenditures retrieves all expenditures from the database
@@ -134,7 +468,7 @@ func (s *DBService) GetAllExpenditures() ([]*domain.Expenditure, error) {
 	s.logger.Debug("Getting all expenditures")
 
 	// Query all expenditures
-	rows, err := s.db.Query("SELECT id, description, amount, date FROM expenditures")
+	rows, err := s.db.Query("SELECT " + expenditureColumns + " FROM expenditures")
 	if err != nil {
 		s.logger.Error("Error querying all expenditures", "error", err)
 		return nil, fmt.Errorf("error querying all expenditures: %w", err)
@@ -144,13 +478,12 @@ func (s *DBService) GetAllExpenditures() ([]*domain.Expenditure, error) {
 	// Collect all expenditures
 	var expenditures []*domain.Expenditure
 	for rows.Next() {
-		var expenditure domain.Expenditure
-		err := rows.Scan(&expenditure.ID, &expenditure.Description, &expenditure.Amount, &expenditure.Date)
+		expenditure, err := scanExpenditure(rows)
 		if err != nil {
 			s.logger.Error("Error scanning expenditure row", "error", err)
 			return nil, fmt.Errorf("error scanning expenditure row: %w", err)
 		}
-		expenditures = append(expenditures, &expenditure)
+		expenditures = append(expenditures, expenditure)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -162,6 +495,62 @@ func (s *DBService) GetAllExpenditures() ([]*domain.Expenditure, error) {
 	return expenditures, nil
 }
 
+// CountExpenditures counts the expenditures dated within [from, to); zero bounds are open
+func (s *DBService) CountExpenditures(from, to time.Time) (int, error) {
+	s.logger.Debug("Counting expenditures", "from", from, "to", to)
+
+	query := "SELECT COUNT(*) FROM expenditures WHERE TRUE"
+	var args []interface{}
+	if !from.IsZero() {
+		args = append(args, from)
+		query += fmt.Sprintf(" AND date >= $%d", len(args))
+	}
+	if !to.IsZero() {
+		args = append(args, to)
+		query += fmt.Sprintf(" AND date < $%d", len(args))
+	}
+
+	var count int
+	if err := s.db.QueryRow(query, args...).Scan(&count); err != nil {
+		s.logger.Error("Error counting expenditures", "error", err)
+		return 0, fmt.Errorf("error counting expenditures: %w", err)
+	}
+	return count, nil
+}
+
+// StreamExpenditures passes the expenditures to fn one row at a time straight from the cursor
+func (s *DBService) StreamExpenditures(fn func(*domain.Expenditure) error) error {
+	s.logger.Debug("Streaming all expenditures")
+
+	rows, err := s.db.Query("SELECT " + expenditureColumns + " FROM expenditures")
+	if err != nil {
+		s.logger.Error("Error querying all expenditures", "error", err)
+		return fmt.Errorf("error querying all expenditures: %w", err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		expenditure, err := scanExpenditure(rows)
+		if err != nil {
+			s.logger.Error("Error scanning expenditure row", "error", err)
+			return fmt.Errorf("error scanning expenditure row: %w", err)
+		}
+		if err := fn(expenditure); err != nil {
+			return err
+		}
+		count++
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating expenditure rows", "error", err)
+		return fmt.Errorf("error iterating expenditure rows: %w", err)
+	}
+
+	s.logger.Info("Streamed all expenditures", "count", count)
+	return nil
+}
+
 // UpdateExpenditure updates an existing expenditure
 func (s *DBService) UpdateExpenditure(expenditure *domain.Expenditure) error {
 	s.logger.Debug("Updating expenditure", 
@@ -170,29 +559,52 @@ func (s *DBService) UpdateExpenditure(expenditure *domain.Expenditure) error {
 		"amount", expenditure.Amount, 
 		"date", expenditure.Date)
 
-	// Check if expenditure exists
-	var exists bool
-	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM expenditures WHERE id = $1)", expenditure.ID).Scan(&exists)
+	tx, err := s.db.Begin()
 	if err != nil {
-		s.logger.Error("Error checking if expenditure exists", "error", err, "id", expenditure.ID)
-		return fmt.Errorf("error checking if expenditure exists: %w", err)
+		s.logger.Error("Error starting transaction", "error", err, "id", expenditure.ID)
+		return fmt.Errorf("error starting transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	if !exists {
+	// Lock the expenditure, so no refund is recorded against it while its amount changes
+	err = checkRefunded(tx, pgRefundQueries, expenditure)
+	switch {
+	case errors.Is(err, domain.ErrExpenditureNotFound):
 		s.logger.Warn("Expenditure not found for update", "id", expenditure.ID)
-		return domain.ErrExpenditureNotFound
+		return err
+	case errors.Is(err, domain.ErrAmountBelowRefunded):
+		s.logger.Warn("Expenditure amount below its refunds", "id", expenditure.ID, "amount", expenditure.Amount)
+		return err
+	case err != nil:
+		s.logger.Error("Error checking expenditure refunds", "error", err, "id", expenditure.ID)
+		return err
 	}
 
+	latitude, longitude, placeName, city := locationColumns(expenditure.Location)
+
 	// Update the expenditure
-	_, err = s.db.Exec(
-		"UPDATE expenditures SET description = $1, amount = $2, date = $3 WHERE id = $4",
-		expenditure.Description, expenditure.Amount, expenditure.Date, expenditure.ID,
+	_, err = tx.Exec(
+		`UPDATE expenditures SET description = $1, amount = $2, date = $3, category_id = $4, tags = $5,
+			quantity = $6, unit_price = $7, unit = $8, tax_rate = $9, tax_amount = $10,
+			merchant_id = $11, latitude = $12, longitude = $13, place_name = $14, city = $15, planned = $16, refund_of = $17, status = $18,
+			account_id = $19, reconciled = $20, original_amount = $21, original_currency = $22, created_by = $23 WHERE id = $24`,
+		expenditure.Description, expenditure.Amount, expenditure.Date,
+		nullUUID(expenditure.CategoryId), pq.Array(expenditure.Tags),
+		expenditure.Quantity, expenditure.UnitPrice, expenditure.Unit,
+		expenditure.TaxRate, expenditure.TaxAmount, nullUUID(expenditure.MerchantId),
+		latitude, longitude, placeName, city, expenditure.Planned, nullUUID(expenditure.RefundOf), expenditureStatus(expenditure),
+		nullUUID(expenditure.AccountId), expenditure.Reconciled, expenditure.OriginalAmount, expenditure.OriginalCurrency, nullUUID(expenditure.CreatedBy), expenditure.ID,
 	)
 	if err != nil {
 		s.logger.Error("Error updating expenditure", "error", err, "id", expenditure.ID)
 		return fmt.Errorf("error updating expenditure: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("Error committing expenditure update", "error", err, "id", expenditure.ID)
+		return fmt.Errorf("error committing expenditure update: %w", err)
+	}
+
 	s.logger.Info("Expenditure updated successfully", "id", expenditure.ID)
 	return nil
 }
@@ -223,6 +635,11 @@ func (s *DBService) DeleteExpenditure(id string) error {
 
 	// Delete the expenditure
 	_, err = s.db.Exec("DELETE FROM expenditures WHERE id = $1", expenditureID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		s.logger.Warn("Expenditure with refunds cannot be deleted", "id", id)
+		return domain.ErrExpenditureHasRefunds
+	}
 	if err != nil {
 		s.logger.Error("Error deleting expenditure", "error", err, "id", id)
 		return fmt.Errorf("error deleting expenditure: %w", err)
@@ -231,3 +648,69 @@ func (s *DBService) DeleteExpenditure(id string) error {
 	s.logger.Info("Expenditure deleted successfully", "id", id)
 	return nil
 }
+
+func scanExpenditure(row rowScanner) (*domain.Expenditure, error) {
+	var expenditure domain.Expenditure
+	var categoryID, merchantID, refundOf, accountID, createdBy uuid.NullUUID
+	var latitude, longitude sql.NullFloat64
+	var placeName, city string
+
+	err := row.Scan(&expenditure.ID, &expenditure.Description, &expenditure.Amount, &expenditure.Date,
+		&categoryID, pq.Array(&expenditure.Tags), &expenditure.Quantity, &expenditure.UnitPrice, &expenditure.Unit,
+		&expenditure.TaxRate, &expenditure.TaxAmount, &merchantID,
+		&latitude, &longitude, &placeName, &city, &expenditure.Planned, &refundOf, &expenditure.Status,
+		&accountID, &expenditure.Reconciled, &expenditure.OriginalAmount, &expenditure.OriginalCurrency, &createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	expenditure.CategoryId = categoryID.UUID
+	expenditure.MerchantId = merchantID.UUID
+	expenditure.RefundOf = refundOf.UUID
+	expenditure.AccountId = accountID.UUID
+	expenditure.CreatedBy = createdBy.UUID
+	if latitude.Valid && longitude.Valid {
+		expenditure.Location = &domain.Location{
+			Latitude:  latitude.Float64,
+			Longitude: longitude.Float64,
+			PlaceName: placeName,
+			City:      city,
+		}
+	}
+	if expenditure.Tags == nil {
+		expenditure.Tags = []string{}
+	}
+	return &expenditure, nil
+}
+
+// locationColumns flattens an optional location into nullable columns
+func locationColumns(location *domain.Location) (sql.NullFloat64, sql.NullFloat64, string, string) {
+	if location == nil {
+		return sql.NullFloat64{}, sql.NullFloat64{}, "", ""
+	}
+	return sql.NullFloat64{Float64: location.Latitude, Valid: true},
+		sql.NullFloat64{Float64: location.Longitude, Valid: true},
+		location.PlaceName, location.City
+}
+
+// expenditureValues returns the values of expenditureColumns, in order
+func expenditureValues(expenditure *domain.Expenditure) []interface{} {
+	latitude, longitude, placeName, city := locationColumns(expenditure.Location)
+	return []interface{}{
+		expenditure.ID, expenditure.Description, expenditure.Amount, expenditure.Date,
+		nullUUID(expenditure.CategoryId), pq.Array(expenditure.Tags),
+		expenditure.Quantity, expenditure.UnitPrice, expenditure.Unit,
+		expenditure.TaxRate, expenditure.TaxAmount, nullUUID(expenditure.MerchantId),
+		latitude, longitude, placeName, city, expenditure.Planned, nullUUID(expenditure.RefundOf),
+		expenditureStatus(expenditure), nullUUID(expenditure.AccountId), expenditure.Reconciled,
+		expenditure.OriginalAmount, expenditure.OriginalCurrency, nullUUID(expenditure.CreatedBy),
+	}
+}
+
+// expenditureStatus returns the status to store, cleared for expenditures built without one
+func expenditureStatus(expenditure *domain.Expenditure) domain.ExpenditureStatus {
+	if expenditure.Status == "" {
+		return domain.StatusCleared
+	}
+	return expenditure.Status
+}
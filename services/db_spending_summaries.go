@@ -0,0 +1,157 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"go-expense-tracker/domain"
+	"time"
+)
+
+// setupSpendingSummaries creates the spending_summaries table together with the trigger that
+// keeps it up to date on every write to expenditures, then rebuilds it so that changes made
+// before the trigger existed are included. Cancelled expenditures are not summarized and pending
+// ones are also counted apart
+func setupSpendingSummaries(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS spending_summaries (
+			day DATE NOT NULL,
+			category_id UUID NOT NULL,
+			count INTEGER NOT NULL,
+			total DECIMAL(14, 2) NOT NULL,
+			tax_amount DECIMAL(14, 2) NOT NULL,
+			PRIMARY KEY (day, category_id)
+		);
+
+		ALTER TABLE spending_summaries
+			ADD COLUMN IF NOT EXISTS pending_count INTEGER NOT NULL DEFAULT 0,
+			ADD COLUMN IF NOT EXISTS pending_total DECIMAL(14, 2) NOT NULL DEFAULT 0,
+			ADD COLUMN IF NOT EXISTS pending_tax_amount DECIMAL(14, 2) NOT NULL DEFAULT 0;
+
+		CREATE OR REPLACE FUNCTION maintain_spending_summaries() RETURNS trigger AS $$
+		BEGIN
+			IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.status <> 'cancelled' THEN
+				UPDATE spending_summaries
+				SET count = count - 1, total = total - OLD.amount, tax_amount = tax_amount - OLD.tax_amount,
+					pending_count = pending_count - (OLD.status = 'pending')::int,
+					pending_total = pending_total - CASE WHEN OLD.status = 'pending' THEN OLD.amount ELSE 0 END,
+					pending_tax_amount = pending_tax_amount - CASE WHEN OLD.status = 'pending' THEN OLD.tax_amount ELSE 0 END
+				WHERE day = OLD.date::date AND category_id = COALESCE(OLD.category_id, '00000000-0000-0000-0000-000000000000');
+			END IF;
+			IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.status <> 'cancelled' THEN
+				INSERT INTO spending_summaries (day, category_id, count, total, tax_amount, pending_count, pending_total, pending_tax_amount)
+				VALUES (NEW.date::date, COALESCE(NEW.category_id, '00000000-0000-0000-0000-000000000000'), 1, NEW.amount, NEW.tax_amount,
+					(NEW.status = 'pending')::int,
+					CASE WHEN NEW.status = 'pending' THEN NEW.amount ELSE 0 END,
+					CASE WHEN NEW.status = 'pending' THEN NEW.tax_amount ELSE 0 END)
+				ON CONFLICT (day, category_id) DO UPDATE SET
+					count = spending_summaries.count + 1,
+					total = spending_summaries.total + EXCLUDED.total,
+					tax_amount = spending_summaries.tax_amount + EXCLUDED.tax_amount,
+					pending_count = spending_summaries.pending_count + EXCLUDED.pending_count,
+					pending_total = spending_summaries.pending_total + EXCLUDED.pending_total,
+					pending_tax_amount = spending_summaries.pending_tax_amount + EXCLUDED.pending_tax_amount;
+			END IF;
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql;
+
+		DROP TRIGGER IF EXISTS expenditures_spending_summaries ON expenditures;
+		CREATE TRIGGER expenditures_spending_summaries
+			AFTER INSERT OR UPDATE OR DELETE ON expenditures
+			FOR EACH ROW EXECUTE FUNCTION maintain_spending_summaries();
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create spending summaries: %w", err)
+	}
+
+	return refreshSpendingSummaries(db)
+}
+
+// refreshSpendingSummaries rebuilds the summaries while blocking writes to expenditures, so no
+// change is counted twice or missed
+func refreshSpendingSummaries(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		LOCK TABLE expenditures IN SHARE MODE;
+		DELETE FROM spending_summaries;
+		INSERT INTO spending_summaries (day, category_id, count, total, tax_amount, pending_count, pending_total, pending_tax_amount)
+		SELECT date::date, COALESCE(category_id, '00000000-0000-0000-0000-000000000000'), COUNT(*), SUM(amount), SUM(tax_amount),
+			COUNT(*) FILTER (WHERE status = 'pending'),
+			COALESCE(SUM(amount) FILTER (WHERE status = 'pending'), 0),
+			COALESCE(SUM(tax_amount) FILTER (WHERE status = 'pending'), 0)
+		FROM expenditures
+		WHERE status <> 'cancelled'
+		GROUP BY 1, 2
+	`)
+	if err != nil {
+		return fmt.Errorf("error rebuilding spending summaries: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing spending summaries: %w", err)
+	}
+	return nil
+}
+
+// GetDailySpending reads the spending per day and category kept by the expenditures trigger
+func (s *DBService) GetDailySpending(from, to time.Time) ([]*domain.DailySpending, error) {
+	s.logger.Debug("Getting daily spending", "from", from, "to", to)
+
+	query := "SELECT day, category_id, count, total, tax_amount, pending_count, pending_total, pending_tax_amount FROM spending_summaries WHERE count > 0"
+	var args []interface{}
+	if !from.IsZero() {
+		args = append(args, domain.SpendingDay(from))
+		query += fmt.Sprintf(" AND day >= $%d", len(args))
+	}
+	if !to.IsZero() {
+		args = append(args, domain.SpendingDay(to))
+		query += fmt.Sprintf(" AND day < $%d", len(args))
+	}
+
+	rows, err := s.db.Query(query+" ORDER BY day", args...)
+	if err != nil {
+		s.logger.Error("Error querying daily spending", "error", err)
+		return nil, fmt.Errorf("error querying daily spending: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []*domain.DailySpending
+	for rows.Next() {
+		var summary domain.DailySpending
+		var day time.Time
+		err := rows.Scan(&day, &summary.CategoryId, &summary.Count, &summary.Total, &summary.TaxAmount,
+			&summary.PendingCount, &summary.PendingTotal, &summary.PendingTaxAmount)
+		if err != nil {
+			s.logger.Error("Error scanning daily spending row", "error", err)
+			return nil, fmt.Errorf("error scanning daily spending row: %w", err)
+		}
+		summary.Day = domain.SpendingDay(day)
+		summaries = append(summaries, &summary)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating daily spending rows", "error", err)
+		return nil, fmt.Errorf("error iterating daily spending rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved daily spending", "count", len(summaries))
+	return summaries, nil
+}
+
+// RefreshSpendingSummaries rebuilds the spending summaries from the expenditures
+func (s *DBService) RefreshSpendingSummaries() error {
+	s.logger.Debug("Refreshing spending summaries")
+
+	if err := refreshSpendingSummaries(s.db); err != nil {
+		s.logger.Error("Error refreshing spending summaries", "error", err)
+		return err
+	}
+
+	s.logger.Info("Spending summaries refreshed")
+	return nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+)
+
+const stagedExpenditureColumns = "id, source, reference, description, amount, date, category_id, raw_text, status, expenditure_id, created_at"
+
+// AddStagedExpenditure adds a new entry to the import review queue
+func (s *DBService) AddStagedExpenditure(staged *domain.StagedExpenditure) error {
+	s.logger.Debug("Adding staged expenditure to database",
+		"id", staged.ID,
+		"source", staged.Source,
+		"reference", staged.Reference,
+		"amount", staged.Amount)
+
+	_, err := s.db.Exec(
+		"INSERT INTO staged_expenditures ("+stagedExpenditureColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)",
+		staged.ID, staged.Source, staged.Reference, staged.Description, staged.Amount, staged.Date,
+		nullUUID(staged.CategoryId), staged.RawText, staged.Status, nullUUID(staged.ExpenditureID), staged.CreatedAt,
+	)
+	if err != nil {
+		s.logger.Error("Error inserting staged expenditure", "error", err, "id", staged.ID)
+		return fmt.Errorf("error inserting staged expenditure: %w", err)
+	}
+
+	s.logger.Info("Staged expenditure added successfully", "id", staged.ID)
+	return nil
+}
+
+// GetStagedExpenditureByID retrieves an entry of the import review queue by its ID
+func (s *DBService) GetStagedExpenditureByID(id string) (*domain.StagedExpenditure, error) {
+	s.logger.Debug("Getting staged expenditure by ID", "id", id)
+
+	stagedID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	row := s.db.QueryRow("SELECT "+stagedExpenditureColumns+" FROM staged_expenditures WHERE id = $1", stagedID)
+	staged, err := scanStagedExpenditure(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Staged expenditure not found", "id", id)
+			return nil, domain.ErrStagedExpenditureNotFound
+		}
+		s.logger.Error("Error querying staged expenditure", "error", err, "id", id)
+		return nil, fmt.Errorf("error querying staged expenditure: %w", err)
+	}
+
+	return staged, nil
+}
+
+// GetAllStagedExpenditures retrieves the whole import review queue, oldest first
+func (s *DBService) GetAllStagedExpenditures() ([]*domain.StagedExpenditure, error) {
+	s.logger.Debug("Getting all staged expenditures")
+
+	rows, err := s.db.Query("SELECT " + stagedExpenditureColumns + " FROM staged_expenditures ORDER BY created_at")
+	if err != nil {
+		s.logger.Error("Error querying all staged expenditures", "error", err)
+		return nil, fmt.Errorf("error querying all staged expenditures: %w", err)
+	}
+	defer rows.Close()
+
+	var staged []*domain.StagedExpenditure
+	for rows.Next() {
+		entry, err := scanStagedExpenditure(rows)
+		if err != nil {
+			s.logger.Error("Error scanning staged expenditure row", "error", err)
+			return nil, fmt.Errorf("error scanning staged expenditure row: %w", err)
+		}
+		staged = append(staged, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating staged expenditure rows", "error", err)
+		return nil, fmt.Errorf("error iterating staged expenditure rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved all staged expenditures", "count", len(staged))
+	return staged, nil
+}
+
+// UpdateStagedExpenditure updates an existing entry of the import review queue
+func (s *DBService) UpdateStagedExpenditure(staged *domain.StagedExpenditure) error {
+	s.logger.Debug("Updating staged expenditure", "id", staged.ID, "status", staged.Status)
+
+	result, err := s.db.Exec(
+		`UPDATE staged_expenditures SET description = $1, amount = $2, date = $3, category_id = $4,
+			status = $5, expenditure_id = $6 WHERE id = $7`,
+		staged.Description, staged.Amount, staged.Date, nullUUID(staged.CategoryId),
+		staged.Status, nullUUID(staged.ExpenditureID), staged.ID,
+	)
+	if err != nil {
+		s.logger.Error("Error updating staged expenditure", "error", err, "id", staged.ID)
+		return fmt.Errorf("error updating staged expenditure: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("Staged expenditure not found for update", "id", staged.ID)
+		return domain.ErrStagedExpenditureNotFound
+	}
+
+	s.logger.Info("Staged expenditure updated successfully", "id", staged.ID, "status", staged.Status)
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanStagedExpenditure(row rowScanner) (*domain.StagedExpenditure, error) {
+	var staged domain.StagedExpenditure
+	var categoryID, expenditureID uuid.NullUUID
+
+	err := row.Scan(&staged.ID, &staged.Source, &staged.Reference, &staged.Description, &staged.Amount, &staged.Date,
+		&categoryID, &staged.RawText, &staged.Status, &expenditureID, &staged.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	staged.CategoryId = categoryID.UUID
+	staged.ExpenditureID = expenditureID.UUID
+	return &staged, nil
+}
+
+// nullUUID stores uuid.Nil as SQL NULL
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
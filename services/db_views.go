@@ -0,0 +1,236 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+)
+
+const viewColumns = "id, name, filter, created_at, updated_at"
+
+// viewCategoryMerge replaces the category $2 by $1 in the filters of saved views
+const viewCategoryMerge = `
+	UPDATE views SET filter = jsonb_set(filter, '{category_ids}', (
+		SELECT jsonb_agg(DISTINCT CASE WHEN id = $2 THEN $1 ELSE id END)
+		FROM jsonb_array_elements_text(filter->'category_ids') AS ids (id)
+	))
+	WHERE filter->'category_ids' ? $2`
+
+// setupViews creates the tables of saved views and pinned expenditures. Pins do not reference
+// expenditures, so an expenditure restored by an undo keeps its pin
+func setupViews(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS views (
+			id UUID PRIMARY KEY,
+			name TEXT NOT NULL,
+			filter JSONB NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS expenditure_pins (
+			expenditure_id UUID PRIMARY KEY,
+			pinned_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create views tables: %w", err)
+	}
+	return nil
+}
+
+// AddView saves a new view
+func (s *DBService) AddView(view *domain.View) error {
+	s.logger.Debug("Adding view to database", "id", view.ID, "name", view.Name)
+
+	filter, err := json.Marshal(view.Filter)
+	if err != nil {
+		return fmt.Errorf("error encoding view filter: %w", err)
+	}
+
+	_, err = s.db.Exec(
+		"INSERT INTO views ("+viewColumns+") VALUES ($1, $2, $3, $4, $5)",
+		view.ID, view.Name, filter, view.CreatedAt, view.UpdatedAt,
+	)
+	if err != nil {
+		s.logger.Error("Error inserting view", "error", err, "id", view.ID)
+		return fmt.Errorf("error inserting view: %w", err)
+	}
+
+	s.logger.Info("View added successfully", "id", view.ID)
+	return nil
+}
+
+// GetViewByID retrieves a view by its ID
+func (s *DBService) GetViewByID(id string) (*domain.View, error) {
+	s.logger.Debug("Getting view by ID", "id", id)
+
+	viewID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	view, err := scanView(s.db.QueryRow("SELECT "+viewColumns+" FROM views WHERE id = $1", viewID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("View not found", "id", id)
+			return nil, domain.ErrViewNotFound
+		}
+		s.logger.Error("Error querying view", "error", err, "id", id)
+		return nil, fmt.Errorf("error querying view: %w", err)
+	}
+
+	return view, nil
+}
+
+// GetAllViews retrieves all views, by name
+func (s *DBService) GetAllViews() ([]*domain.View, error) {
+	s.logger.Debug("Getting all views")
+
+	rows, err := s.db.Query("SELECT " + viewColumns + " FROM views ORDER BY name")
+	if err != nil {
+		s.logger.Error("Error querying all views", "error", err)
+		return nil, fmt.Errorf("error querying all views: %w", err)
+	}
+	defer rows.Close()
+
+	var views []*domain.View
+	for rows.Next() {
+		view, err := scanView(rows)
+		if err != nil {
+			s.logger.Error("Error scanning view row", "error", err)
+			return nil, fmt.Errorf("error scanning view row: %w", err)
+		}
+		views = append(views, view)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating view rows", "error", err)
+		return nil, fmt.Errorf("error iterating view rows: %w", err)
+	}
+
+	s.logger.Info("Retrieved all views", "count", len(views))
+	return views, nil
+}
+
+// UpdateView updates the name and filter of an existing view
+func (s *DBService) UpdateView(view *domain.View) error {
+	s.logger.Debug("Updating view", "id", view.ID, "name", view.Name)
+
+	filter, err := json.Marshal(view.Filter)
+	if err != nil {
+		return fmt.Errorf("error encoding view filter: %w", err)
+	}
+
+	result, err := s.db.Exec(
+		"UPDATE views SET name = $1, filter = $2, updated_at = $3 WHERE id = $4",
+		view.Name, filter, view.UpdatedAt, view.ID,
+	)
+	if err != nil {
+		s.logger.Error("Error updating view", "error", err, "id", view.ID)
+		return fmt.Errorf("error updating view: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("View not found for update", "id", view.ID)
+		return domain.ErrViewNotFound
+	}
+
+	s.logger.Info("View updated successfully", "id", view.ID)
+	return nil
+}
+
+// DeleteView deletes a view by its ID
+func (s *DBService) DeleteView(id string) error {
+	s.logger.Debug("Deleting view", "id", id)
+
+	viewID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	result, err := s.db.Exec("DELETE FROM views WHERE id = $1", viewID)
+	if err != nil {
+		s.logger.Error("Error deleting view", "error", err, "id", id)
+		return fmt.Errorf("error deleting view: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		s.logger.Warn("View not found for deletion", "id", id)
+		return domain.ErrViewNotFound
+	}
+
+	s.logger.Info("View deleted successfully", "id", id)
+	return nil
+}
+
+// PinExpenditure stars an expenditure, keeping the time it was first pinned
+func (s *DBService) PinExpenditure(id uuid.UUID) error {
+	s.logger.Debug("Pinning expenditure", "id", id)
+
+	_, err := s.db.Exec("INSERT INTO expenditure_pins (expenditure_id, pinned_at) VALUES ($1, NOW()) ON CONFLICT DO NOTHING", id)
+	if err != nil {
+		s.logger.Error("Error pinning expenditure", "error", err, "id", id)
+		return fmt.Errorf("error pinning expenditure: %w", err)
+	}
+	return nil
+}
+
+// UnpinExpenditure removes the star of an expenditure
+func (s *DBService) UnpinExpenditure(id uuid.UUID) error {
+	s.logger.Debug("Unpinning expenditure", "id", id)
+
+	_, err := s.db.Exec("DELETE FROM expenditure_pins WHERE expenditure_id = $1", id)
+	if err != nil {
+		s.logger.Error("Error unpinning expenditure", "error", err, "id", id)
+		return fmt.Errorf("error unpinning expenditure: %w", err)
+	}
+	return nil
+}
+
+// GetPinnedExpenditureIDs returns the pinned expenditures, most recently pinned first
+func (s *DBService) GetPinnedExpenditureIDs() ([]uuid.UUID, error) {
+	s.logger.Debug("Getting pinned expenditures")
+
+	rows, err := s.db.Query("SELECT expenditure_id FROM expenditure_pins ORDER BY pinned_at DESC")
+	if err != nil {
+		s.logger.Error("Error querying pinned expenditures", "error", err)
+		return nil, fmt.Errorf("error querying pinned expenditures: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			s.logger.Error("Error scanning pinned expenditure row", "error", err)
+			return nil, fmt.Errorf("error scanning pinned expenditure row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating pinned expenditure rows", "error", err)
+		return nil, fmt.Errorf("error iterating pinned expenditure rows: %w", err)
+	}
+	return ids, nil
+}
+
+func scanView(row rowScanner) (*domain.View, error) {
+	var view domain.View
+	var filter []byte
+
+	if err := row.Scan(&view.ID, &view.Name, &filter, &view.CreatedAt, &view.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(filter, &view.Filter); err != nil {
+		return nil, fmt.Errorf("error decoding view filter: %w", err)
+	}
+	return &view, nil
+}
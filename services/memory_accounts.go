@@ -0,0 +1,68 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"sort"
+)
+
+func (m *MemoryService) AddAccount(account *domain.Account) error {
+	m.logger.Debug("Adding account", "id", account.ID, "name", account.Name)
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.Accounts[account.ID.String()] = account.Clone()
+	m.logger.Info("Account added successfully", "id", account.ID, "total_count", len(m.Accounts))
+	return nil
+}
+
+func (m *MemoryService) GetAccountByID(id string) (*domain.Account, error) {
+	m.logger.Debug("Getting account by ID", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	account, exists := m.Accounts[id]
+	if !exists {
+		m.logger.Warn("Account not found", "id", id)
+		return nil, domain.ErrAccountNotFound
+	}
+
+	return account.Clone(), nil
+}
+
+func (m *MemoryService) GetAllAccounts() ([]*domain.Account, error) {
+	m.logger.Debug("Getting all accounts")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	accounts := make([]*domain.Account, 0, len(m.Accounts))
+	for _, account := range m.Accounts {
+		accounts = append(accounts, account.Clone())
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].CreatedAt.Before(accounts[j].CreatedAt)
+	})
+
+	m.logger.Info("Retrieved all accounts", "count", len(accounts))
+	return accounts, nil
+}
+
+func (m *MemoryService) UpdateAccount(account *domain.Account) error {
+	m.logger.Debug("Updating account", "id", account.ID, "name", account.Name)
+
+	m.Lock()
+	defer m.Unlock()
+
+	id := account.ID.String()
+	if _, exists := m.Accounts[id]; !exists {
+		m.logger.Warn("Account not found for update", "id", id)
+		return domain.ErrAccountNotFound
+	}
+
+	m.Accounts[id] = account.Clone()
+	m.logger.Info("Account updated successfully", "id", id)
+	return nil
+}
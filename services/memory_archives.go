@@ -0,0 +1,138 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (m *MemoryService) ArchiveExpenditures(before time.Time) (*domain.Archive, error) {
+	m.logger.Debug("Archiving expenditures", "before", before)
+
+	m.Lock()
+	defer m.Unlock()
+
+	// The date index holds them oldest first; refunds dated later go with their originals
+	archived := slices.Clone(m.expenditures.between(time.Time{}, before))
+	originals := make(map[uuid.UUID]bool, len(archived))
+	for _, expenditure := range archived {
+		originals[expenditure.ID] = true
+	}
+	for _, expenditure := range m.expenditures.between(before, time.Time{}) {
+		if originals[expenditure.RefundOf] {
+			archived = append(archived, expenditure)
+		}
+	}
+	if len(archived) == 0 {
+		m.logger.Info("No expenditures to archive", "before", before)
+		return nil, domain.ErrNothingToArchive
+	}
+
+	archive := domain.NewArchive(before, archived)
+	for _, expenditure := range archived {
+		m.expenditures.remove(expenditure.ID.String())
+		m.writeOutbox(domain.OutboxExpenditureDeleted, expenditure)
+	}
+	m.Archives[archive.ID.String()] = archive.Clone()
+	m.ArchivedExpenditures[archive.ID.String()] = archived
+
+	m.logger.Info("Expenditures archived successfully", "id", archive.ID, "count", archive.Count, "remaining_count", m.expenditures.len())
+	return archive.Clone(), nil
+}
+
+func (m *MemoryService) GetArchiveByID(id string) (*domain.Archive, error) {
+	m.logger.Debug("Getting archive by ID", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	archive, exists := m.Archives[id]
+	if !exists {
+		m.logger.Warn("Archive not found", "id", id)
+		return nil, domain.ErrArchiveNotFound
+	}
+
+	return archive.Clone(), nil
+}
+
+func (m *MemoryService) GetAllArchives() ([]*domain.Archive, error) {
+	m.logger.Debug("Getting all archives")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	archives := make([]*domain.Archive, 0, len(m.Archives))
+	for _, archive := range m.Archives {
+		archives = append(archives, archive.Clone())
+	}
+
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].CreatedAt.Before(archives[j].CreatedAt)
+	})
+
+	m.logger.Info("Retrieved all archives", "count", len(archives))
+	return archives, nil
+}
+
+func (m *MemoryService) GetArchivedExpenditures(archiveID string) ([]*domain.Expenditure, error) {
+	m.logger.Debug("Getting archived expenditures", "archive_id", archiveID)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	if _, exists := m.Archives[archiveID]; !exists {
+		m.logger.Warn("Archive not found", "id", archiveID)
+		return nil, domain.ErrArchiveNotFound
+	}
+
+	expenditures := m.ArchivedExpenditures[archiveID]
+	m.logger.Info("Retrieved archived expenditures", "archive_id", archiveID, "count", len(expenditures))
+	return cloneExpenditures(expenditures), nil
+}
+
+func (m *MemoryService) DeleteArchive(id string) error {
+	m.logger.Debug("Deleting archive", "id", id)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.Archives[id]; !exists {
+		m.logger.Warn("Archive not found for deletion", "id", id)
+		return domain.ErrArchiveNotFound
+	}
+
+	delete(m.Archives, id)
+	delete(m.ArchivedExpenditures, id)
+	m.logger.Info("Archive deleted successfully", "id", id, "remaining_count", len(m.Archives))
+	return nil
+}
+
+func (m *MemoryService) ScrubArchivedExpenditures(member uuid.UUID, erase bool) (int, error) {
+	m.logger.Debug("Scrubbing archived expenditures", "member_id", member, "erase", erase)
+
+	m.Lock()
+	defer m.Unlock()
+
+	scrubbed := 0
+	for archiveID, expenditures := range m.ArchivedExpenditures {
+		kept := expenditures[:0]
+		for _, expenditure := range expenditures {
+			if member == uuid.Nil || expenditure.CreatedBy != member {
+				kept = append(kept, expenditure)
+				continue
+			}
+			scrubbed++
+			if !erase {
+				expenditure.CreatedBy = uuid.Nil
+				kept = append(kept, expenditure)
+			}
+		}
+		m.ArchivedExpenditures[archiveID] = kept
+	}
+
+	m.logger.Info("Archived expenditures scrubbed successfully", "member_id", member, "count", scrubbed)
+	return scrubbed, nil
+}
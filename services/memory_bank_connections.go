@@ -0,0 +1,89 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"sort"
+)
+
+func (m *MemoryService) AddBankConnection(connection *domain.BankConnection) error {
+	m.logger.Debug("Adding bank connection", "id", connection.ID, "connector", connection.Connector, "name", connection.Name)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.BankConnections[connection.ID.String()]; exists {
+		m.logger.Warn("Bank connection already exists", "id", connection.ID)
+		return domain.ErrBankConnectionAlreadyExists
+	}
+
+	m.BankConnections[connection.ID.String()] = connection.Clone()
+	m.logger.Info("Bank connection added successfully", "id", connection.ID, "total_count", len(m.BankConnections))
+	return nil
+}
+
+func (m *MemoryService) GetBankConnectionByID(id string) (*domain.BankConnection, error) {
+	m.logger.Debug("Getting bank connection by ID", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	connection, exists := m.BankConnections[id]
+	if !exists {
+		m.logger.Warn("Bank connection not found", "id", id)
+		return nil, domain.ErrBankConnectionNotFound
+	}
+
+	return connection.Clone(), nil
+}
+
+func (m *MemoryService) GetAllBankConnections() ([]*domain.BankConnection, error) {
+	m.logger.Debug("Getting all bank connections")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	connections := make([]*domain.BankConnection, 0, len(m.BankConnections))
+	for _, connection := range m.BankConnections {
+		connections = append(connections, connection.Clone())
+	}
+
+	sort.Slice(connections, func(i, j int) bool {
+		return connections[i].CreatedAt.Before(connections[j].CreatedAt)
+	})
+
+	m.logger.Info("Retrieved all bank connections", "count", len(connections))
+	return connections, nil
+}
+
+func (m *MemoryService) UpdateBankConnection(connection *domain.BankConnection) error {
+	m.logger.Debug("Updating bank connection", "id", connection.ID)
+
+	m.Lock()
+	defer m.Unlock()
+
+	id := connection.ID.String()
+	if _, exists := m.BankConnections[id]; !exists {
+		m.logger.Warn("Bank connection not found for update", "id", id)
+		return domain.ErrBankConnectionNotFound
+	}
+
+	m.BankConnections[id] = connection.Clone()
+	m.logger.Info("Bank connection updated successfully", "id", id)
+	return nil
+}
+
+func (m *MemoryService) DeleteBankConnection(id string) error {
+	m.logger.Debug("Deleting bank connection", "id", id)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.BankConnections[id]; !exists {
+		m.logger.Warn("Bank connection not found for deletion", "id", id)
+		return domain.ErrBankConnectionNotFound
+	}
+
+	delete(m.BankConnections, id)
+	m.logger.Info("Bank connection deleted successfully", "id", id, "remaining_count", len(m.BankConnections))
+	return nil
+}
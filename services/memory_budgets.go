@@ -0,0 +1,84 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"sort"
+)
+
+func (m *MemoryService) AddBudget(budget *domain.Budget) error {
+	m.logger.Debug("Adding budget", "id", budget.ID, "name", budget.Name, "amount", budget.Amount, "period", budget.Period)
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.Budgets[budget.ID.String()] = budget.Clone()
+	m.logger.Info("Budget added successfully", "id", budget.ID, "total_count", len(m.Budgets))
+	return nil
+}
+
+func (m *MemoryService) GetBudgetByID(id string) (*domain.Budget, error) {
+	m.logger.Debug("Getting budget by ID", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	budget, exists := m.Budgets[id]
+	if !exists {
+		m.logger.Warn("Budget not found", "id", id)
+		return nil, domain.ErrBudgetNotFound
+	}
+
+	return budget.Clone(), nil
+}
+
+func (m *MemoryService) GetAllBudgets() ([]*domain.Budget, error) {
+	m.logger.Debug("Getting all budgets")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	budgets := make([]*domain.Budget, 0, len(m.Budgets))
+	for _, budget := range m.Budgets {
+		budgets = append(budgets, budget.Clone())
+	}
+
+	sort.Slice(budgets, func(i, j int) bool {
+		return budgets[i].Name < budgets[j].Name
+	})
+
+	m.logger.Info("Retrieved all budgets", "count", len(budgets))
+	return budgets, nil
+}
+
+func (m *MemoryService) UpdateBudget(budget *domain.Budget) error {
+	m.logger.Debug("Updating budget", "id", budget.ID, "name", budget.Name, "amount", budget.Amount)
+
+	m.Lock()
+	defer m.Unlock()
+
+	id := budget.ID.String()
+	if _, exists := m.Budgets[id]; !exists {
+		m.logger.Warn("Budget not found for update", "id", id)
+		return domain.ErrBudgetNotFound
+	}
+
+	m.Budgets[id] = budget.Clone()
+	m.logger.Info("Budget updated successfully", "id", id)
+	return nil
+}
+
+func (m *MemoryService) DeleteBudget(id string) error {
+	m.logger.Debug("Deleting budget", "id", id)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.Budgets[id]; !exists {
+		m.logger.Warn("Budget not found for deletion", "id", id)
+		return domain.ErrBudgetNotFound
+	}
+
+	delete(m.Budgets, id)
+	m.logger.Info("Budget deleted successfully", "id", id, "remaining_count", len(m.Budgets))
+	return nil
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+func (m *MemoryService) MergeCategory(source, target uuid.UUID, dryRun bool) (*domain.CategoryMerge, error) {
+	m.logger.Debug("Merging category", "source_id", source, "target_id", target, "dry_run", dryRun)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.Categories[source.String()]; !exists {
+		m.logger.Warn("Category not found for merge", "id", source)
+		return nil, domain.ErrCategoryNotFound
+	}
+
+	categories := make([]*domain.Category, 0, len(m.Categories))
+	for _, category := range m.Categories {
+		categories = append(categories, category)
+	}
+	if err := domain.CheckCategoryMerge(categories, source, target); err != nil {
+		m.logger.Warn("Invalid category merge", "source_id", source, "target_id", target, "error", err)
+		return nil, err
+	}
+
+	merge := &domain.CategoryMerge{SourceID: source, TargetID: target, DryRun: dryRun}
+	for _, expenditure := range slices.Clone(m.expenditures.inCategory(source)) {
+		merge.Expenditures++
+		if !dryRun {
+			moved := expenditure.Clone()
+			moved.CategoryId = target
+			m.expenditures.put(moved)
+			m.writeOutbox(domain.OutboxExpenditureUpdated, moved)
+		}
+	}
+	for _, goal := range m.Goals {
+		if goal.CategoryId == source {
+			merge.Goals++
+			if !dryRun {
+				goal.CategoryId = target
+			}
+		}
+	}
+	for _, budget := range m.Budgets {
+		if budget.CategoryId == source {
+			merge.Budgets++
+			if !dryRun {
+				budget.CategoryId = target
+			}
+		}
+	}
+	for _, recurring := range m.Recurring {
+		if recurring.CategoryId == source {
+			merge.Recurring++
+			if !dryRun {
+				recurring.CategoryId = target
+			}
+		}
+	}
+	for _, purchase := range m.InstallmentPurchases {
+		if purchase.CategoryId == source {
+			merge.Installments++
+			if !dryRun {
+				purchase.CategoryId = target
+			}
+		}
+	}
+	for _, allocation := range m.EnvelopeAllocations {
+		if allocation.CategoryId == source {
+			merge.Envelopes++
+			if !dryRun {
+				allocation.CategoryId = target
+			}
+		}
+	}
+	for _, merchant := range m.Merchants {
+		if merchant.DefaultCategoryId == source {
+			merge.Merchants++
+			if !dryRun {
+				merchant.DefaultCategoryId = target
+			}
+		}
+	}
+	for _, staged := range m.StagedExpenditures {
+		if staged.CategoryId == source {
+			merge.StagedExpenditures++
+			if !dryRun {
+				staged.CategoryId = target
+			}
+		}
+	}
+	for _, draft := range m.Drafts {
+		if draft.CategoryId == source {
+			merge.Drafts++
+			if !dryRun {
+				draft.CategoryId = target
+			}
+		}
+	}
+	for _, view := range m.Views {
+		if dryRun {
+			view = view.Clone()
+		}
+		if view.ReplaceCategory(source, target) {
+			merge.Views++
+		}
+	}
+	for _, category := range m.Categories {
+		if category.ParentID == source {
+			merge.Subcategories++
+			if !dryRun {
+				category.ParentID = target
+			}
+		}
+	}
+
+	if !dryRun {
+		delete(m.Categories, source.String())
+	}
+
+	m.logger.Info("Category merged successfully", "source_id", source, "target_id", target, "dry_run", dryRun, "expenditures", merge.Expenditures)
+	return merge, nil
+}
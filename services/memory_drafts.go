@@ -0,0 +1,72 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"sort"
+)
+
+func (m *MemoryService) AddDraft(draft *domain.Draft) error {
+	m.logger.Debug("Adding draft", "id", draft.ID, "description", draft.Description, "amount", draft.Amount)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.Drafts[draft.ID.String()]; exists {
+		m.logger.Warn("Draft already exists", "id", draft.ID)
+		return domain.ErrDraftAlreadyExists
+	}
+
+	m.Drafts[draft.ID.String()] = draft.Clone()
+	m.logger.Info("Draft added successfully", "id", draft.ID, "total_count", len(m.Drafts))
+	return nil
+}
+
+func (m *MemoryService) GetDraftByID(id string) (*domain.Draft, error) {
+	m.logger.Debug("Getting draft by ID", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	draft, exists := m.Drafts[id]
+	if !exists {
+		m.logger.Warn("Draft not found", "id", id)
+		return nil, domain.ErrDraftNotFound
+	}
+
+	return draft.Clone(), nil
+}
+
+func (m *MemoryService) GetAllDrafts() ([]*domain.Draft, error) {
+	m.logger.Debug("Getting all drafts")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	drafts := make([]*domain.Draft, 0, len(m.Drafts))
+	for _, draft := range m.Drafts {
+		drafts = append(drafts, draft.Clone())
+	}
+
+	sort.Slice(drafts, func(i, j int) bool {
+		return drafts[i].CreatedAt.Before(drafts[j].CreatedAt)
+	})
+
+	m.logger.Info("Retrieved all drafts", "count", len(drafts))
+	return drafts, nil
+}
+
+func (m *MemoryService) DeleteDraft(id string) error {
+	m.logger.Debug("Deleting draft", "id", id)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.Drafts[id]; !exists {
+		m.logger.Warn("Draft not found for deletion", "id", id)
+		return domain.ErrDraftNotFound
+	}
+
+	delete(m.Drafts, id)
+	m.logger.Info("Draft deleted successfully", "id", id)
+	return nil
+}
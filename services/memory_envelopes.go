@@ -0,0 +1,33 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+)
+
+func (m *MemoryService) AddEnvelopeAllocations(allocations []*domain.EnvelopeAllocation) error {
+	m.logger.Debug("Adding envelope allocations", "count", len(allocations))
+
+	m.Lock()
+	defer m.Unlock()
+
+	for _, allocation := range allocations {
+		m.EnvelopeAllocations = append(m.EnvelopeAllocations, copyOf(allocation))
+	}
+	m.logger.Info("Envelope allocations added successfully", "count", len(allocations), "total_count", len(m.EnvelopeAllocations))
+	return nil
+}
+
+func (m *MemoryService) GetAllEnvelopeAllocations() ([]*domain.EnvelopeAllocation, error) {
+	m.logger.Debug("Getting all envelope allocations")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	allocations := make([]*domain.EnvelopeAllocation, len(m.EnvelopeAllocations))
+	for i, allocation := range m.EnvelopeAllocations {
+		allocations[i] = copyOf(allocation)
+	}
+
+	m.logger.Info("Retrieved all envelope allocations", "count", len(allocations))
+	return allocations, nil
+}
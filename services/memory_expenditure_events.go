@@ -0,0 +1,88 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+func (m *MemoryService) AppendExpenditureEvents(events []*domain.ExpenditureEvent) error {
+	m.logger.Debug("Appending expenditure events", "count", len(events))
+
+	m.Lock()
+	defer m.Unlock()
+
+	for _, event := range events {
+		event.Seq = int64(len(m.ExpenditureEvents)) + 1
+		m.ExpenditureEvents = append(m.ExpenditureEvents, event.Clone())
+	}
+
+	m.logger.Info("Expenditure events appended successfully", "count", len(events), "total_count", len(m.ExpenditureEvents))
+	return nil
+}
+
+func (m *MemoryService) GetExpenditureEvents(after int64, limit int) ([]*domain.ExpenditureEvent, error) {
+	m.logger.Debug("Getting expenditure events", "after", after, "limit", limit)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	// Sequence numbers start at 1 and have no gaps
+	start := min(max(after, 0), int64(len(m.ExpenditureEvents)))
+	events := m.ExpenditureEvents[start:]
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+	}
+
+	m.logger.Info("Retrieved expenditure events", "count", len(events))
+	return cloneEvents(events), nil
+}
+
+func (m *MemoryService) GetExpenditureHistory(id uuid.UUID) ([]*domain.ExpenditureEvent, error) {
+	m.logger.Debug("Getting expenditure history", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	var events []*domain.ExpenditureEvent
+	for _, event := range m.ExpenditureEvents {
+		if event.ExpenditureID == id {
+			events = append(events, event.Clone())
+		}
+	}
+
+	m.logger.Info("Retrieved expenditure history", "id", id, "count", len(events))
+	return events, nil
+}
+
+func cloneEvents(events []*domain.ExpenditureEvent) []*domain.ExpenditureEvent {
+	clones := make([]*domain.ExpenditureEvent, len(events))
+	for i, event := range events {
+		clones[i] = event.Clone()
+	}
+	return clones
+}
+
+func (m *MemoryService) ScrubExpenditureEvents(member uuid.UUID, erased []uuid.UUID) (int, error) {
+	m.logger.Debug("Scrubbing expenditure events", "member_id", member, "erased_count", len(erased))
+
+	m.Lock()
+	defer m.Unlock()
+
+	scrubbed := 0
+	for _, event := range m.ExpenditureEvents {
+		switch {
+		case event.Expenditure == nil:
+		case slices.Contains(erased, event.ExpenditureID):
+			event.Expenditure = nil
+			scrubbed++
+		case member != uuid.Nil && event.Expenditure.CreatedBy == member:
+			event.Expenditure.CreatedBy = uuid.Nil
+			scrubbed++
+		}
+	}
+
+	m.logger.Info("Expenditure events scrubbed successfully", "member_id", member, "count", scrubbed)
+	return scrubbed, nil
+}
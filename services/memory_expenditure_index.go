@@ -0,0 +1,182 @@
+package services
+
+import (
+	"bytes"
+	"go-expense-tracker/domain"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// expenditureIndex holds the expenditures of the memory storage by ID, along with secondary
+// indexes sorted by date and then by ID, over all expenditures and per category, so pages and
+// counts only visit the expenditures they return. It is guarded by the lock of MemoryService and
+// owns its expenditures: callers store and hand out copies
+type expenditureIndex struct {
+	byID       map[string]*domain.Expenditure
+	byDate     []*domain.Expenditure
+	byCategory map[uuid.UUID][]*domain.Expenditure
+}
+
+func newExpenditureIndex() *expenditureIndex {
+	return &expenditureIndex{
+		byID:       make(map[string]*domain.Expenditure),
+		byCategory: make(map[uuid.UUID][]*domain.Expenditure),
+	}
+}
+
+func (x *expenditureIndex) len() int {
+	return len(x.byID)
+}
+
+func (x *expenditureIndex) get(id string) (*domain.Expenditure, bool) {
+	expenditure, exists := x.byID[id]
+	return expenditure, exists
+}
+
+// put stores the expenditure, replacing the one with the same ID. A replacement with the same
+// date and category keeps its index positions
+func (x *expenditureIndex) put(expenditure *domain.Expenditure) {
+	id := expenditure.ID.String()
+	if old, exists := x.byID[id]; exists && old.Date.Equal(expenditure.Date) && old.CategoryId == expenditure.CategoryId {
+		x.byID[id] = expenditure
+		replaceSorted(x.byDate, expenditure)
+		replaceSorted(x.byCategory[expenditure.CategoryId], expenditure)
+		return
+	}
+	x.remove(id)
+
+	x.byID[id] = expenditure
+	x.byDate = insertSorted(x.byDate, expenditure)
+	x.byCategory[expenditure.CategoryId] = insertSorted(x.byCategory[expenditure.CategoryId], expenditure)
+}
+
+// putAll stores new expenditures, none of them stored yet. They are sorted once and merged into
+// the indexes, which is faster than inserting them one by one
+func (x *expenditureIndex) putAll(expenditures []*domain.Expenditure) {
+	added := slices.Clone(expenditures)
+	slices.SortFunc(added, compareSorted)
+
+	byCategory := make(map[uuid.UUID][]*domain.Expenditure)
+	for _, expenditure := range added {
+		x.byID[expenditure.ID.String()] = expenditure
+		byCategory[expenditure.CategoryId] = append(byCategory[expenditure.CategoryId], expenditure)
+	}
+
+	x.byDate = mergeSorted(x.byDate, added)
+	for categoryID, entries := range byCategory {
+		x.byCategory[categoryID] = mergeSorted(x.byCategory[categoryID], entries)
+	}
+}
+
+// remove deletes the expenditure with the ID and returns it
+func (x *expenditureIndex) remove(id string) (*domain.Expenditure, bool) {
+	expenditure, exists := x.byID[id]
+	if !exists {
+		return nil, false
+	}
+
+	delete(x.byID, id)
+	x.byDate = deleteSorted(x.byDate, expenditure)
+	if entries := deleteSorted(x.byCategory[expenditure.CategoryId], expenditure); len(entries) > 0 {
+		x.byCategory[expenditure.CategoryId] = entries
+	} else {
+		delete(x.byCategory, expenditure.CategoryId)
+	}
+	return expenditure, true
+}
+
+// between returns the expenditures dated within [from, to), oldest first; zero bounds are open
+func (x *expenditureIndex) between(from, to time.Time) []*domain.Expenditure {
+	start, end := bounds(x.byDate, from, to)
+	return x.byDate[start:end]
+}
+
+// inCategory returns the expenditures of a category, oldest first
+func (x *expenditureIndex) inCategory(categoryID uuid.UUID) []*domain.Expenditure {
+	return x.byCategory[categoryID]
+}
+
+// page returns the expenditures of a page of the listing, newest first
+func (x *expenditureIndex) page(query domain.ExpenditurePageQuery) []*domain.Expenditure {
+	entries := x.byDate
+	if query.CategoryId != uuid.Nil {
+		entries = x.byCategory[query.CategoryId]
+	}
+
+	// The page ends before the upper date bound and before the cursor, whichever comes first
+	start, end := bounds(entries, query.From, query.To)
+	if query.After != nil {
+		end = max(min(end, searchSorted(entries, query.After.Date, query.After.ID)), start)
+	}
+
+	expenditures := make([]*domain.Expenditure, 0, min(end-start, query.Limit))
+	for i := end - 1; i >= start && len(expenditures) < query.Limit; i-- {
+		expenditures = append(expenditures, entries[i])
+	}
+	return expenditures
+}
+
+// bounds returns the range of entries dated within [from, to); zero bounds are open
+func bounds(entries []*domain.Expenditure, from, to time.Time) (int, int) {
+	start, end := 0, len(entries)
+	if !from.IsZero() {
+		start = searchSorted(entries, from, uuid.Nil)
+	}
+	if !to.IsZero() {
+		end = max(searchSorted(entries, to, uuid.Nil), start)
+	}
+	return start, end
+}
+
+// compareSorted orders expenditures by date and then by ID
+func compareSorted(a, b *domain.Expenditure) int {
+	if c := a.Date.Compare(b.Date); c != 0 {
+		return c
+	}
+	return bytes.Compare(a.ID[:], b.ID[:])
+}
+
+// searchSorted returns the position of the first of entries at or after the date and ID, the
+// entries being sorted by date and then by ID
+func searchSorted(entries []*domain.Expenditure, date time.Time, id uuid.UUID) int {
+	return sort.Search(len(entries), func(i int) bool {
+		if !entries[i].Date.Equal(date) {
+			return entries[i].Date.After(date)
+		}
+		return bytes.Compare(entries[i].ID[:], id[:]) >= 0
+	})
+}
+
+func insertSorted(entries []*domain.Expenditure, expenditure *domain.Expenditure) []*domain.Expenditure {
+	return slices.Insert(entries, searchSorted(entries, expenditure.Date, expenditure.ID), expenditure)
+}
+
+func deleteSorted(entries []*domain.Expenditure, expenditure *domain.Expenditure) []*domain.Expenditure {
+	i := searchSorted(entries, expenditure.Date, expenditure.ID)
+	if i < len(entries) && entries[i] == expenditure {
+		entries = slices.Delete(entries, i, i+1)
+	}
+	return entries
+}
+
+func replaceSorted(entries []*domain.Expenditure, expenditure *domain.Expenditure) {
+	entries[searchSorted(entries, expenditure.Date, expenditure.ID)] = expenditure
+}
+
+// mergeSorted merges sorted added expenditures into sorted entries. It works in place from the
+// back, moving the entries after each added expenditure in one copy, so adding expenditures
+// newer than all entries only appends them
+func mergeSorted(entries, added []*domain.Expenditure) []*domain.Expenditure {
+	end := len(entries) // Entries before end have not moved yet
+	entries = append(entries, added...)
+	for j := len(added) - 1; j >= 0; j-- {
+		i := searchSorted(entries[:end], added[j].Date, added[j].ID)
+		copy(entries[i+j+1:end+j+1], entries[i:end])
+		entries[i+j] = added[j]
+		end = i
+	}
+	return entries
+}
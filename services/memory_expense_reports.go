@@ -0,0 +1,89 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"sort"
+)
+
+func (m *MemoryService) AddExpenseReport(report *domain.ExpenseReport) error {
+	m.logger.Debug("Adding expense report", "id", report.ID, "title", report.Title, "status", report.Status)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.ExpenseReports[report.ID.String()]; exists {
+		m.logger.Warn("Expense report already exists", "id", report.ID)
+		return domain.ErrExpenseReportAlreadyExists
+	}
+
+	m.ExpenseReports[report.ID.String()] = report.Clone()
+	m.logger.Info("Expense report added successfully", "id", report.ID, "total_count", len(m.ExpenseReports))
+	return nil
+}
+
+func (m *MemoryService) GetExpenseReportByID(id string) (*domain.ExpenseReport, error) {
+	m.logger.Debug("Getting expense report by ID", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	report, exists := m.ExpenseReports[id]
+	if !exists {
+		m.logger.Warn("Expense report not found", "id", id)
+		return nil, domain.ErrExpenseReportNotFound
+	}
+
+	return report.Clone(), nil
+}
+
+func (m *MemoryService) GetAllExpenseReports() ([]*domain.ExpenseReport, error) {
+	m.logger.Debug("Getting all expense reports")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	reports := make([]*domain.ExpenseReport, 0, len(m.ExpenseReports))
+	for _, report := range m.ExpenseReports {
+		reports = append(reports, report.Clone())
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].CreatedAt.Before(reports[j].CreatedAt)
+	})
+
+	m.logger.Info("Retrieved all expense reports", "count", len(reports))
+	return reports, nil
+}
+
+func (m *MemoryService) UpdateExpenseReport(report *domain.ExpenseReport) error {
+	m.logger.Debug("Updating expense report", "id", report.ID, "title", report.Title, "status", report.Status)
+
+	m.Lock()
+	defer m.Unlock()
+
+	id := report.ID.String()
+	if _, exists := m.ExpenseReports[id]; !exists {
+		m.logger.Warn("Expense report not found for update", "id", id)
+		return domain.ErrExpenseReportNotFound
+	}
+
+	m.ExpenseReports[id] = report.Clone()
+	m.logger.Info("Expense report updated successfully", "id", id)
+	return nil
+}
+
+func (m *MemoryService) DeleteExpenseReport(id string) error {
+	m.logger.Debug("Deleting expense report", "id", id)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.ExpenseReports[id]; !exists {
+		m.logger.Warn("Expense report not found for deletion", "id", id)
+		return domain.ErrExpenseReportNotFound
+	}
+
+	delete(m.ExpenseReports, id)
+	m.logger.Info("Expense report deleted successfully", "id", id, "remaining_count", len(m.ExpenseReports))
+	return nil
+}
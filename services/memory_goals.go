@@ -0,0 +1,89 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"sort"
+)
+
+func (m *MemoryService) AddGoal(goal *domain.Goal) error {
+	m.logger.Debug("Adding goal", "id", goal.ID, "name", goal.Name, "target_amount", goal.TargetAmount)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.Goals[goal.ID.String()]; exists {
+		m.logger.Warn("Goal already exists", "id", goal.ID)
+		return domain.ErrGoalAlreadyExists
+	}
+
+	m.Goals[goal.ID.String()] = copyOf(goal)
+	m.logger.Info("Goal added successfully", "id", goal.ID, "total_count", len(m.Goals))
+	return nil
+}
+
+func (m *MemoryService) GetGoalByID(id string) (*domain.Goal, error) {
+	m.logger.Debug("Getting goal by ID", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	goal, exists := m.Goals[id]
+	if !exists {
+		m.logger.Warn("Goal not found", "id", id)
+		return nil, domain.ErrGoalNotFound
+	}
+
+	return copyOf(goal), nil
+}
+
+func (m *MemoryService) GetAllGoals() ([]*domain.Goal, error) {
+	m.logger.Debug("Getting all goals")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	goals := make([]*domain.Goal, 0, len(m.Goals))
+	for _, goal := range m.Goals {
+		goals = append(goals, copyOf(goal))
+	}
+
+	sort.Slice(goals, func(i, j int) bool {
+		return goals[i].Deadline.Before(goals[j].Deadline)
+	})
+
+	m.logger.Info("Retrieved all goals", "count", len(goals))
+	return goals, nil
+}
+
+func (m *MemoryService) UpdateGoal(goal *domain.Goal) error {
+	m.logger.Debug("Updating goal", "id", goal.ID, "name", goal.Name, "target_amount", goal.TargetAmount)
+
+	m.Lock()
+	defer m.Unlock()
+
+	id := goal.ID.String()
+	if _, exists := m.Goals[id]; !exists {
+		m.logger.Warn("Goal not found for update", "id", id)
+		return domain.ErrGoalNotFound
+	}
+
+	m.Goals[id] = copyOf(goal)
+	m.logger.Info("Goal updated successfully", "id", id)
+	return nil
+}
+
+func (m *MemoryService) DeleteGoal(id string) error {
+	m.logger.Debug("Deleting goal", "id", id)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.Goals[id]; !exists {
+		m.logger.Warn("Goal not found for deletion", "id", id)
+		return domain.ErrGoalNotFound
+	}
+
+	delete(m.Goals, id)
+	m.logger.Info("Goal deleted successfully", "id", id, "remaining_count", len(m.Goals))
+	return nil
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (m *MemoryService) AddHousehold(household *domain.Household) error {
+	m.logger.Debug("Adding household", "id", household.ID, "name", household.Name)
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.Households[household.ID.String()] = household.Clone()
+	m.logger.Info("Household added successfully", "id", household.ID, "total_count", len(m.Households))
+	return nil
+}
+
+func (m *MemoryService) GetHouseholdByID(id string) (*domain.Household, error) {
+	m.logger.Debug("Getting household by ID", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	household, exists := m.Households[id]
+	if !exists {
+		m.logger.Warn("Household not found", "id", id)
+		return nil, domain.ErrHouseholdNotFound
+	}
+
+	return household.Clone(), nil
+}
+
+func (m *MemoryService) GetAllHouseholds() ([]*domain.Household, error) {
+	m.logger.Debug("Getting all households")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	households := make([]*domain.Household, 0, len(m.Households))
+	for _, household := range m.Households {
+		households = append(households, household.Clone())
+	}
+
+	sort.Slice(households, func(i, j int) bool {
+		return households[i].CreatedAt.Before(households[j].CreatedAt)
+	})
+
+	m.logger.Info("Retrieved all households", "count", len(households))
+	return households, nil
+}
+
+func (m *MemoryService) GetHouseholdMember(id string) (*domain.HouseholdMember, error) {
+	m.logger.Debug("Getting household member", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	for _, household := range m.Households {
+		for _, member := range household.Members {
+			if member.ID.String() == id {
+				return &member, nil
+			}
+		}
+	}
+
+	m.logger.Warn("Household member not found", "id", id)
+	return nil, domain.ErrMemberNotFound
+}
+
+func (m *MemoryService) SetHouseholdMemberAllowance(id string, categoryId uuid.UUID) error {
+	m.logger.Debug("Setting household member allowance", "id", id, "category_id", categoryId)
+
+	m.Lock()
+	defer m.Unlock()
+
+	for _, household := range m.Households {
+		for i := range household.Members {
+			if household.Members[i].ID.String() == id {
+				household.Members[i].AllowanceCategoryId = categoryId
+				m.logger.Info("Household member allowance set successfully", "id", id, "category_id", categoryId)
+				return nil
+			}
+		}
+	}
+
+	m.logger.Warn("Household member not found", "id", id)
+	return domain.ErrMemberNotFound
+}
+
+func (m *MemoryService) AddInvitation(invitation *domain.Invitation) error {
+	m.logger.Debug("Adding invitation", "id", invitation.ID, "household_id", invitation.HouseholdId)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.Households[invitation.HouseholdId.String()]; !exists {
+		m.logger.Warn("Household not found for invitation", "household_id", invitation.HouseholdId)
+		return domain.ErrHouseholdNotFound
+	}
+
+	m.Invitations[invitation.ID.String()] = invitation.Clone()
+	m.logger.Info("Invitation added successfully", "id", invitation.ID, "household_id", invitation.HouseholdId)
+	return nil
+}
+
+func (m *MemoryService) GetInvitationByID(id string) (*domain.Invitation, error) {
+	m.logger.Debug("Getting invitation by ID", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	invitation, exists := m.Invitations[id]
+	if !exists {
+		m.logger.Warn("Invitation not found", "id", id)
+		return nil, domain.ErrInvitationNotFound
+	}
+
+	return invitation.Clone(), nil
+}
+
+func (m *MemoryService) GetInvitationByTokenHash(hash string) (*domain.Invitation, error) {
+	m.logger.Debug("Getting invitation by token")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	for _, invitation := range m.Invitations {
+		if invitation.TokenHash == hash {
+			return invitation.Clone(), nil
+		}
+	}
+
+	m.logger.Warn("Invitation not found by token")
+	return nil, domain.ErrInvitationNotFound
+}
+
+func (m *MemoryService) GetInvitations(householdId string) ([]*domain.Invitation, error) {
+	m.logger.Debug("Getting invitations", "household_id", householdId)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	var invitations []*domain.Invitation
+	for _, invitation := range m.Invitations {
+		if invitation.HouseholdId.String() == householdId {
+			invitations = append(invitations, invitation.Clone())
+		}
+	}
+
+	// Newest first, by ID among those created at once, as the database lists them
+	sort.Slice(invitations, func(i, j int) bool {
+		if !invitations[i].CreatedAt.Equal(invitations[j].CreatedAt) {
+			return invitations[i].CreatedAt.After(invitations[j].CreatedAt)
+		}
+		return invitations[i].ID.String() < invitations[j].ID.String()
+	})
+
+	m.logger.Info("Retrieved invitations", "household_id", householdId, "count", len(invitations))
+	return invitations, nil
+}
+
+func (m *MemoryService) RevokeInvitation(invitation *domain.Invitation) error {
+	m.logger.Debug("Revoking invitation", "id", invitation.ID)
+
+	m.Lock()
+	defer m.Unlock()
+
+	stored, exists := m.Invitations[invitation.ID.String()]
+	if !exists {
+		m.logger.Warn("Invitation not found for revoking", "id", invitation.ID)
+		return domain.ErrInvitationNotFound
+	}
+	if stored.AcceptedAt != nil {
+		return domain.ErrInvitationAccepted
+	}
+
+	m.Invitations[invitation.ID.String()] = invitation.Clone()
+	m.logger.Info("Invitation revoked successfully", "id", invitation.ID)
+	return nil
+}
+
+func (m *MemoryService) AcceptInvitation(invitation *domain.Invitation, member *domain.HouseholdMember) error {
+	m.logger.Debug("Accepting invitation", "id", invitation.ID, "member_id", member.ID)
+
+	m.Lock()
+	defer m.Unlock()
+
+	stored, exists := m.Invitations[invitation.ID.String()]
+	if !exists {
+		m.logger.Warn("Invitation not found for accepting", "id", invitation.ID)
+		return domain.ErrInvitationNotFound
+	}
+	// Another request may have accepted or revoked it since it was read
+	switch stored.Status(time.Now()) {
+	case domain.InvitationAccepted:
+		return domain.ErrInvitationAccepted
+	case domain.InvitationRevoked:
+		return domain.ErrInvitationRevoked
+	}
+
+	household, exists := m.Households[invitation.HouseholdId.String()]
+	if !exists {
+		m.logger.Warn("Household not found for invitation", "household_id", invitation.HouseholdId)
+		return domain.ErrHouseholdNotFound
+	}
+
+	household.Members = append(household.Members, *member)
+	m.Invitations[invitation.ID.String()] = invitation.Clone()
+	m.logger.Info("Invitation accepted successfully", "id", invitation.ID, "household_id", household.ID, "member_id", member.ID)
+	return nil
+}
+
+func (m *MemoryService) RemoveHouseholdMember(id string) error {
+	m.logger.Debug("Removing household member", "id", id)
+
+	m.Lock()
+	defer m.Unlock()
+
+	for householdID, household := range m.Households {
+		for i, member := range household.Members {
+			if member.ID.String() != id {
+				continue
+			}
+
+			household.Members = append(household.Members[:i], household.Members[i+1:]...)
+			for invitationID, invitation := range m.Invitations {
+				switch {
+				case invitation.HouseholdId != household.ID:
+				case len(household.Members) == 0:
+					delete(m.Invitations, invitationID)
+				case invitation.MemberId == member.ID:
+					invitation.Email = ""
+				}
+			}
+			if len(household.Members) == 0 {
+				delete(m.Households, householdID)
+			}
+
+			m.logger.Info("Household member removed successfully", "id", id, "household_id", household.ID, "remaining_members", len(household.Members))
+			return nil
+		}
+	}
+
+	m.logger.Warn("Household member not found", "id", id)
+	return domain.ErrMemberNotFound
+}
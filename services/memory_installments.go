@@ -0,0 +1,68 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"sort"
+)
+
+func (m *MemoryService) AddInstallmentPurchase(purchase *domain.InstallmentPurchase) error {
+	m.logger.Debug("Adding installment purchase", "id", purchase.ID, "description", purchase.Description, "total_amount", purchase.TotalAmount)
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.InstallmentPurchases[purchase.ID.String()] = purchase.Clone()
+	m.logger.Info("Installment purchase added successfully", "id", purchase.ID, "total_count", len(m.InstallmentPurchases))
+	return nil
+}
+
+func (m *MemoryService) GetInstallmentPurchaseByID(id string) (*domain.InstallmentPurchase, error) {
+	m.logger.Debug("Getting installment purchase by ID", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	purchase, exists := m.InstallmentPurchases[id]
+	if !exists {
+		m.logger.Warn("Installment purchase not found", "id", id)
+		return nil, domain.ErrInstallmentPurchaseNotFound
+	}
+
+	return purchase.Clone(), nil
+}
+
+func (m *MemoryService) GetAllInstallmentPurchases() ([]*domain.InstallmentPurchase, error) {
+	m.logger.Debug("Getting all installment purchases")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	purchases := make([]*domain.InstallmentPurchase, 0, len(m.InstallmentPurchases))
+	for _, purchase := range m.InstallmentPurchases {
+		purchases = append(purchases, purchase.Clone())
+	}
+
+	sort.Slice(purchases, func(i, j int) bool {
+		return purchases[i].CreatedAt.Before(purchases[j].CreatedAt)
+	})
+
+	m.logger.Info("Retrieved all installment purchases", "count", len(purchases))
+	return purchases, nil
+}
+
+func (m *MemoryService) UpdateInstallmentPurchase(purchase *domain.InstallmentPurchase) error {
+	m.logger.Debug("Updating installment purchase", "id", purchase.ID, "total_amount", purchase.TotalAmount)
+
+	m.Lock()
+	defer m.Unlock()
+
+	id := purchase.ID.String()
+	if _, exists := m.InstallmentPurchases[id]; !exists {
+		m.logger.Warn("Installment purchase not found for update", "id", id)
+		return domain.ErrInstallmentPurchaseNotFound
+	}
+
+	m.InstallmentPurchases[id] = purchase.Clone()
+	m.logger.Info("Installment purchase updated successfully", "id", id)
+	return nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"sort"
+)
+
+func (m *MemoryService) AddMerchant(merchant *domain.Merchant) error {
+	m.logger.Debug("Adding merchant", "id", merchant.ID, "name", merchant.Name)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.Merchants[merchant.ID.String()]; exists {
+		m.logger.Warn("Merchant already exists", "id", merchant.ID)
+		return domain.ErrMerchantAlreadyExists
+	}
+
+	m.Merchants[merchant.ID.String()] = merchant.Clone()
+	m.logger.Info("Merchant added successfully", "id", merchant.ID, "total_count", len(m.Merchants))
+	return nil
+}
+
+func (m *MemoryService) GetMerchantByID(id string) (*domain.Merchant, error) {
+	m.logger.Debug("Getting merchant by ID", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	merchant, exists := m.Merchants[id]
+	if !exists {
+		m.logger.Warn("Merchant not found", "id", id)
+		return nil, domain.ErrMerchantNotFound
+	}
+
+	return merchant.Clone(), nil
+}
+
+func (m *MemoryService) GetAllMerchants() ([]*domain.Merchant, error) {
+	m.logger.Debug("Getting all merchants")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	merchants := make([]*domain.Merchant, 0, len(m.Merchants))
+	for _, merchant := range m.Merchants {
+		merchants = append(merchants, merchant.Clone())
+	}
+
+	sort.Slice(merchants, func(i, j int) bool {
+		return merchants[i].Name < merchants[j].Name
+	})
+
+	m.logger.Info("Retrieved all merchants", "count", len(merchants))
+	return merchants, nil
+}
+
+func (m *MemoryService) UpdateMerchant(merchant *domain.Merchant) error {
+	m.logger.Debug("Updating merchant", "id", merchant.ID, "name", merchant.Name)
+
+	m.Lock()
+	defer m.Unlock()
+
+	id := merchant.ID.String()
+	if _, exists := m.Merchants[id]; !exists {
+		m.logger.Warn("Merchant not found for update", "id", id)
+		return domain.ErrMerchantNotFound
+	}
+
+	m.Merchants[id] = merchant.Clone()
+	m.logger.Info("Merchant updated successfully", "id", id)
+	return nil
+}
+
+func (m *MemoryService) DeleteMerchant(id string) error {
+	m.logger.Debug("Deleting merchant", "id", id)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.Merchants[id]; !exists {
+		m.logger.Warn("Merchant not found for deletion", "id", id)
+		return domain.ErrMerchantNotFound
+	}
+
+	delete(m.Merchants, id)
+	m.logger.Info("Merchant deleted successfully", "id", id, "remaining_count", len(m.Merchants))
+	return nil
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (m *MemoryService) EnableOutbox(enabled bool) error {
+	m.logger.Debug("Setting outbox", "enabled", enabled)
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.outboxEnabled = enabled
+	m.logger.Info("Outbox set successfully", "enabled", enabled)
+	return nil
+}
+
+// writeOutbox queues a message about a changed expenditure; callers hold the lock, which makes
+// the message part of the change
+func (m *MemoryService) writeOutbox(topic string, expenditure *domain.Expenditure) {
+	if !m.outboxEnabled {
+		return
+	}
+
+	state := expenditure.Clone()
+	now := time.Now()
+	m.outboxSeq++
+	m.Outbox = append(m.Outbox, &domain.OutboxMessage{
+		ID:            m.outboxSeq,
+		Topic:         topic,
+		ExpenditureID: expenditure.ID,
+		Expenditure:   state,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	})
+}
+
+func (m *MemoryService) GetPendingOutboxMessages(limit int) ([]*domain.OutboxMessage, error) {
+	m.logger.Debug("Getting pending outbox messages", "limit", limit)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	var messages []*domain.OutboxMessage
+	for _, message := range m.Outbox {
+		if len(messages) == limit {
+			break
+		}
+		if message.NextAttemptAt.IsZero() {
+			continue
+		}
+		messages = append(messages, message.Clone())
+	}
+
+	m.logger.Debug("Retrieved pending outbox messages", "count", len(messages))
+	return messages, nil
+}
+
+func (m *MemoryService) GetFailedOutboxMessages(limit int) ([]*domain.OutboxMessage, error) {
+	m.logger.Debug("Getting failed outbox messages", "limit", limit)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	var messages []*domain.OutboxMessage
+	for _, message := range m.Outbox {
+		if len(messages) == limit {
+			break
+		}
+		if !message.NextAttemptAt.IsZero() {
+			continue
+		}
+		messages = append(messages, message.Clone())
+	}
+
+	m.logger.Debug("Retrieved failed outbox messages", "count", len(messages))
+	return messages, nil
+}
+
+func (m *MemoryService) MarkOutboxMessageDelivered(id int64) error {
+	m.logger.Debug("Marking outbox message delivered", "id", id)
+
+	m.Lock()
+	defer m.Unlock()
+
+	for i, message := range m.Outbox {
+		if message.ID == id {
+			m.Outbox = append(m.Outbox[:i], m.Outbox[i+1:]...)
+			m.logger.Info("Outbox message delivered", "id", id, "remaining_count", len(m.Outbox))
+			return nil
+		}
+	}
+
+	m.logger.Warn("Outbox message not found", "id", id)
+	return domain.ErrOutboxMessageNotFound
+}
+
+func (m *MemoryService) MarkOutboxMessageFailed(id int64, reason string, retryAt time.Time) error {
+	m.logger.Debug("Marking outbox message failed", "id", id, "reason", reason)
+
+	m.Lock()
+	defer m.Unlock()
+
+	for _, message := range m.Outbox {
+		if message.ID == id {
+			message.Attempts++
+			message.LastError = reason
+			message.NextAttemptAt = retryAt
+			m.logger.Info("Outbox message failed", "id", id, "attempts", message.Attempts, "retry_at", retryAt)
+			return nil
+		}
+	}
+
+	m.logger.Warn("Outbox message not found", "id", id)
+	return domain.ErrOutboxMessageNotFound
+}
+
+func (m *MemoryService) RedeliverOutboxMessage(id int64) error {
+	m.logger.Debug("Redelivering outbox message", "id", id)
+
+	m.Lock()
+	defer m.Unlock()
+
+	for _, message := range m.Outbox {
+		if message.ID == id {
+			if !message.NextAttemptAt.IsZero() {
+				return domain.ErrOutboxMessageNotFailed
+			}
+			message.Attempts = 0
+			message.NextAttemptAt = time.Now()
+			m.logger.Info("Outbox message queued for redelivery", "id", id)
+			return nil
+		}
+	}
+
+	m.logger.Warn("Outbox message not found", "id", id)
+	return domain.ErrOutboxMessageNotFound
+}
+
+func (m *MemoryService) ScrubOutboxMessages(member uuid.UUID) (int, error) {
+	m.logger.Debug("Scrubbing outbox messages", "member_id", member)
+
+	m.Lock()
+	defer m.Unlock()
+
+	scrubbed := 0
+	for _, message := range m.Outbox {
+		if member != uuid.Nil && message.Expenditure != nil && message.Expenditure.CreatedBy == member {
+			message.Expenditure.CreatedBy = uuid.Nil
+			scrubbed++
+		}
+	}
+
+	m.logger.Info("Outbox messages scrubbed successfully", "member_id", member, "count", scrubbed)
+	return scrubbed, nil
+}
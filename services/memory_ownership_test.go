@@ -0,0 +1,104 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"go-expense-tracker/storagetest"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// TestMemoryOwnership checks the other memory repositories store and hand out copies that share
+// no memory with what they were given or handed out before
+func TestMemoryOwnership(t *testing.T) {
+	m := NewMemoryService(slog.New(slog.DiscardHandler))
+
+	tests := []struct {
+		name string
+		// roundTrip stores a populated object and returns it along with two fetched copies
+		roundTrip func(t *testing.T) (stored, first, second any)
+	}{
+		{"account", func(t *testing.T) (any, any, any) {
+			v := &domain.Account{ID: uuid.New()}
+			return roundTrip(t, v, v.ID.String(), m.AddAccount, m.GetAccountByID)
+		}},
+		{"bank connection", func(t *testing.T) (any, any, any) {
+			v := &domain.BankConnection{ID: uuid.New()}
+			return roundTrip(t, v, v.ID.String(), m.AddBankConnection, m.GetBankConnectionByID)
+		}},
+		{"budget", func(t *testing.T) (any, any, any) {
+			v := &domain.Budget{ID: uuid.New()}
+			return roundTrip(t, v, v.ID.String(), m.AddBudget, m.GetBudgetByID)
+		}},
+		{"draft", func(t *testing.T) (any, any, any) {
+			v := &domain.Draft{Expenditure: domain.Expenditure{ID: uuid.New()}}
+			return roundTrip(t, v, v.ID.String(), m.AddDraft, m.GetDraftByID)
+		}},
+		{"expense report", func(t *testing.T) (any, any, any) {
+			v := &domain.ExpenseReport{ID: uuid.New()}
+			return roundTrip(t, v, v.ID.String(), m.AddExpenseReport, m.GetExpenseReportByID)
+		}},
+		{"household", func(t *testing.T) (any, any, any) {
+			v := &domain.Household{ID: uuid.New()}
+			return roundTrip(t, v, v.ID.String(), m.AddHousehold, m.GetHouseholdByID)
+		}},
+		{"invitation", func(t *testing.T) (any, any, any) {
+			household := &domain.Household{ID: uuid.New()}
+			if err := m.AddHousehold(household); err != nil {
+				t.Fatalf("adding household: %v", err)
+			}
+			v := &domain.Invitation{ID: uuid.New(), HouseholdId: household.ID}
+			return roundTrip(t, v, v.ID.String(), m.AddInvitation, m.GetInvitationByID)
+		}},
+		{"installment purchase", func(t *testing.T) (any, any, any) {
+			v := &domain.InstallmentPurchase{ID: uuid.New()}
+			return roundTrip(t, v, v.ID.String(), m.AddInstallmentPurchase, m.GetInstallmentPurchaseByID)
+		}},
+		{"merchant", func(t *testing.T) (any, any, any) {
+			v := &domain.Merchant{ID: uuid.New()}
+			return roundTrip(t, v, v.ID.String(), m.AddMerchant, m.GetMerchantByID)
+		}},
+		{"recurring expenditure", func(t *testing.T) (any, any, any) {
+			v := &domain.RecurringExpenditure{ID: uuid.New()}
+			return roundTrip(t, v, v.ID.String(), m.AddRecurring, m.GetRecurringByID)
+		}},
+		{"report snapshot", func(t *testing.T) (any, any, any) {
+			v := &domain.ReportSnapshot{ID: uuid.New()}
+			return roundTrip(t, v, v.ID.String(), m.AddReportSnapshot, m.GetReportSnapshotByID)
+		}},
+		{"view", func(t *testing.T) (any, any, any) {
+			v := &domain.View{ID: uuid.New()}
+			return roundTrip(t, v, v.ID.String(), m.AddView, m.GetViewByID)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored, first, second := tt.roundTrip(t)
+			if path, shared := storagetest.SharesMemory(stored, first); shared {
+				t.Errorf("%s is shared with the object that was stored", path)
+			}
+			if path, shared := storagetest.SharesMemory(first, second); shared {
+				t.Errorf("%s is shared between two fetched copies", path)
+			}
+		})
+	}
+}
+
+func roundTrip[T any](t *testing.T, v *T, id string, add func(*T) error, get func(string) (*T, error)) (any, any, any) {
+	t.Helper()
+	storagetest.Populate(v)
+	if err := add(v); err != nil {
+		t.Fatalf("adding: %v", err)
+	}
+	first, err := get(id)
+	if err != nil {
+		t.Fatalf("getting: %v", err)
+	}
+	second, err := get(id)
+	if err != nil {
+		t.Fatalf("getting again: %v", err)
+	}
+	return v, first, second
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"sort"
+)
+
+func (m *MemoryService) AddRecurring(recurring *domain.RecurringExpenditure) error {
+	m.logger.Debug("Adding recurring expenditure", "id", recurring.ID, "description", recurring.Description, "frequency", recurring.Frequency)
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.Recurring[recurring.ID.String()] = recurring.Clone()
+	m.logger.Info("Recurring expenditure added successfully", "id", recurring.ID, "total_count", len(m.Recurring))
+	return nil
+}
+
+func (m *MemoryService) GetRecurringByID(id string) (*domain.RecurringExpenditure, error) {
+	m.logger.Debug("Getting recurring expenditure by ID", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	recurring, exists := m.Recurring[id]
+	if !exists {
+		m.logger.Warn("Recurring expenditure not found", "id", id)
+		return nil, domain.ErrRecurringNotFound
+	}
+
+	return recurring.Clone(), nil
+}
+
+func (m *MemoryService) GetAllRecurring() ([]*domain.RecurringExpenditure, error) {
+	m.logger.Debug("Getting all recurring expenditures")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	recurring := make([]*domain.RecurringExpenditure, 0, len(m.Recurring))
+	for _, r := range m.Recurring {
+		recurring = append(recurring, r.Clone())
+	}
+
+	sort.Slice(recurring, func(i, j int) bool {
+		return recurring[i].CreatedAt.Before(recurring[j].CreatedAt)
+	})
+
+	m.logger.Info("Retrieved all recurring expenditures", "count", len(recurring))
+	return recurring, nil
+}
+
+func (m *MemoryService) UpdateRecurring(recurring *domain.RecurringExpenditure) error {
+	m.logger.Debug("Updating recurring expenditure", "id", recurring.ID, "occurrences", recurring.Occurrences, "paused", recurring.Paused)
+
+	m.Lock()
+	defer m.Unlock()
+
+	id := recurring.ID.String()
+	if _, exists := m.Recurring[id]; !exists {
+		m.logger.Warn("Recurring expenditure not found for update", "id", id)
+		return domain.ErrRecurringNotFound
+	}
+
+	m.Recurring[id] = recurring.Clone()
+	m.logger.Info("Recurring expenditure updated successfully", "id", id)
+	return nil
+}
+
+func (m *MemoryService) DeleteRecurring(id string) error {
+	m.logger.Debug("Deleting recurring expenditure", "id", id)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.Recurring[id]; !exists {
+		m.logger.Warn("Recurring expenditure not found for deletion", "id", id)
+		return domain.ErrRecurringNotFound
+	}
+
+	delete(m.Recurring, id)
+	m.logger.Info("Recurring expenditure deleted successfully", "id", id, "remaining_count", len(m.Recurring))
+	return nil
+}
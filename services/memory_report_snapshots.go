@@ -0,0 +1,54 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"sort"
+)
+
+func (m *MemoryService) AddReportSnapshot(snapshot *domain.ReportSnapshot) error {
+	m.logger.Debug("Adding report snapshot", "id", snapshot.ID, "month", snapshot.Month)
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.ReportSnapshots[snapshot.ID.String()] = snapshot.Clone()
+	m.logger.Info("Report snapshot added successfully", "id", snapshot.ID, "total_count", len(m.ReportSnapshots))
+	return nil
+}
+
+func (m *MemoryService) GetReportSnapshotByID(id string) (*domain.ReportSnapshot, error) {
+	m.logger.Debug("Getting report snapshot by ID", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	snapshot, exists := m.ReportSnapshots[id]
+	if !exists {
+		m.logger.Warn("Report snapshot not found", "id", id)
+		return nil, domain.ErrReportSnapshotNotFound
+	}
+
+	return snapshot.Clone(), nil
+}
+
+func (m *MemoryService) GetAllReportSnapshots() ([]*domain.ReportSnapshot, error) {
+	m.logger.Debug("Getting all report snapshots")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	snapshots := make([]*domain.ReportSnapshot, 0, len(m.ReportSnapshots))
+	for _, snapshot := range m.ReportSnapshots {
+		snapshots = append(snapshots, snapshot.Clone())
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		if snapshots[i].Month != snapshots[j].Month {
+			return snapshots[i].Month > snapshots[j].Month
+		}
+		return snapshots[i].TakenAt.After(snapshots[j].TakenAt)
+	})
+
+	m.logger.Info("Retrieved all report snapshots", "count", len(snapshots))
+	return snapshots, nil
+}
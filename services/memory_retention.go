@@ -0,0 +1,36 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"sort"
+)
+
+func (m *MemoryService) AddRetentionPurge(purge *domain.RetentionPurge) error {
+	m.logger.Debug("Adding retention purge", "id", purge.ID, "target", purge.Target)
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.RetentionPurges = append(m.RetentionPurges, purge.Clone())
+	m.logger.Info("Retention purge added successfully", "id", purge.ID, "target", purge.Target, "count", purge.Count)
+	return nil
+}
+
+func (m *MemoryService) GetAllRetentionPurges() ([]*domain.RetentionPurge, error) {
+	m.logger.Debug("Getting all retention purges")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	purges := make([]*domain.RetentionPurge, 0, len(m.RetentionPurges))
+	for _, purge := range m.RetentionPurges {
+		purges = append(purges, purge.Clone())
+	}
+
+	sort.SliceStable(purges, func(i, j int) bool {
+		return purges[i].PurgedAt.After(purges[j].PurgedAt)
+	})
+
+	m.logger.Info("Retrieved all retention purges", "count", len(purges))
+	return purges, nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"sort"
+)
+
+func (m *MemoryService) AddRule(rule *domain.CategorizationRule) error {
+	m.logger.Debug("Adding rule", "id", rule.ID, "name", rule.Name)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.Rules[rule.ID.String()]; exists {
+		m.logger.Warn("Rule already exists", "id", rule.ID)
+		return domain.ErrRuleAlreadyExists
+	}
+
+	m.Rules[rule.ID.String()] = rule.Clone()
+	m.logger.Info("Rule added successfully", "id", rule.ID, "total_count", len(m.Rules))
+	return nil
+}
+
+func (m *MemoryService) GetRuleByID(id string) (*domain.CategorizationRule, error) {
+	m.logger.Debug("Getting rule by ID", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	rule, exists := m.Rules[id]
+	if !exists {
+		m.logger.Warn("Rule not found", "id", id)
+		return nil, domain.ErrRuleNotFound
+	}
+
+	return rule.Clone(), nil
+}
+
+func (m *MemoryService) GetAllRules() ([]*domain.CategorizationRule, error) {
+	m.logger.Debug("Getting all rules")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	rules := make([]*domain.CategorizationRule, 0, len(m.Rules))
+	for _, rule := range m.Rules {
+		rules = append(rules, rule.Clone())
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].CreatedAt.Before(rules[j].CreatedAt)
+	})
+
+	m.logger.Info("Retrieved all rules", "count", len(rules))
+	return rules, nil
+}
+
+func (m *MemoryService) UpdateRule(rule *domain.CategorizationRule) error {
+	m.logger.Debug("Updating rule", "id", rule.ID, "name", rule.Name)
+
+	m.Lock()
+	defer m.Unlock()
+
+	id := rule.ID.String()
+	if _, exists := m.Rules[id]; !exists {
+		m.logger.Warn("Rule not found for update", "id", id)
+		return domain.ErrRuleNotFound
+	}
+
+	m.Rules[id] = rule.Clone()
+	m.logger.Info("Rule updated successfully", "id", id)
+	return nil
+}
+
+func (m *MemoryService) DeleteRule(id string) error {
+	m.logger.Debug("Deleting rule", "id", id)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.Rules[id]; !exists {
+		m.logger.Warn("Rule not found for deletion", "id", id)
+		return domain.ErrRuleNotFound
+	}
+
+	delete(m.Rules, id)
+	m.logger.Info("Rule deleted successfully", "id", id, "remaining_count", len(m.Rules))
+	return nil
+}
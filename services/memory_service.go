@@ -3,13 +3,43 @@ package services
 import (
 	domain "go-expense-tracker/domain"
 	"log/slog"
+	"strings"
 	"sync"
+	"time"
+
+	"github.com/google/uuid"
 )
 
+// MemoryService keeps everything in memory behind one lock. Expenditures and categories are
+// copied on the way in and out, so callers cannot change the stored ones without the lock
 type MemoryService struct {
-	Expenditures map[string]*domain.Expenditure
-	Categories   map[string]*domain.Category
-	logger       *slog.Logger
+	expenditures         *expenditureIndex
+	Categories           map[string]*domain.Category
+	StagedExpenditures   map[string]*domain.StagedExpenditure
+	Drafts               map[string]*domain.Draft
+	Views                map[string]*domain.View
+	Pins                 map[uuid.UUID]time.Time // When each pinned expenditure was pinned
+	BankConnections      map[string]*domain.BankConnection
+	Goals                map[string]*domain.Goal
+	Budgets              map[string]*domain.Budget
+	Recurring            map[string]*domain.RecurringExpenditure
+	InstallmentPurchases map[string]*domain.InstallmentPurchase
+	Accounts             map[string]*domain.Account
+	EnvelopeAllocations  []*domain.EnvelopeAllocation // Oldest first
+	ReportSnapshots      map[string]*domain.ReportSnapshot
+	Households           map[string]*domain.Household
+	Invitations          map[string]*domain.Invitation
+	ExpenditureEvents    []*domain.ExpenditureEvent // Oldest first
+	Outbox               []*domain.OutboxMessage    // Undelivered messages, oldest first
+	outboxEnabled        bool
+	outboxSeq            int64
+	ExpenseReports       map[string]*domain.ExpenseReport
+	Merchants            map[string]*domain.Merchant
+	Rules                map[string]*domain.CategorizationRule
+	Archives             map[string]*domain.Archive
+	ArchivedExpenditures map[string][]*domain.Expenditure // Keyed by archive ID
+	RetentionPurges      []*domain.RetentionPurge         // Oldest first
+	logger               *slog.Logger
 	sync.RWMutex
 }
 
@@ -20,34 +50,37 @@ func NewMemoryService(logger *slog.Logger) *MemoryService {
 		return nil
 	}
 	return &MemoryService{
-		Expenditures: make(map[string]*domain.Expenditure),
-		Categories:   categories,
-		logger:       logger,
+		expenditures:         newExpenditureIndex(),
+		Categories:           categories,
+		StagedExpenditures:   make(map[string]*domain.StagedExpenditure),
+		Drafts:               make(map[string]*domain.Draft),
+		Views:                make(map[string]*domain.View),
+		Pins:                 make(map[uuid.UUID]time.Time),
+		BankConnections:      make(map[string]*domain.BankConnection),
+		Goals:                make(map[string]*domain.Goal),
+		Budgets:              make(map[string]*domain.Budget),
+		Recurring:            make(map[string]*domain.RecurringExpenditure),
+		InstallmentPurchases: make(map[string]*domain.InstallmentPurchase),
+		Accounts:             make(map[string]*domain.Account),
+		ReportSnapshots:      make(map[string]*domain.ReportSnapshot),
+		Households:           make(map[string]*domain.Household),
+		Invitations:          make(map[string]*domain.Invitation),
+		ExpenseReports:       make(map[string]*domain.ExpenseReport),
+		Merchants:            make(map[string]*domain.Merchant),
+		Rules:                make(map[string]*domain.CategorizationRule),
+		Archives:             make(map[string]*domain.Archive),
+		ArchivedExpenditures: make(map[string][]*domain.Expenditure),
+		logger:               logger,
 	}
 }
 
 func setupCategories() (map[string]*domain.Category, error) {
 
 	categories := make(map[string]*domain.Category)
-	categoryData := map[string]string{
-		"Food & Dining":      "#FF6B6B",
-		"Transportation":     "#4ECDC4",
-		"Housing":            "#1A535C",
-		"Utilities":          "#FFE66D",
-		"Health & Fitness":   "#2EC4B6",
-		"Entertainment":      "#FF9F1C",
-		"Shopping":           "#C084FC",
-		"Travel":             "#00A8E8",
-		"Education":          "#6D6875",
-		"Financial Services": "#5D2E8C",
-		"Personal Care":      "#FFB6B9",
-		"Gifts & Donations":  "#FF7E67",
-		"Miscellaneous":      "#A0AEC0",
-	}
-
-	for name, color := range categoryData {
+	for name, color := range domain.DefaultCategories {
 		category, err := domain.NewCategory(name, color)
 		if err == nil {
+			category.Icon = domain.DefaultCategoryIcons[name]
 			categories[category.ID.String()] = category
 		}
 	}
@@ -65,13 +98,64 @@ func (m *MemoryService) AddExpenditure(expenditure *domain.Expenditure) error {
 	m.Lock()
 	defer m.Unlock()
 
-	if _, exists := m.Expenditures[expenditure.ID.String()]; exists {
+	if _, exists := m.expenditures.get(expenditure.ID.String()); exists {
 		m.logger.Warn("Expenditure already exists", "id", expenditure.ID)
 		return domain.ErrExpenditureAlreadyExists
 	}
+	if err := m.checkRefund(expenditure); err != nil {
+		return err
+	}
 
-	m.Expenditures[expenditure.ID.String()] = expenditure
-	m.logger.Info("Expenditure added successfully", "id", expenditure.ID, "total_count", len(m.Expenditures))
+	m.expenditures.put(expenditure.Clone())
+	m.writeOutbox(domain.OutboxExpenditureCreated, expenditure)
+	m.logger.Info("Expenditure added successfully", "id", expenditure.ID, "total_count", m.expenditures.len())
+	return nil
+}
+
+// checkRefund checks that a refund points to an existing expenditure and that, with the refunds
+// recorded before, it does not exceed it; the caller holds the lock
+func (m *MemoryService) checkRefund(expenditure *domain.Expenditure) error {
+	if !expenditure.IsRefund() {
+		return nil
+	}
+	original, exists := m.expenditures.get(expenditure.RefundOf.String())
+	if !exists {
+		m.logger.Warn("Original of refund not found", "id", expenditure.ID, "refund_of", expenditure.RefundOf)
+		return domain.ErrExpenditureNotFound
+	}
+	if refunded := m.refunded(original.ID) - expenditure.Amount; !domain.RefundsWithin(original.Amount, refunded) {
+		m.logger.Warn("Refunds exceed original expenditure", "id", expenditure.ID, "refund_of", original.ID, "refunded", refunded)
+		return domain.ErrRefundExceedsOriginal
+	}
+	return nil
+}
+
+// refunded returns the positive sum of the refunds of an expenditure; the caller holds the lock
+func (m *MemoryService) refunded(id uuid.UUID) float64 {
+	return domain.RefundedTotal(m.expenditures.between(time.Time{}, time.Time{}), id)
+}
+
+func (m *MemoryService) AddExpenditures(expenditures []*domain.Expenditure) error {
+	m.logger.Debug("Adding expenditures in bulk", "count", len(expenditures))
+
+	m.Lock()
+	defer m.Unlock()
+
+	seen := make(map[string]bool, len(expenditures))
+	for _, expenditure := range expenditures {
+		id := expenditure.ID.String()
+		if _, exists := m.expenditures.get(id); exists || seen[id] {
+			m.logger.Warn("Expenditure already exists", "id", expenditure.ID)
+			return domain.ErrExpenditureAlreadyExists
+		}
+		seen[id] = true
+	}
+
+	m.expenditures.putAll(cloneExpenditures(expenditures))
+	for _, expenditure := range expenditures {
+		m.writeOutbox(domain.OutboxExpenditureCreated, expenditure)
+	}
+	m.logger.Info("Expenditures added successfully", "count", len(expenditures), "total_count", m.expenditures.len())
 	return nil
 }
 
@@ -81,7 +165,7 @@ func (m *MemoryService) GetExpenditureByID(id string) (*domain.Expenditure, erro
 	m.RLock()
 	defer m.RUnlock()
 
-	expenditure, exists := m.Expenditures[id]
+	expenditure, exists := m.expenditures.get(id)
 	if !exists {
 		m.logger.Warn("Expenditure not found", "id", id)
 		return nil, domain.ErrExpenditureNotFound
@@ -92,7 +176,7 @@ func (m *MemoryService) GetExpenditureByID(id string) (*domain.Expenditure, erro
 		"amount", expenditure.Amount,
 		"date", expenditure.Date,
 		"category_id", expenditure.CategoryId)
-	return expenditure, nil
+	return expenditure.Clone(), nil
 }
 
 func (m *MemoryService) GetAllExpenditures() ([]*domain.Expenditure, error) {
@@ -101,15 +185,36 @@ func (m *MemoryService) GetAllExpenditures() ([]*domain.Expenditure, error) {
 	m.RLock()
 	defer m.RUnlock()
 
-	expenditures := make([]*domain.Expenditure, 0, len(m.Expenditures))
-	for _, expenditure := range m.Expenditures {
-		expenditures = append(expenditures, expenditure)
-	}
+	expenditures := cloneExpenditures(m.expenditures.between(time.Time{}, time.Time{}))
 
 	m.logger.Info("Retrieved all expenditures", "count", len(expenditures))
 	return expenditures, nil
 }
 
+// GetExpenditurePage reads a page from the date index, or from the category index when
+// filtering by category
+func (m *MemoryService) GetExpenditurePage(query domain.ExpenditurePageQuery) ([]*domain.Expenditure, error) {
+	m.logger.Debug("Getting expenditure page", "from", query.From, "to", query.To, "category_id", query.CategoryId, "limit", query.Limit)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	expenditures := cloneExpenditures(m.expenditures.page(query))
+
+	m.logger.Info("Retrieved expenditure page", "count", len(expenditures))
+	return expenditures, nil
+}
+
+// CountExpenditures counts the expenditures dated within [from, to) on the date index
+func (m *MemoryService) CountExpenditures(from, to time.Time) (int, error) {
+	m.logger.Debug("Counting expenditures", "from", from, "to", to)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	return len(m.expenditures.between(from, to)), nil
+}
+
 func (m *MemoryService) UpdateExpenditure(expenditure *domain.Expenditure) error {
 	m.logger.Debug("Updating expenditure", "id", expenditure.ID,
 		"description", expenditure.Description, "amount",
@@ -121,12 +226,17 @@ func (m *MemoryService) UpdateExpenditure(expenditure *domain.Expenditure) error
 	defer m.Unlock()
 
 	id := expenditure.ID.String()
-	if _, exists := m.Expenditures[id]; !exists {
+	if _, exists := m.expenditures.get(id); !exists {
 		m.logger.Warn("Expenditure not found for update", "id", id)
 		return domain.ErrExpenditureNotFound
 	}
+	if refunded := m.refunded(expenditure.ID); !domain.RefundsWithin(expenditure.Amount, refunded) {
+		m.logger.Warn("Expenditure amount below its refunds", "id", id, "amount", expenditure.Amount, "refunded", refunded)
+		return domain.ErrAmountBelowRefunded
+	}
 
-	m.Expenditures[id] = expenditure
+	m.expenditures.put(expenditure.Clone())
+	m.writeOutbox(domain.OutboxExpenditureUpdated, expenditure)
 	m.logger.Info("Expenditure updated successfully", "id", id)
 	return nil
 }
@@ -137,12 +247,125 @@ func (m *MemoryService) DeleteExpenditure(id string) error {
 	m.Lock()
 	defer m.Unlock()
 
-	if _, exists := m.Expenditures[id]; !exists {
+	if parsed, err := uuid.Parse(id); err == nil && m.refunded(parsed) != 0 {
+		m.logger.Warn("Expenditure with refunds cannot be deleted", "id", id)
+		return domain.ErrExpenditureHasRefunds
+	}
+
+	expenditure, exists := m.expenditures.remove(id)
+	if !exists {
 		m.logger.Warn("Expenditure not found for deletion", "id", id)
 		return domain.ErrExpenditureNotFound
 	}
 
-	delete(m.Expenditures, id)
-	m.logger.Info("Expenditure deleted successfully", "id", id, "remaining_count", len(m.Expenditures))
+	m.writeOutbox(domain.OutboxExpenditureDeleted, expenditure)
+	m.logger.Info("Expenditure deleted successfully", "id", id, "remaining_count", m.expenditures.len())
 	return nil
 }
+
+func (m *MemoryService) AddCategory(category *domain.Category) error {
+	m.logger.Debug("Adding category", "id", category.ID, "name", category.Name)
+
+	m.Lock()
+	defer m.Unlock()
+
+	for id, existing := range m.Categories {
+		if id == category.ID.String() || strings.EqualFold(existing.Name, category.Name) {
+			m.logger.Warn("Category already exists", "id", category.ID, "name", category.Name)
+			return domain.ErrCategoryAlreadyExists
+		}
+	}
+
+	m.Categories[category.ID.String()] = copyOf(category)
+	m.logger.Info("Category added successfully", "id", category.ID, "total_count", len(m.Categories))
+	return nil
+}
+
+func (m *MemoryService) GetCategoryByID(id string) (*domain.Category, error) {
+	m.logger.Debug("Getting category by ID", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	category, exists := m.Categories[id]
+	if !exists {
+		m.logger.Warn("Category not found", "id", id)
+		return nil, domain.ErrCategoryNotFound
+	}
+
+	return copyOf(category), nil
+}
+
+func (m *MemoryService) GetAllCategories() ([]*domain.Category, error) {
+	m.logger.Debug("Getting all categories")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	categories := make([]*domain.Category, 0, len(m.Categories))
+	for _, category := range m.Categories {
+		categories = append(categories, copyOf(category))
+	}
+
+	m.logger.Info("Retrieved all categories", "count", len(categories))
+	return categories, nil
+}
+
+func (m *MemoryService) UpdateCategory(category *domain.Category) error {
+	m.logger.Debug("Updating category", "id", category.ID, "name", category.Name)
+
+	m.Lock()
+	defer m.Unlock()
+
+	id := category.ID.String()
+	if _, exists := m.Categories[id]; !exists {
+		m.logger.Warn("Category not found for update", "id", id)
+		return domain.ErrCategoryNotFound
+	}
+
+	for otherID, other := range m.Categories {
+		if otherID != id && strings.EqualFold(other.Name, category.Name) {
+			m.logger.Warn("Category name already taken", "id", id, "name", category.Name)
+			return domain.ErrCategoryAlreadyExists
+		}
+	}
+
+	m.Categories[id] = copyOf(category)
+	m.logger.Info("Category updated successfully", "id", id)
+	return nil
+}
+
+func (m *MemoryService) ReorderCategories(ids []uuid.UUID) error {
+	m.logger.Debug("Reordering categories", "count", len(ids))
+
+	m.Lock()
+	defer m.Unlock()
+
+	categories := make([]*domain.Category, 0, len(m.Categories))
+	for _, category := range m.Categories {
+		categories = append(categories, category)
+	}
+
+	if err := domain.OrderCategories(categories, ids); err != nil {
+		m.logger.Warn("Invalid category order", "error", err)
+		return err
+	}
+
+	m.logger.Info("Categories reordered successfully", "count", len(categories))
+	return nil
+}
+
+func cloneExpenditures(expenditures []*domain.Expenditure) []*domain.Expenditure {
+	clones := make([]*domain.Expenditure, len(expenditures))
+	for i, expenditure := range expenditures {
+		clones[i] = expenditure.Clone()
+	}
+	return clones
+}
+
+// copyOf returns a copy of a domain object without slices or pointers, which a shallow copy
+// keeps apart from the original
+func copyOf[T any](v *T) *T {
+	copied := *v
+	return &copied
+}
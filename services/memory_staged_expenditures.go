@@ -0,0 +1,77 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"sort"
+)
+
+func (m *MemoryService) AddStagedExpenditure(staged *domain.StagedExpenditure) error {
+	m.logger.Debug("Adding staged expenditure", "id", staged.ID,
+		"source", staged.Source,
+		"reference", staged.Reference,
+		"amount", staged.Amount)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.StagedExpenditures[staged.ID.String()]; exists {
+		m.logger.Warn("Staged expenditure already exists", "id", staged.ID)
+		return domain.ErrStagedExpenditureAlreadyExists
+	}
+
+	m.StagedExpenditures[staged.ID.String()] = copyOf(staged)
+	m.logger.Info("Staged expenditure added successfully", "id", staged.ID, "total_count", len(m.StagedExpenditures))
+	return nil
+}
+
+func (m *MemoryService) GetStagedExpenditureByID(id string) (*domain.StagedExpenditure, error) {
+	m.logger.Debug("Getting staged expenditure by ID", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	staged, exists := m.StagedExpenditures[id]
+	if !exists {
+		m.logger.Warn("Staged expenditure not found", "id", id)
+		return nil, domain.ErrStagedExpenditureNotFound
+	}
+
+	return copyOf(staged), nil
+}
+
+func (m *MemoryService) GetAllStagedExpenditures() ([]*domain.StagedExpenditure, error) {
+	m.logger.Debug("Getting all staged expenditures")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	staged := make([]*domain.StagedExpenditure, 0, len(m.StagedExpenditures))
+	for _, s := range m.StagedExpenditures {
+		staged = append(staged, copyOf(s))
+	}
+
+	// Oldest first, so the review queue reads in arrival order
+	sort.Slice(staged, func(i, j int) bool {
+		return staged[i].CreatedAt.Before(staged[j].CreatedAt)
+	})
+
+	m.logger.Info("Retrieved all staged expenditures", "count", len(staged))
+	return staged, nil
+}
+
+func (m *MemoryService) UpdateStagedExpenditure(staged *domain.StagedExpenditure) error {
+	m.logger.Debug("Updating staged expenditure", "id", staged.ID, "status", staged.Status)
+
+	m.Lock()
+	defer m.Unlock()
+
+	id := staged.ID.String()
+	if _, exists := m.StagedExpenditures[id]; !exists {
+		m.logger.Warn("Staged expenditure not found for update", "id", id)
+		return domain.ErrStagedExpenditureNotFound
+	}
+
+	m.StagedExpenditures[id] = copyOf(staged)
+	m.logger.Info("Staged expenditure updated successfully", "id", id, "status", staged.Status)
+	return nil
+}
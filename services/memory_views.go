@@ -0,0 +1,126 @@
+package services
+
+import (
+	"go-expense-tracker/domain"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (m *MemoryService) AddView(view *domain.View) error {
+	m.logger.Debug("Adding view", "id", view.ID, "name", view.Name)
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.Views[view.ID.String()] = view.Clone()
+	m.logger.Info("View added successfully", "id", view.ID, "total_count", len(m.Views))
+	return nil
+}
+
+func (m *MemoryService) GetViewByID(id string) (*domain.View, error) {
+	m.logger.Debug("Getting view by ID", "id", id)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	view, exists := m.Views[id]
+	if !exists {
+		m.logger.Warn("View not found", "id", id)
+		return nil, domain.ErrViewNotFound
+	}
+
+	return view.Clone(), nil
+}
+
+func (m *MemoryService) GetAllViews() ([]*domain.View, error) {
+	m.logger.Debug("Getting all views")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	views := make([]*domain.View, 0, len(m.Views))
+	for _, view := range m.Views {
+		views = append(views, view.Clone())
+	}
+
+	sort.Slice(views, func(i, j int) bool {
+		return views[i].Name < views[j].Name
+	})
+
+	m.logger.Info("Retrieved all views", "count", len(views))
+	return views, nil
+}
+
+func (m *MemoryService) UpdateView(view *domain.View) error {
+	m.logger.Debug("Updating view", "id", view.ID, "name", view.Name)
+
+	m.Lock()
+	defer m.Unlock()
+
+	id := view.ID.String()
+	if _, exists := m.Views[id]; !exists {
+		m.logger.Warn("View not found for update", "id", id)
+		return domain.ErrViewNotFound
+	}
+
+	m.Views[id] = view.Clone()
+	m.logger.Info("View updated successfully", "id", id)
+	return nil
+}
+
+func (m *MemoryService) DeleteView(id string) error {
+	m.logger.Debug("Deleting view", "id", id)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.Views[id]; !exists {
+		m.logger.Warn("View not found for deletion", "id", id)
+		return domain.ErrViewNotFound
+	}
+
+	delete(m.Views, id)
+	m.logger.Info("View deleted successfully", "id", id, "remaining_count", len(m.Views))
+	return nil
+}
+
+func (m *MemoryService) PinExpenditure(id uuid.UUID) error {
+	m.logger.Debug("Pinning expenditure", "id", id)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.Pins[id]; !exists {
+		m.Pins[id] = time.Now()
+	}
+	return nil
+}
+
+func (m *MemoryService) UnpinExpenditure(id uuid.UUID) error {
+	m.logger.Debug("Unpinning expenditure", "id", id)
+
+	m.Lock()
+	defer m.Unlock()
+
+	delete(m.Pins, id)
+	return nil
+}
+
+func (m *MemoryService) GetPinnedExpenditureIDs() ([]uuid.UUID, error) {
+	m.logger.Debug("Getting pinned expenditures")
+
+	m.RLock()
+	defer m.RUnlock()
+
+	ids := make([]uuid.UUID, 0, len(m.Pins))
+	for id := range m.Pins {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return m.Pins[ids[i]].After(m.Pins[ids[j]])
+	})
+	return ids, nil
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+
+	"github.com/google/uuid"
+)
+
+// seedMySQLCategories inserts the default categories that do not exist yet
+func seedMySQLCategories(db *sql.DB) error {
+	for name, color := range domain.DefaultCategories {
+		_, err := db.Exec(
+			"INSERT IGNORE INTO categories (id, name, color, deductible, icon) VALUES (?, ?, ?, FALSE, ?)",
+			uuid.New(), name, color, domain.DefaultCategoryIcons[name],
+		)
+		if err != nil {
+			return fmt.Errorf("error seeding category %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// AddCategory adds a new category; names are unique
+func (s *MySQLService) AddCategory(category *domain.Category) error {
+	s.logger.Debug("Adding category to database", "id", category.ID, "name", category.Name)
+
+	taken, err := s.categoryNameTaken(category)
+	if err != nil {
+		return err
+	}
+	if taken {
+		s.logger.Warn("Category already exists", "id", category.ID, "name", category.Name)
+		return domain.ErrCategoryAlreadyExists
+	}
+
+	_, err = s.db.Exec(
+		"INSERT INTO categories ("+categoryColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		category.ID, category.Name, category.Color, category.Deductible, nullUUID(category.ParentID),
+		category.Icon, category.SortOrder, category.Active, category.TransactionLimit, category.LimitMode,
+	)
+	if err != nil {
+		s.logger.Error("Error inserting category", "error", err, "id", category.ID)
+		return fmt.Errorf("error inserting category: %w", err)
+	}
+
+	s.logger.Info("Category added successfully", "id", category.ID)
+	return nil
+}
+
+// GetCategoryByID retrieves a category by its ID
+func (s *MySQLService) GetCategoryByID(id string) (*domain.Category, error) {
+	s.logger.Debug("Getting category by ID", "id", id)
+
+	categoryID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	category, err := scanCategory(s.db.QueryRow("SELECT "+categoryColumns+" FROM categories WHERE id = ?", categoryID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Category not found", "id", id)
+			return nil, domain.ErrCategoryNotFound
+		}
+		s.logger.Error("Error querying category", "error", err, "id", id)
+		return nil, fmt.Errorf("error querying category: %w", err)
+	}
+
+	return category, nil
+}
+
+// GetAllCategories retrieves all categories ordered by their sort order and name
+func (s *MySQLService) GetAllCategories() ([]*domain.Category, error) {
+	s.logger.Debug("Getting all categories")
+
+	categories, err := queryMySQLCategories(s.db, "SELECT "+categoryColumns+" FROM categories ORDER BY sort_order, name")
+	if err != nil {
+		s.logger.Error("Error querying all categories", "error", err)
+		return nil, err
+	}
+
+	s.logger.Info("Retrieved all categories", "count", len(categories))
+	return categories, nil
+}
+
+// UpdateCategory updates an existing category
+func (s *MySQLService) UpdateCategory(category *domain.Category) error {
+	s.logger.Debug("Updating category", "id", category.ID, "name", category.Name)
+
+	if _, err := s.GetCategoryByID(category.ID.String()); err != nil {
+		return err
+	}
+
+	taken, err := s.categoryNameTaken(category)
+	if err != nil {
+		return err
+	}
+	if taken {
+		s.logger.Warn("Category name already taken", "id", category.ID, "name", category.Name)
+		return domain.ErrCategoryAlreadyExists
+	}
+
+	_, err = s.db.Exec(
+		`UPDATE categories SET name = ?, color = ?, deductible = ?, parent_id = ?, icon = ?, sort_order = ?,
+			active = ?, transaction_limit = ?, limit_mode = ? WHERE id = ?`,
+		category.Name, category.Color, category.Deductible, nullUUID(category.ParentID), category.Icon, category.SortOrder,
+		category.Active, category.TransactionLimit, category.LimitMode, category.ID,
+	)
+	if err != nil {
+		s.logger.Error("Error updating category", "error", err, "id", category.ID)
+		return fmt.Errorf("error updating category: %w", err)
+	}
+
+	s.logger.Info("Category updated successfully", "id", category.ID)
+	return nil
+}
+
+// ReorderCategories renumbers the sort order of all categories in one transaction
+func (s *MySQLService) ReorderCategories(ids []uuid.UUID) error {
+	s.logger.Debug("Reordering categories", "count", len(ids))
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("Error starting category reorder transaction", "error", err)
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	categories, err := queryMySQLCategories(tx, "SELECT "+categoryColumns+" FROM categories FOR UPDATE")
+	if err != nil {
+		s.logger.Error("Error locking categories", "error", err)
+		return err
+	}
+
+	if err := domain.OrderCategories(categories, ids); err != nil {
+		s.logger.Warn("Invalid category order", "error", err)
+		return err
+	}
+
+	for _, category := range categories {
+		if _, err := tx.Exec("UPDATE categories SET sort_order = ? WHERE id = ?", category.SortOrder, category.ID); err != nil {
+			s.logger.Error("Error updating category sort order", "error", err, "id", category.ID)
+			return fmt.Errorf("error updating category sort order: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.logger.Error("Error committing category reorder", "error", err)
+		return fmt.Errorf("error committing category reorder: %w", err)
+	}
+
+	s.logger.Info("Categories reordered successfully", "count", len(categories))
+	return nil
+}
+
+// MergeCategory moves the expenditures and subcategories of source over to target and deletes
+// source in one transaction. A dry run performs the same updates to count them and rolls them back
+func (s *MySQLService) MergeCategory(source, target uuid.UUID, dryRun bool) (*domain.CategoryMerge, error) {
+	s.logger.Debug("Merging category", "source_id", source, "target_id", target, "dry_run", dryRun)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("Error starting category merge transaction", "error", err)
+		return nil, fmt.Errorf("error starting category merge transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the categories so the hierarchy cannot change while it is checked
+	categories, err := queryMySQLCategories(tx, "SELECT "+categoryColumns+" FROM categories FOR UPDATE")
+	if err != nil {
+		s.logger.Error("Error querying categories for merge", "error", err)
+		return nil, err
+	}
+
+	found := false
+	for _, category := range categories {
+		found = found || category.ID == source
+	}
+	if !found {
+		s.logger.Warn("Category not found for merge", "id", source)
+		return nil, domain.ErrCategoryNotFound
+	}
+	if err := domain.CheckCategoryMerge(categories, source, target); err != nil {
+		s.logger.Warn("Invalid category merge", "source_id", source, "target_id", target, "error", err)
+		return nil, err
+	}
+
+	merge := &domain.CategoryMerge{SourceID: source, TargetID: target, DryRun: dryRun}
+	updates := []struct {
+		query string
+		count *int
+	}{
+		{"UPDATE expenditures SET category_id = ? WHERE category_id = ?", &merge.Expenditures},
+		{"UPDATE categories SET parent_id = ? WHERE parent_id = ?", &merge.Subcategories},
+	}
+	for _, update := range updates {
+		*update.count, err = execCount(tx, update.query, target, source)
+		if err != nil {
+			s.logger.Error("Error reassigning category", "error", err, "source_id", source)
+			return nil, fmt.Errorf("error reassigning category: %w", err)
+		}
+	}
+
+	if _, err = tx.Exec("DELETE FROM categories WHERE id = ?", source); err != nil {
+		s.logger.Error("Error deleting merged category", "error", err, "id", source)
+		return nil, fmt.Errorf("error deleting merged category: %w", err)
+	}
+
+	if dryRun {
+		s.logger.Info("Category merge dry run", "source_id", source, "target_id", target, "expenditures", merge.Expenditures)
+		return merge, nil
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.logger.Error("Error committing category merge", "error", err)
+		return nil, fmt.Errorf("error committing category merge: %w", err)
+	}
+
+	s.logger.Info("Category merged successfully", "source_id", source, "target_id", target, "expenditures", merge.Expenditures)
+	return merge, nil
+}
+
+// categoryNameTaken tells whether another category has the name. MySQL compares names ignoring
+// case, like the unique index on them
+func (s *MySQLService) categoryNameTaken(category *domain.Category) (bool, error) {
+	var taken bool
+	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM categories WHERE name = ? AND id <> ?)", category.Name, category.ID).Scan(&taken)
+	if err != nil {
+		s.logger.Error("Error checking category name", "error", err, "name", category.Name)
+		return false, fmt.Errorf("error checking category name: %w", err)
+	}
+	return taken, nil
+}
+
+// queryMySQLCategories runs a query selecting categoryColumns on the database or a transaction
+func queryMySQLCategories(q interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}, query string, args ...interface{}) ([]*domain.Category, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []*domain.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning category row: %w", err)
+		}
+		categories = append(categories, category)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating category rows: %w", err)
+	}
+	return categories, nil
+}
@@ -0,0 +1,6 @@
+//go:build mysql
+
+package services
+
+// The MySQL driver, pinned in go.mod, is only linked into binaries built with the mysql tag
+import _ "github.com/go-sql-driver/mysql"
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// mysqlMigrations are the schema changes of the MySQL storage, applied in order and each once.
+// MySQL cannot add columns only when they are missing, so changes are versioned instead of
+// written to be repeatable; append new ones, never edit applied ones
+var mysqlMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS expenditures (
+		id CHAR(36) PRIMARY KEY,
+		description TEXT NOT NULL,
+		amount DECIMAL(10, 2) NOT NULL,
+		date DATETIME(6) NOT NULL,
+		category_id CHAR(36) NULL,
+		tags JSON NOT NULL,
+		quantity DECIMAL(12, 4) NOT NULL DEFAULT 0,
+		unit_price DECIMAL(12, 4) NOT NULL DEFAULT 0,
+		unit VARCHAR(32) NOT NULL DEFAULT '',
+		tax_rate DECIMAL(5, 2) NOT NULL DEFAULT 0,
+		tax_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+		merchant_id CHAR(36) NULL,
+		latitude DOUBLE NULL,
+		longitude DOUBLE NULL,
+		place_name VARCHAR(255) NOT NULL DEFAULT '',
+		city VARCHAR(255) NOT NULL DEFAULT '',
+		INDEX expenditures_date (date),
+		INDEX expenditures_category_id (category_id)
+	) DEFAULT CHARSET = utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS categories (
+		id CHAR(36) PRIMARY KEY,
+		name VARCHAR(255) NOT NULL UNIQUE,
+		color VARCHAR(16) NOT NULL,
+		deductible BOOLEAN NOT NULL DEFAULT FALSE,
+		parent_id CHAR(36) NULL,
+		icon VARCHAR(64) NOT NULL DEFAULT '',
+		sort_order INT NOT NULL DEFAULT 0,
+		active BOOLEAN NOT NULL DEFAULT TRUE,
+		transaction_limit DECIMAL(10, 2) NOT NULL DEFAULT 0,
+		limit_mode VARCHAR(16) NOT NULL DEFAULT ''
+	) DEFAULT CHARSET = utf8mb4`,
+	`ALTER TABLE expenditures ADD COLUMN planned BOOLEAN NOT NULL DEFAULT FALSE`,
+	`ALTER TABLE expenditures ADD COLUMN refund_of CHAR(36) NULL`,
+	`ALTER TABLE expenditures ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'cleared'`,
+	`ALTER TABLE expenditures ADD COLUMN account_id CHAR(36) NULL, ADD COLUMN reconciled BOOLEAN NOT NULL DEFAULT FALSE`,
+	`ALTER TABLE expenditures ADD COLUMN original_amount DECIMAL(12, 2) NOT NULL DEFAULT 0, ADD COLUMN original_currency CHAR(3) NOT NULL DEFAULT ''`,
+	`ALTER TABLE expenditures ADD COLUMN created_by CHAR(36) NULL`,
+	// MySQL validates a foreign key against the rows already there, so refunds of deleted
+	// expenditures become plain credits first
+	`UPDATE expenditures SET refund_of = NULL
+		WHERE refund_of IS NOT NULL AND refund_of NOT IN (SELECT id FROM (SELECT id FROM expenditures) AS originals)`,
+	`ALTER TABLE expenditures ADD CONSTRAINT expenditures_refund_of_fkey FOREIGN KEY (refund_of) REFERENCES expenditures (id)`,
+}
+
+// migrateMySQL applies the migrations not applied yet, recording each in schema_migrations.
+// DDL statements commit implicitly in MySQL, so every migration is recorded right after it ran
+func migrateMySQL(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INT PRIMARY KEY,
+		applied_at DATETIME(6) NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var applied int
+	if err := db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&applied); err != nil {
+		return fmt.Errorf("failed to read the schema version: %w", err)
+	}
+
+	for i := applied; i < len(mysqlMigrations); i++ {
+		if _, err := db.Exec(mysqlMigrations[i]); err != nil {
+			return fmt.Errorf("failed to apply migration %d: %w", i+1, err)
+		}
+		if _, err := db.Exec("INSERT INTO schema_migrations (version, applied_at) VALUES (?, UTC_TIMESTAMP(6))", i+1); err != nil {
+			return fmt.Errorf("failed to record migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,423 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MySQLService implements the ExpenditureRepository and CategoryRepository interfaces using
+// MySQL or MariaDB. Other optional repositories are not supported yet, so the features built on
+// them are turned off with this storage
+type MySQLService struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+// NewMySQLService connects to MySQL with a DSN in the format of the MySQL driver, such as
+// user:password@tcp(localhost:3306)/expense_tracker, and migrates the schema. The driver is
+// only linked into binaries built with the mysql build tag
+func NewMySQLService(dsn string, logger *slog.Logger) (*MySQLService, error) {
+	if !slices.Contains(sql.Drivers(), "mysql") {
+		return nil, errors.New("built without MySQL support, build with -tags mysql")
+	}
+
+	// Dates are scanned into time.Time only with parseTime
+	if !strings.Contains(dsn, "parseTime=") {
+		separator := "?"
+		if strings.Contains(dsn, "?") {
+			separator = "&"
+		}
+		dsn += separator + "parseTime=true"
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	if err = migrateMySQL(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err = seedMySQLCategories(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &MySQLService{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+// Close closes the database connection
+func (s *MySQLService) Close() error {
+	return s.db.Close()
+}
+
+// AddExpenditure adds a new expenditure to the database
+func (s *MySQLService) AddExpenditure(expenditure *domain.Expenditure) error {
+	s.logger.Debug("Adding expenditure to database", "id", expenditure.ID, "description", expenditure.Description, "amount", expenditure.Amount)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("Error starting transaction", "error", err, "id", expenditure.ID)
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM expenditures WHERE id = ?)", expenditure.ID).Scan(&exists)
+	if err != nil {
+		s.logger.Error("Error checking if expenditure exists", "error", err, "id", expenditure.ID)
+		return fmt.Errorf("error checking if expenditure exists: %w", err)
+	}
+	if exists {
+		s.logger.Warn("Expenditure already exists", "id", expenditure.ID)
+		return domain.ErrExpenditureAlreadyExists
+	}
+
+	// A refund locks its original, so concurrent refunds are summed one after the other
+	if expenditure.IsRefund() {
+		if err := checkRefund(tx, mysqlRefundQueries, expenditure); err != nil {
+			s.logger.Warn("Refund rejected", "error", err, "id", expenditure.ID, "refund_of", expenditure.RefundOf)
+			return err
+		}
+	}
+
+	values, err := mysqlExpenditureValues(expenditure)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec("INSERT INTO expenditures ("+expenditureColumns+") VALUES ("+mysqlPlaceholders(len(values))+")", values...); err != nil {
+		s.logger.Error("Error inserting expenditure", "error", err, "id", expenditure.ID)
+		return fmt.Errorf("error inserting expenditure: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("Error committing expenditure", "error", err, "id", expenditure.ID)
+		return fmt.Errorf("error committing expenditure: %w", err)
+	}
+
+	s.logger.Info("Expenditure added successfully", "id", expenditure.ID)
+	return nil
+}
+
+// AddExpenditures adds the expenditures in one transaction, none of them when one exists already
+func (s *MySQLService) AddExpenditures(expenditures []*domain.Expenditure) error {
+	s.logger.Debug("Adding expenditures in bulk to database", "count", len(expenditures))
+
+	if len(expenditures) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("Error starting bulk insert transaction", "error", err)
+		return fmt.Errorf("error starting bulk insert transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	seen := make(map[uuid.UUID]bool, len(expenditures))
+	for _, expenditure := range expenditures {
+		var exists bool
+		err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM expenditures WHERE id = ?)", expenditure.ID).Scan(&exists)
+		if err != nil {
+			s.logger.Error("Error checking if expenditure exists", "error", err, "id", expenditure.ID)
+			return fmt.Errorf("error checking if expenditure exists: %w", err)
+		}
+		if exists || seen[expenditure.ID] {
+			s.logger.Warn("Expenditure already exists in bulk insert", "id", expenditure.ID)
+			return domain.ErrExpenditureAlreadyExists
+		}
+		seen[expenditure.ID] = true
+
+		values, err := mysqlExpenditureValues(expenditure)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec("INSERT INTO expenditures ("+expenditureColumns+") VALUES ("+mysqlPlaceholders(len(values))+")", values...); err != nil {
+			s.logger.Error("Error inserting expenditure", "error", err, "id", expenditure.ID)
+			return fmt.Errorf("error inserting expenditure: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.logger.Error("Error committing bulk insert", "error", err)
+		return fmt.Errorf("error committing bulk insert: %w", err)
+	}
+
+	s.logger.Info("Expenditures added successfully", "count", len(expenditures))
+	return nil
+}
+
+// GetExpenditureByID retrieves an expenditure by its ID
+func (s *MySQLService) GetExpenditureByID(id string) (*domain.Expenditure, error) {
+	s.logger.Debug("Getting expenditure by ID", "id", id)
+
+	expenditureID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	expenditure, err := scanMySQLExpenditure(s.db.QueryRow("SELECT "+expenditureColumns+" FROM expenditures WHERE id = ?", expenditureID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn("Expenditure not found", "id", id)
+			return nil, domain.ErrExpenditureNotFound
+		}
+		s.logger.Error("Error querying expenditure", "error", err, "id", id)
+		return nil, fmt.Errorf("error querying expenditure: %w", err)
+	}
+
+	return expenditure, nil
+}
+
+// GetAllExpenditures retrieves all expenditures from the database
+func (s *MySQLService) GetAllExpenditures() ([]*domain.Expenditure, error) {
+	s.logger.Debug("Getting all expenditures")
+
+	var expenditures []*domain.Expenditure
+	err := s.StreamExpenditures(func(expenditure *domain.Expenditure) error {
+		expenditures = append(expenditures, expenditure)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Retrieved all expenditures", "count", len(expenditures))
+	return expenditures, nil
+}
+
+// CountExpenditures counts the expenditures dated within [from, to); zero bounds are open
+func (s *MySQLService) CountExpenditures(from, to time.Time) (int, error) {
+	s.logger.Debug("Counting expenditures", "from", from, "to", to)
+
+	query := "SELECT COUNT(*) FROM expenditures WHERE TRUE"
+	var args []interface{}
+	if !from.IsZero() {
+		query += " AND date >= ?"
+		args = append(args, from.UTC())
+	}
+	if !to.IsZero() {
+		query += " AND date < ?"
+		args = append(args, to.UTC())
+	}
+
+	var count int
+	if err := s.db.QueryRow(query, args...).Scan(&count); err != nil {
+		s.logger.Error("Error counting expenditures", "error", err)
+		return 0, fmt.Errorf("error counting expenditures: %w", err)
+	}
+	return count, nil
+}
+
+// StreamExpenditures passes the expenditures to fn one row at a time straight from the cursor
+func (s *MySQLService) StreamExpenditures(fn func(*domain.Expenditure) error) error {
+	s.logger.Debug("Streaming all expenditures")
+
+	rows, err := s.db.Query("SELECT " + expenditureColumns + " FROM expenditures")
+	if err != nil {
+		s.logger.Error("Error querying all expenditures", "error", err)
+		return fmt.Errorf("error querying all expenditures: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		expenditure, err := scanMySQLExpenditure(rows)
+		if err != nil {
+			s.logger.Error("Error scanning expenditure row", "error", err)
+			return fmt.Errorf("error scanning expenditure row: %w", err)
+		}
+		if err := fn(expenditure); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Error("Error iterating expenditure rows", "error", err)
+		return fmt.Errorf("error iterating expenditure rows: %w", err)
+	}
+	return nil
+}
+
+// UpdateExpenditure updates an existing expenditure
+func (s *MySQLService) UpdateExpenditure(expenditure *domain.Expenditure) error {
+	s.logger.Debug("Updating expenditure", "id", expenditure.ID, "description", expenditure.Description, "amount", expenditure.Amount)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("Error starting transaction", "error", err, "id", expenditure.ID)
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the expenditure, so no refund is recorded against it while its amount changes
+	err = checkRefunded(tx, mysqlRefundQueries, expenditure)
+	switch {
+	case errors.Is(err, domain.ErrExpenditureNotFound):
+		s.logger.Warn("Expenditure not found for update", "id", expenditure.ID)
+		return err
+	case errors.Is(err, domain.ErrAmountBelowRefunded):
+		s.logger.Warn("Expenditure amount below its refunds", "id", expenditure.ID, "amount", expenditure.Amount)
+		return err
+	case err != nil:
+		s.logger.Error("Error checking expenditure refunds", "error", err, "id", expenditure.ID)
+		return err
+	}
+
+	// The values are in column order with the ID first; the update sets the others by it
+	values, err := mysqlExpenditureValues(expenditure)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		`UPDATE expenditures SET description = ?, amount = ?, date = ?, category_id = ?, tags = ?,
+			quantity = ?, unit_price = ?, unit = ?, tax_rate = ?, tax_amount = ?,
+			merchant_id = ?, latitude = ?, longitude = ?, place_name = ?, city = ?, planned = ?, refund_of = ?, status = ?,
+			account_id = ?, reconciled = ?, original_amount = ?, original_currency = ?, created_by = ? WHERE id = ?`,
+		append(values[1:], values[0])...,
+	)
+	if err != nil {
+		s.logger.Error("Error updating expenditure", "error", err, "id", expenditure.ID)
+		return fmt.Errorf("error updating expenditure: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("Error committing expenditure update", "error", err, "id", expenditure.ID)
+		return fmt.Errorf("error committing expenditure update: %w", err)
+	}
+
+	s.logger.Info("Expenditure updated successfully", "id", expenditure.ID)
+	return nil
+}
+
+// DeleteExpenditure deletes an expenditure by its ID
+func (s *MySQLService) DeleteExpenditure(id string) error {
+	s.logger.Debug("Deleting expenditure", "id", id)
+
+	expenditureID, err := uuid.Parse(id)
+	if err != nil {
+		s.logger.Error("Invalid UUID format", "error", err, "id", id)
+		return fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("Error starting transaction", "error", err, "id", id)
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the expenditure, so no refund is recorded against it while it is deleted
+	_, refunded, err := lockRefunded(tx, mysqlRefundQueries, expenditureID)
+	if errors.Is(err, domain.ErrExpenditureNotFound) {
+		s.logger.Warn("Expenditure not found for deletion", "id", id)
+		return err
+	}
+	if err != nil {
+		s.logger.Error("Error checking expenditure refunds", "error", err, "id", id)
+		return err
+	}
+	if refunded != 0 {
+		s.logger.Warn("Expenditure with refunds cannot be deleted", "id", id)
+		return domain.ErrExpenditureHasRefunds
+	}
+
+	if _, err := tx.Exec("DELETE FROM expenditures WHERE id = ?", expenditureID); err != nil {
+		s.logger.Error("Error deleting expenditure", "error", err, "id", id)
+		return fmt.Errorf("error deleting expenditure: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("Error committing expenditure deletion", "error", err, "id", id)
+		return fmt.Errorf("error committing expenditure deletion: %w", err)
+	}
+
+	s.logger.Info("Expenditure deleted successfully", "id", id)
+	return nil
+}
+
+// mysqlExpenditureValues returns the values of expenditureColumns, in order. MySQL has no
+// arrays, so the tags are stored as JSON, and dates are stored in UTC
+func mysqlExpenditureValues(expenditure *domain.Expenditure) ([]interface{}, error) {
+	tags := expenditure.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	encodedTags, err := json.Marshal(tags)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding expenditure tags: %w", err)
+	}
+
+	latitude, longitude, placeName, city := locationColumns(expenditure.Location)
+	return []interface{}{
+		expenditure.ID, expenditure.Description, expenditure.Amount, expenditure.Date.UTC(),
+		nullUUID(expenditure.CategoryId), string(encodedTags),
+		expenditure.Quantity, expenditure.UnitPrice, expenditure.Unit,
+		expenditure.TaxRate, expenditure.TaxAmount, nullUUID(expenditure.MerchantId),
+		latitude, longitude, placeName, city, expenditure.Planned, nullUUID(expenditure.RefundOf),
+		expenditureStatus(expenditure), nullUUID(expenditure.AccountId), expenditure.Reconciled,
+		expenditure.OriginalAmount, expenditure.OriginalCurrency, nullUUID(expenditure.CreatedBy),
+	}, nil
+}
+
+func scanMySQLExpenditure(row rowScanner) (*domain.Expenditure, error) {
+	var expenditure domain.Expenditure
+	var categoryID, merchantID, refundOf, accountID, createdBy uuid.NullUUID
+	var latitude, longitude sql.NullFloat64
+	var placeName, city string
+	var tags []byte
+
+	err := row.Scan(&expenditure.ID, &expenditure.Description, &expenditure.Amount, &expenditure.Date,
+		&categoryID, &tags, &expenditure.Quantity, &expenditure.UnitPrice, &expenditure.Unit,
+		&expenditure.TaxRate, &expenditure.TaxAmount, &merchantID,
+		&latitude, &longitude, &placeName, &city, &expenditure.Planned, &refundOf, &expenditure.Status,
+		&accountID, &expenditure.Reconciled, &expenditure.OriginalAmount, &expenditure.OriginalCurrency, &createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(tags, &expenditure.Tags); err != nil {
+		return nil, fmt.Errorf("error decoding expenditure tags: %w", err)
+	}
+	if expenditure.Tags == nil {
+		expenditure.Tags = []string{}
+	}
+	expenditure.CategoryId = categoryID.UUID
+	expenditure.MerchantId = merchantID.UUID
+	expenditure.RefundOf = refundOf.UUID
+	expenditure.AccountId = accountID.UUID
+	expenditure.CreatedBy = createdBy.UUID
+	if latitude.Valid && longitude.Valid {
+		expenditure.Location = &domain.Location{
+			Latitude:  latitude.Float64,
+			Longitude: longitude.Float64,
+			PlaceName: placeName,
+			City:      city,
+		}
+	}
+	return &expenditure, nil
+}
+
+// mysqlPlaceholders returns n comma separated placeholders; MySQL uses ? where PostgreSQL uses $n
+func mysqlPlaceholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"go-expense-tracker/domain"
+	"go-expense-tracker/interchange"
+	"go-expense-tracker/reports"
+	"log/slog"
+	"math"
+	"math/rand"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"testing/quick"
+	"time"
+)
+
+// Run with: go test ./services -run Property
+// The properties are checked against every storage, PostgreSQL only when BENCHMARK_DB_NAME names
+// a scratch database as for the benchmarks. Generated expenditures are described with
+// propertyDescription and dated in propertyYear, apart from other data of the scratch database
+
+const propertyDescription = "Property test"
+
+var propertyYear = time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// propertyCategories are the categories generated expenditures are spread over
+var propertyCategories = func() []*domain.Category {
+	var categories []*domain.Category
+	for _, name := range []string{"Groceries", "Transport", "Eating out"} {
+		category, _ := domain.NewCategory(name, "#868e96")
+		categories = append(categories, category)
+	}
+	return categories
+}()
+
+// propertyWords make up descriptions, with the characters file formats must escape
+var propertyWords = []string{"coffee", "rent", "Bäckerei", "bus,pass", `"quoted"`, "a;b", "tab\tstop", "50%", "x"}
+
+// propertyExpenditure is a generated expenditure
+type propertyExpenditure struct {
+	Cents    int64
+	Category int
+	Day      int
+	Words    []string
+}
+
+// propertyBatch is a generated set of expenditures, stored in a fresh storage
+type propertyBatch []propertyExpenditure
+
+func (propertyBatch) Generate(r *rand.Rand, size int) reflect.Value {
+	batch := make(propertyBatch, r.Intn(size+1))
+	for i := range batch {
+		words := make([]string, r.Intn(3))
+		for j := range words {
+			words[j] = propertyWords[r.Intn(len(propertyWords))]
+		}
+		// Mostly everyday amounts, now and then one in the millions
+		cents := r.Int63n(10_000) + 1
+		if r.Intn(10) == 0 {
+			cents = r.Int63n(1_000_000_000) + 1
+		}
+		batch[i] = propertyExpenditure{Cents: cents, Category: r.Intn(len(propertyCategories)), Day: r.Intn(365), Words: words}
+	}
+	return reflect.ValueOf(batch)
+}
+
+func (e propertyExpenditure) description() string {
+	return strings.Join(append([]string{propertyDescription}, e.Words...), " ")
+}
+
+func (e propertyExpenditure) expenditure(t *testing.T) *domain.Expenditure {
+	expenditure, err := domain.NewExpenditure(e.description(), float64(e.Cents)/100, propertyYear.AddDate(0, 0, e.Day), propertyCategories[e.Category].ID)
+	if err != nil {
+		t.Fatalf("creating expenditure: %v", err)
+	}
+	return expenditure
+}
+
+// propertyStorage is a storage holding exactly the expenditures of a batch
+type propertyStorage struct {
+	expenditures domain.ExpenditureRepository
+	summaries    domain.SpendingSummaryRepository // Nil when the storage keeps none
+}
+
+// propertyBackends runs check with a function storing a batch in a fresh storage, per backend
+func propertyBackends(t *testing.T, check func(t *testing.T, store func(batch propertyBatch) propertyStorage)) {
+	t.Run("memory", func(t *testing.T) {
+		check(t, func(batch propertyBatch) propertyStorage {
+			service := NewMemoryService(slog.New(slog.DiscardHandler))
+			for _, e := range batch {
+				if err := service.AddExpenditure(e.expenditure(t)); err != nil {
+					t.Fatalf("adding expenditure: %v", err)
+				}
+			}
+			return propertyStorage{expenditures: service}
+		})
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		service := openScratchDB(t)
+		clear := func() {
+			if _, err := service.db.Exec("DELETE FROM expenditures WHERE description LIKE $1", propertyDescription+"%"); err != nil {
+				t.Fatalf("clearing expenditures: %v", err)
+			}
+		}
+		t.Cleanup(clear)
+		check(t, func(batch propertyBatch) propertyStorage {
+			clear()
+			for _, e := range batch {
+				if err := service.AddExpenditure(e.expenditure(t)); err != nil {
+					t.Fatalf("adding expenditure: %v", err)
+				}
+			}
+			return propertyStorage{expenditures: service, summaries: service}
+		})
+	})
+}
+
+func propertyConfig() *quick.Config {
+	return &quick.Config{MaxCount: 50, Rand: rand.New(rand.NewSource(1))}
+}
+
+func cents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// TestPropertyCategoryTotals checks the category report adds up: the totals and counts of the
+// categories sum to those of all expenditures, and each category's to its own expenditures'
+func TestPropertyCategoryTotals(t *testing.T) {
+	propertyBackends(t, func(t *testing.T, store func(batch propertyBatch) propertyStorage) {
+		property := func(batch propertyBatch) bool {
+			storage := store(batch)
+			daily, err := reports.DailySpending(storage.summaries, storage.expenditures, propertyYear, propertyYear.AddDate(1, 0, 0))
+			if err != nil {
+				t.Fatalf("reading daily spending: %v", err)
+			}
+
+			wantTotal := make([]int64, len(propertyCategories))
+			wantCount := make([]int, len(propertyCategories))
+			var overall int64
+			for _, e := range batch {
+				wantTotal[e.Category] += e.Cents
+				wantCount[e.Category]++
+				overall += e.Cents
+			}
+
+			var gotOverall int64
+			var gotCount int
+			for _, spend := range reports.CategorySpendingFromSummaries(daily, propertyCategories) {
+				i := slices.IndexFunc(propertyCategories, func(c *domain.Category) bool { return c.ID == spend.ID })
+				if cents(spend.Total) != wantTotal[i] || spend.Count != wantCount[i] {
+					t.Logf("%s: total %v of %d expenditures, want %d cents of %d", spend.Name, spend.Total, spend.Count, wantTotal[i], wantCount[i])
+					return false
+				}
+				gotOverall += cents(spend.Total)
+				gotCount += spend.Count
+			}
+			if gotOverall != overall || gotCount != len(batch) {
+				t.Logf("categories sum to %d cents of %d expenditures, want %d cents of %d", gotOverall, gotCount, overall, len(batch))
+				return false
+			}
+			return true
+		}
+		if err := quick.Check(property, propertyConfig()); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+// TestPropertyExportRoundTrip checks that expenditures exported to a YNAB register, GnuCash CSV or
+// GnuCash book and imported again keep their descriptions, amounts, dates and categories
+func TestPropertyExportRoundTrip(t *testing.T) {
+	formats := []struct {
+		format interchange.Format
+		read   func(data []byte) ([]interchange.Transaction, int, error)
+	}{
+		{interchange.FormatYNAB, func(data []byte) ([]interchange.Transaction, int, error) {
+			return interchange.ReadYNABRegister(bytes.NewReader(data), "")
+		}},
+		{interchange.FormatGnuCash, func(data []byte) ([]interchange.Transaction, int, error) {
+			return interchange.ReadGnuCashCSV(bytes.NewReader(data), "", "")
+		}},
+		{interchange.FormatGnuCashXML, func(data []byte) ([]interchange.Transaction, int, error) {
+			return interchange.ReadGnuCashXML(bytes.NewReader(data))
+		}},
+	}
+
+	propertyBackends(t, func(t *testing.T, store func(batch propertyBatch) propertyStorage) {
+		for _, f := range formats {
+			t.Run(string(f.format), func(t *testing.T) {
+				property := func(batch propertyBatch) bool {
+					storage := store(batch)
+					stored, err := storage.expenditures.GetAllExpenditures()
+					if err != nil {
+						t.Fatalf("reading expenditures: %v", err)
+					}
+
+					var file bytes.Buffer
+					writer := interchange.NewWriter(&file, f.format, interchange.Options{}, propertyCategories, nil, nil)
+					var want []string
+					for _, expenditure := range stored {
+						if !strings.HasPrefix(expenditure.Description, propertyDescription) {
+							continue
+						}
+						if err := writer.Write(expenditure); err != nil {
+							t.Fatalf("writing %s: %v", f.format, err)
+						}
+						category := propertyCategories[slices.IndexFunc(propertyCategories, func(c *domain.Category) bool { return c.ID == expenditure.CategoryId })]
+						want = append(want, roundTripKey(expenditure.Description, cents(expenditure.Amount), expenditure.Date, []string{category.Name}))
+					}
+					if err := writer.Close(); err != nil {
+						t.Fatalf("closing %s: %v", f.format, err)
+					}
+
+					transactions, skipped, err := f.read(file.Bytes())
+					if err != nil {
+						t.Logf("reading back %s: %v", f.format, err)
+						return false
+					}
+					var got []string
+					for _, tx := range transactions {
+						got = append(got, roundTripKey(tx.Description, cents(tx.Amount), tx.Date, tx.Category))
+					}
+					slices.Sort(want)
+					slices.Sort(got)
+					if skipped != 0 || !slices.Equal(got, want) {
+						t.Logf("read back %d skipped and\n%q\nwant\n%q", skipped, got, want)
+						return false
+					}
+					return true
+				}
+				if err := quick.Check(property, propertyConfig()); err != nil {
+					t.Error(err)
+				}
+			})
+		}
+	})
+}
+
+func roundTripKey(description string, amount int64, date time.Time, category []string) string {
+	return fmt.Sprintf("%s|%d|%s|%s", description, amount, domain.SpendingDay(date).Format("2006-01-02"), strings.Join(category, "/"))
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"fmt"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// benchmarkDescription marks the rows written by the benchmarks, which are removed afterwards
+const benchmarkDescription = "Benchmark expenditure"
+
+// Run with: go test ./services -run '^$' -bench .
+// The PostgreSQL benchmarks only run when BENCHMARK_DB_NAME names a scratch database; the
+// connection otherwise uses the DB_HOST, DB_PORT, DB_USER and DB_PASSWORD variables of the app
+func benchmarkBackends(b *testing.B, run func(b *testing.B, repo domain.ExpenditureRepository)) {
+	b.Run("memory", func(b *testing.B) {
+		run(b, NewMemoryService(slog.New(slog.DiscardHandler)))
+	})
+
+	b.Run("postgres", func(b *testing.B) {
+		run(b, openBenchmarkDB(b))
+	})
+}
+
+func openBenchmarkDB(b *testing.B) *DBService {
+	service := openScratchDB(b)
+	b.Cleanup(func() {
+		service.db.Exec("DELETE FROM expenditures WHERE description = $1", benchmarkDescription)
+	})
+	return service
+}
+
+// openScratchDB connects to the scratch database named by BENCHMARK_DB_NAME, skipping the test or
+// benchmark when it is not set
+func openScratchDB(tb testing.TB) *DBService {
+	name := os.Getenv("BENCHMARK_DB_NAME")
+	if name == "" {
+		tb.Skip("BENCHMARK_DB_NAME is not set")
+	}
+
+	port := 5432
+	if s := os.Getenv("DB_PORT"); s != "" {
+		var err error
+		if port, err = strconv.Atoi(s); err != nil {
+			tb.Fatalf("invalid DB_PORT: %v", err)
+		}
+	}
+
+	service, err := NewDBService(envOr("DB_HOST", "localhost"), port, envOr("DB_USER", "postgres"), envOr("DB_PASSWORD", "postgres"), name, 0, slog.New(slog.DiscardHandler))
+	if err != nil {
+		tb.Fatalf("connecting to scratch database: %v", err)
+	}
+	tb.Cleanup(func() {
+		service.Close()
+	})
+	return service
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func benchmarkExpenditures(n int) []*domain.Expenditure {
+	categoryID := uuid.New()
+	start := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	expenditures := make([]*domain.Expenditure, n)
+	for i := range expenditures {
+		expenditure, err := domain.NewExpenditure(benchmarkDescription, float64(i%500)+0.99, start.Add(time.Duration(i)*time.Minute), categoryID)
+		if err != nil {
+			panic(err)
+		}
+		expenditure.SetTags([]string{"benchmark"})
+		expenditures[i] = expenditure
+	}
+	return expenditures
+}
+
+func BenchmarkAddExpenditure(b *testing.B) {
+	benchmarkBackends(b, func(b *testing.B, repo domain.ExpenditureRepository) {
+		expenditures := benchmarkExpenditures(b.N)
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			if err := repo.AddExpenditure(expenditures[i]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkAddExpendituresBulk(b *testing.B) {
+	for _, size := range []int{100, 10000} {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			benchmarkBackends(b, func(b *testing.B, repo domain.ExpenditureRepository) {
+				batches := make([][]*domain.Expenditure, b.N)
+				for i := range batches {
+					batches[i] = benchmarkExpenditures(size)
+				}
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					if err := domain.AddExpenditures(repo, batches[i]); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkGetAllExpenditures(b *testing.B) {
+	benchmarkBackends(b, func(b *testing.B, repo domain.ExpenditureRepository) {
+		if err := domain.AddExpenditures(repo, benchmarkExpenditures(10000)); err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			if _, err := repo.GetAllExpenditures(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkStreamExpenditures(b *testing.B) {
+	benchmarkBackends(b, func(b *testing.B, repo domain.ExpenditureRepository) {
+		if err := domain.AddExpenditures(repo, benchmarkExpenditures(10000)); err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			err := domain.EachExpenditure(repo, func(*domain.Expenditure) error { return nil })
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkGetExpenditurePage(b *testing.B) {
+	benchmarkBackends(b, func(b *testing.B, repo domain.ExpenditureRepository) {
+		expenditures := benchmarkExpenditures(10000)
+		if err := domain.AddExpenditures(repo, expenditures); err != nil {
+			b.Fatal(err)
+		}
+		query := domain.ExpenditurePageQuery{
+			From:       expenditures[2000].Date,
+			To:         expenditures[8000].Date,
+			CategoryId: expenditures[0].CategoryId,
+			Limit:      100,
+		}
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			if _, err := domain.GetExpenditurePage(repo, query); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkCountExpenditures(b *testing.B) {
+	benchmarkBackends(b, func(b *testing.B, repo domain.ExpenditureRepository) {
+		expenditures := benchmarkExpenditures(10000)
+		if err := domain.AddExpenditures(repo, expenditures); err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			if _, err := domain.CountExpenditures(repo, expenditures[2000].Date, expenditures[8000].Date); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkParallelReadWrite measures contention: goroutines read expenditures by ID and every
+// tenth operation updates one instead. Run with -cpu 1,4,16 to see how it scales
+func BenchmarkParallelReadWrite(b *testing.B) {
+	benchmarkBackends(b, func(b *testing.B, repo domain.ExpenditureRepository) {
+		expenditures := benchmarkExpenditures(10000)
+		if err := domain.AddExpenditures(repo, expenditures); err != nil {
+			b.Fatal(err)
+		}
+		var next atomic.Int64
+		b.ResetTimer()
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				i := next.Add(1)
+				expenditure := expenditures[i%int64(len(expenditures))]
+				if i%10 == 0 {
+					updated := expenditure.Clone()
+					updated.Amount = float64(i%500) + 0.49
+					if err := repo.UpdateExpenditure(updated); err != nil {
+						b.Error(err)
+						return
+					}
+					continue
+				}
+				if _, err := repo.GetExpenditureByID(expenditure.ID.String()); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		})
+	})
+}
+
+// BenchmarkParallelPageAndAdd measures listing pages while other goroutines keep adding
+// expenditures, which moves the secondary indexes under the readers
+func BenchmarkParallelPageAndAdd(b *testing.B) {
+	benchmarkBackends(b, func(b *testing.B, repo domain.ExpenditureRepository) {
+		if err := domain.AddExpenditures(repo, benchmarkExpenditures(10000)); err != nil {
+			b.Fatal(err)
+		}
+		added := benchmarkExpenditures(b.N)
+		var next atomic.Int64
+		b.ResetTimer()
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				i := next.Add(1)
+				if i%4 == 0 {
+					if err := repo.AddExpenditure(added[i%int64(len(added))]); err != nil && err != domain.ErrExpenditureAlreadyExists {
+						b.Error(err)
+						return
+					}
+					continue
+				}
+				if _, err := domain.GetExpenditurePage(repo, domain.ExpenditurePageQuery{Limit: 50}); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		})
+	})
+}
@@ -0,0 +1,63 @@
+// Package storage keeps the registry of storage drivers. Drivers register themselves by name,
+// usually from an init function, and are opened with a DSN whose format is up to the driver.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"go-expense-tracker/domain"
+	"log/slog"
+	"sort"
+	"sync"
+)
+
+var ErrUnknownDriver = errors.New("unknown storage driver")
+
+// Factory opens a storage from a DSN. The returned repository may implement any of the optional
+// repository interfaces, and io.Closer when it holds resources to release
+type Factory func(dsn string, logger *slog.Logger) (domain.ExpenditureRepository, error)
+
+var (
+	mu      sync.RWMutex
+	drivers = make(map[string]Factory)
+)
+
+// Register makes a storage driver available under the given name. It panics when the name is
+// taken, as two drivers registering the same name is a programming error
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if factory == nil {
+		panic("storage: Register factory is nil")
+	}
+	if _, taken := drivers[name]; taken {
+		panic("storage: Register called twice for driver " + name)
+	}
+	drivers[name] = factory
+}
+
+// Open opens the storage of the named driver with the given DSN
+func Open(name, dsn string, logger *slog.Logger) (domain.ExpenditureRepository, error) {
+	mu.RLock()
+	factory, ok := drivers[name]
+	mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w %q, available: %v", ErrUnknownDriver, name, Drivers())
+	}
+	return factory(dsn, logger)
+}
+
+// Drivers returns the names of the registered drivers, sorted
+func Drivers() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
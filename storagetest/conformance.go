@@ -0,0 +1,98 @@
+package storagetest
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CheckConformance runs every check of the package against repo, each as a subtest, so every
+// storage backend is held to the same contract of domain.ExpenditureRepository. The checks add
+// their own expenditures and delete them when done, so repo may be a scratch database holding
+// other data
+func CheckConformance(t *testing.T, repo domain.ExpenditureRepository) {
+	t.Run("crud", func(t *testing.T) { CheckExpenditureCRUD(t, repo) })
+	t.Run("ownership", func(t *testing.T) { CheckExpenditureOwnership(t, repo) })
+	t.Run("refunds", func(t *testing.T) { CheckRefundIntegrity(t, repo) })
+}
+
+// CheckExpenditureCRUD checks that repo stores, lists, updates and deletes an expenditure with
+// all its fields, and returns the domain errors for duplicates and missing expenditures. It adds
+// an expenditure and deletes it when done
+func CheckExpenditureCRUD(t testing.TB, repo domain.ExpenditureRepository) {
+	t.Helper()
+
+	expenditure, err := domain.NewExpenditure("CRUD check", 42.5, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), uuid.New())
+	if err != nil {
+		t.Fatalf("creating expenditure: %v", err)
+	}
+	expenditure.SetTags([]string{"groceries", "weekly"})
+	expenditure.Location = &domain.Location{Latitude: 52.52, Longitude: 13.405, PlaceName: "Market", City: "Berlin"}
+	id := expenditure.ID.String()
+
+	if err := repo.AddExpenditure(expenditure); err != nil {
+		t.Fatalf("adding expenditure: %v", err)
+	}
+	t.Cleanup(func() { _ = repo.DeleteExpenditure(id) })
+
+	check := func(step string, want *domain.Expenditure) {
+		t.Helper()
+		got, err := repo.GetExpenditureByID(id)
+		if err != nil {
+			t.Fatalf("%s: getting expenditure: %v", step, err)
+		}
+		if got.ID != want.ID || got.Description != want.Description || got.Amount != want.Amount ||
+			!got.Date.Equal(want.Date) || got.CategoryId != want.CategoryId || !slices.Equal(got.Tags, want.Tags) ||
+			got.Location == nil || *got.Location != *want.Location {
+			t.Fatalf("%s: stored %+v, want %+v", step, got, want)
+		}
+	}
+	check("after adding", expenditure)
+
+	if err := repo.AddExpenditure(expenditure.Clone()); !errors.Is(err, domain.ErrExpenditureAlreadyExists) {
+		t.Errorf("adding a duplicate: got %v, want %v", err, domain.ErrExpenditureAlreadyExists)
+	}
+
+	all, err := repo.GetAllExpenditures()
+	if err != nil {
+		t.Fatalf("listing expenditures: %v", err)
+	}
+	if !slices.ContainsFunc(all, func(e *domain.Expenditure) bool { return e.ID == expenditure.ID }) {
+		t.Errorf("listing expenditures: the added expenditure is missing")
+	}
+
+	updated := expenditure.Clone()
+	updated.Description = "CRUD check, updated"
+	updated.Amount = 17.25
+	updated.Date = time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	updated.CategoryId = uuid.New()
+	updated.SetTags([]string{"monthly"})
+	updated.Location.City = "Hamburg"
+	if err := repo.UpdateExpenditure(updated); err != nil {
+		t.Fatalf("updating expenditure: %v", err)
+	}
+	check("after updating", updated)
+
+	missing := updated.Clone()
+	missing.ID = uuid.New()
+	if err := repo.UpdateExpenditure(missing); !errors.Is(err, domain.ErrExpenditureNotFound) {
+		t.Errorf("updating a missing expenditure: got %v, want %v", err, domain.ErrExpenditureNotFound)
+	}
+	if _, err := repo.GetExpenditureByID(missing.ID.String()); !errors.Is(err, domain.ErrExpenditureNotFound) {
+		t.Errorf("getting a missing expenditure: got %v, want %v", err, domain.ErrExpenditureNotFound)
+	}
+
+	if err := repo.DeleteExpenditure(id); err != nil {
+		t.Fatalf("deleting expenditure: %v", err)
+	}
+	if _, err := repo.GetExpenditureByID(id); !errors.Is(err, domain.ErrExpenditureNotFound) {
+		t.Errorf("getting a deleted expenditure: got %v, want %v", err, domain.ErrExpenditureNotFound)
+	}
+	if err := repo.DeleteExpenditure(id); !errors.Is(err, domain.ErrExpenditureNotFound) {
+		t.Errorf("deleting a missing expenditure: got %v, want %v", err, domain.ErrExpenditureNotFound)
+	}
+}
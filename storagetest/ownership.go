@@ -0,0 +1,215 @@
+package storagetest
+
+import (
+	"fmt"
+	"go-expense-tracker/domain"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SharesMemory reports whether two values of the same type share memory a change through one
+// would show through the other: the same pointer, map or slice backing array anywhere within
+// them. It returns the path of the first shared field, such as "Tags" or "Location". Times are
+// skipped, as they share their read-only location
+func SharesMemory(a, b any) (string, bool) {
+	return sharesMemory(reflect.ValueOf(a), reflect.ValueOf(b), "")
+}
+
+func sharesMemory(a, b reflect.Value, path string) (string, bool) {
+	if !a.IsValid() || !b.IsValid() || a.Type() != b.Type() || a.Type() == timeType {
+		return "", false
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return "", false
+		}
+		if a.Pointer() == b.Pointer() {
+			return pathOr(path), true
+		}
+		return sharesMemory(a.Elem(), b.Elem(), path)
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return "", false
+		}
+		return sharesMemory(a.Elem(), b.Elem(), path)
+	case reflect.Map:
+		if a.IsNil() || b.IsNil() {
+			return "", false
+		}
+		if a.Pointer() == b.Pointer() {
+			return pathOr(path), true
+		}
+		for _, key := range a.MapKeys() {
+			if other := b.MapIndex(key); other.IsValid() {
+				if shared, ok := sharesMemory(a.MapIndex(key), other, fmt.Sprintf("%s[%v]", path, key)); ok {
+					return shared, true
+				}
+			}
+		}
+	case reflect.Slice:
+		if a.Cap() == 0 || b.Cap() == 0 {
+			return "", false
+		}
+		if a.Pointer() == b.Pointer() {
+			return pathOr(path), true
+		}
+		for i := range min(a.Len(), b.Len()) {
+			if shared, ok := sharesMemory(a.Index(i), b.Index(i), fmt.Sprintf("%s[%d]", path, i)); ok {
+				return shared, true
+			}
+		}
+	case reflect.Array:
+		for i := range a.Len() {
+			if shared, ok := sharesMemory(a.Index(i), b.Index(i), fmt.Sprintf("%s[%d]", path, i)); ok {
+				return shared, true
+			}
+		}
+	case reflect.Struct:
+		for i := range a.NumField() {
+			name := a.Type().Field(i).Name
+			if path != "" {
+				name = path + "." + name
+			}
+			if shared, ok := sharesMemory(a.Field(i), b.Field(i), name); ok {
+				return shared, true
+			}
+		}
+	}
+	return "", false
+}
+
+func pathOr(path string) string {
+	if path == "" {
+		return "(value)"
+	}
+	return path
+}
+
+// Populate fills the nil pointers, slices and maps of the exported fields of the struct v
+// points to with one non-zero element each, so a Clone method that forgets one of them is
+// caught by SharesMemory even when the field is new
+func Populate(v any) {
+	populate(reflect.ValueOf(v).Elem(), 0)
+}
+
+// maxPopulateDepth stops recursive types, such as trees of categories, from filling forever
+const maxPopulateDepth = 4
+
+func populate(v reflect.Value, depth int) {
+	if depth > maxPopulateDepth || v.Type() == timeType {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		populate(v.Elem(), depth+1)
+	case reflect.Slice:
+		if v.Len() == 0 {
+			v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		}
+		for i := range v.Len() {
+			populate(v.Index(i), depth+1)
+		}
+	case reflect.Map:
+		if v.Len() == 0 {
+			m := reflect.MakeMap(v.Type())
+			key := reflect.New(v.Type().Key()).Elem()
+			value := reflect.New(v.Type().Elem()).Elem()
+			populate(value, depth+1)
+			m.SetMapIndex(key, value)
+			v.Set(m)
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if v.Field(i).CanSet() {
+				populate(v.Field(i), depth+1)
+			}
+		}
+	}
+}
+
+// CheckExpenditureOwnership checks that repo keeps the ownership contract of
+// domain.ExpenditureRepository: changing an expenditure after adding or updating it, or one it
+// handed out, must not change what it stores. It adds an expenditure and deletes it when done
+func CheckExpenditureOwnership(t testing.TB, repo domain.ExpenditureRepository) {
+	t.Helper()
+
+	expenditure, err := domain.NewExpenditure("Ownership check", 42, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), uuid.New())
+	if err != nil {
+		t.Fatalf("creating expenditure: %v", err)
+	}
+	expenditure.SetTags([]string{"groceries", "weekly"})
+	expenditure.Location = &domain.Location{Latitude: 52.52, Longitude: 13.405, PlaceName: "Market", City: "Berlin"}
+	id := expenditure.ID.String()
+
+	if err := repo.AddExpenditure(expenditure); err != nil {
+		t.Fatalf("adding expenditure: %v", err)
+	}
+	t.Cleanup(func() { _ = repo.DeleteExpenditure(id) })
+	want := expenditure.Clone()
+
+	get := func(step string) *domain.Expenditure {
+		t.Helper()
+		stored, err := repo.GetExpenditureByID(id)
+		if err != nil {
+			t.Fatalf("%s: getting expenditure: %v", step, err)
+		}
+		if stored.Description != want.Description || !slices.Equal(stored.Tags, want.Tags) ||
+			stored.Location == nil || stored.Location.PlaceName != want.Location.PlaceName {
+			t.Fatalf("%s: stored expenditure changed to %q %v %+v, want %q %v %+v", step,
+				stored.Description, stored.Tags, stored.Location, want.Description, want.Tags, want.Location)
+		}
+		return stored
+	}
+	change := func(e *domain.Expenditure) {
+		e.Description = "Changed"
+		e.Tags[0] = "changed"
+		e.Location.PlaceName = "Changed"
+	}
+	shares := func(step string, a, b *domain.Expenditure) {
+		t.Helper()
+		if path, shared := SharesMemory(a, b); shared {
+			t.Fatalf("%s: %s is shared with the storage", step, path)
+		}
+	}
+
+	change(expenditure)
+	stored := get("after changing the added expenditure")
+	shares("get", expenditure, stored)
+
+	change(stored)
+	again := get("after changing a fetched expenditure")
+	shares("get twice", stored, again)
+
+	all, err := repo.GetAllExpenditures()
+	if err != nil {
+		t.Fatalf("listing expenditures: %v", err)
+	}
+	for _, listed := range all {
+		if listed.ID == want.ID {
+			shares("list", listed, again)
+			change(listed)
+		}
+	}
+	get("after changing a listed expenditure")
+
+	updated := want.Clone()
+	updated.Description = "Updated"
+	if err := repo.UpdateExpenditure(updated); err != nil {
+		t.Fatalf("updating expenditure: %v", err)
+	}
+	want.Description = updated.Description
+	change(updated)
+	get("after changing the updated expenditure")
+}
@@ -0,0 +1,34 @@
+package storagetest_test
+
+import (
+	"go-expense-tracker/domain"
+	"go-expense-tracker/storagetest"
+	"testing"
+)
+
+func TestSharesMemory(t *testing.T) {
+	expenditure := &domain.Expenditure{Tags: []string{"a", "b"}, Location: &domain.Location{City: "Berlin"}}
+
+	shallow := *expenditure
+	if path, shared := storagetest.SharesMemory(expenditure, &shallow); !shared || path != "Tags" {
+		t.Errorf("shallow copy: got %q %v, want Tags shared", path, shared)
+	}
+
+	shallow.Tags = []string{"a", "b"}
+	if path, shared := storagetest.SharesMemory(expenditure, &shallow); !shared || path != "Location" {
+		t.Errorf("copied tags: got %q %v, want Location shared", path, shared)
+	}
+
+	if path, shared := storagetest.SharesMemory(expenditure, expenditure.Clone()); shared {
+		t.Errorf("clone: %s is shared", path)
+	}
+
+	draft := &domain.Draft{Expenditure: *expenditure}
+	if path, shared := storagetest.SharesMemory(draft, &domain.Draft{Expenditure: *expenditure}); !shared || path != "Expenditure.Tags" {
+		t.Errorf("draft: got %q %v, want Expenditure.Tags shared", path, shared)
+	}
+}
+
+func TestRepositoryOwnership(t *testing.T) {
+	storagetest.CheckExpenditureOwnership(t, storagetest.NewRepository())
+}
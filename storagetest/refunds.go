@@ -0,0 +1,106 @@
+package storagetest
+
+import (
+	"errors"
+	"go-expense-tracker/domain"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CheckRefundIntegrity checks that repo keeps refunds linked to their original expenditure:
+// refunds of a missing expenditure are rejected, refunds added at the same time together never
+// exceed their original, its amount cannot be lowered below them and it cannot be deleted
+// before them. It adds an expenditure with refunds and deletes them when done
+func CheckRefundIntegrity(t testing.TB, repo domain.ExpenditureRepository) {
+	t.Helper()
+
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	original, err := domain.NewExpenditure("Refund check", 100, date, uuid.New())
+	if err != nil {
+		t.Fatalf("creating expenditure: %v", err)
+	}
+	if err := repo.AddExpenditure(original); err != nil {
+		t.Fatalf("adding expenditure: %v", err)
+	}
+	id := original.ID.String()
+	var added []*domain.Expenditure
+	t.Cleanup(func() {
+		for _, refund := range added {
+			_ = repo.DeleteExpenditure(refund.ID.String())
+		}
+		_ = repo.DeleteExpenditure(id)
+	})
+
+	refund := func(of *domain.Expenditure, amount float64) *domain.Expenditure {
+		r, err := of.NewRefund(amount, date, "", 0, date)
+		if err != nil {
+			t.Fatalf("creating refund: %v", err)
+		}
+		return r
+	}
+
+	missing := &domain.Expenditure{ID: uuid.New(), Description: "Missing", Amount: 10, Date: date}
+	if err := repo.AddExpenditure(refund(missing, 5)); !errors.Is(err, domain.ErrExpenditureNotFound) {
+		t.Errorf("refund of a missing expenditure: got %v, want %v", err, domain.ErrExpenditureNotFound)
+	}
+
+	// Every refund fits alone, but only four fit together
+	const attempts = 10
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		rejected int
+	)
+	for range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := refund(original, 25)
+			err := repo.AddExpenditure(r)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				added = append(added, r)
+			case errors.Is(err, domain.ErrRefundExceedsOriginal):
+				rejected++
+			default:
+				t.Errorf("adding refund: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if len(added) != 4 || rejected != attempts-4 {
+		t.Fatalf("concurrent refunds: %d added and %d rejected, want 4 and %d", len(added), rejected, attempts-4)
+	}
+
+	lowered := original.Clone()
+	lowered.Amount = 90
+	if err := repo.UpdateExpenditure(lowered); !errors.Is(err, domain.ErrAmountBelowRefunded) {
+		t.Errorf("lowering the amount below the refunds: got %v, want %v", err, domain.ErrAmountBelowRefunded)
+	}
+	raised := original.Clone()
+	raised.Amount = 120
+	if err := repo.UpdateExpenditure(raised); err != nil {
+		t.Errorf("raising the amount: %v", err)
+	}
+
+	if err := repo.DeleteExpenditure(id); !errors.Is(err, domain.ErrExpenditureHasRefunds) {
+		t.Errorf("deleting an expenditure with refunds: got %v, want %v", err, domain.ErrExpenditureHasRefunds)
+	}
+	if _, err := repo.GetExpenditureByID(id); err != nil {
+		t.Errorf("getting the expenditure after a rejected delete: %v", err)
+	}
+	for _, r := range added {
+		if err := repo.DeleteExpenditure(r.ID.String()); err != nil {
+			t.Fatalf("deleting refund: %v", err)
+		}
+	}
+	added = nil
+	if err := repo.DeleteExpenditure(id); err != nil {
+		t.Errorf("deleting the expenditure after its refunds: %v", err)
+	}
+}
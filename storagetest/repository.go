@@ -0,0 +1,217 @@
+// Package storagetest provides an in-memory ExpenditureRepository for tests whose latency and
+// failures can be programmed, so handlers and middleware can be exercised against storage
+// errors and slow queries without a database. Its conformance suite holds every storage backend
+// to the contract of the domain package: storing and returning expenditures with all their
+// fields, handing out copies that share no memory with what they store, and keeping refunds
+// linked to their original expenditure.
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"go-expense-tracker/domain"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrTimeout is what a storage returns when a query ran out of time
+var ErrTimeout = fmt.Errorf("storage timeout: %w", context.DeadlineExceeded)
+
+// Method names a method of domain.ExpenditureRepository for failure injection
+type Method string
+
+const (
+	AddExpenditure     Method = "AddExpenditure"
+	GetExpenditureByID Method = "GetExpenditureByID"
+	GetAllExpenditures Method = "GetAllExpenditures"
+	UpdateExpenditure  Method = "UpdateExpenditure"
+	DeleteExpenditure  Method = "DeleteExpenditure"
+)
+
+// Repository is an in-memory domain.ExpenditureRepository with programmable latency and errors.
+// The zero value is not usable, create one with NewRepository
+type Repository struct {
+	mu           sync.Mutex
+	expenditures map[uuid.UUID]*domain.Expenditure
+	latency      map[Method]time.Duration
+	failures     map[Method]error
+	next         map[Method][]error
+	calls        map[Method]int
+}
+
+// NewRepository creates a new Repository holding the given expenditures
+func NewRepository(expenditures ...*domain.Expenditure) *Repository {
+	r := &Repository{
+		expenditures: make(map[uuid.UUID]*domain.Expenditure),
+		latency:      make(map[Method]time.Duration),
+		failures:     make(map[Method]error),
+		next:         make(map[Method][]error),
+		calls:        make(map[Method]int),
+	}
+	for _, expenditure := range expenditures {
+		r.expenditures[expenditure.ID] = expenditure.Clone()
+	}
+	return r
+}
+
+// SetLatency delays every call of the methods by d; without methods all of them are delayed
+func (r *Repository) SetLatency(d time.Duration, methods ...Method) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, method := range allMethods(methods) {
+		r.latency[method] = d
+	}
+}
+
+// FailWith makes every call of method return err until Reset
+func (r *Repository) FailWith(method Method, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failures[method] = err
+}
+
+// FailNext makes the next call of method return err; queued errors are returned in order
+// before any error set with FailWith
+func (r *Repository) FailNext(method Method, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.next[method] = append(r.next[method], err)
+}
+
+// Reset removes all programmed latency and failures, keeping the expenditures and call counts
+func (r *Repository) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.latency)
+	clear(r.failures)
+	clear(r.next)
+}
+
+// Calls returns how often method was called, failed calls included
+func (r *Repository) Calls(method Method) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.calls[method]
+}
+
+// call counts the call, waits out its latency and returns the error programmed for it
+func (r *Repository) call(method Method) error {
+	r.mu.Lock()
+	r.calls[method]++
+	latency := r.latency[method]
+	var err error
+	if queued := r.next[method]; len(queued) > 0 {
+		err, r.next[method] = queued[0], queued[1:]
+	} else {
+		err = r.failures[method]
+	}
+	r.mu.Unlock()
+
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+	return err
+}
+
+func (r *Repository) AddExpenditure(expenditure *domain.Expenditure) error {
+	if err := r.call(AddExpenditure); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.expenditures[expenditure.ID]; exists {
+		return domain.ErrExpenditureAlreadyExists
+	}
+	r.expenditures[expenditure.ID] = expenditure.Clone()
+	return nil
+}
+
+func (r *Repository) GetExpenditureByID(id string) (*domain.Expenditure, error) {
+	if err := r.call(GetExpenditureByID); err != nil {
+		return nil, err
+	}
+
+	expenditureID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expenditure, exists := r.expenditures[expenditureID]
+	if !exists {
+		return nil, domain.ErrExpenditureNotFound
+	}
+	return expenditure.Clone(), nil
+}
+
+func (r *Repository) GetAllExpenditures() ([]*domain.Expenditure, error) {
+	if err := r.call(GetAllExpenditures); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expenditures := make([]*domain.Expenditure, 0, len(r.expenditures))
+	for _, expenditure := range r.expenditures {
+		expenditures = append(expenditures, expenditure.Clone())
+	}
+	sort.Slice(expenditures, func(i, j int) bool {
+		return expenditures[i].ID.String() < expenditures[j].ID.String()
+	})
+	return expenditures, nil
+}
+
+func (r *Repository) UpdateExpenditure(expenditure *domain.Expenditure) error {
+	if err := r.call(UpdateExpenditure); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.expenditures[expenditure.ID]; !exists {
+		return domain.ErrExpenditureNotFound
+	}
+	r.expenditures[expenditure.ID] = expenditure.Clone()
+	return nil
+}
+
+func (r *Repository) DeleteExpenditure(id string) error {
+	if err := r.call(DeleteExpenditure); err != nil {
+		return err
+	}
+
+	expenditureID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.expenditures[expenditureID]; !exists {
+		return domain.ErrExpenditureNotFound
+	}
+	delete(r.expenditures, expenditureID)
+	return nil
+}
+
+func allMethods(methods []Method) []Method {
+	if len(methods) > 0 {
+		return methods
+	}
+	return []Method{AddExpenditure, GetExpenditureByID, GetAllExpenditures, UpdateExpenditure, DeleteExpenditure}
+}